@@ -65,6 +65,7 @@ const (
 	phaseNameInitialBackup               = "InitialBackup"
 	phaseNameRestoreLastBackup           = "RestoreLastBackup"
 	phaseNameTakeNewBackup               = "TakeNewBackup"
+	phaseNameVerifyBackup                = "VerifyBackup"
 	phaseStatusCatchupReplicationStalled = "Stalled"
 	phaseStatusCatchupReplicationStopped = "Stopped"
 
@@ -79,6 +80,8 @@ var (
 	allowFirstBackup    bool
 	restartBeforeBackup bool
 	upgradeSafe         bool
+	verifyBackup        bool
+	verifyBackupName    string
 
 	// vttablet-like flags
 	initDbNameOverride string
@@ -126,6 +129,7 @@ var (
 		phaseNameInitialBackup,
 		phaseNameRestoreLastBackup,
 		phaseNameTakeNewBackup,
+		phaseNameVerifyBackup,
 	}
 	phaseStatus = stats.NewGaugesWithMultiLabels(
 		"PhaseStatus",
@@ -186,7 +190,13 @@ to the handling of the query path.
 The command-line parameters to vtbackup specify a policy for when a new backup
 is needed, and when old backups should be removed. If the existing backups
 already satisfy the policy, then vtbackup will do nothing and return success
-immediately.`,
+immediately.
+
+With --verify-backup, vtbackup instead restores an existing full backup into a
+scratch mysqld, checks the consistency of its tables, compares their checksums
+and row counts with the ones recorded at backup time (see
+--builtinbackup-table-checksums), and records the result in the MANIFEST of the
+backup, where GetBackups reports it.`,
 		Version: servenv.AppVersion.String(),
 		Args:    cobra.NoArgs,
 		PreRunE: servenv.CobraPreRunE,
@@ -208,6 +218,8 @@ func init() {
 	Main.Flags().BoolVar(&allowFirstBackup, "allow_first_backup", allowFirstBackup, "Allow this job to take the first backup of an existing shard.")
 	Main.Flags().BoolVar(&restartBeforeBackup, "restart_before_backup", restartBeforeBackup, "Perform a mysqld clean/full restart after applying binlogs, but before taking the backup. Only makes sense to work around xtrabackup bugs.")
 	Main.Flags().BoolVar(&upgradeSafe, "upgrade-safe", upgradeSafe, "Whether to use innodb_fast_shutdown=0 for the backup so it is safe to use for MySQL upgrades.")
+	Main.Flags().BoolVar(&verifyBackup, "verify-backup", verifyBackup, "Instead of taking a new backup, restore an existing full backup into a scratch mysqld, check the consistency of its tables and compare their checksums and row counts with the ones recorded at backup time, and record the result in the MANIFEST of the backup. Old backups are not pruned in this mode.")
+	Main.Flags().StringVar(&verifyBackupName, "verify-backup-name", verifyBackupName, "The name of the full backup to verify with --verify-backup. Defaults to the most recent complete full backup.")
 
 	// vttablet-like flags
 	utils.SetFlagStringVar(Main.Flags(), &initDbNameOverride, "init-db-name-override", initDbNameOverride, "(init parameter) override the name of the db used by vttablet")
@@ -283,10 +295,18 @@ func run(cc *cobra.Command, args []string) error {
 		}
 	}
 
+	backupDir := mysqlctl.GetBackupDir(initKeyspace, initShard)
+	if verifyBackup {
+		if err := verifyExistingBackup(ctx, cc.Context(), backupStorage, backupDir); err != nil {
+			return fmt.Errorf("Failed to verify backup: %w", err)
+		}
+		log.Info("Exiting.")
+		return nil
+	}
+
 	// Try to take a backup, if it's been long enough since the last one.
 	// Skip pruning if backup wasn't fully successful. We don't want to be
 	// deleting things if the backup process is not healthy.
-	backupDir := mysqlctl.GetBackupDir(initKeyspace, initShard)
 	doBackup, err := shouldBackup(ctx, topoServer, backupStorage, backupDir)
 	if err != nil {
 		return fmt.Errorf("Can't take backup: %w", err)
@@ -298,9 +318,13 @@ func run(cc *cobra.Command, args []string) error {
 	}
 
 	// Prune old backups.
-	if err := pruneBackups(ctx, backupStorage, backupDir); err != nil {
+	if err := pruneBackups(ctx, topoServer, backupStorage, backupDir); err != nil {
 		return fmt.Errorf("Couldn't prune old backups: %w", err)
 	}
+	// Remove the chunks that only the pruned backups referenced.
+	if _, err := mysqlctl.PruneBackupChunks(ctx, logutil.NewConsoleLogger(), backupStorage, backupDir); err != nil {
+		return fmt.Errorf("Couldn't prune backup chunks: %w", err)
+	}
 
 	if keepAliveTimeout > 0 {
 		log.Infof("Backup was successful, waiting %s before exiting (or until context expires).", keepAliveTimeout)
@@ -652,6 +676,136 @@ func takeBackup(ctx, backgroundCtx context.Context, topoServer *topo.Server, bac
 	return nil
 }
 
+// verifyExistingBackup restores a full backup into a scratch mysqld, verifies
+// the restored data against the MANIFEST of the backup, and records the result
+// of the verification in the MANIFEST.
+func verifyExistingBackup(ctx, backgroundCtx context.Context, backupStorage backupstorage.BackupStorage, backupDir string) error {
+	backups, err := backupStorage.ListBackups(ctx, backupDir)
+	if err != nil {
+		return fmt.Errorf("can't list backups: %v", err)
+	}
+	var backup backupstorage.BackupHandle
+	for i := len(backups) - 1; i >= 0 && backup == nil; i-- {
+		bh := backups[i]
+		if verifyBackupName != "" {
+			if bh.Name() == verifyBackupName {
+				backup = bh
+			}
+			continue
+		}
+		manifest, err := mysqlctl.GetBackupManifest(ctx, bh)
+		if err != nil {
+			log.Warningf("Ignoring backup %v because it's incomplete: %v", bh.Name(), err)
+			continue
+		}
+		if !manifest.Incremental {
+			backup = bh
+		}
+	}
+	if backup == nil {
+		if verifyBackupName != "" {
+			return fmt.Errorf("backup %v not found in %v", verifyBackupName, backupDir)
+		}
+		return fmt.Errorf("no complete full backup found in %v", backupDir)
+	}
+	manifest, err := mysqlctl.GetBackupManifest(ctx, backup)
+	if err != nil {
+		return fmt.Errorf("can't get backup MANIFEST: %v", err)
+	}
+	if manifest.Incremental {
+		return fmt.Errorf("backup %v is an incremental backup, only full backups can be verified", backup.Name())
+	}
+
+	// As in takeBackup, use an imaginary tablet alias with a random UID.
+	bigN, err := rand.Int(rand.Reader, big.NewInt(math.MaxUint32))
+	if err != nil {
+		return fmt.Errorf("can't generate random tablet UID: %v", err)
+	}
+	tabletAlias := &topodatapb.TabletAlias{
+		Cell: "vtbackup",
+		Uid:  uint32(bigN.Uint64()),
+	}
+	tabletDir := mysqlctl.TabletDir(tabletAlias.Uid)
+	defer func() {
+		log.Infof("Removing temporary tablet directory: %v", tabletDir)
+		if err := os.RemoveAll(tabletDir); err != nil {
+			log.Warningf("Failed to remove temporary tablet directory: %v", err)
+		}
+	}()
+
+	mysqld, mycnf, err := mysqlctl.CreateMysqldAndMycnf(tabletAlias.Uid, mysqlSocket, mysqlPort, collationEnv)
+	if err != nil {
+		return fmt.Errorf("failed to initialize mysql config: %v", err)
+	}
+	ctx, cancelCtx := context.WithCancel(ctx)
+	defer cancelCtx()
+	mysqld.OnTerm(func() {
+		log.Warning("Cancelling vtbackup as MySQL has terminated")
+		cancelCtx()
+	})
+
+	initCtx, initCancel := context.WithTimeout(ctx, mysqlTimeout)
+	defer initCancel()
+	if err := mysqld.Init(initCtx, mycnf, initDBSQLFile); err != nil {
+		return fmt.Errorf("failed to initialize mysql data dir and start mysqld: %v", err)
+	}
+	defer func() {
+		mysqlShutdownCtx, mysqlShutdownCancel := context.WithTimeout(backgroundCtx, mysqlShutdownTimeout+10*time.Second)
+		defer mysqlShutdownCancel()
+		if err := mysqld.Shutdown(mysqlShutdownCtx, mycnf, false, mysqlShutdownTimeout); err != nil {
+			log.Errorf("failed to shutdown mysqld: %v", err)
+		}
+	}()
+
+	phase.Set(phaseNameVerifyBackup, int64(1))
+	defer phase.Set(phaseNameVerifyBackup, int64(0))
+	dbName := initDbNameOverride
+	if dbName == "" {
+		dbName = fmt.Sprintf("vt_%s", initKeyspace)
+	}
+	log.Infof("Restoring backup %v from directory %v to verify it", backup.Name(), backupDir)
+	params := mysqlctl.RestoreParams{
+		Cnf:                  mycnf,
+		Mysqld:               mysqld,
+		Logger:               logutil.NewConsoleLogger(),
+		Concurrency:          concurrency,
+		HookExtraEnv:         map[string]string{"TABLET_ALIAS": topoproto.TabletAliasString(tabletAlias)},
+		DeleteBeforeRestore:  true,
+		DbName:               dbName,
+		Keyspace:             initKeyspace,
+		Shard:                initShard,
+		Stats:                backupstats.RestoreStats(),
+		MysqlShutdownTimeout: mysqlShutdownTimeout,
+		BackupName:           backup.Name(),
+	}
+	verifyErr := func() error {
+		if _, err := mysqlctl.Restore(ctx, params); err != nil {
+			return fmt.Errorf("can't restore from backup: %v", err)
+		}
+		return mysqlctl.VerifyRestoredBackup(ctx, mysqld, params.Logger, manifest)
+	}()
+	if ctx.Err() != nil {
+		// Don't record an interrupted verification as a failed one.
+		return errors.Join(verifyErr, ctx.Err())
+	}
+
+	verification := &mysqlctl.BackupVerification{
+		VerifiedTime: mysqlctl.FormatRFC3339(time.Now()),
+		Success:      verifyErr == nil,
+	}
+	if verifyErr != nil {
+		verification.Error = verifyErr.Error()
+	}
+	if err := mysqlctl.WriteBackupVerification(ctx, backupStorage, backupDir, backup, verification); err != nil {
+		return errors.Join(verifyErr, fmt.Errorf("can't record the verification in the MANIFEST: %v", err))
+	}
+	if verifyErr != nil {
+		return verifyErr
+	}
+	log.Infof("Backup %v was verified successfully", backup.Name())
+	return nil
+}
+
 func resetReplication(ctx context.Context, pos replication.Position, mysqld mysqlctl.MysqlDaemon) error {
 	if err := mysqld.StopReplication(ctx, nil); err != nil {
 		return vterrors.Wrap(err, "failed to stop replication")
@@ -747,7 +901,22 @@ func retryOnError(ctx context.Context, fn func() error) error {
 	}
 }
 
-func pruneBackups(ctx context.Context, backupStorage backupstorage.BackupStorage, backupDir string) error {
+func pruneBackups(ctx context.Context, topoServer *topo.Server, backupStorage backupstorage.BackupStorage, backupDir string) error {
+	// The backup retention policy of the keyspace, when it has one, takes
+	// precedence over min_retention_time and min_retention_count.
+	ki, err := topoServer.GetKeyspace(ctx, initKeyspace)
+	if err != nil {
+		return fmt.Errorf("can't get keyspace %v: %v", initKeyspace, err)
+	}
+	if policy := ki.BackupRetentionPolicy; policy != nil {
+		log.Infof("Pruning old backups with the backup retention policy of keyspace %v: %v", initKeyspace, policy)
+		_, removed, err := mysqlctl.EnforceBackupRetentionPolicy(ctx, logutil.NewConsoleLogger(), backupStorage, backupDir, policy, time.Now(), false)
+		if err != nil {
+			return err
+		}
+		log.Infof("Removed %v old backups from %v: %v", len(removed), backupDir, removed)
+		return nil
+	}
 	if minRetentionTime == 0 {
 		log.Info("Pruning of old backups is disabled.")
 		return nil
@@ -29,6 +29,7 @@ import (
 	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
@@ -52,9 +53,22 @@ If no replica-type tablet can be found, the backup can be taken on the primary i
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandBackupShard,
 	}
+	// EnforceBackupRetentionPolicy makes an EnforceBackupRetentionPolicy gRPC call to a vtctld.
+	EnforceBackupRetentionPolicy = &cobra.Command{
+		Use:   "EnforceBackupRetentionPolicy [--dry-run] <keyspace|keyspace/shard>",
+		Short: "Removes the backups of the shards of a keyspace that its backup retention policy does not keep.",
+		Long: `Removes the backups of the shards of a keyspace that its backup retention policy does not keep.
+
+The backups of all the shards of the keyspace are pruned, unless a single shard is given. Backups whose MANIFEST
+cannot be read, such as backups that are still in progress, are never removed. With --dry-run, the backups that
+would be removed are listed, but are not removed.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandEnforceBackupRetentionPolicy,
+	}
 	// GetBackups makes a GetBackups gRPC call to a vtctld.
 	GetBackups = &cobra.Command{
-		Use:                   "GetBackups [--limit <limit>] [--json] <keyspace/shard>",
+		Use:                   "GetBackups [--limit <limit>] [--detailed] [--json] <keyspace/shard>",
 		Short:                 "Lists backups for the given shard.",
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(1),
@@ -76,6 +90,24 @@ If no replica-type tablet can be found, the backup can be taken on the primary i
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandRestoreFromBackup,
 	}
+	// SetKeyspaceBackupRetentionPolicy makes a SetKeyspaceBackupRetentionPolicy gRPC call to a vtctld.
+	SetKeyspaceBackupRetentionPolicy = &cobra.Command{
+		Use:   "SetKeyspaceBackupRetentionPolicy [--keep-full <count>] [--keep-daily <count>] [--keep-weekly <count>] [--keep-monthly <count>] [--point-in-time-recovery-days <days>] <keyspace>",
+		Short: "Sets the policy that decides which backups of the shards of the keyspace are kept when they are pruned.",
+		Long: `Sets the policy that decides which backups of the shards of the keyspace are kept when they are pruned.
+
+A backup is kept if any of the rules keeps it, and the most recent full backup of a shard is always kept. The daily,
+weekly and monthly rules keep the last full backup of each of the most recent days, weeks and months that have a full
+backup. With --point-in-time-recovery-days, the backups that are needed to restore to any point in time within that
+many days are kept, and incremental backups always keep the backups they depend on. When no rule is set, the policy is
+removed, and the backups of the keyspace are not pruned.
+
+To keep the last 3 full backups, a backup per day for a week, and to allow point in time recoveries within the last 2 days:
+SetKeyspaceBackupRetentionPolicy --keep-full 3 --keep-daily 7 --point-in-time-recovery-days 2 customer`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandSetKeyspaceBackupRetentionPolicy,
+	}
 )
 
 var backupOptions = struct {
@@ -168,8 +200,43 @@ func commandBackupShard(cmd *cobra.Command, args []string) error {
 	}
 }
 
+var enforceBackupRetentionPolicyOptions = struct {
+	DryRun bool
+}{}
+
+func commandEnforceBackupRetentionPolicy(cmd *cobra.Command, args []string) error {
+	keyspace, shard := cmd.Flags().Arg(0), ""
+	if strings.Contains(keyspace, "/") {
+		var err error
+		keyspace, shard, err = topoproto.ParseKeyspaceShard(keyspace)
+		if err != nil {
+			return err
+		}
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.EnforceBackupRetentionPolicy(commandCtx, &vtctldatapb.EnforceBackupRetentionPolicyRequest{
+		Keyspace: keyspace,
+		Shard:    shard,
+		DryRun:   enforceBackupRetentionPolicyOptions.DryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
 var getBackupsOptions = struct {
 	Limit      uint32
+	Detailed   bool
 	OutputJSON bool
 }{}
 
@@ -185,6 +252,7 @@ func commandGetBackups(cmd *cobra.Command, args []string) error {
 		Keyspace: keyspace,
 		Shard:    shard,
 		Limit:    getBackupsOptions.Limit,
+		Detailed: getBackupsOptions.Detailed,
 	})
 	if err != nil {
 		return err
@@ -203,6 +271,9 @@ func commandGetBackups(cmd *cobra.Command, args []string) error {
 	names := make([]string, len(resp.Backups))
 	for i, b := range resp.Backups {
 		names[i] = b.Name
+		if getBackupsOptions.Detailed {
+			names[i] = fmt.Sprintf("%s %s", b.Name, b.Status)
+		}
 	}
 
 	fmt.Printf("%s\n", strings.Join(names, "\n"))
@@ -291,6 +362,46 @@ func commandRestoreFromBackup(cmd *cobra.Command, args []string) error {
 	}
 }
 
+var setKeyspaceBackupRetentionPolicyOptions = struct {
+	KeepFull                uint32
+	KeepDaily               uint32
+	KeepWeekly              uint32
+	KeepMonthly             uint32
+	PointInTimeRecoveryDays uint32
+}{}
+
+func commandSetKeyspaceBackupRetentionPolicy(cmd *cobra.Command, args []string) error {
+	keyspace := cmd.Flags().Arg(0)
+	cli.FinishedParsing(cmd)
+
+	opts := setKeyspaceBackupRetentionPolicyOptions
+	req := &vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest{
+		Keyspace: keyspace,
+	}
+	if opts.KeepFull > 0 || opts.KeepDaily > 0 || opts.KeepWeekly > 0 || opts.KeepMonthly > 0 || opts.PointInTimeRecoveryDays > 0 {
+		req.BackupRetentionPolicy = &topodatapb.BackupRetentionPolicy{
+			KeepFull:                opts.KeepFull,
+			KeepDaily:               opts.KeepDaily,
+			KeepWeekly:              opts.KeepWeekly,
+			KeepMonthly:             opts.KeepMonthly,
+			PointInTimeRecoveryDays: opts.PointInTimeRecoveryDays,
+		}
+	}
+
+	resp, err := client.SetKeyspaceBackupRetentionPolicy(commandCtx, req)
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
 func init() {
 	Backup.Flags().BoolVar(&backupOptions.AllowPrimary, "allow-primary", false, "Allow the primary of a shard to be used for the backup. WARNING: If using the builtin backup engine, this will shutdown mysqld on the primary and stop writes for the duration of the backup.")
 	Backup.Flags().Int32Var(&backupOptions.Concurrency, "concurrency", 4, "Specifies the number of compression/checksum jobs to run simultaneously.")
@@ -308,7 +419,11 @@ func init() {
 	BackupShard.Flags().DurationVar(&backupShardOptions.MysqlShutdownTimeout, "mysql-shutdown-timeout", mysqlctl.DefaultShutdownTimeout, "Timeout to use when MySQL is being shut down.")
 	Root.AddCommand(BackupShard)
 
+	EnforceBackupRetentionPolicy.Flags().BoolVar(&enforceBackupRetentionPolicyOptions.DryRun, "dry-run", false, "Only list the backups that would be removed, without removing them.")
+	Root.AddCommand(EnforceBackupRetentionPolicy)
+
 	GetBackups.Flags().Uint32VarP(&getBackupsOptions.Limit, "limit", "l", 0, "Retrieve only the most recent N backups.")
+	GetBackups.Flags().BoolVar(&getBackupsOptions.Detailed, "detailed", false, "Read the MANIFEST of each backup to report its engine and status, including whether the backup was verified to be valid.")
 	GetBackups.Flags().BoolVarP(&getBackupsOptions.OutputJSON, "json", "j", false, "Output backup info in JSON format rather than a list of backups.")
 	Root.AddCommand(GetBackups)
 
@@ -320,4 +435,11 @@ func init() {
 	RestoreFromBackup.Flags().StringVar(&restoreFromBackupOptions.RestoreToTimestamp, "restore-to-timestamp", "", "Run a point in time recovery that restores up to, and excluding, given timestamp in RFC3339 format (`2006-01-02T15:04:05Z07:00`). This will attempt to use one full backup followed by zero or more incremental backups")
 	RestoreFromBackup.Flags().BoolVar(&restoreFromBackupOptions.DryRun, "dry-run", false, "Only validate restore steps, do not actually restore data")
 	Root.AddCommand(RestoreFromBackup)
+
+	SetKeyspaceBackupRetentionPolicy.Flags().Uint32Var(&setKeyspaceBackupRetentionPolicyOptions.KeepFull, "keep-full", 0, "Keep this many of the most recent full backups.")
+	SetKeyspaceBackupRetentionPolicy.Flags().Uint32Var(&setKeyspaceBackupRetentionPolicyOptions.KeepDaily, "keep-daily", 0, "Keep the last full backup of each of this many of the most recent days.")
+	SetKeyspaceBackupRetentionPolicy.Flags().Uint32Var(&setKeyspaceBackupRetentionPolicyOptions.KeepWeekly, "keep-weekly", 0, "Keep the last full backup of each of this many of the most recent weeks.")
+	SetKeyspaceBackupRetentionPolicy.Flags().Uint32Var(&setKeyspaceBackupRetentionPolicyOptions.KeepMonthly, "keep-monthly", 0, "Keep the last full backup of each of this many of the most recent months.")
+	SetKeyspaceBackupRetentionPolicy.Flags().Uint32Var(&setKeyspaceBackupRetentionPolicyOptions.PointInTimeRecoveryDays, "point-in-time-recovery-days", 0, "Keep the backups that are needed to restore to any point in time within this many days.")
+	Root.AddCommand(SetKeyspaceBackupRetentionPolicy)
 }
@@ -19,11 +19,13 @@ package command
 import (
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"vitess.io/vitess/go/json2"
 	"vitess.io/vitess/go/mysql/sqlerror"
 	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/vt/vtctl/reparentutil/policy"
@@ -34,6 +36,7 @@ import (
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 	"vitess.io/vitess/go/vt/proto/vttime"
+	"vitess.io/vitess/go/vt/schema"
 )
 
 var (
@@ -96,20 +99,65 @@ Otherwise, the keyspace must be empty (have no shards), or returns an error.`,
 		Args:                  cobra.ExactArgs(2),
 		RunE:                  commandRemoveKeyspaceCell,
 	}
+	// SetKeyspaceCutOverWindowPolicy makes a SetKeyspaceCutOverWindowPolicy gRPC call to a vtctld.
+	SetKeyspaceCutOverWindowPolicy = &cobra.Command{
+		Use:   "SetKeyspaceCutOverWindowPolicy [--window <window> ...] [--time-zone <tz>] [--max-replication-lag <seconds>] [--max-threads-running <count>] <keyspace>",
+		Short: "Sets the policy that restricts when the Online DDL migrations of the keyspace cut over.",
+		Long: `Sets the policy that restricts when the Online DDL migrations of the keyspace cut over.
+
+A migration that is ready to cut over keeps up with the changes to its table until one of the --window windows opens,
+and cuts over only while the replication lag of the shard and the threads_running metric of the primary, as measured
+by the tablet throttler, are below --max-replication-lag and --max-threads-running. A window is given as
+"[days] HH:MM-HH:MM", where the days are a comma separated list of days and day ranges, such as "mon-fri" or
+"sat,sun", and default to every day. A window that ends before it starts closes on the next day. The windows are in
+the --time-zone time zone, UTC by default. A migration that is forced to cut over, e.g. with
+'OnlineDDL force-cutover', ignores the policy. When no flag is set, the policy is removed.
+
+To only cut over between 02:00 and 05:00 UTC on weekdays, while the replication lag is below 5 seconds:
+SetKeyspaceCutOverWindowPolicy --window "mon-fri 02:00-05:00" --max-replication-lag 5 customer`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandSetKeyspaceCutOverWindowPolicy,
+	}
 	// SetKeyspaceDurabilityPolicy makes a SetKeyspaceDurabilityPolicy gRPC call to a vtcltd.
 	SetKeyspaceDurabilityPolicy = &cobra.Command{
-		Use:   "SetKeyspaceDurabilityPolicy [--durability-policy=policy_name] <keyspace name>",
+		Use:   "SetKeyspaceDurabilityPolicy [--durability-policy=policy_name] [--custom-durability-policy <json> | --custom-durability-policy-file <path>] <keyspace name>",
 		Short: "Sets the durability-policy used by the specified keyspace.",
 		Long: `Sets the durability-policy used by the specified keyspace. 
 Durability policy governs the durability of the keyspace by describing which tablets should be sending semi-sync acknowledgements to the primary.
 Possible values include 'semi_sync', 'none' and others as dictated by registered plugins.
 
 To set the durability policy of customer keyspace to semi_sync, you would use the following command:
-SetKeyspaceDurabilityPolicy --durability-policy='semi_sync' customer`,
+SetKeyspaceDurabilityPolicy --durability-policy='semi_sync' customer
+
+The 'custom' durability policy is declared in the keyspace record, with --custom-durability-policy or
+--custom-durability-policy-file, as a JSON topodata.CustomDurabilityPolicy. Its promotion rules and semi-sync ackers
+select the tablets by their types, cells and tags. For instance, to never promote the tablets of the zone3 cell, and to
+require a semi-sync ack from a replica of another availability zone than the primary, as given by the "az" tablet tag:
+SetKeyspaceDurabilityPolicy --durability-policy='custom' --custom-durability-policy='{"promotion_rules": [{"selector": {"cells": ["zone3"]}, "rule": "must_not"}], "semi_sync_ackers": 1, "acker_distinct_tags": ["az"]}' customer`,
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandSetKeyspaceDurabilityPolicy,
 	}
+	// SetKeyspaceErrantGtidRemediationPolicy makes a SetKeyspaceErrantGtidRemediationPolicy gRPC call to a vtctld.
+	SetKeyspaceErrantGtidRemediationPolicy = &cobra.Command{
+		Use:   "SetKeyspaceErrantGtidRemediationPolicy [--inject-empty-transactions] [--restore-from-backup] [--max-errant-transactions <count>] <keyspace>",
+		Short: "Sets the policy that VTOrc applies to remediate the errant GTIDs of the replicas of the keyspace.",
+		Long: `Sets the policy that VTOrc applies to remediate the errant GTIDs of the replicas of the keyspace.
+
+VTOrc inspects the errant transactions of a replica in its binary logs. With --inject-empty-transactions, when none
+of them writes any data, VTOrc commits empty transactions with the errant GTIDs on the primary. With
+--restore-from-backup, when some of them write data, VTOrc restores the replica from a backup. The replicas with more
+than --max-errant-transactions errant transactions, and the ones that the policy does not allow VTOrc to remediate,
+are only fenced. When neither --inject-empty-transactions nor --restore-from-backup is set, the policy is removed, and
+VTOrc does not remediate the errant GTIDs of the keyspace.
+
+To inject empty transactions for up to 10 empty errant transactions, and to restore the other replicas from a backup:
+SetKeyspaceErrantGtidRemediationPolicy --inject-empty-transactions --restore-from-backup --max-errant-transactions 10 customer`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandSetKeyspaceErrantGtidRemediationPolicy,
+	}
 	// ValidateVersionKeyspace makes a ValidateVersionKeyspace gRPC call to a vtctld.
 	ValidateVersionKeyspace = &cobra.Command{
 		Use:                   "ValidateVersionKeyspace <keyspace>",
@@ -317,18 +365,125 @@ func commandRemoveKeyspaceCell(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var setKeyspaceCutOverWindowPolicyOptions = struct {
+	Windows           []string
+	TimeZone          string
+	MaxReplicationLag float64
+	MaxThreadsRunning float64
+}{}
+
+func commandSetKeyspaceCutOverWindowPolicy(cmd *cobra.Command, args []string) error {
+	keyspace := cmd.Flags().Arg(0)
+
+	opts := setKeyspaceCutOverWindowPolicyOptions
+	req := &vtctldatapb.SetKeyspaceCutOverWindowPolicyRequest{
+		Keyspace: keyspace,
+	}
+	if len(opts.Windows) > 0 || opts.TimeZone != "" || opts.MaxReplicationLag > 0 || opts.MaxThreadsRunning > 0 {
+		req.CutOverWindowPolicy = &topodatapb.CutOverWindowPolicy{
+			TimeZone:                 opts.TimeZone,
+			MaxReplicationLagSeconds: opts.MaxReplicationLag,
+			MaxThreadsRunning:        opts.MaxThreadsRunning,
+		}
+		for _, w := range opts.Windows {
+			window, err := schema.ParseCutOverWindow(w)
+			if err != nil {
+				return err
+			}
+			req.CutOverWindowPolicy.Windows = append(req.CutOverWindowPolicy.Windows, window)
+		}
+		if err := schema.ValidateCutOverWindowPolicy(req.CutOverWindowPolicy); err != nil {
+			return err
+		}
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.SetKeyspaceCutOverWindowPolicy(commandCtx, req)
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
 var setKeyspaceDurabilityPolicyOptions = struct {
-	DurabilityPolicy string
+	DurabilityPolicy           string
+	CustomDurabilityPolicy     string
+	CustomDurabilityPolicyFile string
 }{}
 
 func commandSetKeyspaceDurabilityPolicy(cmd *cobra.Command, args []string) error {
 	keyspace := cmd.Flags().Arg(0)
+	opts := setKeyspaceDurabilityPolicyOptions
+	if opts.CustomDurabilityPolicy != "" && opts.CustomDurabilityPolicyFile != "" {
+		return fmt.Errorf("cannot pass both --custom-durability-policy (=%s) and --custom-durability-policy-file (=%s)", opts.CustomDurabilityPolicy, opts.CustomDurabilityPolicyFile)
+	}
+
 	cli.FinishedParsing(cmd)
 
-	resp, err := client.SetKeyspaceDurabilityPolicy(commandCtx, &vtctldatapb.SetKeyspaceDurabilityPolicyRequest{
+	req := &vtctldatapb.SetKeyspaceDurabilityPolicyRequest{
 		Keyspace:         keyspace,
-		DurabilityPolicy: setKeyspaceDurabilityPolicyOptions.DurabilityPolicy,
-	})
+		DurabilityPolicy: opts.DurabilityPolicy,
+	}
+	customPolicy := []byte(opts.CustomDurabilityPolicy)
+	if opts.CustomDurabilityPolicyFile != "" {
+		data, err := os.ReadFile(opts.CustomDurabilityPolicyFile)
+		if err != nil {
+			return err
+		}
+		customPolicy = data
+	}
+	if len(customPolicy) > 0 {
+		req.CustomDurabilityPolicy = &topodatapb.CustomDurabilityPolicy{}
+		if err := json2.UnmarshalPB(customPolicy, req.CustomDurabilityPolicy); err != nil {
+			return err
+		}
+	}
+
+	resp, err := client.SetKeyspaceDurabilityPolicy(commandCtx, req)
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
+var setKeyspaceErrantGtidRemediationPolicyOptions = struct {
+	InjectEmptyTransactions bool
+	RestoreFromBackup       bool
+	MaxErrantTransactions   uint64
+}{}
+
+func commandSetKeyspaceErrantGtidRemediationPolicy(cmd *cobra.Command, args []string) error {
+	keyspace := cmd.Flags().Arg(0)
+	cli.FinishedParsing(cmd)
+
+	opts := setKeyspaceErrantGtidRemediationPolicyOptions
+	req := &vtctldatapb.SetKeyspaceErrantGtidRemediationPolicyRequest{
+		Keyspace: keyspace,
+	}
+	if opts.InjectEmptyTransactions || opts.RestoreFromBackup {
+		req.ErrantGtidRemediationPolicy = &topodatapb.ErrantGtidRemediationPolicy{
+			InjectEmptyTransactions: opts.InjectEmptyTransactions,
+			RestoreFromBackup:       opts.RestoreFromBackup,
+			MaxErrantTransactions:   opts.MaxErrantTransactions,
+		}
+	}
+
+	resp, err := client.SetKeyspaceErrantGtidRemediationPolicy(commandCtx, req)
 	if err != nil {
 		return err
 	}
@@ -385,8 +540,21 @@ func init() {
 	RemoveKeyspaceCell.Flags().BoolVarP(&removeKeyspaceCellOptions.Recursive, "recursive", "r", false, "Also delete all tablets in that cell beloning to the specified keyspace.")
 	Root.AddCommand(RemoveKeyspaceCell)
 
+	SetKeyspaceCutOverWindowPolicy.Flags().StringArrayVar(&setKeyspaceCutOverWindowPolicyOptions.Windows, "window", nil, "A window in which the migrations may cut over, as \"[days] HH:MM-HH:MM\", e.g. \"mon-fri 02:00-05:00\". May be repeated. The migrations may cut over at any time when no window is set.")
+	SetKeyspaceCutOverWindowPolicy.Flags().StringVar(&setKeyspaceCutOverWindowPolicyOptions.TimeZone, "time-zone", "", "The IANA time zone of the windows, e.g. \"America/New_York\". Defaults to UTC.")
+	SetKeyspaceCutOverWindowPolicy.Flags().Float64Var(&setKeyspaceCutOverWindowPolicyOptions.MaxReplicationLag, "max-replication-lag", 0, "Reject the cut-overs while the replication lag of the shard, in seconds, is above this value. The lag is not checked when it is 0.")
+	SetKeyspaceCutOverWindowPolicy.Flags().Float64Var(&setKeyspaceCutOverWindowPolicyOptions.MaxThreadsRunning, "max-threads-running", 0, "Reject the cut-overs while the threads_running metric of the primary is above this value. The metric is not checked when it is 0.")
+	Root.AddCommand(SetKeyspaceCutOverWindowPolicy)
+
 	SetKeyspaceDurabilityPolicy.Flags().StringVar(&setKeyspaceDurabilityPolicyOptions.DurabilityPolicy, "durability-policy", policy.DurabilityNone, "Type of durability to enforce for this keyspace. Default is none. Other values include 'semi_sync' and others as dictated by registered plugins.")
+	SetKeyspaceDurabilityPolicy.Flags().StringVar(&setKeyspaceDurabilityPolicyOptions.CustomDurabilityPolicy, "custom-durability-policy", "", "The custom durability policy of the keyspace, as JSON, when --durability-policy is 'custom'.")
+	SetKeyspaceDurabilityPolicy.Flags().StringVar(&setKeyspaceDurabilityPolicyOptions.CustomDurabilityPolicyFile, "custom-durability-policy-file", "", "Path to a file containing the custom durability policy of the keyspace, as JSON, when --durability-policy is 'custom'.")
 	Root.AddCommand(SetKeyspaceDurabilityPolicy)
 
+	SetKeyspaceErrantGtidRemediationPolicy.Flags().BoolVar(&setKeyspaceErrantGtidRemediationPolicyOptions.InjectEmptyTransactions, "inject-empty-transactions", false, "Allow VTOrc to inject empty transactions on the primary when the errant transactions of a replica do not write any data.")
+	SetKeyspaceErrantGtidRemediationPolicy.Flags().BoolVar(&setKeyspaceErrantGtidRemediationPolicyOptions.RestoreFromBackup, "restore-from-backup", false, "Allow VTOrc to restore a replica from a backup when its errant transactions write data.")
+	SetKeyspaceErrantGtidRemediationPolicy.Flags().Uint64Var(&setKeyspaceErrantGtidRemediationPolicyOptions.MaxErrantTransactions, "max-errant-transactions", 0, "Only remediate the replicas with at most this many errant transactions. There is no limit when it is 0.")
+	Root.AddCommand(SetKeyspaceErrantGtidRemediationPolicy)
+
 	Root.AddCommand(ValidateVersionKeyspace)
 }
@@ -42,6 +42,22 @@ var (
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandEmergencyReparentShard,
 	}
+	// EvacuateCell makes an EvacuateCell gRPC call to a vtctld.
+	EvacuateCell = &cobra.Command{
+		Use:   "EvacuateCell [--keyspaces <keyspace>[,<keyspace>...]] [--concurrency <n>] [--pause-between-batches <duration>] [--max-replication-lag <duration>] [--resume | --abort] <cell>",
+		Short: "Moves the primaries of all the shards out of the cell, with PlannedReparentShard.",
+		Long: `Moves the primaries of all the shards out of the cell, with PlannedReparentShard.
+
+The shards of the keyspaces passed with --keyspaces are reparented first, in that order, followed by the shards of
+the other keyspaces in alphabetical order. The shards of a keyspace are reparented in batches of --concurrency shards.
+
+The progress of the evacuation is recorded in the topology server. An evacuation that failed or was aborted can be
+resumed with --resume, which reparents the shards that were not evacuated yet. A running evacuation can be aborted
+with --abort, and stops once the batch of shards it is reparenting is done.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandEvacuateCell,
+	}
 	// InitShardPrimary makes an InitShardPrimary gRPC call to a vtctld.
 	InitShardPrimary = &cobra.Command{
 		Use:   "InitShardPrimary <keyspace/shard> <primary alias>",
@@ -156,6 +172,49 @@ func commandEmergencyReparentShard(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var evacuateCellOptions = struct {
+	Keyspaces           []string
+	Concurrency         uint32
+	PauseBetweenBatches time.Duration
+	MaxReplicationLag   time.Duration
+	WaitReplicasTimeout time.Duration
+	Resume              bool
+	Abort               bool
+}{}
+
+func commandEvacuateCell(cmd *cobra.Command, args []string) error {
+	cell := cmd.Flags().Arg(0)
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.EvacuateCell(commandCtx, &vtctldatapb.EvacuateCellRequest{
+		Cell:                cell,
+		Keyspaces:           evacuateCellOptions.Keyspaces,
+		Concurrency:         evacuateCellOptions.Concurrency,
+		PauseBetweenBatches: protoutil.DurationToProto(evacuateCellOptions.PauseBetweenBatches),
+		MaxReplicationLag:   protoutil.DurationToProto(evacuateCellOptions.MaxReplicationLag),
+		WaitReplicasTimeout: protoutil.DurationToProto(evacuateCellOptions.WaitReplicasTimeout),
+		Resume:              evacuateCellOptions.Resume,
+		Abort:               evacuateCellOptions.Abort,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp.Evacuation)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	if resp.Evacuation.State == topodatapb.CellEvacuation_FAILED {
+		return fmt.Errorf("some shards of cell %s could not be evacuated", cell)
+	}
+
+	return nil
+}
+
 var initShardPrimaryOptions = struct {
 	WaitReplicasTimeout time.Duration
 	Force               bool
@@ -312,6 +371,16 @@ func init() {
 	EmergencyReparentShard.Flags().StringSliceVarP(&emergencyReparentShardOptions.IgnoreReplicaAliasStrList, "ignore-replicas", "i", nil, "Comma-separated, repeated list of replica tablet aliases to ignore during the emergency reparent.")
 	Root.AddCommand(EmergencyReparentShard)
 
+	EvacuateCell.Flags().StringSliceVar(&evacuateCellOptions.Keyspaces, "keyspaces", nil, "Comma-separated list of keyspaces whose shards are evacuated first, in this order.")
+	EvacuateCell.Flags().Uint32Var(&evacuateCellOptions.Concurrency, "concurrency", 1, "Number of shards of a keyspace that are reparented at the same time.")
+	EvacuateCell.Flags().DurationVar(&evacuateCellOptions.PauseBetweenBatches, "pause-between-batches", 0, "Time to wait between two batches of shards.")
+	EvacuateCell.Flags().DurationVar(&evacuateCellOptions.MaxReplicationLag, "max-replication-lag", 0, "Replication lag above which a replica is not promoted. A shard without such a replica in another cell is not evacuated. 0 means the lag is not considered.")
+	EvacuateCell.Flags().DurationVar(&evacuateCellOptions.WaitReplicasTimeout, "wait-replicas-timeout", topo.RemoteOperationTimeout, "Time to wait for replicas to catch up on replication both before and after reparenting each shard.")
+	EvacuateCell.Flags().BoolVar(&evacuateCellOptions.Resume, "resume", false, "Resume the last evacuation of the cell, retrying the shards that failed.")
+	EvacuateCell.Flags().BoolVar(&evacuateCellOptions.Abort, "abort", false, "Abort the running evacuation of the cell.")
+	EvacuateCell.MarkFlagsMutuallyExclusive("resume", "abort")
+	Root.AddCommand(EvacuateCell)
+
 	InitShardPrimary.Flags().DurationVar(&initShardPrimaryOptions.WaitReplicasTimeout, "wait-replicas-timeout", 30*time.Second, "Time to wait for replicas to catch up in reparenting.")
 	InitShardPrimary.Flags().BoolVar(&initShardPrimaryOptions.Force, "force", false, "Force the reparent even if the provided tablet is not writable or the shard primary.")
 	Root.AddCommand(InitShardPrimary)
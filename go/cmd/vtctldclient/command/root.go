@@ -39,7 +39,9 @@ import (
 
 	// These imports ensure init()s within them get called and they register their commands/subcommands.
 	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	_ "vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/changeprimaryvindex"
 	vreplcommon "vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/common"
+	_ "vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/importworkflow"
 	_ "vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/lookupvindex"
 	_ "vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/materialize"
 	_ "vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/migrate"
@@ -91,6 +91,11 @@ func TestRootWithInternalVtctld(t *testing.T) {
 		{
 			command: "GetTablets",
 		},
+		{
+			command:   "ApplyDesiredSchema",
+			args:      []string{"--keyspace", "ks", "--dir", ".", "--poll-interval", "0s"},
+			expectErr: "--poll-interval must be a positive value",
+		},
 		{
 			command:   "NoCommandDrJones",
 			expectErr: "unknown command", // Invalid command
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -39,6 +40,23 @@ import (
 )
 
 var (
+	// ApplyDesiredSchema makes an ApplyDesiredSchema gRPC call to a vtctld.
+	ApplyDesiredSchema = &cobra.Command{
+		Use:   "ApplyDesiredSchema --keyspace <keyspace> --dir <dir> [--ddl-strategy <strategy>] [--migration-context <context>] [--caller-id <caller_id>] [--plan] [--wait=false] [--poll-interval <duration>]",
+		Short: "Applies the desired schema read from the CREATE statements of a directory to the specified keyspace, with online DDL migrations.",
+		Long: `Applies the desired schema read from the CREATE statements of a directory to the specified keyspace, with online DDL migrations.
+
+The .sql files of --dir hold the CREATE TABLE and CREATE VIEW statements of the desired schema. They are diffed against
+the schema of the keyspace, and the resulting statements are submitted as online DDL migrations with --ddl-strategy, in
+an order in which they can be applied. The tables and views of the keyspace that are not in the desired schema are dropped.
+
+With --plan, the statements of the migrations are printed without submitting them. Otherwise the progress of the
+migrations is printed until they are complete and the keyspace has the desired schema, unless --wait=false is passed.`,
+		Example:               "ApplyDesiredSchema --keyspace commerce --dir ./schema/commerce --ddl-strategy \"vitess --allow-concurrent\"",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		RunE:                  commandApplyDesiredSchema,
+	}
 	// ApplySchema makes an ApplySchema gRPC call to a vtctld.
 	ApplySchema = &cobra.Command{
 		Use:   "ApplySchema [--ddl-strategy <strategy>] [--uuid <uuid> ...] [--migration-context <context>] [--wait-replicas-timeout <duration>] [--caller-id <caller_id>] {--sql-file <file> | --sql <sql>} <keyspace>",
@@ -144,6 +162,188 @@ func (o *ApplySchemaOptions) CallerIDProto() *vtrpcpb.CallerID {
 	return nil
 }
 
+var applyDesiredSchemaOptions = struct {
+	Keyspace         string
+	Dir              string
+	DDLStrategy      string
+	MigrationContext string
+	CallerID         string
+	PlanOnly         bool
+	Wait             bool
+	PollInterval     time.Duration
+}{}
+
+func commandApplyDesiredSchema(cmd *cobra.Command, args []string) error {
+	if applyDesiredSchemaOptions.PollInterval <= 0 {
+		return fmt.Errorf("--poll-interval must be a positive value")
+	}
+	sql, err := readDesiredSchema(applyDesiredSchemaOptions.Dir)
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	var cid *vtrpcpb.CallerID
+	if applyDesiredSchemaOptions.CallerID != "" {
+		cid = &vtrpcpb.CallerID{Principal: applyDesiredSchemaOptions.CallerID}
+	}
+
+	req := &vtctldatapb.ApplyDesiredSchemaRequest{
+		Keyspace:         applyDesiredSchemaOptions.Keyspace,
+		Sql:              sql,
+		DdlStrategy:      applyDesiredSchemaOptions.DDLStrategy,
+		MigrationContext: applyDesiredSchemaOptions.MigrationContext,
+		PlanOnly:         applyDesiredSchemaOptions.PlanOnly,
+		CallerId:         cid,
+	}
+	resp, err := client.ApplyDesiredSchema(commandCtx, req)
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Statements) == 0 {
+		fmt.Printf("Keyspace %s has the desired schema.\n", req.Keyspace)
+		return nil
+	}
+
+	if req.PlanOnly {
+		for _, statement := range resp.Statements {
+			fmt.Printf("%s;\n", statement)
+		}
+		return nil
+	}
+
+	for i, uuid := range resp.UuidList {
+		if i < len(resp.Statements) {
+			fmt.Printf("%s: %s;\n", uuid, resp.Statements[i])
+		} else {
+			fmt.Println(uuid)
+		}
+	}
+
+	if !applyDesiredSchemaOptions.Wait {
+		return nil
+	}
+
+	if err := waitForSchemaMigrations(req.Keyspace, resp.UuidList, applyDesiredSchemaOptions.PollInterval); err != nil {
+		return err
+	}
+
+	// The keyspace converged once there is nothing left to apply.
+	req.PlanOnly = true
+	resp, err = client.ApplyDesiredSchema(commandCtx, req)
+	if err != nil {
+		return err
+	}
+	if len(resp.Statements) > 0 {
+		return fmt.Errorf("keyspace %s does not have the desired schema after the migrations, %d statements are left to apply", req.Keyspace, len(resp.Statements))
+	}
+
+	fmt.Printf("Keyspace %s has the desired schema.\n", req.Keyspace)
+	return nil
+}
+
+// readDesiredSchema returns the statements of the .sql files of the given
+// directory, in the alphabetical order of the files.
+func readDesiredSchema(dir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no .sql files in %s", dir)
+	}
+
+	var sql []string
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		parts, err := env.Parser().SplitStatementToPieces(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("cannot split %s into statements: %w", path, err)
+		}
+
+		sql = append(sql, parts...)
+	}
+
+	return sql, nil
+}
+
+// waitForSchemaMigrations prints the progress of the given migrations, every
+// pollInterval, until they are all complete. It returns an error as soon as
+// one of them failed or was cancelled.
+func waitForSchemaMigrations(keyspace string, uuids []string, pollInterval time.Duration) error {
+	reported := make(map[string]string, len(uuids))
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		pending := 0
+		for _, uuid := range uuids {
+			resp, err := client.GetSchemaMigrations(commandCtx, &vtctldatapb.GetSchemaMigrationsRequest{
+				Keyspace: keyspace,
+				Uuid:     uuid,
+			})
+			if err != nil {
+				return err
+			}
+
+			status, progress := schemaMigrationProgress(resp.Migrations)
+			report := fmt.Sprintf("%s: %s %.0f%%", uuid, strings.ToLower(status.String()), progress)
+			if reported[uuid] != report {
+				fmt.Println(report)
+				reported[uuid] = report
+			}
+
+			switch status {
+			case vtctldatapb.SchemaMigration_COMPLETE:
+			case vtctldatapb.SchemaMigration_FAILED, vtctldatapb.SchemaMigration_CANCELLED:
+				return fmt.Errorf("migration %s is %s", uuid, strings.ToLower(status.String()))
+			default:
+				pending++
+			}
+		}
+
+		if pending == 0 {
+			return nil
+		}
+
+		select {
+		case <-commandCtx.Done():
+			return commandCtx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// schemaMigrationProgress returns the status and the average progress of a
+// migration, from its rows on the shards of the keyspace. A migration is only
+// complete once it is complete on all the shards.
+func schemaMigrationProgress(migrations []*vtctldatapb.SchemaMigration) (vtctldatapb.SchemaMigration_Status, float32) {
+	if len(migrations) == 0 {
+		return vtctldatapb.SchemaMigration_REQUESTED, 0
+	}
+
+	status := vtctldatapb.SchemaMigration_COMPLETE
+	var progress float32
+	for _, migration := range migrations {
+		progress += migration.Progress
+		switch migration.Status {
+		case vtctldatapb.SchemaMigration_FAILED, vtctldatapb.SchemaMigration_CANCELLED:
+			return migration.Status, 0
+		case vtctldatapb.SchemaMigration_COMPLETE:
+		default:
+			status = migration.Status
+		}
+	}
+
+	return status, progress / float32(len(migrations))
+}
+
 var applySchemaOptions ApplySchemaOptions
 
 func commandApplySchema(cmd *cobra.Command, args []string) error {
@@ -441,6 +641,18 @@ func commandValidateSchemaShard(cmd *cobra.Command, args []string) error {
 }
 
 func init() {
+	ApplyDesiredSchema.Flags().StringVar(&applyDesiredSchemaOptions.Keyspace, "keyspace", "", "Keyspace to apply the desired schema to.")
+	ApplyDesiredSchema.MarkFlagRequired("keyspace")
+	ApplyDesiredSchema.Flags().StringVar(&applyDesiredSchemaOptions.Dir, "dir", "", "Directory of the .sql files holding the CREATE statements of the desired schema.")
+	ApplyDesiredSchema.MarkFlagRequired("dir")
+	ApplyDesiredSchema.Flags().StringVar(&applyDesiredSchemaOptions.DDLStrategy, "ddl-strategy", string(schema.DDLStrategyVitess), "Online DDL strategy of the migrations, compatible with @@ddl_strategy session variable (examples: 'vitess', 'mysql', 'vitess --allow-concurrent'). The 'direct' strategy is not allowed.")
+	ApplyDesiredSchema.Flags().StringVar(&applyDesiredSchemaOptions.MigrationContext, "migration-context", "", "Optional custom unique string used as context for the migrations. By default a unique context is auto-generated by Vitess.")
+	ApplyDesiredSchema.Flags().StringVar(&applyDesiredSchemaOptions.CallerID, "caller-id", "", "Effective caller ID used for the operation and should map to an ACL name which grants this identity the necessary permissions to perform the operation (this is only necessary when strict table ACLs are used).")
+	ApplyDesiredSchema.Flags().BoolVar(&applyDesiredSchemaOptions.PlanOnly, "plan", false, "Print the statements of the migrations without submitting them.")
+	ApplyDesiredSchema.Flags().BoolVar(&applyDesiredSchemaOptions.Wait, "wait", true, "Wait for the migrations to complete and for the keyspace to have the desired schema, printing their progress.")
+	ApplyDesiredSchema.Flags().DurationVar(&applyDesiredSchemaOptions.PollInterval, "poll-interval", 5*time.Second, "How often to poll the progress of the migrations.")
+	Root.AddCommand(ApplyDesiredSchema)
+
 	utils.SetFlagStringVar(ApplySchema.Flags(), &applySchemaOptions.DDLStrategy, "ddl-strategy", string(schema.DDLStrategyDirect), "Online DDL strategy, compatible with @@ddl_strategy session variable (examples: 'direct', 'mysql', 'vitess --postpone-completion'.")
 	ApplySchema.Flags().StringSliceVar(&applySchemaOptions.UUIDList, "uuid", nil, "Optional, comma-delimited, repeatable, explicit UUIDs for migration. If given, must match number of DDL changes.")
 	ApplySchema.Flags().StringVar(&applySchemaOptions.MigrationContext, "migration-context", "", "For Online DDL, optionally supply a custom unique string used as context for the migration(s) in this command. By default a unique context is auto-generated by Vitess.")
@@ -0,0 +1,339 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package changeprimaryvindex
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/common"
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// base is the base command for all actions related to ChangePrimaryVindex.
+	base = &cobra.Command{
+		Use:                   "ChangePrimaryVindex --workflow <workflow> --target-keyspace <keyspace> [command] [command-flags]",
+		Short:                 "Perform commands related to changing the primary vindex of a table in a sharded keyspace.",
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"changeprimaryvindex"},
+		Args:                  cobra.ExactArgs(1),
+	}
+
+	createOptions = struct {
+		Table                        string
+		PrimaryVindex                string
+		Columns                      []string
+		VindexType                   string
+		VindexParams                 map[string]string
+		Cells                        []string
+		TabletTypes                  []topodatapb.TabletType
+		TabletTypesInPreferenceOrder bool
+		DeferSecondaryKeys           bool
+	}{}
+
+	switchTrafficOptions = struct {
+		Timeout   time.Duration
+		SkipVDiff bool
+	}{}
+
+	reverseTrafficOptions = struct {
+		Timeout time.Duration
+	}{}
+
+	completeOptions = struct {
+		KeepData bool
+	}{}
+
+	cancelOptions = struct {
+		KeepData bool
+	}{}
+
+	// create makes a ChangePrimaryVindexCreate call to a vtctld.
+	create = &cobra.Command{
+		Use:                   "create",
+		Short:                 "Create and run a workflow which copies a table into a shadow table that is sharded by its new primary vindex, and recreates its owned lookup vindexes.",
+		Example:               `vtctldclient --server localhost:15999 ChangePrimaryVindex --workflow orders_by_account --target-keyspace commerce create --table orders --primary-vindex xxhash --columns account_id --vindex-type xxhash`,
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"Create"},
+		Args:                  cobra.NoArgs,
+		RunE:                  commandCreate,
+	}
+
+	// switchTraffic makes a ChangePrimaryVindexSwitchTraffic call to a vtctld.
+	switchTraffic = &cobra.Command{
+		Use:                   "switchtraffic",
+		Short:                 "Switch the table to its new primary vindex once the workflow has caught up and a VDiff found no differences.",
+		Example:               `vtctldclient --server localhost:15999 ChangePrimaryVindex --workflow orders_by_account --target-keyspace commerce switchtraffic`,
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"SwitchTraffic"},
+		Args:                  cobra.NoArgs,
+		RunE:                  commandSwitchTraffic,
+	}
+
+	// reverseTraffic makes a ChangePrimaryVindexReverseTraffic call to a vtctld.
+	reverseTraffic = &cobra.Command{
+		Use:                   "reversetraffic",
+		Short:                 "Switch the table back to its original primary vindex once the reverse workflow has caught up.",
+		Example:               `vtctldclient --server localhost:15999 ChangePrimaryVindex --workflow orders_by_account --target-keyspace commerce reversetraffic`,
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"ReverseTraffic"},
+		Args:                  cobra.NoArgs,
+		RunE:                  commandReverseTraffic,
+	}
+
+	// complete makes a ChangePrimaryVindexComplete call to a vtctld.
+	complete = &cobra.Command{
+		Use:                   "complete",
+		Short:                 "Complete a ChangePrimaryVindex workflow once traffic has been switched, dropping the original tables unless --keep-data is specified.",
+		Example:               `vtctldclient --server localhost:15999 ChangePrimaryVindex --workflow orders_by_account --target-keyspace commerce complete`,
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"Complete"},
+		Args:                  cobra.NoArgs,
+		RunE:                  commandComplete,
+	}
+
+	// cancel makes a ChangePrimaryVindexCancel call to a vtctld.
+	cancel = &cobra.Command{
+		Use:                   "cancel",
+		Short:                 "Cancel a ChangePrimaryVindex workflow whose traffic has not been switched, dropping the shadow tables unless --keep-data is specified.",
+		Example:               `vtctldclient --server localhost:15999 ChangePrimaryVindex --workflow orders_by_account --target-keyspace commerce cancel`,
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"Cancel"},
+		Args:                  cobra.NoArgs,
+		RunE:                  commandCancel,
+	}
+)
+
+func commandCreate(cmd *cobra.Command, args []string) error {
+	format, err := common.GetOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	cli.FinishedParsing(cmd)
+
+	req := &vtctldatapb.ChangePrimaryVindexCreateRequest{
+		Workflow: common.BaseOptions.Workflow,
+		Keyspace: common.BaseOptions.TargetKeyspace,
+		Table:    createOptions.Table,
+		PrimaryVindex: &vschemapb.ColumnVindex{
+			Name:    createOptions.PrimaryVindex,
+			Columns: createOptions.Columns,
+		},
+		Cells:              createOptions.Cells,
+		TabletTypes:        createOptions.TabletTypes,
+		DeferSecondaryKeys: createOptions.DeferSecondaryKeys,
+	}
+	if createOptions.VindexType != "" {
+		req.Vindexes = map[string]*vschemapb.Vindex{
+			createOptions.PrimaryVindex: {
+				Type:   createOptions.VindexType,
+				Params: createOptions.VindexParams,
+			},
+		}
+	}
+	if createOptions.TabletTypesInPreferenceOrder {
+		req.TabletSelectionPreference = tabletmanagerdatapb.TabletSelectionPreference_INORDER
+	}
+	resp, err := common.GetClient().ChangePrimaryVindexCreate(common.GetCommandCtx(), req)
+	if err != nil {
+		return err
+	}
+	if format == "json" {
+		data, err := cli.MarshalJSONPretty(resp)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", data)
+		return nil
+	}
+	fmt.Printf("ChangePrimaryVindex workflow %s created in the %s keyspace, copying table %s into %s", common.BaseOptions.Workflow,
+		common.BaseOptions.TargetKeyspace, createOptions.Table, resp.ShadowTable)
+	if len(resp.LookupVindexes) > 0 {
+		fmt.Printf(" and recreating the lookup vindexes %s", strings.Join(resp.LookupVindexes, ","))
+	}
+	fmt.Println(", use show to view progress")
+	return nil
+}
+
+func commandSwitchTraffic(cmd *cobra.Command, args []string) error {
+	format, err := common.GetOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	cli.FinishedParsing(cmd)
+
+	resp, err := common.GetClient().ChangePrimaryVindexSwitchTraffic(common.GetCommandCtx(), &vtctldatapb.ChangePrimaryVindexSwitchTrafficRequest{
+		Workflow:  common.BaseOptions.Workflow,
+		Keyspace:  common.BaseOptions.TargetKeyspace,
+		Timeout:   protoutil.DurationToProto(switchTrafficOptions.Timeout),
+		SkipVdiff: switchTrafficOptions.SkipVDiff,
+	})
+	if err != nil {
+		return err
+	}
+	if format == "json" {
+		data, err := cli.MarshalJSONPretty(resp)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", data)
+		return nil
+	}
+	fmt.Println(resp.Summary)
+	return nil
+}
+
+func commandReverseTraffic(cmd *cobra.Command, args []string) error {
+	format, err := common.GetOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	cli.FinishedParsing(cmd)
+
+	resp, err := common.GetClient().ChangePrimaryVindexReverseTraffic(common.GetCommandCtx(), &vtctldatapb.ChangePrimaryVindexReverseTrafficRequest{
+		Workflow: common.BaseOptions.Workflow,
+		Keyspace: common.BaseOptions.TargetKeyspace,
+		Timeout:  protoutil.DurationToProto(reverseTrafficOptions.Timeout),
+	})
+	if err != nil {
+		return err
+	}
+	if format == "json" {
+		data, err := cli.MarshalJSONPretty(resp)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", data)
+		return nil
+	}
+	fmt.Println(resp.Summary)
+	return nil
+}
+
+func commandComplete(cmd *cobra.Command, args []string) error {
+	format, err := common.GetOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	cli.FinishedParsing(cmd)
+
+	resp, err := common.GetClient().ChangePrimaryVindexComplete(common.GetCommandCtx(), &vtctldatapb.ChangePrimaryVindexCompleteRequest{
+		Workflow: common.BaseOptions.Workflow,
+		Keyspace: common.BaseOptions.TargetKeyspace,
+		KeepData: completeOptions.KeepData,
+	})
+	if err != nil {
+		return err
+	}
+	if format == "json" {
+		data, err := cli.MarshalJSONPretty(resp)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", data)
+		return nil
+	}
+	fmt.Println(resp.Summary)
+	return nil
+}
+
+func commandCancel(cmd *cobra.Command, args []string) error {
+	format, err := common.GetOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	cli.FinishedParsing(cmd)
+
+	resp, err := common.GetClient().ChangePrimaryVindexCancel(common.GetCommandCtx(), &vtctldatapb.ChangePrimaryVindexCancelRequest{
+		Workflow: common.BaseOptions.Workflow,
+		Keyspace: common.BaseOptions.TargetKeyspace,
+		KeepData: cancelOptions.KeepData,
+	})
+	if err != nil {
+		return err
+	}
+	if format == "json" {
+		data, err := cli.MarshalJSONPretty(resp)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", data)
+		return nil
+	}
+	fmt.Println(resp.Summary)
+	return nil
+}
+
+func registerCommands(root *cobra.Command) {
+	common.AddCommonFlags(base)
+	root.AddCommand(base)
+
+	create.Flags().StringVar(&createOptions.Table, "table", "", "The table whose primary vindex is changed.")
+	create.MarkFlagRequired("table")
+	create.Flags().StringVar(&createOptions.PrimaryVindex, "primary-vindex", "", "The name of the vindex that will become the table's primary vindex.")
+	create.MarkFlagRequired("primary-vindex")
+	create.Flags().StringSliceVar(&createOptions.Columns, "columns", nil, "The columns of the table that the new primary vindex uses.")
+	create.MarkFlagRequired("columns")
+	create.Flags().StringVar(&createOptions.VindexType, "vindex-type", "", "The type of the new primary vindex, when it needs to be added to the keyspace's vschema.")
+	create.Flags().StringToStringVar(&createOptions.VindexParams, "vindex-params", nil, "The params of the new primary vindex, when it needs to be added to the keyspace's vschema.")
+	create.Flags().StringSliceVarP(&createOptions.Cells, "cells", "c", nil, "Cells and/or CellAliases to copy table data from.")
+	create.Flags().Var((*topoproto.TabletTypeListFlag)(&createOptions.TabletTypes), "tablet-types", "Source tablet types to replicate table data from (e.g. PRIMARY,REPLICA,RDONLY).")
+	create.Flags().BoolVar(&createOptions.TabletTypesInPreferenceOrder, "tablet-types-in-preference-order", true, "When performing source tablet selection, look for candidates in the type order as they are listed in the tablet-types flag.")
+	create.Flags().BoolVar(&createOptions.DeferSecondaryKeys, "defer-secondary-keys", true, "Defer secondary index creation for a table until after it has been copied.")
+	base.AddCommand(create)
+
+	switchTraffic.Flags().DurationVar(&switchTrafficOptions.Timeout, "timeout", 30*time.Second, "The maximum time to wait for the workflows to catch up once queries to the table have been denied.")
+	switchTraffic.Flags().BoolVar(&switchTrafficOptions.SkipVDiff, "skip-vdiff", false, "Switch traffic without requiring a completed VDiff of the workflow that found no differences.")
+	base.AddCommand(switchTraffic)
+
+	reverseTraffic.Flags().DurationVar(&reverseTrafficOptions.Timeout, "timeout", 30*time.Second, "The maximum time to wait for the reverse workflows to catch up once queries to the table have been denied.")
+	base.AddCommand(reverseTraffic)
+
+	complete.Flags().BoolVar(&completeOptions.KeepData, "keep-data", false, "Keep the original tables and their data.")
+	base.AddCommand(complete)
+
+	cancel.Flags().BoolVar(&cancelOptions.KeepData, "keep-data", false, "Keep the shadow tables and their data.")
+	base.AddCommand(cancel)
+
+	// The show and status commands are shared with the other workflows.
+	opts := &common.SubCommandsOpts{
+		SubCommand: "ChangePrimaryVindex",
+		Workflow:   "orders_by_account",
+	}
+	base.AddCommand(common.GetShowCommand(opts))
+	base.AddCommand(common.GetStatusCommand(opts))
+}
+
+func init() {
+	common.RegisterCommandHandler("ChangePrimaryVindex", registerCommands)
+}
@@ -0,0 +1,290 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importworkflow
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/common"
+	"vitess.io/vitess/go/protoutil"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// base is the base command for all actions related to Import.
+	base = &cobra.Command{
+		Use:                   "Import --workflow <workflow> --target-keyspace <keyspace> [command] [command-flags]",
+		Short:                 "Perform commands related to importing tables from an external MySQL or MariaDB server that is not managed by Vitess.",
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"import"},
+		Args:                  cobra.ExactArgs(1),
+	}
+
+	createOptions = struct {
+		ExternalMysql      string
+		SourceHost         string
+		SourcePort         int32
+		SourceUser         string
+		SourcePassword     string
+		SourceDBName       string
+		AllTables          bool
+		IncludeTables      []string
+		ExcludeTables      []string
+		TableFilters       []string
+		SourceTimeZone     string
+		OnDDL              string
+		StopAfterCopy      bool
+		DeferSecondaryKeys bool
+		AutoStart          bool
+	}{}
+
+	completeOptions = struct {
+		Timeout time.Duration
+	}{}
+
+	cancelOptions = struct {
+		KeepData bool
+	}{}
+
+	// create makes an ImportCreate call to a vtctld.
+	create = &cobra.Command{
+		Use:                   "create",
+		Short:                 "Create and optionally run an Import workflow which copies tables from an external MySQL into the target keyspace and keeps them up to date.",
+		Example:               `vtctldclient --server localhost:15999 Import --workflow legacy2commerce --target-keyspace commerce create --external-mysql legacy --tables customer,corder --table-filter "corder:created_at >= '2024-01-01'"`,
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"Create"},
+		Args:                  cobra.NoArgs,
+		PreRunE:               validateCreate,
+		RunE:                  commandCreate,
+	}
+
+	// complete makes an ImportComplete call to a vtctld.
+	complete = &cobra.Command{
+		Use:                   "complete",
+		Short:                 "Complete an Import workflow once writes have been locked on the external MySQL, after confirming that the target has caught up with it.",
+		Example:               `vtctldclient --server localhost:15999 Import --workflow legacy2commerce --target-keyspace commerce complete`,
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"Complete"},
+		Args:                  cobra.NoArgs,
+		RunE:                  commandComplete,
+	}
+
+	// cancel makes an ImportCancel call to a vtctld.
+	cancel = &cobra.Command{
+		Use:                   "cancel",
+		Short:                 "Cancel an Import workflow, dropping the imported tables unless --keep-data is specified.",
+		Example:               `vtctldclient --server localhost:15999 Import --workflow legacy2commerce --target-keyspace commerce cancel`,
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"Cancel"},
+		Args:                  cobra.NoArgs,
+		RunE:                  commandCancel,
+	}
+)
+
+func validateCreate(cmd *cobra.Command, args []string) error {
+	if createOptions.AllTables && len(createOptions.IncludeTables) > 0 {
+		return fmt.Errorf("cannot specify both --all-tables and --tables")
+	}
+	if !createOptions.AllTables && len(createOptions.IncludeTables) == 0 {
+		return fmt.Errorf("must specify either --all-tables or --tables")
+	}
+	if _, ok := binlogdatapb.OnDDLAction_value[strings.ToUpper(createOptions.OnDDL)]; !ok {
+		return fmt.Errorf("invalid on-ddl value: %s", createOptions.OnDDL)
+	}
+	if _, err := parseTableFilters(createOptions.TableFilters); err != nil {
+		return err
+	}
+	if createOptions.SourceHost == "" && (createOptions.SourcePort != 0 || createOptions.SourceUser != "" || createOptions.SourcePassword != "" || createOptions.SourceDBName != "") {
+		return fmt.Errorf("--source-host must be specified along with the other --source connection flags")
+	}
+	if createOptions.SourceHost != "" && (createOptions.SourceUser == "" || createOptions.SourceDBName == "") {
+		return fmt.Errorf("--source-user and --source-db-name must be specified along with --source-host")
+	}
+	return nil
+}
+
+// parseTableFilters parses filters specified as table:expression into a map
+// of table name to the expression used in the where clause.
+func parseTableFilters(filters []string) (map[string]string, error) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+	tableFilters := make(map[string]string, len(filters))
+	for _, filter := range filters {
+		table, expr, ok := strings.Cut(filter, ":")
+		table, expr = strings.TrimSpace(table), strings.TrimSpace(expr)
+		if !ok || table == "" || expr == "" {
+			return nil, fmt.Errorf("invalid table filter %q, it must be specified as table:expression", filter)
+		}
+		if _, exists := tableFilters[table]; exists {
+			return nil, fmt.Errorf("more than one filter specified for table %s", table)
+		}
+		tableFilters[table] = expr
+	}
+	return tableFilters, nil
+}
+
+func commandCreate(cmd *cobra.Command, args []string) error {
+	format, err := common.GetOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	tableFilters, err := parseTableFilters(createOptions.TableFilters)
+	if err != nil {
+		return err
+	}
+	cli.FinishedParsing(cmd)
+
+	resp, err := common.GetClient().ImportCreate(common.GetCommandCtx(), &vtctldatapb.ImportCreateRequest{
+		Workflow:           common.BaseOptions.Workflow,
+		TargetKeyspace:     common.BaseOptions.TargetKeyspace,
+		ExternalMysql:      createOptions.ExternalMysql,
+		SourceHost:         createOptions.SourceHost,
+		SourcePort:         createOptions.SourcePort,
+		SourceUser:         createOptions.SourceUser,
+		SourcePassword:     createOptions.SourcePassword,
+		SourceDbName:       createOptions.SourceDBName,
+		AllTables:          createOptions.AllTables,
+		IncludeTables:      createOptions.IncludeTables,
+		ExcludeTables:      createOptions.ExcludeTables,
+		TableFilters:       tableFilters,
+		SourceTimeZone:     createOptions.SourceTimeZone,
+		OnDdl:              strings.ToUpper(createOptions.OnDDL),
+		StopAfterCopy:      createOptions.StopAfterCopy,
+		DeferSecondaryKeys: createOptions.DeferSecondaryKeys,
+		AutoStart:          createOptions.AutoStart,
+	})
+	if err != nil {
+		return err
+	}
+	if format == "json" {
+		data, err := cli.MarshalJSONPretty(resp)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", data)
+		return nil
+	}
+	fmt.Printf("Import workflow %s created in the %s keyspace for tables %s, use show to view progress\n",
+		common.BaseOptions.Workflow, common.BaseOptions.TargetKeyspace, strings.Join(resp.Tables, ","))
+	return nil
+}
+
+func commandComplete(cmd *cobra.Command, args []string) error {
+	format, err := common.GetOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	cli.FinishedParsing(cmd)
+
+	resp, err := common.GetClient().ImportComplete(common.GetCommandCtx(), &vtctldatapb.ImportCompleteRequest{
+		Workflow:       common.BaseOptions.Workflow,
+		TargetKeyspace: common.BaseOptions.TargetKeyspace,
+		Timeout:        protoutil.DurationToProto(completeOptions.Timeout),
+	})
+	if err != nil {
+		return err
+	}
+	if format == "json" {
+		data, err := cli.MarshalJSONPretty(resp)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", data)
+		return nil
+	}
+	fmt.Println(resp.Summary)
+	return nil
+}
+
+func commandCancel(cmd *cobra.Command, args []string) error {
+	format, err := common.GetOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	cli.FinishedParsing(cmd)
+
+	resp, err := common.GetClient().ImportCancel(common.GetCommandCtx(), &vtctldatapb.ImportCancelRequest{
+		Workflow:       common.BaseOptions.Workflow,
+		TargetKeyspace: common.BaseOptions.TargetKeyspace,
+		KeepData:       cancelOptions.KeepData,
+	})
+	if err != nil {
+		return err
+	}
+	if format == "json" {
+		data, err := cli.MarshalJSONPretty(resp)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", data)
+		return nil
+	}
+	fmt.Println(resp.Summary)
+	return nil
+}
+
+func registerCommands(root *cobra.Command) {
+	common.AddCommonFlags(base)
+	root.AddCommand(base)
+
+	create.Flags().StringVar(&createOptions.ExternalMysql, "external-mysql", "", "The name of the external MySQL to import the tables from, as configured in the target tablets' external dbconfigs, or under which the --source connection flags are registered.")
+	create.MarkFlagRequired("external-mysql")
+	create.Flags().StringVar(&createOptions.SourceHost, "source-host", "", "The host of the external MySQL, when it is not configured in the target tablets' external dbconfigs. The connection flags are registered in the topo until the workflow is completed or cancelled.")
+	create.Flags().Int32Var(&createOptions.SourcePort, "source-port", 0, "The port of the external MySQL. Defaults to 3306 when --source-host is specified.")
+	create.Flags().StringVar(&createOptions.SourceUser, "source-user", "", "The user to connect to the external MySQL with.")
+	create.Flags().StringVar(&createOptions.SourcePassword, "source-password", "", "The password of --source-user. When it is not specified, the target tablets look it up with their --db-credentials-server.")
+	create.Flags().StringVar(&createOptions.SourceDBName, "source-db-name", "", "The database to import the tables from on the external MySQL.")
+	create.Flags().BoolVar(&createOptions.AllTables, "all-tables", false, "Import all base tables in the external MySQL's database.")
+	create.Flags().StringSliceVar(&createOptions.IncludeTables, "tables", nil, "Tables to import from the external MySQL.")
+	create.Flags().StringSliceVar(&createOptions.ExcludeTables, "exclude-tables", nil, "Tables to exclude when using --all-tables.")
+	create.Flags().StringArrayVar(&createOptions.TableFilters, "table-filter", nil, "A filter, specified as table:expression, used in the where clause when selecting the table's rows on the external MySQL. May be repeated.")
+	create.Flags().StringVar(&createOptions.SourceTimeZone, "source-time-zone", "", "Specifying this causes any DATETIME fields to be converted from the given time zone into UTC.")
+	create.Flags().StringVar(&createOptions.OnDDL, "on-ddl", binlogdatapb.OnDDLAction_IGNORE.String(), "What to do when DDL is encountered in the VReplication stream. Possible values are IGNORE, STOP, EXEC, EXEC_IGNORE, and EXEC_SAFE.")
+	create.Flags().BoolVar(&createOptions.StopAfterCopy, "stop-after-copy", false, "Stop the workflow after it's finished copying the existing rows and before it starts replicating changes.")
+	create.Flags().BoolVar(&createOptions.DeferSecondaryKeys, "defer-secondary-keys", true, "Defer secondary index creation for a table until after it has been copied.")
+	create.Flags().BoolVar(&createOptions.AutoStart, "auto-start", true, "Start the workflow after creating it.")
+	base.AddCommand(create)
+
+	complete.Flags().DurationVar(&completeOptions.Timeout, "timeout", 30*time.Second, "The maximum time to wait for the workflow to catch up with the write locked external MySQL.")
+	base.AddCommand(complete)
+
+	cancel.Flags().BoolVar(&cancelOptions.KeepData, "keep-data", false, "Keep the imported tables and their data in the target keyspace.")
+	base.AddCommand(cancel)
+
+	// The show and status commands are shared with the other workflows.
+	opts := &common.SubCommandsOpts{
+		SubCommand: "Import",
+		Workflow:   "legacy2commerce",
+	}
+	base.AddCommand(common.GetShowCommand(opts))
+	base.AddCommand(common.GetStatusCommand(opts))
+}
+
+func init() {
+	common.RegisterCommandHandler("Import", registerCommands)
+}
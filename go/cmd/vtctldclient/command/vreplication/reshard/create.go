@@ -17,26 +17,44 @@ limitations under the License.
 package reshard
 
 import (
+	"fmt"
+	"os"
+	"strings"
+
 	"github.com/spf13/cobra"
 
 	"vitess.io/vitess/go/cmd/vtctldclient/cli"
 	"vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/common"
+	"vitess.io/vitess/go/json2"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/vschemaadvisor"
 
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
 var (
 	reshardCreateOptions = struct {
-		sourceShards   []string
-		targetShards   []string
-		skipSchemaCopy bool
+		sourceShards        []string
+		targetShards        []string
+		skipSchemaCopy      bool
+		vschemaFile         string
+		querySampleFile     string
+		queryLogFile        string
+		allowScatterQueries bool
+		dryRun              bool
 	}{}
 
 	// reshardCreate makes a ReshardCreate gRPC call to a vtctld.
 	reshardCreate = &cobra.Command{
-		Use:                   "create",
-		Short:                 "Create and optionally run a Reshard VReplication workflow.",
-		Example:               `vtctldclient --server localhost:15999 reshard --workflow customer2customer --target-keyspace customer create --source-shards="0" --target-shards="-80,80-" --cells zone1 --cells zone2 --tablet-types replica`,
+		Use:   "create",
+		Short: "Create and optionally run a Reshard VReplication workflow.",
+		Long: `Create and optionally run a Reshard VReplication workflow.
+
+When an unsharded keyspace is being sharded, --vschema-file provides the VSchema of the sharded keyspace, and --query-sample-file or --querylog-file a sample of the queries of the application. The queries are first planned against the VSchema: the workflow is not created if any of them cannot be planned, or is a scatter query unless --allow-scatter-queries is set. Otherwise the VSchema is applied to the keyspace and the workflow is created, and SwitchTraffic plans the queries again against the VSchema of the keyspace before switching traffic. --dry-run only reports how the queries are routed.`,
+		Example: `vtctldclient --server localhost:15999 reshard --workflow customer2customer --target-keyspace customer create --source-shards="0" --target-shards="-80,80-" --cells zone1 --cells zone2 --tablet-types replica
+
+vtctldclient --server localhost:15999 reshard --workflow customer2customer --target-keyspace customer create --source-shards="0" --target-shards="-80,80-" --vschema-file vschema.json --querylog-file querylog.json --dry-run`,
 		SilenceUsage:          true,
 		DisableFlagsInUseLine: true,
 		Aliases:               []string{"Create"},
@@ -64,7 +82,22 @@ func commandReshardCreate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	workflowOptions := &vtctldatapb.WorkflowOptions{
-		Config: configOverrides,
+		Config:              configOverrides,
+		AllowScatterQueries: reshardCreateOptions.allowScatterQueries,
+	}
+	if workflowOptions.QuerySample, err = readQuerySample(); err != nil {
+		return err
+	}
+	var vschema *vschemapb.Keyspace
+	if reshardCreateOptions.vschemaFile != "" {
+		data, err := os.ReadFile(reshardCreateOptions.vschemaFile)
+		if err != nil {
+			return err
+		}
+		vschema = &vschemapb.Keyspace{}
+		if err := json2.UnmarshalPB(data, vschema); err != nil {
+			return fmt.Errorf("invalid vschema in %s: %w", reshardCreateOptions.vschemaFile, err)
+		}
 	}
 
 	req := &vtctldatapb.ReshardCreateRequest{
@@ -81,21 +114,99 @@ func commandReshardCreate(cmd *cobra.Command, args []string) error {
 		TargetShards:              reshardCreateOptions.targetShards,
 		SkipSchemaCopy:            reshardCreateOptions.skipSchemaCopy,
 		WorkflowOptions:           workflowOptions,
+		Vschema:                   vschema,
+		DryRun:                    reshardCreateOptions.dryRun,
 	}
 	resp, err := common.GetClient().ReshardCreate(common.GetCommandCtx(), req)
 	if err != nil {
 		return err
 	}
+	if format == "text" && len(resp.QueryRouting) > 0 {
+		outputQueryRouting(resp.QueryRouting)
+		if req.DryRun {
+			return nil
+		}
+	}
 	if err = common.OutputStatusResponse(resp, format); err != nil {
 		return err
 	}
 	return nil
 }
 
+// readQuerySample reads the queries of --query-sample-file, separated by
+// semicolons, or of the vtgate query log of --querylog-file.
+func readQuerySample() ([]string, error) {
+	opts := reshardCreateOptions
+	switch {
+	case opts.querySampleFile != "" && opts.queryLogFile != "":
+		return nil, fmt.Errorf("only one of --query-sample-file or --querylog-file may be specified")
+	case opts.querySampleFile != "":
+		data, err := os.ReadFile(opts.querySampleFile)
+		if err != nil {
+			return nil, err
+		}
+		parser, err := sqlparser.New(sqlparser.Options{})
+		if err != nil {
+			return nil, err
+		}
+		queries, err := vschemaadvisor.ParseQueries(parser, string(data))
+		if err != nil {
+			return nil, err
+		}
+		return querySQL(queries), nil
+	case opts.queryLogFile != "":
+		f, err := os.Open(opts.queryLogFile)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		queries, err := vschemaadvisor.ParseQueryLog(f)
+		if err != nil {
+			return nil, err
+		}
+		return querySQL(queries), nil
+	case opts.dryRun:
+		return nil, fmt.Errorf("--dry-run requires --query-sample-file or --querylog-file")
+	}
+	return nil, nil
+}
+
+func querySQL(queries []*vschemaadvisor.Query) []string {
+	sqls := make([]string, 0, len(queries))
+	for _, q := range queries {
+		sqls = append(sqls, q.SQL)
+	}
+	return sqls
+}
+
+func outputQueryRouting(routing []*vtctldatapb.QueryRouting) {
+	counts := make(map[string]int)
+	var sb strings.Builder
+	for _, qr := range routing {
+		counts[qr.Routing]++
+		if qr.Routing == vschemaadvisor.RoutingScatter || qr.Routing == vschemaadvisor.RoutingUnsupported {
+			fmt.Fprintf(&sb, "  [%s] %s", qr.Routing, qr.Query)
+			if qr.Error != "" {
+				fmt.Fprintf(&sb, ": %s", qr.Error)
+			}
+			sb.WriteString("\n")
+		}
+	}
+	fmt.Printf("Routing of the %d queries of the query sample: %d single-shard, %d multi-shard, %d cross-shard, %d scatter, %d unsupported\n",
+		len(routing), counts[vschemaadvisor.RoutingSingleShard], counts[vschemaadvisor.RoutingMultiShard], counts[vschemaadvisor.RoutingCrossShard],
+		counts[vschemaadvisor.RoutingScatter], counts[vschemaadvisor.RoutingUnsupported])
+	fmt.Println(sb.String())
+}
+
 func registerCreateCommand(root *cobra.Command) {
 	common.AddCommonCreateFlags(reshardCreate)
 	reshardCreate.Flags().StringSliceVar(&reshardCreateOptions.sourceShards, "source-shards", nil, "Source shards.")
 	reshardCreate.Flags().StringSliceVar(&reshardCreateOptions.targetShards, "target-shards", nil, "Target shards.")
 	reshardCreate.Flags().BoolVar(&reshardCreateOptions.skipSchemaCopy, "skip-schema-copy", false, "Skip copying the schema from the source shards to the target shards.")
+	reshardCreate.Flags().StringVar(&reshardCreateOptions.vschemaFile, "vschema-file", "", "Path to a file containing the VSchema of the resharded keyspace, in JSON form, to apply to the keyspace before creating the workflow.")
+	reshardCreate.Flags().StringVar(&reshardCreateOptions.querySampleFile, "query-sample-file", "", "Path to a file containing a sample of the queries of the application, separated by semicolons, that must be routable before creating the workflow and switching traffic.")
+	reshardCreate.Flags().StringVar(&reshardCreateOptions.queryLogFile, "querylog-file", "", "Path to a vtgate query log, in JSON format, whose queries are used as the query sample.")
+	reshardCreate.Flags().BoolVar(&reshardCreateOptions.allowScatterQueries, "allow-scatter-queries", false, "Allow the queries of the query sample to be scatter queries.")
+	reshardCreate.Flags().BoolVar(&reshardCreateOptions.dryRun, "dry-run", false, "Only report how the queries of the query sample are routed, without applying the VSchema or creating the workflow.")
 	root.AddCommand(reshardCreate)
 }
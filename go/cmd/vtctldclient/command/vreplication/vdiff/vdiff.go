@@ -21,6 +21,7 @@ import (
 	"html/template"
 	"io"
 	"math"
+	"os"
 	"reflect"
 	"strings"
 	"time"
@@ -69,12 +70,23 @@ var (
 		MaxDiffDuration             time.Duration
 		RowDiffColumnTruncateAt     int64
 		AutoStart                   bool
+		ChunkRows                   int64
+		RediffUUID                  string
+		ChunkConcurrency            int64
 	}{}
 
 	deleteOptions = struct {
 		Arg string
 	}{}
 
+	repairOptions = struct {
+		UUID       uuid.UUID
+		Tables     []string
+		Apply      bool
+		BatchSize  int64
+		OutputFile string
+	}{}
+
 	resumeOptions = struct {
 		UUID         uuid.UUID
 		TargetShards []string
@@ -127,6 +139,17 @@ var (
 		if createOptions.MaxExtraRowsToCompare < 0 {
 			return fmt.Errorf("--max-extra-rows-to-compare must not be a negative value")
 		}
+		if createOptions.ChunkRows < 0 {
+			return fmt.Errorf("--chunk-rows must not be a negative value")
+		}
+		if createOptions.ChunkConcurrency < 1 {
+			return fmt.Errorf("--chunk-concurrency must be at least 1")
+		}
+		if createOptions.RediffUUID != "" {
+			if _, err = uuid.Parse(createOptions.RediffUUID); err != nil {
+				return fmt.Errorf("invalid --rediff-uuid provided: %v", err)
+			}
+		}
 		return nil
 	}
 
@@ -178,6 +201,37 @@ vtctldclient --server localhost:15999 vdiff --workflow commerce2customer --targe
 		RunE: commandDelete,
 	}
 
+	// repair makes a VDiffRepair gRPC call to a vtctld.
+	repair = &cobra.Command{
+		Use:   "repair",
+		Short: "Generate, and optionally apply, the statements needed to make the target match the source for the rows that differed in a completed VDiff.",
+		Long: `Generate, and optionally apply, the statements needed to make the target match the source for the rows that differed in a completed VDiff.
+The current version of each row is read from the source and target primaries and an INSERT, UPDATE, or DELETE statement is generated for the target.
+The VDiff's report must include every row that differs, so it should be created with --max-report-sample-rows=0 (and --only-pks to keep the report small).
+The workflow must be stopped as the repair is refused while the workflow is still replicating writes for the tables being repaired.`,
+		Example: `vtctldclient --server localhost:15999 vdiff --workflow commerce2customer --target-keyspace customer repair a037a9e2-5628-11ee-8c99-0242ac120002 --output /tmp/repair.sql
+vtctldclient --server localhost:15999 vdiff --workflow commerce2customer --target-keyspace customer repair a037a9e2-5628-11ee-8c99-0242ac120002 --tables customer --apply`,
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"Repair"},
+		Args:                  cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			uuid, err := uuid.Parse(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid UUID provided: %v", err)
+			}
+			repairOptions.UUID = uuid
+			for i, table := range repairOptions.Tables {
+				repairOptions.Tables[i] = strings.TrimSpace(table)
+			}
+			if repairOptions.BatchSize < 1 {
+				return fmt.Errorf("--batch-size must be a positive value")
+			}
+			return nil
+		},
+		RunE: commandRepair,
+	}
+
 	// resume makes a VDiffResume gRPC call to a vtctld.
 	resume = &cobra.Command{
 		Use:                   "resume",
@@ -300,6 +354,9 @@ func commandCreate(cmd *cobra.Command, args []string) error {
 		MaxDiffDuration:             protoutil.DurationToProto(createOptions.MaxDiffDuration),
 		RowDiffColumnTruncateAt:     createOptions.RowDiffColumnTruncateAt,
 		AutoStart:                   &createOptions.AutoStart,
+		ChunkRows:                   createOptions.ChunkRows,
+		RediffUuid:                  createOptions.RediffUUID,
+		ChunkConcurrency:            createOptions.ChunkConcurrency,
 	})
 
 	if err != nil {
@@ -372,6 +429,83 @@ func commandDelete(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func commandRepair(cmd *cobra.Command, args []string) error {
+	format, err := common.GetOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	cli.FinishedParsing(cmd)
+
+	resp, err := common.GetClient().VDiffRepair(common.GetCommandCtx(), &vtctldatapb.VDiffRepairRequest{
+		Workflow:       common.BaseOptions.Workflow,
+		TargetKeyspace: common.BaseOptions.TargetKeyspace,
+		Uuid:           repairOptions.UUID.String(),
+		Tables:         repairOptions.Tables,
+		Apply:          repairOptions.Apply,
+		BatchSize:      repairOptions.BatchSize,
+	})
+	if err != nil {
+		return err
+	}
+
+	if repairOptions.OutputFile != "" {
+		f, err := os.Create(repairOptions.OutputFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := writeRepairStatements(f, common.BaseOptions.TargetKeyspace, repairOptions.UUID.String(), resp); err != nil {
+			return err
+		}
+		return f.Close()
+	}
+	return displayRepairResponse(cmd.OutOrStdout(), format, common.BaseOptions.TargetKeyspace, repairOptions.UUID.String(), resp)
+}
+
+// displayRepairResponse displays the statements generated for a repair.
+func displayRepairResponse(out io.Writer, format, keyspace, uuid string, resp *vtctldatapb.VDiffRepairResponse) error {
+	if format == "json" {
+		jsonText, err := cli.MarshalJSONPretty(resp)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, string(jsonText))
+		return nil
+	}
+	if len(resp.Statements) == 0 {
+		fmt.Fprintln(out, "VDiff repair found no rows to repair")
+		return nil
+	}
+	if err := writeRepairStatements(out, keyspace, uuid, resp); err != nil {
+		return err
+	}
+	if resp.Applied {
+		fmt.Fprintf(out, "VDiff repair applied %d statements\n", len(resp.Statements))
+	}
+	return nil
+}
+
+// writeRepairStatements writes the repair statements as SQL, grouped by the
+// target shard whose primary they must be executed on.
+func writeRepairStatements(w io.Writer, keyspace, uuid string, resp *vtctldatapb.VDiffRepairResponse) error {
+	if _, err := fmt.Fprintf(w, "-- Repair statements for VDiff %s on keyspace %s\n", uuid, keyspace); err != nil {
+		return err
+	}
+	shard := ""
+	for i, stmt := range resp.Statements {
+		if i == 0 || stmt.Shard != shard {
+			shard = stmt.Shard
+			if _, err := fmt.Fprintf(w, "-- Shard: %s/%s\n", keyspace, shard); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s;\n", stmt.Sql); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func commandResume(cmd *cobra.Command, args []string) error {
 	format, err := common.GetOutputFormat(cmd)
 	if err != nil {
@@ -683,10 +817,19 @@ func registerCommands(root *cobra.Command) {
 	create.Flags().DurationVar(&createOptions.MaxDiffDuration, "max-diff-duration", 0, "How long should an individual table diff run before being stopped and restarted in order to lessen the impact on tablets due to holding open database snapshots for long periods of time (0 is the default and means no time limit).")
 	create.Flags().Int64Var(&createOptions.RowDiffColumnTruncateAt, "row-diff-column-truncate-at", 128, "When showing row differences, truncate the non Primary Key column values to this length. A value less than 1 means do not truncate.")
 	create.Flags().BoolVar(&createOptions.AutoStart, "auto-start", true, "Start the vdiff upon creation. When false, the vdiff will be created but will not run until resumed.")
+	create.Flags().Int64Var(&createOptions.ChunkRows, "chunk-rows", 0, "Diff each table in chunks of this many rows, based on the primary key, recording the progress of each chunk so that the vdiff can be resumed from the last chunk it was diffing and later rediffed chunk by chunk. 0 means the tables are not diffed in chunks.")
+	create.Flags().Int64Var(&createOptions.ChunkConcurrency, "chunk-concurrency", 1, "The maximum number of chunks of a table to diff concurrently on each target shard, when the table is diffed in chunks. Each chunk that is being diffed holds its own database snapshots.")
+	create.Flags().StringVar(&createOptions.RediffUUID, "rediff-uuid", "", "Only diff the tables, and the table chunks, that had differences in this previous vdiff of the workflow. Tables that had no differences in it are skipped.")
 	base.AddCommand(create)
 
 	base.AddCommand(delete)
 
+	repair.Flags().StringSliceVar(&repairOptions.Tables, "tables", nil, "Only repair these tables; default is all tables that had differences.")
+	repair.Flags().BoolVar(&repairOptions.Apply, "apply", false, "Apply the statements on the target primaries, waiting for the throttler before each batch, rather than only generating them.")
+	repair.Flags().Int64Var(&repairOptions.BatchSize, "batch-size", 100, "The number of rows to fetch and repair at a time. When applying the statements, each batch is executed in a single transaction.")
+	repair.Flags().StringVar(&repairOptions.OutputFile, "output", "", "Write the statements to this SQL file rather than to stdout.")
+	base.AddCommand(repair)
+
 	resume.Flags().StringSliceVar(&resumeOptions.TargetShards, "target-shards", nil, "The target shards to resume the vdiff on; default is all shards.")
 	base.AddCommand(resume)
 
@@ -41,6 +41,7 @@ var (
 		TabletTypesInPreferenceOrder bool
 		OnDDL                        string
 		ConfigOverrides              []string
+		RenameColumns                []string
 	}{}
 
 	// update makes a WorkflowUpdate gRPC call to a vtctld.
@@ -78,6 +79,9 @@ var (
 			if len(updateOptions.ConfigOverrides) > 0 {
 				changes = true
 			}
+			if len(updateOptions.RenameColumns) > 0 {
+				changes = true
+			}
 			if !changes {
 				return fmt.Errorf("no configuration options specified to update")
 			}
@@ -104,6 +108,11 @@ func commandUpdate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	renameColumns, err := parseRenameColumns(updateOptions.RenameColumns)
+	if err != nil {
+		return err
+	}
+
 	req := &vtctldatapb.WorkflowUpdateRequest{
 		Keyspace: baseOptions.Keyspace,
 		TabletRequest: &tabletmanagerdatapb.UpdateVReplicationWorkflowRequest{
@@ -112,6 +121,7 @@ func commandUpdate(cmd *cobra.Command, args []string) error {
 			TabletTypes:               updateOptions.TabletTypes,
 			TabletSelectionPreference: &tsp,
 			ConfigOverrides:           configOverrides,
+			RenameColumns:             renameColumns,
 		},
 	}
 
@@ -141,3 +151,23 @@ func commandUpdate(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// parseRenameColumns parses the table.source_column=target_column mappings of
+// the --rename-columns flag. An empty target column removes the mapping.
+func parseRenameColumns(mappings []string) (map[string]string, error) {
+	if len(mappings) == 0 {
+		return nil, nil
+	}
+	renameColumns := make(map[string]string, len(mappings))
+	for _, mapping := range mappings {
+		sourceColumn, targetColumn, ok := strings.Cut(mapping, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rename-columns format (table.source_column=target_column expected): %s", mapping)
+		}
+		if table, column, ok := strings.Cut(sourceColumn, "."); !ok || table == "" || column == "" {
+			return nil, fmt.Errorf("invalid rename-columns format (table.source_column=target_column expected): %s", mapping)
+		}
+		renameColumns[sourceColumn] = targetColumn
+	}
+	return renameColumns, nil
+}
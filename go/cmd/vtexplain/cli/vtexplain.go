@@ -18,10 +18,13 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"vitess.io/vitess/go/acl"
+	"vitess.io/vitess/go/json2"
 	"vitess.io/vitess/go/stats"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/servenv"
@@ -29,6 +32,7 @@ import (
 	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vtexplain"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/vschemaadvisor"
 
 	"github.com/spf13/cobra"
 
@@ -48,6 +52,12 @@ var (
 	dbName             string
 	plannerVersionStr  string
 
+	advise                    bool
+	adviseKeyspace            = "commerce"
+	queryLogFile              string
+	adviseLookupThreshold     = vschemaadvisor.DefaultLookupThreshold
+	adviseReferenceWriteRatio = vschemaadvisor.DefaultReferenceWriteRatio
+
 	numShards       = 2
 	replicationMode = "ROW"
 	executionMode   = "multi"
@@ -82,7 +92,9 @@ If no keyspace name is present, VTExplain will return the following error:
 		Example: "Explain how Vitess will execute the query `SELECT * FROM users` using the VSchema contained in `vschemas.json` and the database schema `schema.sql`:\n\n" +
 			"```\nvtexplain --vschema-file vschema.json --schema-file schema.sql --sql \"SELECT * FROM users\"\n```\n\n" +
 			"Explain how the example will execute on 128 shards using Row-based replication:\n\n" +
-			"```\nvtexplain -- -shards 128 --vschema-file vschema.json --schema-file schema.sql --replication-mode \"ROW\" --output-mode text --sql \"INSERT INTO users (user_id, name) VALUES(1, 'john')\"\n```\n",
+			"```\nvtexplain -- -shards 128 --vschema-file vschema.json --schema-file schema.sql --replication-mode \"ROW\" --output-mode text --sql \"INSERT INTO users (user_id, name) VALUES(1, 'john')\"\n```\n\n" +
+			"Suggest a VSchema for the keyspace `commerce` from the database schema `schema.sql` and the queries of a vtgate query log in JSON format, and report how each query would be routed:\n\n" +
+			"```\nvtexplain --advise --advise-keyspace commerce --schema-file schema.sql --querylog-file querylog.json\n```\n",
 		Args:    cobra.NoArgs,
 		PreRunE: servenv.CobraPreRunE,
 		Version: servenv.AppVersion.String(),
@@ -107,6 +119,11 @@ func init() {
 	Main.Flags().IntVar(&numShards, "shards", numShards, "Number of shards per keyspace. Passing --ks-shard-map/--ks-shard-map-file causes this flag to be ignored.")
 	Main.Flags().StringVar(&executionMode, "execution-mode", executionMode, "The execution mode to simulate -- must be set to multi, legacy-autocommit, or twopc")
 	Main.Flags().StringVar(&outputMode, "output-mode", outputMode, "Output in human-friendly text or json")
+	Main.Flags().BoolVar(&advise, "advise", advise, "Suggest a VSchema for the schema from the queries of --sql, --sql-file or --querylog-file, instead of explaining the queries with --vschema")
+	Main.Flags().StringVar(&adviseKeyspace, "advise-keyspace", adviseKeyspace, "The keyspace of the VSchema suggested by --advise")
+	Main.Flags().StringVar(&queryLogFile, "querylog-file", queryLogFile, "Identifies the vtgate query log, in JSON format, that contains the queries for --advise")
+	Main.Flags().Float64Var(&adviseLookupThreshold, "advise-lookup-threshold", adviseLookupThreshold, "The minimum fraction of the queries of a table that must filter on a column for --advise to suggest a lookup vindex on it")
+	Main.Flags().Float64Var(&adviseReferenceWriteRatio, "advise-reference-write-ratio", adviseReferenceWriteRatio, "The maximum fraction of the queries of a joined table that may write to it for --advise to suggest a reference table")
 
 	acl.RegisterFlags(Main.Flags())
 }
@@ -139,9 +156,105 @@ func run(cmd *cobra.Command, args []string) error {
 	defer logutil.Flush()
 
 	servenv.Init()
+	if advise {
+		return parseAndAdvise()
+	}
 	return parseAndRun(cmd.Context())
 }
 
+func parseAndAdvise() error {
+	if vschemaFlag != "" || vschemaFileFlag != "" {
+		return fmt.Errorf("--advise suggests the vschema, and does not accept --vschema or --vschema-file")
+	}
+	schema, err := getFileParam(schemaFlag, schemaFileFlag, "schema", true)
+	if err != nil {
+		return err
+	}
+	env, err := vtenv.New(vtenv.Options{
+		MySQLServerVersion: servenv.MySQLServerVersion(),
+		TruncateUILen:      servenv.TruncateUILen,
+		TruncateErrLen:     servenv.TruncateErrLen,
+	})
+	if err != nil {
+		return err
+	}
+
+	var queries []*vschemaadvisor.Query
+	if queryLogFile != "" {
+		if sqlFlag != "" || sqlFileFlag != "" {
+			return fmt.Errorf("--advise requires only one of sql, sql-file or querylog-file")
+		}
+		f, err := os.Open(queryLogFile)
+		if err != nil {
+			return fmt.Errorf("cannot read file %v: %v", queryLogFile, err)
+		}
+		defer f.Close()
+		if queries, err = vschemaadvisor.ParseQueryLog(f); err != nil {
+			return err
+		}
+	} else {
+		sql, err := getFileParam(sqlFlag, sqlFileFlag, "sql", true)
+		if err != nil {
+			return err
+		}
+		if queries, err = vschemaadvisor.ParseQueries(env.Parser(), sql); err != nil {
+			return err
+		}
+	}
+
+	advice, err := vschemaadvisor.Advise(env, schema, queries, vschemaadvisor.Options{
+		Keyspace:            adviseKeyspace,
+		LookupThreshold:     adviseLookupThreshold,
+		ReferenceWriteRatio: adviseReferenceWriteRatio,
+	})
+	if err != nil {
+		return err
+	}
+	vschema, err := json2.MarshalIndentPB(advice.VSchema, "  ")
+	if err != nil {
+		return err
+	}
+
+	if outputMode != "text" {
+		out, err := json.MarshalIndent(struct {
+			VSchema json.RawMessage               `json:"vschema"`
+			Tables  []*vschemaadvisor.TableAdvice `json:"tables"`
+			Routing *vschemaadvisor.Report        `json:"routing"`
+		}{vschema, advice.Tables, advice.Routing}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Tables:\n")
+	for _, ta := range advice.Tables {
+		switch ta.Type {
+		case vschemaadvisor.TableTypeReference:
+			fmt.Fprintf(&sb, "  %s: reference (%s)\n", ta.Table, ta.Reason)
+		default:
+			fmt.Fprintf(&sb, "  %s: %s on %s using %s (%s)\n", ta.Table, ta.Type, ta.Column, ta.Vindex, ta.Reason)
+			for _, lookup := range ta.Lookups {
+				fmt.Fprintf(&sb, "    lookup vindex %s\n", lookup)
+			}
+		}
+	}
+	fmt.Fprintf(&sb, "\nVSchema for keyspace %s:\n%s\n", adviseKeyspace, vschema)
+	r := advice.Routing
+	fmt.Fprintf(&sb, "\nRouting: %d single-shard, %d multi-shard, %d cross-shard, %d scatter, %d unsupported\n",
+		r.SingleShard, r.MultiShard, r.CrossShard, r.Scatter, r.Unsupported)
+	for _, qr := range r.Queries {
+		fmt.Fprintf(&sb, "  [%s] x%d %s\n", qr.Routing, qr.Count, qr.SQL)
+		if qr.Error != "" {
+			fmt.Fprintf(&sb, "    %s\n", qr.Error)
+		}
+	}
+	fmt.Print(sb.String())
+	return nil
+}
+
 func parseAndRun(ctx context.Context) error {
 	plannerVersion, _ := plancontext.PlannerNameToVersion(plannerVersionStr)
 	if plannerVersionStr != "" && plannerVersion != querypb.ExecuteOptions_Gen4 {
@@ -324,6 +324,25 @@ func NewMariaDBGTIDEvent(f BinlogFormat, s *FakeBinlogStream, gtid replication.M
 	return NewMariadbBinlogEvent(ev)
 }
 
+// NewMySQL56GTIDEvent returns a MySQL 5.6 specific GTID event.
+func NewMySQL56GTIDEvent(f BinlogFormat, s *FakeBinlogStream, gtid replication.Mysql56GTID) BinlogEvent {
+	length := 1 + // flags
+		16 + // SID
+		8 + // GNO
+		1 + // logical timestamp type code
+		8 + // last committed
+		8 // sequence number
+	data := make([]byte, length)
+
+	data[0] = 1 // commit flag
+	copy(data[1:1+16], gtid.Server[:])
+	binary.LittleEndian.PutUint64(data[1+16:1+16+8], uint64(gtid.Sequence))
+	data[1+16+8] = 2 // logical timestamps
+
+	ev := s.Packetize(f, eGTIDEvent, 0, data)
+	return NewMysql56BinlogEvent(ev)
+}
+
 // NewTableMapEvent returns a TableMap event.
 // Only works with post_header_length=8.
 func NewTableMapEvent(f BinlogFormat, s *FakeBinlogStream, tableID uint64, tm *TableMap) BinlogEvent {
@@ -190,6 +190,27 @@ func TestMariadDBGTIDEVent(t *testing.T) {
 	}
 }
 
+func TestMySQL56GTIDEvent(t *testing.T) {
+	f := NewMySQL56BinlogFormat()
+	s := NewFakeBinlogStream()
+
+	want := replication.Mysql56GTID{
+		Server:   replication.SID{0x43, 0x91, 0x92, 0xbd, 0xf3, 0x7c, 0x11, 0xe4, 0xbb, 0xeb, 0x2, 0x42, 0xac, 0x11, 0x3, 0x5a},
+		Sequence: 0x123456789abcdef0,
+	}
+	event := NewMySQL56GTIDEvent(f, s, want)
+	require.True(t, event.IsValid(), "NewMySQL56GTIDEvent().IsValid() is false")
+	require.True(t, event.IsGTID(), "NewMySQL56GTIDEvent().IsGTID() if false")
+
+	event, _, err := event.StripChecksum(f)
+	require.NoError(t, err, "StripChecksum failed: %v", err)
+
+	gtid, hasBegin, err := event.GTID(f)
+	require.NoError(t, err, "NewMySQL56GTIDEvent().GTID() returned error: %v", err)
+	require.False(t, hasBegin, "NewMySQL56GTIDEvent().GTID() returned hasBegin")
+	require.Equal(t, want, gtid)
+}
+
 func TestTableMapEvent(t *testing.T) {
 	f := NewMySQL56BinlogFormat()
 	s := NewFakeBinlogStream()
@@ -203,6 +203,20 @@ func (set Mysql56GTIDSet) Last() string {
 	return buf.String()
 }
 
+// ForEachGTID calls f for each GTID of the set, in the order of the sorted
+// SIDs and of the sequence numbers, until f returns false.
+func (set Mysql56GTIDSet) ForEachGTID(f func(gtid Mysql56GTID) bool) {
+	for _, sid := range set.SIDs() {
+		for _, interval := range set[sid] {
+			for sequence := interval.start; sequence <= interval.end; sequence++ {
+				if !f(Mysql56GTID{Server: sid, Sequence: sequence}) {
+					return
+				}
+			}
+		}
+	}
+}
+
 // Flavor implements GTIDSet.
 func (Mysql56GTIDSet) Flavor() string { return Mysql56FlavorID }
 
@@ -585,6 +585,40 @@ func TestMySQL56GTIDSetLast(t *testing.T) {
 	}
 }
 
+func TestMysql56GTIDSetForEachGTID(t *testing.T) {
+	sid1 := SID{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	sid2 := SID{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 255}
+	set := Mysql56GTIDSet{
+		sid2: []interval{{7, 7}},
+		sid1: []interval{{1, 2}, {5, 6}},
+	}
+
+	var got []Mysql56GTID
+	set.ForEachGTID(func(gtid Mysql56GTID) bool {
+		got = append(got, gtid)
+		return true
+	})
+	assert.Equal(t, []Mysql56GTID{
+		{Server: sid1, Sequence: 1},
+		{Server: sid1, Sequence: 2},
+		{Server: sid1, Sequence: 5},
+		{Server: sid1, Sequence: 6},
+		{Server: sid2, Sequence: 7},
+	}, got)
+
+	// The iteration stops when the function returns false.
+	got = nil
+	set.ForEachGTID(func(gtid Mysql56GTID) bool {
+		got = append(got, gtid)
+		return len(got) < 3
+	})
+	assert.Equal(t, []Mysql56GTID{
+		{Server: sid1, Sequence: 1},
+		{Server: sid1, Sequence: 2},
+		{Server: sid1, Sequence: 5},
+	}, got)
+}
+
 func TestSubtract(t *testing.T) {
 	tests := []struct {
 		name       string
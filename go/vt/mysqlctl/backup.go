@@ -422,6 +422,16 @@ func Restore(ctx context.Context, params RestoreParams) (*BackupManifest, error)
 		return nil, ErrNoBackup
 	}
 
+	if params.IsIncrementalRecovery() {
+		// Archived binlogs are incremental backups too, and can extend the
+		// restore path beyond the latest incremental backup.
+		abhs, err := bs.ListBackups(ctx, GetBinlogArchiveDir(params.Keyspace, params.Shard))
+		if err != nil {
+			return nil, vterrors.Wrap(err, "ListBackups failed for the binlog archive")
+		}
+		bhs = append(bhs, abhs...)
+	}
+
 	restorePath, err := FindBackupToRestore(ctx, params, bhs)
 	if err != nil {
 		return nil, err
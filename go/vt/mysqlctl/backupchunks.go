@@ -0,0 +1,809 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math/bits"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+
+	"vitess.io/vitess/go/textutil"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// Chunked builtin backups split the files of full backups into chunks at
+// content-defined boundaries, and store each chunk once in the chunk store of
+// the shard, which is the <keyspace>/<shard>.chunks directory of the backup
+// storage. Each chunk is stored as a backup of its own, named after the
+// SHA-256 of its content and the compression engine of the chunk, with a
+// single file. The chunks that a previous backup of the shard already stored
+// are not uploaded again, and the MANIFEST lists the chunks of each file.
+//
+// The chunks of encrypted backups are encrypted with a chunk key, which the
+// encrypted backups of the shard share, so that they can share chunks. Each
+// backup stores the chunk key in its MANIFEST, encrypted with its own data
+// key, and reuses the chunk key of the last backup that was encrypted with
+// the same key encryption key: a new chunk key is generated, and every chunk
+// is uploaded again, when the key encryption key changes. Encrypted chunks
+// are named after the HMAC-SHA256 of their content, keyed with the chunk
+// key, so that their names do not reveal their content.
+const (
+	backupChunkDirSuffix = ".chunks"
+
+	// backupChunkFileName is the name of the file of a chunk.
+	backupChunkFileName = "chunk"
+
+	// backupChunkStoreFileName is the name of the file that a chunked backup
+	// adds as soon as it starts, so that the backup is visible, and prevents
+	// the pruning of the chunk store, while it is in progress.
+	backupChunkStoreFileName = "CHUNKSTORE"
+
+	// backupChunkUncompressed is the compression engine in the name of the
+	// chunks that are not compressed.
+	backupChunkUncompressed = "none"
+)
+
+// chunkGear maps each byte to a random value for the gear rolling hash. It
+// must never change, as the chunk boundaries of a file depend on it.
+var chunkGear = func() (gear [256]uint64) {
+	// splitmix64, with a fixed seed.
+	x := uint64(0x5669746573734344)
+	for i := range gear {
+		x += 0x9e3779b97f4a7c15
+		z := x
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		gear[i] = z ^ (z >> 31)
+	}
+	return gear
+}()
+
+// FileChunk is a chunk of a file of a chunked backup.
+type FileChunk struct {
+	// Name is the name of the chunk in the chunk store: the hex encoded
+	// SHA-256 of the content of the chunk, and the compression engine of the
+	// chunk, separated by a dot.
+	Name string
+
+	// Size is the size of the chunk, before compression.
+	Size int64
+
+	// KeyID is the id of the chunk key that the chunk is encrypted with,
+	// for encrypted backups.
+	KeyID string `json:",omitempty"`
+}
+
+// backupChunkKey is the key that the chunks of an encrypted chunked backup
+// are encrypted with.
+type backupChunkKey struct {
+	id string
+	// enc encrypts the chunks, with the chunk key as data key.
+	enc *backupEncryption
+	// nameKey keys the HMAC that names the chunks.
+	nameKey []byte
+	// sealed is the chunk key, encrypted with the data key of the backup.
+	sealed []byte
+}
+
+func newBackupChunkKey(id string, key []byte) (*backupChunkKey, error) {
+	if len(key) != encryptionDataKeySize {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid chunk key size %d, expected %d", len(key), encryptionDataKeySize)
+	}
+	nameKey, err := hkdf.Key(sha256.New, key, nil, "vitess builtin backup chunk name", encryptionDataKeySize)
+	if err != nil {
+		return nil, err
+	}
+	return &backupChunkKey{
+		id:      id,
+		enc:     &backupEncryption{dataKey: key},
+		nameKey: nameKey,
+	}, nil
+}
+
+// selectBackupChunkKey returns the chunk key of a chunked backup that is
+// encrypted with enc: the chunk key of the last of the given backups that was
+// encrypted with the same key encryption key, or a new one.
+func selectBackupChunkKey(ctx context.Context, logger logutil.Logger, bms []builtinBackupManifest, enc *backupEncryption) (*backupChunkKey, error) {
+	var key *backupChunkKey
+	for i := len(bms) - 1; i >= 0 && key == nil; i-- {
+		bm := &bms[i]
+		if bm.ChunkKeyID == "" || bm.EncryptionKeyProvider != enc.keyProvider || bm.EncryptionKeyID != enc.keyID {
+			continue
+		}
+		var err error
+		if key, err = openBackupChunkKey(ctx, bm); err != nil {
+			logger.Warningf("Cannot reuse chunk key %v of backup %v: %v", bm.ChunkKeyID, bm.BackupName, err)
+		}
+	}
+	if key == nil {
+		id := make([]byte, 8)
+		if _, err := rand.Read(id); err != nil {
+			return nil, vterrors.Wrap(err, "cannot generate the id of the chunk key")
+		}
+		dataKey := make([]byte, encryptionDataKeySize)
+		if _, err := rand.Read(dataKey); err != nil {
+			return nil, vterrors.Wrap(err, "cannot generate the chunk key")
+		}
+		var err error
+		if key, err = newBackupChunkKey(hex.EncodeToString(id), dataKey); err != nil {
+			return nil, err
+		}
+		logger.Infof("Generated new chunk key %v", key.id)
+	}
+
+	sealed, err := enc.sealKey(key.enc.dataKey)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot encrypt chunk key %v", key.id)
+	}
+	key.sealed = sealed
+	return key, nil
+}
+
+// openBackupChunkKey decrypts the chunk key of an encrypted chunked backup.
+// It returns a nil backupChunkKey when the chunks of the backup are not
+// encrypted.
+func openBackupChunkKey(ctx context.Context, bm *builtinBackupManifest) (*backupChunkKey, error) {
+	if bm.ChunkKeyID == "" {
+		return nil, nil
+	}
+	enc, err := openBackupEncryption(ctx, bm)
+	if err != nil {
+		return nil, err
+	}
+	if enc == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "chunk key %v of backup %v is set, but the backup is not encrypted", bm.ChunkKeyID, bm.BackupName)
+	}
+	key, err := enc.openKey(bm.EncryptedChunkKey)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot decrypt chunk key %v", bm.ChunkKeyID)
+	}
+	return newBackupChunkKey(bm.ChunkKeyID, key)
+}
+
+// GetBackupChunkDir returns the directory of the backup storage where the
+// chunks of the chunked backups of a shard are stored.
+func GetBackupChunkDir(keyspace, shard string) string {
+	return GetBackupDir(keyspace, shard) + backupChunkDirSuffix
+}
+
+// chunker splits a stream at content-defined boundaries, with the FastCDC
+// algorithm: a boundary is where the gear hash of the last 64 bytes matches
+// a mask, which has more bits before the average chunk size than after it,
+// so that the sizes of the chunks are close to the average.
+type chunker struct {
+	r   io.Reader
+	buf []byte
+	n   int
+	eof bool
+
+	minSize, avgSize, maxSize int
+	maskS, maskL              uint64
+}
+
+// newChunker returns a chunker that splits r into chunks of avgSize bytes on
+// average, and from a quarter to four times that size.
+func newChunker(r io.Reader, avgSize int) *chunker {
+	b := bits.Len(uint(avgSize)) - 1
+	return &chunker{
+		r:       r,
+		buf:     make([]byte, 4*avgSize),
+		minSize: avgSize / 4,
+		avgSize: avgSize,
+		maxSize: 4 * avgSize,
+		maskS:   ^uint64(0) << (64 - (b + 1)),
+		maskL:   ^uint64(0) << (64 - (b - 1)),
+	}
+}
+
+// next returns the next chunk, or io.EOF after the last one. The returned
+// slice is not modified by later calls.
+func (c *chunker) next() ([]byte, error) {
+	if !c.eof && c.n < c.maxSize {
+		m, err := io.ReadFull(c.r, c.buf[c.n:])
+		c.n += m
+		switch {
+		case err == io.EOF || err == io.ErrUnexpectedEOF:
+			c.eof = true
+		case err != nil:
+			return nil, err
+		}
+	}
+	if c.n == 0 {
+		return nil, io.EOF
+	}
+	cut := c.cutPoint(c.buf[:c.n])
+	chunk := bytes.Clone(c.buf[:cut])
+	c.n = copy(c.buf, c.buf[cut:c.n])
+	return chunk, nil
+}
+
+// cutPoint returns the size of the chunk at the start of data.
+func (c *chunker) cutPoint(data []byte) int {
+	if len(data) <= c.minSize {
+		return len(data)
+	}
+	n := min(len(data), c.maxSize)
+	normal := min(n, c.avgSize)
+	var fp uint64
+	i := c.minSize
+	for ; i < normal; i++ {
+		fp = (fp << 1) + chunkGear[data[i]]
+		if fp&c.maskS == 0 {
+			return i + 1
+		}
+	}
+	for ; i < n; i++ {
+		fp = (fp << 1) + chunkGear[data[i]]
+		if fp&c.maskL == 0 {
+			return i + 1
+		}
+	}
+	return n
+}
+
+// backupChunkName returns the name of an unencrypted chunk with the given
+// content, stored with the given compression engine.
+func backupChunkName(data []byte, engine string) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]) + "." + engine
+}
+
+// getBackupChunkStorage returns the BackupStorage, with its stats scoped to
+// the selected implementation.
+func getBackupChunkStorage(logger logutil.Logger, stats backupstats.Stats) (backupstorage.BackupStorage, error) {
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, vterrors.Wrap(err, "unable to get backup storage")
+	}
+	bsStats := stats.Scope(
+		backupstats.Component(backupstats.BackupStorage),
+		backupstats.Implementation(
+			textutil.Title(backupstorage.BackupStorageImplementation),
+		),
+	)
+	return bs.WithParams(backupstorage.Params{
+		Logger: logger,
+		Stats:  bsStats,
+	}), nil
+}
+
+// backupChunkUpload is the upload of a chunk to the chunk store, by one of
+// the files of a backup.
+type backupChunkUpload struct {
+	done chan struct{}
+	err  error
+
+	// referenced is set for the chunks that a complete backup references,
+	// and that are not uploaded again.
+	referenced bool
+	used       bool
+}
+
+// backupChunkStore is the chunk store of a shard, as used by a chunked
+// backup or restore.
+type backupChunkStore struct {
+	bs     backupstorage.BackupStorage
+	dir    string
+	logger logutil.Logger
+
+	// engine is the compression engine of the chunks that are uploaded.
+	engine string
+
+	// key is the chunk key of encrypted backups, and nil otherwise.
+	key *backupChunkKey
+
+	mu sync.Mutex
+	// uploads has the chunks that were uploaded, or are being uploaded, by
+	// the backup, and the chunks that complete backups reference.
+	uploads map[string]*backupChunkUpload
+	// unreferenced has the chunks of the store that no complete backup
+	// references. They may be incomplete, and are uploaded again.
+	unreferenced map[string]bool
+
+	// handles has the chunks of the store, for restores.
+	handles map[string]backupstorage.BackupHandle
+}
+
+// newBackupChunkStore returns the chunk store of the shard whose backups are
+// in backupDir, to upload the chunks of a backup that is encrypted with enc,
+// if it is not nil.
+func newBackupChunkStore(ctx context.Context, logger logutil.Logger, bs backupstorage.BackupStorage, backupDir string, engine string, enc *backupEncryption) (*backupChunkStore, error) {
+	s := &backupChunkStore{
+		bs:           bs,
+		dir:          backupDir + backupChunkDirSuffix,
+		logger:       logger,
+		engine:       engine,
+		uploads:      make(map[string]*backupChunkUpload),
+		unreferenced: make(map[string]bool),
+	}
+	bhs, err := bs.ListBackups(ctx, backupDir)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+	var bms []builtinBackupManifest
+	for _, bh := range bhs {
+		var bm builtinBackupManifest
+		if err := getBackupManifestInto(ctx, bh, &bm); err != nil || bm.ChunkStore != s.dir {
+			continue
+		}
+		bms = append(bms, bm)
+	}
+	if enc != nil {
+		if s.key, err = selectBackupChunkKey(ctx, logger, bms, enc); err != nil {
+			return nil, err
+		}
+	}
+	// Only the chunks with the same key can be shared.
+	referenced := make(chan struct{})
+	close(referenced)
+	for _, bm := range bms {
+		for _, fe := range bm.FileEntries {
+			for _, c := range fe.Chunks {
+				if c.KeyID == s.keyID() {
+					s.uploads[c.Name] = &backupChunkUpload{done: referenced, referenced: true}
+				}
+			}
+		}
+	}
+	chs, err := bs.ListBackups(ctx, s.dir)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+	for _, ch := range chs {
+		if _, ok := s.uploads[ch.Name()]; !ok {
+			s.unreferenced[ch.Name()] = true
+		}
+	}
+	logger.Infof("Found %d chunks in chunk store %v, %d of which are referenced by complete backups", len(chs), s.dir, len(chs)-len(s.unreferenced))
+	return s, nil
+}
+
+// openBackupChunkStore returns the chunk store in dir, to restore the chunks
+// of a backup whose chunks are encrypted with key, if it is not nil.
+func openBackupChunkStore(ctx context.Context, logger logutil.Logger, bs backupstorage.BackupStorage, dir string, key *backupChunkKey) (*backupChunkStore, error) {
+	chs, err := bs.ListBackups(ctx, dir)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+	s := &backupChunkStore{
+		bs:      bs,
+		dir:     dir,
+		logger:  logger,
+		key:     key,
+		handles: make(map[string]backupstorage.BackupHandle, len(chs)),
+	}
+	for _, ch := range chs {
+		s.handles[ch.Name()] = ch
+	}
+	return s, nil
+}
+
+// keyID returns the id of the chunk key of the store, which is empty when
+// the chunks are not encrypted.
+func (s *backupChunkStore) keyID() string {
+	if s.key == nil {
+		return ""
+	}
+	return s.key.id
+}
+
+// chunkName returns the name of a chunk with the given content.
+func (s *backupChunkStore) chunkName(data []byte) string {
+	if s.key == nil {
+		return backupChunkName(data, s.engine)
+	}
+	mac := hmac.New(sha256.New, s.key.nameKey)
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil)) + "." + s.engine
+}
+
+// put stores a chunk, unless the store already has it, and returns whether
+// the chunk was uploaded.
+func (s *backupChunkStore) put(ctx context.Context, name string, data []byte) (bool, error) {
+	s.mu.Lock()
+	if u, ok := s.uploads[name]; ok {
+		u.used = true
+		s.mu.Unlock()
+		select {
+		case <-u.done:
+			return false, u.err
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+	u := &backupChunkUpload{done: make(chan struct{})}
+	s.uploads[name] = u
+	overwrite := s.unreferenced[name]
+	s.mu.Unlock()
+
+	u.err = s.upload(ctx, name, data, overwrite)
+	if u.err != nil {
+		// Let a retry of the file upload the chunk again.
+		s.mu.Lock()
+		delete(s.uploads, name)
+		s.mu.Unlock()
+	}
+	close(u.done)
+	return u.err == nil, u.err
+}
+
+func (s *backupChunkStore) upload(ctx context.Context, name string, data []byte, overwrite bool) (err error) {
+	var buf bytes.Buffer
+	var writer io.Writer = &buf
+	// The encryptor comes after the compressor, as encrypted data does not
+	// compress.
+	var encryptor *encryptingWriter
+	if s.key != nil {
+		if encryptor, err = s.key.enc.newEncryptingWriter(&buf); err != nil {
+			return vterrors.Wrap(err, "can't create encryptor")
+		}
+		writer = encryptor
+	}
+	if s.engine == backupChunkUncompressed {
+		if _, err := writer.Write(data); err != nil {
+			return vterrors.Wrapf(err, "cannot encrypt chunk %v", name)
+		}
+	} else {
+		compressor, err := newBuiltinCompressor(s.engine, writer, s.logger)
+		if err != nil {
+			return vterrors.Wrap(err, "can't create compressor")
+		}
+		if _, err := compressor.Write(data); err != nil {
+			compressor.Close()
+			return vterrors.Wrapf(err, "cannot compress chunk %v", name)
+		}
+		if err := compressor.Close(); err != nil {
+			return vterrors.Wrapf(err, "cannot compress chunk %v", name)
+		}
+	}
+	if encryptor != nil {
+		if err := encryptor.Close(); err != nil {
+			return vterrors.Wrapf(err, "cannot encrypt chunk %v", name)
+		}
+	}
+
+	var bh backupstorage.BackupHandle
+	if updater, ok := s.bs.(backupstorage.BackupUpdater); ok && overwrite {
+		bh, err = updater.UpdateBackup(ctx, s.dir, name)
+	} else {
+		bh, err = s.bs.StartBackup(ctx, s.dir, name)
+	}
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot store chunk %v", name)
+	}
+	defer func() {
+		if err != nil {
+			if aerr := bh.AbortBackup(ctx); aerr != nil {
+				s.logger.Warningf("Failed to remove partial chunk %v: %v", name, aerr)
+			}
+		}
+	}()
+	wc, err := bh.AddFile(ctx, backupChunkFileName, int64(buf.Len()))
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot add chunk %v", name)
+	}
+	if _, err := wc.Write(buf.Bytes()); err != nil {
+		wc.Close()
+		return vterrors.Wrapf(err, "cannot write chunk %v", name)
+	}
+	if err := wc.Close(); err != nil {
+		return vterrors.Wrapf(err, "cannot close chunk %v", name)
+	}
+	if err := bh.EndBackup(ctx); err != nil {
+		return vterrors.Wrapf(err, "cannot store chunk %v", name)
+	}
+	return bh.Error()
+}
+
+// checkReferencedChunks checks that the chunks that the backup did not
+// upload, because complete backups referenced them, are still in the store.
+func (s *backupChunkStore) checkReferencedChunks(ctx context.Context) error {
+	chs, err := s.bs.ListBackups(ctx, s.dir)
+	if err != nil {
+		return vterrors.Wrap(err, "ListBackups failed")
+	}
+	stored := make(map[string]bool, len(chs))
+	for _, ch := range chs {
+		stored[ch.Name()] = true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name, u := range s.uploads {
+		if u.referenced && u.used && !stored[name] {
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "chunk %v was removed from chunk store %v during the backup", name, s.dir)
+		}
+	}
+	return nil
+}
+
+// get writes the content of a chunk to w, and checks it against the name of
+// the chunk.
+func (s *backupChunkStore) get(ctx context.Context, c FileChunk, w io.Writer) (finalErr error) {
+	bh, ok := s.handles[c.Name]
+	if !ok {
+		return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "chunk %v is missing from chunk store %v", c.Name, s.dir)
+	}
+	if c.KeyID != s.keyID() {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "chunk %v is encrypted with chunk key %q, not %q", c.Name, c.KeyID, s.keyID())
+	}
+	rc, err := bh.ReadFile(ctx, backupChunkFileName)
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot read chunk %v", c.Name)
+	}
+	defer rc.Close()
+
+	sum, engine, _ := strings.Cut(c.Name, ".")
+	var reader io.Reader = rc
+	var decryptor *decryptingReader
+	if s.key != nil {
+		decryptor = s.key.enc.newDecryptingReader(reader)
+		reader = decryptor
+	}
+	if engine != backupChunkUncompressed {
+		if engine == PargzipCompressor {
+			engine = PgzipCompressor
+		}
+		decompressor, err := newBuiltinDecompressor(engine, reader, s.logger)
+		if err != nil {
+			return vterrors.Wrap(err, "can't create decompressor")
+		}
+		defer func() {
+			if err := decompressor.Close(); err != nil {
+				finalErr = errors.Join(finalErr, vterrors.Wrapf(err, "failed to close decompressor of chunk %v", c.Name))
+			}
+		}()
+		reader = decompressor
+	}
+	h := sha256.New()
+	if s.key != nil {
+		h = hmac.New(sha256.New, s.key.nameKey)
+	}
+	n, err := io.Copy(io.MultiWriter(w, h), reader)
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot copy chunk %v", c.Name)
+	}
+	// Authenticate the end of the chunk, which the decompressor may not read.
+	if decryptor != nil {
+		if err := decryptor.verify(); err != nil {
+			return vterrors.Wrapf(err, "cannot decrypt chunk %v", c.Name)
+		}
+	}
+	if n != c.Size || hex.EncodeToString(h.Sum(nil)) != sum {
+		return vterrors.Errorf(vtrpcpb.Code_DATA_LOSS, "chunk %v is corrupted", c.Name)
+	}
+	return nil
+}
+
+// startChunkedBackup returns the chunk store of the shard, for a chunked
+// backup that is encrypted with enc, if it is not nil, after marking the
+// backup as a chunked backup in progress.
+func (be *BuiltinBackupEngine) startChunkedBackup(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, enc *backupEncryption) (*backupChunkStore, error) {
+	if builtinBackupChunkSize < 64*1024 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the average size of chunks must be at least 64KiB, not %d", builtinBackupChunkSize)
+	}
+	engine, err := backupChunkEngine()
+	if err != nil {
+		return nil, vterrors.Wrap(err, "cannot start chunked backup")
+	}
+	backupDir := GetBackupDir(params.Keyspace, params.Shard)
+	wc, err := bh.AddFile(ctx, backupChunkStoreFileName, int64(len(backupDir+backupChunkDirSuffix)))
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot add %v to backup", backupChunkStoreFileName)
+	}
+	_, err = io.WriteString(wc, backupDir+backupChunkDirSuffix)
+	if cerr := wc.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot write %v", backupChunkStoreFileName)
+	}
+
+	bs, err := getBackupChunkStorage(params.Logger, params.Stats)
+	if err != nil {
+		return nil, err
+	}
+	store, err := newBackupChunkStore(ctx, params.Logger, bs, backupDir, engine, enc)
+	if err != nil {
+		bs.Close()
+		return nil, err
+	}
+	params.Logger.Infof("Backing up files in chunks of %d bytes on average to %v", builtinBackupChunkSize, store.dir)
+	if store.key != nil {
+		params.Logger.Infof("Encrypting chunks with chunk key %v", store.key.id)
+	}
+	return store, nil
+}
+
+// backupChunkedFile stores the content of a file in the chunk store, and
+// records its chunks in the FileEntry.
+func (be *BuiltinBackupEngine) backupChunkedFile(ctx context.Context, params BackupParams, store *backupChunkStore, fe *FileEntry) (finalErr error) {
+	source, err := fe.open(params.Cnf, true)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	params.Logger.Infof("Backing up file in chunks: %v %s", fe.Name, retryToString(fe.RetryCount))
+	var reader io.Reader = source
+	if builtinBackupFileReadBufferSize > 0 {
+		reader = bufio.NewReaderSize(source, int(builtinBackupFileReadBufferSize))
+	}
+	ch := newChunker(reader, builtinBackupChunkSize)
+	crc := crc32.NewIEEE()
+	var chunks []FileChunk
+	var uploaded, uploadedBytes int64
+	var mu sync.Mutex
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(params.Concurrency)
+	for {
+		data, err := ch.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			_ = g.Wait()
+			return vterrors.Wrapf(err, "cannot read %v", fe.Name)
+		}
+		crc.Write(data)
+		chunk := FileChunk{Name: store.chunkName(data), Size: int64(len(data)), KeyID: store.keyID()}
+		chunks = append(chunks, chunk)
+		g.Go(func() error {
+			up, err := store.put(gctx, chunk.Name, data)
+			if up {
+				mu.Lock()
+				uploaded++
+				uploadedBytes += chunk.Size
+				mu.Unlock()
+			}
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return vterrors.Wrapf(err, "cannot back up %v", fe.Name)
+	}
+	params.Logger.Infof("Backed up file %v in %d chunks, uploaded %d new chunks of %d bytes", fe.Name, len(chunks), uploaded, uploadedBytes)
+
+	fe.Chunks = chunks
+	fe.Hash = hex.EncodeToString(crc.Sum(nil))
+	return nil
+}
+
+// restoreChunkedFile restores a file from its chunks in the chunk store.
+func (be *BuiltinBackupEngine) restoreChunkedFile(ctx context.Context, params RestoreParams, store *backupChunkStore, fe *FileEntry) (finalErr error) {
+	dest, err := fe.open(params.Cnf, false)
+	if err != nil {
+		return vterrors.Wrap(err, "can't open destination file for writing")
+	}
+	defer func() {
+		if cerr := dest.Close(); cerr != nil {
+			finalErr = errors.Join(finalErr, vterrors.Wrap(cerr, "failed to close destination file"))
+		}
+	}()
+
+	bufferedDest := bufio.NewWriterSize(dest, int(builtinBackupFileWriteBufferSize))
+	crc := crc32.NewIEEE()
+	w := io.MultiWriter(bufferedDest, crc)
+	for _, c := range fe.Chunks {
+		if err := store.get(ctx, c, w); err != nil {
+			return err
+		}
+	}
+	if hash := hex.EncodeToString(crc.Sum(nil)); hash != fe.Hash {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "hash mismatch for %v, got %v expected %v", fe.Name, hash, fe.Hash)
+	}
+	if err := bufferedDest.Flush(); err != nil {
+		return vterrors.Wrap(err, "failed to flush destination buffer")
+	}
+	return nil
+}
+
+// PruneBackupChunks removes the chunks of the chunk store of the shard whose
+// backups are in backupDir that no backup references anymore, and returns
+// their names. Backups whose MANIFEST cannot be read are skipped, unless they
+// are chunked backups that use the chunk store, which may be in progress and
+// reference any chunk: nothing is removed while such a backup exists.
+func PruneBackupChunks(ctx context.Context, logger logutil.Logger, bs backupstorage.BackupStorage, backupDir string) (removed []string, err error) {
+	chunkDir := backupDir + backupChunkDirSuffix
+	chs, err := bs.ListBackups(ctx, chunkDir)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+	if len(chs) == 0 {
+		return nil, nil
+	}
+	bhs, err := bs.ListBackups(ctx, backupDir)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+	referenced := make(map[string]bool)
+	for _, bh := range bhs {
+		var bm builtinBackupManifest
+		if err := getBackupManifestInto(ctx, bh, &bm); err != nil {
+			// A chunked backup adds its CHUNKSTORE file before it uploads
+			// any chunk, or lists the chunks of the store.
+			if store, serr := readBackupChunkStore(ctx, bh); serr == nil && store == chunkDir {
+				logger.Infof("Not pruning chunk store %v, as chunked backup %v is incomplete or in progress: %v", chunkDir, bh.Name(), err)
+				return nil, nil
+			}
+			logger.Warningf("Skipping backup %v, which does not use chunk store %v, while pruning it: %v", bh.Name(), chunkDir, err)
+			continue
+		}
+		for _, fe := range bm.FileEntries {
+			for _, c := range fe.Chunks {
+				referenced[c.Name] = true
+			}
+		}
+	}
+	for _, ch := range chs {
+		if referenced[ch.Name()] {
+			continue
+		}
+		if err := bs.RemoveBackup(ctx, chunkDir, ch.Name()); err != nil {
+			return removed, vterrors.Wrapf(err, "cannot remove chunk %v from %v", ch.Name(), chunkDir)
+		}
+		removed = append(removed, ch.Name())
+	}
+	logger.Infof("Removed %d chunks from %v that no backup references", len(removed), chunkDir)
+	return removed, nil
+}
+
+// readBackupChunkStore returns the chunk store that the CHUNKSTORE file of a
+// chunked backup names.
+func readBackupChunkStore(ctx context.Context, bh backupstorage.BackupHandle) (string, error) {
+	rc, err := bh.ReadFile(ctx, backupChunkStoreFileName)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	store, err := io.ReadAll(rc)
+	if err != nil {
+		return "", err
+	}
+	return string(store), nil
+}
+
+// backupChunkEngine returns the compression engine of the chunks of a
+// chunked backup, which must be a builtin one.
+func backupChunkEngine() (string, error) {
+	if !backupStorageCompress {
+		return backupChunkUncompressed, nil
+	}
+	if ExternalCompressorCmd != "" || CompressionEngineName == ExternalCompressor {
+		return "", fmt.Errorf("chunked builtin backups need a builtin compression engine, not %q", ExternalCompressorCmd)
+	}
+	return CompressionEngineName, nil
+}
@@ -0,0 +1,313 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+func chunkTestData(size int) []byte {
+	r := rand.New(rand.NewPCG(1, 2))
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(r.Uint32())
+	}
+	return data
+}
+
+func splitChunks(t *testing.T, data []byte, avgSize int) [][]byte {
+	ch := newChunker(bytes.NewReader(data), avgSize)
+	var chunks [][]byte
+	for {
+		chunk, err := ch.next()
+		if err == io.EOF {
+			return chunks
+		}
+		require.NoError(t, err)
+		chunks = append(chunks, chunk)
+	}
+}
+
+func TestChunker(t *testing.T) {
+	const avgSize = 64 * 1024
+	data := chunkTestData(8 * 1024 * 1024)
+	chunks := splitChunks(t, data, avgSize)
+
+	assert.Equal(t, data, bytes.Join(chunks, nil))
+	for i, chunk := range chunks {
+		assert.LessOrEqual(t, len(chunk), 4*avgSize)
+		if i < len(chunks)-1 {
+			assert.GreaterOrEqual(t, len(chunk), avgSize/4)
+		}
+	}
+	assert.InDelta(t, len(data)/avgSize, len(chunks), float64(len(data)/avgSize)/2)
+
+	// Inserting data only changes the chunks around the insertion.
+	modified := append(bytes.Clone(data[:1024*1024]), append([]byte("inserted"), data[1024*1024:]...)...)
+	names := make(map[string]bool)
+	for _, chunk := range chunks {
+		names[backupChunkName(chunk, backupChunkUncompressed)] = true
+	}
+	modifiedChunks := splitChunks(t, modified, avgSize)
+	changed := 0
+	for _, chunk := range modifiedChunks {
+		if !names[backupChunkName(chunk, backupChunkUncompressed)] {
+			changed++
+		}
+	}
+	assert.LessOrEqual(t, changed, 3)
+
+	chunks = splitChunks(t, nil, avgSize)
+	assert.Empty(t, chunks)
+}
+
+func TestChunkedBackup(t *testing.T) {
+	ctx := context.Background()
+	oldRoot := filebackupstorage.FileBackupStorageRoot
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+	defer func() { filebackupstorage.FileBackupStorageRoot = oldRoot }()
+	oldChunkSize := builtinBackupChunkSize
+	builtinBackupChunkSize = 64 * 1024
+	defer func() { builtinBackupChunkSize = oldChunkSize }()
+
+	logger := logutil.NewMemoryLogger()
+	bs := (&filebackupstorage.FileBackupStorage{}).WithParams(backupstorage.Params{
+		Logger: logger,
+		Stats:  backupstats.NewFakeStats(),
+	})
+	sourceDir, destDir := t.TempDir(), t.TempDir()
+	backupDir := GetBackupDir("ks", "0")
+	be := &BuiltinBackupEngine{}
+	params := BackupParams{
+		Cnf:         &Mycnf{DataDir: sourceDir},
+		Logger:      logger,
+		Concurrency: 4,
+		Stats:       backupstats.NewFakeStats(),
+	}
+
+	// backup backs up the data as the only file of a backup with the given
+	// name, and returns the number of chunks it uploaded.
+	backup := func(name string, data []byte) (*FileEntry, int) {
+		require.NoError(t, os.WriteFile(path.Join(sourceDir, "t1.ibd"), data, 0600))
+		store, err := newBackupChunkStore(ctx, logger, bs, backupDir, ZstdCompressor, nil)
+		require.NoError(t, err)
+		fe := &FileEntry{Base: backupData, Name: "t1.ibd"}
+		require.NoError(t, be.backupChunkedFile(ctx, params, store, fe))
+		require.NoError(t, store.checkReferencedChunks(ctx))
+
+		bm := &builtinBackupManifest{
+			BackupManifest: BackupManifest{BackupName: name, BackupMethod: builtinBackupEngineName},
+			FileEntries:    []FileEntry{*fe},
+			ChunkStore:     store.dir,
+		}
+		manifest, err := json.Marshal(bm)
+		require.NoError(t, err)
+		dir := path.Join(filebackupstorage.FileBackupStorageRoot, backupDir, name)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(path.Join(dir, backupManifestFileName), manifest, 0644))
+
+		uploaded := 0
+		for _, u := range store.uploads {
+			if !u.referenced {
+				uploaded++
+			}
+		}
+		return fe, uploaded
+	}
+	restore := func(fe *FileEntry) []byte {
+		store, err := openBackupChunkStore(ctx, logger, bs, GetBackupChunkDir("ks", "0"), nil)
+		require.NoError(t, err)
+		require.NoError(t, be.restoreChunkedFile(ctx, RestoreParams{Cnf: &Mycnf{DataDir: destDir}, Logger: logger}, store, fe))
+		restored, err := os.ReadFile(path.Join(destDir, "t1.ibd"))
+		require.NoError(t, err)
+		return restored
+	}
+	storedChunks := func() int {
+		chs, err := bs.ListBackups(ctx, GetBackupChunkDir("ks", "0"))
+		require.NoError(t, err)
+		return len(chs)
+	}
+
+	data := chunkTestData(4 * 1024 * 1024)
+	fe1, uploaded := backup("2025-03-20.010000.zone1-0000000101", data)
+	assert.Equal(t, uploaded, len(fe1.Chunks))
+	assert.Equal(t, data, restore(fe1))
+
+	// The second backup only uploads the chunks that changed.
+	copy(data[2*1024*1024:], "modified")
+	fe2, uploaded := backup("2025-03-21.010000.zone1-0000000101", data)
+	assert.LessOrEqual(t, uploaded, 2)
+	assert.Greater(t, uploaded, 0)
+	assert.Equal(t, data, restore(fe2))
+	assert.Equal(t, len(fe1.Chunks)+uploaded, storedChunks())
+
+	// Nothing is pruned while a chunked backup is incomplete.
+	incompleteDir := path.Join(filebackupstorage.FileBackupStorageRoot, backupDir, "2025-03-22.010000.zone1-0000000101")
+	require.NoError(t, os.MkdirAll(incompleteDir, 0755))
+	require.NoError(t, os.WriteFile(path.Join(incompleteDir, backupChunkStoreFileName), []byte(GetBackupChunkDir("ks", "0")), 0644))
+	require.NoError(t, bs.RemoveBackup(ctx, backupDir, "2025-03-20.010000.zone1-0000000101"))
+	removed, err := PruneBackupChunks(ctx, logger, bs, backupDir)
+	require.NoError(t, err)
+	assert.Empty(t, removed)
+	assert.Equal(t, len(fe1.Chunks)+uploaded, storedChunks())
+
+	// The chunks that only the removed backup referenced are pruned, and an
+	// incomplete backup that is not chunked is skipped.
+	require.NoError(t, os.Remove(path.Join(incompleteDir, backupChunkStoreFileName)))
+	removed, err = PruneBackupChunks(ctx, logger, bs, backupDir)
+	require.NoError(t, err)
+	assert.Len(t, removed, uploaded)
+	assert.Equal(t, len(fe2.Chunks), storedChunks())
+	assert.Equal(t, data, restore(fe2))
+
+	// A corrupted chunk fails the restore.
+	chunkPath := path.Join(filebackupstorage.FileBackupStorageRoot, GetBackupChunkDir("ks", "0"), fe2.Chunks[0].Name, backupChunkFileName)
+	stored, err := os.ReadFile(chunkPath)
+	require.NoError(t, err)
+	stored[len(stored)/2] ^= 0xff
+	require.NoError(t, os.WriteFile(chunkPath, stored, 0644))
+	store, err := openBackupChunkStore(ctx, logger, bs, GetBackupChunkDir("ks", "0"), nil)
+	require.NoError(t, err)
+	assert.Error(t, be.restoreChunkedFile(ctx, RestoreParams{Cnf: &Mycnf{DataDir: destDir}, Logger: logger}, store, fe2))
+}
+
+func TestChunkedBackupEncrypted(t *testing.T) {
+	ctx := context.Background()
+	setKeyfile(t, writeKeyfile(t))
+	oldRoot := filebackupstorage.FileBackupStorageRoot
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+	defer func() { filebackupstorage.FileBackupStorageRoot = oldRoot }()
+	oldChunkSize := builtinBackupChunkSize
+	builtinBackupChunkSize = 64 * 1024
+	defer func() { builtinBackupChunkSize = oldChunkSize }()
+
+	logger := logutil.NewMemoryLogger()
+	bs := (&filebackupstorage.FileBackupStorage{}).WithParams(backupstorage.Params{
+		Logger: logger,
+		Stats:  backupstats.NewFakeStats(),
+	})
+	sourceDir, destDir := t.TempDir(), t.TempDir()
+	backupDir := GetBackupDir("ks", "0")
+	be := &BuiltinBackupEngine{}
+	params := BackupParams{
+		Cnf:         &Mycnf{DataDir: sourceDir},
+		Logger:      logger,
+		Concurrency: 4,
+		Stats:       backupstats.NewFakeStats(),
+	}
+
+	// backup backs up the data as the only file of an encrypted backup with
+	// the given name, and returns its MANIFEST and the number of chunks it
+	// uploaded.
+	backup := func(name string, data []byte) (*builtinBackupManifest, int) {
+		require.NoError(t, os.WriteFile(path.Join(sourceDir, "t1.ibd"), data, 0600))
+		enc, err := newBackupEncryption(ctx, keyfileKeyProviderName)
+		require.NoError(t, err)
+		store, err := newBackupChunkStore(ctx, logger, bs, backupDir, ZstdCompressor, enc)
+		require.NoError(t, err)
+		fe := &FileEntry{Base: backupData, Name: "t1.ibd"}
+		require.NoError(t, be.backupChunkedFile(ctx, params, store, fe))
+
+		bm := &builtinBackupManifest{
+			BackupManifest:        BackupManifest{BackupName: name, BackupMethod: builtinBackupEngineName},
+			FileEntries:           []FileEntry{*fe},
+			EncryptionKeyProvider: enc.keyProvider,
+			EncryptionKeyID:       enc.keyID,
+			EncryptedDataKey:      enc.wrappedKey,
+			ChunkStore:            store.dir,
+			ChunkKeyID:            store.key.id,
+			EncryptedChunkKey:     store.key.sealed,
+		}
+		manifest, err := json.Marshal(bm)
+		require.NoError(t, err)
+		dir := path.Join(filebackupstorage.FileBackupStorageRoot, backupDir, name)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(path.Join(dir, backupManifestFileName), manifest, 0644))
+
+		uploaded := 0
+		for _, u := range store.uploads {
+			if !u.referenced {
+				uploaded++
+			}
+		}
+		return bm, uploaded
+	}
+	restore := func(bm *builtinBackupManifest, key *backupChunkKey) error {
+		store, err := openBackupChunkStore(ctx, logger, bs, bm.ChunkStore, key)
+		require.NoError(t, err)
+		return be.restoreChunkedFile(ctx, RestoreParams{Cnf: &Mycnf{DataDir: destDir}, Logger: logger}, store, &bm.FileEntries[0])
+	}
+
+	data := bytes.Repeat([]byte("vitess chunks are encrypted\n"), 40000)
+	bm1, uploaded := backup("2025-03-20.010000.zone1-0000000101", data)
+	chunks := bm1.FileEntries[0].Chunks
+	assert.Equal(t, uploaded, len(chunks))
+	for _, c := range chunks {
+		assert.Equal(t, bm1.ChunkKeyID, c.KeyID)
+		stored, err := os.ReadFile(path.Join(filebackupstorage.FileBackupStorageRoot, bm1.ChunkStore, c.Name, backupChunkFileName))
+		require.NoError(t, err)
+		assert.False(t, bytes.Contains(stored, []byte("vitess chunks are encrypted")))
+	}
+	// The names of the chunks do not reveal their content.
+	assert.NotEqual(t, backupChunkName(data[:chunks[0].Size], ZstdCompressor), chunks[0].Name)
+
+	key, err := openBackupChunkKey(ctx, bm1)
+	require.NoError(t, err)
+	require.NoError(t, restore(bm1, key))
+	restored, err := os.ReadFile(path.Join(destDir, "t1.ibd"))
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(data, restored))
+	assert.ErrorContains(t, restore(bm1, nil), "is encrypted with chunk key")
+
+	// The next backup reuses the chunk key, and only uploads the chunks that
+	// changed.
+	copy(data[len(data)/2:], "modified")
+	bm2, uploaded := backup("2025-03-21.010000.zone1-0000000101", data)
+	assert.Equal(t, bm1.ChunkKeyID, bm2.ChunkKeyID)
+	assert.NotEqual(t, bm1.EncryptedDataKey, bm2.EncryptedDataKey)
+	assert.LessOrEqual(t, uploaded, 2)
+	assert.Greater(t, uploaded, 0)
+	key, err = openBackupChunkKey(ctx, bm2)
+	require.NoError(t, err)
+	require.NoError(t, restore(bm2, key))
+	restored, err = os.ReadFile(path.Join(destDir, "t1.ibd"))
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(data, restored))
+
+	// A new chunk key is generated once the key encryption key changes.
+	setKeyfile(t, writeKeyfile(t))
+	bm3, uploaded := backup("2025-03-22.010000.zone1-0000000101", data)
+	assert.NotEqual(t, bm2.ChunkKeyID, bm3.ChunkKeyID)
+	assert.Equal(t, len(bm3.FileEntries[0].Chunks), uploaded)
+	_, err = openBackupChunkKey(ctx, bm2)
+	assert.ErrorContains(t, err, "the backup was encrypted with key")
+}
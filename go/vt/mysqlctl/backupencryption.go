@@ -0,0 +1,395 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// The builtin backup engine encrypts the files of a backup with envelope
+// encryption: a random data key is generated for each backup, and is stored
+// in the MANIFEST once wrapped by a BackupKeyProvider. Each file is then
+// encrypted with its own key, derived from the data key and a random salt
+// that is stored in the header of the file, so that no two files, nor two
+// attempts at backing up the same file, are encrypted with the same key.
+//
+// A file is encrypted in segments of encryptionSegmentSize bytes with
+// AES-256-GCM. The nonce of a segment is its number and a flag that is only
+// set for the last segment, so that segments cannot be reordered, and a
+// file cannot be truncated, without restore failing.
+
+const (
+	// encryptionDataKeySize is the size of the data keys, for AES-256.
+	encryptionDataKeySize = 32
+	// encryptionSegmentSize is the size of the plaintext of a segment.
+	encryptionSegmentSize = 64 * 1024
+	encryptionSaltSize    = 32
+	encryptionVersion     = 1
+)
+
+// encryptionMagic starts the header of the encrypted files.
+var encryptionMagic = []byte("VTBE")
+
+var errEncryptedFileTruncated = errors.New("encrypted backup file is truncated")
+
+// BackupKeyProvider wraps the data keys of encrypted backups with a key
+// encryption key, such as a key of a KMS, and unwraps them on restore.
+type BackupKeyProvider interface {
+	// WrapKey encrypts a data key. It returns the id of the key encryption
+	// key, which is recorded in the MANIFEST, and the wrapped data key.
+	WrapKey(ctx context.Context, dataKey []byte) (keyID string, wrappedKey []byte, err error)
+
+	// UnwrapKey decrypts a data key that WrapKey wrapped with the key
+	// encryption key keyID.
+	UnwrapKey(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error)
+}
+
+// BackupKeyProviderMap contains the registered implementations of
+// BackupKeyProvider, by name.
+var BackupKeyProviderMap = make(map[string]BackupKeyProvider)
+
+// backupEncryption is the encryption of the files of a backup.
+type backupEncryption struct {
+	keyProvider string
+	keyID       string
+	wrappedKey  []byte
+	dataKey     []byte
+}
+
+// getBackupKeyProvider returns a registered BackupKeyProvider.
+func getBackupKeyProvider(name string) (BackupKeyProvider, error) {
+	kp, ok := BackupKeyProviderMap[name]
+	if !ok {
+		return nil, vterrors.Errorf(vtrpc.Code_NOT_FOUND, "unknown backup key provider %q", name)
+	}
+	return kp, nil
+}
+
+// newBackupEncryption generates the data key of a backup, and wraps it with
+// the key provider. It returns a nil backupEncryption when providerName is
+// empty, as the backup is then not encrypted.
+func newBackupEncryption(ctx context.Context, providerName string) (*backupEncryption, error) {
+	if providerName == "" {
+		return nil, nil
+	}
+	kp, err := getBackupKeyProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+	dataKey := make([]byte, encryptionDataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, vterrors.Wrap(err, "cannot generate the data key of the backup")
+	}
+	keyID, wrappedKey, err := kp.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot wrap the data key of the backup with key provider %q", providerName)
+	}
+	return &backupEncryption{
+		keyProvider: providerName,
+		keyID:       keyID,
+		wrappedKey:  wrappedKey,
+		dataKey:     dataKey,
+	}, nil
+}
+
+// openBackupEncryption unwraps the data key of an encrypted backup. It
+// returns a nil backupEncryption when the backup is not encrypted.
+func openBackupEncryption(ctx context.Context, bm *builtinBackupManifest) (*backupEncryption, error) {
+	if bm.EncryptionKeyProvider == "" {
+		return nil, nil
+	}
+	kp, err := getBackupKeyProvider(bm.EncryptionKeyProvider)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := kp.UnwrapKey(ctx, bm.EncryptionKeyID, bm.EncryptedDataKey)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot unwrap the data key of the backup with key provider %q and key %q", bm.EncryptionKeyProvider, bm.EncryptionKeyID)
+	}
+	if len(dataKey) != encryptionDataKeySize {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "invalid data key size %d, expected %d", len(dataKey), encryptionDataKeySize)
+	}
+	return &backupEncryption{
+		keyProvider: bm.EncryptionKeyProvider,
+		keyID:       bm.EncryptionKeyID,
+		wrappedKey:  bm.EncryptedDataKey,
+		dataKey:     dataKey,
+	}, nil
+}
+
+// fileAEAD returns the cipher of a file, which is keyed with the data key
+// of the backup and the salt of the file.
+func (enc *backupEncryption) fileAEAD(salt []byte) (cipher.AEAD, error) {
+	key, err := hkdf.Key(sha256.New, enc.dataKey, salt, "vitess builtin backup file", encryptionDataKeySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// keyAEAD returns the cipher that the keys stored in the MANIFEST, such as
+// the chunk key of chunked backups, are encrypted with.
+func (enc *backupEncryption) keyAEAD() (cipher.AEAD, error) {
+	key, err := hkdf.Key(sha256.New, enc.dataKey, nil, "vitess builtin backup key", encryptionDataKeySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealKey encrypts a key with the data key of the backup, so that it can be
+// stored in the MANIFEST. The random nonce comes first.
+func (enc *backupEncryption) sealKey(key []byte) ([]byte, error) {
+	aead, err := enc.keyAEAD()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, key, nil), nil
+}
+
+// openKey decrypts a key that sealKey encrypted.
+func (enc *backupEncryption) openKey(sealed []byte) ([]byte, error) {
+	aead, err := enc.keyAEAD()
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, vterrors.Errorf(vtrpc.Code_DATA_LOSS, "encrypted key is truncated")
+	}
+	key, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, vterrors.Errorf(vtrpc.Code_DATA_LOSS, "cannot decrypt key, it is corrupted or was modified: %v", err)
+	}
+	return key, nil
+}
+
+func encryptionHeaderSize() int {
+	return len(encryptionMagic) + 1 + encryptionSaltSize
+}
+
+// segmentNonce returns the nonce of a segment.
+func segmentNonce(nonce []byte, segment uint64, last bool) []byte {
+	clear(nonce)
+	binary.BigEndian.PutUint64(nonce[len(nonce)-9:], segment)
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+	return nonce
+}
+
+// encryptingWriter encrypts the data written to it into w. Close must be
+// called to write the last segment; it does not close w.
+type encryptingWriter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	header  []byte
+	nonce   []byte
+	buf     []byte
+	out     []byte
+	segment uint64
+	closed  bool
+}
+
+func (enc *backupEncryption) newEncryptingWriter(w io.Writer) (*encryptingWriter, error) {
+	header := make([]byte, 0, encryptionHeaderSize())
+	header = append(header, encryptionMagic...)
+	header = append(header, encryptionVersion)
+	salt := make([]byte, encryptionSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	header = append(header, salt...)
+	aead, err := enc.fileAEAD(salt)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &encryptingWriter{
+		w:      w,
+		aead:   aead,
+		header: header,
+		nonce:  make([]byte, aead.NonceSize()),
+		buf:    make([]byte, 0, encryptionSegmentSize),
+		out:    make([]byte, 0, encryptionSegmentSize+aead.Overhead()),
+	}, nil
+}
+
+// Write is part of the io.Writer interface.
+func (ew *encryptingWriter) Write(p []byte) (int, error) {
+	if ew.closed {
+		return 0, vterrors.Errorf(vtrpc.Code_INTERNAL, "write to a closed encrypting writer")
+	}
+	n := 0
+	for len(p) > 0 {
+		// A full segment is only written once more data comes, since the
+		// last segment is always written by Close.
+		if len(ew.buf) == encryptionSegmentSize {
+			if err := ew.writeSegment(false); err != nil {
+				return n, err
+			}
+		}
+		c := copy(ew.buf[len(ew.buf):encryptionSegmentSize], p)
+		ew.buf = ew.buf[:len(ew.buf)+c]
+		p = p[c:]
+		n += c
+	}
+	return n, nil
+}
+
+// Close writes the last segment, which is shorter than a full segment, and
+// can be empty.
+func (ew *encryptingWriter) Close() error {
+	if ew.closed {
+		return nil
+	}
+	ew.closed = true
+	if len(ew.buf) == encryptionSegmentSize {
+		if err := ew.writeSegment(false); err != nil {
+			return err
+		}
+	}
+	return ew.writeSegment(true)
+}
+
+func (ew *encryptingWriter) writeSegment(last bool) error {
+	ew.out = ew.aead.Seal(ew.out[:0], segmentNonce(ew.nonce, ew.segment, last), ew.buf, ew.header)
+	ew.segment++
+	ew.buf = ew.buf[:0]
+	_, err := ew.w.Write(ew.out)
+	return err
+}
+
+// decryptingReader decrypts the data that an encryptingWriter wrote to r,
+// and fails when it was modified or truncated.
+type decryptingReader struct {
+	r       io.Reader
+	enc     *backupEncryption
+	aead    cipher.AEAD
+	header  []byte
+	nonce   []byte
+	in      []byte
+	buf     []byte
+	pos     int
+	segment uint64
+	done    bool
+}
+
+func (enc *backupEncryption) newDecryptingReader(r io.Reader) *decryptingReader {
+	return &decryptingReader{r: r, enc: enc}
+}
+
+func (dr *decryptingReader) readHeader() error {
+	dr.header = make([]byte, encryptionHeaderSize())
+	if _, err := io.ReadFull(dr.r, dr.header); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return errEncryptedFileTruncated
+		}
+		return err
+	}
+	if !bytes.Equal(dr.header[:len(encryptionMagic)], encryptionMagic) {
+		return vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "not an encrypted backup file")
+	}
+	if version := dr.header[len(encryptionMagic)]; version != encryptionVersion {
+		return vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "unsupported backup encryption version %d", version)
+	}
+	aead, err := dr.enc.fileAEAD(dr.header[len(encryptionMagic)+1:])
+	if err != nil {
+		return err
+	}
+	dr.aead = aead
+	dr.nonce = make([]byte, aead.NonceSize())
+	dr.in = make([]byte, encryptionSegmentSize+aead.Overhead())
+	dr.buf = make([]byte, 0, encryptionSegmentSize)
+	return nil
+}
+
+// Read is part of the io.Reader interface.
+func (dr *decryptingReader) Read(p []byte) (int, error) {
+	if dr.aead == nil {
+		if err := dr.readHeader(); err != nil {
+			return 0, err
+		}
+	}
+	for dr.pos == len(dr.buf) {
+		if dr.done {
+			return 0, io.EOF
+		}
+		if err := dr.readSegment(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, dr.buf[dr.pos:])
+	dr.pos += n
+	return n, nil
+}
+
+func (dr *decryptingReader) readSegment() error {
+	n, err := io.ReadFull(dr.r, dr.in)
+	last := false
+	switch {
+	case err == io.ErrUnexpectedEOF || (err == io.EOF && n == 0):
+		// Only the last segment is shorter than a full segment.
+		if n < dr.aead.Overhead() {
+			return errEncryptedFileTruncated
+		}
+		last = true
+	case err != nil:
+		return err
+	}
+	dr.buf, err = dr.aead.Open(dr.buf[:0], segmentNonce(dr.nonce, dr.segment, last), dr.in[:n], dr.header)
+	if err != nil {
+		return vterrors.Errorf(vtrpc.Code_DATA_LOSS, "cannot decrypt segment %d of the encrypted backup file, it is corrupted or was modified: %v", dr.segment, err)
+	}
+	dr.segment++
+	dr.pos = 0
+	dr.done = last
+	return nil
+}
+
+// verify reads the rest of the file, so that a file that was truncated or
+// modified after the data that was read fails to restore.
+func (dr *decryptingReader) verify() error {
+	_, err := io.Copy(io.Discard, dr)
+	return err
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+func writeKeyfile(t *testing.T) string {
+	key := make([]byte, encryptionDataKeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	name := path.Join(t.TempDir(), "backup.key")
+	require.NoError(t, os.WriteFile(name, []byte(hex.EncodeToString(key)+"\n"), 0600))
+	return name
+}
+
+func setKeyfile(t *testing.T, name string) {
+	oldKeyfilePath := keyfilePath
+	keyfilePath = name
+	t.Cleanup(func() { keyfilePath = oldKeyfilePath })
+}
+
+func encrypt(t *testing.T, enc *backupEncryption, data []byte, writeSize int) []byte {
+	var out bytes.Buffer
+	ew, err := enc.newEncryptingWriter(&out)
+	require.NoError(t, err)
+	for len(data) > 0 {
+		n := min(writeSize, len(data))
+		written, err := ew.Write(data[:n])
+		require.NoError(t, err)
+		require.Equal(t, n, written)
+		data = data[n:]
+	}
+	require.NoError(t, ew.Close())
+	return out.Bytes()
+}
+
+func TestBackupEncryptionRoundTrip(t *testing.T) {
+	setKeyfile(t, writeKeyfile(t))
+	enc, err := newBackupEncryption(context.Background(), keyfileKeyProviderName)
+	require.NoError(t, err)
+
+	for _, size := range []int{0, 1, encryptionSegmentSize - 1, encryptionSegmentSize, encryptionSegmentSize + 1, 3*encryptionSegmentSize + 17} {
+		data := make([]byte, size)
+		_, err := rand.Read(data)
+		require.NoError(t, err)
+
+		encrypted := encrypt(t, enc, data, 1000)
+		assert.NotEqual(t, encrypted, encrypt(t, enc, data, 1000), "each file is encrypted with its own key")
+
+		decrypted, err := io.ReadAll(enc.newDecryptingReader(bytes.NewReader(encrypted)))
+		require.NoError(t, err, "size %d", size)
+		assert.True(t, bytes.Equal(data, decrypted), "size %d", size)
+
+		if size == 0 {
+			continue
+		}
+
+		// Any modification of the data fails to decrypt.
+		modified := bytes.Clone(encrypted)
+		modified[len(modified)-1] ^= 1
+		_, err = io.ReadAll(enc.newDecryptingReader(bytes.NewReader(modified)))
+		assert.ErrorContains(t, err, "corrupted or was modified", "size %d", size)
+
+		// Truncating the file, even at a segment boundary, fails to decrypt.
+		segment := encryptionSegmentSize + 16
+		for _, truncated := range [][]byte{
+			encrypted[:len(encrypted)-1],
+			encrypted[:encryptionHeaderSize()+min(segment, len(encrypted)-encryptionHeaderSize()-1)],
+			encrypted[:encryptionHeaderSize()],
+		} {
+			_, err = io.ReadAll(enc.newDecryptingReader(bytes.NewReader(truncated)))
+			assert.Error(t, err, "size %d truncated to %d", size, len(truncated))
+		}
+	}
+
+	// Another data key cannot decrypt the file.
+	other, err := newBackupEncryption(context.Background(), keyfileKeyProviderName)
+	require.NoError(t, err)
+	_, err = io.ReadAll(other.newDecryptingReader(bytes.NewReader(encrypt(t, enc, []byte("data"), 10))))
+	assert.ErrorContains(t, err, "corrupted or was modified")
+}
+
+func TestOpenBackupEncryption(t *testing.T) {
+	ctx := context.Background()
+	keyfile := writeKeyfile(t)
+	setKeyfile(t, keyfile)
+
+	enc, err := newBackupEncryption(ctx, "")
+	require.NoError(t, err)
+	assert.Nil(t, enc)
+	_, err = newBackupEncryption(ctx, "nope")
+	assert.EqualError(t, err, `unknown backup key provider "nope"`)
+
+	enc, err = newBackupEncryption(ctx, keyfileKeyProviderName)
+	require.NoError(t, err)
+	assert.Len(t, enc.keyID, 16)
+
+	// The key of the backup is recorded in the MANIFEST.
+	data, err := json.Marshal(&builtinBackupManifest{
+		EncryptionKeyProvider: enc.keyProvider,
+		EncryptionKeyID:       enc.keyID,
+		EncryptedDataKey:      enc.wrappedKey,
+	})
+	require.NoError(t, err)
+	var bm builtinBackupManifest
+	require.NoError(t, json.Unmarshal(data, &bm))
+
+	opened, err := openBackupEncryption(ctx, &bm)
+	require.NoError(t, err)
+	assert.Equal(t, enc.dataKey, opened.dataKey)
+
+	opened, err = openBackupEncryption(ctx, &builtinBackupManifest{})
+	require.NoError(t, err)
+	assert.Nil(t, opened)
+
+	// Restoring with another key fails.
+	setKeyfile(t, writeKeyfile(t))
+	_, err = openBackupEncryption(ctx, &bm)
+	assert.ErrorContains(t, err, "the backup was encrypted with key "+enc.keyID)
+
+	setKeyfile(t, "")
+	_, err = openBackupEncryption(ctx, &bm)
+	assert.ErrorContains(t, err, "--builtinbackup-encryption-keyfile is required by the keyfile key provider")
+
+	require.NoError(t, os.WriteFile(keyfile, []byte("short"), 0600))
+	setKeyfile(t, keyfile)
+	_, err = openBackupEncryption(ctx, &bm)
+	assert.ErrorContains(t, err, "must hold a 32 bytes key")
+}
+
+func TestBackupFileEncrypted(t *testing.T) {
+	ctx := context.Background()
+	setKeyfile(t, writeKeyfile(t))
+	oldRoot := filebackupstorage.FileBackupStorageRoot
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+	defer func() { filebackupstorage.FileBackupStorageRoot = oldRoot }()
+
+	sourceDir, destDir := t.TempDir(), t.TempDir()
+	data := bytes.Repeat([]byte("vitess backups are encrypted\n"), 10000)
+	require.NoError(t, os.WriteFile(path.Join(sourceDir, "t1.ibd"), data, 0600))
+
+	enc, err := newBackupEncryption(ctx, keyfileKeyProviderName)
+	require.NoError(t, err)
+
+	be := &BuiltinBackupEngine{}
+	fe := &FileEntry{Base: backupData, Name: "t1.ibd"}
+	err = be.backupFile(ctx, BackupParams{
+		Cnf:    &Mycnf{DataDir: sourceDir},
+		Logger: logutil.NewMemoryLogger(),
+		Stats:  backupstats.NewFakeStats(),
+	}, filebackupstorage.NewBackupHandle(nil, "", "", false), fe, "0", enc)
+	require.NoError(t, err)
+
+	stored, err := os.ReadFile(path.Join(filebackupstorage.FileBackupStorageRoot, "0"))
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(stored, []byte("vitess backups are encrypted")))
+
+	restore := func(enc *backupEncryption) error {
+		return be.restoreFile(ctx, RestoreParams{
+			Cnf:    &Mycnf{DataDir: destDir},
+			Logger: logutil.NewMemoryLogger(),
+			Stats:  backupstats.NewFakeStats(),
+		}, filebackupstorage.NewBackupHandle(nil, "", "", true), fe, builtinBackupManifest{CompressionEngine: CompressionEngineName, SkipCompress: !backupStorageCompress}, "0", enc)
+	}
+	require.NoError(t, restore(enc))
+	restored, err := os.ReadFile(path.Join(destDir, "t1.ibd"))
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(data, restored))
+
+	other, err := newBackupEncryption(ctx, keyfileKeyProviderName)
+	require.NoError(t, err)
+	assert.ErrorContains(t, restore(other), "corrupted or was modified")
+}
@@ -151,6 +151,8 @@ type RestoreParams struct {
 	MysqlShutdownTimeout time.Duration
 	// AllowedBackupEngines if present will filter out any backups taken with engines not included in the list
 	AllowedBackupEngines []string
+	// BackupName if present restores the full backup with this name, instead of the latest one
+	BackupName string
 }
 
 func (p *RestoreParams) Copy() RestoreParams {
@@ -170,6 +172,7 @@ func (p *RestoreParams) Copy() RestoreParams {
 		DryRun:               p.DryRun,
 		Stats:                p.Stats,
 		MysqlShutdownTimeout: p.MysqlShutdownTimeout,
+		BackupName:           p.BackupName,
 	}
 }
 
@@ -339,6 +342,14 @@ type BackupManifest struct {
 
 	// IncrementalDetails is nil for non-incremental backups
 	IncrementalDetails *IncrementalBackupDetails
+
+	// TableChecksums are the checksums and row counts of the tables at the
+	// time of the backup, if they were recorded.
+	TableChecksums []TableChecksum `json:",omitempty"`
+
+	// Verification is the result of the latest verification of the backup,
+	// or nil if the backup was never verified.
+	Verification *BackupVerification `json:",omitempty"`
 }
 
 func (m *BackupManifest) HashKey() string {
@@ -554,6 +565,9 @@ func FindBackupToRestore(ctx context.Context, params RestoreParams, bhs []backup
 					continue
 				}
 				bh := manifestHandleMap.Handle(bm)
+				if params.BackupName != "" && bh.Name() != params.BackupName {
+					continue
+				}
 
 				// check if the backup can be used with this MySQL version.
 				if bm.MySQLVersion != "" {
@@ -584,6 +598,9 @@ func FindBackupToRestore(ctx context.Context, params RestoreParams, bhs []backup
 			return -1
 		}()
 		if fullBackupIndex < 0 {
+			if params.BackupName != "" {
+				params.Logger.Errorf("No valid full backup found with name %v", params.BackupName)
+			}
 			if checkBackupTime {
 				params.Logger.Errorf("No valid backup found before time %v", params.StartTime.Format(BackupTimestampFormat))
 			}
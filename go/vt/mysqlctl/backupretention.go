@@ -0,0 +1,183 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// retainedBackup is a backup of a shard that a BackupRetentionPolicy is
+// applied to.
+type retainedBackup struct {
+	manifest *BackupManifest
+	time     time.Time
+	keep     bool
+}
+
+// SelectBackupsToKeep applies a BackupRetentionPolicy to the manifests of the
+// backups of a shard, and returns the manifests that the policy keeps. The
+// daily, weekly and monthly rules count the periods, in UTC, that have at
+// least one full backup. Incremental backups are only kept by the point in
+// time recovery rule, or when a kept incremental backup depends on them, and
+// the full backup that a kept incremental backup depends on is always kept.
+func SelectBackupsToKeep(policy *topodatapb.BackupRetentionPolicy, manifests []*BackupManifest, now time.Time) (map[*BackupManifest]bool, error) {
+	backups := make([]*retainedBackup, 0, len(manifests))
+	for _, m := range manifests {
+		backupTime, err := ParseRFC3339(m.BackupTime)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "cannot parse the time of backup %v", m.BackupName)
+		}
+		backups = append(backups, &retainedBackup{manifest: m, time: backupTime})
+	}
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].time.Before(backups[j].time)
+	})
+
+	// The full backups, most recent first.
+	var fulls []*retainedBackup
+	for i := len(backups) - 1; i >= 0; i-- {
+		if !backups[i].manifest.Incremental {
+			fulls = append(fulls, backups[i])
+		}
+	}
+	if len(fulls) > 0 {
+		fulls[0].keep = true
+	}
+	for i := 0; i < int(policy.GetKeepFull()) && i < len(fulls); i++ {
+		fulls[i].keep = true
+	}
+	keepPerPeriod := func(count uint32, period func(t time.Time) string) {
+		seen := make(map[string]bool)
+		for _, b := range fulls {
+			p := period(b.time.UTC())
+			if seen[p] {
+				continue
+			}
+			if len(seen) == int(count) {
+				return
+			}
+			seen[p] = true
+			b.keep = true
+		}
+	}
+	keepPerPeriod(policy.GetKeepDaily(), func(t time.Time) string {
+		return t.Format(time.DateOnly)
+	})
+	keepPerPeriod(policy.GetKeepWeekly(), func(t time.Time) string {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-%d", year, week)
+	})
+	keepPerPeriod(policy.GetKeepMonthly(), func(t time.Time) string {
+		return t.Format("2006-01")
+	})
+
+	if days := policy.GetPointInTimeRecoveryDays(); days > 0 {
+		// Restoring to the start of the window needs the last full backup
+		// before it, and the incremental backups that follow it.
+		windowStart := now.Add(-time.Duration(days) * 24 * time.Hour)
+		base := 0
+		for i, b := range backups {
+			if !b.manifest.Incremental && !b.time.After(windowStart) {
+				base = i
+			}
+		}
+		for _, b := range backups[base:] {
+			b.keep = true
+		}
+	}
+
+	// A kept incremental backup depends on the backups since the full backup
+	// that its chain of incremental backups can be restored on, which is the
+	// first one that contains the position the chain starts from.
+	for i := len(backups) - 1; i >= 0; i-- {
+		if !backups[i].keep || !backups[i].manifest.Incremental {
+			continue
+		}
+		from := backups[i].manifest.FromPosition
+		for j := i - 1; j >= 0; j-- {
+			b := backups[j]
+			b.keep = true
+			if !b.manifest.Incremental && from.GTIDSet != nil && b.manifest.Position.GTIDSet != nil && b.manifest.Position.GTIDSet.Contains(from.GTIDSet) {
+				break
+			}
+			if b.manifest.Incremental {
+				from = b.manifest.FromPosition
+			}
+		}
+	}
+
+	kept := make(map[*BackupManifest]bool)
+	for _, b := range backups {
+		if b.keep {
+			kept[b.manifest] = true
+		}
+	}
+	return kept, nil
+}
+
+// EnforceBackupRetentionPolicy removes the backups in backupDir that policy
+// does not keep, and returns the names of the kept and the removed backups.
+// Backups whose MANIFEST cannot be read, such as backups that are still in
+// progress, are always kept. When dryRun is set, the backups that would be
+// removed are returned, but are not removed.
+func EnforceBackupRetentionPolicy(ctx context.Context, logger logutil.Logger, bs backupstorage.BackupStorage, backupDir string, policy *topodatapb.BackupRetentionPolicy, now time.Time, dryRun bool) (kept []string, removed []string, err error) {
+	bhs, err := bs.ListBackups(ctx, backupDir)
+	if err != nil {
+		return nil, nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+	manifests := make([]*BackupManifest, 0, len(bhs))
+	handles := make(map[*BackupManifest]backupstorage.BackupHandle, len(bhs))
+	for _, bh := range bhs {
+		bm, err := GetBackupManifest(ctx, bh)
+		if err != nil {
+			logger.Warningf("Keeping possibly incomplete backup %v in directory %v: can't read MANIFEST: %v", bh.Name(), backupDir, err)
+			kept = append(kept, bh.Name())
+			continue
+		}
+		manifests = append(manifests, bm)
+		handles[bm] = bh
+	}
+	keep, err := SelectBackupsToKeep(policy, manifests, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, bm := range manifests {
+		name := handles[bm].Name()
+		if keep[bm] {
+			kept = append(kept, name)
+			continue
+		}
+		if !dryRun {
+			logger.Infof("Removing backup %v from %v, which the backup retention policy does not keep", name, backupDir)
+			if err := bs.RemoveBackup(ctx, backupDir, name); err != nil {
+				return nil, nil, vterrors.Wrapf(err, "cannot remove backup %v from %v", name, backupDir)
+			}
+		}
+		removed = append(removed, name)
+	}
+	sort.Strings(kept)
+	return kept, removed, nil
+}
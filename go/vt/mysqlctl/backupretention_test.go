@@ -0,0 +1,209 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+const retentionTestUUID = "16b1039f-22b6-11ed-b765-0a43f95f28a3"
+
+// retentionTestManifest returns the manifest of a backup taken at the given
+// time, with the transactions up to position. Incremental backups start
+// from the transaction after from.
+func retentionTestManifest(t *testing.T, name string, backupTime time.Time, from, position int) *BackupManifest {
+	gtids := func(n int) replication.Position {
+		if n == 0 {
+			return replication.Position{}
+		}
+		pos, err := replication.ParsePosition(replication.Mysql56FlavorID, fmt.Sprintf("%s:1-%d", retentionTestUUID, n))
+		require.NoError(t, err)
+		return pos
+	}
+	return &BackupManifest{
+		BackupName:   name,
+		BackupMethod: builtinBackupEngineName,
+		BackupTime:   FormatRFC3339(backupTime),
+		FromPosition: gtids(from),
+		Position:     gtids(position),
+		Incremental:  from > 0,
+	}
+}
+
+func keptBackupNames(kept map[*BackupManifest]bool) []string {
+	var names []string
+	for m := range kept {
+		names = append(names, m.BackupName)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestSelectBackupsToKeep(t *testing.T) {
+	now := time.Date(2025, 3, 20, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	manifests := []*BackupManifest{
+		retentionTestManifest(t, "full-jan-10", time.Date(2025, 1, 10, 1, 0, 0, 0, time.UTC), 0, 10),
+		retentionTestManifest(t, "full-jan-31", time.Date(2025, 1, 31, 1, 0, 0, 0, time.UTC), 0, 20),
+		retentionTestManifest(t, "full-feb-28", time.Date(2025, 2, 28, 1, 0, 0, 0, time.UTC), 0, 30),
+		retentionTestManifest(t, "full-mar-10", time.Date(2025, 3, 10, 1, 0, 0, 0, time.UTC), 0, 40),
+		retentionTestManifest(t, "incr-mar-11", time.Date(2025, 3, 11, 1, 0, 0, 0, time.UTC), 40, 45),
+		retentionTestManifest(t, "full-mar-15-a", time.Date(2025, 3, 15, 1, 0, 0, 0, time.UTC), 0, 50),
+		retentionTestManifest(t, "full-mar-15-b", time.Date(2025, 3, 15, 13, 0, 0, 0, time.UTC), 0, 60),
+		retentionTestManifest(t, "incr-mar-16", time.Date(2025, 3, 16, 1, 0, 0, 0, time.UTC), 60, 70),
+		retentionTestManifest(t, "incr-mar-18", time.Date(2025, 3, 18, 1, 0, 0, 0, time.UTC), 70, 80),
+		retentionTestManifest(t, "full-mar-19", time.Date(2025, 3, 19, 1, 0, 0, 0, time.UTC), 0, 85),
+		retentionTestManifest(t, "incr-mar-20", now.Add(-time.Hour), 85, 90),
+	}
+
+	tcs := []struct {
+		name   string
+		policy *topodatapb.BackupRetentionPolicy
+		kept   []string
+	}{
+		{
+			name:   "empty policy keeps the last full backup",
+			policy: &topodatapb.BackupRetentionPolicy{},
+			kept:   []string{"full-mar-19"},
+		},
+		{
+			name:   "keep full",
+			policy: &topodatapb.BackupRetentionPolicy{KeepFull: 3},
+			kept:   []string{"full-mar-15-a", "full-mar-15-b", "full-mar-19"},
+		},
+		{
+			name:   "keep daily",
+			policy: &topodatapb.BackupRetentionPolicy{KeepDaily: 3},
+			kept:   []string{"full-mar-10", "full-mar-15-b", "full-mar-19"},
+		},
+		{
+			name:   "keep weekly and monthly",
+			policy: &topodatapb.BackupRetentionPolicy{KeepWeekly: 2, KeepMonthly: 3},
+			kept:   []string{"full-feb-28", "full-jan-31", "full-mar-15-b", "full-mar-19"},
+		},
+		{
+			name:   "point in time recovery",
+			policy: &topodatapb.BackupRetentionPolicy{PointInTimeRecoveryDays: 3},
+			kept:   []string{"full-mar-15-b", "full-mar-19", "incr-mar-16", "incr-mar-18", "incr-mar-20"},
+		},
+		{
+			name:   "point in time recovery window before the first full backup",
+			policy: &topodatapb.BackupRetentionPolicy{PointInTimeRecoveryDays: 365},
+			kept: []string{
+				"full-feb-28", "full-jan-10", "full-jan-31", "full-mar-10", "full-mar-15-a", "full-mar-15-b", "full-mar-19",
+				"incr-mar-11", "incr-mar-16", "incr-mar-18", "incr-mar-20",
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			kept, err := SelectBackupsToKeep(tc.policy, manifests, now)
+			require.NoError(t, err)
+			assert.Equal(t, tc.kept, keptBackupNames(kept))
+		})
+	}
+
+	t.Run("kept incremental backups keep the full backup they depend on", func(t *testing.T) {
+		// The point in time recovery window starts after the last full backup.
+		kept, err := SelectBackupsToKeep(&topodatapb.BackupRetentionPolicy{PointInTimeRecoveryDays: 1}, manifests[:9], now.Add(3*day))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"full-mar-15-b", "incr-mar-16", "incr-mar-18"}, keptBackupNames(kept))
+	})
+
+	t.Run("full backup between incremental backups", func(t *testing.T) {
+		// full-mar-17 contains the position incr-mar-18 starts from, so
+		// incr-mar-18 can be restored on it without the backups before it.
+		manifests := []*BackupManifest{
+			retentionTestManifest(t, "full-mar-15", time.Date(2025, 3, 15, 1, 0, 0, 0, time.UTC), 0, 60),
+			retentionTestManifest(t, "incr-mar-16", time.Date(2025, 3, 16, 1, 0, 0, 0, time.UTC), 60, 70),
+			retentionTestManifest(t, "full-mar-17", time.Date(2025, 3, 17, 1, 0, 0, 0, time.UTC), 0, 75),
+			retentionTestManifest(t, "incr-mar-18", time.Date(2025, 3, 18, 1, 0, 0, 0, time.UTC), 70, 80),
+		}
+		kept, err := SelectBackupsToKeep(&topodatapb.BackupRetentionPolicy{PointInTimeRecoveryDays: 1}, manifests, time.Date(2025, 3, 18, 12, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"full-mar-17", "incr-mar-18"}, keptBackupNames(kept))
+	})
+
+	t.Run("invalid backup time", func(t *testing.T) {
+		_, err := SelectBackupsToKeep(&topodatapb.BackupRetentionPolicy{}, []*BackupManifest{{BackupName: "bad", BackupTime: "yesterday"}}, now)
+		assert.ErrorContains(t, err, "cannot parse the time of backup bad")
+	})
+}
+
+func TestEnforceBackupRetentionPolicy(t *testing.T) {
+	ctx := context.Background()
+	oldRoot := filebackupstorage.FileBackupStorageRoot
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+	defer func() { filebackupstorage.FileBackupStorageRoot = oldRoot }()
+
+	now := time.Date(2025, 3, 20, 12, 0, 0, 0, time.UTC)
+	backupDir := GetBackupDir("ks", "0")
+	writeBackup := func(m *BackupManifest) {
+		dir := path.Join(filebackupstorage.FileBackupStorageRoot, backupDir, m.BackupName)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		if m.BackupTime == "" {
+			// An incomplete backup, without a MANIFEST.
+			return
+		}
+		data, err := json.Marshal(m)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path.Join(dir, backupManifestFileName), data, 0644))
+	}
+	writeBackup(retentionTestManifest(t, "2025-03-17.010000.zone1-0000000101", now.Add(-3*24*time.Hour), 0, 10))
+	writeBackup(retentionTestManifest(t, "2025-03-18.010000.zone1-0000000101", now.Add(-2*24*time.Hour), 0, 20))
+	writeBackup(retentionTestManifest(t, "2025-03-19.010000.zone1-0000000101", now.Add(-24*time.Hour), 0, 30))
+	writeBackup(&BackupManifest{BackupName: "2025-03-20.010000.zone1-0000000101"})
+
+	bs := (&filebackupstorage.FileBackupStorage{}).WithParams(backupstorage.Params{
+		Logger: logutil.NewMemoryLogger(),
+		Stats:  backupstats.NewFakeStats(),
+	})
+	policy := &topodatapb.BackupRetentionPolicy{KeepFull: 2}
+	kept, removed, err := EnforceBackupRetentionPolicy(ctx, logutil.NewMemoryLogger(), bs, backupDir, policy, now, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2025-03-18.010000.zone1-0000000101", "2025-03-19.010000.zone1-0000000101", "2025-03-20.010000.zone1-0000000101"}, kept)
+	assert.Equal(t, []string{"2025-03-17.010000.zone1-0000000101"}, removed)
+
+	bhs, err := bs.ListBackups(ctx, backupDir)
+	require.NoError(t, err)
+	assert.Len(t, bhs, 4, "a dry run does not remove backups")
+
+	_, removed, err = EnforceBackupRetentionPolicy(ctx, logutil.NewMemoryLogger(), bs, backupDir, policy, now, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2025-03-17.010000.zone1-0000000101"}, removed)
+	bhs, err = bs.ListBackups(ctx, backupDir)
+	require.NoError(t, err)
+	assert.Len(t, bhs, 3)
+}
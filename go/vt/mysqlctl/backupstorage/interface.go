@@ -130,6 +130,17 @@ type BackupStorage interface {
 	WithParams(Params) BackupStorage
 }
 
+// BackupUpdater is implemented by the BackupStorage implementations that can
+// update complete backups, for instance to record the result of their
+// verification in their MANIFEST.
+type BackupUpdater interface {
+	// UpdateBackup returns a read-write handle to the existing backup with
+	// the given name. The files added with AddFile replace the files of the
+	// backup with the same name. AbortBackup must not be called on the
+	// returned handle.
+	UpdateBackup(ctx context.Context, dir, name string) (BackupHandle, error)
+}
+
 // BackupStorageMap contains the registered implementations for BackupStorage
 var BackupStorageMap = make(map[string]BackupStorage)
 
@@ -0,0 +1,196 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// TableChecksum is the checksum and the row count of a table, as recorded
+// in the MANIFEST of a backup.
+type TableChecksum struct {
+	// Table is the qualified name of the table, as in "db.table".
+	Table    string
+	Rows     int64
+	Checksum uint64
+}
+
+// BackupVerification is the result of the verification of a backup, as
+// recorded in its MANIFEST.
+type BackupVerification struct {
+	// VerifiedTime is when the backup was verified, in RFC 3339 format.
+	VerifiedTime string
+	Success      bool
+	Error        string `json:",omitempty"`
+}
+
+const listTablesToChecksumQuery = `SELECT table_schema, table_name FROM information_schema.tables
+	WHERE table_type = 'BASE TABLE' AND table_schema NOT IN ('mysql', 'sys', 'information_schema', 'performance_schema')
+	ORDER BY table_schema, table_name`
+
+// GetTableChecksums returns the checksum and the row count of every user
+// table of mysqld.
+func GetTableChecksums(ctx context.Context, mysqld MysqlDaemon) ([]TableChecksum, error) {
+	qr, err := mysqld.FetchSuperQuery(ctx, listTablesToChecksumQuery)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "cannot list the tables to checksum")
+	}
+	checksums := make([]TableChecksum, 0, len(qr.Rows))
+	for _, row := range qr.Rows {
+		table := sqlescape.EscapeID(row[0].ToString()) + "." + sqlescape.EscapeID(row[1].ToString())
+		checksum := TableChecksum{Table: row[0].ToString() + "." + row[1].ToString()}
+
+		cqr, err := mysqld.FetchSuperQuery(ctx, "CHECKSUM TABLE "+table)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "cannot checksum table %v", checksum.Table)
+		}
+		if len(cqr.Rows) != 1 || len(cqr.Rows[0]) != 2 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unexpected result for CHECKSUM TABLE %v: %v", checksum.Table, cqr.Rows)
+		}
+		if checksum.Checksum, err = cqr.Rows[0][1].ToCastUint64(); err != nil {
+			return nil, vterrors.Wrapf(err, "cannot parse the checksum of table %v", checksum.Table)
+		}
+
+		cqr, err = mysqld.FetchSuperQuery(ctx, "SELECT COUNT(*) FROM "+table)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "cannot count the rows of table %v", checksum.Table)
+		}
+		if len(cqr.Rows) != 1 || len(cqr.Rows[0]) != 1 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unexpected result for the row count of table %v: %v", checksum.Table, cqr.Rows)
+		}
+		if checksum.Rows, err = cqr.Rows[0][0].ToCastInt64(); err != nil {
+			return nil, vterrors.Wrapf(err, "cannot parse the row count of table %v", checksum.Table)
+		}
+		checksums = append(checksums, checksum)
+	}
+	return checksums, nil
+}
+
+// VerifyRestoredBackup checks the consistency of every user table of mysqld,
+// into which the backup with the given manifest was just restored, and
+// compares their checksums and row counts with the ones recorded in the
+// manifest, if any.
+func VerifyRestoredBackup(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, manifest *BackupManifest) error {
+	qr, err := mysqld.FetchSuperQuery(ctx, listTablesToChecksumQuery)
+	if err != nil {
+		return vterrors.Wrap(err, "cannot list the tables to check")
+	}
+	for _, row := range qr.Rows {
+		name := row[0].ToString() + "." + row[1].ToString()
+		cqr, err := mysqld.FetchSuperQuery(ctx, "CHECK TABLE "+sqlescape.EscapeID(row[0].ToString())+"."+sqlescape.EscapeID(row[1].ToString()))
+		if err != nil {
+			return vterrors.Wrapf(err, "cannot check table %v", name)
+		}
+		// The rows of the result are Table, Op, Msg_type and Msg_text, and
+		// the last one has the status of the check.
+		for i, crow := range cqr.Rows {
+			if len(crow) != 4 {
+				return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unexpected result for CHECK TABLE %v: %v", name, cqr.Rows)
+			}
+			msgType, msgText := crow[2].ToString(), crow[3].ToString()
+			if strings.EqualFold(msgType, "error") || (i == len(cqr.Rows)-1 && !strings.EqualFold(msgText, "OK")) {
+				return vterrors.Errorf(vtrpcpb.Code_DATA_LOSS, "table %v is corrupted: %v: %v", name, msgType, msgText)
+			}
+		}
+	}
+	logger.Infof("Checked the consistency of %d tables", len(qr.Rows))
+
+	if len(manifest.TableChecksums) == 0 {
+		logger.Infof("Backup %v has no recorded table checksums, skipping their comparison", manifest.BackupName)
+		return nil
+	}
+	checksums, err := GetTableChecksums(ctx, mysqld)
+	if err != nil {
+		return err
+	}
+	restored := make(map[string]TableChecksum, len(checksums))
+	for _, c := range checksums {
+		restored[c.Table] = c
+	}
+	var mismatches []string
+	for _, expected := range manifest.TableChecksums {
+		actual, ok := restored[expected.Table]
+		switch {
+		case !ok:
+			mismatches = append(mismatches, fmt.Sprintf("%v is missing", expected.Table))
+		case actual.Rows != expected.Rows:
+			mismatches = append(mismatches, fmt.Sprintf("%v has %d rows instead of %d", expected.Table, actual.Rows, expected.Rows))
+		case actual.Checksum != expected.Checksum:
+			mismatches = append(mismatches, fmt.Sprintf("%v has checksum %d instead of %d", expected.Table, actual.Checksum, expected.Checksum))
+		}
+		delete(restored, expected.Table)
+	}
+	for table := range restored {
+		mismatches = append(mismatches, fmt.Sprintf("%v was not in the backup", table))
+	}
+	if len(mismatches) > 0 {
+		return vterrors.Errorf(vtrpcpb.Code_DATA_LOSS, "restored tables do not match the backup: %v", strings.Join(mismatches, ", "))
+	}
+	logger.Infof("Checksums and row counts of %d tables match the backup", len(manifest.TableChecksums))
+	return nil
+}
+
+// WriteBackupVerification records the result of the verification of a
+// backup in its MANIFEST. The other fields of the MANIFEST, including the
+// ones specific to the backup engine, are left unchanged. The backup storage
+// must implement backupstorage.BackupUpdater.
+func WriteBackupVerification(ctx context.Context, bs backupstorage.BackupStorage, dir string, bh backupstorage.BackupHandle, verification *BackupVerification) error {
+	updater, ok := bs.(backupstorage.BackupUpdater)
+	if !ok {
+		return vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "backup storage %T cannot update backups", bs)
+	}
+	manifest := make(map[string]json.RawMessage)
+	if err := getBackupManifestInto(ctx, bh, &manifest); err != nil {
+		return err
+	}
+	data, err := json.Marshal(verification)
+	if err != nil {
+		return vterrors.Wrap(err, "cannot JSON encode the backup verification")
+	}
+	manifest["Verification"] = data
+	data, err = json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return vterrors.Wrap(err, "cannot JSON encode the MANIFEST")
+	}
+
+	uh, err := updater.UpdateBackup(ctx, dir, bh.Name())
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot update backup %v", bh.Name())
+	}
+	wc, err := uh.AddFile(ctx, backupManifestFileName, int64(len(data)))
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot add %v to backup", backupManifestFileName)
+	}
+	if _, err := wc.Write(data); err != nil {
+		wc.Close()
+		return vterrors.Wrapf(err, "cannot write %v", backupManifestFileName)
+	}
+	if err := wc.Close(); err != nil {
+		return vterrors.Wrapf(err, "cannot close %v", backupManifestFileName)
+	}
+	return uh.EndBackup(ctx)
+}
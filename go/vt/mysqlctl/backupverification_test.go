@@ -0,0 +1,108 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/fakesqldb"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+func TestVerifyRestoredBackup(t *testing.T) {
+	ctx := context.Background()
+	fakedb := fakesqldb.New(t)
+	defer fakedb.Close()
+	mysqld := NewFakeMysqlDaemon(fakedb)
+	defer mysqld.Close()
+
+	checkResult := func(msgType, msgText string) *sqltypes.Result {
+		return sqltypes.MakeTestResult(sqltypes.MakeTestFields("Table|Op|Msg_type|Msg_text", "varchar|varchar|varchar|varchar"), "vt_ks.t|check|"+msgType+"|"+msgText)
+	}
+	mysqld.FetchSuperQueryMap = map[string]*sqltypes.Result{
+		listTablesToChecksumQuery:          sqltypes.MakeTestResult(sqltypes.MakeTestFields("table_schema|table_name", "varchar|varchar"), "vt_ks|t"),
+		"CHECK TABLE `vt_ks`.`t`":          checkResult("status", "OK"),
+		"CHECKSUM TABLE `vt_ks`.`t`":       sqltypes.MakeTestResult(sqltypes.MakeTestFields("Table|Checksum", "varchar|uint64"), "vt_ks.t|1234"),
+		"SELECT COUNT(*) FROM `vt_ks`.`t`": sqltypes.MakeTestResult(sqltypes.MakeTestFields("count(*)", "int64"), "10"),
+	}
+
+	checksums, err := GetTableChecksums(ctx, mysqld)
+	require.NoError(t, err)
+	assert.Equal(t, []TableChecksum{{Table: "vt_ks.t", Rows: 10, Checksum: 1234}}, checksums)
+
+	logger := logutil.NewMemoryLogger()
+	assert.NoError(t, VerifyRestoredBackup(ctx, mysqld, logger, &BackupManifest{}))
+	assert.NoError(t, VerifyRestoredBackup(ctx, mysqld, logger, &BackupManifest{TableChecksums: checksums}))
+
+	err = VerifyRestoredBackup(ctx, mysqld, logger, &BackupManifest{TableChecksums: []TableChecksum{{Table: "vt_ks.t", Rows: 11, Checksum: 1234}}})
+	assert.ErrorContains(t, err, "vt_ks.t has 10 rows instead of 11")
+	err = VerifyRestoredBackup(ctx, mysqld, logger, &BackupManifest{TableChecksums: []TableChecksum{{Table: "vt_ks.t", Rows: 10, Checksum: 1}}})
+	assert.ErrorContains(t, err, "vt_ks.t has checksum 1234 instead of 1")
+	err = VerifyRestoredBackup(ctx, mysqld, logger, &BackupManifest{TableChecksums: []TableChecksum{{Table: "vt_ks.u", Rows: 1, Checksum: 1}}})
+	assert.ErrorContains(t, err, "vt_ks.u is missing, vt_ks.t was not in the backup")
+
+	mysqld.FetchSuperQueryMap["CHECK TABLE `vt_ks`.`t`"] = checkResult("error", "Table is marked as crashed")
+	err = VerifyRestoredBackup(ctx, mysqld, logger, &BackupManifest{})
+	assert.ErrorContains(t, err, "table vt_ks.t is corrupted: error: Table is marked as crashed")
+}
+
+func TestWriteBackupVerification(t *testing.T) {
+	ctx := context.Background()
+	oldRoot := filebackupstorage.FileBackupStorageRoot
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+	defer func() { filebackupstorage.FileBackupStorageRoot = oldRoot }()
+
+	backupDir := GetBackupDir("ks", "0")
+	name := "2025-03-20.010000.zone1-0000000101"
+	dir := path.Join(filebackupstorage.FileBackupStorageRoot, backupDir, name)
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	manifest := &builtinBackupManifest{
+		BackupManifest: BackupManifest{BackupName: name, BackupMethod: builtinBackupEngineName},
+		FileEntries:    []FileEntry{{Base: backupData, Name: "ibdata1", Hash: "abcd"}},
+	}
+	data, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path.Join(dir, backupManifestFileName), data, 0644))
+
+	bs := (&filebackupstorage.FileBackupStorage{}).WithParams(backupstorage.Params{
+		Logger: logutil.NewMemoryLogger(),
+		Stats:  backupstats.NewFakeStats(),
+	})
+	bhs, err := bs.ListBackups(ctx, backupDir)
+	require.NoError(t, err)
+	require.Len(t, bhs, 1)
+
+	verification := &BackupVerification{VerifiedTime: "2025-03-21T01:00:00Z", Error: "table vt_ks.t is corrupted"}
+	require.NoError(t, WriteBackupVerification(ctx, bs, backupDir, bhs[0], verification))
+
+	got := &builtinBackupManifest{}
+	require.NoError(t, getBackupManifestInto(ctx, bhs[0], got))
+	assert.Equal(t, verification, got.Verification)
+	assert.Equal(t, manifest.FileEntries, got.FileEntries, "the engine specific fields are kept")
+	assert.Equal(t, name, got.BackupName)
+}
@@ -0,0 +1,334 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/textutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	mysqlctlpb "vitess.io/vitess/go/vt/proto/mysqlctl"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// The binary log archive of a shard holds the binary logs of its tablets, as
+// they are closed. Each archived binary log is an incremental backup of its
+// own, taken with the builtin backup engine, so that point in time recoveries
+// can apply it on top of a full backup of the shard, like any other
+// incremental backup.
+
+// GetBinlogArchiveDir returns the directory where the binary logs of the
+// given keyspace/shard are (or will be) archived. It is a sibling of the
+// backup directory of the shard, so that archived binary logs are not listed
+// with its backups.
+func GetBinlogArchiveDir(keyspace, shard string) string {
+	return fmt.Sprintf("%v/%v.binlogs", keyspace, shard)
+}
+
+// binlogToArchive is a closed binary log, and the GTID range it covers.
+type binlogToArchive struct {
+	name         string
+	fromPosition replication.Position
+	position     replication.Position
+}
+
+// chooseBinlogsToArchive chooses the closed binary logs that follow
+// archivedGTIDSet, using the same rules as incremental backups. Binary logs
+// without transactions are skipped. The position of each binary log
+// includes purgedGTIDSet, like the position of incremental backups does.
+func chooseBinlogsToArchive(
+	ctx context.Context,
+	archivedGTIDSet replication.GTIDSet,
+	purgedGTIDSet replication.GTIDSet,
+	binaryLogs []string,
+	pgtids func(ctx context.Context, binlog string) (gtids string, err error),
+) ([]binlogToArchive, error) {
+	binlogs, _, _, err := ChooseBinlogsForIncrementalBackup(ctx, archivedGTIDSet, purgedGTIDSet, binaryLogs, pgtids)
+	if err != nil {
+		return nil, err
+	}
+	var result []binlogToArchive
+	for _, binlog := range binlogs {
+		fromGTIDs, err := pgtids(ctx, binlog)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "cannot get previous gtids for binlog %v", binlog)
+		}
+		if fromGTIDs == "" {
+			// The first binary log of the server. It covers the archived GTIDs.
+			fromGTIDs = archivedGTIDSet.String()
+		}
+		// The Previous-GTIDs of the next binary log are the GTIDs up to the end
+		// of this one. ChooseBinlogsForIncrementalBackup never chooses the last,
+		// open, binary log, so there always is a next one.
+		toGTIDs, err := pgtids(ctx, binaryLogs[slices.Index(binaryLogs, binlog)+1])
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "cannot get the position at the end of binlog %v", binlog)
+		}
+		fromPosition, err := replication.ParsePosition(replication.Mysql56FlavorID, fromGTIDs)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "cannot parse position %v", fromGTIDs)
+		}
+		position, err := replication.ParsePosition(replication.Mysql56FlavorID, toGTIDs)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "cannot parse position %v", toGTIDs)
+		}
+		if fromPosition.Equal(position) {
+			// The binary log has no transactions.
+			continue
+		}
+		if purgedGTIDSet != nil {
+			position.GTIDSet = position.GTIDSet.Union(purgedGTIDSet)
+		}
+		result = append(result, binlogToArchive{name: binlog, fromPosition: fromPosition, position: position})
+	}
+	return result, nil
+}
+
+// getBinlogArchiveStorage returns the BackupStorage, with its stats scoped
+// like those of backups and restores.
+func getBinlogArchiveStorage(params BackupParams) (backupstorage.BackupStorage, error) {
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, vterrors.Wrap(err, "unable to get backup storage")
+	}
+	bsStats := params.Stats.Scope(
+		backupstats.Component(backupstats.BackupStorage),
+		backupstats.Implementation(
+			textutil.Title(backupstorage.BackupStorageImplementation),
+		),
+	)
+	return bs.WithParams(backupstorage.Params{
+		Logger: params.Logger,
+		Stats:  bsStats,
+	}), nil
+}
+
+// archivedGTIDSet returns the union of the positions of the archived binary
+// logs, or nil if none has been archived.
+func archivedGTIDSet(ctx context.Context, params BackupParams, bhs []backupstorage.BackupHandle) replication.GTIDSet {
+	var gtidSet replication.GTIDSet
+	for _, bh := range bhs {
+		bm, err := GetBackupManifest(ctx, bh)
+		if err != nil {
+			params.Logger.Warningf("Possibly incomplete archived binlog %v: can't read MANIFEST: %v", bh.Name(), err)
+			continue
+		}
+		if gtidSet == nil {
+			gtidSet = bm.Position.GTIDSet
+		} else {
+			gtidSet = gtidSet.Union(bm.Position.GTIDSet)
+		}
+	}
+	return gtidSet
+}
+
+// ArchiveBinlogs copies the binary logs of the server that were closed since
+// the last archived binary log of the shard to its binary log archive. When
+// nothing has been archived yet, or the binary logs that follow the archive
+// were purged, it archives the binary logs that follow the latest backup of
+// the shard. It returns the names of the archived binary logs.
+func ArchiveBinlogs(ctx context.Context, params BackupParams) (archived []string, err error) {
+	if params.Stats == nil {
+		params.Stats = backupstats.NoStats()
+	}
+	bs, err := getBinlogArchiveStorage(params)
+	if err != nil {
+		return nil, err
+	}
+	defer bs.Close()
+
+	archiveDir := GetBinlogArchiveDir(params.Keyspace, params.Shard)
+	abhs, err := bs.ListBackups(ctx, archiveDir)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+	latestBackupGTIDSet := func() (replication.GTIDSet, error) {
+		bhs, err := bs.ListBackups(ctx, GetBackupDir(params.Keyspace, params.Shard))
+		if err != nil {
+			return nil, vterrors.Wrap(err, "ListBackups failed")
+		}
+		_, bm, err := findLatestSuccessfulBackup(ctx, params.Logger, bhs, "")
+		if err != nil {
+			return nil, err
+		}
+		return bm.Position.GTIDSet, nil
+	}
+
+	gtidPurged, err := params.Mysqld.GetGTIDPurged(ctx)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "can't get @@gtid_purged")
+	}
+	binaryLogs, err := params.Mysqld.GetBinaryLogs(ctx)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "cannot get binary logs")
+	}
+	previousGTIDs := map[string]string{}
+	getBinlogPreviousGTIDs := func(ctx context.Context, binlog string) (gtids string, err error) {
+		gtids, ok := previousGTIDs[binlog]
+		if ok {
+			return gtids, nil
+		}
+		gtids, err = params.Mysqld.GetPreviousGTIDs(ctx, binlog)
+		if err != nil {
+			return gtids, err
+		}
+		previousGTIDs[binlog] = gtids
+		return gtids, nil
+	}
+
+	var binlogs []binlogToArchive
+	archivedGTIDs := archivedGTIDSet(ctx, params, abhs)
+	if archivedGTIDs != nil {
+		binlogs, err = chooseBinlogsToArchive(ctx, archivedGTIDs, gtidPurged.GTIDSet, binaryLogs, getBinlogPreviousGTIDs)
+		if err != nil {
+			params.Logger.Warningf("Cannot archive the binlogs that follow the binlog archive at %v, archiving the binlogs that follow the latest backup: %v", archivedGTIDs, err)
+		}
+	}
+	if archivedGTIDs == nil || err != nil {
+		fromGTIDSet, err := latestBackupGTIDSet()
+		if err != nil {
+			return nil, vterrors.Wrap(err, "cannot find a backup for the binlog archive to start from")
+		}
+		if binlogs, err = chooseBinlogsToArchive(ctx, fromGTIDSet, gtidPurged.GTIDSet, binaryLogs, getBinlogPreviousGTIDs); err != nil {
+			return nil, vterrors.Wrapf(err, "cannot choose the binlogs to archive after position %v", fromGTIDSet)
+		}
+		if archivedGTIDs != nil {
+			// Don't archive the same binlogs again on every run.
+			binlogs = slices.DeleteFunc(binlogs, func(binlog binlogToArchive) bool {
+				return archivedGTIDs.Contains(binlog.position.GTIDSet)
+			})
+		}
+	}
+	if len(binlogs) == 0 {
+		return nil, nil
+	}
+
+	serverUUID, err := params.Mysqld.GetServerUUID(ctx)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "can't get server uuid")
+	}
+	mysqlVersion, err := params.Mysqld.GetVersionString(ctx)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "can't get MySQL version")
+	}
+	be := &BuiltinBackupEngine{}
+	for _, binlog := range binlogs {
+		if err := archiveBinlog(ctx, params, bs, be, binlog, gtidPurged, serverUUID, mysqlVersion); err != nil {
+			return archived, vterrors.Wrapf(err, "cannot archive binlog %v", binlog.name)
+		}
+		archived = append(archived, binlog.name)
+	}
+	return archived, nil
+}
+
+// archiveBinlog archives a single binary log, as an incremental backup.
+func archiveBinlog(ctx context.Context, params BackupParams, bs backupstorage.BackupStorage, be *BuiltinBackupEngine, binlog binlogToArchive, gtidPurged replication.Position, serverUUID, mysqlVersion string) error {
+	fe := FileEntry{Base: backupBinlogDir, Name: binlog.name}
+	fullPath, err := fe.fullPath(params.Cnf)
+	if err != nil {
+		return err
+	}
+	req := &mysqlctlpb.ReadBinlogFilesTimestampsRequest{BinlogFileNames: []string{fullPath}}
+	resp, err := params.Mysqld.ReadBinlogFilesTimestamps(ctx, req)
+	if err != nil {
+		return vterrors.Wrapf(err, "reading timestamps from binlog file %v", binlog.name)
+	}
+	if resp == nil || resp.FirstTimestampBinlog == "" || resp.LastTimestampBinlog == "" {
+		return vterrors.Errorf(vtrpc.Code_ABORTED, "empty binlog name in response. Request=%v, Response=%v", req, resp)
+	}
+	firstTimestamp := protoutil.TimeFromProto(resp.FirstTimestamp).UTC()
+	incrDetails := &IncrementalBackupDetails{
+		FirstTimestamp:       FormatRFC3339(firstTimestamp),
+		FirstTimestampBinlog: filepath.Base(resp.FirstTimestampBinlog),
+		LastTimestamp:        FormatRFC3339(protoutil.TimeFromProto(resp.LastTimestamp).UTC()),
+		LastTimestampBinlog:  filepath.Base(resp.LastTimestampBinlog),
+	}
+
+	archiveDir := GetBinlogArchiveDir(params.Keyspace, params.Shard)
+	name := fmt.Sprintf("%v.%v.%v", firstTimestamp.Format(BackupTimestampFormat), params.TabletAlias, binlog.name)
+	bh, err := bs.StartBackup(ctx, archiveDir, name)
+	if err != nil {
+		return vterrors.Wrap(err, "StartBackup failed")
+	}
+	params.Logger.Infof("Archiving binlog %v to %v/%v, from position %v to position %v", binlog.name, archiveDir, name, binlog.fromPosition, binlog.position)
+
+	beParams := params.Copy()
+	beParams.IncrementalFromPos = replication.EncodePosition(binlog.fromPosition)
+	beParams.BackupTime = time.Now()
+	beParams.Stats = params.Stats.Scope(
+		backupstats.Component(backupstats.BackupEngine),
+		backupstats.Implementation(textutil.Title(builtinBackupEngineName)),
+	)
+	if err := be.backupFiles(ctx, beParams, bh, binlog.position, gtidPurged, binlog.fromPosition, "", []string{binlog.name}, serverUUID, mysqlVersion, incrDetails, nil); err != nil {
+		if abortErr := bh.AbortBackup(ctx); abortErr != nil {
+			params.Logger.Errorf2(abortErr, "failed to abort archiving binlog %v", binlog.name)
+		}
+		return err
+	}
+	return nil
+}
+
+// PruneBinlogArchive removes the archived binary logs of the shard whose
+// last transaction is older than before, and returns their names.
+func PruneBinlogArchive(ctx context.Context, params BackupParams, before time.Time) (removed []string, err error) {
+	if params.Stats == nil {
+		params.Stats = backupstats.NoStats()
+	}
+	bs, err := getBinlogArchiveStorage(params)
+	if err != nil {
+		return nil, err
+	}
+	defer bs.Close()
+
+	archiveDir := GetBinlogArchiveDir(params.Keyspace, params.Shard)
+	bhs, err := bs.ListBackups(ctx, archiveDir)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+	for _, bh := range bhs {
+		bm, err := GetBackupManifest(ctx, bh)
+		if err != nil {
+			// The binlog may still be being archived.
+			continue
+		}
+		if bm.IncrementalDetails == nil {
+			continue
+		}
+		lastTimestamp, err := ParseRFC3339(bm.IncrementalDetails.LastTimestamp)
+		if err != nil {
+			return removed, vterrors.Wrapf(err, "cannot parse the last timestamp of archived binlog %v", bh.Name())
+		}
+		if !lastTimestamp.Before(before) {
+			continue
+		}
+		params.Logger.Infof("Removing archived binlog %v from %v, whose last transaction is from %v", bh.Name(), archiveDir, bm.IncrementalDetails.LastTimestamp)
+		if err := bs.RemoveBackup(ctx, archiveDir, bh.Name()); err != nil {
+			return removed, vterrors.Wrapf(err, "cannot remove archived binlog %v", bh.Name())
+		}
+		removed = append(removed, bh.Name())
+	}
+	return removed, nil
+}
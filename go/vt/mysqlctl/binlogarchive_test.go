@@ -0,0 +1,152 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/replication"
+)
+
+func TestGetBinlogArchiveDir(t *testing.T) {
+	assert.Equal(t, "commerce/-80.binlogs", GetBinlogArchiveDir("commerce", "-80"))
+	assert.NotEqual(t, GetBackupDir("commerce", "-80"), GetBinlogArchiveDir("commerce", "-80"))
+}
+
+func TestChooseBinlogsToArchive(t *testing.T) {
+	binlogs := []string{
+		"vt-bin.000001",
+		"vt-bin.000002",
+		"vt-bin.000003",
+		"vt-bin.000004",
+		"vt-bin.000005",
+	}
+	previousGTIDs := map[string]string{
+		"vt-bin.000001": "16b1039f-22b6-11ed-b765-0a43f95f28a3:1-50",
+		"vt-bin.000002": "16b1039f-22b6-11ed-b765-0a43f95f28a3:1-60",
+		"vt-bin.000003": "16b1039f-22b6-11ed-b765-0a43f95f28a3:1-60",
+		"vt-bin.000004": "16b1039f-22b6-11ed-b765-0a43f95f28a3:1-78",
+		"vt-bin.000005": "16b1039f-22b6-11ed-b765-0a43f95f28a3:1-243",
+	}
+	pgtids := func(ctx context.Context, binlog string) (gtids string, err error) {
+		return previousGTIDs[binlog], nil
+	}
+	tcs := []struct {
+		name        string
+		archivedPos string
+		gtidPurged  string
+		expected    []string
+		expectError string
+	}{
+		{
+			name:        "one binlog per GTID range, skipping binlogs without transactions",
+			archivedPos: "16b1039f-22b6-11ed-b765-0a43f95f28a3:1-55",
+			expected: []string{
+				"vt-bin.000001: 16b1039f-22b6-11ed-b765-0a43f95f28a3:1-50...16b1039f-22b6-11ed-b765-0a43f95f28a3:1-60",
+				"vt-bin.000003: 16b1039f-22b6-11ed-b765-0a43f95f28a3:1-60...16b1039f-22b6-11ed-b765-0a43f95f28a3:1-78",
+				"vt-bin.000004: 16b1039f-22b6-11ed-b765-0a43f95f28a3:1-78...16b1039f-22b6-11ed-b765-0a43f95f28a3:1-243",
+			},
+		},
+		{
+			name:        "up to date",
+			archivedPos: "16b1039f-22b6-11ed-b765-0a43f95f28a3:1-243",
+		},
+		{
+			name:        "positions include gtid_purged",
+			archivedPos: "16b1039f-22b6-11ed-b765-0a43f95f28a3:1-78",
+			gtidPurged:  "16b1039f-22b6-11ed-b765-0a43f95f28a3:1-10,2b3c4d5e-22b6-11ed-b765-0a43f95f28a3:1-5",
+			expected: []string{
+				"vt-bin.000004: 16b1039f-22b6-11ed-b765-0a43f95f28a3:1-78...16b1039f-22b6-11ed-b765-0a43f95f28a3:1-243,2b3c4d5e-22b6-11ed-b765-0a43f95f28a3:1-5",
+			},
+		},
+		{
+			name:        "purged binlogs",
+			archivedPos: "16b1039f-22b6-11ed-b765-0a43f95f28a3:1-40",
+			expectError: "Required entries have been purged",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			archived, err := replication.ParsePosition(replication.Mysql56FlavorID, tc.archivedPos)
+			require.NoError(t, err)
+			purged, err := replication.ParsePosition(replication.Mysql56FlavorID, tc.gtidPurged)
+			require.NoError(t, err)
+			result, err := chooseBinlogsToArchive(context.Background(), archived.GTIDSet, purged.GTIDSet, binlogs, pgtids)
+			if tc.expectError != "" {
+				assert.ErrorContains(t, err, tc.expectError)
+				return
+			}
+			require.NoError(t, err)
+			var chosen []string
+			for _, binlog := range result {
+				chosen = append(chosen, fmt.Sprintf("%v: %v...%v", binlog.name, binlog.fromPosition.GTIDSet, binlog.position.GTIDSet))
+			}
+			assert.Equal(t, tc.expected, chosen)
+		})
+	}
+}
+
+func TestFindPITRToTimePathWithBinlogArchive(t *testing.T) {
+	gtidSet := func(n int) replication.Position {
+		pos, err := replication.ParsePosition(replication.Mysql56FlavorID, fmt.Sprintf("16b1039f-22b6-11ed-b765-0a43f95f28a3:1-%d", n))
+		require.NoError(t, err)
+		return pos
+	}
+	start := time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC)
+	manifests := []*BackupManifest{
+		{
+			BackupMethod: builtinBackupEngineName,
+			Position:     gtidSet(10),
+			BackupTime:   FormatRFC3339(start),
+			FinishedTime: FormatRFC3339(start),
+		},
+	}
+	// An archived binlog per minute, with a transaction per second.
+	for i := range 200 {
+		manifests = append(manifests, &BackupManifest{
+			BackupMethod: builtinBackupEngineName,
+			FromPosition: gtidSet(10 + 60*i),
+			Position:     gtidSet(10 + 60*(i+1)),
+			Incremental:  true,
+			BackupTime:   FormatRFC3339(start.Add(time.Duration(i+1) * time.Minute)),
+			FinishedTime: FormatRFC3339(start.Add(time.Duration(i+1) * time.Minute)),
+			IncrementalDetails: &IncrementalBackupDetails{
+				FirstTimestamp: FormatRFC3339(start.Add(time.Duration(i)*time.Minute + time.Second)),
+				LastTimestamp:  FormatRFC3339(start.Add(time.Duration(i+1) * time.Minute)),
+			},
+		})
+	}
+
+	path, err := FindPITRToTimePath(start.Add(90*time.Minute+17*time.Second), manifests)
+	require.NoError(t, err)
+	require.Len(t, path, 92)
+	assert.Equal(t, manifests[91], path[len(path)-1])
+
+	// There is no path beyond the archive. Finding that out must not explore
+	// every subset of the archived binlogs.
+	_, err = FindPITRToTimePath(start.Add(300*time.Minute), manifests)
+	assert.ErrorContains(t, err, "no path found that leads to timestamp")
+
+	_, err = FindPITRPath(gtidSet(100000).GTIDSet, manifests)
+	assert.ErrorContains(t, err, "no path found that leads to GTID")
+}
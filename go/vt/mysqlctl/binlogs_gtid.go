@@ -215,6 +215,9 @@ func FindPITRPath(restoreToGTIDSet replication.GTIDSet, manifests [](*BackupMani
 	purgedGTIDSet := fullBackup.PurgedPosition.GTIDSet
 
 	var validRestorePaths []BackupManifestPath
+	// A binlog archive adds an incremental backup per binary log. Searches that don't lead to a valid path
+	// are remembered, so that we don't explore all their subsets.
+	deadEnds := make(map[string]bool)
 	// recursive function that searches for all possible paths:
 	var findPaths func(baseGTIDSet replication.GTIDSet, pathManifests []*BackupManifest, remainingManifests []*BackupManifest)
 	findPaths = func(baseGTIDSet replication.GTIDSet, pathManifests []*BackupManifest, remainingManifests []*BackupManifest) {
@@ -226,6 +229,15 @@ func FindPITRPath(restoreToGTIDSet replication.GTIDSet, manifests [](*BackupMani
 			return
 		}
 		// remove the above if you wish to explore all paths.
+		deadEnd := fmt.Sprintf("%d/%v", len(remainingManifests), baseGTIDSet)
+		if deadEnds[deadEnd] {
+			return
+		}
+		defer func() {
+			if len(validRestorePaths) == 0 {
+				deadEnds[deadEnd] = true
+			}
+		}()
 		if baseGTIDSet.Contains(restoreToGTIDSet) {
 			// successful end of path. Update list of successful paths
 			validRestorePaths = append(validRestorePaths, pathManifests)
@@ -337,6 +349,8 @@ func FindPITRToTimePath(restoreToTime time.Time, manifests [](*BackupManifest))
 	}
 
 	var validRestorePaths []BackupManifestPath
+	// See FindPITRPath. The end of a path also depends on its last manifest.
+	deadEnds := make(map[string]bool)
 	// recursive function that searches for all possible paths:
 	var findPaths func(baseGTIDSet replication.GTIDSet, pathManifests []*BackupManifest, remainingManifests []*BackupManifest) error
 	findPaths = func(baseGTIDSet replication.GTIDSet, pathManifests []*BackupManifest, remainingManifests []*BackupManifest) error {
@@ -349,6 +363,15 @@ func FindPITRToTimePath(restoreToTime time.Time, manifests [](*BackupManifest))
 		}
 		// remove the above if you wish to explore all paths.
 		lastManifest := pathManifests[len(pathManifests)-1]
+		deadEnd := fmt.Sprintf("%p/%d/%v", lastManifest, len(remainingManifests), baseGTIDSet)
+		if deadEnds[deadEnd] {
+			return nil
+		}
+		defer func() {
+			if len(validRestorePaths) == 0 {
+				deadEnds[deadEnd] = true
+			}
+		}()
 		if lastManifest.Incremental {
 			lastManifestIncrementalDetails := lastManifest.IncrementalDetails
 
@@ -89,6 +89,21 @@ var (
 	// The path should exist.
 	// When empty, the default OS temp dir is assumed.
 	builtinIncrementalRestorePath = ""
+
+	// The key provider that wraps the data keys of encrypted backups. When
+	// empty, backups are not encrypted.
+	builtinBackupEncryptionKeyProvider = ""
+
+	// Whether to record the checksums and row counts of the tables in the
+	// MANIFEST of full backups, so that restores of the backup can be verified.
+	builtinBackupTableChecksums = false
+
+	// Whether to split the files of full backups into content-defined chunks,
+	// which are only uploaded when no previous backup of the shard stored them.
+	builtinBackupChunking = false
+
+	// The average size of the chunks of chunked backups.
+	builtinBackupChunkSize = 4 * 1024 * 1024 /* 4 MiB */
 )
 
 // BuiltinBackupEngine encapsulates the logic of the builtin engine
@@ -131,6 +146,29 @@ type builtinBackupManifest struct {
 	// ExternalDecompressor will be used. If neither are set, the restore will
 	// abort.
 	ExternalDecompressor string
+
+	// EncryptionKeyProvider is the BackupKeyProvider that wrapped the data
+	// key of the backup, when the files of the backup are encrypted.
+	EncryptionKeyProvider string `json:",omitempty"`
+
+	// EncryptionKeyID is the id of the key that the key provider wrapped the
+	// data key with.
+	EncryptionKeyID string `json:",omitempty"`
+
+	// EncryptedDataKey is the wrapped data key of the backup.
+	EncryptedDataKey []byte `json:",omitempty"`
+
+	// ChunkStore is the directory of the backup storage that has the chunks
+	// of the files, for chunked backups.
+	ChunkStore string `json:",omitempty"`
+
+	// ChunkKeyID is the id of the chunk key that the chunks of an encrypted
+	// chunked backup are encrypted with.
+	ChunkKeyID string `json:",omitempty"`
+
+	// EncryptedChunkKey is the chunk key, encrypted with the data key of the
+	// backup.
+	EncryptedChunkKey []byte `json:",omitempty"`
 }
 
 // FileEntry is one file to backup
@@ -153,6 +191,10 @@ type FileEntry struct {
 	// for writing files in a temporary directory
 	ParentPath string
 
+	// Chunks are the chunks of the file in the chunk store, for chunked
+	// backups. Hash is then the hash of the content of the file.
+	Chunks []FileChunk `json:",omitempty"`
+
 	// RetryCount specifies how many times we retried restoring/backing up this FileEntry.
 	// If we fail to restore/backup this FileEntry, we will retry up to maxRetriesPerFile times.
 	// Every time the builtin backup engine retries this file, we increment this field by 1.
@@ -173,6 +215,10 @@ func registerBuiltinBackupEngineFlags(fs *pflag.FlagSet) {
 	fs.UintVar(&builtinBackupFileReadBufferSize, "builtinbackup-file-read-buffer-size", builtinBackupFileReadBufferSize, "read files using an IO buffer of this many bytes. Golang defaults are used when set to 0.")
 	fs.UintVar(&builtinBackupFileWriteBufferSize, "builtinbackup-file-write-buffer-size", builtinBackupFileWriteBufferSize, "write files using an IO buffer of this many bytes. Golang defaults are used when set to 0.")
 	fs.StringVar(&builtinIncrementalRestorePath, "builtinbackup-incremental-restore-path", builtinIncrementalRestorePath, "the directory where incremental restore files, namely binlog files, are extracted to. In k8s environments, this should be set to a directory that is shared between the vttablet and mysqld pods. The path should exist. When empty, the default OS temp dir is assumed.")
+	fs.StringVar(&builtinBackupEncryptionKeyProvider, "builtinbackup-encryption-key-provider", builtinBackupEncryptionKeyProvider, "encrypt the files of builtin backups with AES-256-GCM, with data keys that are wrapped by this key provider (e.g. keyfile). Backups are not encrypted when empty. Restores use the key provider that is recorded in the MANIFEST of the backup.")
+	fs.BoolVar(&builtinBackupChunking, "builtinbackup-chunking", builtinBackupChunking, "split the files of full backups into content-defined chunks, that are stored in the <keyspace>/<shard>.chunks directory of the backup storage, and only upload the chunks that no previous backup of the shard stored. Chunked backups need a builtin compression engine.")
+	fs.IntVar(&builtinBackupChunkSize, "builtinbackup-chunk-size", builtinBackupChunkSize, "the average size in bytes of the chunks of chunked backups. Chunks are from a quarter to four times this size.")
+	fs.BoolVar(&builtinBackupTableChecksums, "builtinbackup-table-checksums", builtinBackupTableChecksums, "record the checksums and row counts of the tables in the MANIFEST of full backups, so that verifying a restore of the backup can compare them. Computing them reads every table before mysqld is shut down.")
 }
 
 // fullPath returns the full path of the entry, based on its type
@@ -393,7 +439,7 @@ func (be *BuiltinBackupEngine) executeIncrementalBackup(ctx context.Context, par
 	// incrementalBackupFromGTID is the "previous GTIDs" of the first binlog file we back up.
 	// It is a fact that incrementalBackupFromGTID is earlier or equal to params.IncrementalFromPos.
 	// In the backup manifest file, we document incrementalBackupFromGTID, not the user's requested position.
-	if err := be.backupFiles(ctx, params, bh, incrementalBackupToPosition, gtidPurged, incrementalBackupFromPosition, fromBackupName, binaryLogsToBackup, serverUUID, mysqlVersion, incrDetails); err != nil {
+	if err := be.backupFiles(ctx, params, bh, incrementalBackupToPosition, gtidPurged, incrementalBackupFromPosition, fromBackupName, binaryLogsToBackup, serverUUID, mysqlVersion, incrDetails, nil); err != nil {
 		return BackupUnusable, err
 	}
 	return BackupUsable, nil
@@ -487,6 +533,14 @@ func (be *BuiltinBackupEngine) executeFullBackup(ctx context.Context, params Bac
 		return BackupUnusable, vterrors.Wrap(err, "can't get MySQL version")
 	}
 
+	var tableChecksums []TableChecksum
+	if builtinBackupTableChecksums {
+		params.Logger.Infof("Computing the checksums of the tables")
+		if tableChecksums, err = GetTableChecksums(ctx, params.Mysqld); err != nil {
+			return BackupUnusable, vterrors.Wrap(err, "can't compute the checksums of the tables")
+		}
+	}
+
 	// check if we need to set innodb_fast_shutdown=0 for a backup safe for upgrades
 	if params.UpgradeSafe {
 		if _, err := params.Mysqld.FetchSuperQuery(ctx, "SET GLOBAL innodb_fast_shutdown=0"); err != nil {
@@ -503,7 +557,7 @@ func (be *BuiltinBackupEngine) executeFullBackup(ctx context.Context, params Bac
 	}
 
 	// Backup everything, capture the error.
-	backupErr := be.backupFiles(ctx, params, bh, replicationPosition, gtidPurgedPosition, replication.Position{}, "", nil, serverUUID, mysqlVersion, nil)
+	backupErr := be.backupFiles(ctx, params, bh, replicationPosition, gtidPurgedPosition, replication.Position{}, "", nil, serverUUID, mysqlVersion, nil, tableChecksums)
 	backupResult := BackupUnusable
 	if backupErr == nil {
 		backupResult = BackupUsable
@@ -594,6 +648,7 @@ func (be *BuiltinBackupEngine) backupFiles(
 	serverUUID string,
 	mysqlVersion string,
 	incrDetails *IncrementalBackupDetails,
+	tableChecksums []TableChecksum,
 ) (finalErr error) {
 	// backupFiles always wait for AddFiles to finish its work before returning, unless there has been a
 	// non-recoverable error in the process, in both cases we can cancel the context safely.
@@ -614,8 +669,24 @@ func (be *BuiltinBackupEngine) backupFiles(
 	}
 	params.Logger.Infof("found %v files to backup", len(fes))
 
+	enc, err := newBackupEncryption(ctx, builtinBackupEncryptionKeyProvider)
+	if err != nil {
+		return err
+	}
+	if enc != nil {
+		params.Logger.Infof("encrypting backup files with key %v of key provider %v", enc.keyID, enc.keyProvider)
+	}
+
+	var chunks *backupChunkStore
+	if builtinBackupChunking && !isIncrementalBackup(params) {
+		if chunks, err = be.startChunkedBackup(ctx, params, bh, enc); err != nil {
+			return err
+		}
+		defer chunks.bs.Close()
+	}
+
 	// The error here can be ignored safely. Failed FileEntry's are handled in the next 'if' statement.
-	_ = be.backupFileEntries(ctx, fes, bh, params)
+	_ = be.backupFileEntries(ctx, fes, bh, params, enc, chunks)
 
 	// BackupHandle supports the BackupErrorRecorder interface for tracking errors
 	// across any goroutines that fan out to take the backup. This means that we
@@ -646,16 +717,22 @@ func (be *BuiltinBackupEngine) backupFiles(
 			}
 			bh.ResetErrorForFile(file)
 		}
-		err = be.backupFileEntries(ctx, newFEs, bh, params)
+		err = be.backupFileEntries(ctx, newFEs, bh, params, enc, chunks)
 		if err != nil {
 			return err
 		}
 	}
 
+	if chunks != nil {
+		if err := chunks.checkReferencedChunks(ctx); err != nil {
+			return err
+		}
+	}
+
 	// Backup the MANIFEST file and apply retry logic.
 	var manifestErr error
 	for currentRetry := 0; currentRetry <= maxRetriesPerFile; currentRetry++ {
-		manifestErr = be.backupManifest(ctx, params, bh, backupPosition, purgedPosition, fromPosition, fromBackupName, serverUUID, mysqlVersion, incrDetails, fes, currentRetry)
+		manifestErr = be.backupManifest(ctx, params, bh, backupPosition, purgedPosition, fromPosition, fromBackupName, serverUUID, mysqlVersion, incrDetails, tableChecksums, fes, enc, chunks, currentRetry)
 		if manifestErr == nil {
 			break
 		}
@@ -671,7 +748,7 @@ func (be *BuiltinBackupEngine) backupFiles(
 // This function will ignore empty FileEntry, allowing the retry mechanism to send a partially empty slice, to not
 // mess up the index of retriable FileEntry.
 // This function does not leave any background operation behind itself, all calls to bh.AddFile will be finished or canceled.
-func (be *BuiltinBackupEngine) backupFileEntries(ctx context.Context, fes []FileEntry, bh backupstorage.BackupHandle, params BackupParams) error {
+func (be *BuiltinBackupEngine) backupFileEntries(ctx context.Context, fes []FileEntry, bh backupstorage.BackupHandle, params BackupParams, enc *backupEncryption, chunks *backupChunkStore) error {
 	ctxCancel, cancel := context.WithCancel(ctx)
 	defer func() {
 		// If we reached this defer in all cases we can cancel the context.
@@ -705,7 +782,12 @@ func (be *BuiltinBackupEngine) backupFileEntries(ctx context.Context, fes []File
 
 			// Backup the individual file.
 			var errBackupFile error
-			if errBackupFile = be.backupFile(ctxCancel, params, bh, fe, name); errBackupFile != nil {
+			if chunks != nil {
+				errBackupFile = be.backupChunkedFile(ctxCancel, params, chunks, fe)
+			} else {
+				errBackupFile = be.backupFile(ctxCancel, params, bh, fe, name, enc)
+			}
+			if errBackupFile != nil {
 				bh.RecordError(name, vterrors.Wrapf(errBackupFile, "failed to backup file '%s'", name))
 				if fe.RetryCount >= maxRetriesPerFile {
 					// this is the last attempt, and we have an error, we can cancel everything and fail fast.
@@ -834,7 +916,7 @@ func (bp *backupPipe) ReportProgress(ctx context.Context, period time.Duration,
 }
 
 // backupFile backs up an individual file.
-func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, fe *FileEntry, name string) (finalErr error) {
+func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, fe *FileEntry, name string, enc *backupEncryption) (finalErr error) {
 	// We need another context that does not live outside of this function.
 	// Reporting progress, compressing and writing are operations that will be
 	// over by the time we exit this function, they can use this cancelable context.
@@ -912,6 +994,21 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 			}
 
 		}()
+		// Create the encryption pipe, if necessary. It comes after the
+		// compressor, as encrypted data does not compress.
+		if enc != nil {
+			encryptor, err := enc.newEncryptingWriter(writer)
+			if err != nil {
+				return vterrors.Wrap(err, "can't create encryptor")
+			}
+			writer = encryptor
+			defer func() {
+				// Close the encryptor after the compressor, to write the last segment.
+				if err := encryptor.Close(); err != nil {
+					createAndCopyErr = errors.Join(createAndCopyErr, vterrors.Wrapf(err, "failed to close encryptor %v", fe.Name))
+				}
+			}()
+		}
 		// Create the gzip compression pipe, if necessary.
 		if backupStorageCompress {
 			var compressor io.WriteCloser
@@ -974,7 +1071,10 @@ func (be *BuiltinBackupEngine) backupManifest(
 	serverUUID string,
 	mysqlVersion string,
 	incrDetails *IncrementalBackupDetails,
+	tableChecksums []TableChecksum,
 	fes []FileEntry,
+	enc *backupEncryption,
+	chunks *backupChunkStore,
 	currentAttempt int,
 ) (finalErr error) {
 	retryStr := retryToString(currentAttempt)
@@ -1021,6 +1121,7 @@ func (be *BuiltinBackupEngine) backupManifest(
 				MySQLVersion:       mysqlVersion,
 				UpgradeSafe:        params.UpgradeSafe,
 				IncrementalDetails: incrDetails,
+				TableChecksums:     tableChecksums,
 			},
 
 			// Builtin-specific fields
@@ -1029,6 +1130,18 @@ func (be *BuiltinBackupEngine) backupManifest(
 			CompressionEngine:    CompressionEngineName,
 			ExternalDecompressor: ManifestExternalDecompressorCmd,
 		}
+		if enc != nil {
+			bm.EncryptionKeyProvider = enc.keyProvider
+			bm.EncryptionKeyID = enc.keyID
+			bm.EncryptedDataKey = enc.wrappedKey
+		}
+		if chunks != nil {
+			bm.ChunkStore = chunks.dir
+			if chunks.key != nil {
+				bm.ChunkKeyID = chunks.key.id
+				bm.EncryptedChunkKey = chunks.key.sealed
+			}
+		}
 		data, err := json.MarshalIndent(bm, "", "  ")
 		if err != nil {
 			return vterrors.Wrapf(err, "cannot JSON encode %v %s", backupManifestFileName, retryStr)
@@ -1171,8 +1284,27 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 			return "", err
 		}
 	}
+	enc, err := openBackupEncryption(ctx, &bm)
+	if err != nil {
+		return "", err
+	}
+	var chunks *backupChunkStore
+	if bm.ChunkStore != "" {
+		bs, err := getBackupChunkStorage(params.Logger, params.Stats)
+		if err != nil {
+			return "", err
+		}
+		defer bs.Close()
+		key, err := openBackupChunkKey(ctx, &bm)
+		if err != nil {
+			return "", err
+		}
+		if chunks, err = openBackupChunkStore(ctx, params.Logger, bs, bm.ChunkStore, key); err != nil {
+			return "", err
+		}
+	}
 	fes := bm.FileEntries
-	_ = be.restoreFileEntries(ctx, fes, bh, bm, params, createdDir)
+	_ = be.restoreFileEntries(ctx, fes, bh, bm, params, createdDir, enc, chunks)
 	if files := bh.GetFailedFiles(); len(files) > 0 {
 		newFEs := make([]FileEntry, len(fes))
 		for _, file := range files {
@@ -1186,11 +1318,12 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 				Name:       oldFes.Name,
 				ParentPath: oldFes.ParentPath,
 				Hash:       oldFes.Hash,
+				Chunks:     oldFes.Chunks,
 				RetryCount: 1,
 			}
 			bh.ResetErrorForFile(file)
 		}
-		err = be.restoreFileEntries(ctx, newFEs, bh, bm, params, createdDir)
+		err = be.restoreFileEntries(ctx, newFEs, bh, bm, params, createdDir, enc, chunks)
 		if err != nil {
 			return "", err
 		}
@@ -1198,7 +1331,7 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 	return createdDir, nil
 }
 
-func (be *BuiltinBackupEngine) restoreFileEntries(ctx context.Context, fes []FileEntry, bh backupstorage.BackupHandle, bm builtinBackupManifest, params RestoreParams, createdDir string) error {
+func (be *BuiltinBackupEngine) restoreFileEntries(ctx context.Context, fes []FileEntry, bh backupstorage.BackupHandle, bm builtinBackupManifest, params RestoreParams, createdDir string, enc *backupEncryption, chunks *backupChunkStore) error {
 	g, ctx := errgroup.WithContext(ctx)
 	g.SetLimit(params.Concurrency)
 
@@ -1225,7 +1358,13 @@ func (be *BuiltinBackupEngine) restoreFileEntries(ctx context.Context, fes []Fil
 
 			// And restore the file.
 			params.Logger.Infof("Copying file %v: %v %s", name, fe.Name, retryToString(fe.RetryCount))
-			if errRestore := be.restoreFile(ctx, params, bh, fe, bm, name); errRestore != nil {
+			var errRestore error
+			if chunks != nil {
+				errRestore = be.restoreChunkedFile(ctx, params, chunks, fe)
+			} else {
+				errRestore = be.restoreFile(ctx, params, bh, fe, bm, name, enc)
+			}
+			if errRestore != nil {
 				bh.RecordError(name, vterrors.Wrapf(errRestore, "failed to restore file %v to %v", name, fe.Name))
 				if fe.RetryCount >= maxRetriesPerFile {
 					// this is the last attempt, and we have an error, we can return an error, which will let errgroup
@@ -1241,7 +1380,7 @@ func (be *BuiltinBackupEngine) restoreFileEntries(ctx context.Context, fes []Fil
 }
 
 // restoreFile restores an individual file.
-func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle, fe *FileEntry, bm builtinBackupManifest, name string) (finalErr error) {
+func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle, fe *FileEntry, bm builtinBackupManifest, name string, enc *backupEncryption) (finalErr error) {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
@@ -1273,6 +1412,13 @@ func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestorePa
 	}()
 	var reader io.Reader = br
 
+	// Create the decryptor if needed.
+	var decryptor *decryptingReader
+	if enc != nil {
+		decryptor = enc.newDecryptingReader(reader)
+		reader = decryptor
+	}
+
 	// Open the destination file for writing.
 	openDestAt := time.Now()
 	dest, err := fe.open(params.Cnf, false)
@@ -1345,6 +1491,13 @@ func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestorePa
 		return vterrors.Wrap(err, "failed to copy file contents")
 	}
 
+	// Authenticate the end of the file, which the decompressor may not read.
+	if decryptor != nil {
+		if err := decryptor.verify(); err != nil {
+			return vterrors.Wrapf(err, "failed to decrypt %v", fe.Name)
+		}
+	}
+
 	// Check the hash.
 	hash := br.HashString()
 	if hash != fe.Hash {
@@ -186,6 +186,15 @@ func (fbs *FileBackupStorage) StartBackup(ctx context.Context, dir, name string)
 	return NewBackupHandle(fbs, dir, name, false /*readOnly*/), nil
 }
 
+// UpdateBackup is part of the BackupUpdater interface
+func (fbs *FileBackupStorage) UpdateBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	p := path.Join(FileBackupStorageRoot, dir, name)
+	if _, err := os.Stat(p); err != nil {
+		return nil, err
+	}
+	return NewBackupHandle(fbs, dir, name, false /*readOnly*/), nil
+}
+
 // RemoveBackup is part of the BackupStorage interface
 func (fbs *FileBackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
 	p := path.Join(FileBackupStorageRoot, dir, name)
@@ -195,6 +195,12 @@ func (bs *GCSBackupStorage) StartBackup(ctx context.Context, dir, name string) (
 	}, nil
 }
 
+// UpdateBackup implements BackupUpdater. The objects written to the backup
+// replace the objects of the same name.
+func (bs *GCSBackupStorage) UpdateBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	return bs.StartBackup(ctx, dir, name)
+}
+
 // RemoveBackup implements BackupStorage.
 func (bs *GCSBackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
 	c, err := bs.client(ctx)
@@ -0,0 +1,122 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const keyfileKeyProviderName = "keyfile"
+
+// keyfilePath is the file that holds the key of the keyfile key provider.
+var keyfilePath string
+
+func init() {
+	for _, cmd := range []string{"vtbackup", "vtcombo", "vttablet", "vttestserver", "vtctld", "vtctldclient"} {
+		servenv.OnParseFor(cmd, registerKeyfileKeyProviderFlags)
+	}
+	BackupKeyProviderMap[keyfileKeyProviderName] = &keyfileKeyProvider{}
+}
+
+func registerKeyfileKeyProviderFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&keyfilePath, "builtinbackup-encryption-keyfile", keyfilePath, "the file that holds the key, as 64 hexadecimal characters, that the keyfile key provider wraps the data keys of encrypted backups with.")
+}
+
+// keyfileKeyProvider is a BackupKeyProvider that wraps the data keys with
+// AES-256-GCM, with a key read from a local file. The key id is derived from
+// the key, so that restoring with another key fails with a clear error.
+type keyfileKeyProvider struct{}
+
+// readKey reads the key of the keyfile, and returns it with its id.
+func (kp *keyfileKeyProvider) readKey() ([]byte, string, error) {
+	if keyfilePath == "" {
+		return nil, "", vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "--builtinbackup-encryption-keyfile is required by the %s key provider", keyfileKeyProviderName)
+	}
+	data, err := os.ReadFile(keyfilePath)
+	if err != nil {
+		return nil, "", vterrors.Wrapf(err, "cannot read the backup encryption keyfile %v", keyfilePath)
+	}
+	key, err := hex.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil || len(key) != encryptionDataKeySize {
+		return nil, "", vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "the backup encryption keyfile %v must hold a %d bytes key, as hexadecimal characters", keyfilePath, encryptionDataKeySize)
+	}
+	sum := sha256.Sum256(key)
+	return key, hex.EncodeToString(sum[:8]), nil
+}
+
+func newKeyfileAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// WrapKey is part of the BackupKeyProvider interface.
+func (kp *keyfileKeyProvider) WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	key, keyID, err := kp.readKey()
+	if err != nil {
+		return "", nil, err
+	}
+	aead, err := newKeyfileAEAD(key)
+	if err != nil {
+		return "", nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, err
+	}
+	return keyID, aead.Seal(nonce, nonce, dataKey, []byte(keyID)), nil
+}
+
+// UnwrapKey is part of the BackupKeyProvider interface.
+func (kp *keyfileKeyProvider) UnwrapKey(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error) {
+	key, fileKeyID, err := kp.readKey()
+	if err != nil {
+		return nil, err
+	}
+	if keyID != fileKeyID {
+		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "the backup was encrypted with key %s, but the keyfile %v holds key %s", keyID, keyfilePath, fileKeyID)
+	}
+	aead, err := newKeyfileAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrappedKey) < aead.NonceSize() {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "invalid wrapped data key")
+	}
+	nonce, ciphertext := wrappedKey[:aead.NonceSize()], wrappedKey[aead.NonceSize():]
+	dataKey, err := aead.Open(nil, nonce, ciphertext, []byte(keyID))
+	if err != nil {
+		return nil, vterrors.Wrap(err, "cannot unwrap the data key")
+	}
+	return dataKey, nil
+}
@@ -44,3 +44,27 @@ func BackupHandleToProto(bh backupstorage.BackupHandle) *mysqlctlpb.BackupInfo {
 
 	return bi
 }
+
+// AddBackupManifestDetails sets the fields of a BackupInfo proto that are read
+// from the MANIFEST of the backup. A backup with a MANIFEST is complete, and
+// it is valid or invalid once it was verified.
+func AddBackupManifestDetails(bi *mysqlctlpb.BackupInfo, manifest *mysqlctl.BackupManifest) {
+	bi.Engine = manifest.BackupMethod
+	bi.Status = mysqlctlpb.BackupInfo_COMPLETE
+
+	if manifest.Verification == nil {
+		return
+	}
+	bi.Verification = &mysqlctlpb.BackupInfo_Verification{
+		Success: manifest.Verification.Success,
+		Error:   manifest.Verification.Error,
+	}
+	if verifiedTime, err := mysqlctl.ParseRFC3339(manifest.Verification.VerifiedTime); err == nil {
+		bi.Verification.Time = protoutil.TimeToProto(verifiedTime)
+	}
+	if manifest.Verification.Success {
+		bi.Status = mysqlctlpb.BackupInfo_VALID
+	} else {
+		bi.Status = mysqlctlpb.BackupInfo_INVALID
+	}
+}
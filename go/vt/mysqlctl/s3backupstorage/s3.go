@@ -436,6 +436,12 @@ func (bs *S3BackupStorage) StartBackup(ctx context.Context, dir, name string) (b
 	}, nil
 }
 
+// UpdateBackup is part of the backupstorage.BackupUpdater interface. The objects
+// written to the backup replace the objects of the same name.
+func (bs *S3BackupStorage) UpdateBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	return bs.StartBackup(ctx, dir, name)
+}
+
 // RemoveBackup is part of the backupstorage.BackupStorage interface.
 func (bs *S3BackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
 	log.Infof("RemoveBackup: [s3] dir: %v, name: %v, bucket: %v", dir, name, bucket)
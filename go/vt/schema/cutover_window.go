@@ -0,0 +1,181 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+var cutOverWindowDays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// ParseCutOverWindow parses a cut-over window such as "mon-fri 02:00-05:00",
+// "sat,sun 00:00-06:00" or "22:00-02:00". The window opens every day when
+// the days are omitted.
+func ParseCutOverWindow(s string) (*topodatapb.CutOverWindowPolicy_Window, error) {
+	fields := strings.Fields(s)
+	window := &topodatapb.CutOverWindowPolicy_Window{}
+	switch len(fields) {
+	case 1:
+	case 2:
+		days, err := parseCutOverWindowDays(fields[0])
+		if err != nil {
+			return nil, err
+		}
+		window.Days = days
+	default:
+		return nil, fmt.Errorf("invalid cut-over window %q: expected [days] HH:MM-HH:MM", s)
+	}
+	start, end, ok := strings.Cut(fields[len(fields)-1], "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid cut-over window %q: expected [days] HH:MM-HH:MM", s)
+	}
+	window.Start = start
+	window.End = end
+	if err := validateCutOverWindow(window); err != nil {
+		return nil, err
+	}
+	return window, nil
+}
+
+// parseCutOverWindowDays parses a comma separated list of days and day ranges,
+// e.g. "mon-fri" or "mon,wed,fri".
+func parseCutOverWindowDays(s string) ([]string, error) {
+	var days []string
+	for _, part := range strings.Split(strings.ToLower(s), ",") {
+		first, last, isRange := strings.Cut(part, "-")
+		from := slices.Index(cutOverWindowDays, first)
+		if from < 0 {
+			return nil, fmt.Errorf("invalid cut-over window day %q", first)
+		}
+		if !isRange {
+			days = append(days, first)
+			continue
+		}
+		to := slices.Index(cutOverWindowDays, last)
+		if to < 0 {
+			return nil, fmt.Errorf("invalid cut-over window day %q", last)
+		}
+		for i := from; ; i = (i + 1) % len(cutOverWindowDays) {
+			days = append(days, cutOverWindowDays[i])
+			if i == to {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// parseTimeOfDay returns the minutes since midnight of a HH:MM time of the day.
+func parseTimeOfDay(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of the day %q: expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func validateCutOverWindow(window *topodatapb.CutOverWindowPolicy_Window) error {
+	for _, day := range window.Days {
+		if !slices.Contains(cutOverWindowDays, day) {
+			return fmt.Errorf("invalid cut-over window day %q", day)
+		}
+	}
+	start, err := parseTimeOfDay(window.Start)
+	if err != nil {
+		return err
+	}
+	end, err := parseTimeOfDay(window.End)
+	if err != nil {
+		return err
+	}
+	if start == end {
+		return fmt.Errorf("cut-over window %s-%s is empty", window.Start, window.End)
+	}
+	return nil
+}
+
+// ValidateCutOverWindowPolicy returns an error if the windows, the time zone
+// or the load thresholds of the policy are invalid. A nil policy is valid.
+func ValidateCutOverWindowPolicy(policy *topodatapb.CutOverWindowPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	for _, window := range policy.Windows {
+		if err := validateCutOverWindow(window); err != nil {
+			return err
+		}
+	}
+	if _, err := time.LoadLocation(policy.TimeZone); err != nil {
+		return fmt.Errorf("invalid cut-over window time zone %q: %w", policy.TimeZone, err)
+	}
+	if policy.MaxReplicationLagSeconds < 0 {
+		return fmt.Errorf("invalid max replication lag %v: must not be negative", policy.MaxReplicationLagSeconds)
+	}
+	if policy.MaxThreadsRunning < 0 {
+		return fmt.Errorf("invalid max threads running %v: must not be negative", policy.MaxThreadsRunning)
+	}
+	return nil
+}
+
+// IsCutOverWindowOpen returns true if the policy lets migrations cut over at
+// the given time, i.e. if it has no windows or if one of its windows is open.
+func IsCutOverWindowOpen(policy *topodatapb.CutOverWindowPolicy, now time.Time) (bool, error) {
+	if len(policy.GetWindows()) == 0 {
+		return true, nil
+	}
+	location, err := time.LoadLocation(policy.TimeZone)
+	if err != nil {
+		return false, fmt.Errorf("invalid cut-over window time zone %q: %w", policy.TimeZone, err)
+	}
+	now = now.In(location)
+	minute := now.Hour()*60 + now.Minute()
+	today := cutOverWindowDays[now.Weekday()]
+	yesterday := cutOverWindowDays[(now.Weekday()+6)%7]
+	opensOn := func(window *topodatapb.CutOverWindowPolicy_Window, day string) bool {
+		return len(window.Days) == 0 || slices.Contains(window.Days, day)
+	}
+	for _, window := range policy.Windows {
+		start, err := parseTimeOfDay(window.Start)
+		if err != nil {
+			return false, err
+		}
+		end, err := parseTimeOfDay(window.End)
+		if err != nil {
+			return false, err
+		}
+		if start < end {
+			if minute >= start && minute < end && opensOn(window, today) {
+				return true, nil
+			}
+			continue
+		}
+		// The window spans midnight: it is open after it starts on one of its
+		// days, and until it ends on the next day.
+		if minute >= start && opensOn(window, today) {
+			return true, nil
+		}
+		if minute < end && opensOn(window, yesterday) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
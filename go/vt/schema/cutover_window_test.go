@@ -0,0 +1,227 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestParseCutOverWindow(t *testing.T) {
+	tt := []struct {
+		s         string
+		expect    *topodatapb.CutOverWindowPolicy_Window
+		expectErr bool
+	}{
+		{
+			s:      "02:00-05:00",
+			expect: &topodatapb.CutOverWindowPolicy_Window{Start: "02:00", End: "05:00"},
+		},
+		{
+			s:      "mon-fri 02:00-05:00",
+			expect: &topodatapb.CutOverWindowPolicy_Window{Days: []string{"mon", "tue", "wed", "thu", "fri"}, Start: "02:00", End: "05:00"},
+		},
+		{
+			s:      "fri-mon 22:00-02:00",
+			expect: &topodatapb.CutOverWindowPolicy_Window{Days: []string{"fri", "sat", "sun", "mon"}, Start: "22:00", End: "02:00"},
+		},
+		{
+			s:      "Sat,sun 00:00-06:00",
+			expect: &topodatapb.CutOverWindowPolicy_Window{Days: []string{"sat", "sun"}, Start: "00:00", End: "06:00"},
+		},
+		{
+			s:         "",
+			expectErr: true,
+		},
+		{
+			s:         "mon-fri 02:00",
+			expectErr: true,
+		},
+		{
+			s:         "monday 02:00-05:00",
+			expectErr: true,
+		},
+		{
+			s:         "mon 02:00-25:00",
+			expectErr: true,
+		},
+		{
+			s:         "mon 02:00-02:00",
+			expectErr: true,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.s, func(t *testing.T) {
+			window, err := ParseCutOverWindow(tc.s)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expect.Days, window.Days)
+			assert.Equal(t, tc.expect.Start, window.Start)
+			assert.Equal(t, tc.expect.End, window.End)
+		})
+	}
+}
+
+func TestValidateCutOverWindowPolicy(t *testing.T) {
+	tt := []struct {
+		name      string
+		policy    *topodatapb.CutOverWindowPolicy
+		expectErr bool
+	}{
+		{
+			name: "nil policy",
+		},
+		{
+			name: "valid policy",
+			policy: &topodatapb.CutOverWindowPolicy{
+				Windows:                  []*topodatapb.CutOverWindowPolicy_Window{{Days: []string{"mon"}, Start: "02:00", End: "05:00"}},
+				TimeZone:                 "Europe/Berlin",
+				MaxReplicationLagSeconds: 5,
+				MaxThreadsRunning:        50,
+			},
+		},
+		{
+			name: "invalid day",
+			policy: &topodatapb.CutOverWindowPolicy{
+				Windows: []*topodatapb.CutOverWindowPolicy_Window{{Days: []string{"monday"}, Start: "02:00", End: "05:00"}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "invalid time zone",
+			policy: &topodatapb.CutOverWindowPolicy{
+				TimeZone: "Mars/Olympus_Mons",
+			},
+			expectErr: true,
+		},
+		{
+			name: "negative lag",
+			policy: &topodatapb.CutOverWindowPolicy{
+				MaxReplicationLagSeconds: -1,
+			},
+			expectErr: true,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateCutOverWindowPolicy(tc.policy)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestIsCutOverWindowOpen(t *testing.T) {
+	weekdays := &topodatapb.CutOverWindowPolicy{
+		Windows: []*topodatapb.CutOverWindowPolicy_Window{{Days: []string{"mon", "tue", "wed", "thu", "fri"}, Start: "02:00", End: "05:00"}},
+	}
+	overnight := &topodatapb.CutOverWindowPolicy{
+		Windows: []*topodatapb.CutOverWindowPolicy_Window{{Days: []string{"fri"}, Start: "22:00", End: "02:00"}},
+	}
+	berlin := &topodatapb.CutOverWindowPolicy{
+		Windows:  []*topodatapb.CutOverWindowPolicy_Window{{Start: "02:00", End: "05:00"}},
+		TimeZone: "Europe/Berlin",
+	}
+	tt := []struct {
+		name   string
+		policy *topodatapb.CutOverWindowPolicy
+		now    string
+		expect bool
+	}{
+		{
+			name:   "no policy",
+			now:    "2025-01-06T12:00:00Z",
+			expect: true,
+		},
+		{
+			name:   "no windows",
+			policy: &topodatapb.CutOverWindowPolicy{MaxThreadsRunning: 10},
+			now:    "2025-01-06T12:00:00Z",
+			expect: true,
+		},
+		{
+			name:   "weekday inside",
+			policy: weekdays,
+			now:    "2025-01-06T03:00:00Z", // Monday
+			expect: true,
+		},
+		{
+			name:   "weekday at start",
+			policy: weekdays,
+			now:    "2025-01-06T02:00:00Z",
+			expect: true,
+		},
+		{
+			name:   "weekday at end",
+			policy: weekdays,
+			now:    "2025-01-06T05:00:00Z",
+		},
+		{
+			name:   "weekend",
+			policy: weekdays,
+			now:    "2025-01-05T03:00:00Z", // Sunday
+		},
+		{
+			name:   "overnight before midnight",
+			policy: overnight,
+			now:    "2025-01-10T23:00:00Z", // Friday
+			expect: true,
+		},
+		{
+			name:   "overnight after midnight",
+			policy: overnight,
+			now:    "2025-01-11T01:00:00Z", // Saturday
+			expect: true,
+		},
+		{
+			name:   "overnight on another day",
+			policy: overnight,
+			now:    "2025-01-09T23:00:00Z", // Thursday
+		},
+		{
+			name:   "time zone inside",
+			policy: berlin,
+			now:    "2025-01-06T02:30:00Z", // 03:30 in Berlin
+			expect: true,
+		},
+		{
+			name:   "time zone outside",
+			policy: berlin,
+			now:    "2025-01-06T04:30:00Z", // 05:30 in Berlin
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			now, err := time.Parse(time.RFC3339, tc.now)
+			require.NoError(t, err)
+			open, err := IsCutOverWindowOpen(tc.policy, now)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expect, open)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"path"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// This file provides the utility methods to save / retrieve the
+// evacuations of the cells in the global topology server.
+
+func pathForCellEvacuation(cell string) string {
+	return path.Join(CellEvacuationsPath, cell, CellEvacuationFile)
+}
+
+// GetCellEvacuation returns the last evacuation of the given cell.
+// It returns a NoNode error if the cell was never evacuated.
+func (ts *Server) GetCellEvacuation(ctx context.Context, cell string) (*topodatapb.CellEvacuation, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	contents, _, err := ts.globalCell.Get(ctx, pathForCellEvacuation(cell))
+	if err != nil {
+		return nil, err
+	}
+
+	// Unpack the contents.
+	evacuation := &topodatapb.CellEvacuation{}
+	if err := evacuation.UnmarshalVT(contents); err != nil {
+		return nil, err
+	}
+	return evacuation, nil
+}
+
+// UpdateCellEvacuation reads the evacuation of the given cell, calls
+// update on it, and saves it back if update does not return an error.
+// The evacuation passed to update is empty if the cell was never
+// evacuated. If update returns NoUpdateNeeded, nothing is saved. The
+// saved evacuation is returned.
+func (ts *Server) UpdateCellEvacuation(ctx context.Context, cell string, update func(*topodatapb.CellEvacuation) error) (*topodatapb.CellEvacuation, error) {
+	filePath := pathForCellEvacuation(cell)
+	for {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		evacuation := &topodatapb.CellEvacuation{}
+
+		// Read the file, unpack the contents.
+		contents, version, err := ts.globalCell.Get(ctx, filePath)
+		switch {
+		case err == nil:
+			if err := evacuation.UnmarshalVT(contents); err != nil {
+				return nil, err
+			}
+		case IsErrType(err, NoNode):
+			// Nothing to do.
+		default:
+			return nil, err
+		}
+
+		// Call update method.
+		if err = update(evacuation); err != nil {
+			if IsErrType(err, NoUpdateNeeded) {
+				return evacuation, nil
+			}
+			return nil, err
+		}
+
+		// Pack and save.
+		contents, err = evacuation.MarshalVT()
+		if err != nil {
+			return nil, err
+		}
+		if _, err = ts.globalCell.Update(ctx, filePath, contents, version); !IsErrType(err, BadVersion) {
+			// This includes the 'err=nil' case.
+			if err != nil {
+				return nil, err
+			}
+			return evacuation, nil
+		}
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"path"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// GetExternalMysqlDir returns node path containing external mysqls
+func GetExternalMysqlDir() string {
+	return path.Join(ExternalClustersFile, ExternalClusterMysql)
+}
+
+// GetExternalMysqlPath returns node path containing the named external mysql
+func GetExternalMysqlPath(name string) string {
+	return path.Join(GetExternalMysqlDir(), name)
+}
+
+// CreateExternalMysql creates a topo record for the passed external mysql
+func (ts *Server) CreateExternalMysql(ctx context.Context, name string, value *topodatapb.ExternalMysql) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	data, err := value.MarshalVT()
+	if err != nil {
+		return err
+	}
+	_, err = ts.globalCell.Create(ctx, GetExternalMysqlPath(name), data)
+	return err
+}
+
+// GetExternalMysql returns the topo record for the named external mysql,
+// or nil if there is none.
+func (ts *Server) GetExternalMysql(ctx context.Context, name string) (*topodatapb.ExternalMysql, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	data, _, err := ts.globalCell.Get(ctx, GetExternalMysqlPath(name))
+	switch {
+	case IsErrType(err, NoNode):
+		return nil, nil
+	case err == nil:
+	default:
+		return nil, err
+	}
+	em := &topodatapb.ExternalMysql{}
+	if err = em.UnmarshalVT(data); err != nil {
+		return nil, vterrors.Wrap(err, "bad external mysql data")
+	}
+	return em, nil
+}
+
+// DeleteExternalMysql deletes the topo record for the named external mysql
+func (ts *Server) DeleteExternalMysql(ctx context.Context, name string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return ts.globalCell.Delete(ctx, GetExternalMysqlPath(name), nil)
+}
@@ -84,6 +84,7 @@ const (
 	ShardRoutingRulesFile  = "ShardRoutingRules"
 	CommonRoutingRulesFile = "Rules"
 	MirrorRulesFile        = "MirrorRules"
+	CellEvacuationFile     = "CellEvacuation"
 )
 
 // Path for all object types.
@@ -95,9 +96,11 @@ const (
 	TabletsPath              = "tablets"
 	MetadataPath             = "metadata"
 	ExternalClusterVitess    = "vitess"
+	ExternalClusterMysql     = "mysql"
 	RoutingRulesPath         = "routing_rules"
 	KeyspaceRoutingRulesPath = "keyspace"
 	NamedLocksPath           = "internal/named_locks"
+	CellEvacuationsPath      = "cell_evacuations"
 )
 
 // Factory is a factory interface to create Conn objects.
@@ -23,6 +23,8 @@ import (
 	"sync"
 	"time"
 
+	"google.golang.org/protobuf/proto"
+
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
@@ -159,5 +161,17 @@ func KeyspaceEquality(left, right *topodatapb.Keyspace) bool {
 		return false
 	}
 
+	if !proto.Equal(left.CustomDurabilityPolicy, right.CustomDurabilityPolicy) {
+		return false
+	}
+
+	if !proto.Equal(left.ErrantGtidRemediationPolicy, right.ErrantGtidRemediationPolicy) {
+		return false
+	}
+
+	if !proto.Equal(left.CutOverWindowPolicy, right.CutOverWindowPolicy) {
+		return false
+	}
+
 	return left.DurabilityPolicy == right.DurabilityPolicy
 }
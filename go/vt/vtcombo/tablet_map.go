@@ -972,6 +972,10 @@ func (itmc *internalTabletManagerClient) ReadVReplicationWorkflow(context.Contex
 	return nil, fmt.Errorf("not implemented in vtcombo")
 }
 
+func (itmc *internalTabletManagerClient) ExecuteFetchOnExternalMysql(context.Context, *topodatapb.Tablet, *tabletmanagerdatapb.ExecuteFetchOnExternalMysqlRequest) (*tabletmanagerdatapb.ExecuteFetchOnExternalMysqlResponse, error) {
+	return nil, fmt.Errorf("not implemented in vtcombo")
+}
+
 func (itmc *internalTabletManagerClient) ValidateVReplicationPermissions(context.Context, *topodatapb.Tablet, *tabletmanagerdatapb.ValidateVReplicationPermissionsRequest) (*tabletmanagerdatapb.ValidateVReplicationPermissionsResponse, error) {
 	return nil, fmt.Errorf("not implemented in vtcombo")
 }
@@ -1037,6 +1041,14 @@ func (itmc *internalTabletManagerClient) PromoteReplica(context.Context, *topoda
 	return "", fmt.Errorf("not implemented in vtcombo")
 }
 
+func (itmc *internalTabletManagerClient) InspectBinlogTransactions(context.Context, *topodatapb.Tablet, string) (*tabletmanagerdatapb.InspectBinlogTransactionsResponse, error) {
+	return nil, fmt.Errorf("not implemented in vtcombo")
+}
+
+func (itmc *internalTabletManagerClient) InjectEmptyTransactions(context.Context, *topodatapb.Tablet, string) error {
+	return fmt.Errorf("not implemented in vtcombo")
+}
+
 func (itmc *internalTabletManagerClient) Backup(context.Context, *topodatapb.Tablet, *tabletmanagerdatapb.BackupRequest) (logutil.EventStream, error) {
 	return nil, fmt.Errorf("not implemented in vtcombo")
 }
@@ -47,6 +47,15 @@ func (client *gRPCVtctldClient) AddCellsAlias(ctx context.Context, in *vtctldata
 	return client.c.AddCellsAlias(ctx, in, opts...)
 }
 
+// ApplyDesiredSchema is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ApplyDesiredSchema(ctx context.Context, in *vtctldatapb.ApplyDesiredSchemaRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyDesiredSchemaResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ApplyDesiredSchema(ctx, in, opts...)
+}
+
 // ApplyKeyspaceRoutingRules is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) ApplyKeyspaceRoutingRules(ctx context.Context, in *vtctldatapb.ApplyKeyspaceRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyKeyspaceRoutingRulesResponse, error) {
 	if client.c == nil {
@@ -119,6 +128,51 @@ func (client *gRPCVtctldClient) CancelSchemaMigration(ctx context.Context, in *v
 	return client.c.CancelSchemaMigration(ctx, in, opts...)
 }
 
+// ChangePrimaryVindexCancel is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ChangePrimaryVindexCancel(ctx context.Context, in *vtctldatapb.ChangePrimaryVindexCancelRequest, opts ...grpc.CallOption) (*vtctldatapb.ChangePrimaryVindexCancelResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ChangePrimaryVindexCancel(ctx, in, opts...)
+}
+
+// ChangePrimaryVindexComplete is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ChangePrimaryVindexComplete(ctx context.Context, in *vtctldatapb.ChangePrimaryVindexCompleteRequest, opts ...grpc.CallOption) (*vtctldatapb.ChangePrimaryVindexCompleteResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ChangePrimaryVindexComplete(ctx, in, opts...)
+}
+
+// ChangePrimaryVindexCreate is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ChangePrimaryVindexCreate(ctx context.Context, in *vtctldatapb.ChangePrimaryVindexCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.ChangePrimaryVindexCreateResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ChangePrimaryVindexCreate(ctx, in, opts...)
+}
+
+// ChangePrimaryVindexReverseTraffic is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ChangePrimaryVindexReverseTraffic(ctx context.Context, in *vtctldatapb.ChangePrimaryVindexReverseTrafficRequest, opts ...grpc.CallOption) (*vtctldatapb.ChangePrimaryVindexReverseTrafficResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ChangePrimaryVindexReverseTraffic(ctx, in, opts...)
+}
+
+// ChangePrimaryVindexSwitchTraffic is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ChangePrimaryVindexSwitchTraffic(ctx context.Context, in *vtctldatapb.ChangePrimaryVindexSwitchTrafficRequest, opts ...grpc.CallOption) (*vtctldatapb.ChangePrimaryVindexSwitchTrafficResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ChangePrimaryVindexSwitchTraffic(ctx, in, opts...)
+}
+
 // ChangeTabletTags is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) ChangeTabletTags(ctx context.Context, in *vtctldatapb.ChangeTabletTagsRequest, opts ...grpc.CallOption) (*vtctldatapb.ChangeTabletTagsResponse, error) {
 	if client.c == nil {
@@ -263,6 +317,24 @@ func (client *gRPCVtctldClient) EmergencyReparentShard(ctx context.Context, in *
 	return client.c.EmergencyReparentShard(ctx, in, opts...)
 }
 
+// EnforceBackupRetentionPolicy is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) EnforceBackupRetentionPolicy(ctx context.Context, in *vtctldatapb.EnforceBackupRetentionPolicyRequest, opts ...grpc.CallOption) (*vtctldatapb.EnforceBackupRetentionPolicyResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.EnforceBackupRetentionPolicy(ctx, in, opts...)
+}
+
+// EvacuateCell is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) EvacuateCell(ctx context.Context, in *vtctldatapb.EvacuateCellRequest, opts ...grpc.CallOption) (*vtctldatapb.EvacuateCellResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.EvacuateCell(ctx, in, opts...)
+}
+
 // ExecuteFetchAsApp is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) ExecuteFetchAsApp(ctx context.Context, in *vtctldatapb.ExecuteFetchAsAppRequest, opts ...grpc.CallOption) (*vtctldatapb.ExecuteFetchAsAppResponse, error) {
 	if client.c == nil {
@@ -578,6 +650,33 @@ func (client *gRPCVtctldClient) GetWorkflows(ctx context.Context, in *vtctldatap
 	return client.c.GetWorkflows(ctx, in, opts...)
 }
 
+// ImportCancel is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ImportCancel(ctx context.Context, in *vtctldatapb.ImportCancelRequest, opts ...grpc.CallOption) (*vtctldatapb.ImportCancelResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ImportCancel(ctx, in, opts...)
+}
+
+// ImportComplete is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ImportComplete(ctx context.Context, in *vtctldatapb.ImportCompleteRequest, opts ...grpc.CallOption) (*vtctldatapb.ImportCompleteResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ImportComplete(ctx, in, opts...)
+}
+
+// ImportCreate is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ImportCreate(ctx context.Context, in *vtctldatapb.ImportCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.ImportCreateResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ImportCreate(ctx, in, opts...)
+}
+
 // InitShardPrimary is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) InitShardPrimary(ctx context.Context, in *vtctldatapb.InitShardPrimaryRequest, opts ...grpc.CallOption) (*vtctldatapb.InitShardPrimaryResponse, error) {
 	if client.c == nil {
@@ -857,6 +956,24 @@ func (client *gRPCVtctldClient) RunHealthCheck(ctx context.Context, in *vtctldat
 	return client.c.RunHealthCheck(ctx, in, opts...)
 }
 
+// SetKeyspaceBackupRetentionPolicy is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) SetKeyspaceBackupRetentionPolicy(ctx context.Context, in *vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest, opts ...grpc.CallOption) (*vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.SetKeyspaceBackupRetentionPolicy(ctx, in, opts...)
+}
+
+// SetKeyspaceCutOverWindowPolicy is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) SetKeyspaceCutOverWindowPolicy(ctx context.Context, in *vtctldatapb.SetKeyspaceCutOverWindowPolicyRequest, opts ...grpc.CallOption) (*vtctldatapb.SetKeyspaceCutOverWindowPolicyResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.SetKeyspaceCutOverWindowPolicy(ctx, in, opts...)
+}
+
 // SetKeyspaceDurabilityPolicy is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) SetKeyspaceDurabilityPolicy(ctx context.Context, in *vtctldatapb.SetKeyspaceDurabilityPolicyRequest, opts ...grpc.CallOption) (*vtctldatapb.SetKeyspaceDurabilityPolicyResponse, error) {
 	if client.c == nil {
@@ -866,6 +983,15 @@ func (client *gRPCVtctldClient) SetKeyspaceDurabilityPolicy(ctx context.Context,
 	return client.c.SetKeyspaceDurabilityPolicy(ctx, in, opts...)
 }
 
+// SetKeyspaceErrantGtidRemediationPolicy is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) SetKeyspaceErrantGtidRemediationPolicy(ctx context.Context, in *vtctldatapb.SetKeyspaceErrantGtidRemediationPolicyRequest, opts ...grpc.CallOption) (*vtctldatapb.SetKeyspaceErrantGtidRemediationPolicyResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.SetKeyspaceErrantGtidRemediationPolicy(ctx, in, opts...)
+}
+
 // SetShardIsPrimaryServing is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) SetShardIsPrimaryServing(ctx context.Context, in *vtctldatapb.SetShardIsPrimaryServingRequest, opts ...grpc.CallOption) (*vtctldatapb.SetShardIsPrimaryServingResponse, error) {
 	if client.c == nil {
@@ -1028,6 +1154,15 @@ func (client *gRPCVtctldClient) VDiffDelete(ctx context.Context, in *vtctldatapb
 	return client.c.VDiffDelete(ctx, in, opts...)
 }
 
+// VDiffRepair is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) VDiffRepair(ctx context.Context, in *vtctldatapb.VDiffRepairRequest, opts ...grpc.CallOption) (*vtctldatapb.VDiffRepairResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.VDiffRepair(ctx, in, opts...)
+}
+
 // VDiffResume is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) VDiffResume(ctx context.Context, in *vtctldatapb.VDiffResumeRequest, opts ...grpc.CallOption) (*vtctldatapb.VDiffResumeResponse, error) {
 	if client.c == nil {
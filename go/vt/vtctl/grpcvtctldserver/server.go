@@ -26,6 +26,7 @@ import (
 	"net/http"
 	"path/filepath"
 	"runtime/debug"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -65,6 +66,7 @@ import (
 	vtctlservicepb "vitess.io/vitess/go/vt/proto/vtctlservice"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/schemadiff"
 	"vitess.io/vitess/go/vt/schemamanager"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/topo"
@@ -170,6 +172,128 @@ func (s *VtctldServer) AddCellsAlias(ctx context.Context, req *vtctldatapb.AddCe
 	return &vtctldatapb.AddCellsAliasResponse{}, nil
 }
 
+// ApplyDesiredSchema is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ApplyDesiredSchema(ctx context.Context, req *vtctldatapb.ApplyDesiredSchemaRequest) (resp *vtctldatapb.ApplyDesiredSchemaResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ApplyDesiredSchema")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("ddl_strategy", req.DdlStrategy)
+	span.Annotate("plan_only", req.PlanOnly)
+
+	if len(req.Sql) == 0 {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the desired schema must not be empty")
+		return nil, err
+	}
+
+	ddlStrategySetting, err := schema.ParseDDLStrategy(req.DdlStrategy)
+	if err != nil {
+		err = vterrors.Wrapf(err, "invalid DdlStrategy: %s", req.DdlStrategy)
+		return nil, err
+	}
+	if ddlStrategySetting.Strategy.IsDirect() {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the desired schema must be applied with an online DDL strategy, not %s", ddlStrategySetting.Strategy)
+		return nil, err
+	}
+	if ddlStrategySetting.IsDeclarative() {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the desired schema is already declarative, and cannot be applied with --declarative")
+		return nil, err
+	}
+
+	// The statements depend on each other, so concurrent migrations must
+	// complete in the order in which they are submitted.
+	ddlStrategy := req.DdlStrategy
+	if ddlStrategySetting.IsAllowConcurrent() && !ddlStrategySetting.IsInOrderCompletion() {
+		ddlStrategy += " --in-order-completion"
+	}
+
+	statements, err := s.diffDesiredSchema(ctx, req.Keyspace, req.Sql)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &vtctldatapb.ApplyDesiredSchemaResponse{
+		Statements: statements,
+	}
+	if req.PlanOnly || len(statements) == 0 {
+		return resp, nil
+	}
+
+	applySchemaResp, err := s.ApplySchema(ctx, &vtctldatapb.ApplySchemaRequest{
+		Keyspace:         req.Keyspace,
+		Sql:              statements,
+		DdlStrategy:      ddlStrategy,
+		MigrationContext: req.MigrationContext,
+		CallerId:         req.CallerId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp.UuidList = applySchemaResp.UuidList
+
+	return resp, nil
+}
+
+// diffDesiredSchema returns the statements that turn the schema of the given
+// keyspace into the desired schema, in an order in which they can be applied.
+// The schema of the keyspace is read from the primary of its first shard.
+func (s *VtctldServer) diffDesiredSchema(ctx context.Context, keyspace string, desiredSQL []string) ([]string, error) {
+	shards, err := s.ts.GetShardNames(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	if len(shards) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "keyspace %s has no shards", keyspace)
+	}
+	sort.Strings(shards)
+
+	si, err := s.ts.GetShard(ctx, keyspace, shards[0])
+	if err != nil {
+		return nil, err
+	}
+	if !si.HasPrimary() {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no primary in shard %v/%v", keyspace, shards[0])
+	}
+	sd, err := schematools.GetSchema(ctx, s.ts, s.tmc, si.PrimaryAlias, &tabletmanagerdatapb.GetSchemaRequest{
+		IncludeViews:    true,
+		TableSchemaOnly: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	env := schemadiff.NewEnv(s.ws.Environment(), s.ws.Environment().CollationEnv().DefaultConnectionCharset())
+	currentSQL := make([]string, 0, len(sd.TableDefinitions))
+	for _, td := range sd.TableDefinitions {
+		currentSQL = append(currentSQL, td.Schema)
+	}
+	currentSchema, err := schemadiff.NewSchemaFromQueries(env, currentSQL)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot load the schema of keyspace %s", keyspace)
+	}
+	desiredSchema, err := schemadiff.NewSchemaFromQueries(env, desiredSQL)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "invalid desired schema")
+	}
+
+	schemaDiff, err := schemadiff.DiffSchemas(env, currentSchema, desiredSchema, schemadiff.EmptyDiffHints())
+	if err != nil {
+		return nil, err
+	}
+	diffs, err := schemaDiff.OrderedDiffs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statements := make([]string, 0, len(diffs))
+	for _, diff := range diffs {
+		statements = append(statements, diff.CanonicalStatementString())
+	}
+	return statements, nil
+}
+
 // ApplyRoutingRules is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) ApplyRoutingRules(ctx context.Context, req *vtctldatapb.ApplyRoutingRulesRequest) (resp *vtctldatapb.ApplyRoutingRulesResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ApplyRoutingRules")
@@ -601,6 +725,80 @@ func (s *VtctldServer) CancelSchemaMigration(ctx context.Context, req *vtctldata
 	return resp, nil
 }
 
+// ChangePrimaryVindexCancel is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ChangePrimaryVindexCancel(ctx context.Context, req *vtctldatapb.ChangePrimaryVindexCancelRequest) (resp *vtctldatapb.ChangePrimaryVindexCancelResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ChangePrimaryVindexCancel")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("keep_data", req.KeepData)
+
+	resp, err = s.ws.ChangePrimaryVindexCancel(ctx, req)
+	return resp, err
+}
+
+// ChangePrimaryVindexComplete is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ChangePrimaryVindexComplete(ctx context.Context, req *vtctldatapb.ChangePrimaryVindexCompleteRequest) (resp *vtctldatapb.ChangePrimaryVindexCompleteResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ChangePrimaryVindexComplete")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("keep_data", req.KeepData)
+
+	resp, err = s.ws.ChangePrimaryVindexComplete(ctx, req)
+	return resp, err
+}
+
+// ChangePrimaryVindexCreate is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ChangePrimaryVindexCreate(ctx context.Context, req *vtctldatapb.ChangePrimaryVindexCreateRequest) (resp *vtctldatapb.ChangePrimaryVindexCreateResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ChangePrimaryVindexCreate")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("table", req.Table)
+
+	resp, err = s.ws.ChangePrimaryVindexCreate(ctx, req)
+	return resp, err
+}
+
+// ChangePrimaryVindexReverseTraffic is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ChangePrimaryVindexReverseTraffic(ctx context.Context, req *vtctldatapb.ChangePrimaryVindexReverseTrafficRequest) (resp *vtctldatapb.ChangePrimaryVindexReverseTrafficResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ChangePrimaryVindexReverseTraffic")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("workflow", req.Workflow)
+
+	resp, err = s.ws.ChangePrimaryVindexReverseTraffic(ctx, req)
+	return resp, err
+}
+
+// ChangePrimaryVindexSwitchTraffic is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ChangePrimaryVindexSwitchTraffic(ctx context.Context, req *vtctldatapb.ChangePrimaryVindexSwitchTrafficRequest) (resp *vtctldatapb.ChangePrimaryVindexSwitchTrafficResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ChangePrimaryVindexSwitchTraffic")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("skip_vdiff", req.SkipVdiff)
+
+	resp, err = s.ws.ChangePrimaryVindexSwitchTraffic(ctx, req)
+	return resp, err
+}
+
 // ChangeTabletTags is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) ChangeTabletTags(ctx context.Context, req *vtctldatapb.ChangeTabletTagsRequest) (resp *vtctldatapb.ChangeTabletTagsResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ChangeTabletTags")
@@ -676,12 +874,8 @@ func (s *VtctldServer) ChangeTabletType(ctx context.Context, req *vtctldatapb.Ch
 		return nil, err
 	}
 
-	durabilityName, err := s.ts.GetKeyspaceDurability(ctx, tablet.Keyspace)
-	if err != nil {
-		return nil, err
-	}
-	log.Infof("Getting a new durability policy for %v", durabilityName)
-	durability, err := policy.GetDurabilityPolicy(durabilityName)
+	log.Infof("Getting a new durability policy for keyspace %v", tablet.Keyspace)
+	durability, err := policy.GetKeyspaceDurabilityPolicy(ctx, s.ts, tablet.Keyspace)
 	if err != nil {
 		return nil, err
 	}
@@ -1344,6 +1538,353 @@ func (s *VtctldServer) EmergencyReparentShard(ctx context.Context, req *vtctldat
 	return resp, err
 }
 
+// EnforceBackupRetentionPolicy is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) EnforceBackupRetentionPolicy(ctx context.Context, req *vtctldatapb.EnforceBackupRetentionPolicyRequest) (resp *vtctldatapb.EnforceBackupRetentionPolicyResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.EnforceBackupRetentionPolicy")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("dry_run", req.DryRun)
+
+	ki, err := s.ts.GetKeyspace(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+	if ki.BackupRetentionPolicy == nil {
+		err = vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "keyspace %s has no backup retention policy", req.Keyspace)
+		return nil, err
+	}
+
+	shards := []string{req.Shard}
+	if req.Shard == "" {
+		shards, err = s.ts.GetShardNames(ctx, req.Keyspace)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer bs.Close()
+
+	resp = &vtctldatapb.EnforceBackupRetentionPolicyResponse{
+		Shards: make(map[string]*vtctldatapb.EnforceBackupRetentionPolicyResponse_ShardBackups, len(shards)),
+	}
+	now := time.Now()
+	for _, shard := range shards {
+		kept, removed, err := mysqlctl.EnforceBackupRetentionPolicy(ctx, logutil.NewConsoleLogger(), bs, mysqlctl.GetBackupDir(req.Keyspace, shard), ki.BackupRetentionPolicy, now, req.DryRun)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "cannot enforce the backup retention policy of %v/%v", req.Keyspace, shard)
+		}
+		if !req.DryRun {
+			if _, err := mysqlctl.PruneBackupChunks(ctx, logutil.NewConsoleLogger(), bs, mysqlctl.GetBackupDir(req.Keyspace, shard)); err != nil {
+				return nil, vterrors.Wrapf(err, "cannot prune the backup chunks of %v/%v", req.Keyspace, shard)
+			}
+		}
+		resp.Shards[shard] = &vtctldatapb.EnforceBackupRetentionPolicyResponse_ShardBackups{
+			Kept:    kept,
+			Removed: removed,
+		}
+	}
+
+	return resp, nil
+}
+
+// EvacuateCell is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) EvacuateCell(ctx context.Context, req *vtctldatapb.EvacuateCellRequest) (resp *vtctldatapb.EvacuateCellResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.EvacuateCell")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("cell", req.Cell)
+	span.Annotate("keyspaces", strings.Join(req.Keyspaces, ","))
+	span.Annotate("concurrency", req.Concurrency)
+	span.Annotate("resume", req.Resume)
+	span.Annotate("abort", req.Abort)
+
+	if req.Cell == "" {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "cell must not be empty")
+		return nil, err
+	}
+	if req.Resume && req.Abort {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "cannot both resume and abort the evacuation of cell %s", req.Cell)
+		return nil, err
+	}
+
+	if req.Abort {
+		evacuation, err := s.ts.UpdateCellEvacuation(ctx, req.Cell, func(evacuation *topodatapb.CellEvacuation) error {
+			if evacuation.State != topodatapb.CellEvacuation_RUNNING {
+				return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cell %s is not being evacuated", req.Cell)
+			}
+			evacuation.State = topodatapb.CellEvacuation_ABORTED
+			evacuation.FinishedAt = protoutil.TimeToProto(time.Now())
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &vtctldatapb.EvacuateCellResponse{Evacuation: evacuation}, nil
+	}
+
+	pauseBetweenBatches, _, err := protoutil.DurationFromProto(req.PauseBetweenBatches)
+	if err != nil {
+		return nil, err
+	}
+	maxReplicationLag, _, err := protoutil.DurationFromProto(req.MaxReplicationLag)
+	if err != nil {
+		return nil, err
+	}
+	waitReplicasTimeout, ok, err := protoutil.DurationFromProto(req.WaitReplicasTimeout)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		waitReplicasTimeout = time.Second * 30
+	}
+	concurrency := int(req.Concurrency)
+	if concurrency == 0 {
+		concurrency = 1
+	}
+
+	if req.Resume {
+		_, err = s.ts.UpdateCellEvacuation(ctx, req.Cell, func(evacuation *topodatapb.CellEvacuation) error {
+			switch evacuation.State {
+			case topodatapb.CellEvacuation_UNKNOWN:
+				return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cell %s was never evacuated", req.Cell)
+			case topodatapb.CellEvacuation_RUNNING:
+				return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cell %s is already being evacuated, abort the evacuation first if it is not running anymore", req.Cell)
+			case topodatapb.CellEvacuation_COMPLETE:
+				return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the evacuation of cell %s is already complete", req.Cell)
+			}
+			for _, shard := range evacuation.Shards {
+				if shard.State == topodatapb.CellEvacuation_Shard_FAILED {
+					shard.State = topodatapb.CellEvacuation_Shard_PENDING
+					shard.Error = ""
+				}
+			}
+			evacuation.State = topodatapb.CellEvacuation_RUNNING
+			evacuation.FinishedAt = nil
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if _, err = s.ts.GetCellInfo(ctx, req.Cell, false); err != nil {
+			return nil, vterrors.Wrapf(err, "cannot get cell %s", req.Cell)
+		}
+		shards, err := s.findCellEvacuationShards(ctx, req.Cell, req.Keyspaces)
+		if err != nil {
+			return nil, err
+		}
+		_, err = s.ts.UpdateCellEvacuation(ctx, req.Cell, func(evacuation *topodatapb.CellEvacuation) error {
+			if evacuation.State == topodatapb.CellEvacuation_RUNNING {
+				return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cell %s is already being evacuated", req.Cell)
+			}
+			evacuation.Cell = req.Cell
+			evacuation.State = topodatapb.CellEvacuation_RUNNING
+			evacuation.Shards = shards
+			evacuation.StartedAt = protoutil.TimeToProto(time.Now())
+			evacuation.FinishedAt = nil
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	opts := reparentutil.PlannedReparentOptions{
+		WaitReplicasTimeout:     waitReplicasTimeout,
+		TolerableReplLag:        maxReplicationLag,
+		AllowCrossCellPromotion: true,
+		AvoidPrimaryCells:       []string{req.Cell},
+	}
+	evacuation, err := s.runCellEvacuation(ctx, req.Cell, concurrency, pauseBetweenBatches, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &vtctldatapb.EvacuateCellResponse{Evacuation: evacuation}, nil
+}
+
+// findCellEvacuationShards returns the shards whose primary is in the given
+// cell, with the shards of the given keyspaces first, in that order, and then
+// the shards of the other keyspaces, in alphabetical order.
+func (s *VtctldServer) findCellEvacuationShards(ctx context.Context, cell string, keyspaces []string) ([]*topodatapb.CellEvacuation_Shard, error) {
+	allKeyspaces, err := s.ts.GetKeyspaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(allKeyspaces)
+
+	orderedKeyspaces := make([]string, 0, len(allKeyspaces))
+	for _, keyspace := range keyspaces {
+		if !slices.Contains(allKeyspaces, keyspace) {
+			return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "keyspace %s does not exist", keyspace)
+		}
+		if !slices.Contains(orderedKeyspaces, keyspace) {
+			orderedKeyspaces = append(orderedKeyspaces, keyspace)
+		}
+	}
+	for _, keyspace := range allKeyspaces {
+		if !slices.Contains(orderedKeyspaces, keyspace) {
+			orderedKeyspaces = append(orderedKeyspaces, keyspace)
+		}
+	}
+
+	var shards []*topodatapb.CellEvacuation_Shard
+	for _, keyspace := range orderedKeyspaces {
+		shardInfos, err := s.ts.FindAllShardsInKeyspace(ctx, keyspace, nil)
+		if err != nil {
+			return nil, err
+		}
+		shardNames := make([]string, 0, len(shardInfos))
+		for shardName := range shardInfos {
+			shardNames = append(shardNames, shardName)
+		}
+		sort.Strings(shardNames)
+
+		for _, shardName := range shardNames {
+			si := shardInfos[shardName]
+			if si.PrimaryAlias == nil || si.PrimaryAlias.Cell != cell {
+				continue
+			}
+			shards = append(shards, &topodatapb.CellEvacuation_Shard{
+				Keyspace:        keyspace,
+				Shard:           si.ShardName(),
+				State:           topodatapb.CellEvacuation_Shard_PENDING,
+				PreviousPrimary: si.PrimaryAlias,
+			})
+		}
+	}
+	return shards, nil
+}
+
+// runCellEvacuation reparents the pending shards of the evacuation of the
+// given cell, in batches of at most concurrency shards of the same keyspace,
+// until there are no pending shards left or the evacuation is aborted.
+func (s *VtctldServer) runCellEvacuation(ctx context.Context, cell string, concurrency int, pauseBetweenBatches time.Duration, opts reparentutil.PlannedReparentOptions) (*topodatapb.CellEvacuation, error) {
+	// There is no pause before the first batch.
+	paused := true
+	for {
+		// The evacuation is read again before each batch, to find out if it was aborted.
+		evacuation, err := s.ts.GetCellEvacuation(ctx, cell)
+		if err != nil {
+			return nil, err
+		}
+		if evacuation.State != topodatapb.CellEvacuation_RUNNING {
+			return evacuation, nil
+		}
+
+		batch := nextCellEvacuationBatch(evacuation, concurrency)
+		if len(batch) == 0 {
+			return s.ts.UpdateCellEvacuation(ctx, cell, func(evacuation *topodatapb.CellEvacuation) error {
+				if evacuation.State != topodatapb.CellEvacuation_RUNNING {
+					return topo.NewError(topo.NoUpdateNeeded, cell)
+				}
+				evacuation.State = topodatapb.CellEvacuation_COMPLETE
+				for _, shard := range evacuation.Shards {
+					if shard.State == topodatapb.CellEvacuation_Shard_FAILED {
+						evacuation.State = topodatapb.CellEvacuation_FAILED
+					}
+				}
+				evacuation.FinishedAt = protoutil.TimeToProto(time.Now())
+				return nil
+			})
+		}
+
+		if !paused {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(pauseBetweenBatches):
+			}
+			paused = true
+			continue
+		}
+
+		wg := sync.WaitGroup{}
+		for _, shard := range batch {
+			wg.Add(1)
+			go func(shard *topodatapb.CellEvacuation_Shard) {
+				defer wg.Done()
+				s.evacuateShard(ctx, cell, shard, opts)
+			}(shard)
+		}
+		wg.Wait()
+		paused = pauseBetweenBatches == 0
+	}
+}
+
+// nextCellEvacuationBatch returns the next pending shards of the given
+// evacuation, which are at most concurrency shards of the same keyspace.
+func nextCellEvacuationBatch(evacuation *topodatapb.CellEvacuation, concurrency int) []*topodatapb.CellEvacuation_Shard {
+	var batch []*topodatapb.CellEvacuation_Shard
+	for _, shard := range evacuation.Shards {
+		if shard.State != topodatapb.CellEvacuation_Shard_PENDING {
+			continue
+		}
+		if len(batch) > 0 && shard.Keyspace != batch[0].Keyspace {
+			break
+		}
+		batch = append(batch, shard)
+		if len(batch) == concurrency {
+			break
+		}
+	}
+	return batch
+}
+
+// evacuateShard moves the primary of the given shard out of the given cell,
+// and records the result in the evacuation of the cell.
+func (s *VtctldServer) evacuateShard(ctx context.Context, cell string, shard *topodatapb.CellEvacuation_Shard, opts reparentutil.PlannedReparentOptions) {
+	result := &topodatapb.CellEvacuation_Shard{
+		Keyspace:        shard.Keyspace,
+		Shard:           shard.Shard,
+		State:           topodatapb.CellEvacuation_Shard_EVACUATED,
+		PreviousPrimary: shard.PreviousPrimary,
+	}
+
+	si, err := s.ts.GetShard(ctx, shard.Keyspace, shard.Shard)
+	switch {
+	case err != nil:
+		result.State = topodatapb.CellEvacuation_Shard_FAILED
+		result.Error = err.Error()
+	case si.PrimaryAlias == nil || si.PrimaryAlias.Cell != cell:
+		// The primary was moved out of the cell since the evacuation started.
+		result.NewPrimary = si.PrimaryAlias
+	default:
+		opts.AvoidPrimaryAlias = si.PrimaryAlias
+		opts.ExpectedPrimaryAlias = si.PrimaryAlias
+		ev, err := reparentutil.NewPlannedReparenter(s.ts, s.tmc, logutil.NewConsoleLogger()).ReparentShard(ctx, shard.Keyspace, shard.Shard, opts)
+		if err != nil {
+			result.State = topodatapb.CellEvacuation_Shard_FAILED
+			result.Error = err.Error()
+			break
+		}
+		if ev != nil && ev.NewPrimary != nil {
+			result.NewPrimary = ev.NewPrimary.Alias
+		}
+	}
+
+	_, err = s.ts.UpdateCellEvacuation(ctx, cell, func(evacuation *topodatapb.CellEvacuation) error {
+		for i, evacuationShard := range evacuation.Shards {
+			if evacuationShard.Keyspace == shard.Keyspace && evacuationShard.Shard == shard.Shard {
+				evacuation.Shards[i] = result
+				return nil
+			}
+		}
+		return topo.NewError(topo.NoUpdateNeeded, cell)
+	})
+	if err != nil {
+		log.Errorf("Failed to record the evacuation of %v/%v from cell %v: %v", shard.Keyspace, shard.Shard, cell, err)
+	}
+}
+
 // ExecuteFetchAsApp is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) ExecuteFetchAsApp(ctx context.Context, req *vtctldatapb.ExecuteFetchAsAppRequest) (resp *vtctldatapb.ExecuteFetchAsAppResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ExecuteFetchAsApp")
@@ -1554,10 +2095,14 @@ func (s *VtctldServer) GetBackups(ctx context.Context, req *vtctldatapb.GetBacku
 		bi.Keyspace = req.Keyspace
 		bi.Shard = req.Shard
 
-		if req.Detailed {
-			if i >= backupsToSkipDetails { // nolint:staticcheck
-				// (TODO:@ajm188) Update backupengine/backupstorage implementations
-				// to get Status info for backups.
+		if req.Detailed && i >= backupsToSkipDetails {
+			manifest, err := mysqlctl.GetBackupManifest(ctx, bh)
+			if err != nil {
+				// The MANIFEST is written last, so a backup without a readable
+				// MANIFEST is either in progress or was interrupted.
+				bi.Status = mysqlctlpb.BackupInfo_INCOMPLETE
+			} else {
+				mysqlctlproto.AddBackupManifestDetails(bi, manifest)
 			}
 		}
 
@@ -2732,6 +3277,50 @@ func (s *VtctldServer) GetWorkflows(ctx context.Context, req *vtctldatapb.GetWor
 	return resp, err
 }
 
+// ImportCancel is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ImportCancel(ctx context.Context, req *vtctldatapb.ImportCancelRequest) (resp *vtctldatapb.ImportCancelResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ImportCancel")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.TargetKeyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("keep_data", req.KeepData)
+
+	resp, err = s.ws.ImportCancel(ctx, req)
+	return resp, err
+}
+
+// ImportComplete is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ImportComplete(ctx context.Context, req *vtctldatapb.ImportCompleteRequest) (resp *vtctldatapb.ImportCompleteResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ImportComplete")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.TargetKeyspace)
+	span.Annotate("workflow", req.Workflow)
+
+	resp, err = s.ws.ImportComplete(ctx, req)
+	return resp, err
+}
+
+// ImportCreate is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ImportCreate(ctx context.Context, req *vtctldatapb.ImportCreateRequest) (resp *vtctldatapb.ImportCreateResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ImportCreate")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.TargetKeyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("external_mysql", req.ExternalMysql)
+
+	resp, err = s.ws.ImportCreate(ctx, req)
+	return resp, err
+}
+
 // InitShardPrimary is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) InitShardPrimary(ctx context.Context, req *vtctldatapb.InitShardPrimaryRequest) (resp *vtctldatapb.InitShardPrimaryResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.InitShardPrimary")
@@ -2822,12 +3411,8 @@ func (s *VtctldServer) InitShardPrimaryLocked(
 	}
 	ev.ShardInfo = *shardInfo
 
-	durabilityName, err := s.ts.GetKeyspaceDurability(ctx, req.Keyspace)
-	if err != nil {
-		return err
-	}
-	log.Infof("Getting a new durability policy for %v", durabilityName)
-	durability, err := policy.GetDurabilityPolicy(durabilityName)
+	log.Infof("Getting a new durability policy for keyspace %v", req.Keyspace)
+	durability, err := policy.GetKeyspaceDurabilityPolicy(ctx, s.ts, req.Keyspace)
 	if err != nil {
 		return err
 	}
@@ -3690,12 +4275,8 @@ func (s *VtctldServer) ReparentTablet(ctx context.Context, req *vtctldatapb.Repa
 		return nil, err
 	}
 
-	durabilityName, err := s.ts.GetKeyspaceDurability(ctx, tablet.Keyspace)
-	if err != nil {
-		return nil, err
-	}
-	log.Infof("Getting a new durability policy for %v", durabilityName)
-	durability, err := policy.GetDurabilityPolicy(durabilityName)
+	log.Infof("Getting a new durability policy for keyspace %v", tablet.Keyspace)
+	durability, err := policy.GetKeyspaceDurabilityPolicy(ctx, s.ts, tablet.Keyspace)
 	if err != nil {
 		return nil, err
 	}
@@ -3862,6 +4443,81 @@ func (s *VtctldServer) RunHealthCheck(ctx context.Context, req *vtctldatapb.RunH
 	return &vtctldatapb.RunHealthCheckResponse{}, nil
 }
 
+// SetKeyspaceBackupRetentionPolicy is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) SetKeyspaceBackupRetentionPolicy(ctx context.Context, req *vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest) (resp *vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.SetKeyspaceBackupRetentionPolicy")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("backup_retention_policy", req.BackupRetentionPolicy.String())
+
+	ctx, unlock, lockErr := s.ts.LockKeyspace(ctx, req.Keyspace, "SetKeyspaceBackupRetentionPolicy")
+	if lockErr != nil {
+		err = lockErr
+		return nil, err
+	}
+
+	defer unlock(&err)
+
+	ki, err := s.ts.GetKeyspace(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	ki.BackupRetentionPolicy = req.BackupRetentionPolicy
+
+	err = s.ts.UpdateKeyspace(ctx, ki)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse{
+		Keyspace: ki.Keyspace,
+	}, nil
+}
+
+// SetKeyspaceCutOverWindowPolicy is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) SetKeyspaceCutOverWindowPolicy(ctx context.Context, req *vtctldatapb.SetKeyspaceCutOverWindowPolicyRequest) (resp *vtctldatapb.SetKeyspaceCutOverWindowPolicyResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.SetKeyspaceCutOverWindowPolicy")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("cut_over_window_policy", req.CutOverWindowPolicy.String())
+
+	if err = schema.ValidateCutOverWindowPolicy(req.CutOverWindowPolicy); err != nil {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid CutOverWindowPolicy: %v", err)
+		return nil, err
+	}
+
+	ctx, unlock, lockErr := s.ts.LockKeyspace(ctx, req.Keyspace, "SetKeyspaceCutOverWindowPolicy")
+	if lockErr != nil {
+		err = lockErr
+		return nil, err
+	}
+
+	defer unlock(&err)
+
+	ki, err := s.ts.GetKeyspace(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	ki.CutOverWindowPolicy = req.CutOverWindowPolicy
+
+	err = s.ts.UpdateKeyspace(ctx, ki)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.SetKeyspaceCutOverWindowPolicyResponse{
+		Keyspace: ki.Keyspace,
+	}, nil
+}
+
 // SetKeyspaceDurabilityPolicy is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) SetKeyspaceDurabilityPolicy(ctx context.Context, req *vtctldatapb.SetKeyspaceDurabilityPolicyRequest) (resp *vtctldatapb.SetKeyspaceDurabilityPolicyResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.SetKeyspaceDurabilityPolicy")
@@ -3885,13 +4541,22 @@ func (s *VtctldServer) SetKeyspaceDurabilityPolicy(ctx context.Context, req *vtc
 		return nil, err
 	}
 
-	policyValid := policy.CheckDurabilityPolicyExists(req.DurabilityPolicy)
-	if !policyValid {
+	switch {
+	case req.DurabilityPolicy == policy.DurabilityCustom:
+		if err = policy.ValidateCustomDurabilityPolicy(req.CustomDurabilityPolicy); err != nil {
+			err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid custom durability policy: %v", err)
+			return nil, err
+		}
+	case req.CustomDurabilityPolicy != nil:
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a custom durability policy can only be set with the %v durability policy", policy.DurabilityCustom)
+		return nil, err
+	case !policy.CheckDurabilityPolicyExists(req.DurabilityPolicy):
 		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "durability policy <%v> is not a valid policy. Please register it as a policy first", req.DurabilityPolicy)
 		return nil, err
 	}
 
 	ki.DurabilityPolicy = req.DurabilityPolicy
+	ki.CustomDurabilityPolicy = req.CustomDurabilityPolicy
 
 	err = s.ts.UpdateKeyspace(ctx, ki)
 	if err != nil {
@@ -3903,6 +4568,41 @@ func (s *VtctldServer) SetKeyspaceDurabilityPolicy(ctx context.Context, req *vtc
 	}, nil
 }
 
+// SetKeyspaceErrantGtidRemediationPolicy is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) SetKeyspaceErrantGtidRemediationPolicy(ctx context.Context, req *vtctldatapb.SetKeyspaceErrantGtidRemediationPolicyRequest) (resp *vtctldatapb.SetKeyspaceErrantGtidRemediationPolicyResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.SetKeyspaceErrantGtidRemediationPolicy")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("errant_gtid_remediation_policy", req.ErrantGtidRemediationPolicy.String())
+
+	ctx, unlock, lockErr := s.ts.LockKeyspace(ctx, req.Keyspace, "SetKeyspaceErrantGtidRemediationPolicy")
+	if lockErr != nil {
+		err = lockErr
+		return nil, err
+	}
+
+	defer unlock(&err)
+
+	ki, err := s.ts.GetKeyspace(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	ki.ErrantGtidRemediationPolicy = req.ErrantGtidRemediationPolicy
+
+	err = s.ts.UpdateKeyspace(ctx, ki)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.SetKeyspaceErrantGtidRemediationPolicyResponse{
+		Keyspace: ki.Keyspace,
+	}, nil
+}
+
 // SetShardIsPrimaryServing is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) SetShardIsPrimaryServing(ctx context.Context, req *vtctldatapb.SetShardIsPrimaryServingRequest) (resp *vtctldatapb.SetShardIsPrimaryServingResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.SetShardIsPrimaryServing")
@@ -4401,12 +5101,8 @@ func (s *VtctldServer) StartReplication(ctx context.Context, req *vtctldatapb.St
 		return nil, err
 	}
 
-	durabilityName, err := s.ts.GetKeyspaceDurability(ctx, tablet.Keyspace)
-	if err != nil {
-		return nil, err
-	}
-	log.Infof("Getting a new durability policy for %v", durabilityName)
-	durability, err := policy.GetDurabilityPolicy(durabilityName)
+	log.Infof("Getting a new durability policy for keyspace %v", tablet.Keyspace)
+	durability, err := policy.GetKeyspaceDurabilityPolicy(ctx, s.ts, tablet.Keyspace)
 	if err != nil {
 		return nil, err
 	}
@@ -4506,12 +5202,8 @@ func (s *VtctldServer) TabletExternallyReparented(ctx context.Context, req *vtct
 
 	event.DispatchUpdate(ev, "starting external reparent")
 
-	durabilityName, err := s.ts.GetKeyspaceDurability(ctx, tablet.Keyspace)
-	if err != nil {
-		return nil, err
-	}
-	log.Infof("Getting a new durability policy for %v", durabilityName)
-	durability, err := policy.GetDurabilityPolicy(durabilityName)
+	log.Infof("Getting a new durability policy for keyspace %v", tablet.Keyspace)
+	durability, err := policy.GetKeyspaceDurabilityPolicy(ctx, s.ts, tablet.Keyspace)
 	if err != nil {
 		return nil, err
 	}
@@ -5496,6 +6188,23 @@ func (s *VtctldServer) VDiffDelete(ctx context.Context, req *vtctldatapb.VDiffDe
 	return resp, err
 }
 
+// VDiffRepair is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) VDiffRepair(ctx context.Context, req *vtctldatapb.VDiffRepairRequest) (resp *vtctldatapb.VDiffRepairResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.VDiffRepair")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.TargetKeyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("uuid", req.Uuid)
+	span.Annotate("tables", req.Tables)
+	span.Annotate("apply", req.Apply)
+
+	resp, err = s.ws.VDiffRepair(ctx, req)
+	return resp, err
+}
+
 // VDiffResume is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) VDiffResume(ctx context.Context, req *vtctldatapb.VDiffResumeRequest) (resp *vtctldatapb.VDiffResumeResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.VDiffResume")
@@ -26,6 +26,7 @@ import (
 	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -45,9 +46,12 @@ import (
 	"vitess.io/vitess/go/test/utils"
 	"vitess.io/vitess/go/vt/callerid"
 	hk "vitess.io/vitess/go/vt/hook"
+	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
 	"vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/proto/vttime"
+	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
@@ -240,6 +244,190 @@ func TestAddCellsAlias(t *testing.T) {
 	}
 }
 
+func TestApplyDesiredSchema(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	tmc := &testutil.TabletManagerClient{
+		GetSchemaResults: map[string]struct {
+			Schema *tabletmanagerdatapb.SchemaDefinition
+			Error  error
+		}{
+			"zone1-0000000100": {
+				Schema: &tabletmanagerdatapb.SchemaDefinition{
+					TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+						{
+							Name:   "t1",
+							Schema: "CREATE TABLE `t1` (`id` int NOT NULL, PRIMARY KEY (`id`))",
+							Type:   "BASE TABLE",
+						},
+						{
+							Name:   "t2",
+							Schema: "CREATE TABLE `t2` (`id` int NOT NULL, PRIMARY KEY (`id`))",
+							Type:   "BASE TABLE",
+						},
+						{
+							Name:   "v1",
+							Schema: "CREATE VIEW `v1` AS SELECT `id` FROM `t2`",
+							Type:   "VIEW",
+						},
+					},
+				},
+			},
+		},
+	}
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+		AlsoSetShardPrimary: true,
+	}, &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+		Type:     topodatapb.TabletType_PRIMARY,
+		Keyspace: "testkeyspace",
+		Shard:    "-",
+	})
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	tests := []struct {
+		name        string
+		req         *vtctldatapb.ApplyDesiredSchemaRequest
+		expected    []string
+		expectedErr string
+	}{
+		{
+			name: "plan",
+			req: &vtctldatapb.ApplyDesiredSchemaRequest{
+				Keyspace: "testkeyspace",
+				Sql: []string{
+					"CREATE TABLE t1 (id int NOT NULL, name varchar(64), PRIMARY KEY (id))",
+					"CREATE TABLE t2 (id int NOT NULL, PRIMARY KEY (id))",
+					"CREATE TABLE t3 (id int NOT NULL, PRIMARY KEY (id))",
+				},
+				DdlStrategy: "vitess",
+				PlanOnly:    true,
+			},
+			expected: []string{
+				"DROP VIEW `v1`",
+				"ALTER TABLE `t1` ADD COLUMN `name` varchar(64)",
+				"CREATE TABLE `t3` (\n\t`id` int NOT NULL,\n\tPRIMARY KEY (`id`)\n)",
+			},
+		},
+		{
+			name: "no changes",
+			req: &vtctldatapb.ApplyDesiredSchemaRequest{
+				Keyspace: "testkeyspace",
+				Sql: []string{
+					"CREATE TABLE t1 (id int NOT NULL, PRIMARY KEY (id))",
+					"CREATE TABLE t2 (id int NOT NULL, PRIMARY KEY (id))",
+					"CREATE VIEW v1 AS SELECT id FROM t2",
+				},
+				DdlStrategy: "vitess",
+			},
+			expected: []string{},
+		},
+		{
+			name: "direct strategy",
+			req: &vtctldatapb.ApplyDesiredSchemaRequest{
+				Keyspace:    "testkeyspace",
+				Sql:         []string{"CREATE TABLE t1 (id int NOT NULL, PRIMARY KEY (id))"},
+				DdlStrategy: "direct",
+			},
+			expectedErr: "the desired schema must be applied with an online DDL strategy, not direct",
+		},
+		{
+			name: "not a CREATE statement",
+			req: &vtctldatapb.ApplyDesiredSchemaRequest{
+				Keyspace:    "testkeyspace",
+				Sql:         []string{"ALTER TABLE t1 ADD COLUMN name varchar(64)"},
+				DdlStrategy: "vitess",
+			},
+			expectedErr: "invalid desired schema",
+		},
+		{
+			name: "empty desired schema",
+			req: &vtctldatapb.ApplyDesiredSchemaRequest{
+				Keyspace:    "testkeyspace",
+				DdlStrategy: "vitess",
+			},
+			expectedErr: "the desired schema must not be empty",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := vtctld.ApplyDesiredSchema(ctx, tt.req)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resp.Statements)
+			assert.Empty(t, resp.UuidList)
+		})
+	}
+
+	t.Run("submit", func(t *testing.T) {
+		tmc.PrimaryPositionResults = map[string]struct {
+			Position string
+			Error    error
+		}{
+			"zone1-0000000100": {},
+		}
+		recordingTMC := &recordQueriesTMClient{TabletManagerClient: tmc}
+		vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, recordingTMC, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+			return NewVtctldServer(vtenv.NewTestEnv(), ts)
+		})
+		resp, err := vtctld.ApplyDesiredSchema(ctx, &vtctldatapb.ApplyDesiredSchemaRequest{
+			Keyspace: "testkeyspace",
+			Sql: []string{
+				"CREATE TABLE t1 (id int NOT NULL, name varchar(64), PRIMARY KEY (id))",
+				"CREATE TABLE t2 (id int NOT NULL, PRIMARY KEY (id))",
+				"CREATE TABLE t3 (id int NOT NULL, PRIMARY KEY (id))",
+			},
+			DdlStrategy: "vitess --allow-concurrent",
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.UuidList, 3)
+
+		// The migrations are submitted in the order of the statements, and
+		// complete in that order.
+		queries := recordingTMC.getQueries()
+		require.Len(t, queries, 3)
+		for i, table := range []string{"v1", "t1", "t3"} {
+			stmt, err := sqlparser.NewTestParser().Parse(queries[i])
+			require.NoError(t, err)
+			onlineDDL, err := schema.OnlineDDLFromCommentedStatement(stmt)
+			require.NoError(t, err)
+			assert.Equal(t, table, onlineDDL.Table)
+			assert.Equal(t, resp.UuidList[i], onlineDDL.UUID)
+			assert.Equal(t, schema.DDLStrategyVitess, onlineDDL.Strategy)
+			assert.Equal(t, "--allow-concurrent --in-order-completion", onlineDDL.Options)
+		}
+	})
+}
+
+// recordQueriesTMClient wraps the testutil TabletManagerClient and records
+// the queries of its ExecuteQuery calls.
+type recordQueriesTMClient struct {
+	*testutil.TabletManagerClient
+
+	mu      sync.Mutex
+	queries []string
+}
+
+// ExecuteQuery implements the tmclient.TabletManagerClient interface for recordQueriesTMClient.
+func (tc *recordQueriesTMClient) ExecuteQuery(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.ExecuteQueryRequest) (*querypb.QueryResult, error) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.queries = append(tc.queries, string(req.Query))
+	return &querypb.QueryResult{}, nil
+}
+
+func (tc *recordQueriesTMClient) getQueries() []string {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return slices.Clone(tc.queries)
+}
+
 func TestApplyRoutingRules(t *testing.T) {
 	t.Parallel()
 
@@ -4890,6 +5078,262 @@ func TestEmergencyReparentShard(t *testing.T) {
 	}
 }
 
+func TestEnforceBackupRetentionPolicy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	testutil.AddKeyspaces(ctx, t, ts, &vtctldatapb.Keyspace{
+		Name: "ks1",
+		Keyspace: &topodatapb.Keyspace{
+			BackupRetentionPolicy: &topodatapb.BackupRetentionPolicy{KeepFull: 2},
+		},
+	}, &vtctldatapb.Keyspace{
+		Name:     "ks2",
+		Keyspace: &topodatapb.Keyspace{},
+	})
+	testutil.AddShards(ctx, t, ts, &vtctldatapb.Shard{Keyspace: "ks1", Name: "-80"}, &vtctldatapb.Shard{Keyspace: "ks1", Name: "80-"})
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	manifest := func(daysAgo int) string {
+		return fmt.Sprintf(`{"BackupMethod": "builtin", "BackupTime": %q}`, mysqlctl.FormatRFC3339(time.Now().Add(-time.Duration(daysAgo)*24*time.Hour)))
+	}
+	setup := func() {
+		testutil.BackupStorage.Backups = map[string][]string{
+			"ks1/-80": {"backup1", "backup2", "backup3", "backup4"},
+			"ks1/80-": {"backup1"},
+		}
+		testutil.BackupStorage.Manifests = map[string]string{
+			"ks1/-80/backup1": manifest(3),
+			"ks1/-80/backup2": manifest(2),
+			"ks1/-80/backup3": manifest(1),
+			"ks1/80-/backup1": manifest(1),
+		}
+	}
+	defer func() {
+		testutil.BackupStorage.Backups = map[string][]string{}
+		testutil.BackupStorage.Manifests = nil
+	}()
+
+	t.Run("dry run", func(t *testing.T) {
+		setup()
+		resp, err := vtctld.EnforceBackupRetentionPolicy(ctx, &vtctldatapb.EnforceBackupRetentionPolicyRequest{
+			Keyspace: "ks1",
+			DryRun:   true,
+		})
+		require.NoError(t, err)
+		utils.MustMatch(t, &vtctldatapb.EnforceBackupRetentionPolicyResponse{
+			Shards: map[string]*vtctldatapb.EnforceBackupRetentionPolicyResponse_ShardBackups{
+				// backup4 is incomplete, and is kept.
+				"-80": {Kept: []string{"backup2", "backup3", "backup4"}, Removed: []string{"backup1"}},
+				"80-": {Kept: []string{"backup1"}},
+			},
+		}, resp)
+		assert.Len(t, testutil.BackupStorage.Backups["ks1/-80"], 4)
+	})
+
+	t.Run("single shard", func(t *testing.T) {
+		setup()
+		resp, err := vtctld.EnforceBackupRetentionPolicy(ctx, &vtctldatapb.EnforceBackupRetentionPolicyRequest{
+			Keyspace: "ks1",
+			Shard:    "-80",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"backup1"}, resp.Shards["-80"].Removed)
+		assert.NotContains(t, resp.Shards, "80-")
+		assert.Equal(t, []string{"backup2", "backup3", "backup4"}, testutil.BackupStorage.Backups["ks1/-80"])
+	})
+
+	t.Run("no policy", func(t *testing.T) {
+		setup()
+		_, err := vtctld.EnforceBackupRetentionPolicy(ctx, &vtctldatapb.EnforceBackupRetentionPolicyRequest{
+			Keyspace: "ks2",
+		})
+		assert.ErrorContains(t, err, "keyspace ks2 has no backup retention policy")
+	})
+}
+
+func TestEvacuateCell(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+		AlsoSetShardPrimary:  true,
+		ForceSetShardPrimary: true,
+	}, &topodatapb.Tablet{
+		Alias:                &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+		Type:                 topodatapb.TabletType_PRIMARY,
+		PrimaryTermStartTime: &vttime.Time{Seconds: 100},
+		Keyspace:             "ks1",
+		Shard:                "-",
+	}, &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone2", Uid: 200},
+		Type:     topodatapb.TabletType_REPLICA,
+		Keyspace: "ks1",
+		Shard:    "-",
+	}, &topodatapb.Tablet{
+		// ks2 has no replica outside of zone1, and cannot be evacuated.
+		Alias:                &topodatapb.TabletAlias{Cell: "zone1", Uid: 300},
+		Type:                 topodatapb.TabletType_PRIMARY,
+		PrimaryTermStartTime: &vttime.Time{Seconds: 100},
+		Keyspace:             "ks2",
+		Shard:                "-",
+	}, &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 301},
+		Type:     topodatapb.TabletType_REPLICA,
+		Keyspace: "ks2",
+		Shard:    "-",
+	}, &topodatapb.Tablet{
+		// The primary of ks3 is not in zone1.
+		Alias:                &topodatapb.TabletAlias{Cell: "zone2", Uid: 400},
+		Type:                 topodatapb.TabletType_PRIMARY,
+		PrimaryTermStartTime: &vttime.Time{Seconds: 100},
+		Keyspace:             "ks3",
+		Shard:                "-",
+	})
+
+	tmc := &testutil.TabletManagerClient{
+		DemotePrimaryResults: map[string]struct {
+			Status *replicationdatapb.PrimaryStatus
+			Error  error
+		}{
+			"zone1-0000000100": {
+				Status: &replicationdatapb.PrimaryStatus{
+					Position: "primary-demotion position",
+				},
+			},
+		},
+		GetGlobalStatusVarsResults: map[string]struct {
+			Statuses map[string]string
+			Error    error
+		}{
+			"zone1-0000000100": {
+				Statuses: map[string]string{
+					reparentutil.InnodbBufferPoolsDataVar: "123",
+				},
+			},
+			"zone2-0000000200": {
+				Statuses: map[string]string{
+					reparentutil.InnodbBufferPoolsDataVar: "123",
+				},
+			},
+			"zone1-0000000300": {
+				Statuses: map[string]string{
+					reparentutil.InnodbBufferPoolsDataVar: "123",
+				},
+			},
+			"zone1-0000000301": {
+				Statuses: map[string]string{
+					reparentutil.InnodbBufferPoolsDataVar: "123",
+				},
+			},
+		},
+		PrimaryPositionResults: map[string]struct {
+			Position string
+			Error    error
+		}{
+			"zone1-0000000100": {
+				Position: "doesn't matter",
+			},
+		},
+		PopulateReparentJournalResults: map[string]error{
+			"zone2-0000000200": nil,
+		},
+		PromoteReplicaResults: map[string]struct {
+			Result string
+			Error  error
+		}{
+			"zone2-0000000200": {
+				Result: "promotion position",
+			},
+		},
+		SetReplicationSourceResults: map[string]error{
+			"zone2-0000000200": nil,
+			"zone1-0000000100": nil,
+		},
+		WaitForPositionResults: map[string]map[string]error{
+			"zone2-0000000200": {
+				"primary-demotion position": nil,
+			},
+		},
+	}
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	_, err := vtctld.EvacuateCell(ctx, &vtctldatapb.EvacuateCellRequest{Cell: "zone1", Resume: true})
+	assert.ErrorContains(t, err, "cell zone1 was never evacuated")
+	_, err = vtctld.EvacuateCell(ctx, &vtctldatapb.EvacuateCellRequest{Cell: "zone1", Keyspaces: []string{"nope"}})
+	assert.ErrorContains(t, err, "keyspace nope does not exist")
+
+	// ks2 is evacuated first, and the failure does not stop the evacuation of ks1.
+	resp, err := vtctld.EvacuateCell(ctx, &vtctldatapb.EvacuateCellRequest{
+		Cell:                "zone1",
+		Keyspaces:           []string{"ks2"},
+		WaitReplicasTimeout: protoutil.DurationToProto(time.Millisecond * 10),
+	})
+	require.NoError(t, err)
+	evacuation := resp.Evacuation
+	assert.Equal(t, topodatapb.CellEvacuation_FAILED, evacuation.State)
+	assert.NotNil(t, evacuation.StartedAt)
+	assert.NotNil(t, evacuation.FinishedAt)
+	require.Len(t, evacuation.Shards, 2)
+	assert.Equal(t, "ks2", evacuation.Shards[0].Keyspace)
+	assert.Equal(t, topodatapb.CellEvacuation_Shard_FAILED, evacuation.Shards[0].State)
+	assert.Contains(t, evacuation.Shards[0].Error, "zone1-0000000301 is in a cell to avoid")
+	utils.MustMatch(t, &topodatapb.CellEvacuation_Shard{
+		Keyspace:        "ks1",
+		Shard:           "-",
+		State:           topodatapb.CellEvacuation_Shard_EVACUATED,
+		PreviousPrimary: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+		NewPrimary:      &topodatapb.TabletAlias{Cell: "zone2", Uid: 200},
+	}, evacuation.Shards[1])
+
+	stored, err := ts.GetCellEvacuation(ctx, "zone1")
+	require.NoError(t, err)
+	utils.MustMatch(t, evacuation, stored)
+
+	_, err = vtctld.EvacuateCell(ctx, &vtctldatapb.EvacuateCellRequest{Cell: "zone1", Abort: true})
+	assert.ErrorContains(t, err, "cell zone1 is not being evacuated")
+
+	// Resuming only retries the shards that failed.
+	resp, err = vtctld.EvacuateCell(ctx, &vtctldatapb.EvacuateCellRequest{Cell: "zone1", Resume: true})
+	require.NoError(t, err)
+	assert.Equal(t, topodatapb.CellEvacuation_FAILED, resp.Evacuation.State)
+	assert.Equal(t, topodatapb.CellEvacuation_Shard_FAILED, resp.Evacuation.Shards[0].State)
+	assert.Equal(t, topodatapb.CellEvacuation_Shard_EVACUATED, resp.Evacuation.Shards[1].State)
+
+	// A running evacuation can be aborted, and is not run again until it is resumed.
+	_, err = ts.UpdateCellEvacuation(ctx, "zone1", func(evacuation *topodatapb.CellEvacuation) error {
+		evacuation.State = topodatapb.CellEvacuation_RUNNING
+		return nil
+	})
+	require.NoError(t, err)
+	_, err = vtctld.EvacuateCell(ctx, &vtctldatapb.EvacuateCellRequest{Cell: "zone1"})
+	assert.ErrorContains(t, err, "cell zone1 is already being evacuated")
+	_, err = vtctld.EvacuateCell(ctx, &vtctldatapb.EvacuateCellRequest{Cell: "zone1", Resume: true})
+	assert.ErrorContains(t, err, "cell zone1 is already being evacuated")
+	resp, err = vtctld.EvacuateCell(ctx, &vtctldatapb.EvacuateCellRequest{Cell: "zone1", Abort: true})
+	require.NoError(t, err)
+	assert.Equal(t, topodatapb.CellEvacuation_ABORTED, resp.Evacuation.State)
+
+	// A new evacuation only includes the shards whose primary is still in zone1. The fake tablet manager client does
+	// not update the shard records when it promotes a replica.
+	_, err = ts.UpdateShardFields(ctx, "ks1", "-", func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = &topodatapb.TabletAlias{Cell: "zone2", Uid: 200}
+		return nil
+	})
+	require.NoError(t, err)
+	resp, err = vtctld.EvacuateCell(ctx, &vtctldatapb.EvacuateCellRequest{Cell: "zone1"})
+	require.NoError(t, err)
+	require.Len(t, resp.Evacuation.Shards, 1)
+	assert.Equal(t, "ks2", resp.Evacuation.Shards[0].Keyspace)
+}
+
 func TestExecuteFetchAsApp(t *testing.T) {
 	t.Parallel()
 
@@ -5882,6 +6326,79 @@ func TestGetBackups(t *testing.T) {
 		assert.Less(t, len(limited.Backups), len(unlimited.Backups), "expected limited backups to be less than unlimited")
 		utils.MustMatch(t, limited.Backups[0], unlimited.Backups[len(unlimited.Backups)-1], "expected limiting to keep N most recent")
 	})
+
+	t.Run("detailed", func(t *testing.T) {
+		testutil.BackupStorage.Backups["ks3/-"] = []string{"backup1", "backup2", "backup3", "backup4"}
+		testutil.BackupStorage.Manifests = map[string]string{
+			"ks3/-/backup2": `{"BackupMethod": "builtin"}`,
+			"ks3/-/backup3": `{"BackupMethod": "builtin", "Verification": {"VerifiedTime": "2025-03-21T01:00:00Z", "Success": true}}`,
+			"ks3/-/backup4": `{"BackupMethod": "builtin", "Verification": {"VerifiedTime": "2025-03-21T02:00:00Z", "Success": false, "Error": "table vt_ks3.t is corrupted"}}`,
+		}
+		defer func() { testutil.BackupStorage.Manifests = nil }()
+
+		resp, err := vtctld.GetBackups(ctx, &vtctldatapb.GetBackupsRequest{
+			Keyspace: "ks3",
+			Shard:    "-",
+			Detailed: true,
+		})
+		require.NoError(t, err)
+		expected := &vtctldatapb.GetBackupsResponse{
+			Backups: []*mysqlctlpb.BackupInfo{
+				{
+					Directory: "ks3/-",
+					Name:      "backup1",
+					Keyspace:  "ks3",
+					Shard:     "-",
+					Status:    mysqlctlpb.BackupInfo_INCOMPLETE,
+				},
+				{
+					Directory: "ks3/-",
+					Name:      "backup2",
+					Keyspace:  "ks3",
+					Shard:     "-",
+					Engine:    "builtin",
+					Status:    mysqlctlpb.BackupInfo_COMPLETE,
+				},
+				{
+					Directory: "ks3/-",
+					Name:      "backup3",
+					Keyspace:  "ks3",
+					Shard:     "-",
+					Engine:    "builtin",
+					Status:    mysqlctlpb.BackupInfo_VALID,
+					Verification: &mysqlctlpb.BackupInfo_Verification{
+						Time:    protoutil.TimeToProto(time.Date(2025, time.March, 21, 1, 0, 0, 0, time.UTC)),
+						Success: true,
+					},
+				},
+				{
+					Directory: "ks3/-",
+					Name:      "backup4",
+					Keyspace:  "ks3",
+					Shard:     "-",
+					Engine:    "builtin",
+					Status:    mysqlctlpb.BackupInfo_INVALID,
+					Verification: &mysqlctlpb.BackupInfo_Verification{
+						Time:  protoutil.TimeToProto(time.Date(2025, time.March, 21, 2, 0, 0, 0, time.UTC)),
+						Error: "table vt_ks3.t is corrupted",
+					},
+				},
+			},
+		}
+		utils.MustMatch(t, expected, resp)
+
+		resp, err = vtctld.GetBackups(ctx, &vtctldatapb.GetBackupsRequest{
+			Keyspace:      "ks3",
+			Shard:         "-",
+			Detailed:      true,
+			DetailedLimit: 1,
+		})
+		require.NoError(t, err)
+		for _, bi := range resp.Backups[:3] {
+			assert.Equal(t, mysqlctlpb.BackupInfo_UNKNOWN, bi.Status, "only the most recent backups are detailed")
+		}
+		assert.Equal(t, mysqlctlpb.BackupInfo_INVALID, resp.Backups[3].Status)
+	})
 }
 
 func TestGetKeyspace(t *testing.T) {
@@ -11405,6 +11922,200 @@ func TestRunHealthCheck(t *testing.T) {
 	}
 }
 
+func TestSetKeyspaceBackupRetentionPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		keyspaces   []*vtctldatapb.Keyspace
+		req         *vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest
+		expected    *vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse
+		expectedErr string
+	}{
+		{
+			name: "ok",
+			keyspaces: []*vtctldatapb.Keyspace{
+				{
+					Name:     "ks1",
+					Keyspace: &topodatapb.Keyspace{},
+				},
+			},
+			req: &vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest{
+				Keyspace: "ks1",
+				BackupRetentionPolicy: &topodatapb.BackupRetentionPolicy{
+					KeepFull:                2,
+					KeepDaily:               7,
+					PointInTimeRecoveryDays: 3,
+				},
+			},
+			expected: &vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse{
+				Keyspace: &topodatapb.Keyspace{
+					BackupRetentionPolicy: &topodatapb.BackupRetentionPolicy{
+						KeepFull:                2,
+						KeepDaily:               7,
+						PointInTimeRecoveryDays: 3,
+					},
+				},
+			},
+		},
+		{
+			name: "remove policy",
+			keyspaces: []*vtctldatapb.Keyspace{
+				{
+					Name: "ks1",
+					Keyspace: &topodatapb.Keyspace{
+						BackupRetentionPolicy: &topodatapb.BackupRetentionPolicy{KeepFull: 2},
+					},
+				},
+			},
+			req: &vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest{
+				Keyspace: "ks1",
+			},
+			expected: &vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse{
+				Keyspace: &topodatapb.Keyspace{},
+			},
+		},
+		{
+			name: "keyspace not found",
+			req: &vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest{
+				Keyspace: "ks1",
+			},
+			expectedErr: "node doesn't exist: keyspaces/ks1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddKeyspaces(ctx, t, ts, tt.keyspaces...)
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			resp, err := vtctld.SetKeyspaceBackupRetentionPolicy(ctx, tt.req)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+		})
+	}
+}
+
+func TestSetKeyspaceCutOverWindowPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		keyspaces   []*vtctldatapb.Keyspace
+		req         *vtctldatapb.SetKeyspaceCutOverWindowPolicyRequest
+		expected    *vtctldatapb.SetKeyspaceCutOverWindowPolicyResponse
+		expectedErr string
+	}{
+		{
+			name: "ok",
+			keyspaces: []*vtctldatapb.Keyspace{
+				{
+					Name:     "ks1",
+					Keyspace: &topodatapb.Keyspace{},
+				},
+			},
+			req: &vtctldatapb.SetKeyspaceCutOverWindowPolicyRequest{
+				Keyspace: "ks1",
+				CutOverWindowPolicy: &topodatapb.CutOverWindowPolicy{
+					Windows: []*topodatapb.CutOverWindowPolicy_Window{
+						{Days: []string{"mon", "tue"}, Start: "02:00", End: "05:00"},
+					},
+					MaxReplicationLagSeconds: 5,
+				},
+			},
+			expected: &vtctldatapb.SetKeyspaceCutOverWindowPolicyResponse{
+				Keyspace: &topodatapb.Keyspace{
+					CutOverWindowPolicy: &topodatapb.CutOverWindowPolicy{
+						Windows: []*topodatapb.CutOverWindowPolicy_Window{
+							{Days: []string{"mon", "tue"}, Start: "02:00", End: "05:00"},
+						},
+						MaxReplicationLagSeconds: 5,
+					},
+				},
+			},
+		},
+		{
+			name: "remove policy",
+			keyspaces: []*vtctldatapb.Keyspace{
+				{
+					Name: "ks1",
+					Keyspace: &topodatapb.Keyspace{
+						CutOverWindowPolicy: &topodatapb.CutOverWindowPolicy{MaxThreadsRunning: 10},
+					},
+				},
+			},
+			req: &vtctldatapb.SetKeyspaceCutOverWindowPolicyRequest{
+				Keyspace: "ks1",
+			},
+			expected: &vtctldatapb.SetKeyspaceCutOverWindowPolicyResponse{
+				Keyspace: &topodatapb.Keyspace{},
+			},
+		},
+		{
+			name: "invalid policy",
+			keyspaces: []*vtctldatapb.Keyspace{
+				{
+					Name:     "ks1",
+					Keyspace: &topodatapb.Keyspace{},
+				},
+			},
+			req: &vtctldatapb.SetKeyspaceCutOverWindowPolicyRequest{
+				Keyspace: "ks1",
+				CutOverWindowPolicy: &topodatapb.CutOverWindowPolicy{
+					Windows: []*topodatapb.CutOverWindowPolicy_Window{
+						{Start: "02:00", End: "26:00"},
+					},
+				},
+			},
+			expectedErr: `invalid CutOverWindowPolicy: invalid time of the day "26:00": expected HH:MM`,
+		},
+		{
+			name: "keyspace not found",
+			req: &vtctldatapb.SetKeyspaceCutOverWindowPolicyRequest{
+				Keyspace: "ks1",
+			},
+			expectedErr: "node doesn't exist: keyspaces/ks1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddKeyspaces(ctx, t, ts, tt.keyspaces...)
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			resp, err := vtctld.SetKeyspaceCutOverWindowPolicy(ctx, tt.req)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+		})
+	}
+}
+
 func TestSetKeyspaceDurabilityPolicy(t *testing.T) {
 	t.Parallel()
 
@@ -11458,6 +12169,93 @@ func TestSetKeyspaceDurabilityPolicy(t *testing.T) {
 			},
 			expectedErr: "durability policy <non-existent> is not a valid policy. Please register it as a policy first",
 		},
+		{
+			name: "custom durability policy",
+			keyspaces: []*vtctldatapb.Keyspace{
+				{
+					Name:     "ks1",
+					Keyspace: &topodatapb.Keyspace{},
+				},
+			},
+			req: &vtctldatapb.SetKeyspaceDurabilityPolicyRequest{
+				Keyspace:         "ks1",
+				DurabilityPolicy: policy.DurabilityCustom,
+				CustomDurabilityPolicy: &topodatapb.CustomDurabilityPolicy{
+					PromotionRules: []*topodatapb.CustomDurabilityPolicy_PromotionRule{{
+						Selector: &topodatapb.CustomDurabilityPolicy_TabletSelector{Cells: []string{"zone2"}},
+						Rule:     "must_not",
+					}},
+					SemiSyncAckers:    1,
+					AckerDistinctTags: []string{"az"},
+				},
+			},
+			expected: &vtctldatapb.SetKeyspaceDurabilityPolicyResponse{
+				Keyspace: &topodatapb.Keyspace{
+					DurabilityPolicy: policy.DurabilityCustom,
+					CustomDurabilityPolicy: &topodatapb.CustomDurabilityPolicy{
+						PromotionRules: []*topodatapb.CustomDurabilityPolicy_PromotionRule{{
+							Selector: &topodatapb.CustomDurabilityPolicy_TabletSelector{Cells: []string{"zone2"}},
+							Rule:     "must_not",
+						}},
+						SemiSyncAckers:    1,
+						AckerDistinctTags: []string{"az"},
+					},
+				},
+			},
+		},
+		{
+			name: "replacing a custom durability policy",
+			keyspaces: []*vtctldatapb.Keyspace{
+				{
+					Name: "ks1",
+					Keyspace: &topodatapb.Keyspace{
+						DurabilityPolicy:       policy.DurabilityCustom,
+						CustomDurabilityPolicy: &topodatapb.CustomDurabilityPolicy{SemiSyncAckers: 2},
+					},
+				},
+			},
+			req: &vtctldatapb.SetKeyspaceDurabilityPolicyRequest{
+				Keyspace:         "ks1",
+				DurabilityPolicy: policy.DurabilitySemiSync,
+			},
+			expected: &vtctldatapb.SetKeyspaceDurabilityPolicyResponse{
+				Keyspace: &topodatapb.Keyspace{
+					DurabilityPolicy: policy.DurabilitySemiSync,
+				},
+			},
+		},
+		{
+			name: "invalid custom durability policy",
+			keyspaces: []*vtctldatapb.Keyspace{
+				{
+					Name:     "ks1",
+					Keyspace: &topodatapb.Keyspace{},
+				},
+			},
+			req: &vtctldatapb.SetKeyspaceDurabilityPolicyRequest{
+				Keyspace:         "ks1",
+				DurabilityPolicy: policy.DurabilityCustom,
+				CustomDurabilityPolicy: &topodatapb.CustomDurabilityPolicy{
+					PromotionRules: []*topodatapb.CustomDurabilityPolicy_PromotionRule{{Rule: "always"}},
+				},
+			},
+			expectedErr: "invalid custom durability policy: invalid promotion rule 0: Invalid CandidatePromotionRule: always",
+		},
+		{
+			name: "custom durability policy without the custom durability policy name",
+			keyspaces: []*vtctldatapb.Keyspace{
+				{
+					Name:     "ks1",
+					Keyspace: &topodatapb.Keyspace{},
+				},
+			},
+			req: &vtctldatapb.SetKeyspaceDurabilityPolicyRequest{
+				Keyspace:               "ks1",
+				DurabilityPolicy:       policy.DurabilitySemiSync,
+				CustomDurabilityPolicy: &topodatapb.CustomDurabilityPolicy{SemiSyncAckers: 1},
+			},
+			expectedErr: "a custom durability policy can only be set with the custom durability policy",
+		},
 	}
 
 	for _, tt := range tests {
@@ -11485,6 +12283,91 @@ func TestSetKeyspaceDurabilityPolicy(t *testing.T) {
 	}
 }
 
+func TestSetKeyspaceErrantGtidRemediationPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		keyspaces   []*vtctldatapb.Keyspace
+		req         *vtctldatapb.SetKeyspaceErrantGtidRemediationPolicyRequest
+		expected    *vtctldatapb.SetKeyspaceErrantGtidRemediationPolicyResponse
+		expectedErr string
+	}{
+		{
+			name: "ok",
+			keyspaces: []*vtctldatapb.Keyspace{
+				{
+					Name:     "ks1",
+					Keyspace: &topodatapb.Keyspace{},
+				},
+			},
+			req: &vtctldatapb.SetKeyspaceErrantGtidRemediationPolicyRequest{
+				Keyspace: "ks1",
+				ErrantGtidRemediationPolicy: &topodatapb.ErrantGtidRemediationPolicy{
+					InjectEmptyTransactions: true,
+					MaxErrantTransactions:   10,
+				},
+			},
+			expected: &vtctldatapb.SetKeyspaceErrantGtidRemediationPolicyResponse{
+				Keyspace: &topodatapb.Keyspace{
+					ErrantGtidRemediationPolicy: &topodatapb.ErrantGtidRemediationPolicy{
+						InjectEmptyTransactions: true,
+						MaxErrantTransactions:   10,
+					},
+				},
+			},
+		},
+		{
+			name: "remove policy",
+			keyspaces: []*vtctldatapb.Keyspace{
+				{
+					Name: "ks1",
+					Keyspace: &topodatapb.Keyspace{
+						ErrantGtidRemediationPolicy: &topodatapb.ErrantGtidRemediationPolicy{RestoreFromBackup: true},
+					},
+				},
+			},
+			req: &vtctldatapb.SetKeyspaceErrantGtidRemediationPolicyRequest{
+				Keyspace: "ks1",
+			},
+			expected: &vtctldatapb.SetKeyspaceErrantGtidRemediationPolicyResponse{
+				Keyspace: &topodatapb.Keyspace{},
+			},
+		},
+		{
+			name: "keyspace not found",
+			req: &vtctldatapb.SetKeyspaceErrantGtidRemediationPolicyRequest{
+				Keyspace: "ks1",
+			},
+			expectedErr: "node doesn't exist: keyspaces/ks1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddKeyspaces(ctx, t, ts, tt.keyspaces...)
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			resp, err := vtctld.SetKeyspaceErrantGtidRemediationPolicy(ctx, tt.req)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+		})
+	}
+}
+
 func TestSetShardIsPrimaryServing(t *testing.T) {
 	t.Parallel()
 
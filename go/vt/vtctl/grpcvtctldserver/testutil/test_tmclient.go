@@ -270,6 +270,13 @@ type TabletManagerClient struct {
 	ReadTransactionResult            map[string]*querypb.TransactionMetadata
 	GetTransactionInfoResult         map[string]*tabletmanagerdatapb.GetTransactionInfoResponse
 	// keyed by tablet alias.
+	InjectEmptyTransactionsResults map[string]error
+	// keyed by tablet alias.
+	InspectBinlogTransactionsResults map[string]struct {
+		Response *tabletmanagerdatapb.InspectBinlogTransactionsResponse
+		Error    error
+	}
+	// keyed by tablet alias.
 	InitPrimaryDelays map[string]time.Duration
 	// keyed by tablet alias. injects a sleep to the end of the function
 	// regardless of parent context timeout or error result.
@@ -1022,6 +1029,34 @@ func (fake *TabletManagerClient) PromoteReplica(ctx context.Context, tablet *top
 	return "", assert.AnError
 }
 
+// InspectBinlogTransactions is part of the tmclient.TabletManagerClient interface.
+func (fake *TabletManagerClient) InspectBinlogTransactions(ctx context.Context, tablet *topodatapb.Tablet, gtidSet string) (*tabletmanagerdatapb.InspectBinlogTransactionsResponse, error) {
+	if fake.InspectBinlogTransactionsResults == nil {
+		return nil, fmt.Errorf("%w: no InspectBinlogTransactions results on fake TabletManagerClient", assert.AnError)
+	}
+
+	key := topoproto.TabletAliasString(tablet.Alias)
+	if result, ok := fake.InspectBinlogTransactionsResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no InspectBinlogTransactions result for %s", assert.AnError, key)
+}
+
+// InjectEmptyTransactions is part of the tmclient.TabletManagerClient interface.
+func (fake *TabletManagerClient) InjectEmptyTransactions(ctx context.Context, tablet *topodatapb.Tablet, gtidSet string) error {
+	if fake.InjectEmptyTransactionsResults == nil {
+		return fmt.Errorf("%w: no InjectEmptyTransactions results on fake TabletManagerClient", assert.AnError)
+	}
+
+	key := topoproto.TabletAliasString(tablet.Alias)
+	if err, ok := fake.InjectEmptyTransactionsResults[key]; ok {
+		return err
+	}
+
+	return fmt.Errorf("%w: no InjectEmptyTransactions result for %s", assert.AnError, key)
+}
+
 // RefreshState is part of the tmclient.TabletManagerClient interface.
 func (fake *TabletManagerClient) RefreshState(ctx context.Context, tablet *topodatapb.Tablet) error {
 	if fake.RefreshStateResults == nil {
@@ -39,6 +39,11 @@ func (client *localVtctldClient) AddCellsAlias(ctx context.Context, in *vtctldat
 	return client.s.AddCellsAlias(ctx, in)
 }
 
+// ApplyDesiredSchema is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ApplyDesiredSchema(ctx context.Context, in *vtctldatapb.ApplyDesiredSchemaRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyDesiredSchemaResponse, error) {
+	return client.s.ApplyDesiredSchema(ctx, in)
+}
+
 // ApplyKeyspaceRoutingRules is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) ApplyKeyspaceRoutingRules(ctx context.Context, in *vtctldatapb.ApplyKeyspaceRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyKeyspaceRoutingRulesResponse, error) {
 	return client.s.ApplyKeyspaceRoutingRules(ctx, in)
@@ -171,6 +176,31 @@ func (client *localVtctldClient) CancelSchemaMigration(ctx context.Context, in *
 	return client.s.CancelSchemaMigration(ctx, in)
 }
 
+// ChangePrimaryVindexCancel is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ChangePrimaryVindexCancel(ctx context.Context, in *vtctldatapb.ChangePrimaryVindexCancelRequest, opts ...grpc.CallOption) (*vtctldatapb.ChangePrimaryVindexCancelResponse, error) {
+	return client.s.ChangePrimaryVindexCancel(ctx, in)
+}
+
+// ChangePrimaryVindexComplete is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ChangePrimaryVindexComplete(ctx context.Context, in *vtctldatapb.ChangePrimaryVindexCompleteRequest, opts ...grpc.CallOption) (*vtctldatapb.ChangePrimaryVindexCompleteResponse, error) {
+	return client.s.ChangePrimaryVindexComplete(ctx, in)
+}
+
+// ChangePrimaryVindexCreate is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ChangePrimaryVindexCreate(ctx context.Context, in *vtctldatapb.ChangePrimaryVindexCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.ChangePrimaryVindexCreateResponse, error) {
+	return client.s.ChangePrimaryVindexCreate(ctx, in)
+}
+
+// ChangePrimaryVindexReverseTraffic is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ChangePrimaryVindexReverseTraffic(ctx context.Context, in *vtctldatapb.ChangePrimaryVindexReverseTrafficRequest, opts ...grpc.CallOption) (*vtctldatapb.ChangePrimaryVindexReverseTrafficResponse, error) {
+	return client.s.ChangePrimaryVindexReverseTraffic(ctx, in)
+}
+
+// ChangePrimaryVindexSwitchTraffic is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ChangePrimaryVindexSwitchTraffic(ctx context.Context, in *vtctldatapb.ChangePrimaryVindexSwitchTrafficRequest, opts ...grpc.CallOption) (*vtctldatapb.ChangePrimaryVindexSwitchTrafficResponse, error) {
+	return client.s.ChangePrimaryVindexSwitchTraffic(ctx, in)
+}
+
 // ChangeTabletTags is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) ChangeTabletTags(ctx context.Context, in *vtctldatapb.ChangeTabletTagsRequest, opts ...grpc.CallOption) (*vtctldatapb.ChangeTabletTagsResponse, error) {
 	return client.s.ChangeTabletTags(ctx, in)
@@ -251,6 +281,16 @@ func (client *localVtctldClient) EmergencyReparentShard(ctx context.Context, in
 	return client.s.EmergencyReparentShard(ctx, in)
 }
 
+// EnforceBackupRetentionPolicy is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) EnforceBackupRetentionPolicy(ctx context.Context, in *vtctldatapb.EnforceBackupRetentionPolicyRequest, opts ...grpc.CallOption) (*vtctldatapb.EnforceBackupRetentionPolicyResponse, error) {
+	return client.s.EnforceBackupRetentionPolicy(ctx, in)
+}
+
+// EvacuateCell is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) EvacuateCell(ctx context.Context, in *vtctldatapb.EvacuateCellRequest, opts ...grpc.CallOption) (*vtctldatapb.EvacuateCellResponse, error) {
+	return client.s.EvacuateCell(ctx, in)
+}
+
 // ExecuteFetchAsApp is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) ExecuteFetchAsApp(ctx context.Context, in *vtctldatapb.ExecuteFetchAsAppRequest, opts ...grpc.CallOption) (*vtctldatapb.ExecuteFetchAsAppResponse, error) {
 	return client.s.ExecuteFetchAsApp(ctx, in)
@@ -426,6 +466,21 @@ func (client *localVtctldClient) GetWorkflows(ctx context.Context, in *vtctldata
 	return client.s.GetWorkflows(ctx, in)
 }
 
+// ImportCancel is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ImportCancel(ctx context.Context, in *vtctldatapb.ImportCancelRequest, opts ...grpc.CallOption) (*vtctldatapb.ImportCancelResponse, error) {
+	return client.s.ImportCancel(ctx, in)
+}
+
+// ImportComplete is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ImportComplete(ctx context.Context, in *vtctldatapb.ImportCompleteRequest, opts ...grpc.CallOption) (*vtctldatapb.ImportCompleteResponse, error) {
+	return client.s.ImportComplete(ctx, in)
+}
+
+// ImportCreate is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ImportCreate(ctx context.Context, in *vtctldatapb.ImportCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.ImportCreateResponse, error) {
+	return client.s.ImportCreate(ctx, in)
+}
+
 // InitShardPrimary is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) InitShardPrimary(ctx context.Context, in *vtctldatapb.InitShardPrimaryRequest, opts ...grpc.CallOption) (*vtctldatapb.InitShardPrimaryResponse, error) {
 	return client.s.InitShardPrimary(ctx, in)
@@ -627,11 +682,26 @@ func (client *localVtctldClient) RunHealthCheck(ctx context.Context, in *vtctlda
 	return client.s.RunHealthCheck(ctx, in)
 }
 
+// SetKeyspaceBackupRetentionPolicy is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) SetKeyspaceBackupRetentionPolicy(ctx context.Context, in *vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest, opts ...grpc.CallOption) (*vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse, error) {
+	return client.s.SetKeyspaceBackupRetentionPolicy(ctx, in)
+}
+
+// SetKeyspaceCutOverWindowPolicy is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) SetKeyspaceCutOverWindowPolicy(ctx context.Context, in *vtctldatapb.SetKeyspaceCutOverWindowPolicyRequest, opts ...grpc.CallOption) (*vtctldatapb.SetKeyspaceCutOverWindowPolicyResponse, error) {
+	return client.s.SetKeyspaceCutOverWindowPolicy(ctx, in)
+}
+
 // SetKeyspaceDurabilityPolicy is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) SetKeyspaceDurabilityPolicy(ctx context.Context, in *vtctldatapb.SetKeyspaceDurabilityPolicyRequest, opts ...grpc.CallOption) (*vtctldatapb.SetKeyspaceDurabilityPolicyResponse, error) {
 	return client.s.SetKeyspaceDurabilityPolicy(ctx, in)
 }
 
+// SetKeyspaceErrantGtidRemediationPolicy is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) SetKeyspaceErrantGtidRemediationPolicy(ctx context.Context, in *vtctldatapb.SetKeyspaceErrantGtidRemediationPolicyRequest, opts ...grpc.CallOption) (*vtctldatapb.SetKeyspaceErrantGtidRemediationPolicyResponse, error) {
+	return client.s.SetKeyspaceErrantGtidRemediationPolicy(ctx, in)
+}
+
 // SetShardIsPrimaryServing is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) SetShardIsPrimaryServing(ctx context.Context, in *vtctldatapb.SetShardIsPrimaryServingRequest, opts ...grpc.CallOption) (*vtctldatapb.SetShardIsPrimaryServingResponse, error) {
 	return client.s.SetShardIsPrimaryServing(ctx, in)
@@ -722,6 +792,11 @@ func (client *localVtctldClient) VDiffDelete(ctx context.Context, in *vtctldatap
 	return client.s.VDiffDelete(ctx, in)
 }
 
+// VDiffRepair is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) VDiffRepair(ctx context.Context, in *vtctldatapb.VDiffRepairRequest, opts ...grpc.CallOption) (*vtctldatapb.VDiffRepairResponse, error) {
+	return client.s.VDiffRepair(ctx, in)
+}
+
 // VDiffResume is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) VDiffResume(ctx context.Context, in *vtctldatapb.VDiffResumeRequest, opts ...grpc.CallOption) (*vtctldatapb.VDiffResumeResponse, error) {
 	return client.s.VDiffResume(ctx, in)
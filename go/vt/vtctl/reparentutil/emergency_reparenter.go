@@ -144,6 +144,64 @@ func (erp *EmergencyReparenter) getLockAction(newPrimaryAlias *topodatapb.Tablet
 	return action
 }
 
+// ChooseNewPrimary returns the tablet that an EmergencyReparentShard operation
+// on the given keyspace and shard would promote, given the replication positions
+// of the reachable tablets. The tablets without a position are considered unreachable.
+// It does not lock the shard nor make any changes to the tablets, so the replication
+// positions are expected to have been read by the caller, and to be free of errant GTIDs.
+func (erp *EmergencyReparenter) ChooseNewPrimary(ctx context.Context, keyspace string, shard string, positions map[string]replication.Position, opts EmergencyReparentOptions) (*topodatapb.Tablet, error) {
+	var err error
+	opts.durability, err = policy.GetKeyspaceDurabilityPolicy(ctx, erp.ts, keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	shardInfo, err := erp.ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+	var prevPrimary *topodatapb.Tablet
+	if shardInfo.PrimaryAlias != nil {
+		prevPrimaryInfo, err := erp.ts.GetTablet(ctx, shardInfo.PrimaryAlias)
+		if err != nil {
+			return nil, err
+		}
+		prevPrimary = prevPrimaryInfo.Tablet
+	}
+
+	tabletMap, err := erp.ts.GetTabletMapForShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to get tablet map for %v/%v: %v", keyspace, shard, err)
+	}
+
+	validCandidates := make(map[string]replication.Position, len(positions))
+	var tabletsReachable []*topodatapb.Tablet
+	for alias, position := range positions {
+		tabletInfo, ok := tabletMap[alias]
+		if !ok || opts.IgnoreReplicas.Has(alias) {
+			continue
+		}
+		validCandidates[alias] = position
+		tabletsReachable = append(tabletsReachable, tabletInfo.Tablet)
+	}
+	validCandidates, err = restrictValidCandidates(validCandidates, tabletMap)
+	if err != nil {
+		return nil, err
+	} else if len(validCandidates) == 0 {
+		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "no valid candidates for emergency reparent")
+	}
+
+	intermediateSource, validCandidateTablets, err := erp.findMostAdvanced(validCandidates, tabletMap, opts)
+	if err != nil {
+		return nil, err
+	}
+	validCandidateTablets, err = erp.filterValidCandidates(validCandidateTablets, tabletsReachable, nil, prevPrimary, opts)
+	if err != nil {
+		return nil, err
+	}
+	return erp.identifyPrimaryCandidate(intermediateSource, validCandidateTablets, tabletMap, opts)
+}
+
 // reparentShardLocked performs Emergency Reparent Shard operation assuming that the shard is already locked
 func (erp *EmergencyReparenter) reparentShardLocked(ctx context.Context, ev *events.Reparent, keyspace, shard string, opts EmergencyReparentOptions) (err error) {
 	// log the starting of the operation and increment the counter
@@ -176,13 +234,8 @@ func (erp *EmergencyReparenter) reparentShardLocked(ctx context.Context, ev *eve
 		)
 	}
 
-	keyspaceDurability, err := erp.ts.GetKeyspaceDurability(ctx, keyspace)
-	if err != nil {
-		return err
-	}
-
-	erp.logger.Infof("Getting a new durability policy for %v", keyspaceDurability)
-	opts.durability, err = policy.GetDurabilityPolicy(keyspaceDurability)
+	erp.logger.Infof("Getting a new durability policy for keyspace %v", keyspace)
+	opts.durability, err = policy.GetKeyspaceDurabilityPolicy(ctx, erp.ts, keyspace)
 	if err != nil {
 		return err
 	}
@@ -2778,6 +2778,118 @@ func TestEmergencyReparenterStats(t *testing.T) {
 	require.EqualValues(t, map[string]int64{"All": 2, "EmergencyReparentShard": 2}, reparentShardOpTimings.Counts())
 }
 
+func TestEmergencyReparenter_ChooseNewPrimary(t *testing.T) {
+	tablets := []*topodatapb.Tablet{
+		{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+			Type:     topodatapb.TabletType_PRIMARY,
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+		},
+		{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+			Type:     topodatapb.TabletType_REPLICA,
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+		},
+		{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone2", Uid: 102},
+			Type:     topodatapb.TabletType_REPLICA,
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+		},
+		{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 103},
+			Type:     topodatapb.TabletType_RDONLY,
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+		},
+		{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 104},
+			Type:     topodatapb.TabletType_BACKUP,
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+		},
+	}
+	mustParse := func(pos string) replication.Position {
+		position, err := replication.DecodePosition(pos)
+		require.NoError(t, err)
+		return position
+	}
+	behind := mustParse("MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21")
+	ahead := mustParse("MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26")
+
+	tests := []struct {
+		name       string
+		positions  map[string]replication.Position
+		opts       EmergencyReparentOptions
+		wantAlias  string
+		wantErrStr string
+	}{
+		{
+			name: "most advanced replica",
+			positions: map[string]replication.Position{
+				"zone1-0000000101": behind,
+				"zone2-0000000102": ahead,
+			},
+			wantAlias: "zone2-0000000102",
+		}, {
+			name: "prevent cross cell promotion",
+			positions: map[string]replication.Position{
+				"zone1-0000000101": behind,
+				"zone2-0000000102": ahead,
+			},
+			opts:      EmergencyReparentOptions{PreventCrossCellPromotion: true},
+			wantAlias: "zone1-0000000101",
+		}, {
+			name: "most advanced tablet cannot be promoted",
+			positions: map[string]replication.Position{
+				"zone1-0000000101": behind,
+				"zone1-0000000103": ahead,
+			},
+			wantAlias: "zone1-0000000101",
+		}, {
+			name: "ignored replica",
+			positions: map[string]replication.Position{
+				"zone1-0000000101": behind,
+				"zone2-0000000102": ahead,
+			},
+			opts:      EmergencyReparentOptions{IgnoreReplicas: sets.New[string]("zone2-0000000102")},
+			wantAlias: "zone1-0000000101",
+		}, {
+			name: "no valid candidates",
+			positions: map[string]replication.Position{
+				"zone1-0000000104": ahead,
+			},
+			wantErrStr: "no valid candidates for emergency reparent",
+		}, {
+			name:       "no reachable tablets",
+			wantErrStr: "no valid candidates for emergency reparent",
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+	defer ts.Close()
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+		AlsoSetShardPrimary: true,
+	}, tablets...)
+	erp := NewEmergencyReparenter(ts, nil, logutil.NewMemoryLogger())
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidate, err := erp.ChooseNewPrimary(ctx, "testkeyspace", "-", tt.positions, tt.opts)
+			if tt.wantErrStr != "" {
+				require.ErrorContains(t, err, tt.wantErrStr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantAlias, topoproto.TabletAliasString(candidate.Alias))
+		})
+	}
+}
+
 func TestEmergencyReparenter_findMostAdvanced(t *testing.T) {
 	sid1 := replication.SID{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
 	mysqlGTID1 := replication.Mysql56GTID{
@@ -66,6 +66,7 @@ type PlannedReparentOptions struct {
 	WaitReplicasTimeout     time.Duration
 	TolerableReplLag        time.Duration
 	AllowCrossCellPromotion bool
+	AvoidPrimaryCells       []string
 
 	// Private options managed internally. We use value-passing semantics to
 	// set these options inside a PlannedReparent without leaking these details
@@ -516,13 +517,8 @@ func (pr *PlannedReparenter) reparentShardLocked(
 		)
 	}
 
-	keyspaceDurability, err := pr.ts.GetKeyspaceDurability(ctx, keyspace)
-	if err != nil {
-		return err
-	}
-
-	pr.logger.Infof("Getting a new durability policy for %v", keyspaceDurability)
-	opts.durability, err = policy.GetDurabilityPolicy(keyspaceDurability)
+	pr.logger.Infof("Getting a new durability policy for keyspace %v", keyspace)
+	opts.durability, err = policy.GetKeyspaceDurabilityPolicy(ctx, pr.ts, keyspace)
 	if err != nil {
 		return err
 	}
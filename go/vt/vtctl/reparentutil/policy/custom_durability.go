@@ -0,0 +1,168 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil/promotionrule"
+)
+
+// DurabilityCustom is the name of the durability policy of the keyspaces
+// whose durability policy is declared in their keyspace record.
+const DurabilityCustom = "custom"
+
+// GetDurabilityPolicyForKeyspace returns the durability policy of the given
+// keyspace record, which is either a registered policy or the custom policy
+// of the keyspace.
+func GetDurabilityPolicyForKeyspace(keyspace *topodatapb.Keyspace) (Durabler, error) {
+	if keyspace.GetDurabilityPolicy() == DurabilityCustom {
+		return NewCustomDurability(keyspace.GetCustomDurabilityPolicy())
+	}
+	return GetDurabilityPolicy(keyspace.GetDurabilityPolicy())
+}
+
+// GetKeyspaceDurabilityPolicy reads the given keyspace and returns its
+// durability policy. The policy of the keyspaces that do not have one is
+// "none", for backward compatibility.
+func GetKeyspaceDurabilityPolicy(ctx context.Context, ts *topo.Server, keyspace string) (Durabler, error) {
+	ki, err := ts.GetKeyspace(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	if ki.GetDurabilityPolicy() == "" {
+		return GetDurabilityPolicy(DurabilityNone)
+	}
+	return GetDurabilityPolicyForKeyspace(ki.Keyspace)
+}
+
+// ValidateCustomDurabilityPolicy checks that the given custom durability
+// policy is valid.
+func ValidateCustomDurabilityPolicy(customPolicy *topodatapb.CustomDurabilityPolicy) error {
+	if customPolicy == nil {
+		return fmt.Errorf("custom durability policy not set")
+	}
+	for i, rule := range customPolicy.PromotionRules {
+		if _, err := promotionrule.Parse(rule.Rule); err != nil {
+			return fmt.Errorf("invalid promotion rule %d: %v", i, err)
+		}
+		if err := validateTabletSelector(rule.Selector); err != nil {
+			return fmt.Errorf("invalid selector of promotion rule %d: %v", i, err)
+		}
+	}
+	if err := validateTabletSelector(customPolicy.Ackers); err != nil {
+		return fmt.Errorf("invalid ackers: %v", err)
+	}
+	for _, tag := range customPolicy.AckerDistinctTags {
+		if tag == "" {
+			return fmt.Errorf("invalid acker distinct tags: empty tag")
+		}
+	}
+	return nil
+}
+
+func validateTabletSelector(selector *topodatapb.CustomDurabilityPolicy_TabletSelector) error {
+	for _, tabletType := range selector.GetTabletTypes() {
+		if _, ok := topodatapb.TabletType_name[int32(tabletType)]; !ok || tabletType == topodatapb.TabletType_UNKNOWN {
+			return fmt.Errorf("invalid tablet type %v", tabletType)
+		}
+	}
+	for tag := range selector.GetTags() {
+		if tag == "" {
+			return fmt.Errorf("empty tag")
+		}
+	}
+	return nil
+}
+
+// NewCustomDurability returns the Durabler of the given custom durability
+// policy.
+func NewCustomDurability(customPolicy *topodatapb.CustomDurabilityPolicy) (Durabler, error) {
+	if err := ValidateCustomDurabilityPolicy(customPolicy); err != nil {
+		return nil, err
+	}
+	return &durabilityCustom{policy: customPolicy}, nil
+}
+
+//=======================================================================
+
+// durabilityCustom is a durability policy that is declared in the keyspace record.
+// Its rules select the tablets by their types, cells and tags.
+type durabilityCustom struct {
+	policy *topodatapb.CustomDurabilityPolicy
+}
+
+// PromotionRule implements the Durabler interface
+func (d *durabilityCustom) PromotionRule(tablet *topodatapb.Tablet) promotionrule.CandidatePromotionRule {
+	for _, rule := range d.policy.PromotionRules {
+		if selectsTablet(rule.Selector, tablet) {
+			return promotionrule.CandidatePromotionRule(rule.Rule)
+		}
+	}
+	switch tablet.Type {
+	case topodatapb.TabletType_PRIMARY, topodatapb.TabletType_REPLICA:
+		return promotionrule.Neutral
+	}
+	return promotionrule.MustNot
+}
+
+// SemiSyncAckers implements the Durabler interface
+func (d *durabilityCustom) SemiSyncAckers(tablet *topodatapb.Tablet) int {
+	return int(d.policy.SemiSyncAckers)
+}
+
+// IsReplicaSemiSync implements the Durabler interface
+func (d *durabilityCustom) IsReplicaSemiSync(primary, replica *topodatapb.Tablet) bool {
+	if d.policy.SemiSyncAckers == 0 {
+		return false
+	}
+	if d.policy.Ackers == nil {
+		switch replica.Type {
+		case topodatapb.TabletType_PRIMARY, topodatapb.TabletType_REPLICA:
+		default:
+			return false
+		}
+	} else if !selectsTablet(d.policy.Ackers, replica) {
+		return false
+	}
+	for _, tag := range d.policy.AckerDistinctTags {
+		if value, ok := replica.Tags[tag]; !ok || value == primary.Tags[tag] {
+			return false
+		}
+	}
+	return true
+}
+
+// selectsTablet returns whether the selector selects the tablet.
+func selectsTablet(selector *topodatapb.CustomDurabilityPolicy_TabletSelector, tablet *topodatapb.Tablet) bool {
+	if len(selector.GetTabletTypes()) > 0 && !slices.Contains(selector.TabletTypes, tablet.Type) {
+		return false
+	}
+	if len(selector.GetCells()) > 0 && !slices.Contains(selector.Cells, tablet.Alias.Cell) {
+		return false
+	}
+	for tag, value := range selector.GetTags() {
+		if tabletValue, ok := tablet.Tags[tag]; !ok || tabletValue != value {
+			return false
+		}
+	}
+	return true
+}
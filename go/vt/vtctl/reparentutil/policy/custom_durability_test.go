@@ -0,0 +1,173 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil/promotionrule"
+)
+
+func customDurabilityTablet(cell string, tabletType topodatapb.TabletType, az string) *topodatapb.Tablet {
+	tablet := &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{
+			Cell: cell,
+			Uid:  100,
+		},
+		Type: tabletType,
+	}
+	if az != "" {
+		tablet.Tags = map[string]string{"az": az}
+	}
+	return tablet
+}
+
+func TestDurabilityCustom(t *testing.T) {
+	durability, err := GetDurabilityPolicyForKeyspace(&topodatapb.Keyspace{
+		DurabilityPolicy: DurabilityCustom,
+		CustomDurabilityPolicy: &topodatapb.CustomDurabilityPolicy{
+			PromotionRules: []*topodatapb.CustomDurabilityPolicy_PromotionRule{{
+				Selector: &topodatapb.CustomDurabilityPolicy_TabletSelector{Cells: []string{"cell3"}},
+				Rule:     "must_not",
+			}, {
+				Selector: &topodatapb.CustomDurabilityPolicy_TabletSelector{
+					TabletTypes: []topodatapb.TabletType{topodatapb.TabletType_PRIMARY, topodatapb.TabletType_REPLICA},
+					Tags:        map[string]string{"az": "az1"},
+				},
+				Rule: "prefer",
+			}},
+			SemiSyncAckers: 2,
+			Ackers: &topodatapb.CustomDurabilityPolicy_TabletSelector{
+				TabletTypes: []topodatapb.TabletType{topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY},
+			},
+			AckerDistinctTags: []string{"az"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, promotionrule.MustNot, PromotionRule(durability, customDurabilityTablet("cell3", topodatapb.TabletType_REPLICA, "az1")))
+	assert.Equal(t, promotionrule.Prefer, PromotionRule(durability, customDurabilityTablet("cell1", topodatapb.TabletType_REPLICA, "az1")))
+	assert.Equal(t, promotionrule.Neutral, PromotionRule(durability, customDurabilityTablet("cell1", topodatapb.TabletType_PRIMARY, "az2")))
+	assert.Equal(t, promotionrule.MustNot, PromotionRule(durability, customDurabilityTablet("cell1", topodatapb.TabletType_RDONLY, "az1")))
+	assert.Equal(t, promotionrule.MustNot, PromotionRule(durability, nil))
+
+	primary := customDurabilityTablet("cell1", topodatapb.TabletType_PRIMARY, "az1")
+	assert.Equal(t, 2, SemiSyncAckers(durability, primary))
+	assert.True(t, IsReplicaSemiSync(durability, primary, customDurabilityTablet("cell1", topodatapb.TabletType_REPLICA, "az2")))
+	assert.True(t, IsReplicaSemiSync(durability, primary, customDurabilityTablet("cell2", topodatapb.TabletType_RDONLY, "az3")))
+	assert.False(t, IsReplicaSemiSync(durability, primary, customDurabilityTablet("cell2", topodatapb.TabletType_REPLICA, "az1")), "same availability zone as the primary")
+	assert.False(t, IsReplicaSemiSync(durability, primary, customDurabilityTablet("cell2", topodatapb.TabletType_REPLICA, "")), "no availability zone")
+	assert.False(t, IsReplicaSemiSync(durability, primary, customDurabilityTablet("cell2", topodatapb.TabletType_BACKUP, "az2")), "not an acker")
+
+	// Without ackers, the PRIMARY and REPLICA tablets send semi-sync acks.
+	durability, err = NewCustomDurability(&topodatapb.CustomDurabilityPolicy{SemiSyncAckers: 1})
+	require.NoError(t, err)
+	assert.True(t, IsReplicaSemiSync(durability, primary, customDurabilityTablet("cell1", topodatapb.TabletType_REPLICA, "")))
+	assert.False(t, IsReplicaSemiSync(durability, primary, customDurabilityTablet("cell1", topodatapb.TabletType_RDONLY, "")))
+
+	// Semi-sync is not used without semi-sync ackers.
+	durability, err = NewCustomDurability(&topodatapb.CustomDurabilityPolicy{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, SemiSyncAckers(durability, primary))
+	assert.False(t, IsReplicaSemiSync(durability, primary, customDurabilityTablet("cell1", topodatapb.TabletType_REPLICA, "")))
+}
+
+func TestValidateCustomDurabilityPolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		customPolicy *topodatapb.CustomDurabilityPolicy
+		err          string
+	}{
+		{
+			name: "valid",
+			customPolicy: &topodatapb.CustomDurabilityPolicy{
+				PromotionRules: []*topodatapb.CustomDurabilityPolicy_PromotionRule{{Rule: "prefer_not"}},
+				SemiSyncAckers: 1,
+			},
+		}, {
+			name: "not set",
+			err:  "custom durability policy not set",
+		}, {
+			name: "must promotion rule",
+			customPolicy: &topodatapb.CustomDurabilityPolicy{
+				PromotionRules: []*topodatapb.CustomDurabilityPolicy_PromotionRule{{Rule: "must"}},
+			},
+			err: "invalid promotion rule 0: CandidatePromotionRule: must not supported yet",
+		}, {
+			name: "invalid tablet type",
+			customPolicy: &topodatapb.CustomDurabilityPolicy{
+				PromotionRules: []*topodatapb.CustomDurabilityPolicy_PromotionRule{{
+					Selector: &topodatapb.CustomDurabilityPolicy_TabletSelector{TabletTypes: []topodatapb.TabletType{topodatapb.TabletType_UNKNOWN}},
+					Rule:     "neutral",
+				}},
+			},
+			err: "invalid selector of promotion rule 0: invalid tablet type UNKNOWN",
+		}, {
+			name: "empty acker tag",
+			customPolicy: &topodatapb.CustomDurabilityPolicy{
+				Ackers: &topodatapb.CustomDurabilityPolicy_TabletSelector{Tags: map[string]string{"": "az1"}},
+			},
+			err: "invalid ackers: empty tag",
+		}, {
+			name: "empty acker distinct tag",
+			customPolicy: &topodatapb.CustomDurabilityPolicy{
+				AckerDistinctTags: []string{""},
+			},
+			err: "invalid acker distinct tags: empty tag",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCustomDurabilityPolicy(tt.customPolicy)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestGetKeyspaceDurabilityPolicy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks1", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks2", &topodatapb.Keyspace{
+		DurabilityPolicy:       DurabilityCustom,
+		CustomDurabilityPolicy: &topodatapb.CustomDurabilityPolicy{SemiSyncAckers: 3},
+	}))
+
+	durability, err := GetKeyspaceDurabilityPolicy(ctx, ts, "ks1")
+	require.NoError(t, err)
+	assert.IsType(t, &durabilityNone{}, durability)
+
+	durability, err = GetKeyspaceDurabilityPolicy(ctx, ts, "ks2")
+	require.NoError(t, err)
+	assert.Equal(t, 3, SemiSyncAckers(durability, nil))
+
+	_, err = GetKeyspaceDurabilityPolicy(ctx, ts, "ks3")
+	assert.Error(t, err)
+}
@@ -137,12 +137,8 @@ func SetReplicationSource(ctx context.Context, ts *topo.Server, tmc tmclient.Tab
 		return nil
 	}
 
-	durabilityName, err := ts.GetKeyspaceDurability(ctx, tablet.Keyspace)
-	if err != nil {
-		return err
-	}
-	log.Infof("Getting a new durability policy for %v", durabilityName)
-	durability, err := policy.GetDurabilityPolicy(durabilityName)
+	log.Infof("Getting a new durability policy for keyspace %v", tablet.Keyspace)
+	durability, err := policy.GetKeyspaceDurabilityPolicy(ctx, ts, tablet.Keyspace)
 	if err != nil {
 		return err
 	}
@@ -19,6 +19,7 @@ package reparentutil
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -108,6 +109,9 @@ func ElectNewPrimary(
 		case opts.AvoidPrimaryAlias != nil && topoproto.TabletAliasEqual(tablet.Alias, opts.AvoidPrimaryAlias):
 			reasonsToInvalidate.WriteString(fmt.Sprintf("\n%v matches the primary alias to avoid", topoproto.TabletAliasString(tablet.Alias)))
 			continue
+		case slices.Contains(opts.AvoidPrimaryCells, tablet.Alias.Cell):
+			reasonsToInvalidate.WriteString(fmt.Sprintf("\n%v is in a cell to avoid", topoproto.TabletAliasString(tablet.Alias)))
+			continue
 		case tablet.Tablet.Type != topodatapb.TabletType_REPLICA:
 			reasonsToInvalidate.WriteString(fmt.Sprintf("\n%v is not a replica", topoproto.TabletAliasString(tablet.Alias)))
 			continue
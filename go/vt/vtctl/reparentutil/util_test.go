@@ -77,6 +77,7 @@ func TestElectNewPrimary(t *testing.T) {
 		avoidPrimaryAlias       *topodatapb.TabletAlias
 		tolerableReplLag        time.Duration
 		allowCrossCellPromotion bool
+		avoidPrimaryCells       []string
 		expected                *topodatapb.TabletAlias
 		errContains             []string
 	}{
@@ -1051,6 +1052,65 @@ func TestElectNewPrimary(t *testing.T) {
 				Uid:  102,
 			},
 		},
+		{
+			name: "avoid primary cells",
+			tmc: &chooseNewPrimaryTestTMClient{
+				// zone1-101 is ahead of zone2-201, but it is in a cell to avoid
+				replicationStatuses: map[string]*replicationdatapb.Status{
+					"zone1-0000000101": {
+						Position: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5",
+					},
+					"zone2-0000000201": {
+						Position: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1",
+					},
+				},
+			},
+			allowCrossCellPromotion: true,
+			avoidPrimaryCells:       []string{"zone1"},
+			shardInfo: topo.NewShardInfo("testkeyspace", "-", &topodatapb.Shard{
+				PrimaryAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+			}, nil),
+			tabletMap: map[string]*topo.TabletInfo{
+				"primary": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  100,
+						},
+						Type: topodatapb.TabletType_PRIMARY,
+					},
+				},
+				"replica1": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  101,
+						},
+						Type: topodatapb.TabletType_REPLICA,
+					},
+				},
+				"replica2": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone2",
+							Uid:  201,
+						},
+						Type: topodatapb.TabletType_REPLICA,
+					},
+				},
+			},
+			avoidPrimaryAlias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+			expected: &topodatapb.TabletAlias{
+				Cell: "zone2",
+				Uid:  201,
+			},
+		},
 		{
 			name: "only available tablet is AvoidPrimary",
 			tmc: &chooseNewPrimaryTestTMClient{
@@ -1135,6 +1195,7 @@ zone1-0000000100 is not a replica`,
 				TolerableReplLag:        tt.tolerableReplLag,
 				durability:              durability,
 				AllowCrossCellPromotion: tt.allowCrossCellPromotion,
+				AvoidPrimaryCells:       tt.avoidPrimaryCells,
 				WaitReplicasTimeout:     time.Millisecond * 50,
 			}
 			actual, err := ElectNewPrimary(ctx, tt.tmc, tt.shardInfo, tt.tabletMap, tt.innodbBufferPoolData, options, logger)
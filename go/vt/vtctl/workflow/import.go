@@ -0,0 +1,604 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/trace"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vreplication"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	sqlImportListTables  = "select table_name from information_schema.tables where table_schema = database() and table_type = 'BASE TABLE' order by table_name"
+	sqlImportShowCreate  = "show create table %s"
+	sqlImportReadOnly    = "select @@global.read_only, @@global.version"
+	sqlImportMySQLGTIDs  = "select @@global.gtid_executed"
+	sqlImportMariaDBGTID = "select @@global.gtid_binlog_pos"
+
+	// defaultImportSourcePort is the port of the external mysql when its
+	// connection parameters are passed without one.
+	defaultImportSourcePort = 3306
+
+	// defaultImportCompleteTimeout is how long we wait, by default, for the
+	// workflow to catch up with the write locked source when completing it.
+	defaultImportCompleteTimeout = 30 * time.Second
+)
+
+// ImportCreate is part of the vtctlservicepb.VtctldServer interface. It
+// creates a workflow that copies tables from an external MySQL or MariaDB
+// primary, which is not managed by Vitess, into the target keyspace and
+// then keeps them up to date using the source's binary logs.
+func (s *Server) ImportCreate(ctx context.Context, req *vtctldatapb.ImportCreateRequest) (resp *vtctldatapb.ImportCreateResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.ImportCreate")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.TargetKeyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("external_mysql", req.ExternalMysql)
+	span.Annotate("on_ddl", req.OnDdl)
+
+	if req.ExternalMysql == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "an external mysql must be specified")
+	}
+	if _, ok := binlogdatapb.OnDDLAction_value[strings.ToUpper(req.OnDdl)]; req.OnDdl != "" && !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid on-ddl value: %s", req.OnDdl)
+	}
+	if req.SourceHost == "" && (req.SourcePort != 0 || req.SourceUser != "" || req.SourcePassword != "" || req.SourceDbName != "") {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the source host must be specified along with the other connection parameters of the external mysql")
+	}
+	if req.SourceHost != "" && (req.SourceUser == "" || req.SourceDbName == "") {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the source user and database must be specified along with the source host")
+	}
+	if err := validateNewWorkflow(ctx, s.ts, s.tmc, req.TargetKeyspace, req.Workflow); err != nil {
+		return nil, err
+	}
+
+	lockName := fmt.Sprintf("%s/%s", req.TargetKeyspace, req.Workflow)
+	ctx, workflowUnlock, lockErr := s.ts.LockName(ctx, lockName, "ImportCreate")
+	if lockErr != nil {
+		return nil, vterrors.Wrapf(lockErr, "failed to lock the %s workflow", lockName)
+	}
+	defer workflowUnlock(&err)
+
+	if req.SourceHost != "" {
+		if err := s.registerImportExternalMysql(ctx, req); err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err != nil {
+				if uerr := s.unregisterImportExternalMysql(ctx, req.ExternalMysql, req.TargetKeyspace, req.Workflow); uerr != nil {
+					s.Logger().Errorf("Failed to unregister the external mysql %s: %v", req.ExternalMysql, uerr)
+				}
+			}
+		}()
+	}
+
+	targets, err := s.newWorkflowTargets(ctx, req.TargetKeyspace, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkImportSource(ctx, targets, req.ExternalMysql); err != nil {
+		return nil, err
+	}
+	// All of the target tablets can connect to the external mysql, so we
+	// can use any of them to read its schema.
+	tables, err := s.getImportTables(ctx, targets.first().primary, req)
+	if err != nil {
+		return nil, err
+	}
+	s.Logger().Infof("Found tables to import: %s", strings.Join(tables, ","))
+
+	vschema, err := s.ts.GetVSchema(ctx, req.TargetKeyspace)
+	if err != nil {
+		return nil, err
+	}
+	if !vschema.Sharded {
+		if err := s.addTablesToVSchema(ctx, "", vschema.Keyspace, tables, false); err != nil {
+			return nil, err
+		}
+		if err := s.ts.SaveVSchema(ctx, vschema); err != nil {
+			return nil, err
+		}
+		if err := s.ts.RebuildSrvVSchema(ctx, nil); err != nil {
+			return nil, err
+		}
+	}
+	ksSchema, err := vindexes.BuildKeyspaceSchema(vschema.Keyspace, req.TargetKeyspace, s.env.Parser())
+	if err != nil {
+		return nil, err
+	}
+
+	createDDLs := make(map[string]string, len(tables))
+	for _, table := range tables {
+		qr, err := s.executeFetchOnExternalMysql(ctx, targets.first().primary, req.ExternalMysql, fmt.Sprintf(sqlImportShowCreate, sqlescape.EscapeID(table)))
+		if err != nil {
+			return nil, err
+		}
+		if len(qr.Rows) != 1 || len(qr.Rows[0]) < 2 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unexpected result for the definition of table %s: %v", table, qr.Rows)
+		}
+		createDDLs[table] = qr.Rows[0][1].ToString()
+	}
+
+	err = targets.ts.ForAllTargets(func(target *MigrationTarget) error {
+		if err := s.deployImportSchema(ctx, target, tables, createDDLs); err != nil {
+			return err
+		}
+		bls := &binlogdatapb.BinlogSource{
+			Filter:        &binlogdatapb.Filter{},
+			ExternalMysql: req.ExternalMysql,
+			StopAfterCopy: req.StopAfterCopy,
+			OnDdl:         binlogdatapb.OnDDLAction(binlogdatapb.OnDDLAction_value[strings.ToUpper(req.OnDdl)]),
+		}
+		if req.SourceTimeZone != "" {
+			bls.SourceTimeZone = req.SourceTimeZone
+			bls.TargetTimeZone = "UTC"
+		}
+		for _, table := range tables {
+			rule, err := buildImportRule(ksSchema, vschema.Keyspace, table, req.TableFilters[table], target.si.KeyRange, s.env.Parser())
+			if err != nil {
+				return err
+			}
+			bls.Filter.Rules = append(bls.Filter.Rules, rule)
+		}
+		_, err := s.tmc.CreateVReplicationWorkflow(ctx, target.primary.Tablet, &tabletmanagerdatapb.CreateVReplicationWorkflowRequest{
+			Workflow:           req.Workflow,
+			BinlogSource:       []*binlogdatapb.BinlogSource{bls},
+			WorkflowType:       binlogdatapb.VReplicationWorkflowType_Import,
+			DeferSecondaryKeys: req.DeferSecondaryKeys,
+			AutoStart:          req.AutoStart,
+			StopAfterCopy:      req.StopAfterCopy,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &vtctldatapb.ImportCreateResponse{Tables: tables}, nil
+}
+
+// ImportComplete is part of the vtctlservicepb.VtctldServer interface. It
+// verifies that writes are locked on the external source, waits for the
+// workflow to catch up with it, and then removes the workflow's streams so
+// that the target keyspace can take over the writes.
+func (s *Server) ImportComplete(ctx context.Context, req *vtctldatapb.ImportCompleteRequest) (resp *vtctldatapb.ImportCompleteResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.ImportComplete")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.TargetKeyspace)
+	span.Annotate("workflow", req.Workflow)
+
+	timeout := defaultImportCompleteTimeout
+	if req.Timeout != nil {
+		if timeout, _, err = protoutil.DurationFromProto(req.Timeout); err != nil {
+			return nil, vterrors.Wrapf(err, "invalid timeout")
+		}
+	}
+
+	lockName := fmt.Sprintf("%s/%s", req.TargetKeyspace, req.Workflow)
+	ctx, workflowUnlock, lockErr := s.ts.LockName(ctx, lockName, "ImportComplete")
+	if lockErr != nil {
+		return nil, vterrors.Wrapf(lockErr, "failed to lock the %s workflow", lockName)
+	}
+	defer workflowUnlock(&err)
+
+	targets, err := s.getWorkflowTargets(ctx, req.TargetKeyspace, req.Workflow, binlogdatapb.VReplicationWorkflowType_Import)
+	if err != nil {
+		return nil, err
+	}
+	externalMysql, err := getImportExternalMysql(targets)
+	if err != nil {
+		return nil, err
+	}
+	for _, shard := range targets.shards {
+		for _, stream := range targets.streams[shard] {
+			if stream.State != binlogdatapb.VReplicationWorkflowState_Running {
+				return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "stream %d on shard %s is in the %s state, all streams must be running to complete the import",
+					stream.Id, shard, stream.State)
+			}
+		}
+	}
+
+	// The source must be write locked so that the workflow can catch up
+	// with it and no writes are lost once the target takes over.
+	primary := targets.first().primary
+	pos, err := s.getImportSourcePosition(ctx, primary, externalMysql, true)
+	if err != nil {
+		return nil, err
+	}
+	waitCtx, waitCancel := context.WithTimeout(ctx, timeout)
+	defer waitCancel()
+	err = targets.ts.ForAllTargets(func(target *MigrationTarget) error {
+		for _, stream := range targets.streams[target.si.ShardName()] {
+			if err := s.tmc.VReplicationWaitForPos(waitCtx, target.primary.Tablet, stream.Id, pos); err != nil {
+				return vterrors.Wrapf(err, "stream %d on shard %s did not catch up with the source position %s", stream.Id, target.si.ShardName(), pos)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Confirm that nothing was written on the source while we were waiting.
+	endPos, err := s.getImportSourcePosition(ctx, primary, externalMysql, true)
+	if err != nil {
+		return nil, err
+	}
+	if endPos != pos {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the source position moved from %s to %s while completing the import, writes must be locked on the source",
+			pos, endPos)
+	}
+
+	if err := targets.ts.dropTargetVReplicationStreams(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.unregisterImportExternalMysql(ctx, externalMysql, req.TargetKeyspace, req.Workflow); err != nil {
+		return nil, err
+	}
+	return &vtctldatapb.ImportCompleteResponse{
+		Summary:  fmt.Sprintf("Successfully completed the %s workflow in the %s keyspace at source position %s", req.Workflow, req.TargetKeyspace, pos),
+		Position: pos,
+	}, nil
+}
+
+// ImportCancel is part of the vtctlservicepb.VtctldServer interface. It
+// removes the workflow's streams and, unless asked to keep them, the
+// imported tables.
+func (s *Server) ImportCancel(ctx context.Context, req *vtctldatapb.ImportCancelRequest) (resp *vtctldatapb.ImportCancelResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.ImportCancel")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.TargetKeyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("keep_data", req.KeepData)
+
+	lockName := fmt.Sprintf("%s/%s", req.TargetKeyspace, req.Workflow)
+	ctx, workflowUnlock, lockErr := s.ts.LockName(ctx, lockName, "ImportCancel")
+	if lockErr != nil {
+		return nil, vterrors.Wrapf(lockErr, "failed to lock the %s workflow", lockName)
+	}
+	defer workflowUnlock(&err)
+
+	targets, err := s.getWorkflowTargets(ctx, req.TargetKeyspace, req.Workflow, binlogdatapb.VReplicationWorkflowType_Import)
+	if err != nil {
+		return nil, err
+	}
+	externalMysql, err := getImportExternalMysql(targets)
+	if err != nil {
+		return nil, err
+	}
+	if err := targets.ts.dropTargetVReplicationStreams(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.unregisterImportExternalMysql(ctx, externalMysql, req.TargetKeyspace, req.Workflow); err != nil {
+		return nil, err
+	}
+	if !req.KeepData {
+		err = targets.ts.ForAllTargets(func(target *MigrationTarget) error {
+			for _, table := range getImportStreamTables(targets.streams[target.si.ShardName()]) {
+				query := fmt.Sprintf("drop table if exists %s.%s", sqlescape.EscapeID(target.primary.DbName()), sqlescape.EscapeID(table))
+				if _, err := s.tmc.ExecuteFetchAsDba(ctx, target.primary.Tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+					Query:                   []byte(query),
+					MaxRows:                 1,
+					ReloadSchema:            true,
+					DisableForeignKeyChecks: true,
+				}); err != nil {
+					return vterrors.Wrapf(err, "failed to drop table %s on shard %s", table, target.si.ShardName())
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &vtctldatapb.ImportCancelResponse{
+		Summary: fmt.Sprintf("Successfully cancelled the %s workflow in the %s keyspace", req.Workflow, req.TargetKeyspace),
+	}, nil
+}
+
+// registerImportExternalMysql registers the connection parameters of the
+// request's external mysql in the topo, so that the target tablets which do
+// not have it in their externalConnections config can connect to it.
+func (s *Server) registerImportExternalMysql(ctx context.Context, req *vtctldatapb.ImportCreateRequest) error {
+	em := &topodatapb.ExternalMysql{
+		Host:     req.SourceHost,
+		Port:     req.SourcePort,
+		User:     req.SourceUser,
+		Password: req.SourcePassword,
+		DbName:   req.SourceDbName,
+		Keyspace: req.TargetKeyspace,
+		Workflow: req.Workflow,
+	}
+	if em.Port == 0 {
+		em.Port = defaultImportSourcePort
+	}
+	if err := s.ts.CreateExternalMysql(ctx, req.ExternalMysql, em); err != nil {
+		if topo.IsErrType(err, topo.NodeExists) {
+			return vterrors.Errorf(vtrpcpb.Code_ALREADY_EXISTS, "the external mysql %s is already registered in the topo", req.ExternalMysql)
+		}
+		return vterrors.Wrapf(err, "failed to register the external mysql %s in the topo", req.ExternalMysql)
+	}
+	return nil
+}
+
+// unregisterImportExternalMysql removes the external mysql from the topo if
+// it was registered there by the given workflow. The external mysqls that
+// are configured in the externalConnections of the tablets are left alone.
+func (s *Server) unregisterImportExternalMysql(ctx context.Context, externalMysql, keyspace, workflow string) error {
+	em, err := s.ts.GetExternalMysql(ctx, externalMysql)
+	if err != nil {
+		return vterrors.Wrapf(err, "failed to read the external mysql %s from the topo", externalMysql)
+	}
+	if em == nil || em.Keyspace != keyspace || em.Workflow != workflow {
+		return nil
+	}
+	if err := s.ts.DeleteExternalMysql(ctx, externalMysql); err != nil && !topo.IsErrType(err, topo.NoNode) {
+		return vterrors.Wrapf(err, "failed to unregister the external mysql %s from the topo", externalMysql)
+	}
+	return nil
+}
+
+// checkImportSource checks that the primary of each target shard can connect
+// to the external mysql. The external mysql must either be configured in the
+// externalConnections section of the config of each target tablet, or have
+// been registered in the topo with the connection parameters of the request.
+func (s *Server) checkImportSource(ctx context.Context, targets *workflowTargets, externalMysql string) error {
+	return targets.ts.ForAllTargets(func(target *MigrationTarget) error {
+		if _, err := s.executeFetchOnExternalMysql(ctx, target.primary, externalMysql, sqlImportReadOnly); err != nil {
+			return vterrors.Wrapf(err, "the external mysql %s must be configured in the externalConnections of the config of every target primary tablet, or its connection parameters passed, and be reachable from every target primary tablet",
+				externalMysql)
+		}
+		return nil
+	})
+}
+
+// getImportTables returns the tables to import, based on the tables that
+// exist on the external mysql.
+func (s *Server) getImportTables(ctx context.Context, primary *topo.TabletInfo, req *vtctldatapb.ImportCreateRequest) ([]string, error) {
+	qr, err := s.executeFetchOnExternalMysql(ctx, primary, req.ExternalMysql, sqlImportListTables)
+	if err != nil {
+		return nil, err
+	}
+	sourceTables := make([]string, 0, len(qr.Rows))
+	for _, row := range qr.Rows {
+		sourceTables = append(sourceTables, row[0].ToString())
+	}
+	tables := req.IncludeTables
+	switch {
+	case len(tables) > 0:
+		if err := validateSourceTablesExist(req.ExternalMysql, sourceTables, tables); err != nil {
+			return nil, err
+		}
+	case req.AllTables:
+		tables = sourceTables
+	default:
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no tables to import")
+	}
+	if len(req.ExcludeTables) > 0 {
+		if err := validateSourceTablesExist(req.ExternalMysql, sourceTables, req.ExcludeTables); err != nil {
+			return nil, err
+		}
+	}
+	var included []string
+	for _, table := range tables {
+		if shouldInclude(table, req.ExcludeTables) {
+			included = append(included, table)
+		}
+	}
+	if len(included) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no tables to import")
+	}
+	for table := range req.TableFilters {
+		if !slices.Contains(included, table) {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a filter was specified for table %s which is not being imported", table)
+		}
+	}
+	return included, nil
+}
+
+// deployImportSchema creates the tables that do not yet exist on the target
+// shard, and ensures that the ones that do exist are empty.
+func (s *Server) deployImportSchema(ctx context.Context, target *MigrationTarget, tables []string, createDDLs map[string]string) error {
+	schema, err := s.tmc.GetSchema(ctx, target.primary.Tablet, &tabletmanagerdatapb.GetSchemaRequest{Tables: tables})
+	if err != nil {
+		return err
+	}
+	hasTable := make(map[string]bool)
+	for _, td := range schema.GetTableDefinitions() {
+		hasTable[td.Name] = true
+	}
+	var ddls []string
+	for _, table := range tables {
+		if !hasTable[table] {
+			ddls = append(ddls, createDDLs[table])
+			continue
+		}
+		query := fmt.Sprintf(getNonEmptyTableQuery, sqlescape.EscapeID(table))
+		res, err := s.tmc.ExecuteFetchAsDba(ctx, target.primary.Tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+			Query:   []byte(query),
+			DbName:  target.primary.DbName(),
+			MaxRows: 1,
+		})
+		if err != nil {
+			return err
+		}
+		if len(res.Rows) > 0 {
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s already exists and is not empty on shard %s", table, target.si.ShardName())
+		}
+	}
+	if len(ddls) == 0 {
+		return nil
+	}
+	_, err = s.tmc.ApplySchema(ctx, target.primary.Tablet, &tmutils.SchemaChange{
+		SQL:                     strings.Join(ddls, ";\n"),
+		Force:                   false,
+		AllowReplication:        true,
+		SQLMode:                 vreplication.SQLMode,
+		DisableForeignKeyChecks: true,
+	})
+	return err
+}
+
+// buildImportRule returns the rule used to stream the table from the external
+// mysql to the target shard. As the external mysql knows nothing about the
+// target keyspace's vschema, the rows that belong to the target shard are
+// selected using the vindex type rather than its name.
+func buildImportRule(ksSchema *vindexes.KeyspaceSchema, vschema *vschemapb.Keyspace, table, filter string, keyRange *topodatapb.KeyRange, parser *sqlparser.Parser) (*binlogdatapb.Rule, error) {
+	query := "select * from " + sqlescape.EscapeID(table)
+	if filter != "" {
+		query += " where " + filter
+	}
+	stmt, err := parser.Parse(query)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "invalid filter for table %s", table)
+	}
+	sel, ok := stmt.(*sqlparser.Select)
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid filter for table %s: %s", table, filter)
+	}
+	if ksSchema.Keyspace.Sharded {
+		vtable, ok := ksSchema.Tables[table]
+		if !ok {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s is not in the vschema of the sharded keyspace %s, it must be added with its primary vindex before it can be imported",
+				table, ksSchema.Keyspace.Name)
+		}
+		if vtable.Type != vindexes.TypeReference {
+			cv, err := vindexes.FindBestColVindex(vtable)
+			if err != nil {
+				return nil, err
+			}
+			if vindex := vschema.Vindexes[cv.Name]; vindex == nil || len(vindex.Params) > 0 {
+				return nil, vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "the primary vindex %s of table %s has parameters, which is not supported when importing",
+					cv.Name, table)
+			}
+			exprs := make([]sqlparser.Expr, 0, len(cv.Columns)+2)
+			for _, col := range cv.Columns {
+				exprs = append(exprs, sqlparser.NewColName(col.String()))
+			}
+			exprs = append(exprs, sqlparser.NewStrLiteral(cv.Type), sqlparser.NewStrLiteral(key.KeyRangeString(keyRange)))
+			addFilter(sel, sqlparser.NewFuncExpr("in_keyrange", exprs...))
+		}
+	}
+	return &binlogdatapb.Rule{
+		Match:  table,
+		Filter: sqlparser.String(sel),
+	}, nil
+}
+
+// getImportSourcePosition returns the current GTID position of the external
+// mysql. When checkWriteLocked is set, it returns an error if writes are not
+// locked on it.
+func (s *Server) getImportSourcePosition(ctx context.Context, primary *topo.TabletInfo, externalMysql string, checkWriteLocked bool) (string, error) {
+	qr, err := s.executeFetchOnExternalMysql(ctx, primary, externalMysql, sqlImportReadOnly)
+	if err != nil {
+		return "", err
+	}
+	if len(qr.Rows) != 1 || len(qr.Rows[0]) != 2 {
+		return "", vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unexpected result for %s: %v", sqlImportReadOnly, qr.Rows)
+	}
+	if readOnly, _ := qr.Rows[0][0].ToBool(); checkWriteLocked && !readOnly {
+		return "", vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "writes are not locked on the external mysql %s, set read_only=ON on it before completing the import",
+			externalMysql)
+	}
+	flavor, query := replication.Mysql56FlavorID, sqlImportMySQLGTIDs
+	if strings.Contains(strings.ToLower(qr.Rows[0][1].ToString()), "mariadb") {
+		flavor, query = replication.MariadbFlavorID, sqlImportMariaDBGTID
+	}
+	qr, err = s.executeFetchOnExternalMysql(ctx, primary, externalMysql, query)
+	if err != nil {
+		return "", err
+	}
+	if len(qr.Rows) != 1 || len(qr.Rows[0]) != 1 {
+		return "", vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unexpected result for %s: %v", query, qr.Rows)
+	}
+	pos, err := replication.ParsePosition(flavor, strings.ReplaceAll(qr.Rows[0][0].ToString(), "\n", ""))
+	if err != nil {
+		return "", vterrors.Wrapf(err, "failed to parse the position of the external mysql %s", externalMysql)
+	}
+	return replication.EncodePosition(pos), nil
+}
+
+func (s *Server) executeFetchOnExternalMysql(ctx context.Context, primary *topo.TabletInfo, externalMysql, query string) (*sqltypes.Result, error) {
+	res, err := s.tmc.ExecuteFetchOnExternalMysql(ctx, primary.Tablet, &tabletmanagerdatapb.ExecuteFetchOnExternalMysqlRequest{
+		ExternalMysql: externalMysql,
+		Query:         []byte(query),
+	})
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to execute %q on the external mysql %s using tablet %s", query, externalMysql, primary.AliasString())
+	}
+	return sqltypes.Proto3ToResult(res.GetResult()), nil
+}
+
+// getImportExternalMysql returns the external mysql that the workflow's
+// streams replicate from.
+func getImportExternalMysql(targets *workflowTargets) (string, error) {
+	externalMysql := ""
+	for _, shard := range targets.shards {
+		for _, stream := range targets.streams[shard] {
+			name := stream.GetBls().GetExternalMysql()
+			switch {
+			case name == "":
+				return "", vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "stream %d on shard %s does not replicate from an external mysql", stream.Id, shard)
+			case externalMysql != "" && name != externalMysql:
+				return "", vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the workflow's streams replicate from more than one external mysql: %s and %s", externalMysql, name)
+			}
+			externalMysql = name
+		}
+	}
+	if externalMysql == "" {
+		return "", vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "no streams found for the workflow")
+	}
+	return externalMysql, nil
+}
+
+// getImportStreamTables returns the tables that the streams replicate.
+func getImportStreamTables(streams []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream) []string {
+	var tables []string
+	for _, stream := range streams {
+		for _, rule := range stream.GetBls().GetFilter().GetRules() {
+			if !slices.Contains(tables, rule.Match) {
+				tables = append(tables, rule.Match)
+			}
+		}
+	}
+	sort.Strings(tables)
+	return tables
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func TestBuildImportRule(t *testing.T) {
+	parser := sqlparser.NewTestParser()
+	vschema := &vschemapb.Keyspace{
+		Sharded: true,
+		Vindexes: map[string]*vschemapb.Vindex{
+			"xxhash": {Type: "xxhash"},
+			"salted": {Type: "unicode_loose_xxhash", Params: map[string]string{"salt": "pepper"}},
+		},
+		Tables: map[string]*vschemapb.Table{
+			"t1":  {ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "id", Name: "xxhash"}}},
+			"t2":  {ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "c1", Name: "salted"}}},
+			"ref": {Type: vindexes.TypeReference},
+		},
+	}
+	ksSchema, err := vindexes.BuildKeyspaceSchema(vschema, "ks", parser)
+	require.NoError(t, err)
+	_, keyRange, err := topo.ValidateShardName("-80")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name    string
+		table   string
+		filter  string
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "sharded table",
+			table: "t1",
+			want:  "select * from t1 where in_keyrange(id, 'xxhash', '-80')",
+		},
+		{
+			name:   "sharded table with filter",
+			table:  "t1",
+			filter: "created_at >= '2024-01-01'",
+			want:   "select * from t1 where in_keyrange(id, 'xxhash', '-80') and created_at >= '2024-01-01'",
+		},
+		{
+			name:  "reference table",
+			table: "ref",
+			want:  "select * from ref",
+		},
+		{
+			name:    "vindex with params",
+			table:   "t2",
+			wantErr: "the primary vindex salted of table t2 has parameters",
+		},
+		{
+			name:    "table not in vschema",
+			table:   "t3",
+			wantErr: "table t3 is not in the vschema of the sharded keyspace ks",
+		},
+		{
+			name:    "invalid filter",
+			table:   "t1",
+			filter:  "id in (",
+			wantErr: "invalid filter for table t1",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := buildImportRule(ksSchema, vschema, tc.table, tc.filter, keyRange, parser)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.table, rule.Match)
+			require.Equal(t, tc.want, rule.Filter)
+		})
+	}
+
+	// Tables in an unsharded keyspace are copied in full.
+	unsharded, err := vindexes.BuildKeyspaceSchema(&vschemapb.Keyspace{}, "uks", parser)
+	require.NoError(t, err)
+	rule, err := buildImportRule(unsharded, &vschemapb.Keyspace{}, "t1", "", nil, parser)
+	require.NoError(t, err)
+	require.Equal(t, "select * from t1", rule.Filter)
+}
+
+func TestGetImportStreamInfo(t *testing.T) {
+	stream := func(id int32, externalMysql string, tables ...string) *tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream {
+		filter := &binlogdatapb.Filter{}
+		for _, table := range tables {
+			filter.Rules = append(filter.Rules, &binlogdatapb.Rule{Match: table, Filter: "select * from " + table})
+		}
+		return &tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream{Id: id, Bls: &binlogdatapb.BinlogSource{ExternalMysql: externalMysql, Filter: filter}}
+	}
+	// targets returns the targets of the -80 and 80- shards, with one of the
+	// streams on each of them.
+	targets := func(streams ...*tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream) *workflowTargets {
+		wt := &workflowTargets{streams: make(map[string][]*tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream)}
+		for i, stream := range streams {
+			shard := []string{"-80", "80-"}[i]
+			wt.shards = append(wt.shards, shard)
+			wt.streams[shard] = append(wt.streams[shard], stream)
+		}
+		return wt
+	}
+
+	wt := targets(stream(1, "legacy", "t2", "t1"), stream(1, "legacy", "t1", "t2"))
+	externalMysql, err := getImportExternalMysql(wt)
+	require.NoError(t, err)
+	require.Equal(t, "legacy", externalMysql)
+	require.Equal(t, []string{"t1", "t2"}, getImportStreamTables(wt.streams["-80"]))
+
+	_, err = getImportExternalMysql(targets(stream(1, "legacy", "t1"), stream(1, "other", "t1")))
+	require.ErrorContains(t, err, "more than one external mysql: legacy and other")
+	_, err = getImportExternalMysql(targets(stream(1, "", "t1")))
+	require.ErrorContains(t, err, "stream 1 on shard -80 does not replicate from an external mysql")
+}
+
+// importSourceTMClient fails the queries on the external mysql that are
+// sent to the tablets that do not have it in their config.
+type importSourceTMClient struct {
+	tmclient.TabletManagerClient
+	unconfigured map[uint32]bool
+}
+
+func (tmc *importSourceTMClient) ExecuteFetchOnExternalMysql(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.ExecuteFetchOnExternalMysqlRequest) (*tabletmanagerdatapb.ExecuteFetchOnExternalMysqlResponse, error) {
+	if tmc.unconfigured[tablet.Alias.Uid] {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "external mysql %v not found in the externalConnections of the tablet config", req.ExternalMysql)
+	}
+	return &tabletmanagerdatapb.ExecuteFetchOnExternalMysqlResponse{}, nil
+}
+
+func TestCheckImportSource(t *testing.T) {
+	ctx := context.Background()
+	target := func(shard string, uid uint32) *MigrationTarget {
+		return &MigrationTarget{
+			si:      topo.NewShardInfo("ks", shard, &topodatapb.Shard{}, nil),
+			primary: &topo.TabletInfo{Tablet: &topodatapb.Tablet{Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: uid}}},
+		}
+	}
+	targets := &workflowTargets{
+		ts: &trafficSwitcher{
+			targets: map[string]*MigrationTarget{"-80": target("-80", 100), "80-": target("80-", 200)},
+		},
+		shards: []string{"-80", "80-"},
+	}
+
+	tmc := &importSourceTMClient{}
+	s := &Server{tmc: tmc}
+	require.NoError(t, s.checkImportSource(ctx, targets, "legacy"))
+
+	// The check fails if any of the target primaries cannot connect to the
+	// external mysql, not just the one that is used to read its schema.
+	tmc.unconfigured = map[uint32]bool{200: true}
+	err := s.checkImportSource(ctx, targets, "legacy")
+	require.ErrorContains(t, err, "the external mysql legacy must be configured in the externalConnections of the config of every target primary tablet")
+	require.ErrorContains(t, err, "zone1-0000000200")
+	require.NotContains(t, err.Error(), "zone1-0000000100")
+}
+
+func TestImportExternalMysqlRegistration(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+	s := &Server{ts: ts}
+
+	req := &vtctldatapb.ImportCreateRequest{
+		Workflow:       "wf",
+		TargetKeyspace: "ks",
+		ExternalMysql:  "legacy",
+		SourceHost:     "legacy.example.com",
+		SourceUser:     "vt_import",
+		SourceDbName:   "legacy",
+	}
+	require.NoError(t, s.registerImportExternalMysql(ctx, req))
+	em, err := ts.GetExternalMysql(ctx, "legacy")
+	require.NoError(t, err)
+	require.Equal(t, &topodatapb.ExternalMysql{
+		Host:     "legacy.example.com",
+		Port:     defaultImportSourcePort,
+		User:     "vt_import",
+		DbName:   "legacy",
+		Keyspace: "ks",
+		Workflow: "wf",
+	}, em)
+
+	// Another workflow cannot register the same external mysql.
+	err = s.registerImportExternalMysql(ctx, &vtctldatapb.ImportCreateRequest{
+		Workflow:       "wf2",
+		TargetKeyspace: "ks",
+		ExternalMysql:  "legacy",
+		SourceHost:     "other.example.com",
+	})
+	require.ErrorContains(t, err, "the external mysql legacy is already registered in the topo")
+
+	// Only the workflow that registered the external mysql unregisters it.
+	require.NoError(t, s.unregisterImportExternalMysql(ctx, "legacy", "ks", "wf2"))
+	em, err = ts.GetExternalMysql(ctx, "legacy")
+	require.NoError(t, err)
+	require.NotNil(t, em)
+	require.NoError(t, s.unregisterImportExternalMysql(ctx, "legacy", "ks", "wf"))
+	em, err = ts.GetExternalMysql(ctx, "legacy")
+	require.NoError(t, err)
+	require.Nil(t, em)
+
+	// The external mysqls that are not in the topo are left alone.
+	require.NoError(t, s.unregisterImportExternalMysql(ctx, "legacy", "ks", "wf"))
+}
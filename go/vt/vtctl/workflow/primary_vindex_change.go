@@ -0,0 +1,1518 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/trace"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/topotools"
+	"vitess.io/vitess/go/vt/vtctl/schematools"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vdiff"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	// primaryVindexChangeShadowTableFormat is the name of the table that the
+	// rows of a table, or of one of its lookup tables, are copied into while
+	// its primary vindex is changed.
+	primaryVindexChangeShadowTableFormat = "%.60s_pvc"
+
+	// defaultChangePrimaryVindexSwitchTimeout is how long we wait, by default,
+	// for the workflow to catch up once queries to the table are denied.
+	defaultChangePrimaryVindexSwitchTimeout = 30 * time.Second
+
+	// primaryVindexChangeStoppedMessage is the message of the streams that
+	// were stopped to switch traffic.
+	primaryVindexChangeStoppedMessage = "stopped to switch traffic to the other primary vindex"
+)
+
+// primaryVindexChange is a ChangePrimaryVindex workflow, which copies a table
+// into a shadow table in the same keyspace that is sharded by the new primary
+// vindex, while a workflow for each of the table's owned lookup vindexes
+// copies the rows of the shadow table into a shadow lookup table. Once traffic
+// is switched, the shadow tables replace the tables, which are renamed, and
+// reverse workflows keep them up to date.
+type primaryVindexChange struct {
+	keyspace string
+	workflow string
+	table    string
+	shadow   string
+	// old is the name of the original table once traffic is switched.
+	old            string
+	targets        *workflowTargets
+	reverseTargets *workflowTargets
+	lookups        []*primaryVindexChangeLookup
+}
+
+// primaryVindexChangeLookup is an owned lookup vindex that is recreated with
+// the keyspace ids of the new primary vindex.
+type primaryVindexChangeLookup struct {
+	vindex   string
+	keyspace string
+	table    string
+	shadow   string
+	workflow string
+	old      string
+	// query is the query that selects the rows of the lookup table from the
+	// shadow table of the owner table, and reverseQuery the one that selects
+	// them from the original owner table once traffic is switched.
+	query          string
+	reverseQuery   string
+	targets        *workflowTargets
+	reverseTargets *workflowTargets
+}
+
+// ChangePrimaryVindexCreate is part of the vtctlservicepb.VtctldServer
+// interface. It creates a workflow that copies a table into a shadow table in
+// the same keyspace, which is sharded by the new primary vindex, and keeps it
+// up to date. The table's owned lookup vindexes are recreated along the way,
+// using the keyspace ids of the new primary vindex.
+func (s *Server) ChangePrimaryVindexCreate(ctx context.Context, req *vtctldatapb.ChangePrimaryVindexCreateRequest) (resp *vtctldatapb.ChangePrimaryVindexCreateResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.ChangePrimaryVindexCreate")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("table", req.Table)
+	span.Annotate("cells", req.Cells)
+	span.Annotate("tablet_types", req.TabletTypes)
+
+	if req.Table == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a table must be specified")
+	}
+	if req.PrimaryVindex == nil || req.PrimaryVindex.Name == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a primary vindex must be specified")
+	}
+	if err := validateNewWorkflow(ctx, s.ts, s.tmc, req.Keyspace, req.Workflow); err != nil {
+		return nil, err
+	}
+
+	lockName := fmt.Sprintf("%s/%s", req.Keyspace, req.Workflow)
+	ctx, workflowUnlock, lockErr := s.ts.LockName(ctx, lockName, "ChangePrimaryVindexCreate")
+	if lockErr != nil {
+		return nil, vterrors.Wrapf(lockErr, "failed to lock the %s workflow", lockName)
+	}
+	defer workflowUnlock(&err)
+
+	vschema, err := s.ts.GetVSchema(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+	origVSchemas := map[string]*vschemapb.Keyspace{req.Keyspace: vschema.Keyspace.CloneVT()}
+	shadow := fmt.Sprintf(primaryVindexChangeShadowTableFormat, req.Table)
+	if err := addPrimaryVindexChangeVindexes(vschema.Keyspace, req.Vindexes); err != nil {
+		return nil, err
+	}
+	primaryVindex, err := validatePrimaryVindexChange(vschema.Keyspace, req.Keyspace, req.Table, shadow, req.PrimaryVindex)
+	if err != nil {
+		return nil, err
+	}
+	lookups, err := getPrimaryVindexChangeLookups(vschema.Keyspace, req.Keyspace, req.Workflow, req.Table, shadow)
+	if err != nil {
+		return nil, err
+	}
+
+	createDDL, err := s.getPrimaryVindexChangeShadowDDL(ctx, req.Keyspace, req.Table, shadow)
+	if err != nil {
+		return nil, err
+	}
+	vschema.Tables[shadow] = &vschemapb.Table{ColumnVindexes: []*vschemapb.ColumnVindex{primaryVindex}}
+
+	// The shadow lookup tables must be in the vschema of their keyspace, with
+	// the same vindexes as the lookup tables, before they are materialized.
+	vschemas := map[string]*topo.KeyspaceVSchemaInfo{req.Keyspace: vschema}
+	lookupDDLs := make(map[string]string, len(lookups))
+	for _, lookup := range lookups {
+		lvschema, ok := vschemas[lookup.keyspace]
+		if !ok {
+			if lvschema, err = s.ts.GetVSchema(ctx, lookup.keyspace); err != nil {
+				return nil, err
+			}
+			vschemas[lookup.keyspace] = lvschema
+			origVSchemas[lookup.keyspace] = lvschema.Keyspace.CloneVT()
+		}
+		if _, ok := lvschema.Tables[lookup.shadow]; ok {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s already exists in the vschema of the %s keyspace", lookup.shadow, lookup.keyspace)
+		}
+		if lvschema.Sharded {
+			ltable, ok := lvschema.Tables[lookup.table]
+			if !ok {
+				return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "lookup table %s of vindex %s is not in the vschema of the sharded %s keyspace",
+					lookup.table, lookup.vindex, lookup.keyspace)
+			}
+			lvschema.Tables[lookup.shadow] = ltable.CloneVT()
+		}
+		if lookupDDLs[lookup.vindex], err = s.getPrimaryVindexChangeShadowDDL(ctx, lookup.keyspace, lookup.table, lookup.shadow); err != nil {
+			return nil, err
+		}
+	}
+
+	restoreVSchemas := func(err error) error {
+		for ks, vschema := range vschemas {
+			vschema.Keyspace = origVSchemas[ks]
+			if serr := s.ts.SaveVSchema(ctx, vschema); serr != nil {
+				return vterrors.Wrapf(err, "failed to restore the original vschema of the %s keyspace: %v", ks, serr)
+			}
+		}
+		if serr := s.ts.RebuildSrvVSchema(ctx, nil); serr != nil {
+			return vterrors.Wrapf(err, "failed to rebuild the SrvVSchema: %v", serr)
+		}
+		return err
+	}
+	for _, vschema := range vschemas {
+		if err := s.ts.SaveVSchema(ctx, vschema); err != nil {
+			return nil, restoreVSchemas(err)
+		}
+	}
+	if err := s.ts.RebuildSrvVSchema(ctx, nil); err != nil {
+		return nil, restoreVSchemas(err)
+	}
+
+	lookupVindexes := make([]string, 0, len(lookups))
+	for _, lookup := range lookups {
+		lookupVindexes = append(lookupVindexes, lookup.vindex)
+	}
+	ms := &vtctldatapb.MaterializeSettings{
+		Workflow:       req.Workflow,
+		SourceKeyspace: req.Keyspace,
+		TargetKeyspace: req.Keyspace,
+		TableSettings: []*vtctldatapb.TableMaterializeSettings{{
+			TargetTable:      shadow,
+			SourceExpression: "select * from " + sqlescape.EscapeID(req.Table),
+			CreateDdl:        createDDL,
+		}},
+		Cell:                      strings.Join(req.Cells, ","),
+		TabletTypes:               topoproto.MakeStringTypeCSV(req.TabletTypes),
+		TabletSelectionPreference: req.TabletSelectionPreference,
+		MaterializationIntent:     vtctldatapb.MaterializationIntent_CUSTOM,
+		DeferSecondaryKeys:        req.DeferSecondaryKeys,
+		WorkflowOptions:           &vtctldatapb.WorkflowOptions{LookupVindexes: lookupVindexes},
+	}
+	if err := s.Materialize(ctx, ms); err != nil {
+		return nil, restoreVSchemas(err)
+	}
+	for _, lookup := range lookups {
+		lms := &vtctldatapb.MaterializeSettings{
+			Workflow:       lookup.workflow,
+			SourceKeyspace: req.Keyspace,
+			TargetKeyspace: lookup.keyspace,
+			TableSettings: []*vtctldatapb.TableMaterializeSettings{{
+				TargetTable:      lookup.shadow,
+				SourceExpression: lookup.query,
+				CreateDdl:        lookupDDLs[lookup.vindex],
+			}},
+			Cell:                      ms.Cell,
+			TabletTypes:               ms.TabletTypes,
+			TabletSelectionPreference: req.TabletSelectionPreference,
+			MaterializationIntent:     vtctldatapb.MaterializationIntent_CUSTOM,
+		}
+		if err := s.Materialize(ctx, lms); err != nil {
+			return nil, vterrors.Wrapf(err, "failed to create the %s workflow for the lookup vindex %s, cancel the %s workflow to clean up",
+				lookup.workflow, lookup.vindex, req.Workflow)
+		}
+	}
+	return &vtctldatapb.ChangePrimaryVindexCreateResponse{
+		ShadowTable:    shadow,
+		LookupVindexes: lookupVindexes,
+	}, nil
+}
+
+// ChangePrimaryVindexSwitchTraffic is part of the vtctlservicepb.VtctldServer
+// interface. It denies queries to the table and its lookup tables on all of
+// their tablets, waits for the workflows to catch up and stops them, swaps
+// the tables with their shadow tables, and makes the new vindex the table's
+// primary vindex in the vschema. Reverse workflows then keep the original
+// tables up to date, so that traffic can be switched back, before queries are
+// allowed again. Reads as well as writes fail while the tables are swapped,
+// as the tablets and the vschema are not switched at the same time. When any
+// of these steps fails, the switch is rolled back.
+func (s *Server) ChangePrimaryVindexSwitchTraffic(ctx context.Context, req *vtctldatapb.ChangePrimaryVindexSwitchTrafficRequest) (resp *vtctldatapb.ChangePrimaryVindexSwitchTrafficResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.ChangePrimaryVindexSwitchTraffic")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("skip_vdiff", req.SkipVdiff)
+
+	timeout := defaultChangePrimaryVindexSwitchTimeout
+	if req.Timeout != nil {
+		if timeout, _, err = protoutil.DurationFromProto(req.Timeout); err != nil {
+			return nil, vterrors.Wrapf(err, "invalid timeout")
+		}
+	}
+
+	lockName := fmt.Sprintf("%s/%s", req.Keyspace, req.Workflow)
+	ctx, workflowUnlock, lockErr := s.ts.LockName(ctx, lockName, "ChangePrimaryVindexSwitchTraffic")
+	if lockErr != nil {
+		return nil, vterrors.Wrapf(lockErr, "failed to lock the %s workflow", lockName)
+	}
+	defer workflowUnlock(&err)
+
+	pvc, err := s.getPrimaryVindexChange(ctx, req.Keyspace, req.Workflow, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, ks := range pvc.keyspaces() {
+		lockCtx, keyspaceUnlock, lockErr := s.ts.LockKeyspace(ctx, ks, "ChangePrimaryVindexSwitchTraffic")
+		if lockErr != nil {
+			return nil, vterrors.Wrapf(lockErr, "failed to lock the %s keyspace", ks)
+		}
+		ctx = lockCtx
+		defer keyspaceUnlock(&err)
+	}
+
+	if pvc.switched() {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "traffic of workflow %s has already been switched, use reversetraffic to switch it back", pvc.workflow)
+	}
+	if err := pvc.checkStreamsRunning(); err != nil {
+		return nil, err
+	}
+	if !req.SkipVdiff {
+		if err := s.checkPrimaryVindexChangeVDiff(ctx, pvc); err != nil {
+			return nil, err
+		}
+	}
+
+	// Until the workflows are stopped, nothing has changed and queries can
+	// be allowed again if we cannot go on.
+	allowQueries := func(err error) error {
+		if aerr := s.denyPrimaryVindexChangeQueries(ctx, pvc, true); aerr != nil {
+			return vterrors.Wrapf(err, "failed to allow queries to table %s again: %v", pvc.table, aerr)
+		}
+		return err
+	}
+	if err := s.denyPrimaryVindexChangeQueries(ctx, pvc, false); err != nil {
+		return nil, allowQueries(err)
+	}
+	waitCtx, waitCancel := context.WithTimeout(ctx, timeout)
+	defer waitCancel()
+	if err := s.waitForPrimaryVindexChangeStreams(waitCtx, pvc.keyspace, pvc.targets); err != nil {
+		return nil, allowQueries(err)
+	}
+	// The lookup workflows stream from the shadow table, so we only know how
+	// far they have to catch up once the main workflow has.
+	for _, lookup := range pvc.lookups {
+		if err := s.waitForPrimaryVindexChangeStreams(waitCtx, pvc.keyspace, lookup.targets); err != nil {
+			return nil, allowQueries(err)
+		}
+	}
+
+	err = s.switchPrimaryVindexChange(ctx, pvc)
+	if err == nil {
+		err = s.denyPrimaryVindexChangeQueries(ctx, pvc, true)
+	}
+	if err != nil {
+		return nil, s.rollbackPrimaryVindexChange(ctx, pvc, timeout, err)
+	}
+	return &vtctldatapb.ChangePrimaryVindexSwitchTrafficResponse{
+		Summary: fmt.Sprintf("Successfully switched table %s in the %s keyspace to its new primary vindex", pvc.table, pvc.keyspace),
+	}, nil
+}
+
+// ChangePrimaryVindexReverseTraffic is part of the vtctlservicepb.VtctldServer
+// interface. It denies queries to the table and its lookup tables, waits for
+// the reverse workflows to catch up, and switches the tables back to the
+// original ones, whose primary vindex is again the table's primary vindex in
+// the vschema. The workflows are then restarted before queries are allowed
+// again.
+func (s *Server) ChangePrimaryVindexReverseTraffic(ctx context.Context, req *vtctldatapb.ChangePrimaryVindexReverseTrafficRequest) (resp *vtctldatapb.ChangePrimaryVindexReverseTrafficResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.ChangePrimaryVindexReverseTraffic")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("workflow", req.Workflow)
+
+	timeout := defaultChangePrimaryVindexSwitchTimeout
+	if req.Timeout != nil {
+		if timeout, _, err = protoutil.DurationFromProto(req.Timeout); err != nil {
+			return nil, vterrors.Wrapf(err, "invalid timeout")
+		}
+	}
+
+	lockName := fmt.Sprintf("%s/%s", req.Keyspace, req.Workflow)
+	ctx, workflowUnlock, lockErr := s.ts.LockName(ctx, lockName, "ChangePrimaryVindexReverseTraffic")
+	if lockErr != nil {
+		return nil, vterrors.Wrapf(lockErr, "failed to lock the %s workflow", lockName)
+	}
+	defer workflowUnlock(&err)
+
+	pvc, err := s.getPrimaryVindexChange(ctx, req.Keyspace, req.Workflow, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, ks := range pvc.keyspaces() {
+		lockCtx, keyspaceUnlock, lockErr := s.ts.LockKeyspace(ctx, ks, "ChangePrimaryVindexReverseTraffic")
+		if lockErr != nil {
+			return nil, vterrors.Wrapf(lockErr, "failed to lock the %s keyspace", ks)
+		}
+		ctx = lockCtx
+		defer keyspaceUnlock(&err)
+	}
+
+	if !pvc.switched() {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "traffic of workflow %s has not been switched", pvc.workflow)
+	}
+	// Queries are still denied when an earlier switch could neither be
+	// completed nor rolled back, in which case they must stay denied until
+	// the table has been switched back.
+	queriesDenied := pvc.queriesDenied()
+	allowQueries := func(err error) error {
+		if queriesDenied {
+			return err
+		}
+		if aerr := s.denyPrimaryVindexChangeQueries(ctx, pvc, true); aerr != nil {
+			return vterrors.Wrapf(err, "failed to allow queries to table %s again: %v", pvc.table, aerr)
+		}
+		return err
+	}
+	if err := s.denyPrimaryVindexChangeQueries(ctx, pvc, false); err != nil {
+		return nil, allowQueries(err)
+	}
+	if err := s.waitForPrimaryVindexChangeReverseStreams(ctx, pvc, timeout); err != nil {
+		return nil, allowQueries(err)
+	}
+	if err := s.switchBackPrimaryVindexChange(ctx, pvc); err != nil {
+		return nil, vterrors.Wrapf(err, "failed to switch traffic back, queries to table %s are denied until reversetraffic succeeds", pvc.table)
+	}
+	if err := s.denyPrimaryVindexChangeQueries(ctx, pvc, true); err != nil {
+		return nil, err
+	}
+	return &vtctldatapb.ChangePrimaryVindexReverseTrafficResponse{
+		Summary: fmt.Sprintf("Successfully switched table %s in the %s keyspace back to its original primary vindex", pvc.table, pvc.keyspace),
+	}, nil
+}
+
+// ChangePrimaryVindexComplete is part of the vtctlservicepb.VtctldServer
+// interface. Once traffic has been switched, it removes the workflows and the
+// reverse workflows, along with the original tables unless asked to keep them.
+func (s *Server) ChangePrimaryVindexComplete(ctx context.Context, req *vtctldatapb.ChangePrimaryVindexCompleteRequest) (resp *vtctldatapb.ChangePrimaryVindexCompleteResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.ChangePrimaryVindexComplete")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("keep_data", req.KeepData)
+
+	lockName := fmt.Sprintf("%s/%s", req.Keyspace, req.Workflow)
+	ctx, workflowUnlock, lockErr := s.ts.LockName(ctx, lockName, "ChangePrimaryVindexComplete")
+	if lockErr != nil {
+		return nil, vterrors.Wrapf(lockErr, "failed to lock the %s workflow", lockName)
+	}
+	defer workflowUnlock(&err)
+
+	pvc, err := s.getPrimaryVindexChange(ctx, req.Keyspace, req.Workflow, false)
+	if err != nil {
+		return nil, err
+	}
+	if !pvc.switched() {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "traffic of workflow %s has not been switched, use cancel to remove it", pvc.workflow)
+	}
+	// The reverse workflows are created on every shard before queries are
+	// allowed again, which is the last step of a successful switch.
+	if pvc.queriesDenied() || !pvc.hasReverseStreams() {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "traffic of workflow %s was not completely switched, use reversetraffic to switch it back", pvc.workflow)
+	}
+
+	if err := pvc.reverseTargets.ts.dropTargetVReplicationStreams(ctx); err != nil {
+		return nil, err
+	}
+	for _, lookup := range pvc.lookups {
+		if err := lookup.reverseTargets.ts.dropTargetVReplicationStreams(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if err := pvc.targets.ts.dropTargetVReplicationStreams(ctx); err != nil {
+		return nil, err
+	}
+	for _, lookup := range pvc.lookups {
+		if err := lookup.targets.ts.dropTargetVReplicationStreams(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if !req.KeepData {
+		if err := s.dropPrimaryVindexChangeTable(ctx, pvc.targets, pvc.old); err != nil {
+			return nil, err
+		}
+		for _, lookup := range pvc.lookups {
+			if err := s.dropPrimaryVindexChangeTable(ctx, lookup.targets, lookup.old); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := s.removePrimaryVindexChangeVSchemaTables(ctx, pvc, true); err != nil {
+		return nil, err
+	}
+	return &vtctldatapb.ChangePrimaryVindexCompleteResponse{
+		Summary: fmt.Sprintf("Successfully completed the %s workflow in the %s keyspace", req.Workflow, req.Keyspace),
+	}, nil
+}
+
+// ChangePrimaryVindexCancel is part of the vtctlservicepb.VtctldServer
+// interface. It removes the workflow and its lookup workflows, along with
+// the shadow tables unless asked to keep them.
+func (s *Server) ChangePrimaryVindexCancel(ctx context.Context, req *vtctldatapb.ChangePrimaryVindexCancelRequest) (resp *vtctldatapb.ChangePrimaryVindexCancelResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.ChangePrimaryVindexCancel")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("keep_data", req.KeepData)
+
+	lockName := fmt.Sprintf("%s/%s", req.Keyspace, req.Workflow)
+	ctx, workflowUnlock, lockErr := s.ts.LockName(ctx, lockName, "ChangePrimaryVindexCancel")
+	if lockErr != nil {
+		return nil, vterrors.Wrapf(lockErr, "failed to lock the %s workflow", lockName)
+	}
+	defer workflowUnlock(&err)
+
+	// The lookup workflows may not all exist when the workflow was only
+	// partially created.
+	pvc, err := s.getPrimaryVindexChange(ctx, req.Keyspace, req.Workflow, true)
+	if err != nil {
+		return nil, err
+	}
+	if pvc.switched() {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "traffic of workflow %s has been switched, use reversetraffic to switch it back before cancelling it, or complete to finish it",
+			pvc.workflow)
+	}
+	if err := pvc.targets.ts.dropTargetVReplicationStreams(ctx); err != nil {
+		return nil, err
+	}
+	// The lookup workflows that do not exist have no streams to delete.
+	for _, lookup := range pvc.lookups {
+		if err := lookup.targets.ts.dropTargetVReplicationStreams(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if !req.KeepData {
+		if err := s.dropPrimaryVindexChangeTable(ctx, pvc.targets, pvc.shadow); err != nil {
+			return nil, err
+		}
+		for _, lookup := range pvc.lookups {
+			if err := s.dropPrimaryVindexChangeTable(ctx, lookup.targets, lookup.shadow); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := s.removePrimaryVindexChangeVSchemaTables(ctx, pvc, false); err != nil {
+		return nil, err
+	}
+	return &vtctldatapb.ChangePrimaryVindexCancelResponse{
+		Summary: fmt.Sprintf("Successfully cancelled the %s workflow in the %s keyspace", req.Workflow, req.Keyspace),
+	}, nil
+}
+
+// getPrimaryVindexChange reads the state of a ChangePrimaryVindex workflow
+// from its streams, those of its reverse workflows if any, and from the
+// vschema. When ignoreMissingLookups is set, the lookup workflows that do not
+// exist have targets without streams.
+func (s *Server) getPrimaryVindexChange(ctx context.Context, keyspace, workflow string, ignoreMissingLookups bool) (*primaryVindexChange, error) {
+	targets, err := s.getWorkflowTargets(ctx, keyspace, workflow, binlogdatapb.VReplicationWorkflowType_Materialize)
+	if err != nil {
+		return nil, err
+	}
+	pvc := &primaryVindexChange{
+		keyspace: keyspace,
+		workflow: workflow,
+		targets:  targets,
+	}
+	rules := targets.streams[targets.shards[0]][0].GetBls().GetFilter().GetRules()
+	if len(rules) != 1 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "workflow %s is not a ChangePrimaryVindex workflow", workflow)
+	}
+	sourceTable, err := s.env.Parser().TableFromStatement(rules[0].Filter)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "workflow %s is not a ChangePrimaryVindex workflow", workflow)
+	}
+	pvc.table, pvc.shadow = sourceTable.Name.String(), rules[0].Match
+	if pvc.shadow != fmt.Sprintf(primaryVindexChangeShadowTableFormat, pvc.table) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "workflow %s is not a ChangePrimaryVindex workflow", workflow)
+	}
+	pvc.old = getRenameFileName(pvc.table)
+	// The reverse workflows only exist once traffic has been switched, and
+	// may only exist on some of the shards when the switch failed.
+	if pvc.reverseTargets, err = s.newWorkflowTargets(ctx, keyspace, pvc.reverseWorkflow()); err != nil {
+		return nil, err
+	}
+
+	options := targets.ts.options
+	if len(options.LookupVindexes) == 0 {
+		return pvc, nil
+	}
+	vschema, err := s.ts.GetVSchema(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range options.LookupVindexes {
+		lookup, err := newPrimaryVindexChangeLookup(vschema.Keyspace, keyspace, workflow, pvc.table, pvc.shadow, name)
+		if err != nil {
+			return nil, err
+		}
+		if ignoreMissingLookups {
+			lookup.targets, err = s.newWorkflowTargets(ctx, lookup.keyspace, lookup.workflow)
+		} else {
+			lookup.targets, err = s.getWorkflowTargets(ctx, lookup.keyspace, lookup.workflow, binlogdatapb.VReplicationWorkflowType_Materialize)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if lookup.reverseTargets, err = s.newWorkflowTargets(ctx, lookup.keyspace, lookup.reverseWorkflow()); err != nil {
+			return nil, err
+		}
+		pvc.lookups = append(pvc.lookups, lookup)
+	}
+	return pvc, nil
+}
+
+// keyspaces returns the keyspace of the table and those of its lookup tables,
+// sorted so that they are always locked in the same order.
+func (pvc *primaryVindexChange) keyspaces() []string {
+	keyspaces := []string{pvc.keyspace}
+	for _, lookup := range pvc.lookups {
+		if !slices.Contains(keyspaces, lookup.keyspace) {
+			keyspaces = append(keyspaces, lookup.keyspace)
+		}
+	}
+	sort.Strings(keyspaces)
+	return keyspaces
+}
+
+// switched returns whether traffic was switched, even if only part way, in
+// which case the streams of the workflows were stopped to do so.
+func (pvc *primaryVindexChange) switched() bool {
+	switched := func(targets *workflowTargets) bool {
+		for _, streams := range targets.streams {
+			for _, stream := range streams {
+				if stream.State == binlogdatapb.VReplicationWorkflowState_Stopped && stream.Message == primaryVindexChangeStoppedMessage {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if switched(pvc.targets) {
+		return true
+	}
+	for _, lookup := range pvc.lookups {
+		if switched(lookup.targets) {
+			return true
+		}
+	}
+	return false
+}
+
+// queriesDenied returns whether queries to the table are denied on any of the
+// primaries of the keyspace's shards.
+func (pvc *primaryVindexChange) queriesDenied() bool {
+	for _, target := range pvc.targets.ts.targets {
+		if tc := target.si.GetTabletControl(topodatapb.TabletType_PRIMARY); tc != nil && slices.Contains(tc.DeniedTables, pvc.table) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasReverseStreams returns whether the reverse workflows have streams on
+// all of their targets.
+func (pvc *primaryVindexChange) hasReverseStreams() bool {
+	targets := []*workflowTargets{pvc.reverseTargets}
+	for _, lookup := range pvc.lookups {
+		targets = append(targets, lookup.reverseTargets)
+	}
+	for _, targets := range targets {
+		for _, shard := range targets.shards {
+			if len(targets.streams[shard]) == 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (pvc *primaryVindexChange) reverseWorkflow() string {
+	return pvc.workflow + reverseSuffix
+}
+
+func (lookup *primaryVindexChangeLookup) reverseWorkflow() string {
+	return lookup.workflow + reverseSuffix
+}
+
+// checkStreamsRunning returns an error unless all of the streams of the
+// workflow and of its lookup workflows are done copying and running.
+func (pvc *primaryVindexChange) checkStreamsRunning() error {
+	check := func(workflow string, targets *workflowTargets) error {
+		for _, shard := range targets.shards {
+			for _, stream := range targets.streams[shard] {
+				if stream.State != binlogdatapb.VReplicationWorkflowState_Running {
+					return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "stream %d of workflow %s on shard %s/%s is in the %s state, all streams must be running to switch traffic",
+						stream.Id, workflow, targets.ts.targetKeyspace, shard, stream.State)
+				}
+			}
+		}
+		return nil
+	}
+	if err := check(pvc.workflow, pvc.targets); err != nil {
+		return err
+	}
+	for _, lookup := range pvc.lookups {
+		if err := check(lookup.workflow, lookup.targets); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkPrimaryVindexChangeVDiff returns an error unless the last VDiff of the
+// workflow completed on every shard without finding any differences.
+func (s *Server) checkPrimaryVindexChangeVDiff(ctx context.Context, pvc *primaryVindexChange) error {
+	return pvc.targets.ts.ForAllTargets(func(target *MigrationTarget) error {
+		shard := target.si.ShardName()
+		resp, err := s.tmc.VDiff(ctx, target.primary.Tablet, &tabletmanagerdatapb.VDiffRequest{
+			Keyspace:  pvc.keyspace,
+			Workflow:  pvc.workflow,
+			Action:    string(vdiff.ShowAction),
+			ActionArg: vdiff.LastActionArg,
+		})
+		if err != nil {
+			return err
+		}
+		reports, err := parseVDiffRepairReports(shard, resp)
+		if err != nil {
+			return vterrors.Wrapf(err, "a completed vdiff of workflow %s is required to switch traffic", pvc.workflow)
+		}
+		for _, dr := range reports {
+			if dr.MismatchedRows != 0 || dr.ExtraRowsSource != 0 || dr.ExtraRowsTarget != 0 {
+				return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the last vdiff of workflow %s found differences in table %s on shard %s",
+					pvc.workflow, dr.TableName, shard)
+			}
+		}
+		return nil
+	})
+}
+
+// denyPrimaryVindexChangeQueries adds the table and its lookup tables to, or
+// removes them from, the denied tables of the primary, replica and rdonly
+// tablets of their keyspaces' shards. The tablets then fail all of the
+// queries to the tables, so that nothing is read from or written to a table
+// that is being swapped with its shadow table.
+func (s *Server) denyPrimaryVindexChangeQueries(ctx context.Context, pvc *primaryVindexChange, allow bool) error {
+	for _, ks := range pvc.keyspaces() {
+		var (
+			targets *workflowTargets
+			tables  []string
+		)
+		if ks == pvc.keyspace {
+			targets, tables = pvc.targets, []string{pvc.table}
+		}
+		for _, lookup := range pvc.lookups {
+			if lookup.keyspace == ks {
+				targets, tables = lookup.targets, append(tables, lookup.table)
+			}
+		}
+		sort.Strings(tables)
+		err := targets.ts.ForAllTargets(func(target *MigrationTarget) error {
+			si, err := s.ts.UpdateShardFields(ctx, ks, target.si.ShardName(), func(si *topo.ShardInfo) error {
+				return updatePrimaryVindexChangeDeniedTables(ctx, si, allow, tables)
+			})
+			if err != nil {
+				return err
+			}
+			refreshCtx, cancel := context.WithTimeout(ctx, shardTabletRefreshTimeout)
+			defer cancel()
+			isPartial, partialDetails, err := topotools.RefreshTabletsByShard(refreshCtx, s.ts, s.tmc, si, nil, s.Logger())
+			if isPartial {
+				err = vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "failed to successfully refresh all tablets in the %s/%s shard (%v):\n  %v",
+					si.Keyspace(), si.ShardName(), err, partialDetails)
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updatePrimaryVindexChangeDeniedTables adds the tables to, or removes them
+// from, the denied tables of the shard's serving tablet types. A replica or
+// rdonly tablet control has a single set of tables for all of its cells, so
+// it is only added when there is none and only removed when it has the
+// tables we added.
+func updatePrimaryVindexChangeDeniedTables(ctx context.Context, si *topo.ShardInfo, allow bool, tables []string) error {
+	for _, tabletType := range []topodatapb.TabletType{topodatapb.TabletType_PRIMARY, topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY} {
+		if tabletType != topodatapb.TabletType_PRIMARY {
+			tc := si.GetTabletControl(tabletType)
+			switch {
+			case tc == nil && allow:
+				continue
+			case tc != nil && !slices.Equal(tc.DeniedTables, tables):
+				if allow {
+					continue
+				}
+				return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "shard %s/%s already denies tables %v on %s tablets",
+					si.Keyspace(), si.ShardName(), tc.DeniedTables, topoproto.TabletTypeLString(tabletType))
+			}
+		}
+		if err := si.UpdateDeniedTables(ctx, tabletType, nil, allow, tables); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitForPrimaryVindexChangeStreams waits for the running streams on the
+// targets to catch up with the current positions of the source keyspace's
+// primaries. The streams that were stopped to switch traffic had already
+// caught up when they were stopped.
+func (s *Server) waitForPrimaryVindexChangeStreams(ctx context.Context, sourceKeyspace string, targets *workflowTargets) error {
+	positions, err := s.getPrimaryVindexChangePositions(ctx, sourceKeyspace)
+	if err != nil {
+		return err
+	}
+	return targets.ts.ForAllTargets(func(target *MigrationTarget) error {
+		for _, stream := range targets.streams[target.si.ShardName()] {
+			if stream.State != binlogdatapb.VReplicationWorkflowState_Running {
+				continue
+			}
+			pos, ok := positions[stream.GetBls().GetShard()]
+			if !ok {
+				return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "stream %d on shard %s/%s streams from shard %s, which is not a serving shard of the %s keyspace",
+					stream.Id, target.si.Keyspace(), target.si.ShardName(), stream.GetBls().GetShard(), sourceKeyspace)
+			}
+			if err := s.tmc.VReplicationWaitForPos(ctx, target.primary.Tablet, stream.Id, pos); err != nil {
+				return vterrors.Wrapf(err, "stream %d on shard %s/%s did not catch up with position %s", stream.Id, target.si.Keyspace(), target.si.ShardName(), pos)
+			}
+		}
+		return nil
+	})
+}
+
+// getPrimaryVindexChangePositions returns the current position of the
+// primary of each serving shard in the keyspace.
+func (s *Server) getPrimaryVindexChangePositions(ctx context.Context, keyspace string) (map[string]string, error) {
+	sources, err := s.newWorkflowTargets(ctx, keyspace, "")
+	if err != nil {
+		return nil, err
+	}
+	var mu sync.Mutex
+	positions := make(map[string]string, len(sources.shards))
+	err = sources.ts.ForAllTargets(func(source *MigrationTarget) error {
+		pos, err := s.tmc.PrimaryPosition(ctx, source.primary.Tablet)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		positions[source.si.ShardName()] = pos
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return positions, nil
+}
+
+// switchPrimaryVindexChange switches traffic once queries to the table are
+// denied and the workflows have caught up. The workflows are stopped rather
+// than deleted, so that they can be restarted if traffic is switched back.
+func (s *Server) switchPrimaryVindexChange(ctx context.Context, pvc *primaryVindexChange) error {
+	if err := s.stopPrimaryVindexChangeStreams(ctx, pvc.targets); err != nil {
+		return err
+	}
+	for _, lookup := range pvc.lookups {
+		if err := s.stopPrimaryVindexChangeStreams(ctx, lookup.targets); err != nil {
+			return err
+		}
+	}
+	if err := s.renamePrimaryVindexChangeTables(ctx, pvc.targets, pvc.table, pvc.shadow, pvc.old); err != nil {
+		return err
+	}
+	for _, lookup := range pvc.lookups {
+		if err := s.renamePrimaryVindexChangeTables(ctx, lookup.targets, lookup.table, lookup.shadow, lookup.old); err != nil {
+			return err
+		}
+	}
+	if err := s.switchPrimaryVindexChangeVSchemas(ctx, pvc, false); err != nil {
+		return err
+	}
+	return s.createPrimaryVindexChangeReverseWorkflows(ctx, pvc)
+}
+
+// waitForPrimaryVindexChangeReverseStreams waits for the reverse workflows
+// to catch up once queries to the table are denied, and then stops them.
+func (s *Server) waitForPrimaryVindexChangeReverseStreams(ctx context.Context, pvc *primaryVindexChange, timeout time.Duration) error {
+	check := func(workflow string, targets *workflowTargets) error {
+		for _, shard := range targets.shards {
+			for _, stream := range targets.streams[shard] {
+				if stream.State == binlogdatapb.VReplicationWorkflowState_Running ||
+					(stream.State == binlogdatapb.VReplicationWorkflowState_Stopped && stream.Message == primaryVindexChangeStoppedMessage) {
+					continue
+				}
+				return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "stream %d of workflow %s on shard %s/%s is in the %s state, it must be running to switch traffic back",
+					stream.Id, workflow, targets.ts.targetKeyspace, shard, stream.State)
+			}
+		}
+		return nil
+	}
+	if err := check(pvc.reverseWorkflow(), pvc.reverseTargets); err != nil {
+		return err
+	}
+	for _, lookup := range pvc.lookups {
+		if err := check(lookup.reverseWorkflow(), lookup.reverseTargets); err != nil {
+			return err
+		}
+	}
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, timeout)
+	defer waitCancel()
+	if err := s.waitForPrimaryVindexChangeStreams(waitCtx, pvc.keyspace, pvc.reverseTargets); err != nil {
+		return err
+	}
+	// The reverse lookup workflows stream from the original table, which the
+	// reverse workflow keeps up to date.
+	for _, lookup := range pvc.lookups {
+		if err := s.waitForPrimaryVindexChangeStreams(waitCtx, pvc.keyspace, lookup.reverseTargets); err != nil {
+			return err
+		}
+	}
+	if err := s.stopPrimaryVindexChangeStreams(ctx, pvc.reverseTargets); err != nil {
+		return err
+	}
+	for _, lookup := range pvc.lookups {
+		if err := s.stopPrimaryVindexChangeStreams(ctx, lookup.reverseTargets); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// switchBackPrimaryVindexChange switches traffic back once queries to the
+// table are denied and the reverse workflows have caught up, or when a
+// switch failed part way. Each step skips what was not switched, or was
+// already switched back, so that it can be retried until it succeeds.
+func (s *Server) switchBackPrimaryVindexChange(ctx context.Context, pvc *primaryVindexChange) error {
+	if err := s.renamePrimaryVindexChangeTables(ctx, pvc.targets, pvc.table, pvc.old, pvc.shadow); err != nil {
+		return err
+	}
+	for _, lookup := range pvc.lookups {
+		if err := s.renamePrimaryVindexChangeTables(ctx, lookup.targets, lookup.table, lookup.old, lookup.shadow); err != nil {
+			return err
+		}
+	}
+	if err := s.switchPrimaryVindexChangeVSchemas(ctx, pvc, true); err != nil {
+		return err
+	}
+	if err := pvc.reverseTargets.ts.dropTargetVReplicationStreams(ctx); err != nil {
+		return err
+	}
+	for _, lookup := range pvc.lookups {
+		if err := lookup.reverseTargets.ts.dropTargetVReplicationStreams(ctx); err != nil {
+			return err
+		}
+	}
+	// The renames are in the binary logs of the primaries, so the workflows
+	// continue from their current positions.
+	positions, err := s.getPrimaryVindexChangePositions(ctx, pvc.keyspace)
+	if err != nil {
+		return err
+	}
+	if err := s.restartPrimaryVindexChangeStreams(ctx, pvc.targets, positions); err != nil {
+		return err
+	}
+	for _, lookup := range pvc.lookups {
+		if err := s.restartPrimaryVindexChangeStreams(ctx, lookup.targets, positions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollbackPrimaryVindexChange switches traffic back after the switch failed
+// with the given error. Queries to the table are only allowed again once it
+// has been, and otherwise stay denied until reversetraffic succeeds.
+func (s *Server) rollbackPrimaryVindexChange(ctx context.Context, pvc *primaryVindexChange, timeout time.Duration, err error) error {
+	// Queries may have been allowed again on some of the shards.
+	rerr := s.denyPrimaryVindexChangeQueries(ctx, pvc, false)
+	if rerr == nil {
+		// The streams of the workflows were changed by the switch.
+		pvc, rerr = s.getPrimaryVindexChange(ctx, pvc.keyspace, pvc.workflow, false)
+	}
+	if rerr == nil {
+		rerr = s.waitForPrimaryVindexChangeReverseStreams(ctx, pvc, timeout)
+	}
+	if rerr == nil {
+		rerr = s.switchBackPrimaryVindexChange(ctx, pvc)
+	}
+	if rerr != nil {
+		return vterrors.Wrapf(err, "failed to switch traffic, and failed to switch it back: %v; queries to table %s are denied until reversetraffic succeeds",
+			rerr, pvc.table)
+	}
+	if aerr := s.denyPrimaryVindexChangeQueries(ctx, pvc, true); aerr != nil {
+		return vterrors.Wrapf(err, "failed to switch traffic, which was switched back, but failed to allow queries to table %s again: %v", pvc.table, aerr)
+	}
+	return vterrors.Wrapf(err, "failed to switch traffic, which was switched back")
+}
+
+// stopPrimaryVindexChangeStreams stops the streams on the targets, with a
+// message that tells them apart from the streams stopped for other reasons.
+func (s *Server) stopPrimaryVindexChangeStreams(ctx context.Context, targets *workflowTargets) error {
+	return targets.ts.ForAllTargets(func(target *MigrationTarget) error {
+		for _, stream := range targets.streams[target.si.ShardName()] {
+			if _, err := s.tmc.VReplicationExec(ctx, target.primary.Tablet, binlogplayer.StopVReplication(stream.Id, primaryVindexChangeStoppedMessage)); err != nil {
+				return vterrors.Wrapf(err, "failed to stop stream %d on shard %s/%s", stream.Id, target.si.Keyspace(), target.si.ShardName())
+			}
+		}
+		return nil
+	})
+}
+
+// restartPrimaryVindexChangeStreams starts the streams on the targets that
+// are not running from the given positions of their source shards.
+func (s *Server) restartPrimaryVindexChangeStreams(ctx context.Context, targets *workflowTargets, positions map[string]string) error {
+	return targets.ts.ForAllTargets(func(target *MigrationTarget) error {
+		for _, stream := range targets.streams[target.si.ShardName()] {
+			if stream.State == binlogdatapb.VReplicationWorkflowState_Running {
+				continue
+			}
+			pos, ok := positions[stream.GetBls().GetShard()]
+			if !ok {
+				return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "stream %d on shard %s/%s streams from shard %s, which is not a serving shard",
+					stream.Id, target.si.Keyspace(), target.si.ShardName(), stream.GetBls().GetShard())
+			}
+			query := fmt.Sprintf("update _vt.vreplication set pos=%s, state=%s, message='' where id=%d",
+				encodeString(pos), encodeString(binlogdatapb.VReplicationWorkflowState_Running.String()), stream.Id)
+			if _, err := s.tmc.VReplicationExec(ctx, target.primary.Tablet, query); err != nil {
+				return vterrors.Wrapf(err, "failed to start stream %d on shard %s/%s", stream.Id, target.si.Keyspace(), target.si.ShardName())
+			}
+		}
+		return nil
+	})
+}
+
+// renamePrimaryVindexChangeTables replaces the table with the from table on
+// the targets, renaming the table to the to table. Both tables are renamed by
+// a single statement, so the shards on which the to table already exists
+// instead of the from table were already switched and are skipped. This lets
+// a switch that failed part way be retried, or rolled back by renaming the
+// tables the other way.
+func (s *Server) renamePrimaryVindexChangeTables(ctx context.Context, targets *workflowTargets, table, from, to string) error {
+	return targets.ts.ForAllTargets(func(target *MigrationTarget) error {
+		schema, err := s.tmc.GetSchema(ctx, target.primary.Tablet, &tabletmanagerdatapb.GetSchemaRequest{
+			Tables:          []string{from, to},
+			TableSchemaOnly: true,
+		})
+		if err != nil {
+			return err
+		}
+		exists := make(map[string]bool, 2)
+		for _, td := range schema.GetTableDefinitions() {
+			exists[td.Name] = true
+		}
+		switch {
+		case exists[from] && !exists[to]:
+		case exists[to] && !exists[from]:
+			return nil
+		default:
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "expected either table %s or table %s on shard %s/%s", from, to, target.si.Keyspace(), target.si.ShardName())
+		}
+		query := fmt.Sprintf("rename table %s to %s, %s to %s",
+			sqlescape.EscapeID(table), sqlescape.EscapeID(to), sqlescape.EscapeID(from), sqlescape.EscapeID(table))
+		if _, err := s.tmc.ExecuteFetchAsDba(ctx, target.primary.Tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+			Query:        []byte(query),
+			DbName:       target.primary.DbName(),
+			MaxRows:      1,
+			ReloadSchema: true,
+		}); err != nil {
+			return vterrors.Wrapf(err, "failed to replace table %s with %s on shard %s/%s", table, from, target.si.Keyspace(), target.si.ShardName())
+		}
+		return nil
+	})
+}
+
+func (s *Server) dropPrimaryVindexChangeTable(ctx context.Context, targets *workflowTargets, table string) error {
+	return targets.ts.ForAllTargets(func(target *MigrationTarget) error {
+		query := fmt.Sprintf("drop table if exists %s.%s", sqlescape.EscapeID(target.primary.DbName()), sqlescape.EscapeID(table))
+		if _, err := s.tmc.ExecuteFetchAsDba(ctx, target.primary.Tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+			Query:                   []byte(query),
+			MaxRows:                 1,
+			ReloadSchema:            true,
+			DisableForeignKeyChecks: true,
+		}); err != nil {
+			return vterrors.Wrapf(err, "failed to drop table %s on shard %s/%s", table, target.si.Keyspace(), target.si.ShardName())
+		}
+		return nil
+	})
+}
+
+// switchPrimaryVindexChangeVSchemas makes the primary vindex of the shadow
+// table the table's primary vindex in the vschema, moving the table's
+// primary vindex to the original table that replaces the shadow table, and
+// renames the shadow lookup tables in the same way. When switching back, the
+// original table's primary vindex is moved back. The vschemas that were
+// already switched are skipped.
+func (s *Server) switchPrimaryVindexChangeVSchemas(ctx context.Context, pvc *primaryVindexChange, back bool) error {
+	for _, ks := range pvc.keyspaces() {
+		vschema, err := s.ts.GetVSchema(ctx, ks)
+		if err != nil {
+			return err
+		}
+		changed := false
+		if ks == pvc.keyspace {
+			from, to := pvc.shadow, pvc.old
+			if back {
+				from, to = to, from
+			}
+			if _, ok := vschema.Tables[from]; ok {
+				if err := switchPrimaryVindex(vschema.Keyspace, pvc.table, from, to); err != nil {
+					return err
+				}
+				changed = true
+			}
+		}
+		for _, lookup := range pvc.lookups {
+			from, to := lookup.shadow, lookup.old
+			if back {
+				from, to = to, from
+			}
+			if lookup.keyspace != ks {
+				continue
+			}
+			if ltable, ok := vschema.Tables[from]; ok {
+				vschema.Tables[to] = ltable
+				delete(vschema.Tables, from)
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if err := s.ts.SaveVSchema(ctx, vschema); err != nil {
+			return err
+		}
+	}
+	return s.ts.RebuildSrvVSchema(ctx, nil)
+}
+
+// removePrimaryVindexChangeVSchemaTables removes the shadow tables from the
+// vschemas, or the original tables once traffic has been switched.
+func (s *Server) removePrimaryVindexChangeVSchemaTables(ctx context.Context, pvc *primaryVindexChange, switched bool) error {
+	for _, ks := range pvc.keyspaces() {
+		vschema, err := s.ts.GetVSchema(ctx, ks)
+		if err != nil {
+			return err
+		}
+		if ks == pvc.keyspace {
+			if switched {
+				delete(vschema.Tables, pvc.old)
+			} else {
+				delete(vschema.Tables, pvc.shadow)
+			}
+		}
+		for _, lookup := range pvc.lookups {
+			if lookup.keyspace != ks {
+				continue
+			}
+			if switched {
+				delete(vschema.Tables, lookup.old)
+			} else {
+				delete(vschema.Tables, lookup.shadow)
+			}
+		}
+		if err := s.ts.SaveVSchema(ctx, vschema); err != nil {
+			return err
+		}
+	}
+	return s.ts.RebuildSrvVSchema(ctx, nil)
+}
+
+// createPrimaryVindexChangeReverseWorkflows creates the reverse workflows,
+// which keep the original table and lookup tables up to date once traffic
+// has been switched. They start from the current positions of the keyspace's
+// primaries, as queries to the table are still denied.
+func (s *Server) createPrimaryVindexChangeReverseWorkflows(ctx context.Context, pvc *primaryVindexChange) error {
+	positions, err := s.getPrimaryVindexChangePositions(ctx, pvc.keyspace)
+	if err != nil {
+		return err
+	}
+	vschema, err := s.ts.GetVSchema(ctx, pvc.keyspace)
+	if err != nil {
+		return err
+	}
+	old, ok := vschema.Tables[pvc.old]
+	if !ok || len(old.ColumnVindexes) == 0 {
+		return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "table %s not found in the vschema of the %s keyspace", pvc.old, pvc.keyspace)
+	}
+	query := "select * from " + sqlescape.EscapeID(pvc.table)
+	if err := s.createPrimaryVindexChangeReverseWorkflow(ctx, pvc, pvc.reverseWorkflow(), pvc.reverseTargets, pvc.old, query, old.ColumnVindexes[0], positions); err != nil {
+		return err
+	}
+	for _, lookup := range pvc.lookups {
+		lvschema, err := s.ts.GetVSchema(ctx, lookup.keyspace)
+		if err != nil {
+			return err
+		}
+		var cv *vschemapb.ColumnVindex
+		if lvschema.Sharded {
+			ltable, ok := lvschema.Tables[lookup.old]
+			if !ok || len(ltable.ColumnVindexes) == 0 {
+				return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "table %s not found in the vschema of the %s keyspace", lookup.old, lookup.keyspace)
+			}
+			cv = ltable.ColumnVindexes[0]
+		}
+		if err := s.createPrimaryVindexChangeReverseWorkflow(ctx, pvc, lookup.reverseWorkflow(), lookup.reverseTargets, lookup.old, lookup.reverseQuery, cv, positions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createPrimaryVindexChangeReverseWorkflow creates a reverse workflow on the
+// targets, with a stream from each shard of the table's keyspace that
+// selects the rows of the target shard using the column vindex, if any.
+func (s *Server) createPrimaryVindexChangeReverseWorkflow(ctx context.Context, pvc *primaryVindexChange, workflow string, targets *workflowTargets,
+	table, query string, cv *vschemapb.ColumnVindex, positions map[string]string) error {
+	shards := make([]string, 0, len(positions))
+	for shard := range positions {
+		shards = append(shards, shard)
+	}
+	sort.Strings(shards)
+	return targets.ts.ForAllTargets(func(target *MigrationTarget) error {
+		filter := query
+		if cv != nil {
+			var err error
+			if filter, err = addPrimaryVindexChangeKeyRange(s.env.Parser(), query, target.si.Keyspace(), cv, target.si.KeyRange); err != nil {
+				return err
+			}
+		}
+		// Streams left behind by an earlier switch that failed are replaced.
+		if _, err := s.tmc.DeleteVReplicationWorkflow(ctx, target.primary.Tablet, &tabletmanagerdatapb.DeleteVReplicationWorkflowRequest{
+			Workflow: workflow,
+		}); err != nil {
+			return err
+		}
+		for _, shard := range shards {
+			bls := &binlogdatapb.BinlogSource{
+				Keyspace: pvc.keyspace,
+				Shard:    shard,
+				Filter: &binlogdatapb.Filter{
+					Rules: []*binlogdatapb.Rule{{Match: table, Filter: filter}},
+				},
+			}
+			if _, err := s.tmc.VReplicationExec(ctx, target.primary.Tablet, binlogplayer.CreateVReplicationState(workflow, bls, positions[shard],
+				binlogdatapb.VReplicationWorkflowState_Running, target.primary.DbName(), binlogdatapb.VReplicationWorkflowType_Materialize,
+				binlogdatapb.VReplicationWorkflowSubType_None)); err != nil {
+				return vterrors.Wrapf(err, "failed to create workflow %s on shard %s/%s", workflow, target.si.Keyspace(), target.si.ShardName())
+			}
+		}
+		return nil
+	})
+}
+
+// addPrimaryVindexChangeKeyRange restricts the rows selected by the query to
+// those whose keyspace id, using the column vindex of the table they are
+// copied into, is in the key range.
+func addPrimaryVindexChangeKeyRange(parser *sqlparser.Parser, query, keyspace string, cv *vschemapb.ColumnVindex, keyRange *topodatapb.KeyRange) (string, error) {
+	stmt, err := parser.Parse(query)
+	if err != nil {
+		return "", err
+	}
+	sel, ok := stmt.(*sqlparser.Select)
+	if !ok {
+		return "", vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unrecognized statement: %s", query)
+	}
+	columns := cv.Columns
+	if len(columns) == 0 && cv.Column != "" {
+		columns = []string{cv.Column}
+	}
+	exprs := make([]sqlparser.Expr, 0, len(columns)+2)
+	for _, col := range columns {
+		colName, err := matchColInSelect(sqlparser.NewIdentifierCI(col), sel)
+		if err != nil {
+			return "", err
+		}
+		exprs = append(exprs, colName)
+	}
+	exprs = append(exprs, sqlparser.NewStrLiteral(fmt.Sprintf("%s.%s", keyspace, cv.Name)), sqlparser.NewStrLiteral(key.KeyRangeString(keyRange)))
+	addFilter(sel, sqlparser.NewFuncExpr("in_keyrange", exprs...))
+	return sqlparser.String(sel), nil
+}
+
+// getPrimaryVindexChangeShadowDDL returns the DDL that creates the shadow
+// table of a table, using the table's definition on the keyspace's first
+// shard.
+func (s *Server) getPrimaryVindexChangeShadowDDL(ctx context.Context, keyspace, table, shadow string) (string, error) {
+	targets, err := s.newWorkflowTargets(ctx, keyspace, "")
+	if err != nil {
+		return "", err
+	}
+	schema, err := schematools.GetSchema(ctx, s.ts, s.tmc, targets.first().primary.Alias, &tabletmanagerdatapb.GetSchemaRequest{
+		Tables: []string{table},
+	})
+	if err != nil {
+		return "", err
+	}
+	for _, td := range schema.GetTableDefinitions() {
+		if td.Name == shadow {
+			return "", vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s already exists in the %s keyspace", shadow, keyspace)
+		}
+	}
+	for _, td := range schema.GetTableDefinitions() {
+		if td.Name == table {
+			return buildPrimaryVindexChangeShadowDDL(td.Schema, shadow, s.env.Parser())
+		}
+	}
+	return "", vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "table %s not found on shard %s/%s", table, keyspace, targets.first().si.ShardName())
+}
+
+// buildPrimaryVindexChangeShadowDDL returns the create DDL of a table with
+// the name of its shadow table. Tables with foreign keys are not supported,
+// as the constraints would have to be moved to the shadow table when it
+// replaces the table.
+func buildPrimaryVindexChangeShadowDDL(ddl, shadow string, parser *sqlparser.Parser) (string, error) {
+	stmt, err := parser.ParseStrictDDL(ddl)
+	if err != nil {
+		return "", err
+	}
+	create, ok := stmt.(*sqlparser.CreateTable)
+	if !ok {
+		return "", vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "expected a create table statement: %s", ddl)
+	}
+	for _, constraint := range create.GetTableSpec().Constraints {
+		if _, ok := constraint.Details.(*sqlparser.ForeignKeyDefinition); ok {
+			return "", vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "table %s has foreign keys, which is not supported when changing its primary vindex",
+				create.Table.Name.String())
+		}
+	}
+	create.Table = sqlparser.NewTableName(shadow)
+	return sqlparser.String(create), nil
+}
+
+// addPrimaryVindexChangeVindexes adds the vindexes to the keyspace's vschema.
+// Vindexes that are already defined must have the same definition.
+func addPrimaryVindexChangeVindexes(vschema *vschemapb.Keyspace, vindexes map[string]*vschemapb.Vindex) error {
+	for name, vindex := range vindexes {
+		if existing, ok := vschema.Vindexes[name]; ok {
+			if !proto.Equal(existing, vindex) {
+				return vterrors.Errorf(vtrpcpb.Code_ALREADY_EXISTS, "a different vindex named %s already exists in the vschema", name)
+			}
+			continue
+		}
+		if vschema.Vindexes == nil {
+			vschema.Vindexes = make(map[string]*vschemapb.Vindex)
+		}
+		vschema.Vindexes[name] = vindex
+	}
+	return nil
+}
+
+// validatePrimaryVindexChange validates that the table's primary vindex can
+// be changed to the given column vindex, and returns the column vindex to
+// use for the shadow table.
+func validatePrimaryVindexChange(vschema *vschemapb.Keyspace, keyspace, table, shadow string, primaryVindex *vschemapb.ColumnVindex) (*vschemapb.ColumnVindex, error) {
+	if !vschema.Sharded {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "keyspace %s is not sharded", keyspace)
+	}
+	vtable, ok := vschema.Tables[table]
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "table %s not found in the vschema of the %s keyspace", table, keyspace)
+	}
+	if vtable.Type != "" || len(vtable.ColumnVindexes) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s does not have a primary vindex that can be changed", table)
+	}
+	if _, ok := vschema.Tables[shadow]; ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s already exists in the vschema of the %s keyspace", shadow, keyspace)
+	}
+	vindexDef, ok := vschema.Vindexes[primaryVindex.Name]
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "vindex %s not found in the vschema of the %s keyspace", primaryVindex.Name, keyspace)
+	}
+	vindex, err := vindexes.CreateVindex(vindexDef.Type, primaryVindex.Name, vindexDef.Params)
+	if err != nil {
+		return nil, err
+	}
+	if !vindex.IsUnique() || vindex.NeedsVCursor() {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "vindex %s of type %s cannot be a primary vindex, it must be unique and must not be a lookup vindex",
+			primaryVindex.Name, vindexDef.Type)
+	}
+	columns := primaryVindex.Columns
+	if len(columns) == 0 && primaryVindex.Column != "" {
+		columns = []string{primaryVindex.Column}
+	}
+	if len(columns) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the columns of the primary vindex must be specified")
+	}
+	current := vtable.ColumnVindexes[0]
+	currentColumns := current.Columns
+	if len(currentColumns) == 0 && current.Column != "" {
+		currentColumns = []string{current.Column}
+	}
+	if current.Name == primaryVindex.Name && slices.EqualFunc(currentColumns, columns, strings.EqualFold) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "vindex %s on columns %s is already the primary vindex of table %s",
+			primaryVindex.Name, strings.Join(columns, ","), table)
+	}
+	return &vschemapb.ColumnVindex{Name: primaryVindex.Name, Columns: columns}, nil
+}
+
+// getPrimaryVindexChangeLookups returns the owned lookup vindexes of the
+// table that must be recreated. Its other lookup vindexes are owned by
+// other tables, whose rows do not move, so they cannot be used.
+func getPrimaryVindexChangeLookups(vschema *vschemapb.Keyspace, keyspace, workflow, table, shadow string) ([]*primaryVindexChangeLookup, error) {
+	var lookups []*primaryVindexChangeLookup
+	for _, cv := range vschema.Tables[table].ColumnVindexes[1:] {
+		vindexDef := vschema.Vindexes[cv.Name]
+		if vindexDef == nil || !strings.Contains(vindexDef.Type, "lookup") {
+			continue
+		}
+		if vindexDef.Owner != table {
+			return nil, vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "lookup vindex %s of table %s is owned by table %s, which is not supported when changing the primary vindex",
+				cv.Name, table, vindexDef.Owner)
+		}
+		lookup, err := newPrimaryVindexChangeLookup(vschema, keyspace, workflow, table, shadow, cv.Name)
+		if err != nil {
+			return nil, err
+		}
+		lookups = append(lookups, lookup)
+	}
+	return lookups, nil
+}
+
+// newPrimaryVindexChangeLookup returns the lookup vindex of the table, with
+// the query that selects its rows from the table's shadow table.
+func newPrimaryVindexChangeLookup(vschema *vschemapb.Keyspace, keyspace, workflow, table, shadow, name string) (*primaryVindexChangeLookup, error) {
+	vindexDef := vschema.Vindexes[name]
+	if vindexDef == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "lookup vindex %s not found in the vschema of the %s keyspace", name, keyspace)
+	}
+	switch strings.ToLower(vindexDef.Type) {
+	case "lookup", "lookup_unique", "consistent_lookup", "consistent_lookup_unique":
+	default:
+		// The other lookup vindexes store the value of the primary vindex's
+		// column rather than the keyspace id.
+		return nil, vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "lookup vindex %s of type %s does not store keyspace ids, which is not supported when changing the primary vindex",
+			name, vindexDef.Type)
+	}
+	var cv *vschemapb.ColumnVindex
+	for _, tableCV := range vschema.Tables[table].GetColumnVindexes() {
+		if tableCV.Name == name {
+			cv = tableCV
+			break
+		}
+	}
+	if cv == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "lookup vindex %s is not a vindex of table %s", name, table)
+	}
+	sourceCols := cv.Columns
+	if len(sourceCols) == 0 && cv.Column != "" {
+		sourceCols = []string{cv.Column}
+	}
+	var fromCols []string
+	for _, col := range strings.Split(vindexDef.Params["from"], ",") {
+		fromCols = append(fromCols, strings.TrimSpace(col))
+	}
+	toCol := strings.TrimSpace(vindexDef.Params["to"])
+	if len(sourceCols) != len(fromCols) || toCol == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the columns of lookup vindex %s do not match the from and to columns of its lookup table", name)
+	}
+
+	lookup := &primaryVindexChangeLookup{
+		vindex:   name,
+		keyspace: keyspace,
+		table:    vindexDef.Params["table"],
+		workflow: fmt.Sprintf("%s_%s", workflow, name),
+	}
+	if qualifier, lookupTable, ok := strings.Cut(lookup.table, "."); ok {
+		lookup.keyspace, lookup.table = qualifier, lookupTable
+	}
+	if lookup.table == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "lookup vindex %s does not have a lookup table", name)
+	}
+	lookup.shadow = fmt.Sprintf(primaryVindexChangeShadowTableFormat, lookup.table)
+	lookup.old = getRenameFileName(lookup.table)
+
+	ignoreNulls, _ := strconv.ParseBool(vindexDef.Params["ignore_nulls"])
+	buildQuery := func(sourceTable string) string {
+		buf := sqlparser.NewTrackedBuffer(nil)
+		buf.Myprintf("select ")
+		for i := range fromCols {
+			buf.Myprintf("%s as %s, ", sqlparser.String(sqlparser.NewIdentifierCI(sourceCols[i])), sqlparser.String(sqlparser.NewIdentifierCI(fromCols[i])))
+		}
+		buf.Myprintf("keyspace_id() as %s from %s", sqlparser.String(sqlparser.NewIdentifierCI(toCol)), sqlparser.String(sqlparser.NewIdentifierCS(sourceTable)))
+		if ignoreNulls {
+			// The rows are filtered before the columns of the table are
+			// renamed to the from columns of the lookup table.
+			for i, col := range sourceCols {
+				if i == 0 {
+					buf.Myprintf(" where ")
+				} else {
+					buf.Myprintf(" and ")
+				}
+				buf.Myprintf("%s is not null", sqlparser.String(sqlparser.NewIdentifierCI(col)))
+			}
+		}
+		return buf.String()
+	}
+	lookup.query = buildQuery(shadow)
+	lookup.reverseQuery = buildQuery(getRenameFileName(table))
+	return lookup, nil
+}
+
+// switchPrimaryVindex makes the primary vindex of the from table the primary
+// vindex of the table, keeping the table's other vindexes. The from table is
+// replaced by the to table, whose primary vindex is the table's former one.
+func switchPrimaryVindex(vschema *vschemapb.Keyspace, table, from, to string) error {
+	vtable, ok := vschema.Tables[table]
+	if !ok || len(vtable.ColumnVindexes) == 0 {
+		return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "table %s not found in the vschema", table)
+	}
+	vfrom, ok := vschema.Tables[from]
+	if !ok || len(vfrom.ColumnVindexes) == 0 {
+		return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "table %s not found in the vschema", from)
+	}
+	if _, ok := vschema.Tables[to]; ok {
+		return vterrors.Errorf(vtrpcpb.Code_ALREADY_EXISTS, "table %s already exists in the vschema", to)
+	}
+	vschema.Tables[to] = &vschemapb.Table{ColumnVindexes: []*vschemapb.ColumnVindex{vtable.ColumnVindexes[0]}}
+	columnVindexes := []*vschemapb.ColumnVindex{vfrom.ColumnVindexes[0]}
+	vtable.ColumnVindexes = append(columnVindexes, vtable.ColumnVindexes[1:]...)
+	delete(vschema.Tables, from)
+	return nil
+}
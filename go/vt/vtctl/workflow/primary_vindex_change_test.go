@@ -0,0 +1,862 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/prototext"
+
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func primaryVindexChangeTestVSchema() *vschemapb.Keyspace {
+	return &vschemapb.Keyspace{
+		Sharded: true,
+		Vindexes: map[string]*vschemapb.Vindex{
+			"hash":   {Type: "hash"},
+			"xxhash": {Type: "xxhash"},
+			"email_lookup": {
+				Type:   "consistent_lookup_unique",
+				Params: map[string]string{"table": "lookup.email_idx", "from": "email_addr", "to": "keyspace_id", "ignore_nulls": "true"},
+				Owner:  "orders",
+			},
+			"sku_lookup": {
+				Type:   "lookup",
+				Params: map[string]string{"table": "sku_idx", "from": "sku,region", "to": "kid"},
+				Owner:  "orders",
+			},
+			"name_lookup": {
+				Type:   "lookup_hash",
+				Params: map[string]string{"table": "name_idx", "from": "name", "to": "id"},
+				Owner:  "customer",
+			},
+		},
+		Tables: map[string]*vschemapb.Table{
+			"orders": {ColumnVindexes: []*vschemapb.ColumnVindex{
+				{Column: "id", Name: "hash"},
+				{Column: "email", Name: "email_lookup"},
+				{Columns: []string{"sku", "region"}, Name: "sku_lookup"},
+			}},
+			"customer": {ColumnVindexes: []*vschemapb.ColumnVindex{
+				{Column: "id", Name: "hash"},
+				{Column: "name", Name: "name_lookup"},
+			}},
+			"items": {ColumnVindexes: []*vschemapb.ColumnVindex{
+				{Column: "id", Name: "hash"},
+				{Column: "name", Name: "name_lookup"},
+			}},
+			"ref": {Type: "reference"},
+		},
+	}
+}
+
+func TestValidatePrimaryVindexChange(t *testing.T) {
+	testCases := []struct {
+		name    string
+		table   string
+		vindex  *vschemapb.ColumnVindex
+		want    *vschemapb.ColumnVindex
+		wantErr string
+	}{
+		{
+			name:   "new vindex",
+			table:  "orders",
+			vindex: &vschemapb.ColumnVindex{Column: "account_id", Name: "xxhash"},
+			want:   &vschemapb.ColumnVindex{Columns: []string{"account_id"}, Name: "xxhash"},
+		},
+		{
+			name:   "same vindex on other columns",
+			table:  "orders",
+			vindex: &vschemapb.ColumnVindex{Columns: []string{"account_id"}, Name: "hash"},
+			want:   &vschemapb.ColumnVindex{Columns: []string{"account_id"}, Name: "hash"},
+		},
+		{
+			name:    "same primary vindex",
+			table:   "orders",
+			vindex:  &vschemapb.ColumnVindex{Column: "ID", Name: "hash"},
+			wantErr: "vindex hash on columns ID is already the primary vindex of table orders",
+		},
+		{
+			name:    "lookup vindex",
+			table:   "orders",
+			vindex:  &vschemapb.ColumnVindex{Column: "email", Name: "email_lookup"},
+			wantErr: "vindex email_lookup of type consistent_lookup_unique cannot be a primary vindex",
+		},
+		{
+			name:    "unknown vindex",
+			table:   "orders",
+			vindex:  &vschemapb.ColumnVindex{Column: "id", Name: "nope"},
+			wantErr: "vindex nope not found in the vschema of the ks keyspace",
+		},
+		{
+			name:    "no columns",
+			table:   "orders",
+			vindex:  &vschemapb.ColumnVindex{Name: "xxhash"},
+			wantErr: "the columns of the primary vindex must be specified",
+		},
+		{
+			name:    "reference table",
+			table:   "ref",
+			vindex:  &vschemapb.ColumnVindex{Column: "id", Name: "xxhash"},
+			wantErr: "table ref does not have a primary vindex that can be changed",
+		},
+		{
+			name:    "unknown table",
+			table:   "t1",
+			vindex:  &vschemapb.ColumnVindex{Column: "id", Name: "xxhash"},
+			wantErr: "table t1 not found in the vschema of the ks keyspace",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			vschema := primaryVindexChangeTestVSchema()
+			got, err := validatePrimaryVindexChange(vschema, "ks", tc.table, tc.table+"_pvc", tc.vindex)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+
+	vschema := primaryVindexChangeTestVSchema()
+	vschema.Tables["orders_pvc"] = &vschemapb.Table{}
+	_, err := validatePrimaryVindexChange(vschema, "ks", "orders", "orders_pvc", &vschemapb.ColumnVindex{Column: "account_id", Name: "xxhash"})
+	require.ErrorContains(t, err, "table orders_pvc already exists in the vschema of the ks keyspace")
+
+	_, err = validatePrimaryVindexChange(&vschemapb.Keyspace{}, "uks", "orders", "orders_pvc", &vschemapb.ColumnVindex{Column: "account_id", Name: "xxhash"})
+	require.ErrorContains(t, err, "keyspace uks is not sharded")
+}
+
+func TestAddPrimaryVindexChangeVindexes(t *testing.T) {
+	vschema := primaryVindexChangeTestVSchema()
+	err := addPrimaryVindexChangeVindexes(vschema, map[string]*vschemapb.Vindex{
+		"hash":     {Type: "hash"},
+		"account1": {Type: "xxhash"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "xxhash", vschema.Vindexes["account1"].Type)
+
+	err = addPrimaryVindexChangeVindexes(vschema, map[string]*vschemapb.Vindex{
+		"hash": {Type: "xxhash"},
+	})
+	require.ErrorContains(t, err, "a different vindex named hash already exists in the vschema")
+}
+
+func TestGetPrimaryVindexChangeLookups(t *testing.T) {
+	vschema := primaryVindexChangeTestVSchema()
+	lookups, err := getPrimaryVindexChangeLookups(vschema, "ks", "wf", "orders", "orders_pvc")
+	require.NoError(t, err)
+	require.Equal(t, []*primaryVindexChangeLookup{
+		{
+			vindex:   "email_lookup",
+			keyspace: "lookup",
+			table:    "email_idx",
+			shadow:   "email_idx_pvc",
+			workflow: "wf_email_lookup",
+			old:      "_email_idx_old",
+			query:    "select email as email_addr, keyspace_id() as keyspace_id from orders_pvc where email is not null",
+			// The reverse query reads from the original table once traffic
+			// has been switched.
+			reverseQuery: "select email as email_addr, keyspace_id() as keyspace_id from _orders_old where email is not null",
+		},
+		{
+			vindex:       "sku_lookup",
+			keyspace:     "ks",
+			table:        "sku_idx",
+			shadow:       "sku_idx_pvc",
+			workflow:     "wf_sku_lookup",
+			old:          "_sku_idx_old",
+			query:        "select sku as sku, region as region, keyspace_id() as kid from orders_pvc",
+			reverseQuery: "select sku as sku, region as region, keyspace_id() as kid from _orders_old",
+		},
+	}, lookups)
+
+	// The lookup_hash vindexes store the column of the primary vindex.
+	_, err = getPrimaryVindexChangeLookups(vschema, "ks", "wf", "customer", "customer_pvc")
+	require.ErrorContains(t, err, "lookup vindex name_lookup of type lookup_hash does not store keyspace ids")
+
+	// The rows of the lookup vindexes of other tables do not move.
+	_, err = getPrimaryVindexChangeLookups(vschema, "ks", "wf", "items", "items_pvc")
+	require.ErrorContains(t, err, "lookup vindex name_lookup of table items is owned by table customer")
+}
+
+func TestBuildPrimaryVindexChangeShadowDDL(t *testing.T) {
+	parser := sqlparser.NewTestParser()
+	ddl, err := buildPrimaryVindexChangeShadowDDL("CREATE TABLE `orders` (\n  `id` bigint NOT NULL,\n  `account_id` bigint,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB", "orders_pvc", parser)
+	require.NoError(t, err)
+	require.Equal(t, "create table orders_pvc (\n\tid bigint not null,\n\taccount_id bigint,\n\tprimary key (id)\n) ENGINE InnoDB", ddl)
+
+	_, err = buildPrimaryVindexChangeShadowDDL("CREATE TABLE `orders` (\n  `id` bigint NOT NULL,\n  `account_id` bigint,\n  PRIMARY KEY (`id`),\n  CONSTRAINT `fk` FOREIGN KEY (`account_id`) REFERENCES `account` (`id`)\n)", "orders_pvc", parser)
+	require.ErrorContains(t, err, "table orders has foreign keys")
+}
+
+func TestSwitchPrimaryVindex(t *testing.T) {
+	vschema := primaryVindexChangeTestVSchema()
+	vschema.Tables["orders_pvc"] = &vschemapb.Table{ColumnVindexes: []*vschemapb.ColumnVindex{{Columns: []string{"account_id"}, Name: "xxhash"}}}
+	require.NoError(t, switchPrimaryVindex(vschema, "orders", "orders_pvc", "_orders_old"))
+	require.Equal(t, []*vschemapb.ColumnVindex{
+		{Columns: []string{"account_id"}, Name: "xxhash"},
+		{Column: "email", Name: "email_lookup"},
+		{Columns: []string{"sku", "region"}, Name: "sku_lookup"},
+	}, vschema.Tables["orders"].ColumnVindexes)
+	require.Equal(t, []*vschemapb.ColumnVindex{{Column: "id", Name: "hash"}}, vschema.Tables["_orders_old"].ColumnVindexes)
+	require.NotContains(t, vschema.Tables, "orders_pvc")
+
+	// Switching back restores the original primary vindex.
+	require.NoError(t, switchPrimaryVindex(vschema, "orders", "_orders_old", "orders_pvc"))
+	require.Equal(t, &vschemapb.ColumnVindex{Column: "id", Name: "hash"}, vschema.Tables["orders"].ColumnVindexes[0])
+	require.Equal(t, []*vschemapb.ColumnVindex{{Columns: []string{"account_id"}, Name: "xxhash"}}, vschema.Tables["orders_pvc"].ColumnVindexes)
+	require.NotContains(t, vschema.Tables, "_orders_old")
+
+	require.ErrorContains(t, switchPrimaryVindex(vschema, "customer", "customer_pvc", "_customer_old"), "table customer_pvc not found in the vschema")
+}
+
+func TestUpdatePrimaryVindexChangeDeniedTables(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.CreateShard(ctx, "ks", "0"))
+	ctx, unlock, err := ts.LockKeyspace(ctx, "ks", "TestUpdatePrimaryVindexChangeDeniedTables")
+	require.NoError(t, err)
+	defer unlock(&err)
+	si, err := ts.GetShard(ctx, "ks", "0")
+	require.NoError(t, err)
+	deniedTables := func(tabletType topodatapb.TabletType) []string {
+		return si.GetTabletControl(tabletType).GetDeniedTables()
+	}
+
+	require.NoError(t, updatePrimaryVindexChangeDeniedTables(ctx, si, false, []string{"email_idx", "orders"}))
+	for _, tabletType := range []topodatapb.TabletType{topodatapb.TabletType_PRIMARY, topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY} {
+		require.Equal(t, []string{"email_idx", "orders"}, deniedTables(tabletType))
+	}
+	// Denying the tables again, as a failed switch does, is a no-op.
+	require.NoError(t, updatePrimaryVindexChangeDeniedTables(ctx, si, false, []string{"email_idx", "orders"}))
+	require.NoError(t, updatePrimaryVindexChangeDeniedTables(ctx, si, true, []string{"email_idx", "orders"}))
+	require.Empty(t, si.TabletControls)
+
+	// The replica tablets already deny other tables, which are neither
+	// replaced nor allowed again.
+	require.NoError(t, si.UpdateDeniedTables(ctx, topodatapb.TabletType_REPLICA, nil, false, []string{"t1"}))
+	err = updatePrimaryVindexChangeDeniedTables(ctx, si, false, []string{"orders"})
+	require.ErrorContains(t, err, "shard ks/0 already denies tables [t1] on replica tablets")
+	require.NoError(t, updatePrimaryVindexChangeDeniedTables(ctx, si, true, []string{"orders"}))
+	require.Equal(t, []string{"t1"}, deniedTables(topodatapb.TabletType_REPLICA))
+	require.Empty(t, deniedTables(topodatapb.TabletType_PRIMARY))
+}
+
+// primaryVindexChangeTMClient keeps the tables and the vreplication streams
+// of each tablet, so that a ChangePrimaryVindex workflow can be run through
+// all of its steps.
+type primaryVindexChangeTMClient struct {
+	tmclient.TabletManagerClient
+
+	mu      sync.Mutex
+	parser  *sqlparser.Parser
+	tables  map[uint32]map[string]string
+	streams map[uint32][]*primaryVindexChangeTestStream
+	// gtids is the number of transactions executed by each tablet, which
+	// gives its position.
+	gtids  map[uint32]int
+	nextID int32
+	// renameErrors fails the renames of a table to the given name on a
+	// tablet.
+	renameErrors map[uint32]map[string]error
+}
+
+type primaryVindexChangeTestStream struct {
+	id           int32
+	workflow     string
+	workflowType binlogdatapb.VReplicationWorkflowType
+	options      string
+	bls          *binlogdatapb.BinlogSource
+	pos          string
+	state        binlogdatapb.VReplicationWorkflowState
+	message      string
+}
+
+func newPrimaryVindexChangeTMClient() *primaryVindexChangeTMClient {
+	return &primaryVindexChangeTMClient{
+		parser:       sqlparser.NewTestParser(),
+		tables:       make(map[uint32]map[string]string),
+		streams:      make(map[uint32][]*primaryVindexChangeTestStream),
+		gtids:        make(map[uint32]int),
+		renameErrors: make(map[uint32]map[string]error),
+	}
+}
+
+func (tmc *primaryVindexChangeTMClient) position(uid uint32) string {
+	return fmt.Sprintf("MySQL56/00000000-0000-0000-0000-%012d:1-%d", uid, tmc.gtids[uid])
+}
+
+func (tmc *primaryVindexChangeTMClient) tableNames(uid uint32) []string {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	names := make([]string, 0, len(tmc.tables[uid]))
+	for name := range tmc.tables[uid] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (tmc *primaryVindexChangeTMClient) workflowStreams(uid uint32, workflow string) []*primaryVindexChangeTestStream {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	var streams []*primaryVindexChangeTestStream
+	for _, stream := range tmc.streams[uid] {
+		if stream.workflow == workflow {
+			streams = append(streams, stream)
+		}
+	}
+	return streams
+}
+
+func (tmc *primaryVindexChangeTMClient) exec(uid uint32, query string) error {
+	stmt, err := tmc.parser.Parse(query)
+	if err != nil {
+		return err
+	}
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	tables := tmc.tables[uid]
+	switch stmt := stmt.(type) {
+	case *sqlparser.CreateTable:
+		tables[stmt.Table.Name.String()] = query
+	case *sqlparser.DropTable:
+		for _, table := range stmt.FromTables {
+			delete(tables, table.Name.String())
+		}
+	case *sqlparser.RenameTable:
+		for _, pair := range stmt.TablePairs {
+			if err := tmc.renameErrors[uid][pair.ToTable.Name.String()]; err != nil {
+				return err
+			}
+		}
+		for _, pair := range stmt.TablePairs {
+			from, to := pair.FromTable.Name.String(), pair.ToTable.Name.String()
+			if _, ok := tables[from]; !ok {
+				return fmt.Errorf("table %s does not exist", from)
+			}
+			if _, ok := tables[to]; ok {
+				return fmt.Errorf("table %s already exists", to)
+			}
+			tables[to] = tables[from]
+			delete(tables, from)
+		}
+	default:
+		return fmt.Errorf("unexpected query: %s", query)
+	}
+	tmc.gtids[uid]++
+	return nil
+}
+
+func (tmc *primaryVindexChangeTMClient) ExecuteFetchAsDba(ctx context.Context, tablet *topodatapb.Tablet, usePool bool, req *tabletmanagerdatapb.ExecuteFetchAsDbaRequest) (*querypb.QueryResult, error) {
+	if err := tmc.exec(tablet.Alias.Uid, string(req.Query)); err != nil {
+		return nil, err
+	}
+	return &querypb.QueryResult{}, nil
+}
+
+func (tmc *primaryVindexChangeTMClient) ApplySchema(ctx context.Context, tablet *topodatapb.Tablet, change *tmutils.SchemaChange) (*tabletmanagerdatapb.SchemaChangeResult, error) {
+	if err := tmc.exec(tablet.Alias.Uid, change.SQL); err != nil {
+		return nil, err
+	}
+	return &tabletmanagerdatapb.SchemaChangeResult{}, nil
+}
+
+func (tmc *primaryVindexChangeTMClient) GetSchema(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.GetSchemaRequest) (*tabletmanagerdatapb.SchemaDefinition, error) {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	sd := &tabletmanagerdatapb.SchemaDefinition{}
+	for name, ddl := range tmc.tables[tablet.Alias.Uid] {
+		if len(req.Tables) > 0 && !slices.Contains(req.Tables, name) {
+			continue
+		}
+		sd.TableDefinitions = append(sd.TableDefinitions, &tabletmanagerdatapb.TableDefinition{Name: name, Schema: ddl, Type: tmutils.TableBaseTable})
+	}
+	return sd, nil
+}
+
+func (tmc *primaryVindexChangeTMClient) PrimaryPosition(ctx context.Context, tablet *topodatapb.Tablet) (string, error) {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	return tmc.position(tablet.Alias.Uid), nil
+}
+
+func (tmc *primaryVindexChangeTMClient) VReplicationWaitForPos(ctx context.Context, tablet *topodatapb.Tablet, id int32, pos string) error {
+	return nil
+}
+
+func (tmc *primaryVindexChangeTMClient) RefreshState(ctx context.Context, tablet *topodatapb.Tablet) error {
+	return nil
+}
+
+func (tmc *primaryVindexChangeTMClient) VDiff(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.VDiffRequest) (*tabletmanagerdatapb.VDiffResponse, error) {
+	return &tabletmanagerdatapb.VDiffResponse{}, nil
+}
+
+func (tmc *primaryVindexChangeTMClient) ExecuteFetchAsAllPrivs(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.ExecuteFetchAsAllPrivsRequest) (*querypb.QueryResult, error) {
+	return &querypb.QueryResult{}, nil
+}
+
+func (tmc *primaryVindexChangeTMClient) ValidateVReplicationPermissions(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.ValidateVReplicationPermissionsRequest) (*tabletmanagerdatapb.ValidateVReplicationPermissionsResponse, error) {
+	return &tabletmanagerdatapb.ValidateVReplicationPermissionsResponse{Ok: true}, nil
+}
+
+func (tmc *primaryVindexChangeTMClient) ReadVReplicationWorkflows(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.ReadVReplicationWorkflowsRequest) (*tabletmanagerdatapb.ReadVReplicationWorkflowsResponse, error) {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	resp := &tabletmanagerdatapb.ReadVReplicationWorkflowsResponse{}
+	for _, stream := range tmc.streams[tablet.Alias.Uid] {
+		resp.Workflows = append(resp.Workflows, &tabletmanagerdatapb.ReadVReplicationWorkflowResponse{Workflow: stream.workflow})
+	}
+	return resp, nil
+}
+
+func (tmc *primaryVindexChangeTMClient) ReadVReplicationWorkflow(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.ReadVReplicationWorkflowRequest) (*tabletmanagerdatapb.ReadVReplicationWorkflowResponse, error) {
+	var resp *tabletmanagerdatapb.ReadVReplicationWorkflowResponse
+	for _, stream := range tmc.workflowStreams(tablet.Alias.Uid, req.Workflow) {
+		if resp == nil {
+			resp = &tabletmanagerdatapb.ReadVReplicationWorkflowResponse{
+				Workflow:     stream.workflow,
+				WorkflowType: stream.workflowType,
+				Options:      stream.options,
+			}
+		}
+		resp.Streams = append(resp.Streams, &tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream{
+			Id:      stream.id,
+			Bls:     stream.bls.CloneVT(),
+			Pos:     stream.pos,
+			State:   stream.state,
+			Message: stream.message,
+		})
+	}
+	return resp, nil
+}
+
+func (tmc *primaryVindexChangeTMClient) CreateVReplicationWorkflow(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.CreateVReplicationWorkflowRequest) (*tabletmanagerdatapb.CreateVReplicationWorkflowResponse, error) {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	for _, bls := range req.BinlogSource {
+		tmc.nextID++
+		tmc.streams[tablet.Alias.Uid] = append(tmc.streams[tablet.Alias.Uid], &primaryVindexChangeTestStream{
+			id:           tmc.nextID,
+			workflow:     req.Workflow,
+			workflowType: req.WorkflowType,
+			options:      req.Options,
+			bls:          bls,
+			state:        binlogdatapb.VReplicationWorkflowState_Stopped,
+		})
+	}
+	return &tabletmanagerdatapb.CreateVReplicationWorkflowResponse{}, nil
+}
+
+func (tmc *primaryVindexChangeTMClient) UpdateVReplicationWorkflow(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.UpdateVReplicationWorkflowRequest) (*tabletmanagerdatapb.UpdateVReplicationWorkflowResponse, error) {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	for _, stream := range tmc.streams[tablet.Alias.Uid] {
+		if stream.workflow == req.Workflow && req.State != nil {
+			stream.state = *req.State
+		}
+	}
+	return &tabletmanagerdatapb.UpdateVReplicationWorkflowResponse{}, nil
+}
+
+func (tmc *primaryVindexChangeTMClient) DeleteVReplicationWorkflow(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.DeleteVReplicationWorkflowRequest) (*tabletmanagerdatapb.DeleteVReplicationWorkflowResponse, error) {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	tmc.streams[tablet.Alias.Uid] = slices.DeleteFunc(tmc.streams[tablet.Alias.Uid], func(stream *primaryVindexChangeTestStream) bool {
+		return stream.workflow == req.Workflow
+	})
+	return &tabletmanagerdatapb.DeleteVReplicationWorkflowResponse{Result: &querypb.QueryResult{}}, nil
+}
+
+// VReplicationExec runs the statements that create the reverse streams,
+// update the state and position of the streams, and delete the streams of a
+// workflow.
+func (tmc *primaryVindexChangeTMClient) VReplicationExec(ctx context.Context, tablet *topodatapb.Tablet, query string) (*querypb.QueryResult, error) {
+	stmt, err := tmc.parser.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	value := func(expr sqlparser.Expr) string {
+		if lit, ok := expr.(*sqlparser.Literal); ok {
+			return lit.Val
+		}
+		return sqlparser.String(expr)
+	}
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	uid := tablet.Alias.Uid
+	switch stmt := stmt.(type) {
+	case *sqlparser.Insert:
+		values := make(map[string]string)
+		for i, expr := range stmt.Rows.(sqlparser.Values)[0] {
+			values[stmt.Columns[i].String()] = value(expr)
+		}
+		bls := &binlogdatapb.BinlogSource{}
+		if err := prototext.Unmarshal([]byte(values["source"]), bls); err != nil {
+			return nil, err
+		}
+		workflowType, _ := strconv.Atoi(values["workflow_type"])
+		tmc.nextID++
+		tmc.streams[uid] = append(tmc.streams[uid], &primaryVindexChangeTestStream{
+			id:           tmc.nextID,
+			workflow:     values["workflow"],
+			workflowType: binlogdatapb.VReplicationWorkflowType(workflowType),
+			options:      values["options"],
+			bls:          bls,
+			pos:          values["pos"],
+			state:        binlogdatapb.VReplicationWorkflowState(binlogdatapb.VReplicationWorkflowState_value[values["state"]]),
+		})
+		return &querypb.QueryResult{RowsAffected: 1, InsertId: uint64(tmc.nextID)}, nil
+	case *sqlparser.Update:
+		cmp, ok := stmt.Where.Expr.(*sqlparser.ComparisonExpr)
+		if !ok || sqlparser.String(cmp.Left) != "id" {
+			return nil, fmt.Errorf("unexpected query: %s", query)
+		}
+		id, _ := strconv.Atoi(value(cmp.Right))
+		for _, stream := range tmc.streams[uid] {
+			if stream.id != int32(id) {
+				continue
+			}
+			for _, update := range stmt.Exprs {
+				switch update.Name.Name.String() {
+				case "state":
+					stream.state = binlogdatapb.VReplicationWorkflowState(binlogdatapb.VReplicationWorkflowState_value[value(update.Expr)])
+				case "message":
+					stream.message = value(update.Expr)
+				case "pos":
+					stream.pos = value(update.Expr)
+				}
+			}
+			return &querypb.QueryResult{RowsAffected: 1}, nil
+		}
+		return &querypb.QueryResult{}, nil
+	case *sqlparser.Delete:
+		workflow := ""
+		for _, expr := range sqlparser.SplitAndExpression(nil, stmt.Where.Expr) {
+			if cmp, ok := expr.(*sqlparser.ComparisonExpr); ok && sqlparser.String(cmp.Left) == "workflow" {
+				workflow = value(cmp.Right)
+			}
+		}
+		if workflow == "" {
+			return nil, fmt.Errorf("unexpected query: %s", query)
+		}
+		n := len(tmc.streams[uid])
+		tmc.streams[uid] = slices.DeleteFunc(tmc.streams[uid], func(stream *primaryVindexChangeTestStream) bool {
+			return stream.workflow == workflow
+		})
+		return &querypb.QueryResult{RowsAffected: uint64(n - len(tmc.streams[uid]))}, nil
+	}
+	return nil, fmt.Errorf("unexpected query: %s", query)
+}
+
+// checkPrimaryVindexChangeDenyList checks the denied tables of the primary,
+// replica and rdonly tablets of the shard.
+func checkPrimaryVindexChangeDenyList(t *testing.T, ts *topo.Server, keyspace, shard string, want []string) {
+	t.Helper()
+	si, err := ts.GetShard(context.Background(), keyspace, shard)
+	require.NoError(t, err)
+	for _, tabletType := range []topodatapb.TabletType{topodatapb.TabletType_PRIMARY, topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY} {
+		var got []string
+		if tc := si.GetTabletControl(tabletType); tc != nil {
+			got = tc.DeniedTables
+		}
+		require.EqualValues(t, want, got, "denied tables of the %s tablets of %s/%s", tabletType, keyspace, shard)
+	}
+}
+
+// newPrimaryVindexChangeTestEnv returns a test env with the sharded ks
+// keyspace, whose orders table has an owned lookup vindex in the unsharded
+// lookup keyspace.
+func newPrimaryVindexChangeTestEnv(t *testing.T, ctx context.Context) (*testEnv, *Server, *primaryVindexChangeTMClient) {
+	env := newTestEnv(t, ctx, defaultCellName, &testKeyspace{
+		KeyspaceName: "ks",
+		ShardNames:   []string{"-80", "80-"},
+	}, &testKeyspace{
+		KeyspaceName: "lookup",
+		ShardNames:   []string{"0"},
+	})
+	err := env.ts.SaveVSchema(ctx, &topo.KeyspaceVSchemaInfo{
+		Name: "ks",
+		Keyspace: &vschemapb.Keyspace{
+			Sharded: true,
+			Vindexes: map[string]*vschemapb.Vindex{
+				"hash": {Type: "hash"},
+				"email_lookup": {
+					Type:   "consistent_lookup_unique",
+					Params: map[string]string{"table": "lookup.email_idx", "from": "email_addr", "to": "keyspace_id", "ignore_nulls": "true"},
+					Owner:  "orders",
+				},
+			},
+			Tables: map[string]*vschemapb.Table{
+				"orders": {ColumnVindexes: []*vschemapb.ColumnVindex{
+					{Column: "id", Name: "hash"},
+					{Column: "email", Name: "email_lookup"},
+				}},
+			},
+		},
+	})
+	require.NoError(t, err)
+	err = env.ts.SaveVSchema(ctx, &topo.KeyspaceVSchemaInfo{
+		Name: "lookup",
+		Keyspace: &vschemapb.Keyspace{
+			Tables: map[string]*vschemapb.Table{"email_idx": {}},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, env.ts.RebuildSrvVSchema(ctx, nil))
+
+	tmc := newPrimaryVindexChangeTMClient()
+	for _, uid := range []uint32{100, 110} {
+		tmc.tables[uid] = map[string]string{"orders": "create table orders (id bigint, account_id bigint, email varchar(128), primary key (id))"}
+	}
+	tmc.tables[200] = map[string]string{"email_idx": "create table email_idx (email_addr varchar(128), keyspace_id varbinary(128), primary key (email_addr))"}
+	return env, NewServer(vtenv.NewTestEnv(), env.ts, tmc), tmc
+}
+
+func TestChangePrimaryVindexWorkflow(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env, ws, tmc := newPrimaryVindexChangeTestEnv(t, ctx)
+	defer env.close()
+
+	checkTables := func(want map[uint32][]string) {
+		t.Helper()
+		for uid, tables := range want {
+			require.Equal(t, tables, tmc.tableNames(uid), "tables on tablet %d", uid)
+		}
+	}
+	checkStreams := func(uid uint32, workflow string, state binlogdatapb.VReplicationWorkflowState, count int) []*primaryVindexChangeTestStream {
+		t.Helper()
+		streams := tmc.workflowStreams(uid, workflow)
+		require.Len(t, streams, count, "streams of workflow %s on tablet %d", workflow, uid)
+		for _, stream := range streams {
+			require.Equal(t, state, stream.state, "state of stream %d of workflow %s on tablet %d", stream.id, workflow, uid)
+		}
+		return streams
+	}
+	getVSchema := func(keyspace string) *vschemapb.Keyspace {
+		t.Helper()
+		vschema, err := env.ts.GetVSchema(ctx, keyspace)
+		require.NoError(t, err)
+		return vschema.Keyspace
+	}
+	hash := &vschemapb.ColumnVindex{Column: "id", Name: "hash"}
+	xxhash := &vschemapb.ColumnVindex{Columns: []string{"account_id"}, Name: "xxhash"}
+
+	createResp, err := ws.ChangePrimaryVindexCreate(ctx, &vtctldatapb.ChangePrimaryVindexCreateRequest{
+		Workflow:      "wf",
+		Keyspace:      "ks",
+		Table:         "orders",
+		PrimaryVindex: &vschemapb.ColumnVindex{Name: "xxhash", Columns: []string{"account_id"}},
+		Vindexes:      map[string]*vschemapb.Vindex{"xxhash": {Type: "xxhash"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "orders_pvc", createResp.ShadowTable)
+	require.Equal(t, []string{"email_lookup"}, createResp.LookupVindexes)
+	checkTables(map[uint32][]string{100: {"orders", "orders_pvc"}, 110: {"orders", "orders_pvc"}, 200: {"email_idx", "email_idx_pvc"}})
+	for _, uid := range []uint32{100, 110} {
+		streams := checkStreams(uid, "wf", binlogdatapb.VReplicationWorkflowState_Running, 2)
+		require.Equal(t, "orders_pvc", streams[0].bls.Filter.Rules[0].Match)
+	}
+	streams := checkStreams(200, "wf_email_lookup", binlogdatapb.VReplicationWorkflowState_Running, 2)
+	require.Equal(t, "select email as email_addr, keyspace_id() as keyspace_id from orders_pvc where email is not null", streams[0].bls.Filter.Rules[0].Filter)
+	utils.MustMatch(t, []*vschemapb.ColumnVindex{xxhash}, getVSchema("ks").Tables["orders_pvc"].ColumnVindexes)
+
+	_, err = ws.ChangePrimaryVindexReverseTraffic(ctx, &vtctldatapb.ChangePrimaryVindexReverseTrafficRequest{Workflow: "wf", Keyspace: "ks"})
+	require.ErrorContains(t, err, "traffic of workflow wf has not been switched")
+	_, err = ws.ChangePrimaryVindexComplete(ctx, &vtctldatapb.ChangePrimaryVindexCompleteRequest{Workflow: "wf", Keyspace: "ks"})
+	require.ErrorContains(t, err, "traffic of workflow wf has not been switched, use cancel to remove it")
+
+	switchTraffic := func() {
+		t.Helper()
+		_, err := ws.ChangePrimaryVindexSwitchTraffic(ctx, &vtctldatapb.ChangePrimaryVindexSwitchTrafficRequest{Workflow: "wf", Keyspace: "ks", SkipVdiff: true})
+		require.NoError(t, err)
+
+		// The shadow tables replaced the tables, which are kept up to date by
+		// the reverse workflows, and the workflows are stopped but kept.
+		checkTables(map[uint32][]string{100: {"_orders_old", "orders"}, 110: {"_orders_old", "orders"}, 200: {"_email_idx_old", "email_idx"}})
+		vschema := getVSchema("ks")
+		utils.MustMatch(t, []*vschemapb.ColumnVindex{xxhash, {Column: "email", Name: "email_lookup"}}, vschema.Tables["orders"].ColumnVindexes)
+		utils.MustMatch(t, []*vschemapb.ColumnVindex{hash}, vschema.Tables["_orders_old"].ColumnVindexes)
+		require.NotContains(t, vschema.Tables, "orders_pvc")
+		for _, uid := range []uint32{100, 110} {
+			for _, stream := range checkStreams(uid, "wf", binlogdatapb.VReplicationWorkflowState_Stopped, 2) {
+				require.Equal(t, primaryVindexChangeStoppedMessage, stream.message)
+			}
+		}
+		checkStreams(200, "wf_email_lookup", binlogdatapb.VReplicationWorkflowState_Stopped, 2)
+		for i, uid := range []uint32{100, 110} {
+			keyRange := []string{"-80", "80-"}[i]
+			streams := checkStreams(uid, "wf_reverse", binlogdatapb.VReplicationWorkflowState_Running, 2)
+			for j, stream := range streams {
+				require.Equal(t, []string{"-80", "80-"}[j], stream.bls.Shard)
+				require.Equal(t, tmc.position([]uint32{100, 110}[j]), stream.pos)
+				utils.MustMatch(t, &binlogdatapb.Rule{
+					Match:  "_orders_old",
+					Filter: fmt.Sprintf("select * from orders where in_keyrange(id, 'ks.hash', '%s')", keyRange),
+				}, stream.bls.Filter.Rules[0])
+			}
+		}
+		streams := checkStreams(200, "wf_email_lookup_reverse", binlogdatapb.VReplicationWorkflowState_Running, 2)
+		utils.MustMatch(t, &binlogdatapb.Rule{
+			Match:  "_email_idx_old",
+			Filter: "select email as email_addr, keyspace_id() as keyspace_id from _orders_old where email is not null",
+		}, streams[0].bls.Filter.Rules[0])
+		checkPrimaryVindexChangeDenyList(t, env.ts, "ks", "-80", nil)
+		checkPrimaryVindexChangeDenyList(t, env.ts, "ks", "80-", nil)
+	}
+	switchTraffic()
+
+	_, err = ws.ChangePrimaryVindexSwitchTraffic(ctx, &vtctldatapb.ChangePrimaryVindexSwitchTrafficRequest{Workflow: "wf", Keyspace: "ks", SkipVdiff: true})
+	require.ErrorContains(t, err, "traffic of workflow wf has already been switched, use reversetraffic to switch it back")
+	_, err = ws.ChangePrimaryVindexCancel(ctx, &vtctldatapb.ChangePrimaryVindexCancelRequest{Workflow: "wf", Keyspace: "ks"})
+	require.ErrorContains(t, err, "traffic of workflow wf has been switched")
+
+	_, err = ws.ChangePrimaryVindexReverseTraffic(ctx, &vtctldatapb.ChangePrimaryVindexReverseTrafficRequest{Workflow: "wf", Keyspace: "ks"})
+	require.NoError(t, err)
+	// The original tables are back, the reverse workflows were removed, and
+	// the workflows continue from the current positions.
+	checkTables(map[uint32][]string{100: {"orders", "orders_pvc"}, 110: {"orders", "orders_pvc"}, 200: {"email_idx", "email_idx_pvc"}})
+	vschema := getVSchema("ks")
+	utils.MustMatch(t, []*vschemapb.ColumnVindex{hash, {Column: "email", Name: "email_lookup"}}, vschema.Tables["orders"].ColumnVindexes)
+	utils.MustMatch(t, []*vschemapb.ColumnVindex{xxhash}, vschema.Tables["orders_pvc"].ColumnVindexes)
+	require.NotContains(t, vschema.Tables, "_orders_old")
+	for _, uid := range []uint32{100, 110} {
+		checkStreams(uid, "wf_reverse", binlogdatapb.VReplicationWorkflowState_Running, 0)
+		for j, stream := range checkStreams(uid, "wf", binlogdatapb.VReplicationWorkflowState_Running, 2) {
+			require.Equal(t, tmc.position([]uint32{100, 110}[j]), stream.pos)
+		}
+	}
+	checkStreams(200, "wf_email_lookup", binlogdatapb.VReplicationWorkflowState_Running, 2)
+	checkStreams(200, "wf_email_lookup_reverse", binlogdatapb.VReplicationWorkflowState_Running, 0)
+	checkPrimaryVindexChangeDenyList(t, env.ts, "ks", "-80", nil)
+	checkPrimaryVindexChangeDenyList(t, env.ts, "ks", "80-", nil)
+
+	switchTraffic()
+	_, err = ws.ChangePrimaryVindexComplete(ctx, &vtctldatapb.ChangePrimaryVindexCompleteRequest{Workflow: "wf", Keyspace: "ks"})
+	require.NoError(t, err)
+	checkTables(map[uint32][]string{100: {"orders"}, 110: {"orders"}, 200: {"email_idx"}})
+	for _, uid := range []uint32{100, 110, 200} {
+		require.Empty(t, tmc.streams[uid])
+	}
+	vschema = getVSchema("ks")
+	utils.MustMatch(t, []*vschemapb.ColumnVindex{xxhash, {Column: "email", Name: "email_lookup"}}, vschema.Tables["orders"].ColumnVindexes)
+	require.NotContains(t, vschema.Tables, "_orders_old")
+}
+
+func TestChangePrimaryVindexSwitchTrafficFailure(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env, ws, tmc := newPrimaryVindexChangeTestEnv(t, ctx)
+	defer env.close()
+
+	_, err := ws.ChangePrimaryVindexCreate(ctx, &vtctldatapb.ChangePrimaryVindexCreateRequest{
+		Workflow:      "wf",
+		Keyspace:      "ks",
+		Table:         "orders",
+		PrimaryVindex: &vschemapb.ColumnVindex{Name: "xxhash", Columns: []string{"account_id"}},
+		Vindexes:      map[string]*vschemapb.Vindex{"xxhash": {Type: "xxhash"}},
+	})
+	require.NoError(t, err)
+	checkSwitchedBack := func() {
+		t.Helper()
+		for _, uid := range []uint32{100, 110} {
+			require.Equal(t, []string{"orders", "orders_pvc"}, tmc.tableNames(uid))
+			streams := tmc.workflowStreams(uid, "wf")
+			require.Len(t, streams, 2)
+			for j, stream := range streams {
+				require.Equal(t, binlogdatapb.VReplicationWorkflowState_Running, stream.state)
+				require.Equal(t, tmc.position([]uint32{100, 110}[j]), stream.pos)
+			}
+			require.Empty(t, tmc.workflowStreams(uid, "wf_reverse"))
+		}
+		require.Equal(t, []string{"email_idx", "email_idx_pvc"}, tmc.tableNames(200))
+		vschema, err := env.ts.GetVSchema(ctx, "ks")
+		require.NoError(t, err)
+		require.Equal(t, "hash", vschema.Tables["orders"].ColumnVindexes[0].Name)
+		require.Contains(t, vschema.Tables, "orders_pvc")
+		require.NotContains(t, vschema.Tables, "_orders_old")
+	}
+
+	// The table is swapped on the first shard but not on the second one, so
+	// the first shard is switched back and queries are allowed again.
+	tmc.renameErrors[110] = map[string]error{"_orders_old": errors.New("rename failed")}
+	_, err = ws.ChangePrimaryVindexSwitchTraffic(ctx, &vtctldatapb.ChangePrimaryVindexSwitchTrafficRequest{Workflow: "wf", Keyspace: "ks", SkipVdiff: true})
+	require.ErrorContains(t, err, "rename failed")
+	require.ErrorContains(t, err, "failed to switch traffic, which was switched back")
+	checkSwitchedBack()
+	checkPrimaryVindexChangeDenyList(t, env.ts, "ks", "-80", nil)
+	checkPrimaryVindexChangeDenyList(t, env.ts, "ks", "80-", nil)
+	checkPrimaryVindexChangeDenyList(t, env.ts, "lookup", "0", nil)
+
+	// When the first shard cannot be switched back either, queries to the
+	// table and its lookup table stay denied on all of the tablets until
+	// reversetraffic succeeds.
+	tmc.renameErrors[100] = map[string]error{"orders_pvc": errors.New("rename back failed")}
+	_, err = ws.ChangePrimaryVindexSwitchTraffic(ctx, &vtctldatapb.ChangePrimaryVindexSwitchTrafficRequest{Workflow: "wf", Keyspace: "ks", SkipVdiff: true})
+	require.ErrorContains(t, err, "rename back failed")
+	require.ErrorContains(t, err, "queries to table orders are denied until reversetraffic succeeds")
+	require.Equal(t, []string{"_orders_old", "orders"}, tmc.tableNames(100))
+	checkPrimaryVindexChangeDenyList(t, env.ts, "ks", "-80", []string{"orders"})
+	checkPrimaryVindexChangeDenyList(t, env.ts, "ks", "80-", []string{"orders"})
+	checkPrimaryVindexChangeDenyList(t, env.ts, "lookup", "0", []string{"email_idx"})
+	_, err = ws.ChangePrimaryVindexCancel(ctx, &vtctldatapb.ChangePrimaryVindexCancelRequest{Workflow: "wf", Keyspace: "ks"})
+	require.ErrorContains(t, err, "traffic of workflow wf has been switched")
+	_, err = ws.ChangePrimaryVindexComplete(ctx, &vtctldatapb.ChangePrimaryVindexCompleteRequest{Workflow: "wf", Keyspace: "ks"})
+	require.ErrorContains(t, err, "traffic of workflow wf was not completely switched, use reversetraffic to switch it back")
+
+	delete(tmc.renameErrors, 100)
+	_, err = ws.ChangePrimaryVindexReverseTraffic(ctx, &vtctldatapb.ChangePrimaryVindexReverseTrafficRequest{Workflow: "wf", Keyspace: "ks"})
+	require.NoError(t, err)
+	checkSwitchedBack()
+	checkPrimaryVindexChangeDenyList(t, env.ts, "ks", "-80", nil)
+	checkPrimaryVindexChangeDenyList(t, env.ts, "ks", "80-", nil)
+	checkPrimaryVindexChangeDenyList(t, env.ts, "lookup", "0", nil)
+
+	delete(tmc.renameErrors, 110)
+	_, err = ws.ChangePrimaryVindexSwitchTraffic(ctx, &vtctldatapb.ChangePrimaryVindexSwitchTrafficRequest{Workflow: "wf", Keyspace: "ks", SkipVdiff: true})
+	require.NoError(t, err)
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vschemaadvisor"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// planQuerySample plans the queries of a query sample with the planner of
+// vtgate, against the VSchema of the keyspace, or against vschema instead
+// when it is set. The other keyspaces keep their VSchema.
+func (s *Server) planQuerySample(ctx context.Context, keyspace string, vschema *vschemapb.Keyspace, querySample []string) ([]*vtctldatapb.QueryRouting, error) {
+	keyspaces, err := s.ts.GetKeyspaces(ctx)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "GetKeyspaces")
+	}
+	srvVSchema := &vschemapb.SrvVSchema{Keyspaces: make(map[string]*vschemapb.Keyspace, len(keyspaces))}
+	for _, ks := range keyspaces {
+		ksvs, err := s.ts.GetVSchema(ctx, ks)
+		switch {
+		case topo.IsErrType(err, topo.NoNode):
+			srvVSchema.Keyspaces[ks] = &vschemapb.Keyspace{}
+		case err != nil:
+			return nil, vterrors.Wrapf(err, "GetVSchema(%s)", ks)
+		default:
+			srvVSchema.Keyspaces[ks] = ksvs.Keyspace
+		}
+	}
+	if vschema != nil {
+		srvVSchema.Keyspaces[keyspace] = vschema
+	}
+
+	report, err := vschemaadvisor.PlanQueries(s.env, srvVSchema, keyspace, vschemaadvisor.CountQueries(querySample))
+	if err != nil {
+		return nil, err
+	}
+	routing := make([]*vtctldatapb.QueryRouting, 0, len(report.Queries))
+	for _, qr := range report.Queries {
+		routing = append(routing, &vtctldatapb.QueryRouting{
+			Query:   qr.SQL,
+			Routing: qr.Routing,
+			Error:   qr.Error,
+		})
+	}
+	return routing, nil
+}
+
+// unroutableQueries returns the queries that cannot be planned, and the
+// scatter queries unless they are allowed.
+func unroutableQueries(routing []*vtctldatapb.QueryRouting, allowScatter bool) []*vtctldatapb.QueryRouting {
+	var unroutable []*vtctldatapb.QueryRouting
+	for _, qr := range routing {
+		if qr.Routing == vschemaadvisor.RoutingUnsupported || (qr.Routing == vschemaadvisor.RoutingScatter && !allowScatter) {
+			unroutable = append(unroutable, qr)
+		}
+	}
+	return unroutable
+}
+
+// describeQueryRouting lists queries and their routing, one per line.
+func describeQueryRouting(routing []*vtctldatapb.QueryRouting) string {
+	var sb strings.Builder
+	for _, qr := range routing {
+		fmt.Fprintf(&sb, "  [%s] %s", qr.Routing, qr.Query)
+		if qr.Error != "" {
+			fmt.Fprintf(&sb, ": %s", qr.Error)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// checkQueryRouting returns the reason why traffic cannot be switched to the
+// target keyspace of a workflow when the query sample of the workflow does
+// not plan with the current VSchema of that keyspace.
+func (s *Server) checkQueryRouting(ctx context.Context, ts *trafficSwitcher) (string, error) {
+	querySample := ts.options.GetQuerySample()
+	if len(querySample) == 0 {
+		return "", nil
+	}
+	routing, err := s.planQuerySample(ctx, ts.targetKeyspace, nil, querySample)
+	if err != nil {
+		return "", err
+	}
+	unroutable := unroutableQueries(routing, ts.options.GetAllowScatterQueries())
+	if len(unroutable) == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf(cannotSwitchQueryRouting, len(unroutable), ts.targetKeyspace, describeQueryRouting(unroutable)), nil
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vtgate/vschemaadvisor"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func TestReshardCreateQuerySample(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	keyspace := "targetks"
+	env := newTestEnv(t, ctx, defaultCellName,
+		&testKeyspace{KeyspaceName: keyspace, ShardNames: []string{"0"}},
+		&testKeyspace{KeyspaceName: keyspace, ShardNames: []string{"-80", "80-"}})
+	defer env.close()
+
+	// The tables of the query sample are not qualified, so they must be
+	// looked up in the keyspace being resharded even when another keyspace
+	// has a table with the same name.
+	require.NoError(t, env.ts.CreateKeyspace(ctx, "otherks", &topodatapb.Keyspace{}))
+	require.NoError(t, env.ts.SaveVSchema(ctx, &topo.KeyspaceVSchemaInfo{
+		Name:     "otherks",
+		Keyspace: &vschemapb.Keyspace{Tables: map[string]*vschemapb.Table{"t1": {}}},
+	}))
+
+	shardedVSchema := &vschemapb.Keyspace{
+		Sharded:  true,
+		Vindexes: map[string]*vschemapb.Vindex{"xxhash": {Type: "xxhash"}},
+		Tables: map[string]*vschemapb.Table{
+			"t1": {ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "id", Name: "xxhash"}}},
+		},
+	}
+	querySample := []string{
+		"select * from t1 where id = 1",
+		"select * from t1 where name = 'a'",
+		"select * from t1 where id = 1",
+		"select * from nope",
+	}
+	newRequest := func() *vtctldatapb.ReshardCreateRequest {
+		return &vtctldatapb.ReshardCreateRequest{
+			Keyspace:        keyspace,
+			Workflow:        "wf1",
+			SourceShards:    []string{"0"},
+			TargetShards:    []string{"-80", "80-"},
+			Cells:           []string{env.cell},
+			Vschema:         shardedVSchema,
+			WorkflowOptions: &vtctldatapb.WorkflowOptions{QuerySample: querySample},
+		}
+	}
+	origVSchema, err := env.ts.GetVSchema(ctx, keyspace)
+	require.NoError(t, err)
+
+	req := newRequest()
+	req.DryRun = true
+	resp, err := env.ws.ReshardCreate(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, resp.QueryRouting, 3)
+	assert.Equal(t, vschemaadvisor.RoutingSingleShard, resp.QueryRouting[0].Routing)
+	assert.Equal(t, vschemaadvisor.RoutingScatter, resp.QueryRouting[1].Routing)
+	assert.Equal(t, vschemaadvisor.RoutingUnsupported, resp.QueryRouting[2].Routing)
+	assert.NotEmpty(t, resp.QueryRouting[2].Error)
+	assert.Empty(t, resp.ShardStreams)
+
+	// The queries that cannot be routed prevent the workflow from being
+	// created, and the vschema is left as it is.
+	_, err = env.ws.ReshardCreate(ctx, newRequest())
+	require.ErrorContains(t, err, "2 queries of the query sample cannot be routed with the vschema of keyspace targetks")
+	require.ErrorContains(t, err, "[scatter] select * from t1 where name = 'a'")
+	req = newRequest()
+	req.WorkflowOptions.AllowScatterQueries = true
+	_, err = env.ws.ReshardCreate(ctx, req)
+	require.ErrorContains(t, err, "1 queries of the query sample cannot be routed")
+	vschema, err := env.ts.GetVSchema(ctx, keyspace)
+	require.NoError(t, err)
+	assert.Equal(t, origVSchema.Keyspace, vschema.Keyspace)
+
+	req = newRequest()
+	req.Vschema = &vschemapb.Keyspace{}
+	_, err = env.ws.ReshardCreate(ctx, req)
+	require.EqualError(t, err, "the vschema of keyspace targetks must be sharded")
+
+	req = newRequest()
+	req.DryRun = true
+	req.WorkflowOptions = nil
+	_, err = env.ws.ReshardCreate(ctx, req)
+	require.EqualError(t, err, "a dry run requires a query sample")
+}
+
+func TestCheckQueryRouting(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	keyspace := "targetks"
+	env := newTestEnv(t, ctx, defaultCellName,
+		&testKeyspace{KeyspaceName: keyspace, ShardNames: []string{"0"}},
+		&testKeyspace{KeyspaceName: keyspace, ShardNames: []string{"-80", "80-"}})
+	defer env.close()
+
+	vschema, err := env.ts.GetVSchema(ctx, keyspace)
+	require.NoError(t, err)
+	vschema.Keyspace = &vschemapb.Keyspace{
+		Sharded:  true,
+		Vindexes: map[string]*vschemapb.Vindex{"xxhash": {Type: "xxhash"}},
+		Tables: map[string]*vschemapb.Table{
+			"t1": {ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "id", Name: "xxhash"}}},
+		},
+	}
+	require.NoError(t, env.ts.SaveVSchema(ctx, vschema))
+
+	testCases := []struct {
+		name    string
+		options *vtctldatapb.WorkflowOptions
+		want    string
+	}{
+		{
+			name: "no query sample",
+		},
+		{
+			name:    "routable queries",
+			options: &vtctldatapb.WorkflowOptions{QuerySample: []string{"select * from t1 where id = 1"}},
+		},
+		{
+			name:    "scatter query",
+			options: &vtctldatapb.WorkflowOptions{QuerySample: []string{"select * from t1 where id = 1", "select count(*) from t1"}},
+			want:    "1 queries of the query sample cannot be routed with the vschema of keyspace targetks, use --force to switch anyway:\n  [scatter] select count(*) from t1\n",
+		},
+		{
+			name: "allowed scatter query",
+			options: &vtctldatapb.WorkflowOptions{
+				QuerySample:         []string{"select count(*) from t1"},
+				AllowScatterQueries: true,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := &trafficSwitcher{targetKeyspace: keyspace, options: tc.options}
+			reason, err := env.ws.checkQueryRouting(ctx, ts)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, reason)
+		})
+	}
+}
@@ -103,6 +103,7 @@ const (
 	cannotSwitchHighLag             = "replication lag %ds is higher than allowed lag %ds"
 	cannotSwitchFailedTabletRefresh = "could not refresh all of the tablets involved in the operation:\n%s"
 	cannotSwitchFrozen              = "workflow is frozen"
+	cannotSwitchQueryRouting        = "%d queries of the query sample cannot be routed with the vschema of keyspace %s, use --force to switch anyway:\n%s"
 
 	// Number of LOCK TABLES cycles to perform on the sources during SwitchWrites.
 	lockTablesCycles = 2
@@ -160,6 +161,10 @@ func (s *Server) SQLParser() *sqlparser.Parser {
 	return s.env.Parser()
 }
 
+func (s *Server) Environment() *vtenv.Environment {
+	return s.env
+}
+
 // CheckReshardingJournalExistsOnTablet returns the journal (or an empty
 // journal) and a boolean to indicate if the resharding_journal table exists on
 // the given tablet.
@@ -1499,20 +1504,75 @@ func (s *Server) ReshardCreate(ctx context.Context, req *vtctldatapb.ReshardCrea
 		s.Logger().Errorf("%v", err2)
 		return nil, err
 	}
+
+	if vschema := req.GetVschema(); vschema != nil {
+		if !vschema.Sharded {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the vschema of keyspace %s must be sharded", keyspace)
+		}
+		if _, err := vindexes.BuildKeyspace(vschema, s.env.Parser()); err != nil {
+			return nil, vterrors.Wrapf(err, "BuildKeyspace(%s)", keyspace)
+		}
+	}
+
+	var queryRouting []*vtctldatapb.QueryRouting
+	if querySample := req.GetWorkflowOptions().GetQuerySample(); len(querySample) > 0 {
+		var err error
+		if queryRouting, err = s.planQuerySample(ctx, keyspace, req.GetVschema(), querySample); err != nil {
+			return nil, vterrors.Wrap(err, "planQuerySample")
+		}
+		if req.GetDryRun() {
+			return &vtctldatapb.WorkflowStatusResponse{QueryRouting: queryRouting}, nil
+		}
+		if unroutable := unroutableQueries(queryRouting, req.GetWorkflowOptions().GetAllowScatterQueries()); len(unroutable) > 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "%d queries of the query sample cannot be routed with the vschema of keyspace %s:\n%s",
+				len(unroutable), keyspace, describeQueryRouting(unroutable))
+		}
+	} else if req.GetDryRun() {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a dry run requires a query sample")
+	}
+
+	restoreVSchema := func(err error) error { return err }
+	if req.GetVschema() != nil {
+		// The vschema is applied before the workflow is created, as the
+		// filters of its streams are built from the vindexes of the vschema.
+		vschema, err := s.ts.GetVSchema(ctx, keyspace)
+		if err != nil {
+			return nil, vterrors.Wrap(err, "GetVSchema")
+		}
+		origVSchema := vschema.Keyspace
+		restoreVSchema = func(err error) error {
+			vschema.Keyspace = origVSchema
+			if serr := s.ts.SaveVSchema(ctx, vschema); serr != nil {
+				return vterrors.Wrapf(err, "failed to restore the original vschema of the %s keyspace: %v", keyspace, serr)
+			}
+			if serr := s.ts.RebuildSrvVSchema(ctx, nil); serr != nil {
+				return vterrors.Wrapf(err, "failed to rebuild the SrvVSchema: %v", serr)
+			}
+			return err
+		}
+		vschema.Keyspace = req.GetVschema()
+		if err := s.ts.SaveVSchema(ctx, vschema); err != nil {
+			return nil, restoreVSchema(err)
+		}
+		if err := s.ts.RebuildSrvVSchema(ctx, nil); err != nil {
+			return nil, restoreVSchema(err)
+		}
+	}
+
 	rs, err := s.buildResharder(ctx, req)
 	if err != nil {
-		return nil, vterrors.Wrap(err, "buildResharder")
+		return nil, restoreVSchema(vterrors.Wrap(err, "buildResharder"))
 	}
 	rs.onDDL = req.OnDdl
 	rs.stopAfterCopy = req.StopAfterCopy
 	rs.deferSecondaryKeys = req.DeferSecondaryKeys
 	if !req.SkipSchemaCopy {
 		if err := rs.copySchema(ctx); err != nil {
-			return nil, vterrors.Wrap(err, "copySchema")
+			return nil, restoreVSchema(vterrors.Wrap(err, "copySchema"))
 		}
 	}
 	if err := rs.createStreams(ctx); err != nil {
-		return nil, vterrors.Wrap(err, "createStreams")
+		return nil, restoreVSchema(vterrors.Wrap(err, "createStreams"))
 	}
 
 	if req.AutoStart {
@@ -1522,11 +1582,16 @@ func (s *Server) ReshardCreate(ctx context.Context, req *vtctldatapb.ReshardCrea
 	} else {
 		s.Logger().Warningf("Streams will not be started since --auto-start is set to false")
 	}
-	return s.WorkflowStatus(ctx, &vtctldatapb.WorkflowStatusRequest{
+	resp, err := s.WorkflowStatus(ctx, &vtctldatapb.WorkflowStatusRequest{
 		Keyspace: req.Keyspace,
 		Workflow: req.Workflow,
 		Shards:   req.TargetShards,
 	})
+	if err != nil {
+		return nil, err
+	}
+	resp.QueryRouting = queryRouting
+	return resp, nil
 }
 
 // WorkflowDelete is part of the vtctlservicepb.VtctldServer interface.
@@ -3325,6 +3390,12 @@ func (s *Server) canSwitch(ctx context.Context, ts *trafficSwitcher, maxAllowedR
 		}
 	}
 
+	if !ts.force {
+		if reason, err := s.checkQueryRouting(ctx, ts); err != nil || reason != "" {
+			return reason, err
+		}
+	}
+
 	// Ensure that the tablets on both sides are in good shape as we make this same call in the
 	// process and an error will cause us to backout.
 	refreshErrors := strings.Builder{}
@@ -1056,3 +1056,111 @@ func processWorkflowActionOptions(opts []WorkflowActionOption) workflowActionOpt
 	}
 	return options
 }
+
+// workflowTargets are the primaries of all of the serving shards of a
+// keyspace, as the targets of a trafficSwitcher for a workflow, along with
+// the workflow's streams on each of them.
+type workflowTargets struct {
+	ts *trafficSwitcher
+	// shards are the names of the serving shards, in order.
+	shards []string
+	// streams are the workflow's streams on each shard.
+	streams map[string][]*tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream
+}
+
+// first returns the target of the keyspace's first serving shard.
+func (targets *workflowTargets) first() *MigrationTarget {
+	return targets.ts.targets[targets.shards[0]]
+}
+
+// newWorkflowTargets returns the primary of each serving shard in the
+// keyspace. When a workflow is specified, its streams on each primary, if
+// any, are also read.
+func (s *Server) newWorkflowTargets(ctx context.Context, keyspace, workflow string) (*workflowTargets, error) {
+	shards, err := s.ts.GetServingShards(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	if len(shards) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "keyspace %s has no serving shards", keyspace)
+	}
+	targets := &workflowTargets{
+		ts: &trafficSwitcher{
+			ws:             s,
+			logger:         s.Logger(),
+			workflow:       workflow,
+			targetKeyspace: keyspace,
+			targets:        make(map[string]*MigrationTarget, len(shards)),
+			options:        &vtctldatapb.WorkflowOptions{},
+		},
+		shards:  make([]string, 0, len(shards)),
+		streams: make(map[string][]*tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream, len(shards)),
+	}
+	for _, si := range shards {
+		if si.PrimaryAlias == nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "shard %s/%s has no primary", keyspace, si.ShardName())
+		}
+		primary, err := s.ts.GetTablet(ctx, si.PrimaryAlias)
+		if err != nil {
+			return nil, err
+		}
+		targets.shards = append(targets.shards, si.ShardName())
+		targets.ts.targets[si.ShardName()] = &MigrationTarget{
+			si:      si,
+			primary: primary,
+			Sources: make(map[int32]*binlogdatapb.BinlogSource),
+		}
+	}
+	if workflow == "" {
+		return targets, nil
+	}
+	var mu sync.Mutex
+	err = targets.ts.ForAllTargets(func(target *MigrationTarget) error {
+		res, err := s.tmc.ReadVReplicationWorkflow(ctx, target.primary.Tablet, &tabletmanagerdatapb.ReadVReplicationWorkflowRequest{
+			Workflow: workflow,
+		})
+		if err != nil {
+			return err
+		}
+		if res == nil || len(res.Streams) == 0 {
+			return nil
+		}
+		for _, stream := range res.Streams {
+			target.Sources[stream.Id] = stream.Bls
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		targets.streams[target.si.ShardName()] = res.Streams
+		targets.ts.workflowType = res.WorkflowType
+		targets.ts.workflowSubType = res.WorkflowSubType
+		if res.Options != "" {
+			if err := json.Unmarshal([]byte(res.Options), targets.ts.options); err != nil {
+				return vterrors.Wrapf(err, "failed to unmarshal options: %s", res.Options)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return targets, nil
+}
+
+// getWorkflowTargets returns the primary of each serving shard in the target
+// keyspace. When a workflow is specified, its streams on each primary are
+// also read and the workflow must be of the given type.
+func (s *Server) getWorkflowTargets(ctx context.Context, keyspace, workflow string, workflowType binlogdatapb.VReplicationWorkflowType) (*workflowTargets, error) {
+	targets, err := s.newWorkflowTargets(ctx, keyspace, workflow)
+	if err != nil || workflow == "" {
+		return targets, err
+	}
+	for _, shard := range targets.shards {
+		if len(targets.streams[shard]) == 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "workflow %s not found on shard %s/%s", workflow, keyspace, shard)
+		}
+	}
+	if targets.ts.workflowType != workflowType {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "workflow %s is a %s workflow, not a %s workflow", workflow, targets.ts.workflowType, workflowType)
+	}
+	return targets, nil
+}
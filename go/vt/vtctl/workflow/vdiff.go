@@ -308,6 +308,9 @@ func (s *Server) VDiffCreate(ctx context.Context, req *vtctldatapb.VDiffCreateRe
 	if req.AutoStart != nil {
 		span.Annotate("auto_start", req.GetAutoStart())
 	}
+	span.Annotate("chunk_rows", req.ChunkRows)
+	span.Annotate("rediff_uuid", req.RediffUuid)
+	span.Annotate("chunk_concurrency", req.ChunkConcurrency)
 
 	var err error
 	req.Uuid = strings.TrimSpace(req.Uuid)
@@ -318,6 +321,21 @@ func (s *Server) VDiffCreate(ctx context.Context, req *vtctldatapb.VDiffCreateRe
 			return nil, vterrors.Wrapf(err, "invalid UUID provided: %s", req.Uuid)
 		}
 	}
+	req.RediffUuid = strings.TrimSpace(req.RediffUuid)
+	if req.RediffUuid != "" {
+		if err = uuid.Validate(req.RediffUuid); err != nil {
+			return nil, vterrors.Wrapf(err, "invalid rediff UUID provided: %s", req.RediffUuid)
+		}
+		if req.RediffUuid == req.Uuid {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a vdiff cannot rediff itself")
+		}
+	}
+	if req.ChunkRows < 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid chunk rows value: %d", req.ChunkRows)
+	}
+	if req.ChunkConcurrency < 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid chunk concurrency value: %d", req.ChunkConcurrency)
+	}
 
 	tabletTypesStr := discovery.BuildTabletTypesString(req.TabletTypes, req.TabletSelectionPreference)
 
@@ -362,6 +380,9 @@ func (s *Server) VDiffCreate(ctx context.Context, req *vtctldatapb.VDiffCreateRe
 			UpdateTableStats:      req.UpdateTableStats,
 			MaxDiffSeconds:        req.MaxDiffDuration.Seconds,
 			AutoStart:             &autoStart,
+			ChunkRows:             req.ChunkRows,
+			RediffUuid:            req.RediffUuid,
+			ChunkConcurrency:      req.ChunkConcurrency,
 		},
 		ReportOptions: &tabletmanagerdatapb.VDiffReportOptions{
 			OnlyPks:                 req.OnlyPKs,
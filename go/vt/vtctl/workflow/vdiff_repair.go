@@ -0,0 +1,961 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/trace"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vdiff"
+	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vreplication"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	// defaultVDiffRepairBatchSize is the number of rows that we fetch and
+	// fix at a time when no batch size is specified.
+	defaultVDiffRepairBatchSize = 100
+	// vdiffRepairThrottleInterval is how long we wait before checking the
+	// target primary's throttler again when the repair is being throttled.
+	vdiffRepairThrottleInterval = 1 * time.Second
+)
+
+// vdiffRepairTable is a table on a target shard with the primary key values
+// of the rows that the vdiff found to differ.
+type vdiffRepairTable struct {
+	shard  string
+	table  string
+	pkCols []string
+	// pks holds the values, as strings, of the primary key columns for
+	// each row that differs.
+	pks [][]string
+}
+
+// VDiffRepair is part of the vtctlservicepb.VtctldServer interface. It uses
+// the report of a completed vdiff to generate the statements that bring the
+// rows on the target in line with the current rows on the source, and
+// optionally applies them on the target primaries.
+func (s *Server) VDiffRepair(ctx context.Context, req *vtctldatapb.VDiffRepairRequest) (*vtctldatapb.VDiffRepairResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.VDiffRepair")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.TargetKeyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("uuid", req.Uuid)
+	span.Annotate("tables", req.Tables)
+	span.Annotate("apply", req.Apply)
+	span.Annotate("batch_size", req.BatchSize)
+
+	if _, err := uuid.Parse(req.Uuid); err != nil {
+		return nil, vterrors.Wrapf(err, "invalid UUID provided: %s", req.Uuid)
+	}
+	batchSize := int(req.BatchSize)
+	switch {
+	case batchSize < 0:
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid batch size %d: must be greater than 0", req.BatchSize)
+	case batchSize == 0:
+		batchSize = defaultVDiffRepairBatchSize
+	}
+
+	ts, err := s.buildTrafficSwitcher(ctx, req.TargetKeyspace, req.Workflow)
+	if err != nil {
+		return nil, err
+	}
+	if ts.frozen {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "workflow %s.%s is frozen: writes have already been switched, so the source can no longer be used to repair the target",
+			req.TargetKeyspace, req.Workflow)
+	}
+	switch ts.workflowType {
+	case binlogdatapb.VReplicationWorkflowType_MoveTables, binlogdatapb.VReplicationWorkflowType_Reshard:
+	default:
+		return nil, vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "repairing %s workflows is not supported, only MoveTables and Reshard workflows can be repaired",
+			ts.workflowType)
+	}
+	if ts.sourceTimeZone != "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "repairing workflows that convert time zones is not supported")
+	}
+
+	repairTables, err := s.getVDiffRepairTables(ctx, ts, req)
+	if err != nil {
+		return nil, err
+	}
+	resp := &vtctldatapb.VDiffRepairResponse{}
+	if len(repairTables) == 0 {
+		return resp, nil
+	}
+
+	// We cannot repair a row that the workflow could still be changing as
+	// the source and target would keep diverging.
+	if err := s.checkVDiffRepairStreams(ctx, ts, repairTables); err != nil {
+		return nil, err
+	}
+
+	vschemas := s.vdiffRepairVSchemas(ctx, ts)
+	for _, rt := range repairTables {
+		target := ts.targets[rt.shard]
+		sourceQueries, targetQuery, err := s.buildVDiffRepairQueries(ctx, ts, target, rt, vschemas)
+		if err != nil {
+			return nil, err
+		}
+		for start := 0; start < len(rt.pks); start += batchSize {
+			end := min(start+batchSize, len(rt.pks))
+			stmts, err := s.buildVDiffRepairStatements(ctx, rt, sourceQueries, targetQuery, rt.pks[start:end])
+			if err != nil {
+				return nil, err
+			}
+			if len(stmts) == 0 {
+				continue
+			}
+			if req.Apply {
+				if err := s.applyVDiffRepairStatements(ctx, target, stmts); err != nil {
+					return nil, vterrors.Wrapf(err, "failed to apply repair statements for table %s on shard %s", rt.table, rt.shard)
+				}
+			}
+			for _, stmt := range stmts {
+				resp.Statements = append(resp.Statements, &vtctldatapb.VDiffRepairStatement{
+					Shard: rt.shard,
+					Table: rt.table,
+					Sql:   stmt,
+				})
+			}
+		}
+	}
+	resp.Applied = req.Apply
+	return resp, nil
+}
+
+// getVDiffRepairTables returns the tables on each target shard that have
+// differences which need to be repaired. It returns an error if the vdiff has
+// not completed on every shard or if its report does not include every row
+// that differs.
+func (s *Server) getVDiffRepairTables(ctx context.Context, ts *trafficSwitcher, req *vtctldatapb.VDiffRepairRequest) ([]*vdiffRepairTable, error) {
+	tabletreq := &tabletmanagerdatapb.VDiffRequest{
+		Keyspace:  req.TargetKeyspace,
+		Workflow:  req.Workflow,
+		Action:    string(vdiff.ShowAction),
+		ActionArg: req.Uuid,
+	}
+	var (
+		mu           sync.Mutex
+		repairTables []*vdiffRepairTable
+	)
+	err := ts.ForAllTargets(func(target *MigrationTarget) error {
+		shard := target.GetShard().ShardName()
+		resp, err := s.tmc.VDiff(ctx, target.GetPrimary().Tablet, tabletreq)
+		if err != nil {
+			return err
+		}
+		reports, err := parseVDiffRepairReports(shard, resp)
+		if err != nil {
+			return err
+		}
+		for _, dr := range reports {
+			if len(req.Tables) > 0 && !slices.Contains(req.Tables, dr.TableName) {
+				continue
+			}
+			if dr.MismatchedRows == 0 && dr.ExtraRowsSource == 0 && dr.ExtraRowsTarget == 0 {
+				continue
+			}
+			pkCols, err := s.getPrimaryKeyColumns(ctx, target.GetPrimary(), dr.TableName)
+			if err != nil {
+				return err
+			}
+			rt, err := newVDiffRepairTable(shard, pkCols, dr)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			repairTables = append(repairTables, rt)
+			mu.Unlock()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(repairTables, func(i, j int) bool {
+		if repairTables[i].shard != repairTables[j].shard {
+			return repairTables[i].shard < repairTables[j].shard
+		}
+		return repairTables[i].table < repairTables[j].table
+	})
+	return repairTables, nil
+}
+
+// parseVDiffRepairReports returns the table reports from a target shard's
+// vdiff show response, ensuring that the vdiff completed on the shard.
+func parseVDiffRepairReports(shard string, resp *tabletmanagerdatapb.VDiffResponse) ([]*vdiff.DiffReport, error) {
+	if resp == nil || resp.Output == nil || len(resp.Output.Rows) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "vdiff not found on shard %s", shard)
+	}
+	qr := sqltypes.Proto3ToResult(resp.Output)
+	var reports []*vdiff.DiffReport
+	for _, row := range qr.Named().Rows {
+		if state := vdiff.VDiffState(strings.ToLower(row.AsString("vdiff_state", ""))); state != vdiff.CompletedState {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "vdiff is in the %s state on shard %s, it must be completed before its results can be used",
+				state, shard)
+		}
+		report := row.AsString("report", "")
+		if report == "" {
+			continue
+		}
+		dr := &vdiff.DiffReport{}
+		if err := json.Unmarshal([]byte(report), dr); err != nil {
+			return nil, vterrors.Wrapf(err, "failed to parse the vdiff report for table %s on shard %s", row.AsString("table_name", ""), shard)
+		}
+		reports = append(reports, dr)
+	}
+	return reports, nil
+}
+
+// newVDiffRepairTable builds a vdiffRepairTable from a table's diff report.
+// The report must include a sample for every row that differs, otherwise
+// we'd only repair part of the table.
+func newVDiffRepairTable(shard string, pkCols []string, dr *vdiff.DiffReport) (*vdiffRepairTable, error) {
+	if int64(len(dr.MismatchedRowsDiffs)) != dr.MismatchedRows ||
+		int64(len(dr.ExtraRowsSourceDiffs)) != dr.ExtraRowsSource ||
+		int64(len(dr.ExtraRowsTargetDiffs)) != dr.ExtraRowsTarget {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION,
+			"the vdiff report for table %s on shard %s does not include every row that differs; create a new vdiff with --max-report-sample-rows=0 and a --max-extra-rows-to-compare value greater than %d",
+			dr.TableName, shard, max(dr.ExtraRowsSource, dr.ExtraRowsTarget))
+	}
+	rt := &vdiffRepairTable{
+		shard:  shard,
+		table:  dr.TableName,
+		pkCols: pkCols,
+	}
+	seen := make(map[string]bool)
+	add := func(rd *vdiff.RowDiff) error {
+		if rd == nil {
+			return nil
+		}
+		pk := make([]string, len(pkCols))
+		for i, col := range pkCols {
+			val, ok := rd.Row[col]
+			if !ok {
+				val, ok = rd.Row[sqlparser.String(sqlparser.NewColName(col))]
+			}
+			if !ok {
+				return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the vdiff report for table %s on shard %s does not include the value of primary key column %s",
+					dr.TableName, shard, col)
+			}
+			pk[i] = val
+		}
+		key := vdiffRepairKey(pk)
+		if !seen[key] {
+			seen[key] = true
+			rt.pks = append(rt.pks, pk)
+		}
+		return nil
+	}
+	for _, mm := range dr.MismatchedRowsDiffs {
+		rd := mm.Target
+		if rd == nil {
+			rd = mm.Source
+		}
+		if err := add(rd); err != nil {
+			return nil, err
+		}
+	}
+	for _, rd := range dr.ExtraRowsSourceDiffs {
+		if err := add(rd); err != nil {
+			return nil, err
+		}
+	}
+	for _, rd := range dr.ExtraRowsTargetDiffs {
+		if err := add(rd); err != nil {
+			return nil, err
+		}
+	}
+	return rt, nil
+}
+
+// getPrimaryKeyColumns returns the primary key columns of the table on the
+// given tablet.
+func (s *Server) getPrimaryKeyColumns(ctx context.Context, tablet *topo.TabletInfo, table string) ([]string, error) {
+	schema, err := s.tmc.GetSchema(ctx, tablet.Tablet, &tabletmanagerdatapb.GetSchemaRequest{Tables: []string{table}})
+	if err != nil {
+		return nil, err
+	}
+	if schema == nil || len(schema.TableDefinitions) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "table %s not found on tablet %s", table, tablet.AliasString())
+	}
+	pkCols := schema.TableDefinitions[0].PrimaryKeyColumns
+	if len(pkCols) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s has no primary key, so it cannot be repaired", table)
+	}
+	return pkCols, nil
+}
+
+// checkVDiffRepairStreams returns an error if any of the workflow's streams
+// that replicate one of the tables being repaired is still running.
+func (s *Server) checkVDiffRepairStreams(ctx context.Context, ts *trafficSwitcher, repairTables []*vdiffRepairTable) error {
+	tablesByShard := make(map[string][]string)
+	for _, rt := range repairTables {
+		tablesByShard[rt.shard] = append(tablesByShard[rt.shard], rt.table)
+	}
+	return ts.ForAllTargets(func(target *MigrationTarget) error {
+		tables := tablesByShard[target.GetShard().ShardName()]
+		if len(tables) == 0 {
+			return nil
+		}
+		res, err := s.tmc.ReadVReplicationWorkflow(ctx, target.GetPrimary().Tablet, &tabletmanagerdatapb.ReadVReplicationWorkflowRequest{
+			Workflow: ts.workflow,
+		})
+		if err != nil {
+			return err
+		}
+		return checkVDiffRepairStreamStates(target.GetShard().ShardName(), tables, res.GetStreams())
+	})
+}
+
+func checkVDiffRepairStreamStates(shard string, tables []string, streams []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream) error {
+	for _, stream := range streams {
+		if stream.State == binlogdatapb.VReplicationWorkflowState_Stopped {
+			continue
+		}
+		for _, table := range tables {
+			rule, err := vreplication.MatchTable(table, stream.GetBls().GetFilter())
+			if err != nil {
+				return err
+			}
+			if rule != nil {
+				return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "stream %d on shard %s is still replicating writes for table %s; stop the workflow before repairing it",
+					stream.Id, shard, table)
+			}
+		}
+	}
+	return nil
+}
+
+// buildVDiffRepairQueries returns the queries used to fetch the table's rows
+// from each of the target shard's source primaries and from the target
+// primary. Each source query is derived from the filter of the stream that
+// replicates the table from that source shard, so that we only consider the
+// rows, and the columns, that the workflow copies to this target shard.
+func (s *Server) buildVDiffRepairQueries(ctx context.Context, ts *trafficSwitcher, target *MigrationTarget, rt *vdiffRepairTable,
+	vschemas func(keyspace string) (*vindexes.KeyspaceSchema, error)) ([]*vdiffRepairQuery, *vdiffRepairQuery, error) {
+	ids := make([]int32, 0, len(target.Sources))
+	for id := range target.Sources {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	var (
+		sourceQueries []*vdiffRepairQuery
+		targetQuery   *vdiffRepairQuery
+	)
+	for _, id := range ids {
+		bls := target.Sources[id]
+		rule, err := vreplication.MatchTable(rt.table, bls.Filter)
+		if err != nil {
+			return nil, nil, err
+		}
+		if rule == nil {
+			continue
+		}
+		source, ok := ts.sources[bls.Shard]
+		if !ok {
+			return nil, nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "source shard %s not found for target shard %s", bls.Shard, rt.shard)
+		}
+		sq, err := buildVDiffRepairSourceQuery(s.env.Parser(), vschemas, ts.sourceKeyspace, rt.table, rt.pkCols, rule.Filter)
+		if err != nil {
+			return nil, nil, vterrors.Wrapf(err, "failed to build the query for table %s from the filter of stream %d on shard %s", rt.table, id, rt.shard)
+		}
+		sq.tablet = source.GetPrimary()
+		sourceQueries = append(sourceQueries, sq)
+		if targetQuery == nil {
+			targetQuery = buildVDiffRepairTargetQuery(rt.table, rt.pkCols, sq)
+			targetQuery.tablet = target.GetPrimary()
+		}
+	}
+	if len(sourceQueries) == 0 {
+		return nil, nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no stream on shard %s replicates table %s", rt.shard, rt.table)
+	}
+	return sourceQueries, targetQuery, nil
+}
+
+// vdiffRepairVSchemas returns a function that loads, and caches, the
+// keyspace schemas used to evaluate the in_keyrange() conditions of the
+// workflow's filters.
+func (s *Server) vdiffRepairVSchemas(ctx context.Context, ts *trafficSwitcher) func(keyspace string) (*vindexes.KeyspaceSchema, error) {
+	schemas := map[string]*vindexes.KeyspaceSchema{
+		ts.sourceKeyspace: ts.sourceKSSchema,
+	}
+	return func(keyspace string) (*vindexes.KeyspaceSchema, error) {
+		if ksSchema, ok := schemas[keyspace]; ok && ksSchema != nil {
+			return ksSchema, nil
+		}
+		vschema, err := s.ts.GetVSchema(ctx, keyspace)
+		if err != nil {
+			return nil, err
+		}
+		ksSchema, err := vindexes.BuildKeyspaceSchema(vschema.Keyspace, keyspace, s.env.Parser())
+		if err != nil {
+			return nil, err
+		}
+		schemas[keyspace] = ksSchema
+		return ksSchema, nil
+	}
+}
+
+// buildVDiffRepairStatements fetches the current version of the given rows
+// from the source and target primaries and returns the statements needed to
+// make the target rows match the source ones.
+func (s *Server) buildVDiffRepairStatements(ctx context.Context, rt *vdiffRepairTable, sourceQueries []*vdiffRepairQuery, targetQuery *vdiffRepairQuery,
+	pks [][]string) ([]string, error) {
+	var (
+		sourceRows = make(map[string][]sqltypes.Value)
+		fields     []*querypb.Field
+	)
+	for _, sq := range sourceQueries {
+		qr, err := s.fetchVDiffRepairRows(ctx, sq, pks)
+		if err != nil {
+			return nil, err
+		}
+		if fields == nil {
+			fields = qr.Fields
+		}
+		if err := indexVDiffRepairRows(sourceRows, qr, rt.pkCols); err != nil {
+			return nil, err
+		}
+	}
+	qr, err := s.fetchVDiffRepairRows(ctx, targetQuery, pks)
+	if err != nil {
+		return nil, err
+	}
+	targetRows := make(map[string][]sqltypes.Value)
+	if err := indexVDiffRepairRows(targetRows, qr, rt.pkCols); err != nil {
+		return nil, err
+	}
+	if fields == nil {
+		fields = qr.Fields
+	}
+	return generateVDiffRepairStatements(rt.table, rt.pkCols, fields, pks, sourceRows, targetRows)
+}
+
+// fetchVDiffRepairRows runs the query for the given primary key values and
+// returns the rows that match it.
+func (s *Server) fetchVDiffRepairRows(ctx context.Context, q *vdiffRepairQuery, pks [][]string) (*sqltypes.Result, error) {
+	res, err := s.tmc.ExecuteFetchAsDba(ctx, q.tablet.Tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+		Query:  []byte(q.query(pks)),
+		DbName: q.tablet.DbName(),
+		// The primary key values are unique so there can be at most one
+		// row for each of them.
+		MaxRows: uint64(len(pks)),
+	})
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to fetch rows from tablet %s", q.tablet.AliasString())
+	}
+	qr, err := q.filterRows(ctx, sqltypes.Proto3ToResult(res))
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to filter the rows fetched from tablet %s", q.tablet.AliasString())
+	}
+	return qr, nil
+}
+
+// applyVDiffRepairStatements executes the statements in a transaction on the
+// target primary once its throttler allows it.
+func (s *Server) applyVDiffRepairStatements(ctx context.Context, target *MigrationTarget, stmts []string) error {
+	primary := target.GetPrimary()
+	for {
+		res, err := s.tmc.CheckThrottler(ctx, primary.Tablet, &tabletmanagerdatapb.CheckThrottlerRequest{
+			AppName: throttlerapp.VDiffRepairName.String(),
+		})
+		if err != nil {
+			return vterrors.Wrapf(err, "failed to check the throttler on tablet %s", primary.AliasString())
+		}
+		if res.ResponseCode == tabletmanagerdatapb.CheckThrottlerResponseCode_OK {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(vdiffRepairThrottleInterval):
+		}
+	}
+	sql := "begin;" + strings.Join(stmts, ";") + ";commit"
+	_, err := s.tmc.ExecuteMultiFetchAsDba(ctx, primary.Tablet, false, &tabletmanagerdatapb.ExecuteMultiFetchAsDbaRequest{
+		Sql:    []byte(sql),
+		DbName: primary.DbName(),
+	})
+	return err
+}
+
+// vdiffRepairQuery is the query used to fetch the current version of a
+// table's rows from a tablet.
+type vdiffRepairQuery struct {
+	tablet *topo.TabletInfo
+	// sel is the query without the primary key predicate.
+	sel *sqlparser.Select
+	// pkExprs are the expressions that produce the table's primary key
+	// columns.
+	pkExprs []sqlparser.Expr
+	// keyRanges are the in_keyrange() conditions of the stream's filter.
+	// MySQL cannot evaluate them so they are applied to the fetched rows,
+	// using the vindex columns appended to the select list.
+	keyRanges []*vdiffRepairKeyRange
+	// vindexCols is the number of columns appended to the select list.
+	vindexCols int
+}
+
+// vdiffRepairKeyRange is an in_keyrange() condition of a stream's filter.
+type vdiffRepairKeyRange struct {
+	vindex vindexes.Vindex
+	// cols are the indexes, within the appended vindex columns, of the
+	// vindex's input columns.
+	cols     []int
+	keyRange *topodatapb.KeyRange
+}
+
+// query returns the query that fetches the rows with the given primary key
+// values.
+func (q *vdiffRepairQuery) query(pks [][]string) string {
+	sel := sqlparser.CloneRefOfSelect(q.sel)
+	tuples := make(sqlparser.ValTuple, 0, len(pks))
+	for _, pk := range pks {
+		tuple := make(sqlparser.ValTuple, 0, len(pk))
+		for _, val := range pk {
+			tuple = append(tuple, sqlparser.NewStrLiteral(val))
+		}
+		tuples = append(tuples, tuple)
+	}
+	sel.AddWhere(&sqlparser.ComparisonExpr{
+		Operator: sqlparser.InOp,
+		Left:     sqlparser.ValTuple(slices.Clone(q.pkExprs)),
+		Right:    tuples,
+	})
+	return sqlparser.String(sel)
+}
+
+// filterRows removes the rows that are outside of the query's key ranges,
+// along with the appended vindex columns, from the result.
+func (q *vdiffRepairQuery) filterRows(ctx context.Context, qr *sqltypes.Result) (*sqltypes.Result, error) {
+	if q.vindexCols == 0 {
+		return qr, nil
+	}
+	numCols := len(qr.Fields) - q.vindexCols
+	if numCols < 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "expected at least %d columns in the result, got %d", q.vindexCols, len(qr.Fields))
+	}
+	filtered := &sqltypes.Result{Fields: qr.Fields[:numCols]}
+	for _, row := range qr.Rows {
+		inRange := true
+		for _, kr := range q.keyRanges {
+			vals := make([]sqltypes.Value, 0, len(kr.cols))
+			for _, col := range kr.cols {
+				vals = append(vals, row[numCols+col])
+			}
+			destinations, err := vindexes.Map(ctx, kr.vindex, nil, [][]sqltypes.Value{vals})
+			if err != nil {
+				return nil, err
+			}
+			if len(destinations) != 1 {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "mapping row to keyspace id returned an invalid array of destinations: %v", key.DestinationsString(destinations))
+			}
+			ksid, ok := destinations[0].(key.DestinationKeyspaceID)
+			if !ok || len(ksid) == 0 {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "could not map %v to a keyspace id, got destination %v", vals, destinations[0])
+			}
+			if !key.KeyRangeContains(kr.keyRange, ksid) {
+				inRange = false
+				break
+			}
+		}
+		if inRange {
+			filtered.Rows = append(filtered.Rows, row[:numCols])
+		}
+	}
+	return filtered, nil
+}
+
+// buildVDiffRepairSourceQuery returns the query used to fetch the table's
+// rows from a source shard, based on the filter of the stream that
+// replicates the table from that shard. The filter is either empty, a key
+// range, or a select statement as supported by the vstreamer.
+func buildVDiffRepairSourceQuery(parser *sqlparser.Parser, vschemas func(keyspace string) (*vindexes.KeyspaceSchema, error),
+	sourceKeyspace, table string, pkCols []string, filter string) (*vdiffRepairQuery, error) {
+	q := &vdiffRepairQuery{}
+	switch {
+	case filter == "":
+		q.sel = newVDiffRepairSelectStar(table)
+	case !strings.HasPrefix(strings.ToLower(strings.TrimSpace(filter)), "select"):
+		// The filter is a key range on the table's primary vindex.
+		q.sel = newVDiffRepairSelectStar(table)
+		cv, err := findVDiffRepairColVindex(vschemas, sourceKeyspace, table)
+		if err != nil {
+			return nil, err
+		}
+		if err := q.addKeyRange(table, cv.Vindex, cv.Columns, filter); err != nil {
+			return nil, err
+		}
+	default:
+		stmt, err := parser.Parse(filter)
+		if err != nil {
+			return nil, err
+		}
+		sel, ok := stmt.(*sqlparser.Select)
+		if !ok {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unexpected filter: %s", filter)
+		}
+		q.sel = sel
+		if sel.Where != nil {
+			var conds []sqlparser.Expr
+			for _, cond := range sqlparser.SplitAndExpression(nil, sel.Where.Expr) {
+				fn, ok := cond.(*sqlparser.FuncExpr)
+				if !ok || !fn.Name.EqualString("in_keyrange") {
+					conds = append(conds, cond)
+					continue
+				}
+				if err := q.addInKeyRange(vschemas, sourceKeyspace, table, fn.Exprs); err != nil {
+					return nil, err
+				}
+			}
+			sel.Where = nil
+			if len(conds) > 0 {
+				sel.AddWhere(sqlparser.AndExpressions(conds...))
+			}
+		}
+	}
+	for _, col := range pkCols {
+		expr, err := findVDiffRepairColumnExpr(q.sel, col)
+		if err != nil {
+			return nil, err
+		}
+		q.pkExprs = append(q.pkExprs, expr)
+	}
+	return q, nil
+}
+
+// buildVDiffRepairTargetQuery returns the query used to fetch the table's
+// rows from the target shard, selecting the same columns as the given source
+// query.
+func buildVDiffRepairTargetQuery(table string, pkCols []string, source *vdiffRepairQuery) *vdiffRepairQuery {
+	q := &vdiffRepairQuery{sel: newVDiffRepairSelectStar(table)}
+	exprs := source.sel.GetColumns()
+	exprs = exprs[:len(exprs)-source.vindexCols]
+	if len(exprs) != 1 || !isVDiffRepairStar(exprs[0]) {
+		cols := make([]sqlparser.SelectExpr, 0, len(exprs))
+		for _, expr := range exprs {
+			switch expr := expr.(type) {
+			case *sqlparser.AliasedExpr:
+				cols = append(cols, &sqlparser.AliasedExpr{Expr: sqlparser.NewColName(expr.ColumnName())})
+			default:
+				cols = append(cols, &sqlparser.StarExpr{TableName: sqlparser.NewTableName(table)})
+			}
+		}
+		q.sel.SetSelectExprs(cols...)
+	}
+	for _, col := range pkCols {
+		q.pkExprs = append(q.pkExprs, sqlparser.NewColName(col))
+	}
+	return q
+}
+
+// addInKeyRange adds the condition for one of the filter's in_keyrange()
+// calls. These are "in_keyrange('-80')", which uses the table's primary
+// vindex, or "in_keyrange(col, 'vindex', '-80')", where the vindex can be
+// qualified by its keyspace.
+func (q *vdiffRepairQuery) addInKeyRange(vschemas func(keyspace string) (*vindexes.KeyspaceSchema, error), sourceKeyspace, table string,
+	exprs []sqlparser.Expr) error {
+	switch {
+	case len(exprs) == 1:
+		cv, err := findVDiffRepairColVindex(vschemas, sourceKeyspace, table)
+		if err != nil {
+			return err
+		}
+		kr, err := vdiffRepairLiteral(exprs[0])
+		if err != nil {
+			return err
+		}
+		return q.addKeyRange(table, cv.Vindex, cv.Columns, kr)
+	case len(exprs) >= 3:
+		cols := make([]sqlparser.IdentifierCI, 0, len(exprs)-2)
+		for _, expr := range exprs[:len(exprs)-2] {
+			col, ok := expr.(*sqlparser.ColName)
+			if !ok {
+				return vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "unsupported in_keyrange column: %s", sqlparser.String(expr))
+			}
+			cols = append(cols, col.Name)
+		}
+		name, err := vdiffRepairLiteral(exprs[len(exprs)-2])
+		if err != nil {
+			return err
+		}
+		vindex, err := findVDiffRepairVindex(vschemas, sourceKeyspace, name)
+		if err != nil {
+			return err
+		}
+		if !vindex.IsUnique() {
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "vindex %s is not unique", name)
+		}
+		kr, err := vdiffRepairLiteral(exprs[len(exprs)-1])
+		if err != nil {
+			return err
+		}
+		return q.addKeyRange(table, vindex, cols, kr)
+	default:
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unexpected in_keyrange parameters: %s", sqlparser.SliceString(exprs))
+	}
+}
+
+// addKeyRange appends the vindex's input columns to the select list and
+// records the key range that the rows must be in.
+func (q *vdiffRepairQuery) addKeyRange(table string, vindex vindexes.Vindex, cols []sqlparser.IdentifierCI, spec string) error {
+	keyRanges, err := key.ParseShardingSpec(spec)
+	if err != nil {
+		return err
+	}
+	if len(keyRanges) != 1 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unexpected key range: %s", spec)
+	}
+	// MySQL does not allow an unqualified * to be followed by other
+	// columns.
+	for _, expr := range q.sel.GetColumns() {
+		if star, ok := expr.(*sqlparser.StarExpr); ok && star.TableName.IsEmpty() {
+			star.TableName = sqlparser.NewTableName(table)
+		}
+	}
+	kr := &vdiffRepairKeyRange{
+		vindex:   vindex,
+		keyRange: keyRanges[0],
+	}
+	for _, col := range cols {
+		q.sel.AddSelectExpr(&sqlparser.AliasedExpr{Expr: &sqlparser.ColName{Name: col}})
+		kr.cols = append(kr.cols, q.vindexCols)
+		q.vindexCols++
+	}
+	q.keyRanges = append(q.keyRanges, kr)
+	return nil
+}
+
+// findVDiffRepairColumnExpr returns the expression in the select list that
+// produces the given column.
+func findVDiffRepairColumnExpr(sel *sqlparser.Select, col string) (sqlparser.Expr, error) {
+	hasStar := false
+	for _, expr := range sel.GetColumns() {
+		switch expr := expr.(type) {
+		case *sqlparser.AliasedExpr:
+			if strings.EqualFold(expr.ColumnName(), col) {
+				return expr.Expr, nil
+			}
+		case *sqlparser.StarExpr:
+			hasStar = true
+		}
+	}
+	if !hasStar {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "primary key column %s is not in the filter", col)
+	}
+	return sqlparser.NewColName(col), nil
+}
+
+func findVDiffRepairColVindex(vschemas func(keyspace string) (*vindexes.KeyspaceSchema, error), keyspace, table string) (*vindexes.ColumnVindex, error) {
+	ksSchema, err := vschemas(keyspace)
+	if err != nil {
+		return nil, err
+	}
+	vtable := ksSchema.Tables[table]
+	if vtable == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "table %s not found in the vschema for keyspace %s", table, keyspace)
+	}
+	return vindexes.FindBestColVindex(vtable)
+}
+
+// findVDiffRepairVindex returns the vindex with the given name, which is
+// looked up the way the vstreamer does.
+func findVDiffRepairVindex(vschemas func(keyspace string) (*vindexes.KeyspaceSchema, error), sourceKeyspace, name string) (vindexes.Vindex, error) {
+	keyspace, vindexName, qualified := strings.Cut(name, ".")
+	if !qualified {
+		keyspace, vindexName = sourceKeyspace, name
+	}
+	ksSchema, err := vschemas(keyspace)
+	if err != nil {
+		return nil, err
+	}
+	if vindex := ksSchema.Vindexes[vindexName]; vindex != nil {
+		return vindex, nil
+	}
+	if qualified {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "vindex %s not found", name)
+	}
+	return vindexes.CreateVindex(vindexName, vindexName, map[string]string{})
+}
+
+func newVDiffRepairSelectStar(table string) *sqlparser.Select {
+	return &sqlparser.Select{
+		SelectExprs: &sqlparser.SelectExprs{Exprs: []sqlparser.SelectExpr{&sqlparser.StarExpr{}}},
+		From:        []sqlparser.TableExpr{sqlparser.NewAliasedTableExpr(sqlparser.NewTableName(table), "")},
+	}
+}
+
+func isVDiffRepairStar(expr sqlparser.SelectExpr) bool {
+	_, ok := expr.(*sqlparser.StarExpr)
+	return ok
+}
+
+func vdiffRepairLiteral(expr sqlparser.Expr) (string, error) {
+	lit, ok := expr.(*sqlparser.Literal)
+	if !ok {
+		return "", vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "unsupported in_keyrange parameter: %s", sqlparser.String(expr))
+	}
+	return lit.Val, nil
+}
+
+// indexVDiffRepairRows adds the rows in the result to the map, keyed by
+// their primary key values.
+func indexVDiffRepairRows(rows map[string][]sqltypes.Value, qr *sqltypes.Result, pkCols []string) error {
+	pkIdx, err := vdiffRepairPKIndexes(qr.Fields, pkCols)
+	if err != nil {
+		return err
+	}
+	for _, row := range qr.Rows {
+		pk := make([]string, len(pkIdx))
+		for i, idx := range pkIdx {
+			pk[i] = row[idx].ToString()
+		}
+		rows[vdiffRepairKey(pk)] = row
+	}
+	return nil
+}
+
+// generateVDiffRepairStatements returns, for each of the given primary key
+// values, the statement that makes the target row match the source row:
+// an INSERT if the row only exists on the source, a DELETE if it only exists
+// on the target, and an UPDATE if the two versions differ.
+func generateVDiffRepairStatements(table string, pkCols []string, fields []*querypb.Field, pks [][]string,
+	sourceRows, targetRows map[string][]sqltypes.Value) ([]string, error) {
+	pkIdx, err := vdiffRepairPKIndexes(fields, pkCols)
+	if err != nil {
+		return nil, err
+	}
+	isPK := make(map[int]bool, len(pkIdx))
+	for _, idx := range pkIdx {
+		isPK[idx] = true
+	}
+	tableName := sqlescape.EscapeID(table)
+	var stmts []string
+	for _, pk := range pks {
+		key := vdiffRepairKey(pk)
+		sourceRow, onSource := sourceRows[key]
+		targetRow, onTarget := targetRows[key]
+		buf := sqlparser.NewTrackedBuffer(nil)
+		switch {
+		case onSource && !onTarget:
+			buf.Myprintf("insert into %s (", tableName)
+			for i, field := range fields {
+				if i > 0 {
+					buf.WriteString(", ")
+				}
+				buf.WriteString(sqlescape.EscapeID(field.Name))
+			}
+			buf.WriteString(") values (")
+			for i, val := range sourceRow {
+				if i > 0 {
+					buf.WriteString(", ")
+				}
+				val.EncodeSQL(buf)
+			}
+			buf.WriteByte(')')
+		case !onSource && onTarget:
+			buf.Myprintf("delete from %s where ", tableName)
+			writeVDiffRepairWhere(buf, fields, pkIdx, targetRow)
+		case onSource && onTarget:
+			if len(sourceRow) != len(targetRow) {
+				return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s has %d columns on the source and %d on the target",
+					table, len(sourceRow), len(targetRow))
+			}
+			first := true
+			for i, field := range fields {
+				if isPK[i] || sourceRow[i].Equal(targetRow[i]) {
+					continue
+				}
+				if first {
+					buf.Myprintf("update %s set ", tableName)
+					first = false
+				} else {
+					buf.WriteString(", ")
+				}
+				buf.WriteString(sqlescape.EscapeID(field.Name))
+				buf.WriteString(" = ")
+				sourceRow[i].EncodeSQL(buf)
+			}
+			if first {
+				// The rows match now.
+				continue
+			}
+			buf.WriteString(" where ")
+			writeVDiffRepairWhere(buf, fields, pkIdx, targetRow)
+		default:
+			// The row no longer exists on either side.
+			continue
+		}
+		stmts = append(stmts, buf.String())
+	}
+	return stmts, nil
+}
+
+func writeVDiffRepairWhere(buf *sqlparser.TrackedBuffer, fields []*querypb.Field, pkIdx []int, row []sqltypes.Value) {
+	for i, idx := range pkIdx {
+		if i > 0 {
+			buf.WriteString(" and ")
+		}
+		buf.WriteString(sqlescape.EscapeID(fields[idx].Name))
+		buf.WriteString(" = ")
+		row[idx].EncodeSQL(buf)
+	}
+}
+
+// vdiffRepairPKIndexes returns the index of each primary key column in the
+// fields.
+func vdiffRepairPKIndexes(fields []*querypb.Field, pkCols []string) ([]int, error) {
+	pkIdx := make([]int, len(pkCols))
+	for i, col := range pkCols {
+		pkIdx[i] = slices.IndexFunc(fields, func(field *querypb.Field) bool {
+			return strings.EqualFold(field.Name, col)
+		})
+		if pkIdx[i] < 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "primary key column %s not found in the result", col)
+		}
+	}
+	return pkIdx, nil
+}
+
+func vdiffRepairKey(pk []string) string {
+	return fmt.Sprintf("%q", pk)
+}
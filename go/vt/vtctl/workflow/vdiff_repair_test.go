@@ -0,0 +1,324 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vdiff"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+func TestNewVDiffRepairTable(t *testing.T) {
+	pkCols := []string{"id", "order"}
+	row := func(id, order, val string) *vdiff.RowDiff {
+		return &vdiff.RowDiff{Row: map[string]string{"id": id, "`order`": order, "val": val}}
+	}
+	testCases := []struct {
+		name    string
+		dr      *vdiff.DiffReport
+		wantPKs [][]string
+		wantErr string
+	}{
+		{
+			name: "all differences",
+			dr: &vdiff.DiffReport{
+				TableName:      "t1",
+				MismatchedRows: 1,
+				MismatchedRowsDiffs: []*vdiff.DiffMismatch{
+					{Source: row("1", "a", "x"), Target: row("1", "a", "y")},
+				},
+				ExtraRowsSource:      1,
+				ExtraRowsSourceDiffs: []*vdiff.RowDiff{row("2", "b", "x")},
+				ExtraRowsTarget:      2,
+				ExtraRowsTargetDiffs: []*vdiff.RowDiff{row("3", "c", "x"), row("1", "a", "y")},
+			},
+			wantPKs: [][]string{{"1", "a"}, {"2", "b"}, {"3", "c"}},
+		},
+		{
+			name: "truncated samples",
+			dr: &vdiff.DiffReport{
+				TableName:      "t1",
+				MismatchedRows: 11,
+				MismatchedRowsDiffs: []*vdiff.DiffMismatch{
+					{Source: row("1", "a", "x"), Target: row("1", "a", "y")},
+				},
+			},
+			wantErr: "does not include every row that differs",
+		},
+		{
+			name: "missing pk column",
+			dr: &vdiff.DiffReport{
+				TableName:            "t1",
+				ExtraRowsTarget:      1,
+				ExtraRowsTargetDiffs: []*vdiff.RowDiff{{Row: map[string]string{"id": "1"}}},
+			},
+			wantErr: "does not include the value of primary key column order",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rt, err := newVDiffRepairTable("-80", pkCols, tc.dr)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "-80", rt.shard)
+			require.Equal(t, "t1", rt.table)
+			require.Equal(t, tc.wantPKs, rt.pks)
+		})
+	}
+}
+
+func TestParseVDiffRepairReports(t *testing.T) {
+	fields := sqltypes.MakeTestFields("vdiff_state|table_name|report", "varchar|varchar|json")
+	resp := func(rows ...string) *tabletmanagerdatapb.VDiffResponse {
+		return &tabletmanagerdatapb.VDiffResponse{Output: sqltypes.ResultToProto3(sqltypes.MakeTestResult(fields, rows...))}
+	}
+
+	reports, err := parseVDiffRepairReports("-80", resp(
+		`completed|t1|{"TableName": "t1", "ProcessedRows": 10, "MismatchedRows": 1}`,
+		`completed|t2|{"TableName": "t2", "ProcessedRows": 10}`,
+	))
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	require.Equal(t, "t1", reports[0].TableName)
+	require.EqualValues(t, 1, reports[0].MismatchedRows)
+
+	_, err = parseVDiffRepairReports("-80", resp(`started|t1|{"TableName": "t1"}`))
+	require.ErrorContains(t, err, "vdiff is in the started state on shard -80")
+
+	_, err = parseVDiffRepairReports("-80", &tabletmanagerdatapb.VDiffResponse{})
+	require.ErrorContains(t, err, "vdiff not found on shard -80")
+}
+
+func TestCheckVDiffRepairStreamStates(t *testing.T) {
+	stream := func(id int32, state binlogdatapb.VReplicationWorkflowState, tables ...string) *tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream {
+		filter := &binlogdatapb.Filter{}
+		for _, table := range tables {
+			filter.Rules = append(filter.Rules, &binlogdatapb.Rule{Match: table, Filter: "select * from " + table})
+		}
+		return &tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream{Id: id, State: state, Bls: &binlogdatapb.BinlogSource{Filter: filter}}
+	}
+
+	streams := []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream{
+		stream(1, binlogdatapb.VReplicationWorkflowState_Stopped, "t1", "t2"),
+		stream(2, binlogdatapb.VReplicationWorkflowState_Running, "t3"),
+	}
+	require.NoError(t, checkVDiffRepairStreamStates("-80", []string{"t1", "t2"}, streams))
+	err := checkVDiffRepairStreamStates("-80", []string{"t1", "t3"}, streams)
+	require.ErrorContains(t, err, "stream 2 on shard -80 is still replicating writes for table t3")
+
+	// Reshard streams match all tables.
+	reshard := &tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream{
+		Id:    1,
+		State: binlogdatapb.VReplicationWorkflowState_Error,
+		Bls: &binlogdatapb.BinlogSource{Filter: &binlogdatapb.Filter{
+			Rules: []*binlogdatapb.Rule{{Match: "/.*", Filter: "-80"}},
+		}},
+	}
+	err = checkVDiffRepairStreamStates("-80", []string{"t1"}, []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream{reshard})
+	require.ErrorContains(t, err, "stream 1 on shard -80 is still replicating writes for table t1")
+}
+
+func TestBuildVDiffRepairSourceQuery(t *testing.T) {
+	ksSchema, err := vindexes.BuildKeyspaceSchema(&vschemapb.Keyspace{
+		Sharded: true,
+		Vindexes: map[string]*vschemapb.Vindex{
+			"hash": {Type: "hash"},
+		},
+		Tables: map[string]*vschemapb.Table{
+			"t1": {ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "id", Name: "hash"}}},
+		},
+	}, "ks", sqlparser.NewTestParser())
+	require.NoError(t, err)
+	vschemas := func(keyspace string) (*vindexes.KeyspaceSchema, error) {
+		if keyspace != "ks" {
+			return nil, fmt.Errorf("keyspace %s not found", keyspace)
+		}
+		return ksSchema, nil
+	}
+	pks := [][]string{{"1"}, {"2"}}
+	testCases := []struct {
+		name        string
+		table       string
+		pkCols      []string
+		filter      string
+		wantQuery   string
+		wantTarget  string
+		wantKRCount int
+		wantErr     string
+	}{
+		{
+			name:       "no filter",
+			table:      "order",
+			pkCols:     []string{"c1", "c2"},
+			wantQuery:  "select * from `order` where (c1, c2) in (('1'), ('2'))",
+			wantTarget: "select * from `order` where (c1, c2) in (('1'), ('2'))",
+		},
+		{
+			name:        "key range",
+			table:       "t1",
+			pkCols:      []string{"id"},
+			filter:      "-80",
+			wantQuery:   "select t1.*, id from t1 where (id) in (('1'), ('2'))",
+			wantTarget:  "select * from t1 where (id) in (('1'), ('2'))",
+			wantKRCount: 1,
+		},
+		{
+			name:        "in_keyrange on the primary vindex",
+			table:       "t1",
+			pkCols:      []string{"id"},
+			filter:      "select * from t1 where in_keyrange('-80')",
+			wantQuery:   "select t1.*, id from t1 where (id) in (('1'), ('2'))",
+			wantTarget:  "select * from t1 where (id) in (('1'), ('2'))",
+			wantKRCount: 1,
+		},
+		{
+			name:        "in_keyrange with other conditions and a projection",
+			table:       "t1",
+			pkCols:      []string{"id"},
+			filter:      "select c1 as id, val from t1 where tenant_id = 1 and in_keyrange(c1, 'ks.hash', '80-')",
+			wantQuery:   "select c1 as id, val, c1 from t1 where tenant_id = 1 and (c1) in (('1'), ('2'))",
+			wantTarget:  "select id, val from t1 where (id) in (('1'), ('2'))",
+			wantKRCount: 1,
+		},
+		{
+			name:    "primary key not in the projection",
+			table:   "t1",
+			pkCols:  []string{"id"},
+			filter:  "select val from t1",
+			wantErr: "primary key column id is not in the filter",
+		},
+		{
+			name:    "unknown vindex keyspace",
+			table:   "t1",
+			pkCols:  []string{"id"},
+			filter:  "select * from t1 where in_keyrange(id, 'other.hash', '-80')",
+			wantErr: "keyspace other not found",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := buildVDiffRepairSourceQuery(sqlparser.NewTestParser(), vschemas, "ks", tc.table, tc.pkCols, tc.filter)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantQuery, q.query(pks))
+			require.Len(t, q.keyRanges, tc.wantKRCount)
+			// Building the query for a batch must not change the query used
+			// for the next one.
+			require.Equal(t, tc.wantQuery, q.query(pks))
+			require.Equal(t, tc.wantTarget, buildVDiffRepairTargetQuery(tc.table, tc.pkCols, q).query(pks))
+		})
+	}
+}
+
+// vdiffRepairFetchTMClient records the requests sent to ExecuteFetchAsDba
+// and returns the given result.
+type vdiffRepairFetchTMClient struct {
+	tmclient.TabletManagerClient
+	reqs []*tabletmanagerdatapb.ExecuteFetchAsDbaRequest
+	qr   *sqltypes.Result
+}
+
+func (tmc *vdiffRepairFetchTMClient) ExecuteFetchAsDba(ctx context.Context, tablet *topodatapb.Tablet, usePool bool, req *tabletmanagerdatapb.ExecuteFetchAsDbaRequest) (*querypb.QueryResult, error) {
+	tmc.reqs = append(tmc.reqs, req)
+	return sqltypes.ResultToProto3(tmc.qr), nil
+}
+
+func TestFetchVDiffRepairRows(t *testing.T) {
+	ctx := context.Background()
+	hash, err := vindexes.CreateVindex("hash", "hash", nil)
+	require.NoError(t, err)
+	q := &vdiffRepairQuery{
+		tablet: &topo.TabletInfo{Tablet: &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+			Keyspace: "ks",
+		}},
+		sel:     newVDiffRepairSelectStar("t1"),
+		pkExprs: []sqlparser.Expr{sqlparser.NewColName("id")},
+	}
+	require.NoError(t, q.addKeyRange("t1", hash, []sqlparser.IdentifierCI{sqlparser.NewIdentifierCI("id")}, "-80"))
+
+	// Rows 1 and 2 are in -80 while row 4 is in 80-, so row 4 must not be
+	// used to repair a target shard that only has the rows in -80.
+	tmc := &vdiffRepairFetchTMClient{
+		qr: sqltypes.MakeTestResult(sqltypes.MakeTestFields("id|val|id", "int64|varchar|int64"),
+			"1|a|1",
+			"2|b|2",
+			"4|d|4",
+		),
+	}
+	s := &Server{tmc: tmc}
+	pks := [][]string{{"1"}, {"2"}, {"4"}}
+	qr, err := s.fetchVDiffRepairRows(ctx, q, pks)
+	require.NoError(t, err)
+	require.Len(t, tmc.reqs, 1)
+	require.Equal(t, "select t1.*, id from t1 where (id) in (('1'), ('2'), ('4'))", string(tmc.reqs[0].Query))
+	require.Equal(t, "vt_ks", tmc.reqs[0].DbName)
+	require.EqualValues(t, len(pks), tmc.reqs[0].MaxRows)
+	require.Equal(t, sqltypes.MakeTestResult(sqltypes.MakeTestFields("id|val", "int64|varchar"),
+		"1|a",
+		"2|b",
+	), qr)
+}
+
+func TestGenerateVDiffRepairStatements(t *testing.T) {
+	fields := sqltypes.MakeTestFields("id|name|val", "int64|varchar|varchar")
+	pkCols := []string{"id"}
+	sourceRows := make(map[string][]sqltypes.Value)
+	targetRows := make(map[string][]sqltypes.Value)
+	require.NoError(t, indexVDiffRepairRows(sourceRows, sqltypes.MakeTestResult(fields,
+		"1|a|x",
+		"2|b|x",
+		"4|d|x",
+	), pkCols))
+	require.NoError(t, indexVDiffRepairRows(targetRows, sqltypes.MakeTestResult(fields,
+		"1|a|y",
+		"3|c|x",
+		"4|d|x",
+	), pkCols))
+
+	// Row 5 no longer exists anywhere and row 4 now matches, so neither
+	// needs to be repaired.
+	pks := [][]string{{"1"}, {"2"}, {"3"}, {"4"}, {"5"}}
+	stmts, err := generateVDiffRepairStatements("t1", pkCols, fields, pks, sourceRows, targetRows)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"update `t1` set `val` = 'x' where `id` = 1",
+		"insert into `t1` (`id`, `name`, `val`) values (2, 'b', 'x')",
+		"delete from `t1` where `id` = 3",
+	}, stmts)
+}
@@ -387,12 +387,18 @@ func (cached *Generate) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(48)
+		size += int64(80)
 	}
 	// field Keyspace *vitess.io/vitess/go/vt/vtgate/vindexes.Keyspace
 	size += cached.Keyspace.CachedSize(true)
 	// field Query string
 	size += hack.RuntimeAllocSize(int64(len(cached.Query)))
+	// field Generator vitess.io/vitess/go/vt/vtgate/vindexes.SequenceGenerator
+	if cc, ok := cached.Generator.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field Block *vitess.io/vitess/go/vt/vtgate/vindexes.SequenceBlock
+	size += cached.Block.CachedSize(true)
 	// field Values vitess.io/vitess/go/vt/vtgate/evalengine.Expr
 	if cc, ok := cached.Values.(cachedObject); ok {
 		size += cc.CachedSize(true)
@@ -91,6 +91,14 @@ type (
 	Generate struct {
 		Keyspace *vindexes.Keyspace
 		Query    string
+		// Generator, if set, generates the values inside vtgate
+		// instead of the Query on the sequence table.
+		Generator vindexes.SequenceGenerator
+		// Batch, if set, is the number of values that the Query
+		// reserves from the sequence table at once. They are kept
+		// in the Block, which hands them out until it runs out.
+		Batch int64
+		Block *vindexes.SequenceBlock
 		// Values are the supplied values for the column, which
 		// will be stored as a list within the expression. New
 		// values will be generated based on how many were not
@@ -371,20 +379,20 @@ func (ic *InsertCommon) processGenerateFromSelect(
 		return 0, nil
 	}
 
-	insertID, err = ic.execGenerate(ctx, vcursor, loggingPrimitive, count)
+	insertID, generated, err := ic.generate(ctx, vcursor, loggingPrimitive, count)
 	if err != nil {
 		return 0, err
 	}
 
-	used := insertID
+	used := 0
 	for idx, val := range rows {
 		if genColPresent {
 			if shouldGenerate(val[offset], evalengine.ParseSQLMode(vcursor.SQLMode())) {
-				val[offset] = sqltypes.NewInt64(used)
+				val[offset] = generated(used)
 				used++
 			}
 		} else {
-			rows[idx] = append(val, sqltypes.NewInt64(used))
+			rows[idx] = append(val, generated(used))
 			used++
 		}
 	}
@@ -421,19 +429,20 @@ func (ic *InsertCommon) processGenerateFromValues(
 	}
 
 	// If generation is needed, generate the requested number of values (as one call).
+	var generated func(int) sqltypes.Value
 	if count != 0 {
-		insertID, err = ic.execGenerate(ctx, vcursor, loggingPrimitive, count)
+		insertID, generated, err = ic.generate(ctx, vcursor, loggingPrimitive, count)
 		if err != nil {
 			return 0, err
 		}
 	}
 
 	// Fill the holes where no value was supplied.
-	cur := insertID
+	used := 0
 	for i, v := range values {
 		if shouldGenerate(v, evalengine.ParseSQLMode(vcursor.SQLMode())) {
-			bindVars[SeqVarName+strconv.Itoa(i)] = sqltypes.Int64BindVariable(cur)
-			cur++
+			bindVars[SeqVarName+strconv.Itoa(i)] = sqltypes.ValueBindVariable(generated(used))
+			used++
 		} else {
 			bindVars[SeqVarName+strconv.Itoa(i)] = sqltypes.ValueBindVariable(v)
 		}
@@ -441,6 +450,44 @@ func (ic *InsertCommon) processGenerateFromValues(
 	return insertID, nil
 }
 
+// generate generates count new values, either with the generator or from the
+// sequence table. It returns the insert id, which is the first generated value
+// when the values are integers and 0 otherwise, and a function that returns
+// the i-th generated value.
+func (ic *InsertCommon) generate(ctx context.Context, vcursor VCursor, loggingPrimitive Primitive, count int64) (int64, func(int) sqltypes.Value, error) {
+	if ic.Generate.Generator == nil {
+		insertID, err := ic.reserveGenerate(ctx, vcursor, loggingPrimitive, count)
+		if err != nil {
+			return 0, nil, err
+		}
+		return insertID, func(i int) sqltypes.Value { return sqltypes.NewInt64(insertID + int64(i)) }, nil
+	}
+	values, err := ic.Generate.Generator.Generate(count)
+	if err != nil {
+		return 0, nil, err
+	}
+	var insertID int64
+	if values[0].IsIntegral() {
+		insertID, err = values[0].ToCastInt64()
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+	return insertID, func(i int) sqltypes.Value { return values[i] }, nil
+}
+
+// reserveGenerate returns the first of count consecutive values of the
+// sequence table. When the values are reserved in batches, they come from
+// the block, and the sequence table is only queried when the block runs out.
+func (ic *InsertCommon) reserveGenerate(ctx context.Context, vcursor VCursor, loggingPrimitive Primitive, count int64) (int64, error) {
+	if ic.Generate.Block == nil {
+		return ic.execGenerate(ctx, vcursor, loggingPrimitive, count)
+	}
+	return ic.Generate.Block.Reserve(count, ic.Generate.Batch, func(n int64) (int64, error) {
+		return ic.execGenerate(ctx, vcursor, loggingPrimitive, n)
+	})
+}
+
 func (ic *InsertCommon) execGenerate(ctx context.Context, vcursor VCursor, loggingPrimitive Primitive, count int64) (int64, error) {
 	// If generation is needed, generate the requested number of values (as one call).
 	rss, _, err := vcursor.ResolveDestinations(ctx, ic.Generate.Keyspace.Name, nil, []key.ShardDestination{key.DestinationAnyShard{}})
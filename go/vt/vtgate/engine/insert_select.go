@@ -346,10 +346,17 @@ func (ic *InsertCommon) commonDesc() map[string]any {
 	}
 
 	if ic.Generate != nil {
+		generator := ic.Generate.Query
+		if ic.Generate.Generator != nil {
+			generator = ic.Generate.Generator.Type()
+		}
 		if ic.Generate.Values == nil {
-			other["AutoIncrement"] = fmt.Sprintf("%s:Offset(%d)", ic.Generate.Query, ic.Generate.Offset)
+			other["AutoIncrement"] = fmt.Sprintf("%s:Offset(%d)", generator, ic.Generate.Offset)
 		} else {
-			other["AutoIncrement"] = fmt.Sprintf("%s:Values::%s", ic.Generate.Query, sqlparser.String(ic.Generate.Values))
+			other["AutoIncrement"] = fmt.Sprintf("%s:Values::%s", generator, sqlparser.String(ic.Generate.Values))
+		}
+		if ic.Generate.Batch > 0 {
+			other["AutoIncrementBatch"] = ic.Generate.Batch
 		}
 	}
 	return other
@@ -19,6 +19,7 @@ package engine
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -173,6 +174,157 @@ func TestInsertUnshardedGenerate_Zeros(t *testing.T) {
 	expectResult(t, result, &sqltypes.Result{InsertID: 4})
 }
 
+func TestInsertUnshardedGenerate_Batch(t *testing.T) {
+	ins := newQueryInsert(
+		InsertUnsharded,
+		&vindexes.Keyspace{
+			Name:    "ks",
+			Sharded: false,
+		},
+		"dummy_insert",
+	)
+	ins.Generate = &Generate{
+		Keyspace: &vindexes.Keyspace{
+			Name:    "ks2",
+			Sharded: false,
+		},
+		Query: "dummy_generate",
+		Batch: 3,
+		Block: &vindexes.SequenceBlock{},
+		Values: evalengine.NewTupleExpr(
+			evalengine.NullExpr,
+			evalengine.NullExpr,
+		),
+	}
+
+	vc := newTestVCursor("0")
+	vc.results = []*sqltypes.Result{
+		sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"nextval",
+				"int64",
+			),
+			"4",
+		),
+		{InsertID: 1},
+	}
+
+	result, err := ins.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		// Reserve a batch of three sequence values.
+		`ResolveDestinations ks2 [] Destinations:DestinationAnyShard()`,
+		`ExecuteStandalone dummy_generate n: type:INT64 value:"3" ks2 0`,
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.0: dummy_insert {__seq0: type:INT64 value:"4" __seq1: type:INT64 value:"5"} true true`,
+	})
+	expectResult(t, result, &sqltypes.Result{InsertID: 4})
+
+	// The next insert gets one value from the batch, but needs two, so the
+	// value left in the batch is skipped and a new batch is reserved.
+	vc = newTestVCursor("0")
+	vc.results = []*sqltypes.Result{
+		sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"nextval",
+				"int64",
+			),
+			"7",
+		),
+		{InsertID: 1},
+	}
+	result, err = ins.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks2 [] Destinations:DestinationAnyShard()`,
+		`ExecuteStandalone dummy_generate n: type:INT64 value:"3" ks2 0`,
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.0: dummy_insert {__seq0: type:INT64 value:"7" __seq1: type:INT64 value:"8"} true true`,
+	})
+	expectResult(t, result, &sqltypes.Result{InsertID: 7})
+
+	// The value left in the batch is used without a round trip to the
+	// sequence table.
+	ins.Generate.Values = evalengine.NewTupleExpr(evalengine.NullExpr)
+	vc = newTestVCursor("0")
+	vc.results = []*sqltypes.Result{{InsertID: 1}}
+	result, err = ins.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.0: dummy_insert {__seq0: type:INT64 value:"9"} true true`,
+	})
+	expectResult(t, result, &sqltypes.Result{InsertID: 9})
+}
+
+// testSequenceGenerator generates consecutive values from next,
+// or string values when str is set.
+type testSequenceGenerator struct {
+	next int64
+	str  bool
+}
+
+func (*testSequenceGenerator) Type() string {
+	return "test"
+}
+
+func (g *testSequenceGenerator) Generate(count int64) ([]sqltypes.Value, error) {
+	values := make([]sqltypes.Value, 0, count)
+	for range count {
+		if g.str {
+			values = append(values, sqltypes.NewVarChar(fmt.Sprintf("id-%d", g.next)))
+		} else {
+			values = append(values, sqltypes.NewInt64(g.next))
+		}
+		g.next++
+	}
+	return values, nil
+}
+
+func TestInsertUnshardedGenerator(t *testing.T) {
+	ins := newQueryInsert(
+		InsertUnsharded,
+		&vindexes.Keyspace{
+			Name:    "ks",
+			Sharded: false,
+		},
+		"dummy_insert",
+	)
+	ins.Generate = &Generate{
+		Generator: &testSequenceGenerator{next: 100},
+		Values: evalengine.NewTupleExpr(
+			evalengine.NewLiteralInt(1),
+			evalengine.NullExpr,
+			evalengine.NullExpr,
+		),
+	}
+
+	vc := newTestVCursor("0")
+	vc.results = []*sqltypes.Result{{InsertID: 1}}
+
+	result, err := ins.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	// The values are generated without a query.
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.0: dummy_insert {__seq0: type:INT64 value:"1" __seq1: type:INT64 value:"100" __seq2: type:INT64 value:"101"} true true`,
+	})
+	expectResult(t, result, &sqltypes.Result{InsertID: 100})
+
+	// Values that are not integers do not change the insert id.
+	ins.Generate.Generator = &testSequenceGenerator{str: true}
+	vc = newTestVCursor("0")
+	vc.results = []*sqltypes.Result{{InsertID: 1}}
+
+	result, err = ins.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.0: dummy_insert {__seq0: type:INT64 value:"1" __seq1: type:VARCHAR value:"id-0" __seq2: type:VARCHAR value:"id-1"} true true`,
+	})
+	expectResult(t, result, &sqltypes.Result{InsertID: 1})
+}
+
 func TestInsertShardedSimple(t *testing.T) {
 	invschema := &vschemapb.SrvVSchema{
 		Keyspaces: map[string]*vschemapb.Keyspace{
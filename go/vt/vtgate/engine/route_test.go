@@ -1740,6 +1740,55 @@ func TestBuildRowColValues(t *testing.T) {
 	require.EqualValues(t, "[INT64(2) INT64(20) INT64(4)]", fmt.Sprintf("%s", out[3]))
 }
 
+func TestBetweenMultiColumnVindex(t *testing.T) {
+	vindex, err := vindexes.CreateVindex("multicol", "", map[string]string{
+		"column_count":  "2",
+		"column_vindex": "hash,time_bucket",
+		"column_bytes":  "1,2",
+		"bucket":        "month",
+	})
+	require.NoError(t, err)
+	sel := NewRoute(
+		Between,
+		&vindexes.Keyspace{
+			Name:    "ks",
+			Sharded: true,
+		},
+		"dummy_select",
+		"dummy_select_field",
+	)
+	sel.Vindex = vindex
+	sel.Values = []evalengine.Expr{
+		evalengine.NewLiteralInt(1),
+		evalengine.TupleExpr{
+			evalengine.NewLiteralString([]byte("2024-01-15"), collations.SystemCollation),
+			evalengine.NullExpr,
+		},
+	}
+
+	vc := &loggingVCursor{
+		shards:       []string{"-20", "20-"},
+		shardForKsid: []string{"-20"},
+		results:      []*sqltypes.Result{defaultSelectResult},
+	}
+	result, err := sel.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationKeyRange(160288-17)`,
+		`ExecuteMultiShard ks.-20: dummy_select {} false false`,
+	})
+	expectResult(t, result, defaultSelectResult)
+
+	vc.Rewind()
+	result, err = wrapStreamExecute(sel, vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationKeyRange(160288-17)`,
+		`StreamExecuteMulti dummy_select ks.-20: {} `,
+	})
+	expectResult(t, result, defaultSelectResult)
+}
+
 func TestBuildMultiColumnVindexValues(t *testing.T) {
 	testcases := []struct {
 		input  [][][]sqltypes.Value
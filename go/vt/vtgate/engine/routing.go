@@ -19,6 +19,7 @@ package engine
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"strconv"
 
 	"vitess.io/vitess/go/sqltypes"
@@ -165,9 +166,10 @@ func (rp *RoutingParameters) findRoute(ctx context.Context, vcursor VCursor, bin
 		switch rp.Vindex.(type) {
 		case vindexes.SingleColumn:
 			return rp.between(ctx, vcursor, bindVars)
+		case vindexes.SequentialMultiColumn:
+			return rp.betweenMultiCol(ctx, vcursor, bindVars)
 		default:
-			// Only SingleColumn vindex supported.
-			return nil, nil, vterrors.VT13001("between supported on SingleColumn vindex only")
+			return nil, nil, vterrors.VT13001("between supported on SingleColumn and SequentialMultiColumn vindexes only")
 		}
 	case MultiEqual:
 		switch rp.Vindex.(type) {
@@ -421,6 +423,42 @@ func (rp *RoutingParameters) between(ctx context.Context, vcursor VCursor, bindV
 	return rss, shardVars(bindVars, values), nil
 }
 
+// betweenMultiCol routes using a range of values of one of the columns of a
+// multi-column vindex, which is the column with a tuple value. The values of
+// the columns before it are used as a prefix, and the ones after it are ignored.
+func (rp *RoutingParameters) betweenMultiCol(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) ([]*srvtopo.ResolvedShard, []map[string]*querypb.BindVariable, error) {
+	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
+	var prefix []sqltypes.Value
+	for _, rvalue := range rp.Values {
+		v, err := env.Evaluate(rvalue)
+		if err != nil {
+			return nil, nil, err
+		}
+		bounds := v.TupleValues()
+		if bounds == nil {
+			prefix = append(prefix, v.Value(vcursor.ConnCollation()))
+			continue
+		}
+		if len(bounds) != 2 {
+			return nil, nil, vterrors.VT13001(fmt.Sprintf("expected a range of 2 values, got %d", len(bounds)))
+		}
+		destinations, err := rp.Vindex.(vindexes.SequentialMultiColumn).RangeMap(ctx, vcursor, prefix, bounds[0], bounds[1])
+		if err != nil {
+			return nil, nil, err
+		}
+		rss, _, err := vcursor.ResolveDestinations(ctx, rp.Keyspace.Name, nil, destinations)
+		if err != nil {
+			return nil, nil, err
+		}
+		multiBindVars := make([]map[string]*querypb.BindVariable, len(rss))
+		for i := range multiBindVars {
+			multiBindVars[i] = bindVars
+		}
+		return rss, multiBindVars, nil
+	}
+	return nil, nil, vterrors.VT13001("no range found for the multi-column vindex")
+}
+
 func (rp *RoutingParameters) multiEqual(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) ([]*srvtopo.ResolvedShard, []map[string]*querypb.BindVariable, error) {
 	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
 	value, err := env.Evaluate(rp.Values[0])
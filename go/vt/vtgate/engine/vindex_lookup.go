@@ -156,12 +156,21 @@ func (vr *VindexLookup) lookup(ctx context.Context, vcursor VCursor, ids []sqlty
 		vcursor.Session().SetCommitOrder(co)
 		defer vcursor.Session().SetCommitOrder(vtgatepb.CommitOrder_NORMAL)
 	}
-	if ids[0].IsIntegral() || vr.Vindex.AllowBatch() {
-		return vr.executeBatch(ctx, vcursor, ids)
+	var cache *vindexes.LookupCache
+	if cached, ok := vr.Vindex.(vindexes.CachedLookup); ok && !vcursor.InTransactionAndIsDML() {
+		cache = cached.LookupCache()
 	}
-	return vr.executeNonBatch(ctx, vcursor, ids)
+	return cache.Lookup(ids, func(ids []sqltypes.Value) ([]*sqltypes.Result, error) {
+		if ids[0].IsIntegral() || vr.Vindex.AllowBatch() {
+			return vr.executeBatch(ctx, vcursor, ids)
+		}
+		return vr.executeNonBatch(ctx, vcursor, ids)
+	})
 }
 
+// executeNonBatch and executeBatch return the (from, to) rows of the lookup
+// table for each of the ids.
+
 func (vr *VindexLookup) executeNonBatch(ctx context.Context, vcursor VCursor, ids []sqltypes.Value) ([]*sqltypes.Result, error) {
 	results := make([]*sqltypes.Result, 0, len(ids))
 	// for non integral and binary type, fallback to send query per id
@@ -185,9 +194,7 @@ func (vr *VindexLookup) executeNonBatch(ctx context.Context, vcursor VCursor, id
 		}
 
 		rows := make([][]sqltypes.Value, 0, len(result.Rows))
-		for _, row := range result.Rows {
-			rows = append(rows, []sqltypes.Value{row[1]})
-		}
+		rows = append(rows, result.Rows...)
 		results = append(results, &sqltypes.Result{
 			Rows: rows,
 		})
@@ -217,7 +224,7 @@ func (vr *VindexLookup) executeBatch(ctx context.Context, vcursor VCursor, ids [
 	}
 	resultMap := make(map[string][][]sqltypes.Value)
 	for _, row := range result.Rows {
-		resultMap[row[0].ToString()] = append(resultMap[row[0].ToString()], []sqltypes.Value{row[1]})
+		resultMap[row[0].ToString()] = append(resultMap[row[0].ToString()], row)
 	}
 
 	for _, id := range ids {
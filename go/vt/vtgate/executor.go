@@ -130,6 +130,8 @@ type (
 		mu           sync.Mutex
 		vschema      *vindexes.VSchema
 		vschemaStats *VSchemaStats
+		// lookupCaches invalidates the caches of the lookup vindexes of the vschema.
+		lookupCaches *lookupCacheWatcher
 
 		plans *PlanCache
 		epoch atomic.Uint32
@@ -1082,6 +1084,7 @@ func (e *Executor) SaveVSchema(vschema *vindexes.VSchema, stats *VSchemaStats) {
 	}
 	e.vschemaStats = stats
 	e.ClearPlans()
+	e.lookupCaches.Update(e.vschema)
 
 	if vschemaCounters != nil {
 		vschemaCounters.Add("Reload", 1)
@@ -1096,6 +1099,15 @@ func (e *Executor) SaveVSchema(vschema *vindexes.VSchema, stats *VSchemaStats) {
 	}
 }
 
+// watchLookupCaches sets the watcher that keeps the caches of the lookup
+// vindexes of the vschema up to date.
+func (e *Executor) watchLookupCaches(watcher *lookupCacheWatcher) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.lookupCaches = watcher
+	watcher.Update(e.vschema)
+}
+
 // ParseDestinationTarget parses destination target string and sets default keyspace if possible.
 func (e *Executor) ParseDestinationTarget(targetString string) (string, topodatapb.TabletType, key.ShardDestination, error) {
 	return econtext.ParseDestinationTarget(targetString, defaultTabletType, e.VSchema())
@@ -1786,6 +1798,7 @@ func (e *Executor) Close() {
 	}
 	topo.Close()
 	e.plans.Close()
+	e.lookupCaches.Close()
 }
 
 func (e *Executor) Environment() *vtenv.Environment {
@@ -0,0 +1,214 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+// lookupCacheRetryDelay is the time to wait before restarting the VStream of
+// a lookup table after it failed.
+var lookupCacheRetryDelay = 5 * time.Second
+
+// lookupCacheStreamer is the signature of vstreamManager.VStream.
+type lookupCacheStreamer func(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid,
+	filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags, send func([]*binlogdatapb.VEvent) error) error
+
+// lookupCacheWatcher keeps the vtgate caches of the lookup vindexes up to date
+// by watching the VStreams of their lookup tables, and invalidating the values
+// of the rows that are changed by other vtgates, vreplication or by hand.
+type lookupCacheWatcher struct {
+	ctx    context.Context
+	stream lookupCacheStreamer
+
+	mu      sync.Mutex
+	watches map[lookupTableName]*lookupTableWatch
+}
+
+type lookupTableName struct {
+	keyspace string
+	table    string
+}
+
+// lookupTableWatch is the VStream of a lookup table, and the caches of the
+// vindexes that use it.
+type lookupTableWatch struct {
+	name   lookupTableName
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	caches []*vindexes.LookupCache
+}
+
+func newLookupCacheWatcher(ctx context.Context, stream lookupCacheStreamer) *lookupCacheWatcher {
+	return &lookupCacheWatcher{
+		ctx:     ctx,
+		stream:  stream,
+		watches: make(map[lookupTableName]*lookupTableWatch),
+	}
+}
+
+// Update watches the lookup tables of the cached lookup vindexes of the
+// vschema, and stops watching the tables that are no longer used.
+func (w *lookupCacheWatcher) Update(vschema *vindexes.VSchema) {
+	if w == nil || vschema == nil {
+		return
+	}
+	caches := make(map[lookupTableName][]*vindexes.LookupCache)
+	for ksName, ks := range vschema.Keyspaces {
+		for _, vindex := range ks.Vindexes {
+			cached, ok := vindex.(vindexes.CachedLookup)
+			if !ok || cached.LookupCache() == nil {
+				continue
+			}
+			cache := cached.LookupCache()
+			name := lookupTableName{keyspace: ksName, table: cache.Table()}
+			if qualifier, table, ok := strings.Cut(cache.Table(), "."); ok {
+				name = lookupTableName{keyspace: qualifier, table: table}
+			}
+			caches[name] = append(caches[name], cache)
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for name, watch := range w.watches {
+		if _, ok := caches[name]; !ok {
+			watch.cancel()
+			delete(w.watches, name)
+		}
+	}
+	for name, tableCaches := range caches {
+		watch, ok := w.watches[name]
+		if !ok {
+			ctx, cancel := context.WithCancel(w.ctx)
+			watch = &lookupTableWatch{name: name, cancel: cancel}
+			w.watches[name] = watch
+			go w.run(ctx, watch)
+		}
+		watch.setCaches(tableCaches)
+	}
+}
+
+// Close stops all the VStreams.
+func (w *lookupCacheWatcher) Close() {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for name, watch := range w.watches {
+		watch.cancel()
+		delete(w.watches, name)
+	}
+}
+
+func (w *lookupCacheWatcher) run(ctx context.Context, watch *lookupTableWatch) {
+	vgtid := &binlogdatapb.VGtid{
+		ShardGtids: []*binlogdatapb.ShardGtid{{
+			Keyspace: watch.name.keyspace,
+			Gtid:     "current",
+		}},
+	}
+	filter := &binlogdatapb.Filter{
+		Rules: []*binlogdatapb.Rule{{
+			Match: watch.name.table,
+		}},
+	}
+	flags := &vtgatepb.VStreamFlags{ExcludeKeyspaceFromTableName: true}
+	for {
+		// The changes that were made while the table was not watched are
+		// lost, so the caches must be cleared every time it starts.
+		watch.clear()
+		var fields []*querypb.Field
+		err := w.stream(ctx, topodatapb.TabletType_PRIMARY, vgtid, filter, flags, func(events []*binlogdatapb.VEvent) error {
+			for _, event := range events {
+				switch event.Type {
+				case binlogdatapb.VEventType_FIELD:
+					if event.FieldEvent.TableName == watch.name.table {
+						fields = event.FieldEvent.Fields
+					}
+				case binlogdatapb.VEventType_ROW:
+					if event.RowEvent.TableName != watch.name.table {
+						continue
+					}
+					for _, change := range event.RowEvent.RowChanges {
+						for _, row := range []*querypb.Row{change.Before, change.After} {
+							if row != nil {
+								watch.invalidate(fields, sqltypes.MakeRowTrusted(fields, row))
+							}
+						}
+					}
+				}
+			}
+			return nil
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		log.Warningf("VStream of lookup table %s.%s failed, retrying in %v: %v", watch.name.keyspace, watch.name.table, lookupCacheRetryDelay, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(lookupCacheRetryDelay):
+		}
+	}
+}
+
+func (watch *lookupTableWatch) setCaches(caches []*vindexes.LookupCache) {
+	watch.mu.Lock()
+	defer watch.mu.Unlock()
+	watch.caches = caches
+}
+
+// invalidate removes the from value of the changed row from the caches. The
+// caches whose from column is not in the fields are cleared.
+func (watch *lookupTableWatch) invalidate(fields []*querypb.Field, row []sqltypes.Value) {
+	watch.mu.Lock()
+	defer watch.mu.Unlock()
+	for _, cache := range watch.caches {
+		idx := slices.IndexFunc(fields, func(field *querypb.Field) bool {
+			return strings.EqualFold(field.Name, cache.FromColumn())
+		})
+		if idx < 0 || idx >= len(row) {
+			cache.Clear()
+			continue
+		}
+		cache.Invalidate(row[idx])
+	}
+}
+
+func (watch *lookupTableWatch) clear() {
+	watch.mu.Lock()
+	defer watch.mu.Unlock()
+	for _, cache := range watch.caches {
+		cache.Clear()
+	}
+}
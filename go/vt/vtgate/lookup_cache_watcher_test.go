@@ -0,0 +1,118 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+func TestLookupCacheWatcher(t *testing.T) {
+	vschema := vindexes.BuildVSchema(&vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"ks": {
+				Sharded: true,
+				Vindexes: map[string]*vschemapb.Vindex{
+					"name_lookup": {
+						Type: "lookup_hash",
+						Params: map[string]string{
+							"table":      "lookup.name_idx",
+							"from":       "name",
+							"to":         "user_id",
+							"cache_size": "10",
+						},
+					},
+				},
+			},
+		},
+	}, sqlparser.NewTestParser())
+	cache := vschema.Keyspaces["ks"].Vindexes["name_lookup"].(vindexes.CachedLookup).LookupCache()
+	require.NotNil(t, cache)
+
+	type streamed struct {
+		keyspace string
+		table    string
+	}
+	started := make(chan streamed, 1)
+	events := make(chan []*binlogdatapb.VEvent)
+	processed := make(chan struct{})
+	stream := func(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid,
+		filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags, send func([]*binlogdatapb.VEvent) error) error {
+		started <- streamed{keyspace: vgtid.ShardGtids[0].Keyspace, table: filter.Rules[0].Match}
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case evs := <-events:
+				if err := send(evs); err != nil {
+					return err
+				}
+				processed <- struct{}{}
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watcher := newLookupCacheWatcher(ctx, stream)
+	defer watcher.Close()
+	watcher.Update(vschema)
+	assert.Equal(t, streamed{keyspace: "lookup", table: "name_idx"}, <-started)
+
+	fetch := func(ids []sqltypes.Value) ([]*sqltypes.Result, error) {
+		results := make([]*sqltypes.Result, 0, len(ids))
+		for i, id := range ids {
+			results = append(results, &sqltypes.Result{Rows: [][]sqltypes.Value{{id, sqltypes.NewInt64(int64(i))}}})
+		}
+		return results, nil
+	}
+	_, err := cache.Lookup([]sqltypes.Value{sqltypes.NewVarChar("alice"), sqltypes.NewVarChar("bob")}, fetch)
+	require.NoError(t, err)
+	require.Equal(t, 2, cache.Len())
+
+	fields := sqltypes.MakeTestFields("user_id|name", "int64|varchar")
+	events <- []*binlogdatapb.VEvent{{
+		Type:       binlogdatapb.VEventType_FIELD,
+		FieldEvent: &binlogdatapb.FieldEvent{TableName: "name_idx", Fields: fields},
+	}, {
+		Type: binlogdatapb.VEventType_ROW,
+		RowEvent: &binlogdatapb.RowEvent{
+			TableName: "name_idx",
+			RowChanges: []*binlogdatapb.RowChange{{
+				Before: sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarChar("alice")}),
+			}},
+		},
+	}}
+	<-processed
+	assert.Equal(t, 1, cache.Len())
+
+	// the lookup table is no longer watched when the vindex is removed.
+	watcher.Update(vindexes.BuildVSchema(&vschemapb.SrvVSchema{}, sqlparser.NewTestParser()))
+	assert.Empty(t, watcher.watches)
+}
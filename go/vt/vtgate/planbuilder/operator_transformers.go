@@ -698,6 +698,13 @@ func autoIncGenerate(gen *operators.Generate) *engine.Generate {
 	if gen == nil {
 		return nil
 	}
+	if gen.Generator != nil {
+		return &engine.Generate{
+			Generator: gen.Generator,
+			Values:    gen.Values,
+			Offset:    gen.Offset,
+		}
+	}
 	selNext := &sqlparser.Select{
 		From: []sqlparser.TableExpr{&sqlparser.AliasedTableExpr{Expr: gen.TableName}},
 	}
@@ -705,6 +712,8 @@ func autoIncGenerate(gen *operators.Generate) *engine.Generate {
 	return &engine.Generate{
 		Keyspace: gen.Keyspace,
 		Query:    sqlparser.String(selNext),
+		Batch:    gen.Batch,
+		Block:    gen.Block,
 		Values:   gen.Values,
 		Offset:   gen.Offset,
 	}
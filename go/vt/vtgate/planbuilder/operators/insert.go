@@ -61,6 +61,13 @@ type Generate struct {
 	Keyspace *vindexes.Keyspace
 	// TableName represents the name of the table.
 	TableName sqlparser.TableName
+	// Generator, if set, generates the values inside vtgate
+	// instead of the sequence table.
+	Generator vindexes.SequenceGenerator
+	// Batch, if set, is the number of values that are reserved
+	// from the sequence table at once, and kept in the Block.
+	Batch int64
+	Block *vindexes.SequenceBlock
 
 	// Values are the supplied values for the column, which
 	// will be stored as a list within the expression. New
@@ -610,9 +617,12 @@ func modifyForAutoinc(ctx *plancontext.PlanningContext, ins *sqlparser.Insert, v
 	if vTable.AutoIncrement == nil {
 		return nil
 	}
-	gen := &Generate{
-		Keyspace:  vTable.AutoIncrement.Sequence.Keyspace,
-		TableName: sqlparser.TableName{Name: vTable.AutoIncrement.Sequence.Name},
+	gen := &Generate{Generator: vTable.AutoIncrement.Generator}
+	if seq := vTable.AutoIncrement.Sequence; seq != nil {
+		gen.Keyspace = seq.Keyspace
+		gen.TableName = sqlparser.TableName{Name: seq.Name}
+		gen.Batch = vTable.AutoIncrement.Batch
+		gen.Block = vTable.AutoIncrement.Block
 	}
 	colNum, newColAdded := findOrAddColumn(ins, vTable.AutoIncrement.Column)
 	switch rows := ins.Rows.(type) {
@@ -247,7 +247,10 @@ func (option *VindexOption) updateWithNewColumn(
 	option.Predicates[indexOfCol] = node
 	option.Ready = len(option.ColsSeen) == len(colVindex.Columns)
 	routeOpcode := opcode(colVindex)
-	if option.OpCode < routeOpcode {
+	switch {
+	case option.OpCode == engine.Between && routeOpcode == engine.SubShard:
+		// the range of values of another column of the partial vindex is used for routing
+	case option.OpCode < routeOpcode, option.OpCode == engine.SubShard && routeOpcode == engine.Between:
 		option.OpCode = routeOpcode
 		option.Cost = costFor(colVindex, routeOpcode)
 	}
@@ -251,15 +251,19 @@ func (tr *ShardedRouting) planBetweenOp(ctx *plancontext.PlanningContext, node *
 	}
 	vdValue := sqlparser.ValTuple([]sqlparser.Expr{node.From, node.To})
 
+	sequential := func(vindex *vindexes.ColumnVindex) bool {
+		_, ok := vindex.Vindex.(vindexes.Sequential)
+		return ok || allowsRangeOn(vindex, column)
+	}
 	opcode := func(vindex *vindexes.ColumnVindex) engine.Opcode {
-		if _, ok := vindex.Vindex.(vindexes.Sequential); ok {
+		if sequential(vindex) {
 			return engine.Between
 		}
 		return engine.Scatter
 	}
 
 	sequentialVdx := func(vindex *vindexes.ColumnVindex) vindexes.Vindex {
-		if _, ok := vindex.Vindex.(vindexes.Sequential); ok {
+		if sequential(vindex) {
 			return vindex.Vindex
 		}
 		// if vindex is not of type Sequential, we can't use this vindex at all
@@ -284,11 +288,78 @@ func (tr *ShardedRouting) planComparison(ctx *plancontext.PlanningContext, cmp *
 	case sqlparser.LikeOp:
 		found := tr.planLikeOp(ctx, cmp)
 		return nil, found
+	case sqlparser.LessThanOp, sqlparser.LessEqualOp, sqlparser.GreaterThanOp, sqlparser.GreaterEqualOp:
+		found := tr.planRangeOp(ctx, cmp)
+		return nil, found
 
 	}
 	return nil, false
 }
 
+// planRangeOp plans '<', '<=', '>' and '>=' comparisons as a BETWEEN that is
+// unbounded on one side, for the vindexes that support open ranges.
+func (tr *ShardedRouting) planRangeOp(ctx *plancontext.PlanningContext, cmp *sqlparser.ComparisonExpr) bool {
+	op, bound := cmp.Operator, cmp.Right
+	column, ok := cmp.Left.(*sqlparser.ColName)
+	if !ok {
+		column, ok = cmp.Right.(*sqlparser.ColName)
+		if !ok {
+			return false
+		}
+		op, _ = op.SwitchSides()
+		bound = cmp.Left
+	}
+	if sqlparser.IsNull(bound) {
+		return false
+	}
+
+	// The bounds are inclusive, so '<' and '>' may route to one more shard
+	// than needed, but never to fewer.
+	vdValue := sqlparser.ValTuple{bound, &sqlparser.NullVal{}}
+	if op == sqlparser.LessThanOp || op == sqlparser.LessEqualOp {
+		vdValue = sqlparser.ValTuple{&sqlparser.NullVal{}, bound}
+	}
+
+	openSequential := func(vindex *vindexes.ColumnVindex) bool {
+		vdx, ok := vindex.Vindex.(vindexes.OpenSequential)
+		return ok && vdx.AllowsOpenRange() || allowsRangeOn(vindex, column)
+	}
+	opcode := func(vindex *vindexes.ColumnVindex) engine.Opcode {
+		if openSequential(vindex) {
+			return engine.Between
+		}
+		return engine.Scatter
+	}
+	openSequentialVdx := func(vindex *vindexes.ColumnVindex) vindexes.Vindex {
+		if openSequential(vindex) {
+			return vindex.Vindex
+		}
+		// if vindex does not support open ranges, we can't use this vindex at all
+		return nil
+	}
+
+	val := makeEvalEngineExpr(ctx, vdValue)
+	if val == nil {
+		return false
+	}
+	return tr.haveMatchingVindex(ctx, cmp, vdValue, column, val, opcode, openSequentialVdx)
+}
+
+// allowsRangeOn returns true if the vindex is a multi-column vindex that can
+// route a range of values of the column.
+func allowsRangeOn(vindex *vindexes.ColumnVindex, column *sqlparser.ColName) bool {
+	mcv, ok := vindex.Vindex.(vindexes.SequentialMultiColumn)
+	if !ok {
+		return false
+	}
+	for idx, col := range vindex.Columns {
+		if column.Name.Equal(col) {
+			return mcv.AllowsRangeOnColumn(idx)
+		}
+	}
+	return false
+}
+
 func (tr *ShardedRouting) planIsExpr(ctx *plancontext.PlanningContext, node *sqlparser.IsExpr) bool {
 	// we only handle IS NULL correct. IsExpr can contain other expressions as well
 	if node.Right != sqlparser.IsNullOp {
@@ -497,6 +568,10 @@ func (tr *ShardedRouting) processMultiColumnVindex(
 		if isPresent {
 			continue
 		}
+		if isRangeWithList(op.OpCode, routeOpcode) {
+			// a range of values of one column can't be combined with a list of values of another one
+			continue
+		}
 		option := copyOption(op)
 		optionReady := option.updateWithNewColumn(colLoweredName, valueExpr, indexOfCol, value, node, v.ColVindex, opcode)
 		if optionReady {
@@ -517,6 +592,13 @@ func (tr *ShardedRouting) processMultiColumnVindex(
 	return newVindexFound
 }
 
+func isRangeWithList(op1, op2 engine.Opcode) bool {
+	isList := func(op engine.Opcode) bool {
+		return op == engine.IN || op == engine.MultiEqual
+	}
+	return op1 == engine.Between && isList(op2) || op2 == engine.Between && isList(op1)
+}
+
 func (tr *ShardedRouting) getLoweredNameAndIndex(colVindex *vindexes.ColumnVindex, column *sqlparser.ColName) (string, int) {
 	colLoweredName := ""
 	indexOfCol := -1
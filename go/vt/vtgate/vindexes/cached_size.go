@@ -169,6 +169,86 @@ func (cached *Keyspace) CachedSize(alloc bool) int64 {
 	size += hack.RuntimeAllocSize(int64(len(cached.Name)))
 	return size
 }
+
+//go:nocheckptr
+func (cached *ListMap) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(80)
+	}
+	// field name string
+	size += hack.RuntimeAllocSize(int64(len(cached.name)))
+	// field lookup map[string][]byte
+	if cached.lookup != nil {
+		size += hack.RuntimeMapSize(cached.lookup)
+		for k, v := range cached.lookup {
+			size += hack.RuntimeAllocSize(int64(len(k)))
+			{
+				size += hack.RuntimeAllocSize(int64(cap(v)))
+			}
+		}
+	}
+	// field defaultKsid []byte
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.defaultKsid)))
+	}
+	// field unknownParams []string
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.unknownParams)) * int64(16))
+		for _, elem := range cached.unknownParams {
+			size += hack.RuntimeAllocSize(int64(len(elem)))
+		}
+	}
+	return size
+}
+
+//go:nocheckptr
+func (cached *LookupCache) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(96)
+	}
+	// field table string
+	size += hack.RuntimeAllocSize(int64(len(cached.table)))
+	// field fromColumn string
+	size += hack.RuntimeAllocSize(int64(len(cached.fromColumn)))
+	// field lru *container/list.List
+	if cached.lru != nil {
+		// WARNING: size of external type container/list.List cannot be fully calculated
+		size += hack.RuntimeAllocSize(int64(48))
+	}
+	// field entries map[string]*container/list.Element
+	if cached.entries != nil {
+		size += hack.RuntimeMapSize(cached.entries)
+		for k, v := range cached.entries {
+			size += hack.RuntimeAllocSize(int64(len(k)))
+			if v != nil {
+				// WARNING: size of external type container/list.Element cannot be fully calculated
+				size += hack.RuntimeAllocSize(int64(40))
+			}
+		}
+	}
+	// field byFrom map[string]map[string]struct{}
+	if cached.byFrom != nil {
+		size += hack.RuntimeMapSize(cached.byFrom)
+		for k, v := range cached.byFrom {
+			size += hack.RuntimeAllocSize(int64(len(k)))
+			if v != nil {
+				size += hack.RuntimeMapSize(v)
+				for k := range v {
+					size += hack.RuntimeAllocSize(int64(len(k)))
+				}
+			}
+		}
+	}
+	return size
+}
 func (cached *LookupCost) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -187,7 +267,7 @@ func (cached *LookupHash) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(192)
+		size += int64(208)
 	}
 	// field name string
 	size += hack.RuntimeAllocSize(int64(len(cached.name)))
@@ -208,7 +288,7 @@ func (cached *LookupHashUnique) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(192)
+		size += int64(208)
 	}
 	// field name string
 	size += hack.RuntimeAllocSize(int64(len(cached.name)))
@@ -229,7 +309,7 @@ func (cached *LookupNonUnique) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(192)
+		size += int64(208)
 	}
 	// field name string
 	size += hack.RuntimeAllocSize(int64(len(cached.name)))
@@ -250,7 +330,7 @@ func (cached *LookupUnicodeLooseMD5Hash) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(192)
+		size += int64(208)
 	}
 	// field name string
 	size += hack.RuntimeAllocSize(int64(len(cached.name)))
@@ -271,7 +351,7 @@ func (cached *LookupUnicodeLooseMD5HashUnique) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(192)
+		size += int64(208)
 	}
 	// field name string
 	size += hack.RuntimeAllocSize(int64(len(cached.name)))
@@ -292,7 +372,7 @@ func (cached *LookupUnique) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(192)
+		size += int64(208)
 	}
 	// field name string
 	size += hack.RuntimeAllocSize(int64(len(cached.name)))
@@ -412,6 +492,32 @@ func (cached *NumericStaticMap) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+func (cached *RangeMap) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(80)
+	}
+	// field name string
+	size += hack.RuntimeAllocSize(int64(len(cached.name)))
+	// field ranges []vitess.io/vitess/go/vt/vtgate/vindexes.rangeMapEntry
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.ranges)) * int64(72))
+		for _, elem := range cached.ranges {
+			size += elem.CachedSize(false)
+		}
+	}
+	// field unknownParams []string
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.unknownParams)) * int64(16))
+		for _, elem := range cached.unknownParams {
+			size += hack.RuntimeAllocSize(int64(len(elem)))
+		}
+	}
+	return size
+}
 func (cached *RegionExperimental) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -489,6 +595,78 @@ func (cached *ReverseBits) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+func (cached *SequenceBlock) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(24)
+	}
+	return size
+}
+func (cached *Snowflake) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(24)
+	}
+	return size
+}
+func (cached *TimeBucket) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(64)
+	}
+	// field name string
+	size += hack.RuntimeAllocSize(int64(len(cached.name)))
+	// field bucket string
+	size += hack.RuntimeAllocSize(int64(len(cached.bucket)))
+	// field unknownParams []string
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.unknownParams)) * int64(16))
+		for _, elem := range cached.unknownParams {
+			size += hack.RuntimeAllocSize(int64(len(elem)))
+		}
+	}
+	return size
+}
+func (cached *UUID) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(48)
+	}
+	// field name string
+	size += hack.RuntimeAllocSize(int64(len(cached.name)))
+	// field unknownParams []string
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.unknownParams)) * int64(16))
+		for _, elem := range cached.unknownParams {
+			size += hack.RuntimeAllocSize(int64(len(elem)))
+		}
+	}
+	return size
+}
+func (cached *UUIDv7) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(16)
+	}
+	// field Format string
+	size += hack.RuntimeAllocSize(int64(len(cached.Format)))
+	return size
+}
 func (cached *UnicodeLooseMD5) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -575,7 +753,7 @@ func (cached *clCommon) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(288)
+		size += int64(320)
 	}
 	// field name string
 	size += hack.RuntimeAllocSize(int64(len(cached.name)))
@@ -608,7 +786,7 @@ func (cached *lookupInternal) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(144)
+		size += int64(160)
 	}
 	// field Table string
 	size += hack.RuntimeAllocSize(int64(len(cached.Table)))
@@ -631,6 +809,8 @@ func (cached *lookupInternal) CachedSize(alloc bool) int64 {
 	size += hack.RuntimeAllocSize(int64(len(cached.ver)))
 	// field del string
 	size += hack.RuntimeAllocSize(int64(len(cached.del)))
+	// field cache *vitess.io/vitess/go/vt/vtgate/vindexes.LookupCache
+	size += cached.cache.CachedSize(true)
 	return size
 }
 func (cached *prefixCFC) CachedSize(alloc bool) int64 {
@@ -645,3 +825,25 @@ func (cached *prefixCFC) CachedSize(alloc bool) int64 {
 	size += cached.cfcCommon.CachedSize(true)
 	return size
 }
+func (cached *rangeMapEntry) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(80)
+	}
+	// field from []byte
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.from)))
+	}
+	// field to []byte
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.to)))
+	}
+	// field ksid []byte
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.ksid)))
+	}
+	return size
+}
@@ -46,11 +46,13 @@ var (
 	_ WantOwnerInfo   = (*ConsistentLookupUnique)(nil)
 	_ LookupPlanable  = (*ConsistentLookupUnique)(nil)
 	_ ParamValidating = (*ConsistentLookupUnique)(nil)
+	_ CachedLookup    = (*ConsistentLookupUnique)(nil)
 	_ SingleColumn    = (*ConsistentLookup)(nil)
 	_ Lookup          = (*ConsistentLookup)(nil)
 	_ WantOwnerInfo   = (*ConsistentLookup)(nil)
 	_ LookupPlanable  = (*ConsistentLookup)(nil)
 	_ ParamValidating = (*ConsistentLookup)(nil)
+	_ CachedLookup    = (*ConsistentLookup)(nil)
 
 	consistentLookupParams = append(
 		append(make([]string, 0), lookupInternalParams...),
@@ -426,6 +428,7 @@ func (lu *clCommon) handleDup(ctx context.Context, vcursor VCursor, values []sql
 		if _, err := vcursor.Execute(ctx, "VindexCreate", lu.updateLookupQuery, bindVars, true /* rollbackOnError */, vtgatepb.CommitOrder_PRE); err != nil {
 			return err
 		}
+		lu.lkp.cache.Invalidate(values[0])
 	default:
 		return fmt.Errorf("unexpected rows: %v from consistent lookup vindex", qr.Rows)
 	}
@@ -457,6 +460,11 @@ func (lu *clCommon) Update(ctx context.Context, vcursor VCursor, oldValues []sql
 	return lu.Create(ctx, vcursor, [][]sqltypes.Value{newValues}, [][]byte{ksid}, false /* ignoreMode */)
 }
 
+// LookupCache implements the CachedLookup interface.
+func (lu *clCommon) LookupCache() *LookupCache {
+	return lu.lkp.cache
+}
+
 // MarshalJSON returns a JSON representation of clCommon.
 func (lu *clCommon) MarshalJSON() ([]byte, error) {
 	return json.Marshal(lu.lkp)
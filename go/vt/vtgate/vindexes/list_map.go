@@ -0,0 +1,197 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+const (
+	listMapParamLists             = "lists"
+	listMapParamDefaultKeyspaceID = "default_keyspace_id"
+)
+
+var (
+	_ SingleColumn    = (*ListMap)(nil)
+	_ Hashing         = (*ListMap)(nil)
+	_ ParamValidating = (*ListMap)(nil)
+
+	listMapParams = []string{
+		listMapParamLists,
+		listMapParamDefaultKeyspaceID,
+	}
+)
+
+// ListMap is a functional, unique vindex that maps explicit lists of values
+// to keyspace ids, which are configured in the vschema. For example:
+//
+//	"country_lists": {
+//	  "type": "list_map",
+//	  "params": {
+//	    "lists": "{\"00\": [\"US\", \"CA\", \"MX\"], \"80\": [\"DE\", \"FR\"]}",
+//	    "default_keyspace_id": "c0"
+//	  }
+//	}
+//
+// The lists are keyed by the hex encoded keyspace id that their values map
+// to, and a value may only be in one list. Values are matched exactly, as
+// strings. Values that are not in any list map to the default_keyspace_id,
+// if one is configured, and to no keyspace id otherwise.
+type ListMap struct {
+	name          string
+	lookup        map[string][]byte
+	defaultKsid   []byte
+	unknownParams []string
+}
+
+func init() {
+	Register("list_map", newListMap)
+}
+
+// newListMap creates a ListMap vindex.
+func newListMap(name string, params map[string]string) (Vindex, error) {
+	listsStr, ok := params[listMapParamLists]
+	if !ok {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "ListMap: could not find the `lists` param in vschema")
+	}
+	var lists map[string][]json.Number
+	if err := json.Unmarshal([]byte(listsStr), &lists); err != nil {
+		// The values are either all numbers or all strings.
+		var stringLists map[string][]string
+		if err := json.Unmarshal([]byte(listsStr), &stringLists); err != nil {
+			return nil, vterrors.Wrapf(err, "ListMap: invalid `lists` param")
+		}
+		lists = make(map[string][]json.Number, len(stringLists))
+		for ksid, values := range stringLists {
+			for _, v := range values {
+				lists[ksid] = append(lists[ksid], json.Number(v))
+			}
+		}
+	}
+
+	vind := &ListMap{
+		name:          name,
+		lookup:        make(map[string][]byte),
+		unknownParams: FindUnknownParams(params, listMapParams),
+	}
+	for ksidStr, values := range lists {
+		ksid, err := parseListMapKeyspaceID(ksidStr)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			if other, ok := vind.lookup[v.String()]; ok && !bytes.Equal(other, ksid) {
+				return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "ListMap: value %q is in more than one list", v.String())
+			}
+			vind.lookup[v.String()] = ksid
+		}
+	}
+	if len(vind.lookup) == 0 {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "ListMap: the `lists` param does not contain any value")
+	}
+	if ksidStr, ok := params[listMapParamDefaultKeyspaceID]; ok {
+		ksid, err := parseListMapKeyspaceID(ksidStr)
+		if err != nil {
+			return nil, err
+		}
+		vind.defaultKsid = ksid
+	}
+	return vind, nil
+}
+
+func parseListMapKeyspaceID(ksidStr string) ([]byte, error) {
+	ksid, err := hex.DecodeString(ksidStr)
+	if err != nil || len(ksid) == 0 {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "ListMap: invalid keyspace id %q, it must be a non-empty hex string", ksidStr)
+	}
+	return ksid, nil
+}
+
+// String returns the name of the vindex.
+func (vind *ListMap) String() string {
+	return vind.name
+}
+
+// Cost returns the cost of this vindex as 1.
+func (*ListMap) Cost() int {
+	return 1
+}
+
+// IsUnique returns true since the Vindex is unique.
+func (*ListMap) IsUnique() bool {
+	return true
+}
+
+// NeedsVCursor satisfies the Vindex interface.
+func (*ListMap) NeedsVCursor() bool {
+	return false
+}
+
+// Verify returns true if ids maps to ksids.
+func (vind *ListMap) Verify(ctx context.Context, vcursor VCursor, ids []sqltypes.Value, ksids [][]byte) ([]bool, error) {
+	out := make([]bool, 0, len(ids))
+	for i, id := range ids {
+		ksid, err := vind.Hash(id)
+		if err != nil {
+			out = append(out, false)
+			continue
+		}
+		out = append(out, bytes.Equal(ksid, ksids[i]))
+	}
+	return out, nil
+}
+
+// Map can map ids to key.ShardDestination objects.
+func (vind *ListMap) Map(ctx context.Context, vcursor VCursor, ids []sqltypes.Value) ([]key.ShardDestination, error) {
+	out := make([]key.ShardDestination, 0, len(ids))
+	for _, id := range ids {
+		ksid, err := vind.Hash(id)
+		if err != nil {
+			out = append(out, key.DestinationNone{})
+			continue
+		}
+		out = append(out, key.DestinationKeyspaceID(ksid))
+	}
+	return out, nil
+}
+
+// Hash returns the keyspace id that the id maps to.
+func (vind *ListMap) Hash(id sqltypes.Value) ([]byte, error) {
+	if id.IsNull() {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "ListMap: NULL values are not in any list")
+	}
+	if ksid, ok := vind.lookup[id.ToString()]; ok {
+		return ksid, nil
+	}
+	if vind.defaultKsid != nil {
+		return vind.defaultKsid, nil
+	}
+	return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "ListMap: value %s is not in any list", id.String())
+}
+
+// UnknownParams implements the ParamValidating interface.
+func (vind *ListMap) UnknownParams() []string {
+	return vind.unknownParams
+}
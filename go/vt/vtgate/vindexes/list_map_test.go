@@ -0,0 +1,149 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+func listMapCreateVindexTestCase(
+	testName string,
+	vindexParams map[string]string,
+	expectErr error,
+	expectUnknownParams []string,
+) createVindexTestCase {
+	return createVindexTestCase{
+		testName: testName,
+
+		vindexType:   "list_map",
+		vindexName:   "list_map",
+		vindexParams: vindexParams,
+
+		expectCost:          1,
+		expectErr:           expectErr,
+		expectIsUnique:      true,
+		expectNeedsVCursor:  false,
+		expectString:        "list_map",
+		expectUnknownParams: expectUnknownParams,
+	}
+}
+
+func TestListMapCreateVindex(t *testing.T) {
+	cases := []createVindexTestCase{
+		listMapCreateVindexTestCase(
+			"lists required",
+			nil,
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "ListMap: could not find the `lists` param in vschema"),
+			nil,
+		),
+		listMapCreateVindexTestCase(
+			"string lists ok",
+			map[string]string{"lists": `{"00": ["US", "CA"], "80": ["DE"]}`, "default_keyspace_id": "c0"},
+			nil,
+			nil,
+		),
+		listMapCreateVindexTestCase(
+			"numeric lists ok",
+			map[string]string{"lists": `{"00": [1, 2], "80": [3]}`},
+			nil,
+			nil,
+		),
+		listMapCreateVindexTestCase(
+			"no values",
+			map[string]string{"lists": `{"00": []}`},
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "ListMap: the `lists` param does not contain any value"),
+			nil,
+		),
+		listMapCreateVindexTestCase(
+			"value in more than one list",
+			map[string]string{"lists": `{"00": ["US"], "80": ["US"]}`},
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, `ListMap: value "US" is in more than one list`),
+			nil,
+		),
+		listMapCreateVindexTestCase(
+			"invalid keyspace id",
+			map[string]string{"lists": `{"0g": ["US"]}`},
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, `ListMap: invalid keyspace id "0g", it must be a non-empty hex string`),
+			nil,
+		),
+		listMapCreateVindexTestCase(
+			"invalid default keyspace id",
+			map[string]string{"lists": `{"00": ["US"]}`, "default_keyspace_id": ""},
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, `ListMap: invalid keyspace id "", it must be a non-empty hex string`),
+			nil,
+		),
+		listMapCreateVindexTestCase(
+			"unknown params",
+			map[string]string{"lists": `{"00": ["US"]}`, "hello": "world"},
+			nil,
+			[]string{"hello"},
+		),
+	}
+
+	testCreateVindexes(t, cases)
+}
+
+func TestListMapMap(t *testing.T) {
+	vindex, err := CreateVindex("list_map", "list_map", map[string]string{"lists": `{"00": ["US", "CA"], "80": ["DE", "FR"]}`})
+	require.NoError(t, err)
+	lm := vindex.(SingleColumn)
+	ids := []sqltypes.Value{
+		sqltypes.NewVarChar("US"),
+		sqltypes.NewVarChar("FR"),
+		sqltypes.NewVarChar("us"),
+		sqltypes.NULL,
+	}
+	got, err := lm.Map(context.Background(), nil, ids)
+	require.NoError(t, err)
+	assert.Equal(t, []key.ShardDestination{
+		key.DestinationKeyspaceID([]byte{0x00}),
+		key.DestinationKeyspaceID([]byte{0x80}),
+		key.DestinationNone{},
+		key.DestinationNone{},
+	}, got)
+
+	// Values that are not in any list map to the default keyspace id.
+	vindex, err = CreateVindex("list_map", "list_map", map[string]string{"lists": `{"00": [1, 2], "80": [3]}`, "default_keyspace_id": "c0"})
+	require.NoError(t, err)
+	lm = vindex.(SingleColumn)
+	got, err = lm.Map(context.Background(), nil, []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarChar("3"), sqltypes.NewInt64(4)})
+	require.NoError(t, err)
+	assert.Equal(t, []key.ShardDestination{
+		key.DestinationKeyspaceID([]byte{0x00}),
+		key.DestinationKeyspaceID([]byte{0x80}),
+		key.DestinationKeyspaceID([]byte{0xc0}),
+	}, got)
+}
+
+func TestListMapVerify(t *testing.T) {
+	vindex, err := CreateVindex("list_map", "list_map", map[string]string{"lists": `{"00": ["US", "CA"], "80": ["DE", "FR"]}`})
+	require.NoError(t, err)
+	got, err := vindex.(SingleColumn).Verify(context.Background(), nil,
+		[]sqltypes.Value{sqltypes.NewVarChar("US"), sqltypes.NewVarChar("DE"), sqltypes.NewVarChar("IT")},
+		[][]byte{{0x00}, {0x00}, {0x00}})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, false, false}, got)
+}
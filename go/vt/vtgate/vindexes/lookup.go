@@ -37,10 +37,12 @@ var (
 	_ Lookup          = (*LookupUnique)(nil)
 	_ LookupPlanable  = (*LookupUnique)(nil)
 	_ ParamValidating = (*LookupUnique)(nil)
+	_ CachedLookup    = (*LookupUnique)(nil)
 	_ SingleColumn    = (*LookupNonUnique)(nil)
 	_ Lookup          = (*LookupNonUnique)(nil)
 	_ LookupPlanable  = (*LookupNonUnique)(nil)
 	_ ParamValidating = (*LookupNonUnique)(nil)
+	_ CachedLookup    = (*LookupNonUnique)(nil)
 
 	lookupParams = append(
 		append(make([]string, 0), lookupCommonParams...),
@@ -176,6 +178,11 @@ func (ln *LookupNonUnique) Update(ctx context.Context, vcursor VCursor, oldValue
 	return ln.lkp.Update(ctx, vcursor, oldValues, ksid, sqltypes.MakeTrusted(sqltypes.VarBinary, ksid), newValues)
 }
 
+// LookupCache implements the CachedLookup interface.
+func (ln *LookupNonUnique) LookupCache() *LookupCache {
+	return ln.lkp.cache
+}
+
 // MarshalJSON returns a JSON representation of LookupHash.
 func (ln *LookupNonUnique) MarshalJSON() ([]byte, error) {
 	return json.Marshal(ln.lkp)
@@ -389,6 +396,11 @@ func (lu *LookupUnique) Delete(ctx context.Context, vcursor VCursor, rowsColValu
 	return lu.lkp.Delete(ctx, vcursor, rowsColValues, sqltypes.MakeTrusted(sqltypes.VarBinary, ksid), vtgatepb.CommitOrder_NORMAL)
 }
 
+// LookupCache implements the CachedLookup interface.
+func (lu *LookupUnique) LookupCache() *LookupCache {
+	return lu.lkp.cache
+}
+
 // MarshalJSON returns a JSON representation of LookupUnique.
 func (lu *LookupUnique) MarshalJSON() ([]byte, error) {
 	return json.Marshal(lu.lkp)
@@ -0,0 +1,297 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"container/list"
+	"strconv"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+const (
+	lookupInternalParamCacheSize = "cache_size"
+	lookupInternalParamCacheTTL  = "cache_ttl"
+
+	defaultLookupCacheTTL = time.Minute
+)
+
+var (
+	lookupCacheHits          = stats.NewCountersWithSingleLabel("LookupVindexCacheHits", "Number of lookup vindex values that were found in the vtgate cache", "Table")
+	lookupCacheMisses        = stats.NewCountersWithSingleLabel("LookupVindexCacheMisses", "Number of lookup vindex values that were not found in the vtgate cache", "Table")
+	lookupCacheInvalidations = stats.NewCountersWithSingleLabel("LookupVindexCacheInvalidations", "Number of lookup vindex values that were removed from the vtgate cache because the lookup table changed", "Table")
+)
+
+// CachedLookup is implemented by the lookup vindexes that can cache the
+// results of their lookups in vtgate.
+type CachedLookup interface {
+	// LookupCache returns the cache of the vindex, or nil if the vindex
+	// is not configured to cache its results.
+	LookupCache() *LookupCache
+}
+
+// LookupCache is a bounded cache of the rows of a lookup table, keyed by the
+// value of its 'from' column. Entries expire after a TTL, and are removed when
+// the lookup table is changed by this vtgate, or when Invalidate is called,
+// which vtgate does for the changes that it sees in the VStream of the table.
+//
+// Only the values that have rows in the lookup table are cached, so that new
+// values are always looked up.
+type LookupCache struct {
+	table      string
+	fromColumn string
+	size       int
+	ttl        time.Duration
+
+	mu      sync.Mutex
+	lru     *list.List
+	entries map[string]*list.Element
+	// byFrom maps the values of the 'from' column of the cached rows to the
+	// keys of the entries that they are cached in. They can differ from the
+	// key, e.g. when the column has a case insensitive collation.
+	byFrom map[string]map[string]struct{}
+	// generation is incremented for every invalidation, so that the results
+	// of lookups that ran concurrently with it are not cached.
+	generation uint64
+}
+
+type lookupCacheEntry struct {
+	key     string
+	froms   []string
+	rows    [][]sqltypes.Value
+	expires time.Time
+}
+
+// newLookupCache creates the cache of a lookup vindex from its params. It
+// returns nil if the vindex is not configured to cache its results.
+func newLookupCache(table, fromColumn string, params map[string]string) (*LookupCache, error) {
+	sizeStr, ok := params[lookupInternalParamCacheSize]
+	if !ok {
+		return nil, nil
+	}
+	size, err := strconv.Atoi(sizeStr)
+	if err != nil || size < 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid %s value: %s", lookupInternalParamCacheSize, sizeStr)
+	}
+	ttl := defaultLookupCacheTTL
+	if ttlStr, ok := params[lookupInternalParamCacheTTL]; ok {
+		ttl, err = time.ParseDuration(ttlStr)
+		if err != nil || ttl <= 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid %s value: %s", lookupInternalParamCacheTTL, ttlStr)
+		}
+	}
+	if size == 0 {
+		return nil, nil
+	}
+	return &LookupCache{
+		table:      table,
+		fromColumn: fromColumn,
+		size:       size,
+		ttl:        ttl,
+		lru:        list.New(),
+		entries:    make(map[string]*list.Element),
+		byFrom:     make(map[string]map[string]struct{}),
+	}, nil
+}
+
+// Table returns the lookup table, which may be qualified by its keyspace.
+func (c *LookupCache) Table() string {
+	return c.table
+}
+
+// FromColumn returns the column of the lookup table that the cache is keyed by.
+func (c *LookupCache) FromColumn() string {
+	return c.fromColumn
+}
+
+// Len returns the number of cached values.
+func (c *LookupCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// Lookup returns the 'to' values of the rows of the lookup table for each of
+// the ids. The ids that are not cached are looked up with fetch, which must
+// return the matching rows of the lookup table for each of them, as pairs of
+// 'from' and 'to' values. The cache may be nil, in which case all the ids
+// are fetched.
+func (c *LookupCache) Lookup(ids []sqltypes.Value, fetch func(ids []sqltypes.Value) ([]*sqltypes.Result, error)) ([]*sqltypes.Result, error) {
+	if c == nil {
+		fetched, err := fetch(ids)
+		if err != nil {
+			return nil, err
+		}
+		results := make([]*sqltypes.Result, 0, len(fetched))
+		for _, result := range fetched {
+			rows, _ := lookupToValues(result)
+			results = append(results, &sqltypes.Result{Rows: rows})
+		}
+		return results, nil
+	}
+
+	results := make([]*sqltypes.Result, len(ids))
+	var missing []sqltypes.Value
+	var missingIdx []int
+	for i, id := range ids {
+		if rows, ok := c.get(id.ToString()); ok {
+			results[i] = &sqltypes.Result{Rows: rows}
+			continue
+		}
+		missing = append(missing, id)
+		missingIdx = append(missingIdx, i)
+	}
+	lookupCacheHits.Add(c.table, int64(len(ids)-len(missing)))
+	if len(missing) == 0 {
+		return results, nil
+	}
+	lookupCacheMisses.Add(c.table, int64(len(missing)))
+
+	generation := c.currentGeneration()
+	fetched, err := fetch(missing)
+	if err != nil {
+		return nil, err
+	}
+	for i, result := range fetched {
+		rows, froms := lookupToValues(result)
+		results[missingIdx[i]] = &sqltypes.Result{Rows: rows}
+		if len(rows) != 0 {
+			c.set(generation, missing[i].ToString(), froms, rows)
+		}
+	}
+	return results, nil
+}
+
+// Invalidate removes the entries that contain rows with the from value.
+func (c *LookupCache) Invalidate(from sqltypes.Value) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	fromStr := from.ToString()
+	// the value may also be cached under its own key without rows from it,
+	// e.g. before it is inserted.
+	keys := c.byFrom[fromStr]
+	if _, ok := c.entries[fromStr]; ok {
+		c.remove(c.entries[fromStr])
+		lookupCacheInvalidations.Add(c.table, 1)
+	}
+	for key := range keys {
+		if element, ok := c.entries[key]; ok {
+			c.remove(element)
+			lookupCacheInvalidations.Add(c.table, 1)
+		}
+	}
+}
+
+// Clear removes all the entries of the cache.
+func (c *LookupCache) Clear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.lru.Init()
+	c.entries = make(map[string]*list.Element)
+	c.byFrom = make(map[string]map[string]struct{})
+}
+
+func (c *LookupCache) currentGeneration() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+func (c *LookupCache) get(key string) ([][]sqltypes.Value, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*lookupCacheEntry)
+	if time.Now().After(entry.expires) {
+		c.remove(element)
+		return nil, false
+	}
+	c.lru.MoveToFront(element)
+	return entry.rows, true
+}
+
+func (c *LookupCache) set(generation uint64, key string, froms []string, rows [][]sqltypes.Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		// the lookup table changed while the rows were fetched.
+		return
+	}
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+	entry := &lookupCacheEntry{
+		key:     key,
+		froms:   froms,
+		rows:    rows,
+		expires: time.Now().Add(c.ttl),
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	for _, from := range froms {
+		keys, ok := c.byFrom[from]
+		if !ok {
+			keys = make(map[string]struct{})
+			c.byFrom[from] = keys
+		}
+		keys[key] = struct{}{}
+	}
+	for c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *LookupCache) remove(element *list.Element) {
+	entry := c.lru.Remove(element).(*lookupCacheEntry)
+	delete(c.entries, entry.key)
+	for _, from := range entry.froms {
+		delete(c.byFrom[from], entry.key)
+		if len(c.byFrom[from]) == 0 {
+			delete(c.byFrom, from)
+		}
+	}
+}
+
+// lookupToValues splits the (from, to) rows of a lookup into the rows of the
+// 'to' values and the 'from' values.
+func lookupToValues(result *sqltypes.Result) ([][]sqltypes.Value, []string) {
+	if result.Rows == nil {
+		return nil, nil
+	}
+	rows := make([][]sqltypes.Value, 0, len(result.Rows))
+	froms := make([]string, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		rows = append(rows, []sqltypes.Value{row[1]})
+		froms = append(froms, row[0].ToString())
+	}
+	return rows, froms
+}
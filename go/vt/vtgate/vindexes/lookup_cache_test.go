@@ -0,0 +1,223 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+)
+
+func TestNewLookupCache(t *testing.T) {
+	testCases := []struct {
+		name    string
+		params  map[string]string
+		enabled bool
+		err     string
+	}{
+		{
+			name: "no cache",
+		},
+		{
+			name:   "zero size",
+			params: map[string]string{"cache_size": "0"},
+		},
+		{
+			name:    "default ttl",
+			params:  map[string]string{"cache_size": "10"},
+			enabled: true,
+		},
+		{
+			name:    "ttl",
+			params:  map[string]string{"cache_size": "10", "cache_ttl": "5s"},
+			enabled: true,
+		},
+		{
+			name:   "invalid size",
+			params: map[string]string{"cache_size": "-1"},
+			err:    "invalid cache_size value: -1",
+		},
+		{
+			name:   "invalid ttl",
+			params: map[string]string{"cache_size": "10", "cache_ttl": "soon"},
+			err:    "invalid cache_ttl value: soon",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cache, err := newLookupCache("t", "fromc", tc.params)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.enabled, cache != nil)
+		})
+	}
+}
+
+// lookupCacheFetcher returns a fetch function that maps each id to a row
+// with the id and its length, except for the ids in missing, and counts the
+// ids that it fetches.
+func lookupCacheFetcher(fetched *int, missing ...string) func(ids []sqltypes.Value) ([]*sqltypes.Result, error) {
+	return func(ids []sqltypes.Value) ([]*sqltypes.Result, error) {
+		*fetched += len(ids)
+		results := make([]*sqltypes.Result, 0, len(ids))
+		for _, id := range ids {
+			result := &sqltypes.Result{}
+			if slices.Contains(missing, id.ToString()) {
+				results = append(results, result)
+				continue
+			}
+			result.Rows = [][]sqltypes.Value{{id, sqltypes.NewInt64(int64(len(id.ToString())))}}
+			results = append(results, result)
+		}
+		return results, nil
+	}
+}
+
+func TestLookupCacheLookup(t *testing.T) {
+	cache, err := newLookupCache("t", "fromc", map[string]string{"cache_size": "2"})
+	require.NoError(t, err)
+
+	var fetched int
+	fetch := lookupCacheFetcher(&fetched, "none")
+	results, err := cache.Lookup([]sqltypes.Value{sqltypes.NewVarChar("a"), sqltypes.NewVarChar("none")}, fetch)
+	require.NoError(t, err)
+	assert.Equal(t, []*sqltypes.Result{
+		{Rows: [][]sqltypes.Value{{sqltypes.NewInt64(1)}}},
+		{},
+	}, results)
+	assert.Equal(t, 2, fetched)
+	assert.Equal(t, 1, cache.Len())
+
+	// only the values that are not in the lookup table are fetched again.
+	results, err = cache.Lookup([]sqltypes.Value{sqltypes.NewVarChar("a"), sqltypes.NewVarChar("none")}, fetch)
+	require.NoError(t, err)
+	assert.Equal(t, []*sqltypes.Result{
+		{Rows: [][]sqltypes.Value{{sqltypes.NewInt64(1)}}},
+		{},
+	}, results)
+	assert.Equal(t, 3, fetched)
+
+	// the least recently used value is evicted.
+	_, err = cache.Lookup([]sqltypes.Value{sqltypes.NewVarChar("bb"), sqltypes.NewVarChar("a"), sqltypes.NewVarChar("ccc")}, fetch)
+	require.NoError(t, err)
+	assert.Equal(t, 5, fetched)
+	assert.Equal(t, 2, cache.Len())
+	_, err = cache.Lookup([]sqltypes.Value{sqltypes.NewVarChar("bb"), sqltypes.NewVarChar("ccc")}, fetch)
+	require.NoError(t, err)
+	assert.Equal(t, 5, fetched)
+	_, err = cache.Lookup([]sqltypes.Value{sqltypes.NewVarChar("a")}, fetch)
+	require.NoError(t, err)
+	assert.Equal(t, 6, fetched)
+
+	// expired values are fetched again.
+	cache.ttl = time.Nanosecond
+	cache.Clear()
+	_, err = cache.Lookup([]sqltypes.Value{sqltypes.NewVarChar("a")}, fetch)
+	require.NoError(t, err)
+	time.Sleep(time.Millisecond)
+	_, err = cache.Lookup([]sqltypes.Value{sqltypes.NewVarChar("a")}, fetch)
+	require.NoError(t, err)
+	assert.Equal(t, 8, fetched)
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestLookupCacheNil(t *testing.T) {
+	var cache *LookupCache
+	var fetched int
+	results, err := cache.Lookup([]sqltypes.Value{sqltypes.NewVarChar("a"), sqltypes.NewVarChar("none")}, lookupCacheFetcher(&fetched, "none"))
+	require.NoError(t, err)
+	assert.Equal(t, []*sqltypes.Result{
+		{Rows: [][]sqltypes.Value{{sqltypes.NewInt64(1)}}},
+		{},
+	}, results)
+	cache.Invalidate(sqltypes.NewVarChar("a"))
+	cache.Clear()
+}
+
+func TestLookupCacheInvalidate(t *testing.T) {
+	cache, err := newLookupCache("t", "fromc", map[string]string{"cache_size": "10"})
+	require.NoError(t, err)
+
+	// the rows are stored with a different case than the looked up values,
+	// as with a case insensitive collation.
+	fetch := func(ids []sqltypes.Value) ([]*sqltypes.Result, error) {
+		results := make([]*sqltypes.Result, 0, len(ids))
+		for range ids {
+			results = append(results, &sqltypes.Result{Rows: [][]sqltypes.Value{{sqltypes.NewVarChar("a"), sqltypes.NewInt64(1)}}})
+		}
+		return results, nil
+	}
+	_, err = cache.Lookup([]sqltypes.Value{sqltypes.NewVarChar("A"), sqltypes.NewVarChar("a")}, fetch)
+	require.NoError(t, err)
+	assert.Equal(t, 2, cache.Len())
+
+	cache.Invalidate(sqltypes.NewVarChar("b"))
+	assert.Equal(t, 2, cache.Len())
+	cache.Invalidate(sqltypes.NewVarChar("a"))
+	assert.Equal(t, 0, cache.Len())
+
+	// the results of a lookup that ran concurrently with an invalidation
+	// are not cached.
+	_, err = cache.Lookup([]sqltypes.Value{sqltypes.NewVarChar("a")}, func(ids []sqltypes.Value) ([]*sqltypes.Result, error) {
+		cache.Invalidate(sqltypes.NewVarChar("a"))
+		return fetch(ids)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestLookupHashCache(t *testing.T) {
+	vindex, err := CreateVindex("lookup_hash", "lookup_hash", map[string]string{
+		"table":      "t",
+		"from":       "fromc",
+		"to":         "toc",
+		"cache_size": "10",
+	})
+	require.NoError(t, err)
+	lookup := vindex.(*LookupHash)
+	require.NotNil(t, lookup.LookupCache())
+
+	vc := &vcursor{numRows: 1}
+	ids := []sqltypes.Value{sqltypes.NewInt64(1)}
+	want := []key.ShardDestination{
+		key.DestinationKeyspaceIDs([][]byte{[]byte("\x16k@\xb4J\xbaK\xd6")}),
+	}
+	for range 2 {
+		got, err := lookup.Map(context.Background(), vc, ids)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	assert.Len(t, vc.queries, 1)
+
+	// writes to the lookup table invalidate the values that they change.
+	err = lookup.Create(context.Background(), vc, [][]sqltypes.Value{{sqltypes.NewInt64(1)}}, [][]byte{[]byte("\x16k@\xb4J\xbaK\xd6")}, false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, lookup.LookupCache().Len())
+	_, err = lookup.Map(context.Background(), vc, ids)
+	require.NoError(t, err)
+	assert.Len(t, vc.queries, 3)
+}
@@ -37,10 +37,12 @@ var (
 	_ Lookup          = (*LookupHash)(nil)
 	_ LookupPlanable  = (*LookupHash)(nil)
 	_ ParamValidating = (*LookupHash)(nil)
+	_ CachedLookup    = (*LookupHash)(nil)
 	_ SingleColumn    = (*LookupHashUnique)(nil)
 	_ Lookup          = (*LookupHashUnique)(nil)
 	_ LookupPlanable  = (*LookupHashUnique)(nil)
 	_ ParamValidating = (*LookupHashUnique)(nil)
+	_ CachedLookup    = (*LookupHashUnique)(nil)
 
 	lookupHashParams = append(
 		append(make([]string, 0), lookupCommonParams...),
@@ -238,6 +240,11 @@ func (lh *LookupHash) Delete(ctx context.Context, vcursor VCursor, rowsColValues
 	return lh.lkp.Delete(ctx, vcursor, rowsColValues, sqltypes.NewUint64(v), vtgatepb.CommitOrder_NORMAL)
 }
 
+// LookupCache implements the CachedLookup interface.
+func (lh *LookupHash) LookupCache() *LookupCache {
+	return lh.lkp.cache
+}
+
 // MarshalJSON returns a JSON representation of LookupHash.
 func (lh *LookupHash) MarshalJSON() ([]byte, error) {
 	return json.Marshal(lh.lkp)
@@ -416,6 +423,11 @@ func (lhu *LookupHashUnique) Update(ctx context.Context, vcursor VCursor, oldVal
 	return lhu.lkp.Update(ctx, vcursor, oldValues, ksid, sqltypes.NewUint64(v), newValues)
 }
 
+// LookupCache implements the CachedLookup interface.
+func (lhu *LookupHashUnique) LookupCache() *LookupCache {
+	return lhu.lkp.cache
+}
+
 // MarshalJSON returns a JSON representation of LookupHashUnique.
 func (lhu *LookupHashUnique) MarshalJSON() ([]byte, error) {
 	return json.Marshal(lhu.lkp)
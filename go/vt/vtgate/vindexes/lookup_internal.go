@@ -71,6 +71,8 @@ var (
 		lookupInternalParamIgnoreNulls,
 		lookupInternalParamBatchLookup,
 		lookupInternalParamReadLock,
+		lookupInternalParamCacheSize,
+		lookupInternalParamCacheTTL,
 	}
 )
 
@@ -86,6 +88,7 @@ type lookupInternal struct {
 	BatchLookup             bool     `json:"batch_lookup,omitempty"`
 	ReadLock                string   `json:"read_lock,omitempty"`
 	sel, selTxDml, ver, del string   // sel: map query, ver: verify query, del: delete query
+	cache                   *LookupCache
 }
 
 func (lkp *lookupInternal) Init(lookupQueryParams map[string]string, autocommit, upsert, multiShardAutocommit bool) error {
@@ -113,6 +116,11 @@ func (lkp *lookupInternal) Init(lookupQueryParams map[string]string, autocommit,
 		lkp.ReadLock = readLock
 	}
 
+	lkp.cache, err = newLookupCache(lkp.Table, lkp.FromColumns[0], lookupQueryParams)
+	if err != nil {
+		return err
+	}
+
 	lkp.Autocommit = autocommit
 	lkp.Upsert = upsert
 	if multiShardAutocommit {
@@ -143,16 +151,26 @@ func (lkp *lookupInternal) Lookup(ctx context.Context, vcursor VCursor, ids []sq
 	if vcursor == nil {
 		return nil, vterrors.VT13001("cannot perform lookup: no vcursor provided")
 	}
-	results := make([]*sqltypes.Result, 0, len(ids))
 	if lkp.Autocommit {
 		co = vtgatepb.CommitOrder_AUTOCOMMIT
 	}
 	var sel string
+	cache := lkp.cache
 	if vcursor.InTransactionAndIsDML() {
 		sel = lkp.selTxDml
+		// the rows must be read, and locked, from the lookup table.
+		cache = nil
 	} else {
 		sel = lkp.sel
 	}
+	return cache.Lookup(ids, func(ids []sqltypes.Value) ([]*sqltypes.Result, error) {
+		return lkp.fetch(ctx, vcursor, sel, ids, co)
+	})
+}
+
+// fetch returns the (from, to) rows of the lookup table for each of the ids.
+func (lkp *lookupInternal) fetch(ctx context.Context, vcursor VCursor, sel string, ids []sqltypes.Value, co vtgatepb.CommitOrder) ([]*sqltypes.Result, error) {
+	results := make([]*sqltypes.Result, 0, len(ids))
 	if ids[0].IsIntegral() || lkp.BatchLookup {
 		// for integral types, batch query all ids and then map them back to the input order
 		vars, err := sqltypes.BuildBindVariable(ids)
@@ -168,7 +186,7 @@ func (lkp *lookupInternal) Lookup(ctx context.Context, vcursor VCursor, ids []sq
 		}
 		resultMap := make(map[string][][]sqltypes.Value)
 		for _, row := range result.Rows {
-			resultMap[row[0].ToString()] = append(resultMap[row[0].ToString()], []sqltypes.Value{row[1]})
+			resultMap[row[0].ToString()] = append(resultMap[row[0].ToString()], row)
 		}
 
 		for _, id := range ids {
@@ -192,9 +210,7 @@ func (lkp *lookupInternal) Lookup(ctx context.Context, vcursor VCursor, ids []sq
 				return nil, vterrors.Wrap(err, "lookup.Map")
 			}
 			rows := make([][]sqltypes.Value, 0, len(result.Rows))
-			for _, row := range result.Rows {
-				rows = append(rows, []sqltypes.Value{row[1]})
-			}
+			rows = append(rows, result.Rows...)
 			results = append(results, &sqltypes.Result{
 				Rows: rows,
 			})
@@ -349,6 +365,9 @@ nextRow:
 	if _, err := vcursor.Execute(ctx, "VindexCreate", buf.String(), bindVars, true /* rollbackOnError */, co); err != nil {
 		return vterrors.Wrap(err, "lookup.Create")
 	}
+	for _, row := range trimmedRowsCols {
+		lkp.cache.Invalidate(row[0])
+	}
 	return nil
 }
 
@@ -391,6 +410,7 @@ func (lkp *lookupInternal) Delete(ctx context.Context, vcursor VCursor, rowsColV
 		if err != nil {
 			return vterrors.Wrap(err, "lookup.Delete")
 		}
+		lkp.cache.Invalidate(column[0])
 	}
 	return nil
 }
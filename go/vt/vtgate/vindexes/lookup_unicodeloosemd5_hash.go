@@ -36,9 +36,11 @@ var (
 	_ SingleColumn    = (*LookupUnicodeLooseMD5Hash)(nil)
 	_ Lookup          = (*LookupUnicodeLooseMD5Hash)(nil)
 	_ ParamValidating = (*LookupUnicodeLooseMD5Hash)(nil)
+	_ CachedLookup    = (*LookupUnicodeLooseMD5Hash)(nil)
 	_ SingleColumn    = (*LookupUnicodeLooseMD5HashUnique)(nil)
 	_ Lookup          = (*LookupUnicodeLooseMD5HashUnique)(nil)
 	_ ParamValidating = (*LookupUnicodeLooseMD5HashUnique)(nil)
+	_ CachedLookup    = (*LookupUnicodeLooseMD5HashUnique)(nil)
 
 	lookupUnicodeLooseMD5HashParams = append(
 		append(make([]string, 0), lookupCommonParams...),
@@ -231,6 +233,11 @@ func (lh *LookupUnicodeLooseMD5Hash) Delete(ctx context.Context, vcursor VCursor
 	return lh.lkp.Delete(ctx, vcursor, rowsColValues, sqltypes.NewUint64(v), vtgatepb.CommitOrder_NORMAL)
 }
 
+// LookupCache implements the CachedLookup interface.
+func (lh *LookupUnicodeLooseMD5Hash) LookupCache() *LookupCache {
+	return lh.lkp.cache
+}
+
 // MarshalJSON returns a JSON representation of LookupHash.
 func (lh *LookupUnicodeLooseMD5Hash) MarshalJSON() ([]byte, error) {
 	return json.Marshal(lh.lkp)
@@ -411,6 +418,11 @@ func (lhu *LookupUnicodeLooseMD5HashUnique) Update(ctx context.Context, vcursor
 	return lhu.lkp.Update(ctx, vcursor, oldValues, ksid, sqltypes.NewUint64(v), newValues)
 }
 
+// LookupCache implements the CachedLookup interface.
+func (lhu *LookupUnicodeLooseMD5HashUnique) LookupCache() *LookupCache {
+	return lhu.lkp.cache
+}
+
 // MarshalJSON returns a JSON representation of LookupHashUnique.
 func (lhu *LookupUnicodeLooseMD5HashUnique) MarshalJSON() ([]byte, error) {
 	return json.Marshal(lhu.lkp)
@@ -19,6 +19,7 @@ package vindexes
 import (
 	"bytes"
 	"context"
+	"maps"
 	"math"
 	"strconv"
 	"strings"
@@ -30,7 +31,8 @@ import (
 )
 
 var (
-	_ MultiColumn = (*MultiCol)(nil)
+	_ MultiColumn           = (*MultiCol)(nil)
+	_ SequentialMultiColumn = (*MultiCol)(nil)
 )
 
 type MultiCol struct {
@@ -121,6 +123,51 @@ func (m *MultiCol) PartialVindex() bool {
 	return true
 }
 
+// AllowsRangeOnColumn implements SequentialMultiColumn. Ranges of values can
+// be mapped for the columns whose vindex preserves the order of the values.
+func (m *MultiCol) AllowsRangeOnColumn(idx int) bool {
+	vdx, ok := m.columnVdx[idx].(OrderPreserving)
+	return ok && vdx.PreservesOrder()
+}
+
+// RangeMap implements SequentialMultiColumn. The keyspace ids of the range
+// start with the bytes of the prefix values, followed by the bytes of the
+// range column that are between the ones of startId and endId. The columns
+// that follow the range column are not restricted.
+func (m *MultiCol) RangeMap(ctx context.Context, vcursor VCursor, prefixValues []sqltypes.Value, startId sqltypes.Value, endId sqltypes.Value) ([]key.ShardDestination, error) {
+	idx := len(prefixValues)
+	if idx >= m.noOfCols || !m.AllowsRangeOnColumn(idx) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "[BUG] ranges of values of column %d cannot be mapped by vindex %s", idx, m.name)
+	}
+	_, prefix, err := m.mapKsid(prefixValues)
+	if err != nil {
+		return []key.ShardDestination{key.DestinationNone{}}, nil
+	}
+	// the range column starts after the bytes of the prefix columns.
+	offset := 0
+	for i := 0; i < idx; i++ {
+		offset += m.columnBytes[i]
+	}
+	for len(prefix) < offset {
+		prefix = append(prefix, 0)
+	}
+
+	// a bound that cannot be hashed leaves the range unbounded on that side,
+	// which may route to more shards than needed, but never to fewer.
+	bound := func(id sqltypes.Value) []byte {
+		if id.IsNull() {
+			return nil
+		}
+		hash, err := m.columnVdx[idx].Hash(id)
+		if err != nil {
+			return nil
+		}
+		return hash[:min(len(hash), m.columnBytes[idx])]
+	}
+	start, end := bound(startId), bound(endId)
+	return []key.ShardDestination{orderedKeyRange(prefix, start, end)}, nil
+}
+
 func (m *MultiCol) mapKsid(colValues []sqltypes.Value) (bool, []byte, error) {
 	if m.noOfCols < len(colValues) {
 		// wrong number of column values were passed
@@ -155,6 +202,9 @@ func init() {
 	Register("multicol", newMultiCol)
 }
 
+// getColumnVindex creates the vindex of each column. The params other than
+// the ones of the multicol vindex itself are passed to the column vindexes,
+// and each column vindex is only given the params that it uses.
 func getColumnVindex(m map[string]string, colCount int) (map[int]Hashing, int, error) {
 	var colVdxs []string
 	colVdxsStr, ok := m[paramColumnVindex]
@@ -188,6 +238,17 @@ func getColumnVindex(m map[string]string, colCount int) (map[int]Hashing, int, e
 		if err != nil {
 			return nil, 0, err
 		}
+		if pv, ok := vdx.(ParamValidating); ok && len(pv.UnknownParams()) > 0 {
+			// The params are meant for the other column vindexes, so the
+			// vindex is created again without them.
+			ownParams := maps.Clone(subParams)
+			for _, param := range pv.UnknownParams() {
+				delete(ownParams, param)
+			}
+			if vdx, err = CreateVindex(selVdx, selVdx, ownParams); err != nil {
+				return nil, 0, err
+			}
+		}
 		hashVdx, isHashVdx := vdx.(Hashing)
 		if !isHashVdx || !vdx.IsUnique() || vdx.NeedsVCursor() {
 			return nil, 0, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "multicol vindex supports vindexes that exports hashing function, are unique and are non-lookup vindex, passed vindex '%s' is invalid", selVdx)
@@ -253,3 +253,88 @@ func TestMultiColMap(t *testing.T) {
 	}
 	assert.Equal(t, want, got)
 }
+
+func TestMultiColRangeMap(t *testing.T) {
+	vindex, err := CreateVindex("multicol", "multicol_range_map", map[string]string{
+		"column_count":  "3",
+		"column_vindex": "hash,time_bucket,hash",
+		"column_bytes":  "2,2,4",
+		"bucket":        "month",
+	})
+	require.NoError(t, err)
+	multiCol := vindex.(SequentialMultiColumn)
+	// each column vindex is only given its own params.
+	for idx, vdx := range vindex.(*MultiCol).columnVdx {
+		assert.Empty(t, vdx.(ParamValidating).UnknownParams(), "column %d", idx)
+	}
+	assert.Equal(t, timeBucketMonth, vindex.(*MultiCol).columnVdx[1].(*TimeBucket).bucket)
+	assert.False(t, multiCol.AllowsRangeOnColumn(0))
+	assert.True(t, multiCol.AllowsRangeOnColumn(1))
+	assert.False(t, multiCol.AllowsRangeOnColumn(2))
+
+	testCases := []struct {
+		name       string
+		prefix     []sqltypes.Value
+		start, end sqltypes.Value
+		want       string
+	}{
+		{
+			name:   "range within the prefix",
+			prefix: []sqltypes.Value{sqltypes.NewInt64(1)},
+			start:  sqltypes.NewVarChar("2024-01-15"),
+			end:    sqltypes.NewVarChar("2024-03-01"),
+			want:   "DestinationKeyRange(166b0288-166b028b)",
+		},
+		{
+			name:   "unbounded end",
+			prefix: []sqltypes.Value{sqltypes.NewInt64(1)},
+			start:  sqltypes.NewVarChar("2024-01-15"),
+			end:    sqltypes.NULL,
+			want:   "DestinationKeyRange(166b0288-166c)",
+		},
+		{
+			name:   "unbounded start",
+			prefix: []sqltypes.Value{sqltypes.NewInt64(1)},
+			start:  sqltypes.NULL,
+			end:    sqltypes.NewVarChar("2024-01-15"),
+			want:   "DestinationKeyRange(166b-166b0289)",
+		},
+		{
+			name:   "empty range",
+			prefix: []sqltypes.Value{sqltypes.NewInt64(1)},
+			start:  sqltypes.NewVarChar("2024-03-01"),
+			end:    sqltypes.NewVarChar("2024-01-15"),
+			want:   "DestinationNone()",
+		},
+		{
+			name:   "invalid prefix value",
+			prefix: []sqltypes.Value{sqltypes.NewVarBinary("abcd")},
+			start:  sqltypes.NewVarChar("2024-01-15"),
+			end:    sqltypes.NULL,
+			want:   "DestinationNone()",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := multiCol.RangeMap(context.Background(), nil, tc.prefix, tc.start, tc.end)
+			require.NoError(t, err)
+			require.Len(t, got, 1)
+			assert.Equal(t, tc.want, got[0].String())
+		})
+	}
+
+	_, err = multiCol.RangeMap(context.Background(), nil, nil, sqltypes.NewInt64(1), sqltypes.NULL)
+	require.Error(t, err)
+
+	// the time bucket is the first column, and is truncated to a single byte.
+	vindex, err = CreateVindex("multicol", "multicol_range_map", map[string]string{
+		"column_count":  "2",
+		"column_vindex": "time_bucket,hash",
+		"column_bytes":  "1,7",
+		"bucket":        "month",
+	})
+	require.NoError(t, err)
+	got, err := vindex.(SequentialMultiColumn).RangeMap(context.Background(), nil, nil, sqltypes.NewVarChar("2024-01-15"), sqltypes.NewVarChar("2024-03-01"))
+	require.NoError(t, err)
+	assert.Equal(t, []key.ShardDestination{key.DestinationKeyRange{KeyRange: &topodatapb.KeyRange{Start: []byte{0x02}, End: []byte{0x03}}}}, got)
+}
@@ -0,0 +1,405 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/collations/charset"
+	"vitess.io/vitess/go/mysql/collations/colldata"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+const (
+	rangeMapParamRanges    = "ranges"
+	rangeMapParamType      = "type"
+	rangeMapParamCollation = "collation"
+
+	// rangeMapTypeNumeric compares the values as signed 64 bit integers.
+	rangeMapTypeNumeric = "numeric"
+	// rangeMapTypeString compares the values as strings, using the
+	// collation of the column.
+	rangeMapTypeString = "string"
+)
+
+var (
+	_ SingleColumn    = (*RangeMap)(nil)
+	_ Hashing         = (*RangeMap)(nil)
+	_ OpenSequential  = (*RangeMap)(nil)
+	_ ParamValidating = (*RangeMap)(nil)
+
+	rangeMapParams = []string{
+		rangeMapParamRanges,
+		rangeMapParamType,
+		rangeMapParamCollation,
+	}
+)
+
+// rangeMapEntry maps the values in [from, to) to a keyspace id. A nil from
+// or to means that the range is unbounded on that side. For string ranges,
+// from and to are the weight strings of the bounds, which are kept as they
+// were configured in fromStr and toStr.
+type rangeMapEntry struct {
+	from, to       []byte
+	fromStr, toStr string
+	ksid           []byte
+}
+
+// RangeMap is a functional, unique vindex that maps explicit ranges of values
+// to keyspace ids, which are configured in the vschema. For example:
+//
+//	"tenant_bands": {
+//	  "type": "range_map",
+//	  "params": {
+//	    "ranges": "[{\"to\": 1000, \"keyspace_id\": \"00\"}, {\"from\": 1000, \"to\": 5000, \"keyspace_id\": \"40\"}, {\"from\": 5000, \"keyspace_id\": \"80\"}]"
+//	  }
+//	}
+//
+// The lower bound of a range is inclusive and its upper bound exclusive. The
+// ranges may not overlap, and values outside of all of them do not map to any
+// keyspace id. The values are compared as signed integers by default. When
+// the type param is "string", they are compared as strings using the
+// collation param, which must be the collation of the column, e.g.
+// "utf8mb4_0900_ai_ci", or "binary" for a binary or varbinary column.
+//
+// As the order of the values is preserved, RangeMap can limit the shards that
+// a BETWEEN, '<', '<=', '>' or '>=' predicate is routed to.
+type RangeMap struct {
+	name    string
+	numeric bool
+	// collation is used to compare string values. padSpace is set when it
+	// ignores trailing spaces.
+	collation     colldata.Collation
+	padSpace      bool
+	ranges        []rangeMapEntry
+	unknownParams []string
+}
+
+func init() {
+	Register("range_map", newRangeMap)
+}
+
+// newRangeMap creates a RangeMap vindex.
+func newRangeMap(name string, params map[string]string) (Vindex, error) {
+	typ := params[rangeMapParamType]
+	switch typ {
+	case "":
+		typ = rangeMapTypeNumeric
+	case rangeMapTypeNumeric, rangeMapTypeString:
+	default:
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "RangeMap: invalid type %q, it must be %q or %q", typ, rangeMapTypeNumeric, rangeMapTypeString)
+	}
+	rangesStr, ok := params[rangeMapParamRanges]
+	if !ok {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "RangeMap: could not find the `ranges` param in vschema")
+	}
+	vind := &RangeMap{
+		name:          name,
+		numeric:       typ == rangeMapTypeNumeric,
+		unknownParams: FindUnknownParams(params, rangeMapParams),
+	}
+	collationName, ok := params[rangeMapParamCollation]
+	switch {
+	case vind.numeric && ok:
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "RangeMap: the `collation` param can only be used with the %q type", rangeMapTypeString)
+	case !vind.numeric && !ok:
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "RangeMap: the `collation` param is required with the %q type, it must be the collation of the column, or \"binary\" for a binary or varbinary column",
+			rangeMapTypeString)
+	case !vind.numeric:
+		id, ok := collations.MySQL8().LookupID(collationName)
+		if !ok {
+			return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "RangeMap: unknown collation %q", collationName)
+		}
+		vind.collation = colldata.Lookup(id)
+		vind.padSpace = vind.collation.Collate([]byte("a"), []byte("a "), false) == 0
+	}
+	if err := vind.parseRanges(rangesStr); err != nil {
+		return nil, err
+	}
+	return vind, nil
+}
+
+func (vind *RangeMap) parseRanges(rangesStr string) error {
+	var ranges []struct {
+		From       *json.Number `json:"from"`
+		To         *json.Number `json:"to"`
+		KeyspaceID string       `json:"keyspace_id"`
+	}
+	if vind.numeric {
+		if err := json.Unmarshal([]byte(rangesStr), &ranges); err != nil {
+			return vterrors.Wrapf(err, "RangeMap: invalid `ranges` param")
+		}
+	} else {
+		// String bounds are not numbers, so they are read separately.
+		var stringRanges []struct {
+			From       *string `json:"from"`
+			To         *string `json:"to"`
+			KeyspaceID string  `json:"keyspace_id"`
+		}
+		if err := json.Unmarshal([]byte(rangesStr), &stringRanges); err != nil {
+			return vterrors.Wrapf(err, "RangeMap: invalid `ranges` param")
+		}
+		for _, r := range stringRanges {
+			entry := rangeMapEntry{}
+			if r.From != nil {
+				from, err := vind.weightString([]byte(*r.From))
+				if err != nil {
+					return err
+				}
+				entry.from, entry.fromStr = from, *r.From
+			}
+			if r.To != nil {
+				to, err := vind.weightString([]byte(*r.To))
+				if err != nil {
+					return err
+				}
+				entry.to, entry.toStr = to, *r.To
+			}
+			vind.ranges = append(vind.ranges, entry)
+			if err := vind.setKeyspaceID(r.KeyspaceID); err != nil {
+				return err
+			}
+		}
+	}
+	for _, r := range ranges {
+		from, err := numericBound(r.From)
+		if err != nil {
+			return err
+		}
+		to, err := numericBound(r.To)
+		if err != nil {
+			return err
+		}
+		vind.ranges = append(vind.ranges, rangeMapEntry{from: from, to: to})
+		if err := vind.setKeyspaceID(r.KeyspaceID); err != nil {
+			return err
+		}
+	}
+	if len(vind.ranges) == 0 {
+		return vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "RangeMap: the `ranges` param does not contain any range")
+	}
+
+	sort.SliceStable(vind.ranges, func(i, j int) bool {
+		// A nil lower bound is lower than any other one.
+		return vind.ranges[j].from != nil && (vind.ranges[i].from == nil || bytes.Compare(vind.ranges[i].from, vind.ranges[j].from) < 0)
+	})
+	for i, r := range vind.ranges {
+		if r.from != nil && r.to != nil && bytes.Compare(r.from, r.to) >= 0 {
+			return vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "RangeMap: the range %s is empty", vind.rangeString(r))
+		}
+		if i > 0 {
+			prev := vind.ranges[i-1]
+			if prev.to == nil || r.from == nil || bytes.Compare(prev.to, r.from) > 0 {
+				return vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "RangeMap: the ranges %s and %s overlap", vind.rangeString(prev), vind.rangeString(r))
+			}
+		}
+	}
+	return nil
+}
+
+func (vind *RangeMap) setKeyspaceID(ksidStr string) error {
+	ksid, err := hex.DecodeString(ksidStr)
+	if err != nil || len(ksid) == 0 {
+		return vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "RangeMap: invalid keyspace_id %q, it must be a non-empty hex string", ksidStr)
+	}
+	vind.ranges[len(vind.ranges)-1].ksid = ksid
+	return nil
+}
+
+// String returns the name of the vindex.
+func (vind *RangeMap) String() string {
+	return vind.name
+}
+
+// Cost returns the cost of this vindex as 1.
+func (*RangeMap) Cost() int {
+	return 1
+}
+
+// IsUnique returns true since the Vindex is unique.
+func (*RangeMap) IsUnique() bool {
+	return true
+}
+
+// NeedsVCursor satisfies the Vindex interface.
+func (*RangeMap) NeedsVCursor() bool {
+	return false
+}
+
+// Verify returns true if ids maps to ksids.
+func (vind *RangeMap) Verify(ctx context.Context, vcursor VCursor, ids []sqltypes.Value, ksids [][]byte) ([]bool, error) {
+	out := make([]bool, 0, len(ids))
+	for i, id := range ids {
+		ksid, err := vind.Hash(id)
+		if err != nil {
+			out = append(out, false)
+			continue
+		}
+		out = append(out, bytes.Equal(ksid, ksids[i]))
+	}
+	return out, nil
+}
+
+// Map can map ids to key.ShardDestination objects.
+func (vind *RangeMap) Map(ctx context.Context, vcursor VCursor, ids []sqltypes.Value) ([]key.ShardDestination, error) {
+	out := make([]key.ShardDestination, 0, len(ids))
+	for _, id := range ids {
+		ksid, err := vind.Hash(id)
+		if err != nil {
+			out = append(out, key.DestinationNone{})
+			continue
+		}
+		out = append(out, key.DestinationKeyspaceID(ksid))
+	}
+	return out, nil
+}
+
+// RangeMap implements Sequential. A NULL startId or endId means that the
+// range is unbounded on that side. Both bounds are inclusive.
+func (vind *RangeMap) RangeMap(ctx context.Context, vcursor VCursor, startId sqltypes.Value, endId sqltypes.Value) ([]key.ShardDestination, error) {
+	var start, end []byte
+	if !startId.IsNull() {
+		var err error
+		if start, err = vind.value(startId); err != nil {
+			return nil, err
+		}
+	}
+	if !endId.IsNull() {
+		var err error
+		if end, err = vind.value(endId); err != nil {
+			return nil, err
+		}
+	}
+	if start != nil && end != nil && bytes.Compare(start, end) > 0 {
+		return []key.ShardDestination{key.DestinationNone{}}, nil
+	}
+
+	var ksids key.DestinationKeyspaceIDs
+	for _, r := range vind.ranges {
+		// The range is [r.from, r.to) and the requested one [start, end].
+		if end != nil && r.from != nil && bytes.Compare(r.from, end) > 0 {
+			break
+		}
+		if start != nil && r.to != nil && bytes.Compare(r.to, start) <= 0 {
+			continue
+		}
+		ksids = append(ksids, r.ksid)
+	}
+	if len(ksids) == 0 {
+		return []key.ShardDestination{key.DestinationNone{}}, nil
+	}
+	return []key.ShardDestination{ksids}, nil
+}
+
+// AllowsOpenRange implements OpenSequential.
+func (*RangeMap) AllowsOpenRange() bool {
+	return true
+}
+
+// Hash returns the keyspace id that the id maps to.
+func (vind *RangeMap) Hash(id sqltypes.Value) ([]byte, error) {
+	v, err := vind.value(id)
+	if err != nil {
+		return nil, err
+	}
+	i := sort.Search(len(vind.ranges), func(i int) bool {
+		return vind.ranges[i].to == nil || bytes.Compare(vind.ranges[i].to, v) > 0
+	})
+	if i == len(vind.ranges) || (vind.ranges[i].from != nil && bytes.Compare(vind.ranges[i].from, v) > 0) {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "RangeMap: value %s is not in any range", id.String())
+	}
+	return vind.ranges[i].ksid, nil
+}
+
+// UnknownParams implements the ParamValidating interface.
+func (vind *RangeMap) UnknownParams() []string {
+	return vind.unknownParams
+}
+
+// value returns the id in a form where its byte order is its value order.
+func (vind *RangeMap) value(id sqltypes.Value) ([]byte, error) {
+	if id.IsNull() {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "RangeMap: NULL values are not in any range")
+	}
+	if !vind.numeric {
+		return vind.weightString(id.Raw())
+	}
+	num, err := id.ToCastInt64()
+	if err != nil {
+		return nil, err
+	}
+	return orderedInt64(num), nil
+}
+
+// weightString returns the weight string of a string value in the vindex's
+// collation, so that the byte order of the weight strings is the collation
+// order of the values.
+// The result is never nil, as a nil bound means that a range is unbounded.
+func (vind *RangeMap) weightString(v []byte) ([]byte, error) {
+	if vind.collation.IsBinary() {
+		return append([]byte{}, v...), nil
+	}
+	v, err := charset.ConvertFromUTF8(nil, vind.collation.Charset(), v)
+	if err != nil {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "RangeMap: %v", err)
+	}
+	if vind.padSpace {
+		v = bytes.TrimRight(v, " ")
+	}
+	return vind.collation.WeightString([]byte{}, v, 0), nil
+}
+
+func (vind *RangeMap) rangeString(r rangeMapEntry) string {
+	bound := func(b []byte, str string) string {
+		switch {
+		case b == nil:
+			return ""
+		case vind.numeric:
+			return strconv.FormatInt(int64(binary.BigEndian.Uint64(b)^(1<<63)), 10)
+		default:
+			return strconv.Quote(str)
+		}
+	}
+	return "[" + bound(r.from, r.fromStr) + ", " + bound(r.to, r.toStr) + ")"
+}
+
+func numericBound(n *json.Number) ([]byte, error) {
+	if n == nil {
+		return nil, nil
+	}
+	num, err := strconv.ParseInt(n.String(), 10, 64)
+	if err != nil {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "RangeMap: invalid numeric bound %s", n.String())
+	}
+	return orderedInt64(num), nil
+}
+
+// orderedInt64 encodes num so that the byte order of the encoded values
+// matches the order of the numbers.
+func orderedInt64(num int64) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(num)^(1<<63))
+}
@@ -0,0 +1,320 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+const testRangeMapRanges = `[
+	{"from": 5000, "keyspace_id": "c0"},
+	{"to": 1000, "keyspace_id": "00"},
+	{"from": 1000, "to": 2000, "keyspace_id": "40"},
+	{"from": 3000, "to": 5000, "keyspace_id": "80"}
+]`
+
+func rangeMapCreateVindexTestCase(
+	testName string,
+	vindexParams map[string]string,
+	expectErr error,
+	expectUnknownParams []string,
+) createVindexTestCase {
+	return createVindexTestCase{
+		testName: testName,
+
+		vindexType:   "range_map",
+		vindexName:   "range_map",
+		vindexParams: vindexParams,
+
+		expectCost:          1,
+		expectErr:           expectErr,
+		expectIsUnique:      true,
+		expectNeedsVCursor:  false,
+		expectString:        "range_map",
+		expectUnknownParams: expectUnknownParams,
+	}
+}
+
+func TestRangeMapCreateVindex(t *testing.T) {
+	cases := []createVindexTestCase{
+		rangeMapCreateVindexTestCase(
+			"ranges required",
+			nil,
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "RangeMap: could not find the `ranges` param in vschema"),
+			nil,
+		),
+		rangeMapCreateVindexTestCase(
+			"ranges ok",
+			map[string]string{"ranges": testRangeMapRanges},
+			nil,
+			nil,
+		),
+		rangeMapCreateVindexTestCase(
+			"string ranges ok",
+			map[string]string{"ranges": `[{"to": "m", "keyspace_id": "00"}, {"from": "m", "keyspace_id": "80"}]`, "type": "string", "collation": "utf8mb4_0900_ai_ci"},
+			nil,
+			nil,
+		),
+		rangeMapCreateVindexTestCase(
+			"string ranges without collation",
+			map[string]string{"ranges": `[{"to": "m", "keyspace_id": "00"}, {"from": "m", "keyspace_id": "80"}]`, "type": "string"},
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, `RangeMap: the `+"`collation`"+` param is required with the "string" type, it must be the collation of the column, or "binary" for a binary or varbinary column`),
+			nil,
+		),
+		rangeMapCreateVindexTestCase(
+			"unknown collation",
+			map[string]string{"ranges": `[{"to": "m", "keyspace_id": "00"}]`, "type": "string", "collation": "utf8mb4_klingon_ci"},
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, `RangeMap: unknown collation "utf8mb4_klingon_ci"`),
+			nil,
+		),
+		rangeMapCreateVindexTestCase(
+			"collation with numeric ranges",
+			map[string]string{"ranges": testRangeMapRanges, "collation": "binary"},
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, `RangeMap: the `+"`collation`"+` param can only be used with the "string" type`),
+			nil,
+		),
+		rangeMapCreateVindexTestCase(
+			"string ranges overlapping in the collation",
+			map[string]string{"ranges": `[{"from": "a", "to": "C", "keyspace_id": "00"}, {"from": "b", "keyspace_id": "80"}]`, "type": "string", "collation": "utf8mb4_0900_ai_ci"},
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, `RangeMap: the ranges ["a", "C") and ["b", ) overlap`),
+			nil,
+		),
+		rangeMapCreateVindexTestCase(
+			"invalid type",
+			map[string]string{"ranges": testRangeMapRanges, "type": "float"},
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, `RangeMap: invalid type "float", it must be "numeric" or "string"`),
+			nil,
+		),
+		rangeMapCreateVindexTestCase(
+			"no ranges",
+			map[string]string{"ranges": "[]"},
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "RangeMap: the `ranges` param does not contain any range"),
+			nil,
+		),
+		rangeMapCreateVindexTestCase(
+			"invalid numeric bound",
+			map[string]string{"ranges": `[{"from": 1.5, "keyspace_id": "00"}]`},
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "RangeMap: invalid numeric bound 1.5"),
+			nil,
+		),
+		rangeMapCreateVindexTestCase(
+			"invalid keyspace id",
+			map[string]string{"ranges": `[{"from": 1, "keyspace_id": "zz"}]`},
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, `RangeMap: invalid keyspace_id "zz", it must be a non-empty hex string`),
+			nil,
+		),
+		rangeMapCreateVindexTestCase(
+			"empty range",
+			map[string]string{"ranges": `[{"from": 10, "to": 10, "keyspace_id": "00"}]`},
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "RangeMap: the range [10, 10) is empty"),
+			nil,
+		),
+		rangeMapCreateVindexTestCase(
+			"overlapping ranges",
+			map[string]string{"ranges": `[{"from": -10, "to": 10, "keyspace_id": "00"}, {"from": 5, "keyspace_id": "80"}]`},
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "RangeMap: the ranges [-10, 10) and [5, ) overlap"),
+			nil,
+		),
+		rangeMapCreateVindexTestCase(
+			"overlapping unbounded ranges",
+			map[string]string{"ranges": `[{"to": 10, "keyspace_id": "00"}, {"keyspace_id": "80"}]`},
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "RangeMap: the ranges [, 10) and [, ) overlap"),
+			nil,
+		),
+		rangeMapCreateVindexTestCase(
+			"invalid json",
+			map[string]string{"ranges": "[{]"},
+			errors.New("RangeMap: invalid `ranges` param: invalid character ']' looking for beginning of object key string"),
+			nil,
+		),
+		rangeMapCreateVindexTestCase(
+			"unknown params",
+			map[string]string{"ranges": testRangeMapRanges, "hello": "world"},
+			nil,
+			[]string{"hello"},
+		),
+	}
+
+	testCreateVindexes(t, cases)
+}
+
+func createRangeMap(t *testing.T, params map[string]string) *RangeMap {
+	vindex, err := CreateVindex("range_map", "range_map", params)
+	require.NoError(t, err)
+	return vindex.(*RangeMap)
+}
+
+func TestRangeMapMap(t *testing.T) {
+	rm := createRangeMap(t, map[string]string{"ranges": testRangeMapRanges})
+	got, err := rm.Map(context.Background(), nil, []sqltypes.Value{
+		sqltypes.NewInt64(-5),
+		sqltypes.NewInt64(999),
+		sqltypes.NewInt64(1000),
+		sqltypes.NewVarChar("1999"),
+		sqltypes.NewInt64(2500),
+		sqltypes.NewInt64(4999),
+		sqltypes.NewUint64(5000),
+		sqltypes.NewVarChar("abc"),
+		sqltypes.NULL,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []key.ShardDestination{
+		key.DestinationKeyspaceID([]byte{0x00}),
+		key.DestinationKeyspaceID([]byte{0x00}),
+		key.DestinationKeyspaceID([]byte{0x40}),
+		key.DestinationKeyspaceID([]byte{0x40}),
+		key.DestinationNone{},
+		key.DestinationKeyspaceID([]byte{0x80}),
+		key.DestinationKeyspaceID([]byte{0xc0}),
+		key.DestinationNone{},
+		key.DestinationNone{},
+	}, got)
+
+	// Strings are compared using the collation of the column, so that a row
+	// is routed to the same shard whichever way its value is written.
+	rm = createRangeMap(t, map[string]string{"ranges": `[{"to": "m", "keyspace_id": "00"}, {"from": "m", "keyspace_id": "80"}]`, "type": "string", "collation": "utf8mb4_0900_ai_ci"})
+	got, err = rm.Map(context.Background(), nil, []sqltypes.Value{
+		sqltypes.NewVarChar("apple"),
+		sqltypes.NewVarChar("m"),
+		sqltypes.NewVarChar("zebra"),
+		sqltypes.NewVarChar("Zebra"),
+		sqltypes.NewVarChar("ZEBRA"),
+		sqltypes.NewVarChar("Äpple"),
+		sqltypes.NewVarChar("M"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []key.ShardDestination{
+		key.DestinationKeyspaceID([]byte{0x00}),
+		key.DestinationKeyspaceID([]byte{0x80}),
+		key.DestinationKeyspaceID([]byte{0x80}),
+		key.DestinationKeyspaceID([]byte{0x80}),
+		key.DestinationKeyspaceID([]byte{0x80}),
+		key.DestinationKeyspaceID([]byte{0x00}),
+		key.DestinationKeyspaceID([]byte{0x80}),
+	}, got)
+
+	// With a binary collation, the bytes are compared, and upper case
+	// letters sort before lower case ones.
+	rm = createRangeMap(t, map[string]string{"ranges": `[{"to": "m", "keyspace_id": "00"}, {"from": "m", "keyspace_id": "80"}]`, "type": "string", "collation": "binary"})
+	got, err = rm.Map(context.Background(), nil, []sqltypes.Value{
+		sqltypes.NewVarBinary("apple"),
+		sqltypes.NewVarBinary("zebra"),
+		sqltypes.NewVarBinary("Zebra"),
+		sqltypes.NewVarBinary("m "),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []key.ShardDestination{
+		key.DestinationKeyspaceID([]byte{0x00}),
+		key.DestinationKeyspaceID([]byte{0x80}),
+		key.DestinationKeyspaceID([]byte{0x00}),
+		key.DestinationKeyspaceID([]byte{0x80}),
+	}, got)
+
+	// Trailing spaces are ignored by PAD SPACE collations.
+	rm = createRangeMap(t, map[string]string{"ranges": `[{"to": "m", "keyspace_id": "00"}, {"from": "m", "keyspace_id": "80"}]`, "type": "string", "collation": "latin1_swedish_ci"})
+	got, err = rm.Map(context.Background(), nil, []sqltypes.Value{
+		sqltypes.NewVarChar("l   "),
+		sqltypes.NewVarChar("M  "),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []key.ShardDestination{
+		key.DestinationKeyspaceID([]byte{0x00}),
+		key.DestinationKeyspaceID([]byte{0x80}),
+	}, got)
+}
+
+func TestRangeMapVerify(t *testing.T) {
+	rm := createRangeMap(t, map[string]string{"ranges": testRangeMapRanges})
+	got, err := rm.Verify(context.Background(), nil,
+		[]sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewInt64(1), sqltypes.NewInt64(2500)},
+		[][]byte{{0x00}, {0x40}, {0x40}})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, false, false}, got)
+}
+
+func TestRangeMapRangeMap(t *testing.T) {
+	rm := createRangeMap(t, map[string]string{"ranges": testRangeMapRanges})
+	testCases := []struct {
+		name       string
+		start, end sqltypes.Value
+		want       string
+	}{
+		{
+			name:  "within one range",
+			start: sqltypes.NewInt64(10),
+			end:   sqltypes.NewInt64(20),
+			want:  "DestinationKeyspaceIDs(00)",
+		},
+		{
+			name:  "across ranges",
+			start: sqltypes.NewInt64(500),
+			end:   sqltypes.NewInt64(3000),
+			want:  "DestinationKeyspaceIDs(00,40,80)",
+		},
+		{
+			name:  "bounds are inclusive",
+			start: sqltypes.NewInt64(1999),
+			end:   sqltypes.NewInt64(5000),
+			want:  "DestinationKeyspaceIDs(40,80,c0)",
+		},
+		{
+			name:  "unbounded start",
+			start: sqltypes.NULL,
+			end:   sqltypes.NewInt64(1000),
+			want:  "DestinationKeyspaceIDs(00,40)",
+		},
+		{
+			name:  "unbounded end",
+			start: sqltypes.NewInt64(4000),
+			end:   sqltypes.NULL,
+			want:  "DestinationKeyspaceIDs(80,c0)",
+		},
+		{
+			name:  "gap between ranges",
+			start: sqltypes.NewInt64(2000),
+			end:   sqltypes.NewInt64(2999),
+			want:  "DestinationNone()",
+		},
+		{
+			name:  "empty range",
+			start: sqltypes.NewInt64(20),
+			end:   sqltypes.NewInt64(10),
+			want:  "DestinationNone()",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := rm.RangeMap(context.Background(), nil, tc.start, tc.end)
+			require.NoError(t, err)
+			require.Len(t, got, 1)
+			assert.Equal(t, tc.want, got[0].String())
+		})
+	}
+
+	_, err := rm.RangeMap(context.Background(), nil, sqltypes.NewVarChar("abc"), sqltypes.NULL)
+	require.Error(t, err)
+}
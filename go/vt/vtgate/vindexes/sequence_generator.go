@@ -0,0 +1,332 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// This file defines the generators that produce the values of an
+// auto-increment column inside vtgate, without the round trip to
+// a sequence table, and the blocks in which vtgate reserves the
+// values of a sequence table in batches.
+
+type (
+	// SequenceGenerator generates the values of an auto-increment column.
+	SequenceGenerator interface {
+		// Type returns the type of the generator, as used in the vschema.
+		Type() string
+		// Generate returns count new values.
+		Generate(count int64) ([]sqltypes.Value, error)
+	}
+
+	// NewSequenceGeneratorFunc creates a SequenceGenerator from the
+	// params of the auto_increment section of a table.
+	NewSequenceGeneratorFunc func(params map[string]string) (SequenceGenerator, error)
+)
+
+const (
+	// SnowflakeGenerator is the type of the snowflake sequence generator.
+	SnowflakeGenerator = "snowflake"
+	// UUIDv7Generator is the type of the UUIDv7 sequence generator.
+	UUIDv7Generator = "uuidv7"
+
+	snowflakeParamEpoch = "epoch"
+	uuidv7ParamFormat   = "format"
+
+	// SequenceParamBatch is the param of an auto_increment that uses a
+	// sequence table, with the number of values that vtgate reserves from
+	// the sequence table at once.
+	SequenceParamBatch = "batch"
+
+	uuidv7FormatString = "string"
+	uuidv7FormatBinary = "binary"
+
+	snowflakeWorkerBits   = 10
+	snowflakeSequenceBits = 12
+	snowflakeMaxWorkerID  = 1<<snowflakeWorkerBits - 1
+	snowflakeMaxSequence  = 1<<snowflakeSequenceBits - 1
+)
+
+var (
+	generatorRegistry = make(map[string]NewSequenceGeneratorFunc)
+
+	// defaultSnowflakeEpoch is 2020-01-01T00:00:00Z, which leaves the 41 bits
+	// of the timestamp of the snowflake ids good until 2089.
+	defaultSnowflakeEpoch = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	// sequenceWorkerID is the id of this vtgate in the snowflake ids it
+	// generates, or -1 when it is not set.
+	sequenceWorkerID atomic.Int64
+
+	// snowflakeClock is shared by all the snowflake generators, so that the
+	// ids stay unique when the vschema is rebuilt and new generators are
+	// created.
+	snowflakeClock struct {
+		mu       sync.Mutex
+		millis   int64
+		sequence int64
+	}
+
+	// sequenceBlocks holds the block of each sequence table, so that the
+	// values that were reserved are not lost when the vschema is rebuilt.
+	sequenceBlocks struct {
+		mu     sync.Mutex
+		blocks map[string]*SequenceBlock
+	}
+)
+
+func init() {
+	sequenceWorkerID.Store(-1)
+	RegisterSequenceGenerator(SnowflakeGenerator, newSnowflake)
+	RegisterSequenceGenerator(UUIDv7Generator, newUUIDv7)
+}
+
+// RegisterSequenceGenerator registers a sequence generator type.
+func RegisterSequenceGenerator(generatorType string, newFunc NewSequenceGeneratorFunc) {
+	if _, ok := generatorRegistry[generatorType]; ok {
+		panic(fmt.Sprintf("%s is already registered", generatorType))
+	}
+	generatorRegistry[generatorType] = newFunc
+}
+
+// CreateSequenceGenerator creates a sequence generator of the specified type
+// using the supplied params. The type must have been previously registered.
+func CreateSequenceGenerator(generatorType string, params map[string]string) (SequenceGenerator, error) {
+	f, ok := generatorRegistry[generatorType]
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "sequence generator %q not found", generatorType)
+	}
+	return f(params)
+}
+
+// SetSequenceWorkerID sets the id of this vtgate in the snowflake ids it
+// generates. The id must be unique across the vtgates of the cluster.
+func SetSequenceWorkerID(id int64) error {
+	if id < -1 || id > snowflakeMaxWorkerID {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid sequence worker id %d, it must be between 0 and %d", id, snowflakeMaxWorkerID)
+	}
+	sequenceWorkerID.Store(id)
+	return nil
+}
+
+func checkGeneratorParams(generatorType string, params map[string]string, known ...string) error {
+	for param := range params {
+		found := false
+		for _, k := range known {
+			if param == k {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown param %q for sequence generator %s", param, generatorType)
+		}
+	}
+	return nil
+}
+
+// Snowflake generates time ordered 64 bit ids made of the milliseconds since
+// its epoch (41 bits), the worker id of the vtgate (10 bits) and a sequence
+// number within the millisecond (12 bits). A vtgate generates up to 4096 ids
+// per millisecond; past that, it borrows the following milliseconds rather
+// than waiting for them, so a burst never blocks the insert.
+type Snowflake struct {
+	Epoch time.Time `json:"epoch"`
+}
+
+var _ SequenceGenerator = (*Snowflake)(nil)
+
+func newSnowflake(params map[string]string) (SequenceGenerator, error) {
+	if err := checkGeneratorParams(SnowflakeGenerator, params, snowflakeParamEpoch); err != nil {
+		return nil, err
+	}
+	epoch := defaultSnowflakeEpoch
+	if value, ok := params[snowflakeParamEpoch]; ok {
+		millis, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || millis < 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid epoch %q for sequence generator snowflake, it must be a unix timestamp in milliseconds", value)
+		}
+		epoch = time.UnixMilli(millis).UTC()
+	}
+	return &Snowflake{Epoch: epoch}, nil
+}
+
+// Type implements the SequenceGenerator interface.
+func (*Snowflake) Type() string {
+	return SnowflakeGenerator
+}
+
+// Generate implements the SequenceGenerator interface.
+func (sf *Snowflake) Generate(count int64) ([]sqltypes.Value, error) {
+	return sf.generate(time.Now(), count)
+}
+
+func (sf *Snowflake) generate(now time.Time, count int64) ([]sqltypes.Value, error) {
+	workerID := sequenceWorkerID.Load()
+	if workerID < 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the snowflake sequence generator requires the --sequence-worker-id of vtgate to be set")
+	}
+	epoch := sf.Epoch.UnixMilli()
+	millis := now.UnixMilli()
+	if millis < epoch {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the epoch of the snowflake sequence generator is in the future")
+	}
+
+	snowflakeClock.mu.Lock()
+	defer snowflakeClock.mu.Unlock()
+
+	values := make([]sqltypes.Value, 0, count)
+	for range count {
+		switch {
+		case millis > snowflakeClock.millis:
+			// The clock never goes backwards, so that the ids stay ordered.
+			snowflakeClock.millis = millis
+			snowflakeClock.sequence = 0
+		case snowflakeClock.sequence < snowflakeMaxSequence:
+			snowflakeClock.sequence++
+		default:
+			snowflakeClock.millis++
+			snowflakeClock.sequence = 0
+		}
+		id := (snowflakeClock.millis-epoch)<<(snowflakeWorkerBits+snowflakeSequenceBits) |
+			workerID<<snowflakeSequenceBits |
+			snowflakeClock.sequence
+		values = append(values, sqltypes.NewInt64(id))
+	}
+	return values, nil
+}
+
+// UUIDv7 generates time ordered UUIDs as defined by RFC 9562, either as
+// strings for CHAR(36) columns or as 16 bytes for BINARY(16) columns.
+type UUIDv7 struct {
+	Format string `json:"format"`
+}
+
+var _ SequenceGenerator = (*UUIDv7)(nil)
+
+func newUUIDv7(params map[string]string) (SequenceGenerator, error) {
+	if err := checkGeneratorParams(UUIDv7Generator, params, uuidv7ParamFormat); err != nil {
+		return nil, err
+	}
+	format := params[uuidv7ParamFormat]
+	switch format {
+	case "":
+		format = uuidv7FormatString
+	case uuidv7FormatString, uuidv7FormatBinary:
+	default:
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid format %q for sequence generator uuidv7, it must be %q or %q", format, uuidv7FormatString, uuidv7FormatBinary)
+	}
+	return &UUIDv7{Format: format}, nil
+}
+
+// Type implements the SequenceGenerator interface.
+func (*UUIDv7) Type() string {
+	return UUIDv7Generator
+}
+
+// Generate implements the SequenceGenerator interface.
+func (u *UUIDv7) Generate(count int64) ([]sqltypes.Value, error) {
+	values := make([]sqltypes.Value, 0, count)
+	for range count {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "failed to generate a uuidv7")
+		}
+		if u.Format == uuidv7FormatBinary {
+			values = append(values, sqltypes.MakeTrusted(sqltypes.VarBinary, id[:]))
+		} else {
+			values = append(values, sqltypes.NewVarChar(id.String()))
+		}
+	}
+	return values, nil
+}
+
+// SequenceBlock holds the values of a sequence table that vtgate reserved in
+// a batch and has not handed out yet, so that most inserts get their values
+// without a round trip to the sequence table. The tables that use the same
+// sequence share its block.
+type SequenceBlock struct {
+	mu   sync.Mutex
+	next int64
+	end  int64
+}
+
+// getSequenceBlock returns the block of the sequence table.
+func getSequenceBlock(seq *BaseTable) *SequenceBlock {
+	name := seq.Keyspace.Name + "." + seq.Name.String()
+	sequenceBlocks.mu.Lock()
+	defer sequenceBlocks.mu.Unlock()
+	if sequenceBlocks.blocks == nil {
+		sequenceBlocks.blocks = make(map[string]*SequenceBlock)
+	}
+	block, ok := sequenceBlocks.blocks[name]
+	if !ok {
+		block = &SequenceBlock{}
+		sequenceBlocks.blocks[name] = block
+	}
+	return block
+}
+
+// Reserve returns the first of count consecutive values of the sequence.
+// When the block does not hold count values, it calls reserve to reserve the
+// next batch values, or count values if there are more, from the sequence
+// table, and the values left in the block are skipped.
+func (b *SequenceBlock) Reserve(count, batch int64, reserve func(n int64) (int64, error)) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.end-b.next < count {
+		n := max(count, batch)
+		first, err := reserve(n)
+		if err != nil {
+			return 0, err
+		}
+		b.next, b.end = first, first+n
+	}
+	first := b.next
+	b.next += count
+	return first, nil
+}
+
+func parseSequenceBatch(autoInc *vschemapb.AutoIncrement) (int64, error) {
+	for param := range autoInc.Params {
+		if param != SequenceParamBatch {
+			return 0, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown param %q for sequence %s", param, autoInc.Sequence)
+		}
+	}
+	value, ok := autoInc.Params[SequenceParamBatch]
+	if !ok {
+		return 0, nil
+	}
+	batch, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || batch < 1 {
+		return 0, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid batch %q for sequence %s, it must be a positive number", value, autoInc.Sequence)
+	}
+	return batch, nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+)
+
+func TestSnowflake(t *testing.T) {
+	gen, err := CreateSequenceGenerator("snowflake", nil)
+	require.NoError(t, err)
+	sf := gen.(*Snowflake)
+	assert.Equal(t, defaultSnowflakeEpoch, sf.Epoch)
+
+	// The worker id of the vtgate must be set.
+	_, err = sf.Generate(1)
+	require.ErrorContains(t, err, "the snowflake sequence generator requires the --sequence-worker-id of vtgate to be set")
+
+	require.ErrorContains(t, SetSequenceWorkerID(1024), "invalid sequence worker id 1024, it must be between 0 and 1023")
+	require.NoError(t, SetSequenceWorkerID(5))
+	defer SetSequenceWorkerID(-1)
+
+	now := time.Now().Add(time.Hour)
+	millis := now.UnixMilli() - defaultSnowflakeEpoch.UnixMilli()
+	values, err := sf.generate(now, 3)
+	require.NoError(t, err)
+	require.Equal(t, []sqltypes.Value{
+		sqltypes.NewInt64(millis<<22 | 5<<12),
+		sqltypes.NewInt64(millis<<22 | 5<<12 | 1),
+		sqltypes.NewInt64(millis<<22 | 5<<12 | 2),
+	}, values)
+
+	// The clock does not go backwards, and borrows the next millisecond once
+	// the sequence of the current one is exhausted.
+	values, err = sf.generate(now.Add(-time.Second), snowflakeMaxSequence)
+	require.NoError(t, err)
+	assert.Equal(t, sqltypes.NewInt64(millis<<22|5<<12|snowflakeMaxSequence), values[len(values)-3])
+	assert.Equal(t, sqltypes.NewInt64((millis+1)<<22|5<<12), values[len(values)-2])
+	assert.Equal(t, sqltypes.NewInt64((millis+1)<<22|5<<12|1), values[len(values)-1])
+
+	gen, err = CreateSequenceGenerator("snowflake", map[string]string{"epoch": "1700000000000"})
+	require.NoError(t, err)
+	assert.Equal(t, time.UnixMilli(1700000000000).UTC(), gen.(*Snowflake).Epoch)
+
+	_, err = CreateSequenceGenerator("snowflake", map[string]string{"epoch": "2024-01-01"})
+	require.ErrorContains(t, err, `invalid epoch "2024-01-01" for sequence generator snowflake`)
+	_, err = CreateSequenceGenerator("snowflake", map[string]string{"worker_id": "1"})
+	require.ErrorContains(t, err, `unknown param "worker_id" for sequence generator snowflake`)
+}
+
+func TestUUIDv7(t *testing.T) {
+	gen, err := CreateSequenceGenerator("uuidv7", nil)
+	require.NoError(t, err)
+	values, err := gen.Generate(2)
+	require.NoError(t, err)
+	require.Len(t, values, 2)
+	var ids []uuid.UUID
+	for _, value := range values {
+		assert.Equal(t, sqltypes.VarChar, value.Type())
+		id, err := uuid.Parse(value.ToString())
+		require.NoError(t, err)
+		assert.Equal(t, uuid.Version(7), id.Version())
+		ids = append(ids, id)
+	}
+	// The ids are ordered.
+	assert.Less(t, ids[0].String(), ids[1].String())
+
+	gen, err = CreateSequenceGenerator("uuidv7", map[string]string{"format": "binary"})
+	require.NoError(t, err)
+	values, err = gen.Generate(1)
+	require.NoError(t, err)
+	assert.Equal(t, sqltypes.VarBinary, values[0].Type())
+	assert.Len(t, values[0].Raw(), 16)
+
+	_, err = CreateSequenceGenerator("uuidv7", map[string]string{"format": "hex"})
+	require.ErrorContains(t, err, `invalid format "hex" for sequence generator uuidv7, it must be "string" or "binary"`)
+}
+
+func TestSequenceBlock(t *testing.T) {
+	var reserved []int64
+	next := int64(100)
+	reserve := func(n int64) (int64, error) {
+		reserved = append(reserved, n)
+		first := next
+		next += n
+		return first, nil
+	}
+	block := &SequenceBlock{}
+
+	// The first values reserve a batch.
+	first, err := block.Reserve(2, 5, reserve)
+	require.NoError(t, err)
+	assert.EqualValues(t, 100, first)
+	first, err = block.Reserve(3, 5, reserve)
+	require.NoError(t, err)
+	assert.EqualValues(t, 102, first)
+	assert.Equal(t, []int64{5}, reserved)
+
+	// More values than a batch are reserved at once.
+	first, err = block.Reserve(7, 5, reserve)
+	require.NoError(t, err)
+	assert.EqualValues(t, 105, first)
+	assert.Equal(t, []int64{5, 7}, reserved)
+
+	// The block is left unchanged when the values cannot be reserved.
+	first, err = block.Reserve(1, 5, reserve)
+	require.NoError(t, err)
+	assert.EqualValues(t, 112, first)
+	_, err = block.Reserve(5, 5, func(int64) (int64, error) {
+		return 0, errors.New("sequence table unavailable")
+	})
+	require.ErrorContains(t, err, "sequence table unavailable")
+	first, err = block.Reserve(3, 5, reserve)
+	require.NoError(t, err)
+	assert.EqualValues(t, 113, first)
+	assert.Equal(t, []int64{5, 7, 5}, reserved)
+
+	// The values left in the block are skipped when there are not enough.
+	first, err = block.Reserve(2, 5, reserve)
+	require.NoError(t, err)
+	assert.EqualValues(t, 117, first)
+	assert.Equal(t, []int64{5, 7, 5, 5}, reserved)
+}
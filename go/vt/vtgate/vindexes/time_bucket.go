@@ -0,0 +1,252 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"math"
+	"time"
+
+	"vitess.io/vitess/go/mysql/datetime"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+const (
+	timeBucketParamBucket = "bucket"
+
+	timeBucketDay   = "day"
+	timeBucketWeek  = "week"
+	timeBucketMonth = "month"
+)
+
+var (
+	_ SingleColumn    = (*TimeBucket)(nil)
+	_ Hashing         = (*TimeBucket)(nil)
+	_ OrderPreserving = (*TimeBucket)(nil)
+	_ OpenSequential  = (*TimeBucket)(nil)
+	_ ParamValidating = (*TimeBucket)(nil)
+
+	timeBucketParams = []string{
+		timeBucketParamBucket,
+	}
+)
+
+// TimeBucket is a functional, unique vindex that maps a DATE, DATETIME or
+// TIMESTAMP value to the number of the day, week or month that it falls in,
+// counted from 1970-01-01, as a 2 byte big endian number. Weeks start on
+// Mondays. Values before 1970, or past the last bucket that fits in 2 bytes,
+// are not mapped.
+//
+// As the buckets preserve the order of the values, TimeBucket is meant to be
+// used as the vindex of the time column of a multicol vindex, for example
+// with a tenant hash:
+//
+//	"tenant_day": {
+//	  "type": "multicol",
+//	  "params": {
+//	    "column_count": "2",
+//	    "column_vindex": "xxhash,time_bucket",
+//	    "column_bytes": "2,2",
+//	    "bucket": "day"
+//	  }
+//	}
+//
+// The rows of a tenant are then ordered by their time bucket within the key
+// range of the tenant, and ranges of values of the time column are routed to
+// the shards that hold the matching buckets.
+type TimeBucket struct {
+	name          string
+	bucket        string
+	unknownParams []string
+}
+
+func init() {
+	Register("time_bucket", newTimeBucket)
+}
+
+// newTimeBucket creates a TimeBucket vindex.
+func newTimeBucket(name string, params map[string]string) (Vindex, error) {
+	bucket := params[timeBucketParamBucket]
+	switch bucket {
+	case "":
+		bucket = timeBucketDay
+	case timeBucketDay, timeBucketWeek, timeBucketMonth:
+	default:
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeBucket: invalid bucket %q, it must be %q, %q or %q", bucket, timeBucketDay, timeBucketWeek, timeBucketMonth)
+	}
+	return &TimeBucket{
+		name:          name,
+		bucket:        bucket,
+		unknownParams: FindUnknownParams(params, timeBucketParams),
+	}, nil
+}
+
+// String returns the name of the vindex.
+func (vind *TimeBucket) String() string {
+	return vind.name
+}
+
+// Cost returns the cost of this vindex as 1.
+func (*TimeBucket) Cost() int {
+	return 1
+}
+
+// IsUnique returns true since the Vindex is unique.
+func (*TimeBucket) IsUnique() bool {
+	return true
+}
+
+// NeedsVCursor satisfies the Vindex interface.
+func (*TimeBucket) NeedsVCursor() bool {
+	return false
+}
+
+// Verify returns true if ids maps to ksids.
+func (vind *TimeBucket) Verify(ctx context.Context, vcursor VCursor, ids []sqltypes.Value, ksids [][]byte) ([]bool, error) {
+	out := make([]bool, 0, len(ids))
+	for i, id := range ids {
+		ksid, err := vind.Hash(id)
+		if err != nil {
+			out = append(out, false)
+			continue
+		}
+		out = append(out, bytes.Equal(ksid, ksids[i]))
+	}
+	return out, nil
+}
+
+// Map can map ids to key.ShardDestination objects.
+func (vind *TimeBucket) Map(ctx context.Context, vcursor VCursor, ids []sqltypes.Value) ([]key.ShardDestination, error) {
+	out := make([]key.ShardDestination, 0, len(ids))
+	for _, id := range ids {
+		ksid, err := vind.Hash(id)
+		if err != nil {
+			out = append(out, key.DestinationNone{})
+			continue
+		}
+		out = append(out, key.DestinationKeyspaceID(ksid))
+	}
+	return out, nil
+}
+
+// RangeMap implements Sequential. A NULL startId or endId means that the
+// range is unbounded on that side. Both bounds are inclusive.
+func (vind *TimeBucket) RangeMap(ctx context.Context, vcursor VCursor, startId sqltypes.Value, endId sqltypes.Value) ([]key.ShardDestination, error) {
+	// A bound that is not in any bucket leaves the range unbounded on that
+	// side, which may route to more shards than needed, but never to fewer.
+	var start, end []byte
+	if !startId.IsNull() {
+		start, _ = vind.Hash(startId)
+	}
+	if !endId.IsNull() {
+		end, _ = vind.Hash(endId)
+	}
+	return []key.ShardDestination{orderedKeyRange(nil, start, end)}, nil
+}
+
+// AllowsOpenRange implements OpenSequential.
+func (*TimeBucket) AllowsOpenRange() bool {
+	return true
+}
+
+// PreservesOrder implements OrderPreserving.
+func (*TimeBucket) PreservesOrder() bool {
+	return true
+}
+
+// Hash returns the number of the bucket that the id falls in.
+func (vind *TimeBucket) Hash(id sqltypes.Value) ([]byte, error) {
+	if id.IsNull() {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeBucket: NULL values are not in any bucket")
+	}
+	date, ok := parseTimeBucketDate(id)
+	if !ok {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeBucket: invalid date or datetime value %s", id.String())
+	}
+
+	var num int64
+	switch vind.bucket {
+	case timeBucketMonth:
+		num = int64(date.Year()-1970)*12 + int64(date.Month()-1)
+	default:
+		days := time.Date(date.Year(), time.Month(date.Month()), date.Day(), 0, 0, 0, 0, time.UTC).Unix() / (24 * 60 * 60)
+		num = days
+		if vind.bucket == timeBucketWeek {
+			// 1970-01-01 was a Thursday, so weeks are counted from Monday 1969-12-29.
+			num = (days + 3) / 7
+		}
+	}
+	if num < 0 || num > math.MaxUint16 {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeBucket: value %s is out of the range of supported dates", id.String())
+	}
+	return binary.BigEndian.AppendUint16(nil, uint16(num)), nil
+}
+
+// UnknownParams implements the ParamValidating interface.
+func (vind *TimeBucket) UnknownParams() []string {
+	return vind.unknownParams
+}
+
+func parseTimeBucketDate(id sqltypes.Value) (datetime.Date, bool) {
+	if id.IsIntegral() {
+		num, err := id.ToInt64()
+		if err != nil {
+			return datetime.Date{}, false
+		}
+		// Numbers are YYYYMMDD dates or YYYYMMDDhhmmss datetimes, as in MySQL.
+		if num <= 99991231 {
+			date, ok := datetime.ParseDateInt64(num)
+			return date, ok && !date.IsZero()
+		}
+		dt, ok := datetime.ParseDateTimeInt64(num)
+		return dt.Date, ok && !dt.Date.IsZero()
+	}
+	s := id.ToString()
+	if dt, _, ok := datetime.ParseDateTime(s, -1); ok {
+		return dt.Date, !dt.Date.IsZero()
+	}
+	date, ok := datetime.ParseDate(s)
+	return date, ok && !date.IsZero()
+}
+
+// orderedKeyRange returns the key range of the keyspace ids that start with
+// prefix, followed by bytes in [start, end]. A nil start or end means that the
+// range is unbounded on that side.
+func orderedKeyRange(prefix, start, end []byte) key.ShardDestination {
+	if start != nil && end != nil && bytes.Compare(start, end) > 0 {
+		return key.DestinationNone{}
+	}
+	kr := &topodatapb.KeyRange{
+		Start: append(append([]byte{}, prefix...), start...),
+	}
+	if end != nil {
+		kr.End = addOne(append(append([]byte{}, prefix...), end...))
+	} else if len(prefix) > 0 {
+		kr.End = addOne(append([]byte{}, prefix...))
+	}
+	if len(kr.Start) == 0 && len(kr.End) == 0 {
+		return key.DestinationAllShards{}
+	}
+	return key.DestinationKeyRange{KeyRange: kr}
+}
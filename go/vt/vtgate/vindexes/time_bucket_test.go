@@ -0,0 +1,211 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+func timeBucketCreateVindexTestCase(
+	testName string,
+	vindexParams map[string]string,
+	expectErr error,
+	expectUnknownParams []string,
+) createVindexTestCase {
+	return createVindexTestCase{
+		testName: testName,
+
+		vindexType:   "time_bucket",
+		vindexName:   "time_bucket",
+		vindexParams: vindexParams,
+
+		expectCost:          1,
+		expectErr:           expectErr,
+		expectIsUnique:      true,
+		expectNeedsVCursor:  false,
+		expectString:        "time_bucket",
+		expectUnknownParams: expectUnknownParams,
+	}
+}
+
+func TestTimeBucketCreateVindex(t *testing.T) {
+	cases := []createVindexTestCase{
+		timeBucketCreateVindexTestCase(
+			"no params",
+			nil,
+			nil,
+			nil,
+		),
+		timeBucketCreateVindexTestCase(
+			"week bucket",
+			map[string]string{"bucket": "week"},
+			nil,
+			nil,
+		),
+		timeBucketCreateVindexTestCase(
+			"invalid bucket",
+			map[string]string{"bucket": "hour"},
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeBucket: invalid bucket \"hour\", it must be \"day\", \"week\" or \"month\""),
+			nil,
+		),
+		timeBucketCreateVindexTestCase(
+			"unknown params",
+			map[string]string{"bucket": "month", "hello": "world"},
+			nil,
+			[]string{"hello"},
+		),
+	}
+
+	testCreateVindexes(t, cases)
+}
+
+func TestTimeBucketHash(t *testing.T) {
+	values := []sqltypes.Value{
+		sqltypes.MakeTrusted(sqltypes.Datetime, []byte("2024-01-01 10:20:30")),
+		sqltypes.MakeTrusted(sqltypes.Date, []byte("2024-01-07")),
+		sqltypes.NewVarChar("2024-01-08 00:00:00"),
+		sqltypes.NewInt64(20240229),
+		sqltypes.NewVarChar("1970-01-01"),
+	}
+	testCases := []struct {
+		bucket string
+		want   [][]byte
+	}{{
+		bucket: "day",
+		want:   [][]byte{{0x4d, 0x0b}, {0x4d, 0x11}, {0x4d, 0x12}, {0x4d, 0x46}, {0x00, 0x00}},
+	}, {
+		bucket: "week",
+		want:   [][]byte{{0x0b, 0x02}, {0x0b, 0x02}, {0x0b, 0x03}, {0x0b, 0x0a}, {0x00, 0x00}},
+	}, {
+		bucket: "month",
+		want:   [][]byte{{0x02, 0x88}, {0x02, 0x88}, {0x02, 0x88}, {0x02, 0x89}, {0x00, 0x00}},
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.bucket, func(t *testing.T) {
+			vindex, err := CreateVindex("time_bucket", "time_bucket", map[string]string{"bucket": tc.bucket})
+			require.NoError(t, err)
+			tb := vindex.(*TimeBucket)
+			for i, v := range values {
+				got, err := tb.Hash(v)
+				require.NoError(t, err, v.String())
+				assert.Equal(t, tc.want[i], got, v.String())
+			}
+		})
+	}
+
+	vindex, err := CreateVindex("time_bucket", "time_bucket", nil)
+	require.NoError(t, err)
+	tb := vindex.(*TimeBucket)
+	for _, v := range []sqltypes.Value{
+		sqltypes.NULL,
+		sqltypes.NewVarChar("yesterday"),
+		sqltypes.NewVarChar("0000-00-00 00:00:00"),
+		sqltypes.NewVarChar("1969-12-31"),
+		sqltypes.NewVarChar("2149-06-07"),
+	} {
+		_, err := tb.Hash(v)
+		assert.Error(t, err, v.String())
+	}
+}
+
+func TestTimeBucketMap(t *testing.T) {
+	vindex, err := CreateVindex("time_bucket", "time_bucket", nil)
+	require.NoError(t, err)
+	tb := vindex.(*TimeBucket)
+
+	got, err := tb.Map(context.Background(), nil, []sqltypes.Value{
+		sqltypes.NewVarChar("2024-01-01 10:20:30"),
+		sqltypes.NewVarChar("yesterday"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []key.ShardDestination{
+		key.DestinationKeyspaceID([]byte{0x4d, 0x0b}),
+		key.DestinationNone{},
+	}, got)
+
+	verified, err := tb.Verify(context.Background(), nil,
+		[]sqltypes.Value{sqltypes.NewVarChar("2024-01-01 10:20:30"), sqltypes.NewVarChar("2024-01-01 23:59:59")},
+		[][]byte{{0x4d, 0x0b}, {0x4d, 0x0c}})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, false}, verified)
+}
+
+func TestTimeBucketRangeMap(t *testing.T) {
+	vindex, err := CreateVindex("time_bucket", "time_bucket", map[string]string{"bucket": "month"})
+	require.NoError(t, err)
+	tb := vindex.(*TimeBucket)
+
+	testCases := []struct {
+		name       string
+		start, end sqltypes.Value
+		want       string
+	}{
+		{
+			name:  "within one bucket",
+			start: sqltypes.NewVarChar("2024-01-01"),
+			end:   sqltypes.NewVarChar("2024-01-31 23:59:59"),
+			want:  "DestinationKeyRange(0288-0289)",
+		},
+		{
+			name:  "across buckets",
+			start: sqltypes.NewVarChar("2024-01-15"),
+			end:   sqltypes.NewVarChar("2024-03-01"),
+			want:  "DestinationKeyRange(0288-028b)",
+		},
+		{
+			name:  "unbounded start",
+			start: sqltypes.NULL,
+			end:   sqltypes.NewVarChar("2024-01-15"),
+			want:  "DestinationKeyRange(-0289)",
+		},
+		{
+			name:  "unbounded end",
+			start: sqltypes.NewVarChar("2024-01-15"),
+			end:   sqltypes.NULL,
+			want:  "DestinationKeyRange(0288-)",
+		},
+		{
+			name:  "bound before the first bucket",
+			start: sqltypes.NewVarChar("1960-01-01"),
+			end:   sqltypes.NewVarChar("2024-01-15"),
+			want:  "DestinationKeyRange(-0289)",
+		},
+		{
+			name:  "empty range",
+			start: sqltypes.NewVarChar("2024-03-01"),
+			end:   sqltypes.NewVarChar("2024-01-01"),
+			want:  "DestinationNone()",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tb.RangeMap(context.Background(), nil, tc.start, tc.end)
+			require.NoError(t, err)
+			require.Len(t, got, 1)
+			assert.Equal(t, tc.want, got[0].String())
+		})
+	}
+}
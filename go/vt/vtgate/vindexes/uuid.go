@@ -0,0 +1,188 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+const (
+	uuidParamSwapFlag = "swap_flag"
+)
+
+var (
+	_ SingleColumn    = (*UUID)(nil)
+	_ Hashing         = (*UUID)(nil)
+	_ ParamValidating = (*UUID)(nil)
+
+	uuidParams = []string{
+		uuidParamSwapFlag,
+	}
+)
+
+// UUID is a functional, unique vindex for UUID columns. A UUID is accepted
+// either as a string, such as in a CHAR(36) column, or as 16 bytes, such as
+// in a BINARY(16) column, so the same UUID maps to the same keyspace id in
+// both forms. When the swap_flag param is true, the 16 byte form is expected
+// to be the output of UUID_TO_BIN(uuid, 1), which moves the time fields of
+// the UUID to the front so that the values are ordered by time.
+//
+// The keyspace id is the xxhash of the random component of the UUID: the
+// bits after the timestamp for version 7 UUIDs, and the low bits of the
+// timestamp, the clock sequence and the node for version 1 UUIDs, whose
+// timestamp changes the slowest in its high bits. The time ordering of these
+// UUIDs therefore does not skew the distribution of the rows across the
+// shards. The other versions hash the whole UUID.
+//
+// As UUID implements Hashing, it can also be the vindex of a column of a
+// multicol vindex, for the composite natural keys that include a UUID.
+type UUID struct {
+	name          string
+	swapFlag      bool
+	unknownParams []string
+}
+
+func init() {
+	Register("uuid", newUUID)
+}
+
+// newUUID creates a UUID vindex.
+func newUUID(name string, params map[string]string) (Vindex, error) {
+	var swapFlag bool
+	if value, ok := params[uuidParamSwapFlag]; ok {
+		var err error
+		if swapFlag, err = strconv.ParseBool(value); err != nil {
+			return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "UUID: invalid swap_flag %q, it must be true or false", value)
+		}
+	}
+	return &UUID{
+		name:          name,
+		swapFlag:      swapFlag,
+		unknownParams: FindUnknownParams(params, uuidParams),
+	}, nil
+}
+
+// String returns the name of the vindex.
+func (vind *UUID) String() string {
+	return vind.name
+}
+
+// Cost returns the cost of this vindex as 1.
+func (*UUID) Cost() int {
+	return 1
+}
+
+// IsUnique returns true since the Vindex is unique.
+func (*UUID) IsUnique() bool {
+	return true
+}
+
+// NeedsVCursor satisfies the Vindex interface.
+func (*UUID) NeedsVCursor() bool {
+	return false
+}
+
+// Verify returns true if ids maps to ksids.
+func (vind *UUID) Verify(ctx context.Context, vcursor VCursor, ids []sqltypes.Value, ksids [][]byte) ([]bool, error) {
+	out := make([]bool, 0, len(ids))
+	for i, id := range ids {
+		ksid, err := vind.Hash(id)
+		if err != nil {
+			out = append(out, false)
+			continue
+		}
+		out = append(out, bytes.Equal(ksid, ksids[i]))
+	}
+	return out, nil
+}
+
+// Map can map ids to key.ShardDestination objects.
+func (vind *UUID) Map(ctx context.Context, vcursor VCursor, ids []sqltypes.Value) ([]key.ShardDestination, error) {
+	out := make([]key.ShardDestination, 0, len(ids))
+	for _, id := range ids {
+		ksid, err := vind.Hash(id)
+		if err != nil {
+			// A value that is not a UUID cannot be in the table.
+			out = append(out, key.DestinationNone{})
+			continue
+		}
+		out = append(out, key.DestinationKeyspaceID(ksid))
+	}
+	return out, nil
+}
+
+// Hash returns the keyspace id of a UUID.
+func (vind *UUID) Hash(id sqltypes.Value) ([]byte, error) {
+	u, err := vind.parse(id)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Version() {
+	case 7:
+		// unix_ts_ms (48 bits) | ver | rand_a | var | rand_b
+		return vXXHash(u[6:]), nil
+	case 1:
+		// time_low (32 bits) | time_mid | ver | time_hi | var | clock_seq | node
+		var random [12]byte
+		copy(random[:4], u[:4])
+		copy(random[4:], u[8:])
+		return vXXHash(random[:]), nil
+	default:
+		return vXXHash(u[:]), nil
+	}
+}
+
+// UnknownParams implements the ParamValidating interface.
+func (vind *UUID) UnknownParams() []string {
+	return vind.unknownParams
+}
+
+// parse returns the UUID of a value, in the byte order of RFC 9562.
+func (vind *UUID) parse(id sqltypes.Value) (uuid.UUID, error) {
+	if id.IsNull() {
+		return uuid.UUID{}, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "UUID: NULL is not a valid UUID")
+	}
+	raw := id.Raw()
+	if len(raw) == 16 {
+		var u uuid.UUID
+		if !vind.swapFlag {
+			copy(u[:], raw)
+			return u, nil
+		}
+		// UUID_TO_BIN(uuid, 1) swaps time_low and time_hi_and_version:
+		// time_hi_and_version | time_mid | time_low | clock_seq | node
+		copy(u[0:4], raw[4:8])
+		copy(u[4:6], raw[2:4])
+		copy(u[6:8], raw[0:2])
+		copy(u[8:], raw[8:])
+		return u, nil
+	}
+	u, err := uuid.ParseBytes(raw)
+	if err != nil {
+		return uuid.UUID{}, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "UUID: invalid UUID %s", id.String())
+	}
+	return u, nil
+}
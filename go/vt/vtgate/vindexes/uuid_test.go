@@ -0,0 +1,189 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+func uuidCreateVindexTestCase(
+	testName string,
+	vindexParams map[string]string,
+	expectErr error,
+	expectUnknownParams []string,
+) createVindexTestCase {
+	return createVindexTestCase{
+		testName: testName,
+
+		vindexType:   "uuid",
+		vindexName:   "uuid",
+		vindexParams: vindexParams,
+
+		expectCost:          1,
+		expectErr:           expectErr,
+		expectIsUnique:      true,
+		expectNeedsVCursor:  false,
+		expectString:        "uuid",
+		expectUnknownParams: expectUnknownParams,
+	}
+}
+
+func TestUUIDCreateVindex(t *testing.T) {
+	cases := []createVindexTestCase{
+		uuidCreateVindexTestCase(
+			"no params",
+			nil,
+			nil,
+			nil,
+		),
+		uuidCreateVindexTestCase(
+			"swap flag",
+			map[string]string{"swap_flag": "true"},
+			nil,
+			nil,
+		),
+		uuidCreateVindexTestCase(
+			"invalid swap flag",
+			map[string]string{"swap_flag": "maybe"},
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "UUID: invalid swap_flag \"maybe\", it must be true or false"),
+			nil,
+		),
+		uuidCreateVindexTestCase(
+			"unknown params",
+			map[string]string{"swap_flag": "false", "hello": "world"},
+			nil,
+			[]string{"hello"},
+		),
+	}
+
+	testCreateVindexes(t, cases)
+}
+
+func uuidBytes(t *testing.T, s string) sqltypes.Value {
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return sqltypes.MakeTrusted(sqltypes.VarBinary, b)
+}
+
+func TestUUIDHash(t *testing.T) {
+	vindex, err := CreateVindex("uuid", "uuid", nil)
+	require.NoError(t, err)
+	plain := vindex.(*UUID)
+	vindex, err = CreateVindex("uuid", "uuid", map[string]string{"swap_flag": "true"})
+	require.NoError(t, err)
+	swapped := vindex.(*UUID)
+
+	testCases := []struct {
+		name string
+		// uuid is the string form of the UUID.
+		uuid string
+		// bin and swapped are UUID_TO_BIN(uuid) and UUID_TO_BIN(uuid, 1).
+		bin, swapped string
+		want         string
+	}{{
+		name:    "version 1",
+		uuid:    "6ccd780c-baba-1026-9564-5b8c656024db",
+		bin:     "6ccd780cbaba102695645b8c656024db",
+		swapped: "1026baba6ccd780c95645b8c656024db",
+		want:    "4c4b0426e7b2705d",
+	}, {
+		name:    "version 4",
+		uuid:    "9f6a2d8e-3c1b-4e5f-8a7b-2c4d6e8f0a1b",
+		bin:     "9f6a2d8e3c1b4e5f8a7b2c4d6e8f0a1b",
+		swapped: "4e5f3c1b9f6a2d8e8a7b2c4d6e8f0a1b",
+		want:    "5cad26c388e6a1d8",
+	}, {
+		name:    "version 7",
+		uuid:    "01936f5e-8a3c-7b2d-9e4f-1a2b3c4d5e6f",
+		bin:     "01936f5e8a3c7b2d9e4f1a2b3c4d5e6f",
+		swapped: "7b2d8a3c01936f5e9e4f1a2b3c4d5e6f",
+		want:    "9f6bbd4f41c54c34",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, v := range []sqltypes.Value{
+				sqltypes.NewVarChar(tc.uuid),
+				sqltypes.NewVarChar("{" + tc.uuid + "}"),
+				sqltypes.NewVarChar(tc.bin),
+				uuidBytes(t, tc.bin),
+			} {
+				got, err := plain.Hash(v)
+				require.NoError(t, err, v.String())
+				assert.Equal(t, tc.want, hex.EncodeToString(got), v.String())
+			}
+			for _, v := range []sqltypes.Value{
+				sqltypes.NewVarChar(tc.uuid),
+				uuidBytes(t, tc.swapped),
+			} {
+				got, err := swapped.Hash(v)
+				require.NoError(t, err, v.String())
+				assert.Equal(t, tc.want, hex.EncodeToString(got), v.String())
+			}
+		})
+	}
+
+	// Only the random component of version 7 UUIDs is hashed.
+	a, err := plain.Hash(sqltypes.NewVarChar("01936f5e-8a3c-7b2d-9e4f-1a2b3c4d5e6f"))
+	require.NoError(t, err)
+	b, err := plain.Hash(sqltypes.NewVarChar("01936f5f-0000-7b2d-9e4f-1a2b3c4d5e6f"))
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+
+	for _, v := range []sqltypes.Value{
+		sqltypes.NULL,
+		sqltypes.NewVarChar("not a uuid"),
+		sqltypes.NewInt64(1),
+		uuidBytes(t, "01936f5e8a3c7b2d9e4f1a2b3c4d5e"),
+	} {
+		_, err := plain.Hash(v)
+		assert.Error(t, err, v.String())
+	}
+}
+
+func TestUUIDMap(t *testing.T) {
+	vindex, err := CreateVindex("uuid", "uuid", nil)
+	require.NoError(t, err)
+	u := vindex.(*UUID)
+
+	ksid, err := u.Hash(sqltypes.NewVarChar("01936f5e-8a3c-7b2d-9e4f-1a2b3c4d5e6f"))
+	require.NoError(t, err)
+	got, err := u.Map(context.Background(), nil, []sqltypes.Value{
+		sqltypes.NewVarChar("01936f5e-8a3c-7b2d-9e4f-1a2b3c4d5e6f"),
+		sqltypes.NewVarChar("not a uuid"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []key.ShardDestination{
+		key.DestinationKeyspaceID(ksid),
+		key.DestinationNone{},
+	}, got)
+
+	verified, err := u.Verify(context.Background(), nil,
+		[]sqltypes.Value{uuidBytes(t, "01936f5e8a3c7b2d9e4f1a2b3c4d5e6f"), sqltypes.NewVarChar("9f6a2d8e-3c1b-4e5f-8a7b-2c4d6e8f0a1b")},
+		[][]byte{ksid, ksid})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, false}, verified)
+}
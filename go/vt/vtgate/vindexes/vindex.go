@@ -116,10 +116,31 @@ type (
 		PartialVindex() bool
 	}
 
+	// A SequentialMultiColumn vindex is a MultiColumn vindex that can map a range of
+	// values of some of its columns to a keyspace range. It's being used to reduce
+	// the fan out for 'BETWEEN', '<', '<=', '>' and '>=' expressions on those columns.
+	SequentialMultiColumn interface {
+		MultiColumn
+		// AllowsRangeOnColumn returns true if ranges of values of the column at idx can be mapped.
+		AllowsRangeOnColumn(idx int) bool
+		// RangeMap maps the values of the columns that precede the range column, and the range
+		// of values of that column, to key.ShardDestination objects. A NULL startId or endId
+		// means that the range is unbounded on that side. Both bounds are inclusive.
+		RangeMap(ctx context.Context, vcursor VCursor, prefixValues []sqltypes.Value, startId sqltypes.Value, endId sqltypes.Value) ([]key.ShardDestination, error)
+	}
+
 	// Hashing defined the interface for the vindexes that export the Hash function to be used by multi-column vindex.
 	Hashing interface {
 		Hash(id sqltypes.Value) ([]byte, error)
 	}
+
+	// An OrderPreserving vindex is a Hashing vindex whose hashes sort in the same
+	// order as the values that they are computed from. This lets a multi-column
+	// vindex map ranges of values of the column that it is used for.
+	OrderPreserving interface {
+		Hashing
+		PreservesOrder() bool
+	}
 	// A Reversible vindex is one that can perform a
 	// reverse lookup from a keyspace id to an id. This
 	// is optional. If present, VTGate can use it to
@@ -137,6 +158,14 @@ type (
 		RangeMap(ctx context.Context, vcursor VCursor, startId sqltypes.Value, endId sqltypes.Value) ([]key.ShardDestination, error)
 	}
 
+	// An OpenSequential vindex is a Sequential vindex whose RangeMap also accepts
+	// a NULL startId or endId, for a range that is unbounded on that side. It's
+	// being used to reduce the fan out for '<', '<=', '>' and '>=' expressions.
+	OpenSequential interface {
+		Sequential
+		AllowsOpenRange() bool
+	}
+
 	// A Prefixable vindex is one that maps the prefix of a id to a keyspace range
 	// instead of a single keyspace id. It's being used to reduced the fan out for
 	// 'LIKE' expressions.
@@ -336,7 +336,15 @@ func (ks *KeyspaceSchema) MarshalJSON() ([]byte, error) {
 // AutoIncrement contains the auto-inc information for a table.
 type AutoIncrement struct {
 	Column   sqlparser.IdentifierCI `json:"column"`
-	Sequence *BaseTable             `json:"sequence"`
+	Sequence *BaseTable             `json:"sequence,omitempty"`
+	// Generator, if set, generates the values inside vtgate
+	// instead of the Sequence table.
+	Generator SequenceGenerator `json:"generator,omitempty"`
+	// Batch, if set, is the number of values that vtgate reserves from
+	// the Sequence table at once, and keeps in the Block until they are
+	// handed out.
+	Batch int64          `json:"batch,omitempty"`
+	Block *SequenceBlock `json:"-"`
 }
 
 type Source struct {
@@ -928,6 +936,20 @@ func resolveAutoIncrement(source *vschemapb.SrvVSchema, vschema *VSchema, parser
 			if t == nil || table.AutoIncrement == nil {
 				continue
 			}
+			if table.AutoIncrement.Generator != "" {
+				gen, err := buildSequenceGenerator(table.AutoIncrement)
+				if err != nil {
+					delete(ksvschema.Tables, tname)
+					delete(vschema.globalTables, tname)
+					ksvschema.Error = err
+					continue
+				}
+				t.AutoIncrement = &AutoIncrement{
+					Column:    sqlparser.NewIdentifierCI(table.AutoIncrement.Column),
+					Generator: gen,
+				}
+				continue
+			}
 			seqks, seqtab, err := parser.ParseTable(table.AutoIncrement.Sequence)
 			var seq *BaseTable
 			if err == nil {
@@ -950,14 +972,42 @@ func resolveAutoIncrement(source *vschemapb.SrvVSchema, vschema *VSchema, parser
 
 				continue
 			}
+			batch, err := parseSequenceBatch(table.AutoIncrement)
+			if err != nil {
+				delete(ksvschema.Tables, tname)
+				delete(vschema.globalTables, tname)
+				ksvschema.Error = err
+				continue
+			}
 			t.AutoIncrement = &AutoIncrement{
 				Column:   sqlparser.NewIdentifierCI(table.AutoIncrement.Column),
 				Sequence: seq,
 			}
+			if batch > 1 {
+				t.AutoIncrement.Batch = batch
+				t.AutoIncrement.Block = getSequenceBlock(seq)
+			}
 		}
 	}
 }
 
+func buildSequenceGenerator(autoInc *vschemapb.AutoIncrement) (SequenceGenerator, error) {
+	if autoInc.Sequence != "" {
+		return nil, vterrors.Errorf(
+			vtrpcpb.Code_INVALID_ARGUMENT,
+			"auto_increment of column %s cannot have both the sequence %s and the generator %s",
+			autoInc.Column,
+			autoInc.Sequence,
+			autoInc.Generator,
+		)
+	}
+	gen, err := CreateSequenceGenerator(autoInc.Generator, autoInc.Params)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot create the sequence generator of column %s", autoInc.Column)
+	}
+	return gen, nil
+}
+
 // expects table name of the form <keyspace>.<tablename>
 func escapeQualifiedTable(qualifiedTableName string) (string, error) {
 	keyspace, tableName, err := extractTableParts(qualifiedTableName, false /* allowUnqualified */)
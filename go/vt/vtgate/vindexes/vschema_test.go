@@ -25,6 +25,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -2482,6 +2483,100 @@ func TestBadSequenceName(t *testing.T) {
 	}
 }
 
+func TestSequenceGenerator(t *testing.T) {
+	table := func(autoInc *vschemapb.AutoIncrement) *vschemapb.Table {
+		return &vschemapb.Table{
+			ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "c1", Name: "stfu1"}},
+			AutoIncrement:  autoInc,
+		}
+	}
+	srvVSchema := vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"unsharded": {
+				Tables: map[string]*vschemapb.Table{
+					"seq": {Type: "sequence"},
+				},
+			},
+			"sharded": {
+				Sharded:  true,
+				Vindexes: map[string]*vschemapb.Vindex{"stfu1": {Type: "stfu"}},
+				Tables: map[string]*vschemapb.Table{
+					"t1": table(&vschemapb.AutoIncrement{Column: "id", Generator: "snowflake", Params: map[string]string{"epoch": "1700000000000"}}),
+					"t2": table(&vschemapb.AutoIncrement{Column: "uid", Generator: "uuidv7"}),
+					"t3": table(&vschemapb.AutoIncrement{Column: "id", Sequence: "seq"}),
+					"t4": table(&vschemapb.AutoIncrement{Column: "id", Sequence: "seq", Params: map[string]string{"batch": "100"}}),
+					"t5": table(&vschemapb.AutoIncrement{Column: "id", Sequence: "seq", Params: map[string]string{"batch": "10"}}),
+				},
+			},
+		},
+	}
+	got := BuildVSchema(&srvVSchema, sqlparser.NewTestParser())
+	ks := got.Keyspaces["sharded"]
+	require.NoError(t, ks.Error)
+	assert.Equal(t, &AutoIncrement{
+		Column:    sqlparser.NewIdentifierCI("id"),
+		Generator: &Snowflake{Epoch: time.UnixMilli(1700000000000).UTC()},
+	}, ks.Tables["t1"].AutoIncrement)
+	assert.Equal(t, &AutoIncrement{
+		Column:    sqlparser.NewIdentifierCI("uid"),
+		Generator: &UUIDv7{Format: "string"},
+	}, ks.Tables["t2"].AutoIncrement)
+	assert.Equal(t, "seq", ks.Tables["t3"].AutoIncrement.Sequence.Name.String())
+	assert.Zero(t, ks.Tables["t3"].AutoIncrement.Batch)
+	assert.Nil(t, ks.Tables["t3"].AutoIncrement.Block)
+	// The tables that use the same sequence share its block, also after the
+	// vschema is rebuilt.
+	assert.EqualValues(t, 100, ks.Tables["t4"].AutoIncrement.Batch)
+	assert.EqualValues(t, 10, ks.Tables["t5"].AutoIncrement.Batch)
+	require.NotNil(t, ks.Tables["t4"].AutoIncrement.Block)
+	assert.Same(t, ks.Tables["t4"].AutoIncrement.Block, ks.Tables["t5"].AutoIncrement.Block)
+	rebuilt := BuildVSchema(&srvVSchema, sqlparser.NewTestParser())
+	assert.Same(t, ks.Tables["t4"].AutoIncrement.Block, rebuilt.Keyspaces["sharded"].Tables["t4"].AutoIncrement.Block)
+
+	testCases := []struct {
+		name    string
+		autoInc *vschemapb.AutoIncrement
+		want    string
+	}{
+		{
+			name:    "unknown generator",
+			autoInc: &vschemapb.AutoIncrement{Column: "id", Generator: "nope"},
+			want:    `cannot create the sequence generator of column id: sequence generator "nope" not found`,
+		},
+		{
+			name:    "sequence and generator",
+			autoInc: &vschemapb.AutoIncrement{Column: "id", Sequence: "seq", Generator: "snowflake"},
+			want:    "auto_increment of column id cannot have both the sequence seq and the generator snowflake",
+		},
+		{
+			name:    "unknown param",
+			autoInc: &vschemapb.AutoIncrement{Column: "id", Generator: "uuidv7", Params: map[string]string{"epoch": "0"}},
+			want:    `unknown param "epoch" for sequence generator uuidv7`,
+		},
+		{
+			name:    "unknown sequence param",
+			autoInc: &vschemapb.AutoIncrement{Column: "id", Sequence: "seq", Params: map[string]string{"cache": "10"}},
+			want:    `unknown param "cache" for sequence seq`,
+		},
+		{
+			name:    "invalid batch",
+			autoInc: &vschemapb.AutoIncrement{Column: "id", Sequence: "seq", Params: map[string]string{"batch": "0"}},
+			want:    `invalid batch "0" for sequence seq, it must be a positive number`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bad := srvVSchema.CloneVT()
+			bad.Keyspaces["sharded"].Tables["t1"] = table(tc.autoInc)
+			got := BuildVSchema(bad, sqlparser.NewTestParser())
+			ks := got.Keyspaces["sharded"]
+			require.ErrorContains(t, ks.Error, tc.want)
+			assert.Nil(t, ks.Tables["t1"])
+			assert.NotNil(t, ks.Tables["t2"])
+		})
+	}
+}
+
 func TestBadShardedSequence(t *testing.T) {
 	bad := vschemapb.SrvVSchema{
 		Keyspaces: map[string]*vschemapb.Keyspace{
@@ -0,0 +1,389 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package vschemaadvisor suggests the VSchema of a sharded keyspace from
+// its schema and a captured query workload, and reports how the queries of
+// the workload are routed by the planner with a VSchema.
+package vschemaadvisor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+const (
+	// DefaultLookupThreshold is the default minimum fraction of the queries
+	// of a table that must filter on a column for a lookup vindex to be
+	// suggested on that column.
+	DefaultLookupThreshold = 0.1
+	// DefaultReferenceWriteRatio is the default maximum fraction of the
+	// queries of a table that can write to it for the table to be suggested
+	// as a reference table.
+	DefaultReferenceWriteRatio = 0.01
+
+	// TableTypeSharded is the type of the tables that are sharded by a
+	// primary vindex.
+	TableTypeSharded = "sharded"
+	// TableTypeReference is the type of the tables that are copied to
+	// all the shards.
+	TableTypeReference = vindexes.TypeReference
+	// TableTypeLookup is the type of the tables that back the suggested
+	// lookup vindexes.
+	TableTypeLookup = "lookup"
+
+	lookupKeyspaceIDColumn = "keyspace_id"
+)
+
+// Options are the options of the advisor.
+type Options struct {
+	// Keyspace is the name of the keyspace.
+	Keyspace string
+	// LookupThreshold is the minimum fraction of the queries of a table that
+	// must filter on a column, other than the column of its primary vindex,
+	// for a lookup vindex to be suggested on that column.
+	LookupThreshold float64
+	// ReferenceWriteRatio is the maximum fraction of the queries of a table
+	// that can write to it for the table, if it is joined to other tables,
+	// to be suggested as a reference table.
+	ReferenceWriteRatio float64
+}
+
+// TableAdvice is the advice for a table of the keyspace.
+type TableAdvice struct {
+	Table   string   `json:"table"`
+	Type    string   `json:"type"`
+	Column  string   `json:"column,omitempty"`
+	Vindex  string   `json:"vindex,omitempty"`
+	Lookups []string `json:"lookups,omitempty"`
+	Reason  string   `json:"reason"`
+}
+
+// Advice is the VSchema suggested by the advisor, and how the queries of
+// the workload are routed with it.
+type Advice struct {
+	VSchema *vschemapb.Keyspace `json:"vschema"`
+	Tables  []*TableAdvice      `json:"tables"`
+	Routing *Report             `json:"routing"`
+}
+
+// table is a table of the schema.
+type table struct {
+	name       string
+	columns    []*sqlparser.ColumnDefinition
+	primaryKey []string
+	uniqueKeys [][]string
+}
+
+func (t *table) column(name string) *sqlparser.ColumnDefinition {
+	for _, col := range t.columns {
+		if col.Name.EqualString(name) {
+			return col
+		}
+	}
+	return nil
+}
+
+// isUnique returns true if the column alone is a unique key of the table.
+func (t *table) isUnique(column string) bool {
+	if len(t.primaryKey) == 1 && strings.EqualFold(t.primaryKey[0], column) {
+		return true
+	}
+	for _, key := range t.uniqueKeys {
+		if len(key) == 1 && strings.EqualFold(key[0], column) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSchema parses the CREATE TABLE statements of a schema.
+func parseSchema(parser *sqlparser.Parser, schema string) (map[string]*table, []string, error) {
+	pieces, err := parser.SplitStatementToPieces(schema)
+	if err != nil {
+		return nil, nil, err
+	}
+	tables := make(map[string]*table)
+	var names []string
+	for _, piece := range pieces {
+		if strings.TrimSpace(piece) == "" {
+			continue
+		}
+		stmt, err := parser.Parse(piece)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid schema statement %q: %w", piece, err)
+		}
+		create, ok := stmt.(*sqlparser.CreateTable)
+		if !ok || create.TableSpec == nil {
+			continue
+		}
+		t := &table{name: create.Table.Name.String(), columns: create.TableSpec.Columns}
+		for _, col := range create.TableSpec.Columns {
+			if col.Type.Options == nil || col.Type.Options.KeyOpt == sqlparser.ColKeyNone {
+				continue
+			}
+			switch col.Type.Options.KeyOpt {
+			case sqlparser.ColKeyPrimary:
+				t.primaryKey = []string{col.Name.String()}
+			case sqlparser.ColKeyUnique, sqlparser.ColKeyUniqueKey:
+				t.uniqueKeys = append(t.uniqueKeys, []string{col.Name.String()})
+			}
+		}
+		for _, idx := range create.TableSpec.Indexes {
+			var cols []string
+			for _, col := range idx.Columns {
+				cols = append(cols, col.Column.String())
+			}
+			switch idx.Info.Type {
+			case sqlparser.IndexTypePrimary:
+				t.primaryKey = cols
+			case sqlparser.IndexTypeUnique:
+				t.uniqueKeys = append(t.uniqueKeys, cols)
+			}
+		}
+		tables[t.name] = t
+		names = append(names, t.name)
+	}
+	if len(tables) == 0 {
+		return nil, nil, fmt.Errorf("the schema does not have any CREATE TABLE statement")
+	}
+	sort.Strings(names)
+	return tables, names, nil
+}
+
+// Advise suggests the VSchema of a sharded keyspace with the tables of the
+// schema, given as CREATE TABLE statements, from the queries of a workload:
+//
+//   - Tables that are joined to other tables and are rarely written to are
+//     suggested as reference tables.
+//   - The primary vindex of the other tables is on the column that the
+//     queries most often compare to values or join to the other sharded
+//     tables, so that these queries go to a single shard and the joined
+//     rows are on the same shard.
+//   - A lookup vindex is suggested on the other columns that the queries
+//     of a table often compare to values.
+//
+// The queries of the workload are then planned with the suggested VSchema.
+func Advise(env *vtenv.Environment, schema string, queries []*Query, opts Options) (*Advice, error) {
+	if opts.Keyspace == "" {
+		return nil, fmt.Errorf("the keyspace must be specified")
+	}
+	if opts.LookupThreshold <= 0 {
+		opts.LookupThreshold = DefaultLookupThreshold
+	}
+	if opts.ReferenceWriteRatio <= 0 {
+		opts.ReferenceWriteRatio = DefaultReferenceWriteRatio
+	}
+	tables, names, err := parseSchema(env.Parser(), schema)
+	if err != nil {
+		return nil, err
+	}
+	wl := analyzeWorkload(env.Parser(), tables, queries)
+
+	advice := &Advice{
+		VSchema: &vschemapb.Keyspace{
+			Sharded:  true,
+			Vindexes: make(map[string]*vschemapb.Vindex),
+			Tables:   make(map[string]*vschemapb.Table),
+		},
+	}
+	reference := make(map[string]bool)
+	for _, name := range names {
+		usage := wl.tables[name]
+		if usage.joined && float64(usage.writes) <= opts.ReferenceWriteRatio*float64(usage.queries()) {
+			reference[name] = true
+		}
+	}
+	// A reference table is joined to sharded tables: of two joined candidates,
+	// the one with the most queries stays sharded.
+	for changed := true; changed; {
+		changed = false
+		for _, je := range wl.joins {
+			left, right := je.left.table, je.right.table
+			if !reference[left] || !reference[right] {
+				continue
+			}
+			if wl.tables[left].queries() > wl.tables[right].queries() ||
+				(wl.tables[left].queries() == wl.tables[right].queries() && left < right) {
+				delete(reference, left)
+			} else {
+				delete(reference, right)
+			}
+			changed = true
+		}
+	}
+	// Only the joins between sharded tables need the rows to be co-located.
+	joinCounts := make(map[columnRef]int)
+	for _, je := range wl.joins {
+		if reference[je.left.table] || reference[je.right.table] {
+			continue
+		}
+		joinCounts[je.left] += je.count
+		joinCounts[je.right] += je.count
+	}
+
+	for _, name := range names {
+		t := tables[name]
+		usage := wl.tables[name]
+		vt := &vschemapb.Table{
+			Columns:                 schemaColumns(t.columns),
+			ColumnListAuthoritative: true,
+		}
+		advice.VSchema.Tables[name] = vt
+		if reference[name] {
+			vt.Type = vindexes.TypeReference
+			advice.Tables = append(advice.Tables, &TableAdvice{
+				Table:  name,
+				Type:   TableTypeReference,
+				Reason: fmt.Sprintf("joined to other tables, and written to by %d of its %d queries", usage.writes, usage.queries()),
+			})
+			continue
+		}
+
+		column, reason := primaryVindexColumn(t, usage, joinCounts)
+		vindex := addVindex(advice.VSchema, t.column(column))
+		vt.ColumnVindexes = []*vschemapb.ColumnVindex{{Column: column, Name: vindex}}
+		ta := &TableAdvice{
+			Table:  name,
+			Type:   TableTypeSharded,
+			Column: column,
+			Vindex: vindex,
+			Reason: reason,
+		}
+		advice.Tables = append(advice.Tables, ta)
+
+		for _, lookupColumn := range lookupColumns(t, usage, column, opts.LookupThreshold) {
+			lookup := addLookupVindex(advice.VSchema, opts.Keyspace, t, lookupColumn)
+			vt.ColumnVindexes = append(vt.ColumnVindexes, &vschemapb.ColumnVindex{Column: lookupColumn, Name: lookup})
+			ta.Lookups = append(ta.Lookups, lookup)
+			advice.Tables = append(advice.Tables, &TableAdvice{
+				Table:  lookup,
+				Type:   TableTypeLookup,
+				Column: lookupColumn,
+				Vindex: advice.VSchema.Tables[lookup].ColumnVindexes[0].Name,
+				Reason: fmt.Sprintf("backs the lookup vindex of %s.%s, compared to values by %d of the %d queries of %s",
+					name, lookupColumn, usage.filters[lookupColumn], usage.queries(), name),
+			})
+		}
+	}
+
+	advice.Routing, err = PlanQueries(env, &vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{opts.Keyspace: advice.VSchema},
+	}, opts.Keyspace, queries)
+	if err != nil {
+		return nil, err
+	}
+	return advice, nil
+}
+
+// primaryVindexColumn chooses the column of the primary vindex of a table.
+func primaryVindexColumn(t *table, usage *tableUsage, joinCounts map[columnRef]int) (string, string) {
+	best, bestScore := "", 0
+	for _, col := range t.columns {
+		name := col.Name.String()
+		score := usage.filters[name] + joinCounts[columnRef{table: t.name, column: name}]
+		// The first column of the primary key wins ties.
+		if score > bestScore || (score == bestScore && score > 0 && len(t.primaryKey) > 0 && strings.EqualFold(t.primaryKey[0], name)) {
+			best, bestScore = name, score
+		}
+	}
+	if best != "" {
+		joins := joinCounts[columnRef{table: t.name, column: best}]
+		return best, fmt.Sprintf("compared to values by %d and joined to sharded tables by %d of its %d queries",
+			usage.filters[best], joins, usage.queries())
+	}
+	if len(t.primaryKey) > 0 {
+		return t.column(t.primaryKey[0]).Name.String(), "first column of the primary key, as no query filters on a column"
+	}
+	return t.columns[0].Name.String(), "first column, as the table has no primary key and no query filters on a column"
+}
+
+// lookupColumns returns the columns that should have a lookup vindex.
+func lookupColumns(t *table, usage *tableUsage, primary string, threshold float64) []string {
+	var columns []string
+	for _, col := range t.columns {
+		name := col.Name.String()
+		count := usage.filters[name]
+		if name == primary || count == 0 || float64(count) < threshold*float64(usage.queries()) {
+			continue
+		}
+		columns = append(columns, name)
+	}
+	return columns
+}
+
+// vindexType returns the type of the vindex of a column: unicode_loose_xxhash
+// for text columns, so that the values that the collation considers equal
+// map to the same shard, and xxhash for the others.
+func vindexType(col *sqlparser.ColumnDefinition) string {
+	if sqltypes.IsText(col.Type.SQLType()) {
+		return "unicode_loose_xxhash"
+	}
+	return "xxhash"
+}
+
+// addVindex adds the functional vindex of a column to the keyspace, and
+// returns its name. The columns of the same type share the same vindex,
+// so that their rows are co-located when they have the same values.
+func addVindex(ks *vschemapb.Keyspace, col *sqlparser.ColumnDefinition) string {
+	name := vindexType(col)
+	ks.Vindexes[name] = &vschemapb.Vindex{Type: name}
+	return name
+}
+
+// addLookupVindex adds a consistent lookup vindex, owned by the table, on a
+// column, and the table that backs it, to the keyspace.
+func addLookupVindex(ks *vschemapb.Keyspace, keyspace string, t *table, column string) string {
+	name := fmt.Sprintf("%s_%s_lookup", t.name, column)
+	vindexType := "consistent_lookup"
+	if t.isUnique(column) {
+		vindexType = "consistent_lookup_unique"
+	}
+	ks.Vindexes[name] = &vschemapb.Vindex{
+		Type: vindexType,
+		Params: map[string]string{
+			"table": fmt.Sprintf("%s.%s", keyspace, name),
+			"from":  column,
+			"to":    lookupKeyspaceIDColumn,
+		},
+		Owner: t.name,
+	}
+	col := t.column(column)
+	ks.Tables[name] = &vschemapb.Table{
+		ColumnVindexes: []*vschemapb.ColumnVindex{{Column: column, Name: addVindex(ks, col)}},
+		Columns: []*vschemapb.Column{
+			{Name: column, Type: col.Type.SQLType()},
+			{Name: lookupKeyspaceIDColumn, Type: sqltypes.VarBinary},
+		},
+		ColumnListAuthoritative: true,
+	}
+	return name
+}
+
+func schemaColumns(columns []*sqlparser.ColumnDefinition) []*vschemapb.Column {
+	var out []*vschemapb.Column
+	for _, col := range columns {
+		out = append(out, &vschemapb.Column{Name: col.Name.String(), Type: col.Type.SQLType()})
+	}
+	return out
+}
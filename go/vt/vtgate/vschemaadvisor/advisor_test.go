@@ -0,0 +1,182 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vschemaadvisor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/vtenv"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+const testSchema = `
+create table customer (
+	id bigint not null,
+	email varchar(128),
+	name varchar(128),
+	country char(2),
+	primary key (id),
+	unique key (email)
+);
+create table orders (
+	id bigint not null,
+	customer_id bigint not null,
+	sku varchar(32),
+	primary key (id)
+);
+create table country (
+	code char(2) not null primary key,
+	name varchar(64)
+);
+`
+
+func TestParseQueryLog(t *testing.T) {
+	log := `{"Method": "Execute", "SQL": "select * from customer where id = 1", "Error": ""}
+{"Method": "Execute", "SQL": "select * from orders", "Error": ""}
+
+{"Method": "Execute", "SQL": "select * from customer where id = 1", "Error": ""}
+{"Method": "Execute", "SQL": "select * from nope", "Error": "table nope not found"}
+`
+	queries, err := ParseQueryLog(strings.NewReader(log))
+	require.NoError(t, err)
+	assert.Equal(t, []*Query{
+		{SQL: "select * from customer where id = 1", Count: 2},
+		{SQL: "select * from orders", Count: 1},
+	}, queries)
+
+	_, err = ParseQueryLog(strings.NewReader("select 1\n"))
+	require.ErrorContains(t, err, "invalid query log entry on line 1")
+}
+
+func TestAdvise(t *testing.T) {
+	env := vtenv.NewTestEnv()
+	queries, err := ParseQueries(env.Parser(), strings.Repeat("select * from customer where id = 1;", 5)+
+		strings.Repeat("select * from customer where email = 'a@b.c';", 2)+
+		strings.Repeat("select * from orders where customer_id = 1;", 5)+
+		strings.Repeat("select o.id from orders o join customer c on o.customer_id = c.id where c.id = 1;", 3)+
+		strings.Repeat("select c.name, co.name from customer c join country co on c.country = co.code where c.id = 2;", 2)+
+		"insert into orders(id, customer_id, sku) values (1, 1, 'x');"+
+		"select count(*) from orders;"+
+		"select * from nope")
+	require.NoError(t, err)
+
+	advice, err := Advise(env, testSchema, queries, Options{Keyspace: "commerce"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []*TableAdvice{
+		{
+			Table:  "country",
+			Type:   TableTypeReference,
+			Reason: "joined to other tables, and written to by 0 of its 2 queries",
+		},
+		{
+			Table:   "customer",
+			Type:    TableTypeSharded,
+			Column:  "id",
+			Vindex:  "xxhash",
+			Lookups: []string{"customer_email_lookup"},
+			Reason:  "compared to values by 10 and joined to sharded tables by 3 of its 12 queries",
+		},
+		{
+			Table:  "customer_email_lookup",
+			Type:   TableTypeLookup,
+			Column: "email",
+			Vindex: "unicode_loose_xxhash",
+			Reason: "backs the lookup vindex of customer.email, compared to values by 2 of the 12 queries of customer",
+		},
+		{
+			Table:  "orders",
+			Type:   TableTypeSharded,
+			Column: "customer_id",
+			Vindex: "xxhash",
+			Reason: "compared to values by 5 and joined to sharded tables by 3 of its 10 queries",
+		},
+	}, advice.Tables)
+
+	assert.Equal(t, &vschemapb.Vindex{
+		Type:   "consistent_lookup_unique",
+		Params: map[string]string{"table": "commerce.customer_email_lookup", "from": "email", "to": "keyspace_id"},
+		Owner:  "customer",
+	}, advice.VSchema.Vindexes["customer_email_lookup"])
+	assert.Equal(t, []*vschemapb.ColumnVindex{
+		{Column: "id", Name: "xxhash"},
+		{Column: "email", Name: "customer_email_lookup"},
+	}, advice.VSchema.Tables["customer"].ColumnVindexes)
+	assert.Equal(t, "reference", advice.VSchema.Tables["country"].Type)
+
+	routing := make(map[string]string)
+	for _, qr := range advice.Routing.Queries {
+		routing[qr.SQL] = qr.Routing
+	}
+	assert.Equal(t, map[string]string{
+		"select * from customer where id = 1":                                                          RoutingSingleShard,
+		"select * from customer where email = 'a@b.c'":                                                 RoutingSingleShard,
+		"select * from orders where customer_id = 1":                                                   RoutingSingleShard,
+		"select o.id from orders o join customer c on o.customer_id = c.id where c.id = 1":             RoutingSingleShard,
+		"select c.name, co.name from customer c join country co on c.country = co.code where c.id = 2": RoutingSingleShard,
+		"insert into orders(id, customer_id, sku) values (1, 1, 'x')":                                  RoutingSingleShard,
+		"select count(*) from orders":                                                                  RoutingScatter,
+		"select * from nope":                                                                           RoutingUnsupported,
+	}, routing)
+	assert.Equal(t, 18, advice.Routing.SingleShard)
+	assert.Equal(t, 1, advice.Routing.Scatter)
+	assert.Equal(t, 1, advice.Routing.Unsupported)
+}
+
+func TestPlanQueries(t *testing.T) {
+	env := vtenv.NewTestEnv()
+	vschema := &vschemapb.SrvVSchema{Keyspaces: map[string]*vschemapb.Keyspace{
+		"commerce": {
+			Sharded:  true,
+			Vindexes: map[string]*vschemapb.Vindex{"xxhash": {Type: "xxhash"}},
+			Tables: map[string]*vschemapb.Table{
+				"customer": {ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "id", Name: "xxhash"}}},
+				"orders":   {ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "id", Name: "xxhash"}}},
+			},
+		},
+		// The unqualified tables must be found in the keyspace that the
+		// queries are planned for, even though other keyspaces have
+		// tables with the same names.
+		"archive": {
+			Tables: map[string]*vschemapb.Table{
+				"customer": {},
+			},
+		},
+	}}
+	queries := []*Query{
+		{SQL: "select * from customer where id in (1, 2)", Count: 3},
+		{SQL: "select o.id, c.name from orders o join customer c on o.customer_id = c.id where c.id = 1 and o.id = 2", Count: 2},
+		{SQL: "select * from archive.customer", Count: 1},
+	}
+	report, err := PlanQueries(env, vschema, "commerce", queries)
+	require.NoError(t, err)
+	assert.Equal(t, RoutingMultiShard, report.Queries[0].Routing)
+	assert.Equal(t, []string{"IN"}, report.Queries[0].Routes)
+	assert.Equal(t, RoutingCrossShard, report.Queries[1].Routing)
+	assert.Equal(t, RoutingSingleShard, report.Queries[2].Routing)
+	assert.Equal(t, []string{"Unsharded"}, report.Queries[2].Routes)
+	assert.Equal(t, 3, report.MultiShard)
+	assert.Equal(t, 2, report.CrossShard)
+
+	_, err = PlanQueries(env, vschema, "nope", queries)
+	require.ErrorContains(t, err, "keyspace nope not found in the vschema")
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vschemaadvisor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+const (
+	// RoutingSingleShard is the routing of the queries that are sent to a
+	// single shard.
+	RoutingSingleShard = "single-shard"
+	// RoutingMultiShard is the routing of the queries that are sent to the
+	// shards of a list of values, or of a range of values.
+	RoutingMultiShard = "multi-shard"
+	// RoutingCrossShard is the routing of the queries that vtgate has to
+	// split into several queries, such as cross-shard joins.
+	RoutingCrossShard = "cross-shard"
+	// RoutingScatter is the routing of the queries that are sent to all the
+	// shards of a keyspace.
+	RoutingScatter = "scatter"
+	// RoutingUnsupported is the routing of the queries that cannot be planned.
+	RoutingUnsupported = "unsupported"
+)
+
+// QueryRouting is how a query of the workload is routed.
+type QueryRouting struct {
+	SQL     string `json:"sql"`
+	Count   int    `json:"count"`
+	Routing string `json:"routing"`
+	// Routes are the variants of the routes of the plan of the query.
+	Routes []string `json:"routes,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// Report is how the queries of a workload are routed with a VSchema. The
+// totals count each query as many times as it was seen in the workload.
+type Report struct {
+	Queries     []*QueryRouting `json:"queries"`
+	SingleShard int             `json:"single_shard"`
+	MultiShard  int             `json:"multi_shard"`
+	CrossShard  int             `json:"cross_shard"`
+	Scatter     int             `json:"scatter"`
+	Unsupported int             `json:"unsupported"`
+}
+
+// ddlConfig allows all the DDL statements to be planned.
+type ddlConfig struct{}
+
+func (ddlConfig) OnlineEnabled() bool {
+	return true
+}
+
+func (ddlConfig) DirectEnabled() bool {
+	return true
+}
+
+// PlanQueries plans the queries of a workload with the planner of vtgate,
+// against a VSchema, and reports how they are routed. The tables of the
+// queries that are not qualified are looked up in the keyspace.
+func PlanQueries(env *vtenv.Environment, srvVSchema *vschemapb.SrvVSchema, keyspace string, queries []*Query) (*Report, error) {
+	vschema := vindexes.BuildVSchema(srvVSchema, env.Parser())
+	ks, ok := vschema.Keyspaces[keyspace]
+	if !ok {
+		return nil, fmt.Errorf("keyspace %s not found in the vschema", keyspace)
+	}
+	if ks.Error != nil {
+		return nil, fmt.Errorf("invalid vschema for keyspace %s: %w", keyspace, ks.Error)
+	}
+	vs := newPlannerVSchema(env, srvVSchema, vschema, ks.Keyspace)
+
+	report := &Report{}
+	for _, q := range queries {
+		qr := &QueryRouting{SQL: q.SQL, Count: q.Count}
+		plan, err := planQuery(vs, keyspace, q.SQL)
+		if err != nil {
+			qr.Routing = RoutingUnsupported
+			qr.Error = err.Error()
+		} else {
+			qr.Routes = planRoutes(engine.PrimitiveToPlanDescription(plan.Instructions, nil), nil)
+			qr.Routing = routing(qr.Routes)
+		}
+		switch qr.Routing {
+		case RoutingSingleShard:
+			report.SingleShard += q.Count
+		case RoutingMultiShard:
+			report.MultiShard += q.Count
+		case RoutingCrossShard:
+			report.CrossShard += q.Count
+		case RoutingScatter:
+			report.Scatter += q.Count
+		case RoutingUnsupported:
+			report.Unsupported += q.Count
+		}
+		report.Queries = append(report.Queries, qr)
+	}
+	return report, nil
+}
+
+// planQuery plans a query the way vtgate does, without executing it.
+func planQuery(vschema plancontext.VSchema, keyspace, query string) (*engine.Plan, error) {
+	stmt, known, err := vschema.Environment().Parser().Parse2(query)
+	if err != nil {
+		return nil, err
+	}
+	reservedVars := sqlparser.NewReservedVars("vtg", known)
+	result, err := sqlparser.Normalize(stmt, reservedVars, map[string]*querypb.BindVariable{}, false, keyspace, sqlparser.SQLSelectLimitUnset, "", nil, vschema.GetForeignKeyChecksState(), vschema)
+	if err != nil {
+		return nil, err
+	}
+	return planbuilder.BuildFromStmt(context.Background(), query, result.AST, reservedVars, vschema, result.BindVarNeeds, ddlConfig{})
+}
+
+// planRoutes collects the variants of the primitives of a plan that send
+// queries to the shards. The lookup of a vindex and the query that uses
+// its result count as a single route.
+func planRoutes(pd engine.PrimitiveDescription, routes []string) []string {
+	switch pd.OperatorType {
+	case "VindexLookup":
+		return append(routes, "VindexLookup")
+	case "Route", "Update", "Delete", "Insert":
+		if pd.Variant != "" {
+			routes = append(routes, pd.Variant)
+		} else {
+			routes = append(routes, pd.OperatorType)
+		}
+	}
+	for _, input := range pd.Inputs {
+		routes = planRoutes(input, routes)
+	}
+	return routes
+}
+
+// routing classifies the routes of the plan of a query.
+func routing(routes []string) string {
+	result := RoutingSingleShard
+	for _, route := range routes {
+		switch route {
+		case engine.Scatter.String():
+			return RoutingScatter
+		case engine.Equal.String(), engine.IN.String(), engine.MultiEqual.String(), engine.Between.String(), engine.SubShard.String():
+			result = RoutingMultiShard
+		}
+	}
+	var shardRoutes int
+	for _, route := range routes {
+		// Reference tables and the next values of sequences are read from any shard.
+		if route != engine.Reference.String() && route != engine.Next.String() && !strings.HasPrefix(route, "Select") {
+			shardRoutes++
+		}
+	}
+	if shardRoutes > 1 {
+		return RoutingCrossShard
+	}
+	return result
+}
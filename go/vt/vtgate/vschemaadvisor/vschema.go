@@ -0,0 +1,286 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vschemaadvisor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/semantics"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+var _ plancontext.VSchema = (*plannerVSchema)(nil)
+
+// plannerVSchema is the VSchema that the queries of a workload are planned
+// with. It behaves like a vtgate session that targets the primary tablets of
+// the keyspace, without connecting to anything.
+type plannerVSchema struct {
+	env        *vtenv.Environment
+	srvVSchema *vschemapb.SrvVSchema
+	vschema    *vindexes.VSchema
+	keyspace   *vindexes.Keyspace
+	version    plancontext.PlannerVersion
+}
+
+func newPlannerVSchema(env *vtenv.Environment, srvVSchema *vschemapb.SrvVSchema, vschema *vindexes.VSchema, keyspace *vindexes.Keyspace) *plannerVSchema {
+	return &plannerVSchema{
+		env:        env,
+		srvVSchema: srvVSchema,
+		vschema:    vschema,
+		keyspace:   keyspace,
+	}
+}
+
+// destination returns the keyspace, tablet type and destination of a table
+// qualifier. The tables that are not qualified are in the keyspace.
+func (vs *plannerVSchema) destination(qualifier string) (string, topodatapb.TabletType, key.ShardDestination, error) {
+	keyspace, tabletType, dest, err := topoproto.ParseDestination(qualifier, topodatapb.TabletType_PRIMARY)
+	if err != nil {
+		return "", tabletType, nil, err
+	}
+	if keyspace == "" {
+		keyspace = vs.keyspace.Name
+	}
+	return keyspace, tabletType, dest, nil
+}
+
+func (vs *plannerVSchema) FindTable(name sqlparser.TableName) (*vindexes.BaseTable, string, topodatapb.TabletType, key.ShardDestination, error) {
+	keyspace, tabletType, dest, err := vs.destination(name.Qualifier.String())
+	if err != nil {
+		return nil, "", tabletType, nil, err
+	}
+	table, err := vs.vschema.FindTable(keyspace, name.Name.String())
+	if err != nil {
+		return nil, "", tabletType, nil, err
+	}
+	return table, keyspace, tabletType, dest, nil
+}
+
+func (vs *plannerVSchema) FindView(name sqlparser.TableName) sqlparser.TableStatement {
+	keyspace, _, _, err := vs.destination(name.Qualifier.String())
+	if err != nil {
+		return nil
+	}
+	return vs.vschema.FindView(keyspace, name.Name.String())
+}
+
+func (vs *plannerVSchema) FindViewTarget(name sqlparser.TableName) (*vindexes.Keyspace, error) {
+	keyspace, _, _, err := vs.destination(name.Qualifier.String())
+	if err != nil {
+		return nil, err
+	}
+	return vs.FindKeyspace(keyspace)
+}
+
+func (vs *plannerVSchema) FindTableOrVindex(name sqlparser.TableName) (*vindexes.BaseTable, vindexes.Vindex, string, topodatapb.TabletType, key.ShardDestination, error) {
+	if name.Qualifier.IsEmpty() && name.Name.String() == "dual" {
+		// The dual table is only resolved when it is not qualified, the
+		// same way vtgate does.
+		return &vindexes.BaseTable{
+			Name:     sqlparser.NewIdentifierCS("dual"),
+			Keyspace: vs.keyspace,
+			Type:     vindexes.TypeReference,
+		}, nil, vs.keyspace.Name, topodatapb.TabletType_PRIMARY, nil, nil
+	}
+	keyspace, tabletType, dest, err := vs.destination(name.Qualifier.String())
+	if err != nil {
+		return nil, nil, "", tabletType, nil, err
+	}
+	table, vindex, err := vs.vschema.FindTableOrVindex(keyspace, name.Name.String(), topodatapb.TabletType_PRIMARY)
+	if err != nil {
+		return nil, nil, "", tabletType, nil, err
+	}
+	return table, vindex, keyspace, tabletType, dest, nil
+}
+
+func (vs *plannerVSchema) SelectedKeyspace() (*vindexes.Keyspace, error) {
+	return vs.keyspace, nil
+}
+
+func (vs *plannerVSchema) TargetString() string {
+	return vs.keyspace.Name
+}
+
+func (vs *plannerVSchema) ShardDestination() key.ShardDestination {
+	return nil
+}
+
+func (vs *plannerVSchema) TabletType() topodatapb.TabletType {
+	return topodatapb.TabletType_PRIMARY
+}
+
+func (vs *plannerVSchema) TargetDestination(qualifier string) (key.ShardDestination, *vindexes.Keyspace, topodatapb.TabletType, error) {
+	keyspace := vs.keyspace.Name
+	if qualifier != "" {
+		keyspace = qualifier
+	}
+	ks := vs.vschema.Keyspaces[keyspace]
+	if ks == nil {
+		return nil, nil, 0, vterrors.VT05003(keyspace)
+	}
+	return nil, ks.Keyspace, topodatapb.TabletType_PRIMARY, nil
+}
+
+func (vs *plannerVSchema) AnyKeyspace() (*vindexes.Keyspace, error) {
+	return vs.keyspace, nil
+}
+
+func (vs *plannerVSchema) FirstSortedKeyspace() (*vindexes.Keyspace, error) {
+	return vs.keyspace, nil
+}
+
+func (vs *plannerVSchema) SysVarSetEnabled() bool {
+	return true
+}
+
+func (vs *plannerVSchema) KeyspaceExists(keyspace string) bool {
+	return vs.vschema.Keyspaces[keyspace] != nil
+}
+
+func (vs *plannerVSchema) AllKeyspace() ([]*vindexes.Keyspace, error) {
+	keyspaces := make([]*vindexes.Keyspace, 0, len(vs.vschema.Keyspaces))
+	for _, ks := range vs.vschema.Keyspaces {
+		keyspaces = append(keyspaces, ks.Keyspace)
+	}
+	sort.Slice(keyspaces, func(i, j int) bool {
+		return keyspaces[i].Name < keyspaces[j].Name
+	})
+	return keyspaces, nil
+}
+
+func (vs *plannerVSchema) FindKeyspace(keyspace string) (*vindexes.Keyspace, error) {
+	if ks := vs.vschema.Keyspaces[keyspace]; ks != nil {
+		return ks.Keyspace, nil
+	}
+	return nil, nil
+}
+
+func (vs *plannerVSchema) GetSemTable() *semantics.SemTable {
+	return nil
+}
+
+func (vs *plannerVSchema) Planner() plancontext.PlannerVersion {
+	return vs.version
+}
+
+func (vs *plannerVSchema) SetPlannerVersion(version plancontext.PlannerVersion) {
+	vs.version = version
+}
+
+func (vs *plannerVSchema) ConnCollation() collations.ID {
+	return vs.env.CollationEnv().DefaultConnectionCharset()
+}
+
+func (vs *plannerVSchema) Environment() *vtenv.Environment {
+	return vs.env
+}
+
+func (vs *plannerVSchema) ErrorIfShardedF(keyspace *vindexes.Keyspace, _, errFmt string, params ...any) error {
+	if keyspace.Sharded {
+		return fmt.Errorf(errFmt, params...)
+	}
+	return nil
+}
+
+func (vs *plannerVSchema) WarnUnshardedOnly(string, ...any) {}
+
+func (vs *plannerVSchema) PlannerWarning(string) {}
+
+func (vs *plannerVSchema) ForeignKeyMode(keyspace string) (vschemapb.Keyspace_ForeignKeyMode, error) {
+	if ks := vs.vschema.Keyspaces[keyspace]; ks != nil && ks.ForeignKeyMode != vschemapb.Keyspace_unspecified {
+		return ks.ForeignKeyMode, nil
+	}
+	return vschemapb.Keyspace_unmanaged, nil
+}
+
+func (vs *plannerVSchema) KeyspaceError(keyspace string) error {
+	if ks := vs.vschema.Keyspaces[keyspace]; ks != nil {
+		return ks.Error
+	}
+	return nil
+}
+
+func (vs *plannerVSchema) GetForeignKeyChecksState() *bool {
+	return nil
+}
+
+func (vs *plannerVSchema) GetVSchema() *vindexes.VSchema {
+	return vs.vschema
+}
+
+func (vs *plannerVSchema) GetSrvVschema() *vschemapb.SrvVSchema {
+	return vs.srvVSchema
+}
+
+func (vs *plannerVSchema) FindRoutedShard(keyspace, shard string) (string, error) {
+	return vs.vschema.FindRoutedShard(keyspace, shard)
+}
+
+func (vs *plannerVSchema) IsShardRoutingEnabled() bool {
+	return false
+}
+
+func (vs *plannerVSchema) IsViewsEnabled() bool {
+	return false
+}
+
+func (vs *plannerVSchema) GetUDV(string) *querypb.BindVariable {
+	return nil
+}
+
+func (vs *plannerVSchema) PlanPrepareStatement(ctx context.Context, query string) (*engine.Plan, error) {
+	return planQuery(vs, vs.keyspace.Name, query)
+}
+
+func (vs *plannerVSchema) ClearPrepareData(string) {}
+
+func (vs *plannerVSchema) GetPrepareData(string) *vtgatepb.PrepareData {
+	return nil
+}
+
+func (vs *plannerVSchema) StorePrepareData(string, *vtgatepb.PrepareData) {}
+
+func (vs *plannerVSchema) GetAggregateUDFs() []string {
+	return vs.vschema.GetAggregateUDFs()
+}
+
+func (vs *plannerVSchema) FindMirrorRule(name sqlparser.TableName) (*vindexes.MirrorRule, error) {
+	keyspace, tabletType, _, err := vs.destination(name.Qualifier.String())
+	if err != nil {
+		return nil, err
+	}
+	return vs.vschema.FindMirrorRule(keyspace, name.Name.String(), tabletType)
+}
+
+func (vs *plannerVSchema) GetBindVars() map[string]*querypb.BindVariable {
+	return nil
+}
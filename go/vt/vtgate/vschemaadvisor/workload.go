@@ -0,0 +1,335 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vschemaadvisor
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// Query is a query of the workload, with the number of times it was seen.
+type Query struct {
+	SQL   string `json:"sql"`
+	Count int    `json:"count"`
+}
+
+// queryLogEntry holds the fields of a vtgate query log entry, in JSON
+// format, that the advisor uses.
+type queryLogEntry struct {
+	SQL   string
+	Error string
+}
+
+// maxQueryLogLine is the maximum length of a line of the query log.
+const maxQueryLogLine = 16 * 1024 * 1024
+
+// ParseQueryLog reads the queries of a vtgate query log in JSON format
+// (--querylog-format json), one entry per line. The queries that failed
+// are skipped, and identical queries are counted once, in the order in
+// which they were first seen.
+func ParseQueryLog(r io.Reader) ([]*Query, error) {
+	queries := newQuerySet()
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxQueryLogLine)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry queryLogEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("invalid query log entry on line %d: %w", lineNum, err)
+		}
+		if entry.SQL == "" || entry.Error != "" {
+			continue
+		}
+		queries.add(entry.SQL)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return queries.queries, nil
+}
+
+// ParseQueries splits a list of semicolon separated queries into the
+// queries of a workload, each identical query being counted once.
+func ParseQueries(parser *sqlparser.Parser, sql string) ([]*Query, error) {
+	pieces, err := parser.SplitStatementToPieces(sql)
+	if err != nil {
+		return nil, err
+	}
+	queries := newQuerySet()
+	for _, piece := range pieces {
+		if piece = strings.TrimSpace(piece); piece != "" {
+			queries.add(piece)
+		}
+	}
+	return queries.queries, nil
+}
+
+// CountQueries returns the queries of a workload from a list of queries,
+// each identical query being counted once.
+func CountQueries(sqls []string) []*Query {
+	queries := newQuerySet()
+	for _, sql := range sqls {
+		queries.add(sql)
+	}
+	return queries.queries
+}
+
+type querySet struct {
+	queries []*Query
+	bySQL   map[string]*Query
+}
+
+func newQuerySet() *querySet {
+	return &querySet{bySQL: make(map[string]*Query)}
+}
+
+func (qs *querySet) add(sql string) {
+	if q, ok := qs.bySQL[sql]; ok {
+		q.Count++
+		return
+	}
+	q := &Query{SQL: sql, Count: 1}
+	qs.bySQL[sql] = q
+	qs.queries = append(qs.queries, q)
+}
+
+type columnRef struct {
+	table  string
+	column string
+}
+
+type joinEdge struct {
+	left, right columnRef
+	count       int
+}
+
+// tableUsage is how the queries of the workload use a table.
+type tableUsage struct {
+	reads  int
+	writes int
+	// filters is the number of queries that compare each column to values.
+	filters map[string]int
+	// joined is set when the table is joined to another table.
+	joined bool
+}
+
+func (tu *tableUsage) queries() int {
+	return tu.reads + tu.writes
+}
+
+// workload is the analysis of the queries of a workload against a schema.
+type workload struct {
+	tables map[string]*tableUsage
+	joins  []*joinEdge
+}
+
+func analyzeWorkload(parser *sqlparser.Parser, tables map[string]*table, queries []*Query) *workload {
+	wl := &workload{tables: make(map[string]*tableUsage)}
+	for name := range tables {
+		wl.tables[name] = &tableUsage{filters: make(map[string]int)}
+	}
+	joins := make(map[[2]columnRef]*joinEdge)
+	for _, q := range queries {
+		stmt, err := parser.Parse(q.SQL)
+		if err != nil {
+			// The query is reported as unsupported when it is planned.
+			continue
+		}
+		sa := newStatementAnalysis(tables, stmt)
+		for name, write := range sa.tables {
+			if write {
+				wl.tables[name].writes += q.Count
+			} else {
+				wl.tables[name].reads += q.Count
+			}
+		}
+		for col := range sa.filters {
+			wl.tables[col.table].filters[col.column] += q.Count
+		}
+		for edge := range sa.joins {
+			wl.tables[edge[0].table].joined = true
+			wl.tables[edge[1].table].joined = true
+			je, ok := joins[edge]
+			if !ok {
+				je = &joinEdge{left: edge[0], right: edge[1]}
+				joins[edge] = je
+				wl.joins = append(wl.joins, je)
+			}
+			je.count += q.Count
+		}
+	}
+	return wl
+}
+
+// statementAnalysis collects the tables, the filtered columns and the
+// join predicates of a statement.
+type statementAnalysis struct {
+	schema map[string]*table
+	// aliases maps the aliases of the tables of the statement to the tables.
+	aliases map[string]string
+	// tables are the tables of the statement, and whether they are written to.
+	tables  map[string]bool
+	filters map[columnRef]bool
+	joins   map[[2]columnRef]bool
+}
+
+func newStatementAnalysis(schema map[string]*table, stmt sqlparser.Statement) *statementAnalysis {
+	sa := &statementAnalysis{
+		schema:  schema,
+		aliases: make(map[string]string),
+		tables:  make(map[string]bool),
+		filters: make(map[columnRef]bool),
+		joins:   make(map[[2]columnRef]bool),
+	}
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if ate, ok := node.(*sqlparser.AliasedTableExpr); ok {
+			if tn, ok := ate.Expr.(sqlparser.TableName); ok {
+				name := tn.Name.String()
+				if _, ok := schema[name]; ok {
+					alias := name
+					if !ate.As.IsEmpty() {
+						alias = ate.As.String()
+					}
+					sa.aliases[alias] = name
+					if _, ok := sa.tables[name]; !ok {
+						sa.tables[name] = false
+					}
+				}
+			}
+		}
+		return true, nil
+	}, stmt)
+	sa.markWrites(stmt)
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if cmp, ok := node.(*sqlparser.ComparisonExpr); ok {
+			sa.addComparison(cmp)
+		}
+		return true, nil
+	}, stmt)
+	return sa
+}
+
+func (sa *statementAnalysis) markWrites(stmt sqlparser.Statement) {
+	markTables := func(exprs ...sqlparser.TableExpr) {
+		for _, expr := range exprs {
+			_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+				if ate, ok := node.(*sqlparser.AliasedTableExpr); ok {
+					if tn, ok := ate.Expr.(sqlparser.TableName); ok {
+						if _, ok := sa.tables[tn.Name.String()]; ok {
+							sa.tables[tn.Name.String()] = true
+						}
+					}
+					return false, nil
+				}
+				return true, nil
+			}, expr)
+		}
+	}
+	switch stmt := stmt.(type) {
+	case *sqlparser.Insert:
+		markTables(stmt.Table)
+	case *sqlparser.Update:
+		markTables(stmt.TableExprs...)
+	case *sqlparser.Delete:
+		if len(stmt.Targets) == 0 {
+			markTables(stmt.TableExprs...)
+			return
+		}
+		for _, target := range stmt.Targets {
+			if name, ok := sa.aliases[target.Name.String()]; ok {
+				sa.tables[name] = true
+			}
+		}
+	}
+}
+
+func (sa *statementAnalysis) addComparison(cmp *sqlparser.ComparisonExpr) {
+	if cmp.Operator != sqlparser.EqualOp && cmp.Operator != sqlparser.InOp {
+		return
+	}
+	left, leftIsCol := cmp.Left.(*sqlparser.ColName)
+	right, rightIsCol := cmp.Right.(*sqlparser.ColName)
+	switch {
+	case leftIsCol && rightIsCol:
+		l, lok := sa.resolve(left)
+		r, rok := sa.resolve(right)
+		if !lok || !rok || l.table == r.table {
+			return
+		}
+		if r.table < l.table || (r.table == l.table && r.column < l.column) {
+			l, r = r, l
+		}
+		sa.joins[[2]columnRef{l, r}] = true
+	case leftIsCol && isValue(cmp.Right):
+		if col, ok := sa.resolve(left); ok {
+			sa.filters[col] = true
+		}
+	case rightIsCol && isValue(cmp.Left) && cmp.Operator == sqlparser.EqualOp:
+		if col, ok := sa.resolve(right); ok {
+			sa.filters[col] = true
+		}
+	}
+}
+
+// resolve finds the table of a column, either through its qualifier or,
+// when it is not qualified, as the only table of the statement that has
+// the column.
+func (sa *statementAnalysis) resolve(col *sqlparser.ColName) (columnRef, bool) {
+	name := col.Name.String()
+	if !col.Qualifier.IsEmpty() {
+		tableName, ok := sa.aliases[col.Qualifier.Name.String()]
+		if !ok || sa.schema[tableName].column(name) == nil {
+			return columnRef{}, false
+		}
+		return columnRef{table: tableName, column: sa.schema[tableName].column(name).Name.String()}, true
+	}
+	var found columnRef
+	for _, tableName := range sa.aliases {
+		if c := sa.schema[tableName].column(name); c != nil {
+			if found.table != "" && found.table != tableName {
+				return columnRef{}, false
+			}
+			found = columnRef{table: tableName, column: c.Name.String()}
+		}
+	}
+	return found, found.table != ""
+}
+
+func isValue(expr sqlparser.Expr) bool {
+	switch expr := expr.(type) {
+	case *sqlparser.Literal, *sqlparser.Argument, sqlparser.ListArg:
+		return true
+	case sqlparser.ValTuple:
+		for _, e := range expr {
+			if !isValue(e) {
+				return false
+			}
+		}
+		return len(expr) > 0
+	}
+	return false
+}
@@ -61,6 +61,7 @@ import (
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
 	vtschema "vitess.io/vitess/go/vt/vtgate/schema"
 	"vitess.io/vitess/go/vt/vtgate/txresolver"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
 	"vitess.io/vitess/go/vt/vtgate/vtgateservice"
 )
 
@@ -168,6 +169,9 @@ var (
 	warmingReadsPercent      = 0
 	warmingReadsQueryTimeout = 5 * time.Second
 	warmingReadsConcurrency  = 500
+
+	// sequenceWorkerID is the id of this vtgate in the ids of the snowflake sequence generator.
+	sequenceWorkerID int64 = -1
 )
 
 func registerFlags(fs *pflag.FlagSet) {
@@ -205,6 +209,7 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&warmingReadsPercent, "warming-reads-percent", 0, "Percentage of reads on the primary to forward to replicas. Useful for keeping buffer pools warm")
 	fs.IntVar(&warmingReadsConcurrency, "warming-reads-concurrency", 500, "Number of concurrent warming reads allowed")
 	fs.DurationVar(&warmingReadsQueryTimeout, "warming-reads-query-timeout", 5*time.Second, "Timeout of warming read queries")
+	fs.Int64Var(&sequenceWorkerID, "sequence-worker-id", sequenceWorkerID, "The id of this vtgate, between 0 and 1023 and unique across the vtgates of the cluster, used by the snowflake sequence generator. The snowflake generator cannot be used when it is not set.")
 
 	viperutil.BindFlags(fs,
 		enableOnlineDDL,
@@ -299,6 +304,9 @@ func Init(
 	if err != nil {
 		log.Fatalf("Unable to get Topo server: %v", err)
 	}
+	if err := vindexes.SetSequenceWorkerID(sequenceWorkerID); err != nil {
+		log.Fatalf("Invalid --sequence-worker-id: %v", err)
+	}
 
 	// We need to get the keyspaces and rebuild the keyspace graphs
 	// before we make the topo-server read-only incase we are filtering by
@@ -389,6 +397,9 @@ func Init(
 		log.Fatalf("error initializing query logger: %v", err)
 	}
 
+	// invalidate the caches of the lookup vindexes when their tables change
+	executor.watchLookupCaches(newLookupCacheWatcher(ctx, vsm.VStream))
+
 	// connect the schema tracker with the vschema manager
 	if enableSchemaChangeSignal {
 		st.RegisterSignalReceiver(executor.vm.Rebuild)
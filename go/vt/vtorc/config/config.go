@@ -218,6 +218,33 @@ var (
 			Dynamic:  true,
 		},
 	)
+
+	recoveryDryRun = viperutil.Configure(
+		"recovery-dry-run",
+		viperutil.Options[bool]{
+			FlagName: "recovery-dry-run",
+			Default:  false,
+			Dynamic:  true,
+		},
+	)
+
+	leaderElection = viperutil.Configure(
+		"leader-election",
+		viperutil.Options[bool]{
+			FlagName: "leader-election",
+			Default:  false,
+			Dynamic:  false,
+		},
+	)
+
+	leaderElectionID = viperutil.Configure(
+		"leader-election-id",
+		viperutil.Options[string]{
+			FlagName: "leader-election-id",
+			Default:  "",
+			Dynamic:  false,
+		},
+	)
 )
 
 func init() {
@@ -246,6 +273,9 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.Bool("allow-recovery", allowRecovery.Default(), "Whether VTOrc should be allowed to run recovery actions")
 	fs.Bool("change-tablets-with-errant-gtid-to-drained", convertTabletsWithErrantGTIDs.Default(), "Whether VTOrc should be changing the type of tablets with errant GTIDs to DRAINED")
 	fs.Bool("enable-primary-disk-stalled-recovery", enablePrimaryDiskStalledRecovery.Default(), "Whether VTOrc should detect a stalled disk on the primary and failover")
+	fs.Bool("recovery-dry-run", recoveryDryRun.Default(), "Whether VTOrc should only record the recoveries it would run, including the primary it would promote, instead of running them")
+	fs.Bool("leader-election", leaderElection.Default(), "Whether the VTOrc instances should elect a leader for each shard they watch through the topology server. Only the leader of a shard runs its recoveries, while the other instances keep discovering tablets and detecting problems so that they can take over")
+	fs.String("leader-election-id", leaderElectionID.Default(), "Unique id of this VTOrc instance in the leader election. Defaults to the hostname and port of this instance")
 
 	viperutil.BindFlags(fs,
 		instancePollTime,
@@ -268,6 +298,9 @@ func registerFlags(fs *pflag.FlagSet) {
 		allowRecovery,
 		convertTabletsWithErrantGTIDs,
 		enablePrimaryDiskStalledRecovery,
+		recoveryDryRun,
+		leaderElection,
+		leaderElectionID,
 	)
 }
 
@@ -411,6 +444,31 @@ func GetStalledDiskPrimaryRecovery() bool {
 	return enablePrimaryDiskStalledRecovery.Get()
 }
 
+// GetRecoveryDryRun reports whether VTOrc should only record the recoveries it would run instead of running them.
+func GetRecoveryDryRun() bool {
+	return recoveryDryRun.Get()
+}
+
+// SetRecoveryDryRun sets the value for the recoveryDryRun variable. This should only be used from tests.
+func SetRecoveryDryRun(val bool) {
+	recoveryDryRun.Set(val)
+}
+
+// GetLeaderElection reports whether VTOrc should elect a leader among the instances watching each shard.
+func GetLeaderElection() bool {
+	return leaderElection.Get()
+}
+
+// SetLeaderElection sets the value for the leaderElection variable. This should only be used from tests.
+func SetLeaderElection(val bool) {
+	leaderElection.Set(val)
+}
+
+// GetLeaderElectionID returns the id of this VTOrc instance in the leader election.
+func GetLeaderElectionID() string {
+	return leaderElectionID.Get()
+}
+
 // MarkConfigurationLoaded is called once configuration has first been loaded.
 // Listeners on ConfigurationLoaded will get a notification
 func MarkConfigurationLoaded() {
@@ -23,6 +23,7 @@ var TableNames = []string{
 	"topology_recovery",
 	"database_instance_topology_history",
 	"recovery_detection",
+	"recovery_dry_run",
 	"database_instance_last_analysis",
 	"database_instance_analysis_changelog",
 	"vtorc_db_deployments",
@@ -198,6 +199,26 @@ CREATE TABLE recovery_detection (
 	PRIMARY KEY (detection_id)
 )`,
 	`
+DROP TABLE IF EXISTS recovery_dry_run
+`,
+	`
+CREATE TABLE recovery_dry_run (
+	dry_run_id integer,
+	detection_id bigint NOT NULL DEFAULT 0,
+	alias varchar(256) NOT NULL,
+	analysis varchar(128) NOT NULL,
+	keyspace varchar(128) NOT NULL,
+	shard varchar(128) NOT NULL,
+	recovery_name varchar(128) NOT NULL,
+	candidate_alias varchar(256) NOT NULL DEFAULT '',
+	message text NOT NULL DEFAULT '',
+	dry_run_timestamp timestamp NOT NULL DEFAULT (''),
+	PRIMARY KEY (dry_run_id)
+)`,
+	`
+CREATE INDEX dry_run_timestamp_idx_recovery_dry_run ON recovery_dry_run (dry_run_timestamp)
+	`,
+	`
 DROP TABLE IF EXISTS database_instance_last_analysis
 `,
 	`
@@ -296,6 +317,8 @@ CREATE TABLE vitess_keyspace (
 	keyspace varchar(128) NOT NULL,
 	keyspace_type smallint(5) NOT NULL,
 	durability_policy varchar(512) NOT NULL,
+	custom_durability_policy text NOT NULL DEFAULT '',
+	errant_gtid_remediation_policy text NOT NULL DEFAULT '',
 	PRIMARY KEY (keyspace)
 )`,
 	`
@@ -77,6 +77,7 @@ func GetReplicationAnalysis(keyspace string, shard string, hints *ReplicationAna
 		vitess_keyspace.keyspace AS keyspace,
 		vitess_keyspace.keyspace_type AS keyspace_type,
 		vitess_keyspace.durability_policy AS durability_policy,
+		vitess_keyspace.custom_durability_policy AS custom_durability_policy,
 		vitess_shard.primary_timestamp AS shard_primary_term_timestamp,
 		primary_instance.read_only AS read_only,
 		MIN(primary_instance.gtid_errant) AS gtid_errant,
@@ -383,7 +384,15 @@ func GetReplicationAnalysis(keyspace string, shard string, hints *ReplicationAna
 				log.Errorf("ignoring keyspace %v because no durability_policy is set. Please set it using SetKeyspaceDurabilityPolicy", a.AnalyzedKeyspace)
 				return nil
 			}
-			durability, err := policy.GetDurabilityPolicy(durabilityPolicy)
+			customDurabilityPolicy, err := readCustomDurabilityPolicy(m.GetString("custom_durability_policy"))
+			if err != nil {
+				log.Errorf("can't read the custom durability policy - %v. Skipping keyspace - %v.", err, a.AnalyzedKeyspace)
+				return nil
+			}
+			durability, err := policy.GetDurabilityPolicyForKeyspace(&topodatapb.Keyspace{
+				DurabilityPolicy:       durabilityPolicy,
+				CustomDurabilityPolicy: customDurabilityPolicy,
+			})
 			if err != nil {
 				log.Errorf("can't get the durability policy %v - %v. Skipping keyspace - %v.", durabilityPolicy, err, a.AnalyzedKeyspace)
 				return nil
@@ -708,6 +708,16 @@ func ReadInstancesWithErrantGTIds(keyspace string, shard string) ([]*Instance, e
 	return readInstancesByCondition(condition, args, "")
 }
 
+// ReadShardInstances reads all the instances of the given keyspace and shard
+func ReadShardInstances(keyspace string, shard string) ([]*Instance, error) {
+	condition := `
+		keyspace = ?
+		AND shard = ?`
+
+	args := sqlutils.Args(keyspace, shard)
+	return readInstancesByCondition(condition, args, "")
+}
+
 // GetKeyspaceShardName gets the keyspace shard name for the given instance key
 func GetKeyspaceShardName(tabletAlias string) (keyspace string, shard string, err error) {
 	query := `SELECT
@@ -356,6 +356,51 @@ func TestReadInstancesWithErrantGTIds(t *testing.T) {
 	}
 }
 
+func TestReadShardInstances(t *testing.T) {
+	tests := []struct {
+		name              string
+		keyspace          string
+		shard             string
+		instancesRequired []string
+	}{
+		{
+			name:              "all instances of the shard",
+			keyspace:          "ks",
+			shard:             "0",
+			instancesRequired: []string{"zone1-0000000100", "zone1-0000000101", "zone1-0000000112", "zone2-0000000200"},
+		}, {
+			name:              "unknown shard",
+			keyspace:          "ks",
+			shard:             "unknown",
+			instancesRequired: nil,
+		}, {
+			name:              "empty keyspace and shard",
+			instancesRequired: nil,
+		},
+	}
+
+	// Clear the database after the test. The easiest way to do that is to run all the initialization commands again.
+	defer func() {
+		db.ClearVTOrcDatabase()
+	}()
+	for _, query := range initialSQL {
+		_, err := db.ExecVTOrc(query)
+		require.NoError(t, err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instances, err := ReadShardInstances(tt.keyspace, tt.shard)
+			require.NoError(t, err)
+			var tabletAliases []string
+			for _, instance := range instances {
+				tabletAliases = append(tabletAliases, instance.InstanceAlias)
+			}
+			require.ElementsMatch(t, tabletAliases, tt.instancesRequired)
+		})
+	}
+}
+
 // TestReadInstanceAllFields tests that we read all the fields for a specific instance.
 func TestReadInstanceAllFields(t *testing.T) {
 	// Clear the database after the test. The easiest way to do that is to run all the initialization commands again.
@@ -19,6 +19,8 @@ package inst
 import (
 	"errors"
 
+	"google.golang.org/protobuf/encoding/prototext"
+
 	"vitess.io/vitess/go/vt/external/golib/sqlutils"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	"vitess.io/vitess/go/vt/topo"
@@ -38,7 +40,9 @@ func ReadKeyspace(keyspaceName string) (*topo.KeyspaceInfo, error) {
 	query := `
 		select
 			keyspace_type,
-			durability_policy
+			durability_policy,
+			custom_durability_policy,
+			errant_gtid_remediation_policy
 		from
 			vitess_keyspace
 		where keyspace=?
@@ -50,6 +54,16 @@ func ReadKeyspace(keyspaceName string) (*topo.KeyspaceInfo, error) {
 	err := db.QueryVTOrc(query, args, func(row sqlutils.RowMap) error {
 		keyspace.KeyspaceType = topodatapb.KeyspaceType(row.GetInt32("keyspace_type"))
 		keyspace.DurabilityPolicy = row.GetString("durability_policy")
+		customDurabilityPolicy, err := readCustomDurabilityPolicy(row.GetString("custom_durability_policy"))
+		if err != nil {
+			return err
+		}
+		keyspace.CustomDurabilityPolicy = customDurabilityPolicy
+		errantGtidRemediationPolicy, err := readErrantGtidRemediationPolicy(row.GetString("errant_gtid_remediation_policy"))
+		if err != nil {
+			return err
+		}
+		keyspace.ErrantGtidRemediationPolicy = errantGtidRemediationPolicy
 		keyspace.SetKeyspaceName(keyspaceName)
 		return nil
 	})
@@ -64,26 +78,70 @@ func ReadKeyspace(keyspaceName string) (*topo.KeyspaceInfo, error) {
 
 // SaveKeyspace saves the keyspace record against the keyspace name.
 func SaveKeyspace(keyspace *topo.KeyspaceInfo) error {
+	var customDurabilityPolicy []byte
+	if keyspace.CustomDurabilityPolicy != nil {
+		var err error
+		customDurabilityPolicy, err = prototext.Marshal(keyspace.CustomDurabilityPolicy)
+		if err != nil {
+			return err
+		}
+	}
+	var errantGtidRemediationPolicy []byte
+	if keyspace.ErrantGtidRemediationPolicy != nil {
+		var err error
+		errantGtidRemediationPolicy, err = prototext.Marshal(keyspace.ErrantGtidRemediationPolicy)
+		if err != nil {
+			return err
+		}
+	}
 	_, err := db.ExecVTOrc(`
 		replace
 			into vitess_keyspace (
-				keyspace, keyspace_type, durability_policy
+				keyspace, keyspace_type, durability_policy, custom_durability_policy, errant_gtid_remediation_policy
 			) values (
-				?, ?, ?
+				?, ?, ?, ?, ?
 			)
 		`,
 		keyspace.KeyspaceName(),
 		int(keyspace.KeyspaceType),
 		keyspace.GetDurabilityPolicy(),
+		string(customDurabilityPolicy),
+		string(errantGtidRemediationPolicy),
 	)
 	return err
 }
 
+// readCustomDurabilityPolicy reads the custom durability policy that SaveKeyspace stored.
+func readCustomDurabilityPolicy(str string) (*topodatapb.CustomDurabilityPolicy, error) {
+	if str == "" {
+		return nil, nil
+	}
+	customDurabilityPolicy := &topodatapb.CustomDurabilityPolicy{}
+	opts := prototext.UnmarshalOptions{DiscardUnknown: true}
+	if err := opts.Unmarshal([]byte(str), customDurabilityPolicy); err != nil {
+		return nil, err
+	}
+	return customDurabilityPolicy, nil
+}
+
+// readErrantGtidRemediationPolicy reads the errant GTID remediation policy that SaveKeyspace stored.
+func readErrantGtidRemediationPolicy(str string) (*topodatapb.ErrantGtidRemediationPolicy, error) {
+	if str == "" {
+		return nil, nil
+	}
+	errantGtidRemediationPolicy := &topodatapb.ErrantGtidRemediationPolicy{}
+	opts := prototext.UnmarshalOptions{DiscardUnknown: true}
+	if err := opts.Unmarshal([]byte(str), errantGtidRemediationPolicy); err != nil {
+		return nil, err
+	}
+	return errantGtidRemediationPolicy, nil
+}
+
 // GetDurabilityPolicy gets the durability policy for the given keyspace.
 func GetDurabilityPolicy(keyspace string) (policy.Durabler, error) {
 	ki, err := ReadKeyspace(keyspace)
 	if err != nil {
 		return nil, err
 	}
-	return policy.GetDurabilityPolicy(ki.DurabilityPolicy)
+	return policy.GetDurabilityPolicyForKeyspace(ki.Keyspace)
 }
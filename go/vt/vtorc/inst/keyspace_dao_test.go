@@ -80,6 +80,46 @@ func TestSaveAndReadKeyspace(t *testing.T) {
 				DurabilityPolicy: policy.DurabilityNone,
 			},
 			semiSyncAckersWanted: 0,
+		}, {
+			name:         "Success with custom durability",
+			keyspaceName: "ks6",
+			keyspace: &topodatapb.Keyspace{
+				KeyspaceType:     topodatapb.KeyspaceType_NORMAL,
+				DurabilityPolicy: policy.DurabilityCustom,
+				CustomDurabilityPolicy: &topodatapb.CustomDurabilityPolicy{
+					PromotionRules: []*topodatapb.CustomDurabilityPolicy_PromotionRule{{
+						Selector: &topodatapb.CustomDurabilityPolicy_TabletSelector{Tags: map[string]string{"az": "az3"}},
+						Rule:     "must_not",
+					}},
+					SemiSyncAckers:    2,
+					AckerDistinctTags: []string{"az"},
+				},
+			},
+			keyspaceWanted:       nil,
+			semiSyncAckersWanted: 2,
+		}, {
+			name:         "Success with errant GTID remediation policy",
+			keyspaceName: "ks8",
+			keyspace: &topodatapb.Keyspace{
+				KeyspaceType:     topodatapb.KeyspaceType_NORMAL,
+				DurabilityPolicy: policy.DurabilitySemiSync,
+				ErrantGtidRemediationPolicy: &topodatapb.ErrantGtidRemediationPolicy{
+					InjectEmptyTransactions: true,
+					RestoreFromBackup:       true,
+					MaxErrantTransactions:   5,
+				},
+			},
+			keyspaceWanted:       nil,
+			semiSyncAckersWanted: 1,
+		}, {
+			name:         "Custom durability without a custom durability policy",
+			keyspaceName: "ks7",
+			keyspace: &topodatapb.Keyspace{
+				KeyspaceType:     topodatapb.KeyspaceType_NORMAL,
+				DurabilityPolicy: policy.DurabilityCustom,
+			},
+			keyspaceWanted:        nil,
+			errInDurabilityPolicy: "custom durability policy not set",
 		}, {
 			name:           "No keyspace found",
 			keyspaceName:   "ks5",
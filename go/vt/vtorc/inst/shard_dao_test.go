@@ -115,3 +115,24 @@ func TestSaveReadAndDeleteShard(t *testing.T) {
 		})
 	}
 }
+
+func TestReadAllShardNames(t *testing.T) {
+	// Clear the database after the test. The easiest way to do that is to run all the initialization commands again.
+	defer func() {
+		db.ClearVTOrcDatabase()
+	}()
+
+	shardNames, err := ReadAllShardNames()
+	require.NoError(t, err)
+	require.Empty(t, shardNames)
+
+	for _, shard := range []struct{ keyspace, shard string }{{"ks1", "80-"}, {"ks1", "-80"}, {"ks2", "0"}} {
+		require.NoError(t, SaveShard(topo.NewShardInfo(shard.keyspace, shard.shard, &topodatapb.Shard{}, nil)))
+	}
+	shardNames, err = ReadAllShardNames()
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"ks1": {"-80", "80-"},
+		"ks2": {"0"},
+	}, shardNames)
+}
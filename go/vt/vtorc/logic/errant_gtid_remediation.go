@@ -0,0 +1,189 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil/policy"
+	"vitess.io/vitess/go/vt/vtorc/config"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// errantGTIDRemediationAuditType is the audit type of the steps of the errant GTID remediations.
+const errantGTIDRemediationAuditType = "errant-gtid-remediation"
+
+// readErrantGtidRemediationPolicy returns the errant GTID remediation policy of the keyspace of the given tablet, if
+// it allows VTOrc to remediate errant GTIDs.
+func readErrantGtidRemediationPolicy(tabletAlias string) *topodatapb.ErrantGtidRemediationPolicy {
+	keyspace, _, err := inst.GetKeyspaceShardName(tabletAlias)
+	if err != nil || keyspace == "" {
+		return nil
+	}
+	keyspaceInfo, err := inst.ReadKeyspace(keyspace)
+	if err != nil {
+		log.Errorf("Failed to read the keyspace %v of %v: %v", keyspace, tabletAlias, err)
+		return nil
+	}
+	remediationPolicy := keyspaceInfo.GetErrantGtidRemediationPolicy()
+	if !remediationPolicy.GetInjectEmptyTransactions() && !remediationPolicy.GetRestoreFromBackup() {
+		return nil
+	}
+	return remediationPolicy
+}
+
+// remediateErrantGTID inspects the errant transactions of a replica in its binary logs, and remediates them as the
+// errant GTID remediation policy of its keyspace allows. When none of them writes any data, empty transactions are
+// injected on the primary for the errant GTIDs, so that they are not errant anymore. Otherwise, the replica is
+// restored from a backup. The replicas that cannot be remediated are only fenced, like recoverErrantGTIDDetected does.
+func remediateErrantGTID(ctx context.Context, analysisEntry *inst.ReplicationAnalysis, logger *log.PrefixedLogger) (recoveryAttempted bool, topologyRecovery *TopologyRecovery, err error) {
+	topologyRecovery, err = AttemptRecoveryRegistration(analysisEntry)
+	if topologyRecovery == nil {
+		message := fmt.Sprintf("found an active or recent recovery on %+v. Will not issue another remediateErrantGTID.", analysisEntry.AnalyzedInstanceAlias)
+		logger.Warning(message)
+		_ = AuditTopologyRecovery(topologyRecovery, message)
+		return false, nil, err
+	}
+	logger.Infof("Analysis: %v, will remediate the errant GTIDs of tablet %+v", analysisEntry.Analysis, analysisEntry.AnalyzedInstanceAlias)
+	// This has to be done in the end; whether successful or not, we should mark that the recovery is done.
+	// So that after the active period passes, we are able to run other recoveries.
+	defer func() {
+		_ = resolveRecovery(topologyRecovery, nil)
+	}()
+	audit := func(format string, args ...any) {
+		message := fmt.Sprintf(format, args...)
+		logger.Info(message)
+		_ = AuditTopologyRecovery(topologyRecovery, message)
+		_ = inst.AuditOperation(errantGTIDRemediationAuditType, analysisEntry.AnalyzedInstanceAlias, message)
+	}
+
+	analyzedTablet, err := inst.ReadTablet(analysisEntry.AnalyzedInstanceAlias)
+	if err != nil {
+		return false, topologyRecovery, err
+	}
+	primaryTablet, err := shardPrimary(analyzedTablet.Keyspace, analyzedTablet.Shard)
+	if err != nil {
+		logger.Infof("Could not compute primary for %v/%v", analyzedTablet.Keyspace, analyzedTablet.Shard)
+		return false, topologyRecovery, err
+	}
+	durabilityPolicy, err := inst.GetDurabilityPolicy(analyzedTablet.Keyspace)
+	if err != nil {
+		logger.Infof("Could not read the durability policy for %v/%v", analyzedTablet.Keyspace, analyzedTablet.Shard)
+		return false, topologyRecovery, err
+	}
+	keyspaceInfo, err := inst.ReadKeyspace(analyzedTablet.Keyspace)
+	if err != nil {
+		return false, topologyRecovery, err
+	}
+	remediationPolicy := keyspaceInfo.GetErrantGtidRemediationPolicy()
+	fence := func(reason string) (bool, *TopologyRecovery, error) {
+		if !config.ConvertTabletWithErrantGTIDs() {
+			audit("not remediating the errant GTIDs of %v: %v", analysisEntry.AnalyzedInstanceAlias, reason)
+			return false, topologyRecovery, nil
+		}
+		audit("fencing %v: %v", analysisEntry.AnalyzedInstanceAlias, reason)
+		return true, topologyRecovery, changeTabletType(ctx, analyzedTablet, topodatapb.TabletType_DRAINED, policy.IsReplicaSemiSync(durabilityPolicy, primaryTablet, analyzedTablet))
+	}
+
+	instance, found, err := inst.ReadInstance(analysisEntry.AnalyzedInstanceAlias)
+	if err != nil || !found {
+		logger.Errorf("Failed to read instance %s, aborting recovery", analysisEntry.AnalyzedInstanceAlias)
+		return false, topologyRecovery, err
+	}
+	errantGTIDs := instance.GtidErrant
+	if errantGTIDs == "" {
+		audit("%v has no errant GTIDs anymore", analysisEntry.AnalyzedInstanceAlias)
+		return false, topologyRecovery, nil
+	}
+	count, err := replication.GTIDCount(errantGTIDs)
+	if err != nil {
+		return false, topologyRecovery, err
+	}
+	audit("%v has %d errant transactions: %v", analysisEntry.AnalyzedInstanceAlias, count, errantGTIDs)
+	if maxErrantTransactions := remediationPolicy.GetMaxErrantTransactions(); maxErrantTransactions > 0 && uint64(count) > maxErrantTransactions {
+		return fence(fmt.Sprintf("it has more than %d errant transactions", maxErrantTransactions))
+	}
+
+	tmcCtx, tmcCancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+	defer tmcCancel()
+	inspection, err := tmc.InspectBinlogTransactions(tmcCtx, analyzedTablet, errantGTIDs)
+	if err != nil {
+		audit("failed to inspect the errant transactions of %v: %v", analysisEntry.AnalyzedInstanceAlias, err)
+		return fence("its errant transactions could not be inspected")
+	}
+	audit("inspected the errant transactions of %v: empty: %q, writes: %q, purged from the binary logs: %q",
+		analysisEntry.AnalyzedInstanceAlias, inspection.EmptyGtidSet, inspection.WriteGtidSet, inspection.MissingGtidSet)
+
+	switch {
+	case inspection.WriteGtidSet == "" && inspection.MissingGtidSet == "" && remediationPolicy.GetInjectEmptyTransactions():
+		audit("injecting empty transactions for %v on the primary %v", errantGTIDs, topoproto.TabletAliasString(primaryTablet.Alias))
+		tmcCtx, tmcCancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+		defer tmcCancel()
+		if err = tmc.InjectEmptyTransactions(tmcCtx, primaryTablet, errantGTIDs); err != nil {
+			audit("failed to inject empty transactions on the primary %v: %v", topoproto.TabletAliasString(primaryTablet.Alias), err)
+			return true, topologyRecovery, err
+		}
+		audit("injected empty transactions for %v on the primary %v", errantGTIDs, topoproto.TabletAliasString(primaryTablet.Alias))
+		return true, topologyRecovery, nil
+	case remediationPolicy.GetRestoreFromBackup():
+		audit("restoring %v from a backup", analysisEntry.AnalyzedInstanceAlias)
+		return true, topologyRecovery, restoreFromBackup(analyzedTablet, audit)
+	case inspection.WriteGtidSet != "" || inspection.MissingGtidSet != "":
+		return fence("some of its errant transactions may write data, and the policy does not allow restoring it from a backup")
+	default:
+		return fence("the policy does not allow injecting empty transactions")
+	}
+}
+
+// restoreFromBackup starts restoring the tablet from a backup. The restore runs in the background, since it can take
+// much longer than the shard lock should be held, and its outcome is audited once it is done.
+func restoreFromBackup(tablet *topodatapb.Tablet, audit func(format string, args ...any)) error {
+	alias := topoproto.TabletAliasString(tablet.Alias)
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := tmc.RestoreFromBackup(ctx, tablet, &tabletmanagerdatapb.RestoreFromBackupRequest{})
+	if err != nil {
+		cancel()
+		audit("failed to restore %v from a backup: %v", alias, err)
+		return err
+	}
+	go func() {
+		defer cancel()
+		for {
+			event, err := stream.Recv()
+			switch {
+			case errors.Is(err, io.EOF):
+				_ = inst.AuditOperation(errantGTIDRemediationAuditType, alias, fmt.Sprintf("restored %v from a backup", alias))
+				return
+			case err != nil:
+				_ = inst.AuditOperation(errantGTIDRemediationAuditType, alias, fmt.Sprintf("failed to restore %v from a backup: %v", alias, err))
+				return
+			}
+			log.Infof("Restore of %v: %v", alias, event.GetValue())
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/external/golib/sqlutils"
+	"vitess.io/vitess/go/vt/log"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver/testutil"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil/policy"
+	"vitess.io/vitess/go/vt/vtorc/config"
+	"vitess.io/vitess/go/vt/vtorc/db"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+)
+
+func TestRemediateErrantGTID(t *testing.T) {
+	errantGTIDs := "729a4cc4-8680-11ed-a104-47706090afbd:1-30,8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1-3"
+	tests := []struct {
+		name                         string
+		remediationPolicy            *topodatapb.ErrantGtidRemediationPolicy
+		convertTabletWithErrantGTIDs bool
+		inspection                   *tabletmanagerdatapb.InspectBinlogTransactionsResponse
+		wantRecoveryAttempted        bool
+		wantTabletType               topodatapb.TabletType
+		wantAudit                    string
+	}{
+		{
+			name:              "empty errant transactions are injected on the primary",
+			remediationPolicy: &topodatapb.ErrantGtidRemediationPolicy{InjectEmptyTransactions: true},
+			inspection: &tabletmanagerdatapb.InspectBinlogTransactionsResponse{
+				EmptyGtidSet: "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1-3",
+			},
+			wantRecoveryAttempted: true,
+			wantTabletType:        topodatapb.TabletType_REPLICA,
+			wantAudit:             "injected empty transactions for 8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1-3 on the primary zone1-0000000100",
+		}, {
+			name:                         "errant transactions that write data are fenced",
+			remediationPolicy:            &topodatapb.ErrantGtidRemediationPolicy{InjectEmptyTransactions: true},
+			convertTabletWithErrantGTIDs: true,
+			inspection: &tabletmanagerdatapb.InspectBinlogTransactionsResponse{
+				EmptyGtidSet: "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1-2",
+				WriteGtidSet: "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:3",
+			},
+			wantRecoveryAttempted: true,
+			wantTabletType:        topodatapb.TabletType_DRAINED,
+			wantAudit:             "fencing zone1-0000000101: some of its errant transactions may write data, and the policy does not allow restoring it from a backup",
+		}, {
+			name:              "purged errant transactions are not injected",
+			remediationPolicy: &topodatapb.ErrantGtidRemediationPolicy{InjectEmptyTransactions: true},
+			inspection: &tabletmanagerdatapb.InspectBinlogTransactionsResponse{
+				MissingGtidSet: "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1-3",
+			},
+			wantRecoveryAttempted: false,
+			wantTabletType:        topodatapb.TabletType_REPLICA,
+			wantAudit:             "not remediating the errant GTIDs of zone1-0000000101: some of its errant transactions may write data, and the policy does not allow restoring it from a backup",
+		}, {
+			name:                         "too many errant transactions",
+			remediationPolicy:            &topodatapb.ErrantGtidRemediationPolicy{InjectEmptyTransactions: true, MaxErrantTransactions: 2},
+			convertTabletWithErrantGTIDs: true,
+			wantRecoveryAttempted:        true,
+			wantTabletType:               topodatapb.TabletType_DRAINED,
+			wantAudit:                    "fencing zone1-0000000101: it has more than 2 errant transactions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Clear the database after the test. The easiest way to do that is to run all the initialization commands again.
+			defer func() {
+				db.ClearVTOrcDatabase()
+			}()
+			oldTs := ts
+			oldTmc := tmc
+			defer func() {
+				ts = oldTs
+				tmc = oldTmc
+			}()
+			auditToBackendVal := config.GetAuditToBackend()
+			config.SetAuditToBackend(true)
+			defer config.SetAuditToBackend(auditToBackendVal)
+			convertErrantVal := config.ConvertTabletWithErrantGTIDs()
+			config.SetConvertTabletWithErrantGTIDs(tt.convertTabletWithErrantGTIDs)
+			defer config.SetConvertTabletWithErrantGTIDs(convertErrantVal)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ts = memorytopo.NewServer(ctx, "zone1")
+			defer ts.Close()
+
+			keyspace := &topodatapb.Keyspace{
+				DurabilityPolicy:            policy.DurabilityNone,
+				ErrantGtidRemediationPolicy: tt.remediationPolicy,
+			}
+			require.NoError(t, ts.CreateKeyspace(ctx, "ks", keyspace))
+			require.NoError(t, ts.CreateShard(ctx, "ks", "0"))
+			keyspaceInfo := &topo.KeyspaceInfo{Keyspace: keyspace}
+			keyspaceInfo.SetKeyspaceName("ks")
+			require.NoError(t, inst.SaveKeyspace(keyspaceInfo))
+
+			newTablet := func(uid uint32, tabletType topodatapb.TabletType) *topodatapb.Tablet {
+				return &topodatapb.Tablet{
+					Alias:         &topodatapb.TabletAlias{Cell: "zone1", Uid: uid},
+					Hostname:      "localhost",
+					MysqlHostname: "localhost",
+					MysqlPort:     int32(6700 + uid),
+					Keyspace:      "ks",
+					Shard:         "0",
+					Type:          tabletType,
+				}
+			}
+			primary := newTablet(100, topodatapb.TabletType_PRIMARY)
+			replica := newTablet(101, topodatapb.TabletType_REPLICA)
+			for _, tablet := range []*topodatapb.Tablet{primary, replica} {
+				require.NoError(t, ts.CreateTablet(ctx, tablet))
+				require.NoError(t, inst.SaveTablet(tablet))
+			}
+			_, err := db.ExecVTOrc(`insert into database_instance (alias, hostname, port, tablet_type, cell, server_id, version, binlog_format, log_bin, log_replica_updates,
+binary_log_file, binary_log_pos, source_host, source_port, replica_net_timeout, heartbeat_interval, replica_sql_running, replica_io_running, source_log_file,
+read_source_log_pos, relay_source_log_file, exec_source_log_pos, executed_gtid_set, gtid_errant, last_checked, last_seen)
+values (?, ?, ?, ?, 'zone1', ?, '8.0.31', 'ROW', 1, 1, '', 0, '', 0, 8, 4, 1, 1, '', 0, '', 0, ?, ?, datetime('now'), datetime('now'))`,
+				topoproto.TabletAliasString(replica.Alias), replica.MysqlHostname, replica.MysqlPort, replica.Type, replica.Alias.Uid, errantGTIDs, "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1-3")
+			require.NoError(t, err)
+
+			tmc = &testutil.TabletManagerClient{
+				TopoServer: ts,
+				InspectBinlogTransactionsResults: map[string]struct {
+					Response *tabletmanagerdatapb.InspectBinlogTransactionsResponse
+					Error    error
+				}{
+					"zone1-0000000101": {Response: tt.inspection},
+				},
+				InjectEmptyTransactionsResults: map[string]error{
+					"zone1-0000000100": nil,
+				},
+			}
+
+			recoveryAttempted, topologyRecovery, err := remediateErrantGTID(ctx, &inst.ReplicationAnalysis{
+				AnalyzedInstanceAlias: "zone1-0000000101",
+				AnalyzedKeyspace:      "ks",
+				AnalyzedShard:         "0",
+				Analysis:              inst.ErrantGTIDDetected,
+			}, log.NewPrefixedLogger("prefix"))
+			require.NoError(t, err)
+			require.NotNil(t, topologyRecovery)
+			require.Equal(t, tt.wantRecoveryAttempted, recoveryAttempted)
+
+			tablet, err := ts.GetTablet(ctx, replica.Alias)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantTabletType, tablet.Type)
+
+			var audits []string
+			err = db.QueryVTOrc("select message from audit where audit_type = ? order by audit_id", sqlutils.Args(errantGTIDRemediationAuditType), func(m sqlutils.RowMap) error {
+				audits = append(audits, m.GetString("message"))
+				return nil
+			})
+			require.NoError(t, err)
+			require.Contains(t, audits, tt.wantAudit)
+		})
+	}
+}
@@ -0,0 +1,464 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtorc/config"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+)
+
+const (
+	// leaderElectionAuditType is the audit type of the changes of the leader of the VTOrc instances.
+	leaderElectionAuditType = "leader-election"
+	// leaderElectionRetryInterval is the time to wait before running for leadership again after an error.
+	leaderElectionRetryInterval = 5 * time.Second
+	// maxLeadershipTransitions is the number of leadership transitions of each shard kept in memory.
+	maxLeadershipTransitions = 20
+)
+
+var (
+	isLeaderGauge = stats.NewGaugesWithMultiLabels("LeaderElectionIsLeader", "Whether this VTOrc instance is the leader of the election of a shard, and runs its recoveries", []string{
+		"Keyspace",
+		"Shard",
+	})
+
+	leadershipMu sync.Mutex
+	// leadershipID is the id of this VTOrc instance in the leader elections, which is set once they are opened.
+	leadershipID string
+	// shardElections are the leader elections of the shards that this VTOrc instance watches, by keyspace/shard.
+	shardElections = make(map[string]*shardElection)
+)
+
+// shardElection is the leader election of the VTOrc instances that watch a shard. Every VTOrc instance runs for the
+// leadership of each shard it watches, so that exactly one of them runs the recoveries of the shard, whichever
+// other shards they watch.
+type shardElection struct {
+	keyspace      string
+	shard         string
+	participation topo.LeaderParticipation
+	// isLeader is whether this VTOrc instance is the leader of the shard.
+	isLeader atomic.Bool
+
+	// The fields below are protected by leadershipMu.
+	leaderID    string
+	leaderSince time.Time
+	transitions []LeadershipTransition
+	// publishedProblems is the last list of problems of the shard published by this VTOrc instance while it was
+	// the leader.
+	publishedProblems []byte
+}
+
+// LeadershipTransition is a change of the leader of a shard, as observed by this VTOrc instance.
+type LeadershipTransition struct {
+	Time     time.Time
+	LeaderID string
+}
+
+// ShardLeadership describes the leader election of a shard, as observed by this VTOrc instance.
+type ShardLeadership struct {
+	Keyspace    string
+	Shard       string
+	IsLeader    bool
+	LeaderID    string
+	LeaderSince time.Time
+	Transitions []LeadershipTransition
+}
+
+// LeadershipStatus describes the leader elections of this VTOrc instance. When the leader election is disabled,
+// every VTOrc instance runs the recoveries of the shards it watches.
+type LeadershipStatus struct {
+	Enabled bool
+	ID      string
+	Shards  []ShardLeadership
+}
+
+// SharedProblem is a problem detected by the leader of a shard.
+type SharedProblem struct {
+	TabletAlias string
+	Analysis    inst.AnalysisCode
+	Description string
+}
+
+// SharedAnalysis is the list of the problems that the leader of a shard detected the last time it analyzed the
+// shard, which it shares with the other VTOrc instances through the topology server.
+type SharedAnalysis struct {
+	Keyspace string
+	Shard    string
+	LeaderID string
+	Problems []SharedProblem
+}
+
+// IsLeader reports whether this VTOrc instance should run the recoveries of the given shard. This is always the case
+// when the leader election is disabled.
+func IsLeader(keyspace string, shard string) bool {
+	if !config.GetLeaderElection() {
+		return true
+	}
+	leadershipMu.Lock()
+	election := shardElections[topoproto.KeyspaceShardString(keyspace, shard)]
+	leadershipMu.Unlock()
+	return election != nil && election.isLeader.Load()
+}
+
+// leaderElectionID returns the id of this VTOrc instance in the leader election.
+func leaderElectionID() string {
+	if id := config.GetLeaderElectionID(); id != "" {
+		return id
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		log.Errorf("Failed to get the hostname for the leader election: %v", err)
+	}
+	return fmt.Sprintf("%s:%d", hostname, servenv.Port())
+}
+
+// leaderElectionPath returns the path of the leader election of a shard.
+func leaderElectionPath(keyspace string, shard string) string {
+	return path.Join("vtorc", keyspace, shard)
+}
+
+// sharedAnalysisPath returns the path of the shared analysis of a shard in the global topology.
+func sharedAnalysisPath(keyspace string, shard string) string {
+	return path.Join(leaderElectionPath(keyspace, shard), "analysis")
+}
+
+// OpenLeaderElection makes this VTOrc instance run for the leadership of the shards it watches, if the leader
+// election is enabled.
+func OpenLeaderElection(ctx context.Context) error {
+	if !config.GetLeaderElection() {
+		return nil
+	}
+	leadershipMu.Lock()
+	leadershipID = leaderElectionID()
+	leadershipMu.Unlock()
+	return refreshLeaderElections(ctx)
+}
+
+// refreshLeaderElections makes this VTOrc instance run for the leadership of the shards it started watching, gives up
+// the shards it stopped watching, and records the current leader of each shard.
+func refreshLeaderElections(ctx context.Context) error {
+	leadershipMu.Lock()
+	id := leadershipID
+	leadershipMu.Unlock()
+	if id == "" {
+		return nil
+	}
+
+	shardNames, err := inst.ReadAllShardNames()
+	if err != nil {
+		return err
+	}
+	watched := make(map[string]bool)
+	for keyspace, shards := range shardNames {
+		for _, shard := range shards {
+			watched[topoproto.KeyspaceShardString(keyspace, shard)] = true
+			if err := joinShardElection(ctx, id, keyspace, shard); err != nil {
+				log.Errorf("Failed to run for the leadership of shard %v: %v", topoproto.KeyspaceShardString(keyspace, shard), err)
+			}
+		}
+	}
+
+	leadershipMu.Lock()
+	var stale []*shardElection
+	var elections []*shardElection
+	for name, election := range shardElections {
+		if !watched[name] {
+			delete(shardElections, name)
+			stale = append(stale, election)
+			continue
+		}
+		elections = append(elections, election)
+	}
+	leadershipMu.Unlock()
+	for _, election := range stale {
+		log.Infof("Giving up the leadership of shard %v, which is no longer watched", topoproto.KeyspaceShardString(election.keyspace, election.shard))
+		election.participation.Stop()
+	}
+	for _, election := range elections {
+		election.refreshLeader(ctx)
+	}
+	return nil
+}
+
+// joinShardElection makes this VTOrc instance run for the leadership of a shard, unless it already does.
+func joinShardElection(ctx context.Context, id string, keyspace string, shard string) error {
+	name := topoproto.KeyspaceShardString(keyspace, shard)
+	leadershipMu.Lock()
+	_, ok := shardElections[name]
+	leadershipMu.Unlock()
+	if ok {
+		return nil
+	}
+
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return err
+	}
+	participation, err := conn.NewLeaderParticipation(leaderElectionPath(keyspace, shard), id)
+	if err != nil {
+		return err
+	}
+	election := &shardElection{
+		keyspace:      keyspace,
+		shard:         shard,
+		participation: participation,
+	}
+	leadershipMu.Lock()
+	shardElections[name] = election
+	leadershipMu.Unlock()
+
+	log.Infof("Running for the leadership of shard %v as %v", name, id)
+	go election.run(id)
+	return nil
+}
+
+// run waits to become the leader of the shard, until the participation is stopped.
+func (election *shardElection) run(id string) {
+	for {
+		leaderCtx, err := election.participation.WaitForLeadership()
+		switch {
+		case err == nil:
+		case topo.IsErrType(err, topo.Interrupted):
+			return
+		default:
+			log.Errorf("Failed to run for the leadership of shard %v, will retry in %v: %v",
+				topoproto.KeyspaceShardString(election.keyspace, election.shard), leaderElectionRetryInterval, err)
+			time.Sleep(leaderElectionRetryInterval)
+			continue
+		}
+
+		election.setLeader(id, true)
+		<-leaderCtx.Done()
+		election.setLeader(id, false)
+	}
+}
+
+// setLeader records whether this VTOrc instance is the leader of the shard.
+func (election *shardElection) setLeader(id string, leader bool) {
+	leadershipMu.Lock()
+	defer leadershipMu.Unlock()
+
+	election.isLeader.Store(leader)
+	labels := []string{election.keyspace, election.shard}
+	name := topoproto.KeyspaceShardString(election.keyspace, election.shard)
+	if leader {
+		isLeaderGauge.Set(labels, 1)
+		election.recordLeaderLocked(id)
+		_ = inst.AuditOperation(leaderElectionAuditType, "", fmt.Sprintf("%v became the leader of shard %v", id, name))
+		return
+	}
+	isLeaderGauge.Set(labels, 0)
+	election.publishedProblems = nil
+	_ = inst.AuditOperation(leaderElectionAuditType, "", fmt.Sprintf("%v lost the leadership of shard %v", id, name))
+}
+
+// recordLeaderLocked records the current leader of the shard. leadershipMu must be held.
+func (election *shardElection) recordLeaderLocked(leaderID string) {
+	if leaderID == election.leaderID {
+		return
+	}
+	now := time.Now()
+	election.leaderID = leaderID
+	election.leaderSince = now
+	election.transitions = append(election.transitions, LeadershipTransition{Time: now, LeaderID: leaderID})
+	if len(election.transitions) > maxLeadershipTransitions {
+		election.transitions = election.transitions[len(election.transitions)-maxLeadershipTransitions:]
+	}
+}
+
+// refreshLeader reads the current leader of the shard from the topology server.
+func (election *shardElection) refreshLeader(ctx context.Context) {
+	leaderID, err := election.participation.GetCurrentLeaderID(ctx)
+	if err != nil {
+		log.Errorf("Failed to read the leader of shard %v: %v", topoproto.KeyspaceShardString(election.keyspace, election.shard), err)
+		return
+	}
+
+	leadershipMu.Lock()
+	defer leadershipMu.Unlock()
+	// A new leader is only recorded by itself once it has won the election.
+	if leaderID == leadershipID && !election.isLeader.Load() {
+		return
+	}
+	election.recordLeaderLocked(leaderID)
+}
+
+// GetLeadershipStatus returns the state of the leader elections, as observed by this VTOrc instance.
+func GetLeadershipStatus(ctx context.Context) LeadershipStatus {
+	leadershipMu.Lock()
+	elections := make([]*shardElection, 0, len(shardElections))
+	for _, election := range shardElections {
+		elections = append(elections, election)
+	}
+	leadershipMu.Unlock()
+	for _, election := range elections {
+		election.refreshLeader(ctx)
+	}
+
+	leadershipMu.Lock()
+	defer leadershipMu.Unlock()
+	status := LeadershipStatus{
+		Enabled: config.GetLeaderElection(),
+		ID:      leadershipID,
+		Shards:  []ShardLeadership{},
+	}
+	for _, election := range elections {
+		status.Shards = append(status.Shards, ShardLeadership{
+			Keyspace:    election.keyspace,
+			Shard:       election.shard,
+			IsLeader:    election.isLeader.Load(),
+			LeaderID:    election.leaderID,
+			LeaderSince: election.leaderSince,
+			Transitions: slices.Clone(election.transitions),
+		})
+	}
+	slices.SortFunc(status.Shards, func(a, b ShardLeadership) int {
+		return strings.Compare(topoproto.KeyspaceShardString(a.Keyspace, a.Shard), topoproto.KeyspaceShardString(b.Keyspace, b.Shard))
+	})
+	return status
+}
+
+// stopLeaderElection stops running for the leadership of the shards, and gives up the ones this VTOrc instance leads.
+func stopLeaderElection() {
+	leadershipMu.Lock()
+	elections := shardElections
+	shardElections = make(map[string]*shardElection)
+	leadershipID = ""
+	leadershipMu.Unlock()
+	for _, election := range elections {
+		election.participation.Stop()
+	}
+}
+
+// publishSharedAnalysis shares the problems of the given analysis with the other VTOrc instances, for the shards that
+// this VTOrc instance leads. The topology server is only written to when the problems of a shard change.
+func publishSharedAnalysis(ctx context.Context, replicationAnalysis []*inst.ReplicationAnalysis) error {
+	if !config.GetLeaderElection() {
+		return nil
+	}
+
+	problems := make(map[string][]SharedProblem)
+	for _, analysisEntry := range replicationAnalysis {
+		if analysisEntry.Analysis == inst.NoProblem {
+			continue
+		}
+		name := topoproto.KeyspaceShardString(analysisEntry.AnalyzedKeyspace, analysisEntry.AnalyzedShard)
+		problems[name] = append(problems[name], SharedProblem{
+			TabletAlias: analysisEntry.AnalyzedInstanceAlias,
+			Analysis:    analysisEntry.Analysis,
+			Description: analysisEntry.Description,
+		})
+	}
+
+	leadershipMu.Lock()
+	defer leadershipMu.Unlock()
+
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return err
+	}
+	for name, election := range shardElections {
+		if !election.isLeader.Load() {
+			continue
+		}
+		sharedAnalysis := SharedAnalysis{
+			Keyspace: election.keyspace,
+			Shard:    election.shard,
+			LeaderID: leadershipID,
+			Problems: problems[name],
+		}
+		if sharedAnalysis.Problems == nil {
+			sharedAnalysis.Problems = []SharedProblem{}
+		}
+		slices.SortFunc(sharedAnalysis.Problems, func(a, b SharedProblem) int {
+			return strings.Compare(a.TabletAlias+string(a.Analysis), b.TabletAlias+string(b.Analysis))
+		})
+		data, err := json.Marshal(sharedAnalysis)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(data, election.publishedProblems) {
+			continue
+		}
+		if _, err := conn.Update(ctx, sharedAnalysisPath(election.keyspace, election.shard), data, nil); err != nil {
+			return err
+		}
+		election.publishedProblems = data
+	}
+	return nil
+}
+
+// ReadSharedAnalysis returns the problems that the leaders of the shards that this VTOrc instance watches last
+// shared, optionally filtered by keyspace and shard.
+func ReadSharedAnalysis(ctx context.Context, keyspace string, shard string) ([]*SharedAnalysis, error) {
+	sharedAnalyses := []*SharedAnalysis{}
+	if !config.GetLeaderElection() {
+		return sharedAnalyses, nil
+	}
+
+	leadershipMu.Lock()
+	var names []string
+	for name, election := range shardElections {
+		if (keyspace != "" && election.keyspace != keyspace) || (shard != "" && election.shard != shard) {
+			continue
+		}
+		names = append(names, name)
+	}
+	leadershipMu.Unlock()
+	slices.Sort(names)
+
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		electionKeyspace, electionShard, err := topoproto.ParseKeyspaceShard(name)
+		if err != nil {
+			return nil, err
+		}
+		data, _, err := conn.Get(ctx, sharedAnalysisPath(electionKeyspace, electionShard))
+		switch {
+		case topo.IsErrType(err, topo.NoNode):
+			continue
+		case err != nil:
+			return nil, err
+		}
+		sharedAnalysis := &SharedAnalysis{}
+		if err := json.Unmarshal(data, sharedAnalysis); err != nil {
+			return nil, err
+		}
+		sharedAnalyses = append(sharedAnalyses, sharedAnalysis)
+	}
+	return sharedAnalyses, nil
+}
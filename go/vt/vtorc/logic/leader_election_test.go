@@ -0,0 +1,154 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtorc/config"
+	"vitess.io/vitess/go/vt/vtorc/db"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestLeaderElection(t *testing.T) {
+	// Clear the database after the test. The easiest way to do that is to run all the initialization commands again.
+	defer func() {
+		db.ClearVTOrcDatabase()
+	}()
+	oldTs := ts
+	defer func() {
+		ts = oldTs
+	}()
+	oldLeaderElection := config.GetLeaderElection()
+	config.SetLeaderElection(true)
+	defer config.SetLeaderElection(oldLeaderElection)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts = memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+	for _, shard := range []string{"-80", "80-"} {
+		require.NoError(t, inst.SaveShard(topo.NewShardInfo("ks", shard, &topodatapb.Shard{}, nil)))
+	}
+
+	// Another VTOrc instance, which only watches the first shard, is its leader first.
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	require.NoError(t, err)
+	otherParticipation, err := conn.NewLeaderParticipation(leaderElectionPath("ks", "-80"), "other-vtorc:15000")
+	require.NoError(t, err)
+	_, err = otherParticipation.WaitForLeadership()
+	require.NoError(t, err)
+
+	require.NoError(t, OpenLeaderElection(ctx))
+	defer stopLeaderElection()
+	require.False(t, IsLeader("ks", "-80"))
+	require.Eventually(t, func() bool {
+		return IsLeader("ks", "80-")
+	}, 5*time.Second, 10*time.Millisecond)
+	require.False(t, IsLeader("ks", "c0-"))
+	status := GetLeadershipStatus(ctx)
+	require.True(t, status.Enabled)
+	require.Len(t, status.Shards, 2)
+	require.Equal(t, "-80", status.Shards[0].Shard)
+	require.False(t, status.Shards[0].IsLeader)
+	require.Equal(t, "other-vtorc:15000", status.Shards[0].LeaderID)
+	require.Equal(t, "80-", status.Shards[1].Shard)
+	require.True(t, status.Shards[1].IsLeader)
+	require.Equal(t, status.ID, status.Shards[1].LeaderID)
+
+	// Only the problems of the shards this instance leads are shared.
+	analysis := []*inst.ReplicationAnalysis{{
+		AnalyzedInstanceAlias: "zone1-0000000100",
+		AnalyzedKeyspace:      "ks",
+		AnalyzedShard:         "-80",
+		Analysis:              inst.DeadPrimary,
+		Description:           "Primary cannot be reached by vtorc and none of its replicas is replicating",
+	}, {
+		AnalyzedInstanceAlias: "zone1-0000000200",
+		AnalyzedKeyspace:      "ks",
+		AnalyzedShard:         "80-",
+		Analysis:              inst.ReplicationStopped,
+		Description:           "Replication is stopped",
+	}, {
+		AnalyzedInstanceAlias: "zone1-0000000201",
+		AnalyzedKeyspace:      "ks",
+		AnalyzedShard:         "80-",
+		Analysis:              inst.NoProblem,
+	}}
+	require.NoError(t, publishSharedAnalysis(ctx, analysis))
+	sharedAnalysis, err := ReadSharedAnalysis(ctx, "", "")
+	require.NoError(t, err)
+	require.Equal(t, []*SharedAnalysis{{
+		Keyspace: "ks",
+		Shard:    "80-",
+		LeaderID: status.ID,
+		Problems: []SharedProblem{{
+			TabletAlias: "zone1-0000000200",
+			Analysis:    inst.ReplicationStopped,
+			Description: "Replication is stopped",
+		}},
+	}}, sharedAnalysis)
+	sharedAnalysis, err = ReadSharedAnalysis(ctx, "ks", "-80")
+	require.NoError(t, err)
+	require.Empty(t, sharedAnalysis)
+
+	// This instance takes over the first shard once the other one gives up its leadership.
+	otherParticipation.Stop()
+	require.Eventually(t, func() bool {
+		return IsLeader("ks", "-80")
+	}, 5*time.Second, 10*time.Millisecond)
+	status = GetLeadershipStatus(ctx)
+	require.True(t, status.Shards[0].IsLeader)
+	require.Equal(t, status.ID, status.Shards[0].LeaderID)
+	require.Len(t, status.Shards[0].Transitions, 2)
+	require.Equal(t, "other-vtorc:15000", status.Shards[0].Transitions[0].LeaderID)
+	require.Equal(t, status.ID, status.Shards[0].Transitions[1].LeaderID)
+	require.NoError(t, publishSharedAnalysis(ctx, analysis))
+	sharedAnalysis, err = ReadSharedAnalysis(ctx, "ks", "-80")
+	require.NoError(t, err)
+	require.Len(t, sharedAnalysis, 1)
+	require.Equal(t, inst.DeadPrimary, sharedAnalysis[0].Problems[0].Analysis)
+
+	// The shards that are no longer watched are given up.
+	_, err = db.ExecVTOrc("delete from vitess_shard where keyspace = ? and shard = ?", "ks", "80-")
+	require.NoError(t, err)
+	require.NoError(t, refreshLeaderElections(ctx))
+	require.False(t, IsLeader("ks", "80-"))
+	require.Len(t, GetLeadershipStatus(ctx).Shards, 1)
+	otherParticipation, err = conn.NewLeaderParticipation(leaderElectionPath("ks", "80-"), "other-vtorc:15000")
+	require.NoError(t, err)
+	_, err = otherParticipation.WaitForLeadership()
+	require.NoError(t, err)
+	defer otherParticipation.Stop()
+
+	// Giving up the leadership lets the other instances take over.
+	stopLeaderElection()
+	require.Eventually(t, func() bool {
+		return !IsLeader("ks", "-80")
+	}, 5*time.Second, 10*time.Millisecond)
+	leaderID, err := otherParticipation.GetCurrentLeaderID(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "other-vtorc:15000", leaderID)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/log"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil"
+	"vitess.io/vitess/go/vt/vtorc/config"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+)
+
+const (
+	// recoveryDryRunAuditType is the audit type of the recoveries recorded in dry-run mode.
+	recoveryDryRunAuditType = "recovery-dry-run"
+	// recoveryDryRunDedupInterval is the interval during which an identical dry-run decision is only recorded once.
+	recoveryDryRunDedupInterval = 1 * time.Minute
+)
+
+// RecoveryDryRun is a recovery that VTOrc would have run, had it not been running in dry-run mode.
+type RecoveryDryRun struct {
+	ID             int64
+	DetectionID    int64
+	AnalyzedAlias  string
+	Analysis       inst.AnalysisCode
+	Keyspace       string
+	Shard          string
+	RecoveryName   string
+	CandidateAlias string
+	Message        string
+	Timestamp      string
+}
+
+// recordRecoveryDryRun computes the recovery that would be run for the given analysis, including the primary
+// that would be promoted for the shard-wide recoveries, and records it instead of running it.
+func recordRecoveryDryRun(ctx context.Context, analysisEntry *inst.ReplicationAnalysis, recoveryFunctionCode recoveryFunction, logger *log.PrefixedLogger) error {
+	dryRun := &RecoveryDryRun{
+		DetectionID:   analysisEntry.RecoveryId,
+		AnalyzedAlias: analysisEntry.AnalyzedInstanceAlias,
+		Analysis:      analysisEntry.Analysis,
+		Keyspace:      analysisEntry.AnalyzedKeyspace,
+		Shard:         analysisEntry.AnalyzedShard,
+		RecoveryName:  getRecoverFunctionName(recoveryFunctionCode),
+	}
+	switch recoveryFunctionCode {
+	case recoverDeadPrimaryFunc, recoverPrimaryTabletDeletedFunc:
+		candidate, err := chooseEmergencyReparentCandidate(ctx, analysisEntry)
+		if err != nil {
+			dryRun.Message = fmt.Sprintf("would run %v on %v/%v, which would fail to choose a new primary: %v", dryRun.RecoveryName, dryRun.Keyspace, dryRun.Shard, err)
+			break
+		}
+		dryRun.CandidateAlias = topoproto.TabletAliasString(candidate.Alias)
+		dryRun.Message = fmt.Sprintf("would run %v on %v/%v and promote %v", dryRun.RecoveryName, dryRun.Keyspace, dryRun.Shard, dryRun.CandidateAlias)
+	case electNewPrimaryFunc:
+		dryRun.Message = fmt.Sprintf("would run %v on %v/%v", dryRun.RecoveryName, dryRun.Keyspace, dryRun.Shard)
+	default:
+		dryRun.Message = fmt.Sprintf("would run %v on %v", dryRun.RecoveryName, dryRun.AnalyzedAlias)
+	}
+
+	written, err := writeRecoveryDryRun(dryRun)
+	if err != nil {
+		logger.Errorf("Failed to record the recovery dry-run: %v", err)
+		return err
+	}
+	if !written {
+		// An identical decision was recently recorded.
+		return nil
+	}
+	logger.Infof("Recovery dry-run: %v", dryRun.Message)
+	return inst.AuditOperation(recoveryDryRunAuditType, dryRun.AnalyzedAlias, dryRun.Message)
+}
+
+// chooseEmergencyReparentCandidate returns the primary that an emergency reparent of the analyzed shard would promote.
+// The replication positions are the executed GTID sets that VTOrc last read from the tablets, so unlike ERS,
+// it does not account for the relay logs that the replicas have not applied yet.
+func chooseEmergencyReparentCandidate(ctx context.Context, analysisEntry *inst.ReplicationAnalysis) (*topodatapb.Tablet, error) {
+	instances, err := inst.ReadShardInstances(analysisEntry.AnalyzedKeyspace, analysisEntry.AnalyzedShard)
+	if err != nil {
+		return nil, err
+	}
+	positions := make(map[string]replication.Position)
+	for _, instance := range instances {
+		// The analyzed tablet is the dead or deleted primary, and the tablets that VTOrc
+		// cannot reach or that have errant GTIDs would not be considered by ERS either.
+		if instance.InstanceAlias == analysisEntry.AnalyzedInstanceAlias || !instance.IsLastCheckValid ||
+			instance.GtidErrant != "" || instance.ExecutedGtidSet == "" {
+			continue
+		}
+		position, err := replication.ParsePosition(replication.Mysql56FlavorID, instance.ExecutedGtidSet)
+		if err != nil {
+			log.Warningf("Failed to parse the executed GTID set of %v: %v", instance.InstanceAlias, err)
+			continue
+		}
+		positions[instance.InstanceAlias] = position
+	}
+	return reparentutil.NewEmergencyReparenter(ts, tmc, nil).ChooseNewPrimary(ctx,
+		analysisEntry.AnalyzedKeyspace,
+		analysisEntry.AnalyzedShard,
+		positions,
+		reparentutil.EmergencyReparentOptions{
+			PreventCrossCellPromotion: config.GetPreventCrossCellFailover(),
+		},
+	)
+}
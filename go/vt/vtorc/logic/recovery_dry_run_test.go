@@ -0,0 +1,133 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/log"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtorc/config"
+	"vitess.io/vitess/go/vt/vtorc/db"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+)
+
+func TestRecordRecoveryDryRun(t *testing.T) {
+	// Clear the database after the test. The easiest way to do that is to run all the initialization commands again.
+	defer func() {
+		db.ClearVTOrcDatabase()
+	}()
+	oldTs := ts
+	defer func() {
+		ts = oldTs
+	}()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts = memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.CreateShard(ctx, "ks", "0"))
+	newTablet := func(uid uint32, tabletType topodatapb.TabletType) *topodatapb.Tablet {
+		return &topodatapb.Tablet{
+			Alias:         &topodatapb.TabletAlias{Cell: "zone1", Uid: uid},
+			Hostname:      "localhost",
+			MysqlHostname: "localhost",
+			MysqlPort:     int32(6700 + uid),
+			Keyspace:      "ks",
+			Shard:         "0",
+			Type:          tabletType,
+		}
+	}
+	tablets := map[*topodatapb.Tablet]string{
+		newTablet(100, topodatapb.TabletType_PRIMARY): "729a4cc4-8680-11ed-a104-47706090afbd:1-30",
+		newTablet(101, topodatapb.TabletType_REPLICA): "729a4cc4-8680-11ed-a104-47706090afbd:1-20",
+		newTablet(102, topodatapb.TabletType_REPLICA): "729a4cc4-8680-11ed-a104-47706090afbd:1-25",
+		newTablet(103, topodatapb.TabletType_RDONLY):  "729a4cc4-8680-11ed-a104-47706090afbd:1-28",
+	}
+	for tablet, executedGtidSet := range tablets {
+		require.NoError(t, ts.CreateTablet(ctx, tablet))
+		require.NoError(t, inst.SaveTablet(tablet))
+		_, err := db.ExecVTOrc(`insert into database_instance (alias, hostname, port, tablet_type, cell, server_id, version, binlog_format, log_bin, log_replica_updates,
+binary_log_file, binary_log_pos, source_host, source_port, replica_net_timeout, heartbeat_interval, replica_sql_running, replica_io_running, source_log_file,
+read_source_log_pos, relay_source_log_file, exec_source_log_pos, executed_gtid_set, last_checked, last_seen)
+values (?, ?, ?, ?, 'zone1', ?, '8.0.31', 'ROW', 1, 1, '', 0, '', 0, 8, 4, 1, 1, '', 0, '', 0, ?, datetime('now'), datetime('now'))`,
+			topoproto.TabletAliasString(tablet.Alias), tablet.MysqlHostname, tablet.MysqlPort, tablet.Type, tablet.Alias.Uid, executedGtidSet)
+		require.NoError(t, err)
+		if tablet.Type == topodatapb.TabletType_PRIMARY {
+			_, err := ts.UpdateShardFields(ctx, "ks", "0", func(si *topo.ShardInfo) error {
+				si.PrimaryAlias = tablet.Alias
+				return nil
+			})
+			require.NoError(t, err)
+		}
+	}
+
+	deadPrimaryAnalysis := &inst.ReplicationAnalysis{
+		AnalyzedInstanceAlias: "zone1-0000000100",
+		AnalyzedKeyspace:      "ks",
+		AnalyzedShard:         "0",
+		Analysis:              inst.DeadPrimary,
+	}
+	logger := log.NewPrefixedLogger("prefix")
+
+	// The most advanced replica is the candidate, since the RDONLY tablet cannot be promoted.
+	err := recordRecoveryDryRun(ctx, deadPrimaryAnalysis, recoverDeadPrimaryFunc, logger)
+	require.NoError(t, err)
+	dryRuns, err := ReadRecentRecoveryDryRuns("ks", "0")
+	require.NoError(t, err)
+	require.Len(t, dryRuns, 1)
+	require.Equal(t, "zone1-0000000102", dryRuns[0].CandidateAlias)
+	require.Equal(t, RecoverDeadPrimaryRecoveryName, dryRuns[0].RecoveryName)
+	require.Equal(t, "would run RecoverDeadPrimary on ks/0 and promote zone1-0000000102", dryRuns[0].Message)
+
+	// An identical decision is not recorded again.
+	err = recordRecoveryDryRun(ctx, deadPrimaryAnalysis, recoverDeadPrimaryFunc, logger)
+	require.NoError(t, err)
+	dryRuns, err = ReadRecentRecoveryDryRuns("ks", "0")
+	require.NoError(t, err)
+	require.Len(t, dryRuns, 1)
+
+	// In dry-run mode, the other recoveries are recorded without being run.
+	oldDryRun := config.GetRecoveryDryRun()
+	config.SetRecoveryDryRun(true)
+	defer config.SetRecoveryDryRun(oldDryRun)
+	err = executeCheckAndRecoverFunction(&inst.ReplicationAnalysis{
+		AnalyzedInstanceAlias: "zone1-0000000101",
+		AnalyzedKeyspace:      "ks",
+		AnalyzedShard:         "0",
+		Analysis:              inst.ReplicaIsWritable,
+	})
+	require.NoError(t, err)
+	dryRuns, err = ReadRecentRecoveryDryRuns("ks", "")
+	require.NoError(t, err)
+	require.Len(t, dryRuns, 2)
+	require.Equal(t, "would run FixReplica on zone1-0000000101", dryRuns[0].Message)
+	require.Empty(t, dryRuns[0].CandidateAlias)
+	require.NotZero(t, dryRuns[0].DetectionID)
+
+	// Filtering by another keyspace returns nothing.
+	dryRuns, err = ReadRecentRecoveryDryRuns("unknown", "")
+	require.NoError(t, err)
+	require.Empty(t, dryRuns)
+}
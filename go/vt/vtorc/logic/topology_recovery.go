@@ -30,6 +30,7 @@ import (
 	"vitess.io/vitess/go/vt/logutil"
 	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtctl/reparentutil"
 	"vitess.io/vitess/go/vt/vtctl/reparentutil/policy"
@@ -48,6 +49,7 @@ const (
 	FixPrimaryRecoveryName                           string = "FixPrimary"
 	FixReplicaRecoveryName                           string = "FixReplica"
 	RecoverErrantGTIDDetectedName                    string = "RecoverErrantGTIDDetected"
+	RemediateErrantGTIDRecoveryName                  string = "RemediateErrantGTID"
 )
 
 var (
@@ -100,6 +102,7 @@ const (
 	fixPrimaryFunc
 	fixReplicaFunc
 	recoverErrantGTIDDetectedFunc
+	remediateErrantGTIDFunc
 )
 
 // TopologyRecovery represents an entry in the topology_recovery table
@@ -356,6 +359,11 @@ func getCheckAndRecoverFunctionCode(analysisCode inst.AnalysisCode, tabletAlias
 		}
 		return recoverPrimaryTabletDeletedFunc
 	case inst.ErrantGTIDDetected:
+		// The keyspaces with an errant GTID remediation policy get their errant GTIDs remediated,
+		// even if VTOrc is not configured to convert the tablets with errant GTIDs.
+		if readErrantGtidRemediationPolicy(tabletAlias) != nil {
+			return remediateErrantGTIDFunc
+		}
 		if !config.ConvertTabletWithErrantGTIDs() {
 			log.Infof("VTOrc not configured to do anything on detecting errant GTIDs, skipping recovering %v", analysisCode)
 			return noRecoveryFunc
@@ -416,6 +424,8 @@ func hasActionableRecovery(recoveryFunctionCode recoveryFunction) bool {
 		return true
 	case recoverErrantGTIDDetectedFunc:
 		return true
+	case remediateErrantGTIDFunc:
+		return true
 	default:
 		return false
 	}
@@ -446,6 +456,8 @@ func getCheckAndRecoverFunction(recoveryFunctionCode recoveryFunction) (
 		return fixReplica
 	case recoverErrantGTIDDetectedFunc:
 		return recoverErrantGTIDDetected
+	case remediateErrantGTIDFunc:
+		return remediateErrantGTID
 	default:
 		return nil
 	}
@@ -475,6 +487,8 @@ func getRecoverFunctionName(recoveryFunctionCode recoveryFunction) string {
 		return FixReplicaRecoveryName
 	case recoverErrantGTIDDetectedFunc:
 		return RecoverErrantGTIDDetectedName
+	case remediateErrantGTIDFunc:
+		return RemediateErrantGTIDRecoveryName
 	default:
 		return ""
 	}
@@ -546,6 +560,18 @@ func executeCheckAndRecoverFunction(analysisEntry *inst.ReplicationAnalysis) (er
 		return err
 	}
 
+	// When the VTOrc instances elect a leader for each shard, only the leader of the shard runs its recoveries.
+	if !IsLeader(analysisEntry.AnalyzedKeyspace, analysisEntry.AnalyzedShard) {
+		logger.Infof("CheckAndRecover: Tablet: %+v: NOT Recovering host (not the leader of the shard among the VTOrc instances)",
+			analysisEntry.AnalyzedInstanceAlias)
+		return nil
+	}
+
+	// In dry-run mode, we only record the recovery that we would run, without locking the shard nor changing anything.
+	if isActionableRecovery && config.GetRecoveryDryRun() {
+		return recordRecoveryDryRun(context.Background(), analysisEntry, checkAndRecoverFunctionCode, logger)
+	}
+
 	// Prioritise primary recovery.
 	// If we are performing some other action, first ensure that it is not because of primary issues.
 	// This step is only meant to improve the time taken to detect and fix shard-wide recoveries, it does not impact correctness.
@@ -765,6 +791,15 @@ func CheckAndRecover() {
 		}
 	}
 
+	// Share the detected problems with the other VTOrc instances, for the shards that this instance leads.
+	func() {
+		ctx, cancel := context.WithTimeout(context.Background(), topo.RemoteOperationTimeout)
+		defer cancel()
+		if err := publishSharedAnalysis(ctx, replicationAnalysis); err != nil {
+			log.Errorf("Failed to share the replication analysis: %v", err)
+		}
+	}()
+
 	// intentionally iterating entries in random order
 	for _, j := range rand.Perm(len(replicationAnalysis)) {
 		analysisEntry := replicationAnalysis[j]
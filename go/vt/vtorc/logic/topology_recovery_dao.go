@@ -259,6 +259,119 @@ func writeTopologyRecoveryStep(topologyRecoveryStep *TopologyRecoveryStep) error
 	return err
 }
 
+// writeRecoveryDryRun writes down a recovery that would have been run. It does not write anything,
+// and returns false, if an identical decision was recorded within the dedup interval.
+func writeRecoveryDryRun(dryRun *RecoveryDryRun) (bool, error) {
+	sqlResult, err := db.ExecVTOrc(`INSERT
+		INTO recovery_dry_run (
+			detection_id,
+			alias,
+			analysis,
+			keyspace,
+			shard,
+			recovery_name,
+			candidate_alias,
+			message,
+			dry_run_timestamp
+		) SELECT
+			?,
+			?,
+			?,
+			?,
+			?,
+			?,
+			?,
+			?,
+			DATETIME('now')
+		WHERE NOT EXISTS (
+			SELECT
+				1
+			FROM
+				recovery_dry_run
+			WHERE
+				alias = ?
+				AND analysis = ?
+				AND recovery_name = ?
+				AND candidate_alias = ?
+				AND dry_run_timestamp >= DATETIME('now', PRINTF('-%d SECOND', ?))
+		)`,
+		dryRun.DetectionID,
+		dryRun.AnalyzedAlias,
+		string(dryRun.Analysis),
+		dryRun.Keyspace,
+		dryRun.Shard,
+		dryRun.RecoveryName,
+		dryRun.CandidateAlias,
+		dryRun.Message,
+		dryRun.AnalyzedAlias,
+		string(dryRun.Analysis),
+		dryRun.RecoveryName,
+		dryRun.CandidateAlias,
+		int64(recoveryDryRunDedupInterval.Seconds()),
+	)
+	if err != nil {
+		log.Error(err)
+		return false, err
+	}
+	rows, err := sqlResult.RowsAffected()
+	if err != nil {
+		log.Error(err)
+		return false, err
+	}
+	if rows == 0 {
+		return false, nil
+	}
+	dryRun.ID, err = sqlResult.LastInsertId()
+	if err != nil {
+		log.Error(err)
+	}
+	return true, err
+}
+
+// ReadRecentRecoveryDryRuns reads the latest recoveries recorded in dry-run mode, optionally filtered by keyspace and shard.
+func ReadRecentRecoveryDryRuns(keyspace string, shard string) ([]*RecoveryDryRun, error) {
+	res := []*RecoveryDryRun{}
+	query := `SELECT
+			dry_run_id,
+			detection_id,
+			alias,
+			analysis,
+			keyspace,
+			shard,
+			recovery_name,
+			candidate_alias,
+			message,
+			dry_run_timestamp
+		FROM
+			recovery_dry_run
+		WHERE
+			keyspace LIKE (CASE WHEN ? = '' THEN '%' ELSE ? END)
+			AND shard LIKE (CASE WHEN ? = '' THEN '%' ELSE ? END)
+		ORDER BY dry_run_id DESC
+		LIMIT ?
+		`
+	args := sqlutils.Args(keyspace, keyspace, shard, shard, config.AuditPageSize)
+	err := db.QueryVTOrc(query, args, func(m sqlutils.RowMap) error {
+		res = append(res, &RecoveryDryRun{
+			ID:             m.GetInt64("dry_run_id"),
+			DetectionID:    m.GetInt64("detection_id"),
+			AnalyzedAlias:  m.GetString("alias"),
+			Analysis:       inst.AnalysisCode(m.GetString("analysis")),
+			Keyspace:       m.GetString("keyspace"),
+			Shard:          m.GetString("shard"),
+			RecoveryName:   m.GetString("recovery_name"),
+			CandidateAlias: m.GetString("candidate_alias"),
+			Message:        m.GetString("message"),
+			Timestamp:      m.GetString("dry_run_timestamp"),
+		})
+		return nil
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return res, err
+}
+
 // ExpireRecoveryDetectionHistory removes old rows from the recovery_detection table
 func ExpireRecoveryDetectionHistory() error {
 	return inst.ExpireTableData("recovery_detection", "detection_timestamp")
@@ -273,3 +386,8 @@ func ExpireTopologyRecoveryHistory() error {
 func ExpireTopologyRecoveryStepsHistory() error {
 	return inst.ExpireTableData("topology_recovery_steps", "audit_at")
 }
+
+// ExpireRecoveryDryRunHistory removes old rows from the recovery_dry_run table
+func ExpireRecoveryDryRunHistory() error {
+	return inst.ExpireTableData("recovery_dry_run", "dry_run_timestamp")
+}
@@ -109,6 +109,16 @@ func TestExpireTableData(t *testing.T) {
 (3, datetime('now', '-15 DAY'), 3, 'a')`,
 			expireFunc: ExpireTopologyRecoveryStepsHistory,
 		},
+		{
+			name:             "ExpireRecoveryDryRunHistory",
+			tableName:        "recovery_dry_run",
+			expectedRowCount: 1,
+			insertQuery: `insert into recovery_dry_run (dry_run_id, dry_run_timestamp, alias, analysis, keyspace, shard, recovery_name) values
+(1, datetime('now', '-13 DAY'),'a','a','a','a','a'),
+(2, datetime('now', '-5 DAY'),'a','a','a','a','a'),
+(3, datetime('now', '-15 DAY'),'a','a','a','a','a')`,
+			expireFunc: ExpireRecoveryDryRunHistory,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
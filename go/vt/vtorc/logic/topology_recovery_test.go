@@ -26,6 +26,7 @@ import (
 
 	"vitess.io/vitess/go/vt/external/golib/sqlutils"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtctl/reparentutil/policy"
@@ -221,6 +222,7 @@ func TestGetCheckAndRecoverFunctionCode(t *testing.T) {
 		name                         string
 		ersEnabled                   bool
 		convertTabletWithErrantGTIDs bool
+		errantGtidRemediationPolicy  *topodatapb.ErrantGtidRemediationPolicy
 		analysisCode                 inst.AnalysisCode
 		wantRecoveryFunction         recoveryFunction
 	}{
@@ -296,6 +298,20 @@ func TestGetCheckAndRecoverFunctionCode(t *testing.T) {
 			convertTabletWithErrantGTIDs: false,
 			analysisCode:                 inst.ErrantGTIDDetected,
 			wantRecoveryFunction:         noRecoveryFunc,
+		}, {
+			name:                         "ErrantGTIDDetected with an errant GTID remediation policy",
+			ersEnabled:                   false,
+			convertTabletWithErrantGTIDs: false,
+			errantGtidRemediationPolicy:  &topodatapb.ErrantGtidRemediationPolicy{InjectEmptyTransactions: true},
+			analysisCode:                 inst.ErrantGTIDDetected,
+			wantRecoveryFunction:         remediateErrantGTIDFunc,
+		}, {
+			name:                         "ErrantGTIDDetected with an errant GTID remediation policy that allows nothing",
+			ersEnabled:                   false,
+			convertTabletWithErrantGTIDs: true,
+			errantGtidRemediationPolicy:  &topodatapb.ErrantGtidRemediationPolicy{MaxErrantTransactions: 10},
+			analysisCode:                 inst.ErrantGTIDDetected,
+			wantRecoveryFunction:         recoverErrantGTIDDetectedFunc,
 		},
 	}
 
@@ -309,7 +325,28 @@ func TestGetCheckAndRecoverFunctionCode(t *testing.T) {
 			config.SetConvertTabletWithErrantGTIDs(tt.convertTabletWithErrantGTIDs)
 			defer config.SetConvertTabletWithErrantGTIDs(convertErrantVal)
 
-			gotFunc := getCheckAndRecoverFunctionCode(tt.analysisCode, "")
+			tabletAlias := ""
+			if tt.errantGtidRemediationPolicy != nil {
+				// Clear the database after the test. The easiest way to do that is to run all the initialization commands again.
+				defer func() {
+					db.ClearVTOrcDatabase()
+				}()
+				tablet := &topodatapb.Tablet{
+					Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+					Keyspace: "ks",
+					Shard:    "0",
+					Type:     topodatapb.TabletType_REPLICA,
+				}
+				require.NoError(t, inst.SaveTablet(tablet))
+				keyspaceInfo := &topo.KeyspaceInfo{
+					Keyspace: &topodatapb.Keyspace{ErrantGtidRemediationPolicy: tt.errantGtidRemediationPolicy},
+				}
+				keyspaceInfo.SetKeyspaceName("ks")
+				require.NoError(t, inst.SaveKeyspace(keyspaceInfo))
+				tabletAlias = topoproto.TabletAliasString(tablet.Alias)
+			}
+
+			gotFunc := getCheckAndRecoverFunctionCode(tt.analysisCode, tabletAlias)
 			require.EqualValues(t, tt.wantRecoveryFunction, gotFunc)
 		})
 	}
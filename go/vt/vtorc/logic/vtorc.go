@@ -90,6 +90,8 @@ func closeVTOrc() {
 	_ = inst.AuditOperation("shutdown", "", "Triggered via SIGTERM")
 	// wait for the locks to be released
 	waitForLocksRelease()
+	// give up the leadership, so that another VTOrc instance takes over the recoveries
+	stopLeaderElection()
 	ts.Close()
 	log.Infof("VTOrc closed")
 }
@@ -288,6 +290,10 @@ func ContinuousDiscovery() {
 	caretakingTick := time.Tick(time.Minute)
 	recoveryTick := time.Tick(config.GetRecoveryPollDuration())
 	tabletTopoTick := OpenTabletDiscovery()
+	// If the leader election cannot be opened, this instance stays a follower and does not run the recoveries.
+	if err := OpenLeaderElection(context.Background()); err != nil {
+		log.Errorf("failed to open the leader elections: %+v", err)
+	}
 	var recoveryEntrance int64
 	var snapshotTopologiesTick <-chan time.Time
 	if config.GetSnapshotTopologyInterval() > 0 {
@@ -316,6 +322,7 @@ func ContinuousDiscovery() {
 				go ExpireRecoveryDetectionHistory()
 				go ExpireTopologyRecoveryHistory()
 				go ExpireTopologyRecoveryStepsHistory()
+				go ExpireRecoveryDryRunHistory()
 			}()
 		case <-recoveryTick:
 			go func() {
@@ -340,6 +347,9 @@ func ContinuousDiscovery() {
 			if err := refreshAllInformation(ctx); err != nil {
 				log.Errorf("failed to refresh topo information: %+v", err)
 			}
+			if err := refreshLeaderElections(ctx); err != nil {
+				log.Errorf("failed to refresh the leader elections: %+v", err)
+			}
 			cancel()
 		}
 	}
@@ -43,6 +43,9 @@ type vtorcAPI struct{}
 const (
 	problemsAPI                   = "/api/problems"
 	errantGTIDsAPI                = "/api/errant-gtids"
+	recoveryDryRunsAPI            = "/api/recovery-dry-runs"
+	leadershipAPI                 = "/api/leadership"
+	sharedAnalysisAPI             = "/api/shared-analysis"
 	disableGlobalRecoveriesAPI    = "/api/disable-global-recoveries"
 	enableGlobalRecoveriesAPI     = "/api/enable-global-recoveries"
 	replicationAnalysisAPI        = "/api/replication-analysis"
@@ -60,6 +63,9 @@ var (
 	vtorcAPIPaths = []string{
 		problemsAPI,
 		errantGTIDsAPI,
+		recoveryDryRunsAPI,
+		leadershipAPI,
+		sharedAnalysisAPI,
 		disableGlobalRecoveriesAPI,
 		enableGlobalRecoveriesAPI,
 		replicationAnalysisAPI,
@@ -89,6 +95,12 @@ func (v *vtorcAPI) ServeHTTP(response http.ResponseWriter, request *http.Request
 		problemsAPIHandler(response, request)
 	case errantGTIDsAPI:
 		errantGTIDsAPIHandler(response, request)
+	case recoveryDryRunsAPI:
+		recoveryDryRunsAPIHandler(response, request)
+	case leadershipAPI:
+		leadershipAPIHandler(response, request)
+	case sharedAnalysisAPI:
+		sharedAnalysisAPIHandler(response, request)
 	case replicationAnalysisAPI:
 		replicationAnalysisAPIHandler(response, request)
 	case databaseStateAPI:
@@ -107,7 +119,7 @@ func (v *vtorcAPI) ServeHTTP(response http.ResponseWriter, request *http.Request
 // getACLPermissionLevelForAPI returns the acl permission level that is required to run a given API
 func getACLPermissionLevelForAPI(apiEndpoint string) string {
 	switch apiEndpoint {
-	case problemsAPI, errantGTIDsAPI:
+	case problemsAPI, errantGTIDsAPI, recoveryDryRunsAPI, leadershipAPI, sharedAnalysisAPI:
 		return acl.MONITORING
 	case disableGlobalRecoveriesAPI, enableGlobalRecoveriesAPI:
 		return acl.ADMIN
@@ -175,6 +187,47 @@ func errantGTIDsAPIHandler(response http.ResponseWriter, request *http.Request)
 	returnAsJSON(response, http.StatusOK, instances)
 }
 
+// recoveryDryRunsAPIHandler is the handler for the recoveryDryRunsAPI endpoint
+func recoveryDryRunsAPIHandler(response http.ResponseWriter, request *http.Request) {
+	// This api also supports filtering by shard and keyspace provided.
+	shard := request.URL.Query().Get("shard")
+	keyspace := request.URL.Query().Get("keyspace")
+	if shard != "" && keyspace == "" {
+		http.Error(response, shardWithoutKeyspaceFilteringErrorStr, http.StatusBadRequest)
+		return
+	}
+
+	dryRuns, err := logic.ReadRecentRecoveryDryRuns(keyspace, shard)
+	if err != nil {
+		http.Error(response, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	returnAsJSON(response, http.StatusOK, dryRuns)
+}
+
+// leadershipAPIHandler is the handler for the leadershipAPI endpoint
+func leadershipAPIHandler(response http.ResponseWriter, request *http.Request) {
+	returnAsJSON(response, http.StatusOK, logic.GetLeadershipStatus(request.Context()))
+}
+
+// sharedAnalysisAPIHandler is the handler for the sharedAnalysisAPI endpoint
+func sharedAnalysisAPIHandler(response http.ResponseWriter, request *http.Request) {
+	// This api also supports filtering by shard and keyspace provided.
+	shard := request.URL.Query().Get("shard")
+	keyspace := request.URL.Query().Get("keyspace")
+	if shard != "" && keyspace == "" {
+		http.Error(response, shardWithoutKeyspaceFilteringErrorStr, http.StatusBadRequest)
+		return
+	}
+
+	sharedAnalysis, err := logic.ReadSharedAnalysis(request.Context(), keyspace, shard)
+	if err != nil {
+		http.Error(response, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	returnAsJSON(response, http.StatusOK, sharedAnalysis)
+}
+
 // databaseStateAPIHandler is the handler for the databaseStateAPI endpoint
 func databaseStateAPIHandler(response http.ResponseWriter) {
 	ds, err := inst.GetDatabaseState()
@@ -19,6 +19,15 @@ func TestGetACLPermissionLevelForAPI(t *testing.T) {
 		}, {
 			apiEndpoint: errantGTIDsAPI,
 			want:        acl.MONITORING,
+		}, {
+			apiEndpoint: recoveryDryRunsAPI,
+			want:        acl.MONITORING,
+		}, {
+			apiEndpoint: leadershipAPI,
+			want:        acl.MONITORING,
+		}, {
+			apiEndpoint: sharedAnalysisAPI,
+			want:        acl.MONITORING,
 		}, {
 			apiEndpoint: disableGlobalRecoveriesAPI,
 			want:        acl.ADMIN,
@@ -49,6 +49,7 @@ type VReplicationConfig struct {
 	HeartbeatUpdateInterval int
 	StoreCompressedGTID     bool
 	ParallelInsertWorkers   int
+	ParallelApplyWorkers    int
 	TabletTypesStr          string
 	EnableHttpLog           bool // Enable the /debug/vrlog endpoint
 
@@ -94,6 +95,7 @@ func GetVReplicationConfigDefaults(useCached bool) *VReplicationConfig {
 		HeartbeatUpdateInterval: vreplicationHeartbeatUpdateInterval,
 		StoreCompressedGTID:     vreplicationStoreCompressedGTID,
 		ParallelInsertWorkers:   vreplicationParallelInsertWorkers,
+		ParallelApplyWorkers:    vreplicationParallelApplyWorkers,
 		TabletTypesStr:          vreplicationTabletTypesStr,
 		EnableHttpLog:           vreplicationEnableHttpLog,
 
@@ -220,6 +222,13 @@ func NewVReplicationConfig(overrides map[string]string) (*VReplicationConfig, er
 			} else {
 				c.ParallelInsertWorkers = value
 			}
+		case "vreplication-parallel-apply-workers":
+			value, err := strconv.Atoi(v)
+			if err != nil {
+				errors = append(errors, getError(k, v))
+			} else {
+				c.ParallelApplyWorkers = value
+			}
 		case "vstream-packet-size", "vstream_packet_size":
 			value, err := strconv.Atoi(v)
 			if err != nil {
@@ -271,6 +280,7 @@ func (c VReplicationConfig) Map() map[string]string {
 		"vreplication-heartbeat-update-interval":  strconv.Itoa(c.HeartbeatUpdateInterval),
 		"vreplication-store-compressed-gtid":      strconv.FormatBool(c.StoreCompressedGTID),
 		"vreplication-parallel-insert-workers":    strconv.Itoa(c.ParallelInsertWorkers),
+		"vreplication-parallel-apply-workers":     strconv.Itoa(c.ParallelApplyWorkers),
 		"vstream-packet-size":                     strconv.Itoa(c.VStreamPacketSize),
 		"vstream_packet_size":                     strconv.Itoa(c.VStreamPacketSize),
 		"vstream-dynamic-packet-size":             strconv.FormatBool(c.VStreamDynamicPacketSize),
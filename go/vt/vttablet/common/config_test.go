@@ -48,6 +48,7 @@ func TestNewVReplicationConfig(t *testing.T) {
 				"vreplication-heartbeat-update-interval":  "2",
 				"vreplication-store-compressed-gtid":      "true",
 				"vreplication-parallel-insert-workers":    "4",
+				"vreplication-parallel-apply-workers":     "8",
 				"vstream-packet-size":                     "1024",
 				"vstream_packet_size":                     "1024",
 				"vstream-dynamic-packet-size":             "false",
@@ -68,6 +69,7 @@ func TestNewVReplicationConfig(t *testing.T) {
 				HeartbeatUpdateInterval:                2,
 				StoreCompressedGTID:                    true,
 				ParallelInsertWorkers:                  4,
+				ParallelApplyWorkers:                   8,
 				VStreamPacketSize:                      1024,
 				VStreamDynamicPacketSize:               false,
 				VStreamBinlogRotationThreshold:         2048,
@@ -92,13 +94,14 @@ func TestNewVReplicationConfig(t *testing.T) {
 				"vreplication-heartbeat-update-interval":  "invalid",
 				"vreplication-store-compressed-gtid":      "nottrue",
 				"vreplication-parallel-insert-workers":    "invalid",
+				"vreplication-parallel-apply-workers":     "invalid",
 				"vstream-packet-size":                     "invalid",
 				"vstream_packet_size":                     "invalid",
 				"vstream-dynamic-packet-size":             "waar",
 				"vstream_dynamic_packet_size":             "waar",
 				"vstream_binlog_rotation_threshold":       "invalid",
 			},
-			wantErr: 17,
+			wantErr: 18,
 		},
 		{
 			name: "Partial values",
@@ -122,6 +125,7 @@ func TestNewVReplicationConfig(t *testing.T) {
 				HeartbeatUpdateInterval:          DefaultVReplicationConfig.HeartbeatUpdateInterval,
 				StoreCompressedGTID:              !DefaultVReplicationConfig.StoreCompressedGTID,
 				ParallelInsertWorkers:            DefaultVReplicationConfig.ParallelInsertWorkers,
+				ParallelApplyWorkers:             DefaultVReplicationConfig.ParallelApplyWorkers,
 				VStreamPacketSize:                DefaultVReplicationConfig.VStreamPacketSize,
 				VStreamDynamicPacketSize:         !DefaultVReplicationConfig.VStreamDynamicPacketSize,
 				VStreamBinlogRotationThreshold:   DefaultVReplicationConfig.VStreamBinlogRotationThreshold,
@@ -98,6 +98,10 @@ func (client *FakeTabletManagerClient) UpdateVReplicationWorkflows(ctx context.C
 	return nil, nil
 }
 
+func (client *FakeTabletManagerClient) ExecuteFetchOnExternalMysql(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.ExecuteFetchOnExternalMysqlRequest) (*tabletmanagerdatapb.ExecuteFetchOnExternalMysqlResponse, error) {
+	return nil, nil
+}
+
 func (client *FakeTabletManagerClient) ValidateVReplicationPermissions(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.ValidateVReplicationPermissionsRequest) (*tabletmanagerdatapb.ValidateVReplicationPermissionsResponse, error) {
 	return nil, nil
 }
@@ -397,6 +401,16 @@ func (client *FakeTabletManagerClient) PromoteReplica(ctx context.Context, table
 	return "", nil
 }
 
+// InspectBinlogTransactions is part of the tmclient.TabletManagerClient interface.
+func (client *FakeTabletManagerClient) InspectBinlogTransactions(ctx context.Context, tablet *topodatapb.Tablet, gtidSet string) (*tabletmanagerdatapb.InspectBinlogTransactionsResponse, error) {
+	return &tabletmanagerdatapb.InspectBinlogTransactionsResponse{}, nil
+}
+
+// InjectEmptyTransactions is part of the tmclient.TabletManagerClient interface.
+func (client *FakeTabletManagerClient) InjectEmptyTransactions(ctx context.Context, tablet *topodatapb.Tablet, gtidSet string) error {
+	return nil
+}
+
 //
 // Backup related methods
 //
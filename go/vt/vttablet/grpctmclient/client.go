@@ -995,6 +995,20 @@ func (client *Client) ReadVReplicationWorkflow(ctx context.Context, tablet *topo
 	return response, nil
 }
 
+// ExecuteFetchOnExternalMysql is part of the tmclient.TabletManagerClient interface.
+func (client *Client) ExecuteFetchOnExternalMysql(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.ExecuteFetchOnExternalMysqlRequest) (*tabletmanagerdatapb.ExecuteFetchOnExternalMysqlResponse, error) {
+	c, closer, err := client.dialer.dial(ctx, tablet)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	response, err := c.ExecuteFetchOnExternalMysql(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
 func (client *Client) ValidateVReplicationPermissions(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.ValidateVReplicationPermissionsRequest) (*tabletmanagerdatapb.ValidateVReplicationPermissionsResponse, error) {
 	c, closer, err := client.dialer.dial(ctx, tablet)
 	if err != nil {
@@ -1289,6 +1303,33 @@ func (client *Client) PromoteReplica(ctx context.Context, tablet *topodatapb.Tab
 	return response.Position, nil
 }
 
+// InspectBinlogTransactions is part of the tmclient.TabletManagerClient interface.
+func (client *Client) InspectBinlogTransactions(ctx context.Context, tablet *topodatapb.Tablet, gtidSet string) (*tabletmanagerdatapb.InspectBinlogTransactionsResponse, error) {
+	c, closer, err := client.dialer.dial(ctx, tablet)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	return c.InspectBinlogTransactions(ctx, &tabletmanagerdatapb.InspectBinlogTransactionsRequest{
+		GtidSet: gtidSet,
+	})
+}
+
+// InjectEmptyTransactions is part of the tmclient.TabletManagerClient interface.
+func (client *Client) InjectEmptyTransactions(ctx context.Context, tablet *topodatapb.Tablet, gtidSet string) error {
+	c, closer, err := client.dialer.dial(ctx, tablet)
+	if err != nil {
+		return err
+	}
+	defer closer.Close()
+
+	_, err = c.InjectEmptyTransactions(ctx, &tabletmanagerdatapb.InjectEmptyTransactionsRequest{
+		GtidSet: gtidSet,
+	})
+	return err
+}
+
 // Backup related methods
 type backupStreamAdapter struct {
 	stream tabletmanagerservicepb.TabletManager_BackupClient
@@ -491,6 +491,13 @@ func (s *server) ReadVReplicationWorkflow(ctx context.Context, request *tabletma
 	return s.tm.ReadVReplicationWorkflow(ctx, request)
 }
 
+func (s *server) ExecuteFetchOnExternalMysql(ctx context.Context, request *tabletmanagerdatapb.ExecuteFetchOnExternalMysqlRequest) (response *tabletmanagerdatapb.ExecuteFetchOnExternalMysqlResponse, err error) {
+	defer s.tm.HandleRPCPanic(ctx, "ExecuteFetchOnExternalMysql", request, response, false /*verbose*/, &err)
+	ctx = callinfo.GRPCCallInfo(ctx)
+	response = &tabletmanagerdatapb.ExecuteFetchOnExternalMysqlResponse{}
+	return s.tm.ExecuteFetchOnExternalMysql(ctx, request)
+}
+
 func (s *server) ValidateVReplicationPermissions(ctx context.Context, request *tabletmanagerdatapb.ValidateVReplicationPermissionsRequest) (response *tabletmanagerdatapb.ValidateVReplicationPermissionsResponse, err error) {
 	defer s.tm.HandleRPCPanic(ctx, "ValidateVReplicationPermissions", request, response, true /*verbose*/, &err)
 	ctx = callinfo.GRPCCallInfo(ctx)
@@ -666,6 +673,19 @@ func (s *server) PromoteReplica(ctx context.Context, request *tabletmanagerdatap
 	return response, err
 }
 
+func (s *server) InspectBinlogTransactions(ctx context.Context, request *tabletmanagerdatapb.InspectBinlogTransactionsRequest) (response *tabletmanagerdatapb.InspectBinlogTransactionsResponse, err error) {
+	defer s.tm.HandleRPCPanic(ctx, "InspectBinlogTransactions", request, response, true /*verbose*/, &err)
+	ctx = callinfo.GRPCCallInfo(ctx)
+	return s.tm.InspectBinlogTransactions(ctx, request.GtidSet)
+}
+
+func (s *server) InjectEmptyTransactions(ctx context.Context, request *tabletmanagerdatapb.InjectEmptyTransactionsRequest) (response *tabletmanagerdatapb.InjectEmptyTransactionsResponse, err error) {
+	defer s.tm.HandleRPCPanic(ctx, "InjectEmptyTransactions", request, response, true /*verbose*/, &err)
+	ctx = callinfo.GRPCCallInfo(ctx)
+	response = &tabletmanagerdatapb.InjectEmptyTransactionsResponse{}
+	return response, s.tm.InjectEmptyTransactions(ctx, request.GtidSet)
+}
+
 func (s *server) Backup(request *tabletmanagerdatapb.BackupRequest, stream tabletmanagerservicepb.TabletManager_BackupServer) (err error) {
 	ctx := stream.Context()
 	defer s.tm.HandleRPCPanic(ctx, "Backup", request, nil, true /*verbose*/, &err)
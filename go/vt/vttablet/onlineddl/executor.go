@@ -63,6 +63,7 @@ import (
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/connpool"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/base"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 )
@@ -3101,6 +3102,56 @@ func shouldCutOverAccordingToBackoff(
 	return false, false
 }
 
+// cutOverWindowPolicyBlocker checks the cut-over window policy of the keyspace, if any. It returns
+// an empty string if the policy lets migrations cut over now, or the reason why they should wait:
+//   - none of the policy's windows is open
+//   - the replication lag of the shard, or the threads_running metric of this primary, as measured
+//     by the tablet throttler, is above the policy's threshold
+//   - the policy has a threshold, but the tablet throttler is disabled
+func (e *Executor) cutOverWindowPolicyBlocker(ctx context.Context) (string, error) {
+	ki, err := e.ts.GetKeyspace(ctx, e.keyspace)
+	if err != nil {
+		return "", err
+	}
+	policy := ki.CutOverWindowPolicy
+	if policy == nil {
+		return "", nil
+	}
+	isOpen, err := schema.IsCutOverWindowOpen(policy, time.Now())
+	if err != nil {
+		return "", err
+	}
+	if !isOpen {
+		return "waiting for a cut-over window", nil
+	}
+	loadChecks := []struct {
+		metricName base.MetricName
+		scope      base.Scope
+		threshold  float64
+	}{
+		{metricName: base.LagMetricName, scope: base.ShardScope, threshold: policy.MaxReplicationLagSeconds},
+		{metricName: base.ThreadsRunningMetricName, scope: base.SelfScope, threshold: policy.MaxThreadsRunning},
+	}
+	for _, loadCheck := range loadChecks {
+		if loadCheck.threshold <= 0 {
+			continue
+		}
+		if !e.lagThrottler.IsEnabled() {
+			// The checks of a disabled throttler always pass, and would ignore the thresholds.
+			return "cut-over rejected by cut-over window policy: the tablet throttler is disabled, and cannot check the load thresholds of the policy", nil
+		}
+		checkResult := e.lagThrottler.Check(ctx, throttlerapp.OnlineDDLName.String(), base.MetricNames{loadCheck.metricName}, &throttle.CheckFlags{
+			Scope:                 loadCheck.scope,
+			OverrideThreshold:     loadCheck.threshold,
+			SkipRequestHeartbeats: true,
+		})
+		if !checkResult.IsOK() {
+			return fmt.Sprintf("cut-over rejected by cut-over window policy: %s", checkResult.Summary()), nil
+		}
+	}
+	return "", nil
+}
+
 // reviewRunningMigrations iterates migrations in 'running' state. Normally there's only one running, which was
 // spawned by this tablet; but vreplication migrations could also resume from failure.
 func (e *Executor) reviewRunningMigrations(ctx context.Context) (countRunnning int, cancellable []*cancellableMigration, err error) {
@@ -3261,6 +3312,18 @@ func (e *Executor) reviewRunningMigrations(ctx context.Context) (countRunnning i
 						return nil
 					}
 				}
+				if !shouldForceCutOver {
+					// The keyspace may restrict when migrations cut over. A migration that is ready
+					// keeps up with its table while waiting, unless the user forces its cut-over.
+					blocker, err := e.cutOverWindowPolicyBlocker(ctx)
+					if err != nil {
+						return err
+					}
+					if blocker != "" {
+						_ = e.updateMigrationMessage(ctx, uuid, blocker)
+						return nil
+					}
+				}
 				shouldCutOver, shouldForceCutOver := shouldCutOverAccordingToBackoff(
 					shouldForceCutOver, forceCutOverAfter, sinceReadyToComplete, sinceLastCutoverAttempt, cutoverAttempts,
 				)
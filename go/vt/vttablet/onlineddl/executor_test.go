@@ -21,11 +21,17 @@ Functionality of this Executor is tested in go/test/endtoend/onlineddl/...
 package onlineddl
 
 import (
+	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle"
 )
 
 func TestShouldCutOverAccordingToBackoff(t *testing.T) {
@@ -229,3 +235,73 @@ func TestSafeMigrationCutOverThreshold(t *testing.T) {
 		})
 	}
 }
+
+func TestCutOverWindowPolicyBlocker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+
+	now := time.Now().UTC()
+	window := func(from time.Duration, to time.Duration) *topodatapb.CutOverWindowPolicy_Window {
+		return &topodatapb.CutOverWindowPolicy_Window{
+			Start: now.Add(from).Format("15:04"),
+			End:   now.Add(to).Format("15:04"),
+		}
+	}
+	tcases := []struct {
+		name   string
+		policy *topodatapb.CutOverWindowPolicy
+		expect string
+	}{
+		{
+			name: "no policy",
+		},
+		{
+			name: "open window",
+			policy: &topodatapb.CutOverWindowPolicy{
+				Windows: []*topodatapb.CutOverWindowPolicy_Window{window(-time.Hour, time.Hour)},
+			},
+		},
+		{
+			name: "closed window",
+			policy: &topodatapb.CutOverWindowPolicy{
+				Windows: []*topodatapb.CutOverWindowPolicy_Window{window(2*time.Hour, 3*time.Hour)},
+			},
+			expect: "waiting for a cut-over window",
+		},
+		{
+			// The throttler is disabled, and cannot check the load.
+			name: "load thresholds",
+			policy: &topodatapb.CutOverWindowPolicy{
+				MaxReplicationLagSeconds: 5,
+				MaxThreadsRunning:        50,
+			},
+			expect: "cut-over rejected by cut-over window policy: the tablet throttler is disabled, and cannot check the load thresholds of the policy",
+		},
+		{
+			name: "load threshold in a closed window",
+			policy: &topodatapb.CutOverWindowPolicy{
+				Windows:           []*topodatapb.CutOverWindowPolicy_Window{window(2*time.Hour, 3*time.Hour)},
+				MaxThreadsRunning: 50,
+			},
+			expect: "waiting for a cut-over window",
+		},
+	}
+	for i, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			keyspace := fmt.Sprintf("ks%d", i)
+			require.NoError(t, ts.CreateKeyspace(ctx, keyspace, &topodatapb.Keyspace{CutOverWindowPolicy: tcase.policy}))
+
+			e := &Executor{
+				ts:           ts,
+				keyspace:     keyspace,
+				lagThrottler: &throttle.Throttler{},
+			}
+			blocker, err := e.cutOverWindowPolicyBlocker(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tcase.expect, blocker)
+		})
+	}
+}
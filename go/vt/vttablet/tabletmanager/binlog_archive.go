@@ -0,0 +1,143 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletmanager
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+var (
+	binlogArchive            bool
+	binlogArchiveTabletTypes = topoproto.TabletTypeListFlag{topodatapb.TabletType_PRIMARY}
+	binlogArchiveInterval    = time.Minute
+	binlogArchiveRetention   time.Duration
+
+	statsBinlogsArchived     = stats.NewCounter("BinlogsArchived", "Number of binary logs archived to the backup storage")
+	statsBinlogArchiveErrors = stats.NewCounter("BinlogArchiveErrors", "Number of failed attempts to archive binary logs to the backup storage")
+	statsBinlogArchivePruned = stats.NewCounter("BinlogArchivePruned", "Number of archived binary logs removed from the backup storage")
+)
+
+func registerBinlogArchiveFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&binlogArchive, "binlog-archive", binlogArchive, "Archive the binary logs of this tablet to the backup storage as they are closed, so that point in time recoveries can use them. Binary logs are only archived while the tablet type is one of --binlog-archive-tablet-types.")
+	fs.Var(&binlogArchiveTabletTypes, "binlog-archive-tablet-types", "A comma-separated list of tablet types. Binary logs are only archived while the tablet is of one of these types.")
+	fs.DurationVar(&binlogArchiveInterval, "binlog-archive-interval", binlogArchiveInterval, "How often to look for closed binary logs to archive.")
+	fs.DurationVar(&binlogArchiveRetention, "binlog-archive-retention", binlogArchiveRetention, "Remove the archived binary logs of the shard whose last transaction is older than this. Set to 0 to keep archived binary logs forever.")
+}
+
+func init() {
+	servenv.OnParseFor("vttablet", registerBinlogArchiveFlags)
+}
+
+// binlogArchiveLoop periodically archives the binary logs that were closed
+// since the last archived binary log of the shard, while the tablet is of one
+// of the tablet types that archive binary logs.
+func (tm *TabletManager) binlogArchiveLoop(ctx context.Context, doneChan chan<- struct{}) {
+	defer close(doneChan)
+
+	ticker := time.NewTicker(binlogArchiveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+		tablet := tm.Tablet()
+		if !slices.Contains(binlogArchiveTabletTypes, tablet.Type) {
+			continue
+		}
+		if tm.IsBackupRunning() {
+			continue
+		}
+		tm.archiveBinlogs(ctx, tablet)
+	}
+}
+
+func (tm *TabletManager) archiveBinlogs(ctx context.Context, tablet *topodatapb.Tablet) {
+	params := mysqlctl.BackupParams{
+		Cnf:          tm.Cnf,
+		Mysqld:       tm.MysqlDaemon,
+		Logger:       logutil.NewConsoleLogger(),
+		Concurrency:  1,
+		HookExtraEnv: tm.hookExtraEnv(),
+		TopoServer:   tm.TopoServer,
+		Keyspace:     tablet.Keyspace,
+		Shard:        tablet.Shard,
+		TabletAlias:  topoproto.TabletAliasString(tablet.Alias),
+		Stats:        backupstats.BackupStats(),
+	}
+	archived, err := mysqlctl.ArchiveBinlogs(ctx, params)
+	statsBinlogsArchived.Add(int64(len(archived)))
+	if err != nil {
+		statsBinlogArchiveErrors.Add(1)
+		log.Errorf("Failed to archive binlogs: %v", err)
+		return
+	}
+	if len(archived) > 0 {
+		log.Infof("Archived binlogs: %v", archived)
+	}
+	if binlogArchiveRetention > 0 {
+		removed, err := mysqlctl.PruneBinlogArchive(ctx, params, time.Now().Add(-binlogArchiveRetention))
+		statsBinlogArchivePruned.Add(int64(len(removed)))
+		if err != nil {
+			log.Errorf("Failed to prune the binlog archive: %v", err)
+		}
+	}
+}
+
+func (tm *TabletManager) startBinlogArchive() {
+	if !binlogArchive {
+		return
+	}
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+	tm._binlogArchiveDone = make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	tm._binlogArchiveCancel = cancel
+
+	go tm.binlogArchiveLoop(ctx, tm._binlogArchiveDone)
+}
+
+func (tm *TabletManager) stopBinlogArchive() {
+	var doneChan <-chan struct{}
+
+	tm.mutex.Lock()
+	if tm._binlogArchiveCancel != nil {
+		tm._binlogArchiveCancel()
+	}
+	doneChan = tm._binlogArchiveDone
+	tm.mutex.Unlock()
+
+	// If the binlog archive loop was running, wait for it to fully stop.
+	if doneChan != nil {
+		<-doneChan
+	}
+}
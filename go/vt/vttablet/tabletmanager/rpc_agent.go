@@ -120,6 +120,7 @@ type RPCTM interface {
 	CreateVReplicationWorkflow(ctx context.Context, req *tabletmanagerdatapb.CreateVReplicationWorkflowRequest) (*tabletmanagerdatapb.CreateVReplicationWorkflowResponse, error)
 	DeleteTableData(ctx context.Context, req *tabletmanagerdatapb.DeleteTableDataRequest) (*tabletmanagerdatapb.DeleteTableDataResponse, error)
 	DeleteVReplicationWorkflow(ctx context.Context, req *tabletmanagerdatapb.DeleteVReplicationWorkflowRequest) (*tabletmanagerdatapb.DeleteVReplicationWorkflowResponse, error)
+	ExecuteFetchOnExternalMysql(ctx context.Context, req *tabletmanagerdatapb.ExecuteFetchOnExternalMysqlRequest) (*tabletmanagerdatapb.ExecuteFetchOnExternalMysqlResponse, error)
 	HasVReplicationWorkflows(ctx context.Context, req *tabletmanagerdatapb.HasVReplicationWorkflowsRequest) (*tabletmanagerdatapb.HasVReplicationWorkflowsResponse, error)
 	ReadVReplicationWorkflows(ctx context.Context, req *tabletmanagerdatapb.ReadVReplicationWorkflowsRequest) (*tabletmanagerdatapb.ReadVReplicationWorkflowsResponse, error)
 	ReadVReplicationWorkflow(ctx context.Context, req *tabletmanagerdatapb.ReadVReplicationWorkflowRequest) (*tabletmanagerdatapb.ReadVReplicationWorkflowResponse, error)
@@ -162,6 +163,10 @@ type RPCTM interface {
 
 	PromoteReplica(ctx context.Context, semiSync bool) (string, error)
 
+	InspectBinlogTransactions(ctx context.Context, gtidSet string) (*tabletmanagerdatapb.InspectBinlogTransactionsResponse, error)
+
+	InjectEmptyTransactions(ctx context.Context, gtidSet string) error
+
 	// Backup / restore related methods
 
 	Backup(ctx context.Context, logger logutil.Logger, request *tabletmanagerdatapb.BackupRequest) error
@@ -133,15 +133,11 @@ func (tm *TabletManager) Backup(ctx context.Context, logger logutil.Logger, req
 				return
 			}
 
-			durabilityName, err := tm.TopoServer.GetKeyspaceDurability(bgCtx, tablet.Keyspace)
+			durability, err := policy.GetKeyspaceDurabilityPolicy(bgCtx, tm.TopoServer, tablet.Keyspace)
 			if err != nil {
 				l.Errorf("Failed to get durability policy, error: %v", err)
 				return
 			}
-			durability, err := policy.GetDurabilityPolicy(durabilityName)
-			if err != nil {
-				l.Errorf("Failed to get durability with name %v, error: %v", durabilityName, err)
-			}
 
 			isSemiSync := policy.IsReplicaSemiSync(durability, shardPrimary.Tablet, tabletInfo.Tablet)
 			semiSyncAction, err := tm.convertBoolToSemiSyncAction(bgCtx, isSemiSync)
@@ -0,0 +1,257 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletmanager
+
+import (
+	"context"
+	"fmt"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/binlog"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// InspectBinlogTransactions reads the transactions of the given GTID set from the binary logs, and tells which of
+// them write data. The transactions that were purged from the binary logs are returned as missing.
+func (tm *TabletManager) InspectBinlogTransactions(ctx context.Context, gtidSet string) (*tabletmanagerdatapb.InspectBinlogTransactionsResponse, error) {
+	log.Infof("InspectBinlogTransactions: %v", gtidSet)
+	if err := tm.waitForGrantsToHaveApplied(ctx); err != nil {
+		return nil, err
+	}
+	set, err := replication.ParseMysql56GTIDSet(gtidSet)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "invalid GTID set %v", gtidSet)
+	}
+	executed, err := tm.executedMysql56GTIDSet(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !executed.Contains(set) {
+		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "the GTID set %v was not executed by the tablet, whose executed GTID set is %v", set, executed)
+	}
+	purgedPos, err := tm.MysqlDaemon.GetGTIDPurged(ctx)
+	if err != nil {
+		return nil, err
+	}
+	purged, _ := purgedPos.GTIDSet.(replication.Mysql56GTIDSet)
+	available := set.Difference(purged)
+	response := &tabletmanagerdatapb.InspectBinlogTransactionsResponse{
+		MissingGtidSet: set.Difference(available).String(),
+	}
+	if len(available) == 0 {
+		return response, nil
+	}
+
+	conn, err := binlog.NewBinlogConnection(tm.DBConfigs.DbaConnector())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// Only the inspected transactions are streamed, since all the other executed transactions are in the start position.
+	events, errs, err := conn.StartBinlogDumpFromPosition(ctx, "", replication.Position{GTIDSet: executed.Difference(available)})
+	if err != nil {
+		return nil, err
+	}
+	empty, write, err := classifyBinlogTransactions(ctx, events, errs, available)
+	if err != nil {
+		return nil, err
+	}
+	response.EmptyGtidSet = empty.String()
+	response.WriteGtidSet = write.String()
+	return response, nil
+}
+
+// InjectEmptyTransactions commits an empty transaction for each GTID of the given set that the tablet has not executed.
+func (tm *TabletManager) InjectEmptyTransactions(ctx context.Context, gtidSet string) error {
+	log.Infof("InjectEmptyTransactions: %v", gtidSet)
+	if err := tm.waitForGrantsToHaveApplied(ctx); err != nil {
+		return err
+	}
+	set, err := replication.ParseMysql56GTIDSet(gtidSet)
+	if err != nil {
+		return vterrors.Wrapf(err, "invalid GTID set %v", gtidSet)
+	}
+	if err := tm.lock(ctx); err != nil {
+		return err
+	}
+	defer tm.unlock()
+
+	// The replicas would get errant GTIDs if they were injected on another tablet than the primary.
+	if tabletType := tm.Tablet().Type; tabletType != topodatapb.TabletType_PRIMARY {
+		return vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "empty transactions can only be injected on a PRIMARY tablet, not on a %v tablet", tabletType)
+	}
+	executed, err := tm.executedMysql56GTIDSet(ctx)
+	if err != nil {
+		return err
+	}
+
+	conn, err := tm.MysqlDaemon.GetDbaConnection(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	defer func() {
+		if _, err := conn.ExecuteFetch("SET gtid_next = 'AUTOMATIC'", 0, false); err != nil {
+			log.Warningf("InjectEmptyTransactions failed to reset gtid_next: %v", err)
+		}
+	}()
+
+	set.Difference(executed).ForEachGTID(func(gtid replication.Mysql56GTID) bool {
+		for _, query := range []string{fmt.Sprintf("SET gtid_next = '%s'", gtid), "BEGIN", "COMMIT"} {
+			if _, err = conn.ExecuteFetch(query, 0, false); err != nil {
+				err = vterrors.Wrapf(err, "failed to inject an empty transaction for %v", gtid)
+				return false
+			}
+		}
+		return true
+	})
+	return err
+}
+
+// executedMysql56GTIDSet returns the executed GTID set of the tablet.
+func (tm *TabletManager) executedMysql56GTIDSet(ctx context.Context) (replication.Mysql56GTIDSet, error) {
+	pos, err := tm.MysqlDaemon.PrimaryPosition(ctx)
+	if err != nil {
+		return nil, err
+	}
+	executed, ok := pos.GTIDSet.(replication.Mysql56GTIDSet)
+	if !ok {
+		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "the GTID set of the tablet is not a MySQL 5.6 GTID set: %v", pos)
+	}
+	return executed, nil
+}
+
+// classifyBinlogTransactions reads the binlog events until it has seen the transactions of all the GTIDs of the set,
+// and returns the GTIDs of the transactions that do not write any data, and the GTIDs of the ones that do. The
+// transactions that only contain statements which do not change any data, such as FLUSH or ANALYZE, are empty.
+func classifyBinlogTransactions(ctx context.Context, events <-chan mysql.BinlogEvent, errs <-chan error, gtidSet replication.Mysql56GTIDSet) (empty, write replication.Mysql56GTIDSet, err error) {
+	empty, write = replication.Mysql56GTIDSet{}, replication.Mysql56GTIDSet{}
+	seen := replication.Mysql56GTIDSet{}
+	var format mysql.BinlogFormat
+	// gtid is the GTID of the current transaction, if it is one of the inspected transactions.
+	var gtid replication.GTID
+	var inBegin, writes bool
+
+	commit := func() {
+		if gtid == nil {
+			return
+		}
+		if writes {
+			write = write.AddGTID(gtid).(replication.Mysql56GTIDSet)
+		} else {
+			empty = empty.AddGTID(gtid).(replication.Mysql56GTIDSet)
+		}
+		seen = seen.AddGTID(gtid).(replication.Mysql56GTIDSet)
+		gtid = nil
+	}
+
+	for !seen.Contains(gtidSet) {
+		var ev mysql.BinlogEvent
+		var ok bool
+		select {
+		case ev, ok = <-events:
+			if !ok {
+				return nil, nil, vterrors.Errorf(vtrpc.Code_UNAVAILABLE, "the binlog stream ended before the transactions of %v were read", gtidSet.Difference(seen))
+			}
+		case err = <-errs:
+			if err == nil {
+				err = vterrors.Errorf(vtrpc.Code_UNAVAILABLE, "the binlog stream ended before the transactions of %v were read", gtidSet.Difference(seen))
+			}
+			return nil, nil, err
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+
+		if !ev.IsValid() {
+			return nil, nil, fmt.Errorf("can't parse binlog event, invalid data: %#v", ev)
+		}
+		if ev.IsFormatDescription() {
+			format, err = ev.Format()
+			if err != nil {
+				return nil, nil, fmt.Errorf("can't parse FORMAT_DESCRIPTION_EVENT: %v, event data: %#v", err, ev)
+			}
+			continue
+		}
+		if format.IsZero() {
+			// Only a fake ROTATE_EVENT can come before the FORMAT_DESCRIPTION_EVENT.
+			if ev.IsRotate() {
+				continue
+			}
+			return nil, nil, fmt.Errorf("got a real event before FORMAT_DESCRIPTION_EVENT: %#v", ev)
+		}
+		ev, _, err = ev.StripChecksum(format)
+		if err != nil {
+			return nil, nil, fmt.Errorf("can't strip checksum from binlog event: %v, event data: %#v", err, ev)
+		}
+
+		switch {
+		case ev.IsGTID():
+			commit()
+			next, hasBegin, err := ev.GTID(format)
+			if err != nil {
+				return nil, nil, fmt.Errorf("can't get GTID from binlog event: %v, event data: %#v", err, ev)
+			}
+			if gtidSet.ContainsGTID(next) {
+				gtid = next
+			}
+			inBegin, writes = hasBegin, false
+		case gtid == nil:
+			// The event is not part of an inspected transaction.
+		case ev.IsXID():
+			commit()
+		case ev.IsQuery():
+			q, err := ev.Query(format)
+			if err != nil {
+				return nil, nil, fmt.Errorf("can't get query from binlog event: %v, event data: %#v", err, ev)
+			}
+			switch sqlparser.Preview(q.SQL) {
+			case sqlparser.StmtBegin:
+				inBegin = true
+			case sqlparser.StmtCommit, sqlparser.StmtRollback:
+				commit()
+			case sqlparser.StmtSavepoint, sqlparser.StmtSRollback, sqlparser.StmtRelease,
+				sqlparser.StmtFlush, sqlparser.StmtAnalyze, sqlparser.StmtComment, sqlparser.StmtCommentOnly:
+				if !inBegin {
+					commit()
+				}
+			default:
+				writes = true
+				if !inBegin {
+					// DDLs and the other statements that are not wrapped in BEGIN/COMMIT are committed immediately.
+					commit()
+				}
+			}
+		case ev.IsWriteRows(), ev.IsUpdateRows(), ev.IsPartialUpdateRows(), ev.IsDeleteRows():
+			writes = true
+		case ev.IsTransactionPayload():
+			// A compressed transaction holds all the events of the transaction, which are only considered as writes.
+			writes = true
+			commit()
+		}
+	}
+	return empty, write, nil
+}
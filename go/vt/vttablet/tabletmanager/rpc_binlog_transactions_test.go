@@ -0,0 +1,150 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletmanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/mysql/replication"
+)
+
+func TestClassifyBinlogTransactions(t *testing.T) {
+	f := mysql.NewMySQL56BinlogFormat()
+	s := mysql.NewFakeBinlogStream()
+	sid, err := replication.ParseSID("8bc65c84-3fe4-11ed-a912-257f0fcdd6c9")
+	require.NoError(t, err)
+	gtidEvent := func(sequence int64) mysql.BinlogEvent {
+		return mysql.NewMySQL56GTIDEvent(f, s, replication.Mysql56GTID{Server: sid, Sequence: sequence})
+	}
+	queryEvent := func(sql string) mysql.BinlogEvent {
+		return mysql.NewQueryEvent(f, s, mysql.Query{Database: "vt_ks", SQL: sql})
+	}
+	writeRowsEvent := func() mysql.BinlogEvent {
+		rows := mysql.Rows{
+			IdentifyColumns: mysql.NewServerBitmap(1),
+			DataColumns:     mysql.NewServerBitmap(1),
+			Rows: []mysql.Row{{
+				NullIdentifyColumns: mysql.NewServerBitmap(1),
+				NullColumns:         mysql.NewServerBitmap(1),
+				Data:                []byte{0x01, 0x00, 0x00, 0x00},
+			}},
+		}
+		rows.DataColumns.Set(0, true)
+		return mysql.NewWriteRowsEvent(f, s, 1, rows)
+	}
+
+	tests := []struct {
+		name          string
+		gtidSet       string
+		events        []mysql.BinlogEvent
+		expectedEmpty string
+		expectedWrite string
+		expectedErr   string
+	}{
+		{
+			name:    "empty and write transactions",
+			gtidSet: "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1-6",
+			events: []mysql.BinlogEvent{
+				mysql.NewFakeRotateEvent(f, s, "binlog.000001"),
+				mysql.NewFormatDescriptionEvent(f, s),
+				// An empty transaction, as injected with gtid_next.
+				gtidEvent(1),
+				queryEvent("BEGIN"),
+				queryEvent("COMMIT"),
+				// A row based write.
+				gtidEvent(2),
+				queryEvent("BEGIN"),
+				writeRowsEvent(),
+				mysql.NewXIDEvent(f, s),
+				// A DDL, which is not wrapped in BEGIN/COMMIT.
+				gtidEvent(3),
+				queryEvent("create table t1 (id int primary key)"),
+				// Statements that do not change any data.
+				gtidEvent(4),
+				queryEvent("flush privileges"),
+				gtidEvent(5),
+				queryEvent("analyze table t1"),
+				// A statement based write.
+				gtidEvent(6),
+				queryEvent("BEGIN"),
+				queryEvent("insert into t1 values (1)"),
+				mysql.NewXIDEvent(f, s),
+			},
+			expectedEmpty: "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1:4-5",
+			expectedWrite: "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:2-3:6",
+		},
+		{
+			name:    "transactions outside of the set are ignored",
+			gtidSet: "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:2",
+			events: []mysql.BinlogEvent{
+				mysql.NewFormatDescriptionEvent(f, s),
+				gtidEvent(1),
+				queryEvent("BEGIN"),
+				writeRowsEvent(),
+				mysql.NewXIDEvent(f, s),
+				gtidEvent(2),
+				queryEvent("BEGIN"),
+				queryEvent("COMMIT"),
+			},
+			expectedEmpty: "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:2",
+		},
+		{
+			name:    "stream ends before all the transactions are read",
+			gtidSet: "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1-2",
+			events: []mysql.BinlogEvent{
+				mysql.NewFormatDescriptionEvent(f, s),
+				gtidEvent(1),
+				queryEvent("BEGIN"),
+				queryEvent("COMMIT"),
+			},
+			expectedErr: "the binlog stream ended before the transactions of 8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:2 were read",
+		},
+		{
+			name:    "event before the format description",
+			gtidSet: "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1",
+			events: []mysql.BinlogEvent{
+				gtidEvent(1),
+			},
+			expectedErr: "got a real event before FORMAT_DESCRIPTION_EVENT",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gtidSet, err := replication.ParseMysql56GTIDSet(tt.gtidSet)
+			require.NoError(t, err)
+			events := make(chan mysql.BinlogEvent, len(tt.events))
+			for _, ev := range tt.events {
+				events <- ev
+			}
+			close(events)
+			errs := make(chan error)
+
+			empty, write, err := classifyBinlogTransactions(context.Background(), events, errs, gtidSet)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedEmpty, empty.String())
+			require.Equal(t, tt.expectedWrite, write.String())
+		})
+	}
+}
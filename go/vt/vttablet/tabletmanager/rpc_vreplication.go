@@ -273,6 +273,21 @@ func (tm *TabletManager) DeleteVReplicationWorkflow(ctx context.Context, req *ta
 	return &tabletmanagerdatapb.DeleteVReplicationWorkflowResponse{Result: sqltypes.ResultToProto3(res)}, nil
 }
 
+// ExecuteFetchOnExternalMysql executes a read-only query on one of the
+// external MySQL servers that the tablet is configured to replicate from.
+func (tm *TabletManager) ExecuteFetchOnExternalMysql(ctx context.Context, req *tabletmanagerdatapb.ExecuteFetchOnExternalMysqlRequest) (*tabletmanagerdatapb.ExecuteFetchOnExternalMysqlResponse, error) {
+	if tm.VREngine == nil {
+		return nil, vterrors.New(vtrpcpb.Code_UNAVAILABLE, "vreplication engine is not available")
+	}
+	qr, err := tm.VREngine.ExecuteFetchOnExternalMysql(ctx, req.ExternalMysql, string(req.Query), int(req.MaxRows))
+	if err != nil {
+		return nil, err
+	}
+	return &tabletmanagerdatapb.ExecuteFetchOnExternalMysqlResponse{
+		Result: sqltypes.ResultToProto3(qr),
+	}, nil
+}
+
 func (tm *TabletManager) HasVReplicationWorkflows(ctx context.Context, req *tabletmanagerdatapb.HasVReplicationWorkflowsRequest) (*tabletmanagerdatapb.HasVReplicationWorkflowsResponse, error) {
 	bindVars := map[string]*querypb.BindVariable{
 		"db": sqltypes.StringBindVariable(tm.DBConfigs.DBName),
@@ -598,6 +613,9 @@ func (tm *TabletManager) UpdateVReplicationWorkflow(ctx context.Context, req *ta
 			bls.OnDdl = *req.OnDdl
 		}
 		bls.Filter.Rules = append(bls.Filter.Rules, req.FilterRules...)
+		if err := updateRenameColumns(bls, req.RenameColumns); err != nil {
+			return nil, err
+		}
 		source, err = prototext.Marshal(bls)
 		if err != nil {
 			return nil, err
@@ -647,6 +665,38 @@ func (tm *TabletManager) UpdateVReplicationWorkflow(ctx context.Context, req *ta
 	}, nil
 }
 
+// updateRenameColumns updates the rename_columns of the filter rules of a stream. The keys of
+// renameColumns are source columns, as table.column, and the values are the target columns that
+// they are replicated into. An empty value removes the mapping. The table must have its own
+// filter rule, as the mapping does not apply to the tables matched by a regular expression.
+func updateRenameColumns(bls *binlogdatapb.BinlogSource, renameColumns map[string]string) error {
+	for key, targetColumn := range renameColumns {
+		table, sourceColumn, ok := strings.Cut(key, ".")
+		if !ok || table == "" || sourceColumn == "" {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid rename_columns key %q, expected table.column", key)
+		}
+		var rule *binlogdatapb.Rule
+		for _, r := range bls.GetFilter().GetRules() {
+			if r.Match == table {
+				rule = r
+				break
+			}
+		}
+		if rule == nil {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "no filter rule for table %s to rename column %s in", table, sourceColumn)
+		}
+		if strings.TrimSpace(targetColumn) == "" {
+			delete(rule.RenameColumns, sourceColumn)
+			continue
+		}
+		if rule.RenameColumns == nil {
+			rule.RenameColumns = make(map[string]string)
+		}
+		rule.RenameColumns[sourceColumn] = targetColumn
+	}
+	return nil
+}
+
 // getOptionSetString takes the option keys passed in and creates a sql clause to update the existing options
 // field in the vreplication table. The clause is built using the json_set() for new and updated options
 // and json_remove() for deleted options, denoted by an empty value.
@@ -874,6 +874,68 @@ func TestGetOptionSetString(t *testing.T) {
 	}
 }
 
+func TestUpdateRenameColumns(t *testing.T) {
+	newSource := func() *binlogdatapb.BinlogSource {
+		return &binlogdatapb.BinlogSource{
+			Filter: &binlogdatapb.Filter{
+				Rules: []*binlogdatapb.Rule{
+					{Match: "corder"},
+					{Match: "customer", RenameColumns: map[string]string{"contact_phone": "phone"}},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name          string
+		renameColumns map[string]string
+		want          map[string]map[string]string
+		wantErr       string
+	}{
+		{
+			name: "no change",
+			want: map[string]map[string]string{
+				"customer": {"contact_phone": "phone"},
+			},
+		},
+		{
+			name:          "add and remove mappings",
+			renameColumns: map[string]string{"customer.contact_email": "email", "customer.contact_phone": "", "corder.total": "amount"},
+			want: map[string]map[string]string{
+				"corder":   {"total": "amount"},
+				"customer": {"contact_email": "email"},
+			},
+		},
+		{
+			name:          "invalid key",
+			renameColumns: map[string]string{"contact_email": "email"},
+			wantErr:       `invalid rename_columns key "contact_email", expected table.column`,
+		},
+		{
+			name:          "unknown table",
+			renameColumns: map[string]string{"product.name": "title"},
+			wantErr:       "no filter rule for table product to rename column name in",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bls := newSource()
+			err := updateRenameColumns(bls, tt.renameColumns)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			got := make(map[string]map[string]string)
+			for _, rule := range bls.Filter.Rules {
+				if len(rule.RenameColumns) > 0 {
+					got[rule.Match] = rule.RenameColumns
+				}
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestUpdateVReplicationWorkflow(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -1052,6 +1114,15 @@ func TestUpdateVReplicationWorkflow(t *testing.T) {
 			query: fmt.Sprintf(`update _vt.vreplication set state = 'Copying', source = 'keyspace:"%s" shard:"%s" filter:{rules:{match:"corder" filter:"select * from corder"} rules:{match:"customer" filter:"select * from customer"}}', cell = '%s', tablet_types = '%s', message = '' where id in (%d)`,
 				keyspace, shard, cells[0], tabletTypes[0], vreplID),
 		},
+		{
+			name: "update rename_columns",
+			request: &tabletmanagerdatapb.UpdateVReplicationWorkflowRequest{
+				Workflow:      workflow,
+				RenameColumns: map[string]string{"customer.contact_email": "email"},
+			},
+			query: fmt.Sprintf(`update _vt.vreplication set state = 'Running', source = 'keyspace:"%s" shard:"%s" filter:{rules:{match:"corder" filter:"select * from corder"} rules:{match:"customer" filter:"select * from customer" rename_columns:{key:"contact_email" value:"email"}}}', cell = '', tablet_types = '', message = '' where id in (%d)`,
+				keyspace, shard, vreplID),
+		},
 		{
 			name: "update cells and options",
 			request: &tabletmanagerdatapb.UpdateVReplicationWorkflowRequest{
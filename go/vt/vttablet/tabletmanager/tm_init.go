@@ -209,6 +209,13 @@ type TabletManager struct {
 	// _shardSyncCancel is the function to stop the background shard sync goroutine.
 	_shardSyncCancel context.CancelFunc
 
+	// _binlogArchiveDone is a channel for waiting until the binlog archive
+	// goroutine has really finished after _binlogArchiveCancel was called.
+	_binlogArchiveDone chan struct{}
+
+	// _binlogArchiveCancel is the function to stop the background binlog archive goroutine.
+	_binlogArchiveCancel context.CancelFunc
+
 	// _rebuildKeyspaceDone is a channel for waiting until the current keyspace
 	// has been rebuilt
 	_rebuildKeyspaceDone chan struct{}
@@ -424,6 +431,7 @@ func (tm *TabletManager) Start(tablet *topodatapb.Tablet, config *tabletenv.Tabl
 	// The following initializations don't need to be done
 	// in any specific order.
 	tm.startShardSync()
+	tm.startBinlogArchive()
 	tm.exportStats()
 	servenv.OnRun(tm.registerTabletManager)
 
@@ -461,6 +469,7 @@ func (tm *TabletManager) Close() {
 	// rather than registering it as an OnTerm hook so the shard sync loop keeps
 	// running during lame duck.
 	tm.stopShardSync()
+	tm.stopBinlogArchive()
 	tm.stopRebuildKeyspace()
 
 	// cleanup initialized fields in the tablet entry
@@ -492,6 +501,7 @@ func (tm *TabletManager) Stop() {
 	// Stop the shard sync loop and wait for it to exit. This needs to be done
 	// here in addition to in Close() because tests do not call Close().
 	tm.stopShardSync()
+	tm.stopBinlogArchive()
 	tm.stopRebuildKeyspace()
 
 	if tm.QueryServiceControl != nil {
@@ -1009,14 +1019,10 @@ func (tm *TabletManager) initializeReplication(ctx context.Context, tabletType t
 		return "", vterrors.Wrapf(err, "cannot read primary tablet %v", si.PrimaryAlias)
 	}
 
-	durabilityName, err := tm.TopoServer.GetKeyspaceDurability(ctx, tablet.Keyspace)
-	if err != nil {
-		return "", vterrors.Wrapf(err, "cannot read keyspace durability policy %v", tablet.Keyspace)
-	}
-	log.Infof("Getting a new durability policy for %v", durabilityName)
-	durability, err := policy.GetDurabilityPolicy(durabilityName)
+	log.Infof("Getting a new durability policy for keyspace %v", tablet.Keyspace)
+	durability, err := policy.GetKeyspaceDurabilityPolicy(ctx, tm.TopoServer, tablet.Keyspace)
 	if err != nil {
-		return "", vterrors.Wrapf(err, "cannot get durability policy %v", durabilityName)
+		return "", vterrors.Wrapf(err, "cannot get durability policy of keyspace %v", tablet.Keyspace)
 	}
 	// If using semi-sync, we need to enable it before connecting to primary.
 	// We should set the correct type, since it is used in replica semi-sync
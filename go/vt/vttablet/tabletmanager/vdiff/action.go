@@ -248,6 +248,13 @@ func (vde *Engine) handleCreateResumeAction(ctx context.Context, dbClient binlog
 		if err != nil {
 			return err
 		}
+		if rowsAffected > 0 {
+			// Continue diffing the tables that were diffed in chunks from
+			// the end of their last chunk.
+			if _, err := execResume(sqlResumeVDiffChunks); err != nil {
+				return err
+			}
+		}
 		if rowsAffected == 0 { // See if it's a vdiff that was never started
 			rowsAffected, err := execResume(sqlStartVDiff)
 			if err != nil {
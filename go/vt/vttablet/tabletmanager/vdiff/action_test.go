@@ -221,6 +221,10 @@ func TestPerformVDiffAction(t *testing.T) {
 						RowsAffected: 1,
 					},
 				},
+				{
+					query: fmt.Sprintf(`update _vt.vdiff as vd, _vt.vdiff_table_chunk as vdtc set vdtc.state = 'pending'
+					where vd.vdiff_uuid = %s and vd.id = vdtc.vdiff_id and vdtc.upper_pk is NULL`, encodeString(uuid)),
+				},
 				{
 					query: "select * from _vt.vdiff where id = 1",
 				},
@@ -244,7 +248,8 @@ func TestPerformVDiffAction(t *testing.T) {
 					),
 				},
 				{
-					query: fmt.Sprintf(`delete from vd, vdt using _vt.vdiff as vd left join _vt.vdiff_table as vdt on (vd.id = vdt.vdiff_id)
+					query: fmt.Sprintf(`delete from vd, vdt, vdtc using _vt.vdiff as vd left join _vt.vdiff_table as vdt on (vd.id = vdt.vdiff_id)
+							left join _vt.vdiff_table_chunk as vdtc on (vd.id = vdtc.vdiff_id)
 							where vd.vdiff_uuid = %s`, encodeString(uuid)),
 				},
 			},
@@ -270,7 +275,8 @@ func TestPerformVDiffAction(t *testing.T) {
 					),
 				},
 				{
-					query: fmt.Sprintf(`delete from vd, vdt, vdl using _vt.vdiff as vd left join _vt.vdiff_table as vdt on (vd.id = vdt.vdiff_id)
+					query: fmt.Sprintf(`delete from vd, vdt, vdtc, vdl using _vt.vdiff as vd left join _vt.vdiff_table as vdt on (vd.id = vdt.vdiff_id)
+										left join _vt.vdiff_table_chunk as vdtc on (vd.id = vdtc.vdiff_id)
 										left join _vt.vdiff_log as vdl on (vd.id = vdl.vdiff_id)
 										where vd.keyspace = %s and vd.workflow = %s`, encodeString(keyspace), encodeString(workflow)),
 				},
@@ -69,9 +69,8 @@ type controller struct {
 	sourceKeyspace string
 	tmc            tmclient.TabletManagerClient
 
-	targetShardStreamer *shardStreamer
-	filter              *binlogdatapb.Filter            // VReplication row filter
-	options             *tabletmanagerdata.VDiffOptions // Options initially from vtctld command and later from _vt.vdiff
+	filter  *binlogdatapb.Filter            // VReplication row filter
+	options *tabletmanagerdata.VDiffOptions // Options initially from vtctld command and later from _vt.vdiff
 
 	sourceTimeZone, targetTimeZone string // Named time zones if conversions are necessary for datetime values
 
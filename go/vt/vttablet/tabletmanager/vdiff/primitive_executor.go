@@ -48,6 +48,11 @@ type primitiveExecutor struct {
 	resultch chan *sqltypes.Result
 	err      error
 
+	// pastEnd, if set, reports whether a row is beyond the range being
+	// diffed. The first such row ends the iteration.
+	pastEnd func(row []sqltypes.Value) (bool, error)
+	ended   bool
+
 	name string // for debug purposes only
 }
 
@@ -78,6 +83,9 @@ func newPrimitiveExecutor(ctx context.Context, prim vtgateEngine.Primitive, name
 // next gets the next row in the stream for this shard, if there's currently no rows to process in the stream then wait on the
 // result channel for the shard streamer to produce them.
 func (pe *primitiveExecutor) next() ([]sqltypes.Value, error) {
+	if pe.ended {
+		return nil, nil
+	}
 	for len(pe.rows) == 0 {
 		qr, ok := <-pe.resultch
 		if !ok {
@@ -88,6 +96,16 @@ func (pe *primitiveExecutor) next() ([]sqltypes.Value, error) {
 
 	row := pe.rows[0]
 	pe.rows = pe.rows[1:]
+	if pe.pastEnd != nil {
+		past, err := pe.pastEnd(row)
+		if err != nil {
+			return nil, err
+		}
+		if past {
+			pe.ended = true
+			return nil, nil
+		}
+	}
 	return row, nil
 }
 
@@ -22,6 +22,10 @@ const (
 	sqlResumeVDiff  = `update _vt.vdiff as vd, _vt.vdiff_table as vdt set vd.started_at = NULL, vd.completed_at = NULL, vd.state = 'pending',
 					vdt.state = 'pending' where vd.vdiff_uuid = %a and vd.id = vdt.vdiff_id and vd.state in ('completed', 'stopped')
 					and vdt.state in ('completed', 'stopped')`
+	// sqlResumeVDiffChunks resumes the last, open ended, chunk of each table so that
+	// the rows added since the vdiff completed are diffed.
+	sqlResumeVDiffChunks = `update _vt.vdiff as vd, _vt.vdiff_table_chunk as vdtc set vdtc.state = 'pending'
+					where vd.vdiff_uuid = %a and vd.id = vdtc.vdiff_id and vdtc.upper_pk is NULL`
 	sqlStartVDiff = `update _vt.vdiff as vd set vd.state = 'pending' where vd.vdiff_uuid = %a and vd.state = 'stopped' and
 					vd.started_at is NULL and vd.completed_at is NULL and
 					(select count(*) as cnt from _vt.vdiff_table as vdt where vd.id = vdt.vdiff_id) = 0`
@@ -30,10 +34,12 @@ const (
 	sqlGetVDiffByKeyspaceWorkflowUUID       = "select * from _vt.vdiff where keyspace = %a and workflow = %a and vdiff_uuid = %a"
 	sqlGetMostRecentVDiffByKeyspaceWorkflow = "select * from _vt.vdiff where keyspace = %a and workflow = %a order by id desc limit %a"
 	sqlGetVDiffByID                         = "select * from _vt.vdiff where id = %a"
-	sqlDeleteVDiffs                         = `delete from vd, vdt, vdl using _vt.vdiff as vd left join _vt.vdiff_table as vdt on (vd.id = vdt.vdiff_id)
+	sqlDeleteVDiffs                         = `delete from vd, vdt, vdtc, vdl using _vt.vdiff as vd left join _vt.vdiff_table as vdt on (vd.id = vdt.vdiff_id)
+										left join _vt.vdiff_table_chunk as vdtc on (vd.id = vdtc.vdiff_id)
 										left join _vt.vdiff_log as vdl on (vd.id = vdl.vdiff_id)
 										where vd.keyspace = %a and vd.workflow = %a`
-	sqlDeleteVDiffByUUID = `delete from vd, vdt using _vt.vdiff as vd left join _vt.vdiff_table as vdt on (vd.id = vdt.vdiff_id)
+	sqlDeleteVDiffByUUID = `delete from vd, vdt, vdtc using _vt.vdiff as vd left join _vt.vdiff_table as vdt on (vd.id = vdt.vdiff_id)
+							left join _vt.vdiff_table_chunk as vdtc on (vd.id = vdtc.vdiff_id)
 							where vd.vdiff_uuid = %a`
 	sqlVDiffSummary = `select vd.state as vdiff_state, vd.last_error as last_error, vdt.table_name as table_name,
 						vd.vdiff_uuid as 'uuid', vdt.state as table_state, vdt.table_rows as table_rows,
@@ -65,5 +71,20 @@ const (
 	sqlUpdateTableStateAndReport = "update _vt.vdiff_table set state = %a, rows_compared = %a, report = %a where vdiff_id = %a and table_name = %a"
 	sqlUpdateTableMismatch       = "update _vt.vdiff_table set mismatch = true where vdiff_id = %a and table_name = %a"
 
+	sqlNewVDiffTableChunk       = "insert into _vt.vdiff_table_chunk(vdiff_id, table_name, chunk_num, state, lower_pk, upper_pk) values(%a, %a, %a, 'pending', %a, %a)"
+	sqlGetVDiffTableChunks      = "select chunk_num as chunk_num, state as state, lower_pk as lower_pk, upper_pk as upper_pk, lastpk as lastpk, rows_compared as rows_compared, mismatch as mismatch from _vt.vdiff_table_chunk where vdiff_id = %a and table_name = %a order by chunk_num"
+	sqlUpdateTableChunkProgress = "update _vt.vdiff_table_chunk set state = %a, rows_compared = %a, mismatch = %a, lastpk = %a where vdiff_id = %a and table_name = %a and chunk_num = %a"
+	// sqlGetChunkUpperPK has placeholders for the PK column list, the table, an optional
+	// where clause, the PK column list again for the ordering, and the chunk size minus one.
+	sqlGetChunkUpperPK = "select %s from %s%s order by %s limit %d, 1"
+
+	sqlGetRediffTable = `select vdt.state as state, vdt.mismatch as mismatch
+						from _vt.vdiff as vd inner join _vt.vdiff_table as vdt on (vd.id = vdt.vdiff_id)
+						where vd.vdiff_uuid = %a and vd.keyspace = %a and vd.workflow = %a and vdt.table_name = %a`
+	sqlGetRediffTableChunks = `select vdtc.lower_pk as lower_pk, vdtc.upper_pk as upper_pk, vdtc.mismatch as mismatch
+						from _vt.vdiff as vd inner join _vt.vdiff_table_chunk as vdtc on (vd.id = vdtc.vdiff_id)
+						where vd.vdiff_uuid = %a and vd.keyspace = %a and vd.workflow = %a and vdtc.table_name = %a
+						order by vdtc.chunk_num`
+
 	sqlGetIncompleteTables = "select table_name as table_name from _vt.vdiff_table where vdiff_id = %a and state != 'completed' order by table_name"
 )
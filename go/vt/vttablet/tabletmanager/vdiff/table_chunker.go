@@ -0,0 +1,592 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vdiff
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/protobuf/encoding/prototext"
+
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+/*
+	A table can be diffed in PK chunks, either because the chunk-rows option was
+	specified or because only some PK ranges of the table are rediffed. Each chunk
+	is diffed using its own consistent snapshot, and its state and progress are
+	recorded in the _vt.vdiff_table_chunk table so that the diff can be resumed
+	from the last chunk that was being diffed, e.g. after a tablet restart.
+
+	A chunk covers the PK range (lower_pk, upper_pk]. A NULL lower_pk means the
+	range starts with the first row of the table, and a NULL upper_pk means it
+	ends with the last row.
+
+	When the chunk-rows option is used, the next chunk's upper bound is looked
+	up on the target table just before the chunk is diffed, so there's no need to
+	scan the whole PK index up front. When rediffing, all of the chunks are known
+	up front and no others are added.
+
+	Up to chunk-concurrency chunks of a table are diffed at the same time. Each
+	one has its own differ, with its own shard streamers and diff report. The
+	chunks' reports are merged into the table's report, which also covers the
+	rows that had been compared before the vdiff was resumed.
+*/
+
+// tableChunk is a PK range of a table that is diffed on its own.
+type tableChunk struct {
+	num          int64
+	state        VDiffState
+	lowerPK      *tabletmanagerdatapb.VDiffTableLastPK
+	upperPK      *tabletmanagerdatapb.VDiffTableLastPK
+	lastPK       *tabletmanagerdatapb.VDiffTableLastPK
+	rowsCompared int64
+	mismatch     bool
+
+	// baseRows is the number of the chunk's rows that had been compared before
+	// the chunk's diff started, and report is the diff report of the rows that
+	// were compared since then.
+	baseRows int64
+	report   *DiffReport
+	// limitReached is set when the chunk's diff is stopped by the row limit.
+	limitReached bool
+}
+
+// diffCount returns the number of rows in the report that did not match.
+func diffCount(dr *DiffReport) int64 {
+	return dr.MismatchedRows + dr.ExtraRowsSource + dr.ExtraRowsTarget
+}
+
+// begin starts a new diff report for the chunk, when the chunk's diff starts.
+func (chunk *tableChunk) begin(tableName string) {
+	chunk.baseRows = chunk.rowsCompared
+	chunk.report = &DiffReport{TableName: tableName}
+	if chunk.state != CompletedState {
+		chunk.state = StartedState
+	}
+}
+
+// record updates the chunk's progress from the chunk's diff report.
+func (chunk *tableChunk) record(dr *DiffReport, lastPK *tabletmanagerdatapb.VDiffTableLastPK) {
+	chunk.rowsCompared = chunk.baseRows + dr.ProcessedRows
+	if diffCount(dr) > 0 {
+		chunk.mismatch = true
+	}
+	if lastPK != nil {
+		chunk.lastPK = lastPK
+	}
+}
+
+// startPK returns the PK that the chunk's diff should continue after.
+func (chunk *tableChunk) startPK() *tabletmanagerdatapb.VDiffTableLastPK {
+	if chunk.lastPK != nil {
+		return chunk.lastPK
+	}
+	return chunk.lowerPK
+}
+
+// chunked returns true if the tables in the vdiff may be diffed in chunks.
+func (wd *workflowDiffer) chunked() bool {
+	return wd.opts.CoreOptions.GetChunkRows() > 0 || wd.opts.CoreOptions.GetRediffUuid() != ""
+}
+
+// chunkConcurrency returns the maximum number of chunks of a table that are
+// diffed at the same time.
+func (wd *workflowDiffer) chunkConcurrency() int {
+	return max(1, int(wd.opts.CoreOptions.GetChunkConcurrency()))
+}
+
+// chunkedTableDiff is the state shared by the differs of a table's chunks.
+type chunkedTableDiff struct {
+	// rowsLeft is the number of rows that can still be compared, as the row
+	// limit applies to the table as a whole.
+	rowsLeft atomic.Int64
+
+	maxExtraRowsToCompare int64
+	maxReportSampleRows   int64
+
+	mu sync.Mutex
+	// base is the table's diff report from before the chunks' diffs started.
+	base *DiffReport
+	// reports are copies of the chunks' diff reports, by chunk number.
+	reports map[int64]*DiffReport
+}
+
+func newChunkedTableDiff(base *DiffReport, opts *tabletmanagerdatapb.VDiffOptions) *chunkedTableDiff {
+	ctd := &chunkedTableDiff{
+		maxExtraRowsToCompare: opts.CoreOptions.GetMaxExtraRowsToCompare(),
+		maxReportSampleRows:   opts.ReportOptions.GetMaxSampleRows(),
+		base:                  base,
+		reports:               make(map[int64]*DiffReport),
+	}
+	ctd.rowsLeft.Store(opts.CoreOptions.GetMaxRows() - base.ProcessedRows)
+	return ctd
+}
+
+// diffReport returns the table's diff report, merged from the base report and
+// the chunks' reports. It must be called with the mutex held.
+func (ctd *chunkedTableDiff) diffReport() *DiffReport {
+	dr := &DiffReport{TableName: ctd.base.TableName}
+	merge := func(r *DiffReport) {
+		dr.ProcessedRows += r.ProcessedRows
+		dr.MatchingRows += r.MatchingRows
+		dr.MismatchedRows += r.MismatchedRows
+		dr.ExtraRowsSource += r.ExtraRowsSource
+		dr.ExtraRowsTarget += r.ExtraRowsTarget
+		dr.ExtraRowsSourceDiffs = append(dr.ExtraRowsSourceDiffs, r.ExtraRowsSourceDiffs...)
+		dr.ExtraRowsTargetDiffs = append(dr.ExtraRowsTargetDiffs, r.ExtraRowsTargetDiffs...)
+		dr.MismatchedRowsDiffs = append(dr.MismatchedRowsDiffs, r.MismatchedRowsDiffs...)
+	}
+	merge(ctd.base)
+	for _, num := range slices.Sorted(maps.Keys(ctd.reports)) {
+		merge(ctd.reports[num])
+	}
+	// Each chunk's samples are capped, but not their sum.
+	if n := ctd.maxExtraRowsToCompare; n > 0 && int64(len(dr.ExtraRowsSourceDiffs)) > n {
+		dr.ExtraRowsSourceDiffs = dr.ExtraRowsSourceDiffs[:n]
+	}
+	if n := ctd.maxExtraRowsToCompare; n > 0 && int64(len(dr.ExtraRowsTargetDiffs)) > n {
+		dr.ExtraRowsTargetDiffs = dr.ExtraRowsTargetDiffs[:n]
+	}
+	if n := ctd.maxReportSampleRows; n > 0 && int64(len(dr.MismatchedRowsDiffs)) > n {
+		dr.MismatchedRowsDiffs = dr.MismatchedRowsDiffs[:n]
+	}
+	return dr
+}
+
+// report returns the table's diff report.
+func (ctd *chunkedTableDiff) report() *DiffReport {
+	ctd.mu.Lock()
+	defer ctd.mu.Unlock()
+	return ctd.diffReport()
+}
+
+// saveReport records the progress of a chunk's diff, and saves the table's
+// merged diff report.
+func (ctd *chunkedTableDiff) saveReport(dbClient binlogplayer.DBClient, td *tableDiffer, dr *DiffReport) error {
+	ctd.mu.Lock()
+	defer ctd.mu.Unlock()
+	// The chunk's differ keeps updating its report, so we keep a copy.
+	ctd.reports[td.chunk.num] = &DiffReport{
+		TableName:            dr.TableName,
+		ProcessedRows:        dr.ProcessedRows,
+		MatchingRows:         dr.MatchingRows,
+		MismatchedRows:       dr.MismatchedRows,
+		ExtraRowsSource:      dr.ExtraRowsSource,
+		ExtraRowsTarget:      dr.ExtraRowsTarget,
+		ExtraRowsSourceDiffs: slices.Clone(dr.ExtraRowsSourceDiffs),
+		ExtraRowsTargetDiffs: slices.Clone(dr.ExtraRowsTargetDiffs),
+		MismatchedRowsDiffs:  slices.Clone(dr.MismatchedRowsDiffs),
+	}
+	merged := ctd.diffReport()
+	rpt, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+	query, err := sqlparser.ParseAndBind(sqlUpdateTableNoProgress,
+		sqltypes.Int64BindVariable(merged.ProcessedRows),
+		sqltypes.StringBindVariable(string(rpt)),
+		sqltypes.Int64BindVariable(td.wd.ct.id),
+		sqltypes.StringBindVariable(td.table.Name),
+	)
+	if err != nil {
+		return err
+	}
+	_, err = dbClient.ExecuteFetch(query, 1)
+	return err
+}
+
+// newChunkDiffer returns a differ for one of the table's chunks. It has its
+// own shard streamers, so that the chunk can be diffed at the same time as
+// the table's other chunks, using its own snapshots.
+func (td *tableDiffer) newChunkDiffer(chunk *tableChunk, chunks *chunkedTableDiff) *tableDiffer {
+	cd := newTableDiffer(td.wd, td.table, td.sourceQuery)
+	cd.tablePlan = td.tablePlan
+	cd.chunk, cd.chunks = chunk, chunks
+	cd.sources = make(map[string]*migrationSource, len(td.sources))
+	for shard, source := range td.sources {
+		cd.sources[shard] = &migrationSource{
+			shardStreamer: &shardStreamer{shard: source.shard},
+			vrID:          source.vrID,
+		}
+	}
+	if startPK := chunk.startPK(); startPK != nil {
+		cd.lastSourcePK, cd.lastTargetPK = startPK.Target, startPK.Target
+	}
+	chunk.begin(td.table.Name)
+	return cd
+}
+
+// updateChunkedTableProgress is updateTableProgress for the differ of a chunk.
+// It saves the chunk's progress, along with the table's merged diff report.
+func (td *tableDiffer) updateChunkedTableProgress(dbClient binlogplayer.DBClient, dr *DiffReport, lastRow []sqltypes.Value) error {
+	var lastPK *tabletmanagerdatapb.VDiffTableLastPK
+	if lastRow != nil {
+		lastPK = td.lastPKFromRow(lastRow)
+		td.setLastPK(lastPK)
+	}
+	td.chunk.record(dr, lastPK)
+	if err := td.chunks.saveReport(dbClient, td, dr); err != nil {
+		return err
+	}
+	if err := td.updateChunkProgress(dbClient); err != nil {
+		return err
+	}
+	td.wd.ct.TableDiffRowCounts.Add(td.table.Name, dr.ProcessedRows)
+	return nil
+}
+
+// canChunk returns true if the table can be diffed in PK chunks. This
+// requires the source and target to have the same PK, which is used to
+// bound the chunks on both sides, and that the rows not be aggregated.
+func (td *tableDiffer) canChunk() bool {
+	tp := td.tablePlan
+	return len(tp.pkCols) > 0 && slices.Equal(tp.pkCols, tp.sourcePkCols) && len(tp.aggregates) == 0
+}
+
+func unmarshalTableLastPK(val []byte) (*tabletmanagerdatapb.VDiffTableLastPK, error) {
+	if len(val) == 0 {
+		return nil, nil
+	}
+	lastPK := &tabletmanagerdatapb.VDiffTableLastPK{}
+	if err := prototext.Unmarshal(val, lastPK); err != nil {
+		return nil, vterrors.Wrapf(err, "failed to unmarshal pk value %s", string(val))
+	}
+	return lastPK, nil
+}
+
+func marshalTableLastPK(lastPK *tabletmanagerdatapb.VDiffTableLastPK) (*querypb.BindVariable, error) {
+	if lastPK == nil {
+		return sqltypes.NullBindVariable, nil
+	}
+	val, err := prototext.Marshal(lastPK)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to marshal pk value %+v", lastPK)
+	}
+	return sqltypes.StringBindVariable(string(val)), nil
+}
+
+// getChunks returns the table's chunks for this vdiff, if any.
+func (td *tableDiffer) getChunks(dbClient binlogplayer.DBClient) ([]*tableChunk, error) {
+	query, err := sqlparser.ParseAndBind(sqlGetVDiffTableChunks,
+		sqltypes.Int64BindVariable(td.wd.ct.id),
+		sqltypes.StringBindVariable(td.table.Name),
+	)
+	if err != nil {
+		return nil, err
+	}
+	qr, err := dbClient.ExecuteFetch(query, -1)
+	if err != nil {
+		return nil, err
+	}
+	chunks := make([]*tableChunk, 0, len(qr.Rows))
+	for _, row := range qr.Named().Rows {
+		chunk := &tableChunk{
+			state:    VDiffState(row.AsString("state", "")),
+			mismatch: row.AsBool("mismatch", false),
+		}
+		if chunk.num, err = row.ToInt64("chunk_num"); err != nil {
+			return nil, err
+		}
+		if chunk.rowsCompared, err = row.ToInt64("rows_compared"); err != nil {
+			return nil, err
+		}
+		if chunk.lowerPK, err = unmarshalTableLastPK(row.AsBytes("lower_pk", nil)); err != nil {
+			return nil, err
+		}
+		if chunk.upperPK, err = unmarshalTableLastPK(row.AsBytes("upper_pk", nil)); err != nil {
+			return nil, err
+		}
+		if chunk.lastPK, err = unmarshalTableLastPK(row.AsBytes("lastpk", nil)); err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
+
+// insertChunk records a new chunk for the table.
+func (td *tableDiffer) insertChunk(dbClient binlogplayer.DBClient, chunk *tableChunk) error {
+	lower, err := marshalTableLastPK(chunk.lowerPK)
+	if err != nil {
+		return err
+	}
+	upper, err := marshalTableLastPK(chunk.upperPK)
+	if err != nil {
+		return err
+	}
+	query, err := sqlparser.ParseAndBind(sqlNewVDiffTableChunk,
+		sqltypes.Int64BindVariable(td.wd.ct.id),
+		sqltypes.StringBindVariable(td.table.Name),
+		sqltypes.Int64BindVariable(chunk.num),
+		lower,
+		upper,
+	)
+	if err != nil {
+		return err
+	}
+	_, err = dbClient.ExecuteFetch(query, 1)
+	return err
+}
+
+// updateChunkProgress saves the state and progress of the current chunk.
+func (td *tableDiffer) updateChunkProgress(dbClient binlogplayer.DBClient) error {
+	chunk := td.chunk
+	lastPK, err := marshalTableLastPK(chunk.lastPK)
+	if err != nil {
+		return err
+	}
+	query, err := sqlparser.ParseAndBind(sqlUpdateTableChunkProgress,
+		sqltypes.StringBindVariable(string(chunk.state)),
+		sqltypes.Int64BindVariable(chunk.rowsCompared),
+		sqltypes.BoolBindVariable(chunk.mismatch),
+		lastPK,
+		sqltypes.Int64BindVariable(td.wd.ct.id),
+		sqltypes.StringBindVariable(td.table.Name),
+		sqltypes.Int64BindVariable(chunk.num),
+	)
+	if err != nil {
+		return err
+	}
+	_, err = dbClient.ExecuteFetch(query, 1)
+	return err
+}
+
+// buildChunkUpperPKQuery returns the query that finds the PK of the chunkRows'th
+// row after the given lower bound.
+func buildChunkUpperPKQuery(dbName, tableName string, pkColumns []string, lower []sqltypes.Value, chunkRows int64) string {
+	cols := strings.Join(sqlescape.EscapeIDs(pkColumns), ", ")
+	where := ""
+	if len(lower) != 0 {
+		var buf strings.Builder
+		buf.WriteString(" where (")
+		buf.WriteString(cols)
+		buf.WriteString(") > (")
+		for i, val := range lower {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			val.EncodeSQLStringBuilder(&buf)
+		}
+		buf.WriteString(")")
+		where = buf.String()
+	}
+	table := fmt.Sprintf("%s.%s", sqlescape.EscapeID(dbName), sqlescape.EscapeID(tableName))
+	return fmt.Sprintf(sqlGetChunkUpperPK, cols, table, where, cols, chunkRows-1)
+}
+
+// nextChunkUpperPK returns the upper bound of the chunk that starts after the
+// given lower bound, using the rows in the target table. It returns nil if
+// there are no more than chunkRows rows left, in which case the chunk is the
+// table's last one.
+func (td *tableDiffer) nextChunkUpperPK(dbClient binlogplayer.DBClient, lowerPK *tabletmanagerdatapb.VDiffTableLastPK, chunkRows int64) (*tabletmanagerdatapb.VDiffTableLastPK, error) {
+	var lower []sqltypes.Value
+	if lowerPK != nil {
+		qr := sqltypes.Proto3ToResult(lowerPK.Target)
+		if len(qr.Rows) != 1 {
+			return nil, fmt.Errorf("invalid chunk bound %v for table %s", lowerPK, td.table.Name)
+		}
+		lower = qr.Rows[0]
+	}
+	query := buildChunkUpperPKQuery(td.wd.ct.vde.dbName, td.table.Name, td.table.PrimaryKeyColumns, lower, chunkRows)
+	qr, err := dbClient.ExecuteFetch(query, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(qr.Rows) == 0 {
+		return nil, nil
+	}
+	pkFields := make([]*querypb.Field, len(td.tablePlan.pkCols))
+	for i, colIndex := range td.tablePlan.pkCols {
+		pkFields[i] = td.tablePlan.table.Fields[colIndex]
+	}
+	return &tabletmanagerdatapb.VDiffTableLastPK{
+		Target: &querypb.QueryResult{
+			Fields: pkFields,
+			Rows:   []*querypb.Row{sqltypes.RowToProto3(qr.Rows[0])},
+		},
+	}, nil
+}
+
+// nextChunk adds the chunk that follows the given one, when the table is
+// being diffed using the chunk-rows option. It returns nil if the given
+// chunk is the table's last one.
+func (td *tableDiffer) nextChunk(dbClient binlogplayer.DBClient, prev *tableChunk) (*tableChunk, error) {
+	chunkRows := td.wd.opts.CoreOptions.GetChunkRows()
+	if chunkRows <= 0 || td.wd.opts.CoreOptions.GetRediffUuid() != "" {
+		return nil, nil
+	}
+	chunk := &tableChunk{num: 1, state: PendingState}
+	if prev != nil {
+		if prev.upperPK == nil {
+			return nil, nil
+		}
+		chunk.num = prev.num + 1
+		chunk.lowerPK = prev.upperPK
+	}
+	upperPK, err := td.nextChunkUpperPK(dbClient, chunk.lowerPK, chunkRows)
+	if err != nil {
+		return nil, err
+	}
+	chunk.upperPK = upperPK
+	if err := td.insertChunk(dbClient, chunk); err != nil {
+		return nil, err
+	}
+	return chunk, nil
+}
+
+// planChunks decides how a table that has not been diffed yet is diffed. It
+// returns the table's chunks, if it is diffed in chunks, or skip as true if
+// the table doesn't need to be diffed at all: when rediffing, a table that
+// had no differences in the previous vdiff is skipped, and only the chunks
+// that had differences are diffed again.
+func (td *tableDiffer) planChunks(dbClient binlogplayer.DBClient) (chunks []*tableChunk, skip bool, err error) {
+	if !td.canChunk() {
+		log.Infof("Table %s cannot be diffed in chunks for vdiff %s", td.table.Name, td.wd.ct.uuid)
+		return nil, false, nil
+	}
+	coreOpts := td.wd.opts.CoreOptions
+	if coreOpts.GetRediffUuid() == "" {
+		chunk, err := td.nextChunk(dbClient, nil)
+		if err != nil || chunk == nil {
+			return nil, false, err
+		}
+		return []*tableChunk{chunk}, false, nil
+	}
+
+	ranges, skip, err := td.getRediffRanges(dbClient)
+	if err != nil || skip {
+		return nil, skip, err
+	}
+	if ranges == nil && coreOpts.GetChunkRows() > 0 {
+		// The whole table is rediffed, using chunk-rows sized chunks.
+		var lowerPK *tabletmanagerdatapb.VDiffTableLastPK
+		for {
+			upperPK, err := td.nextChunkUpperPK(dbClient, lowerPK, coreOpts.GetChunkRows())
+			if err != nil {
+				return nil, false, err
+			}
+			ranges = append(ranges, &tableChunk{lowerPK: lowerPK, upperPK: upperPK})
+			if upperPK == nil {
+				break
+			}
+			lowerPK = upperPK
+		}
+	}
+	for i, chunk := range ranges {
+		chunk.num = int64(i + 1)
+		chunk.state = PendingState
+		if err := td.insertChunk(dbClient, chunk); err != nil {
+			return nil, false, err
+		}
+	}
+	return ranges, false, nil
+}
+
+// getRediffRanges returns the PK ranges of the table that had differences in
+// the vdiff being rediffed. It returns no ranges if the whole table needs to be
+// diffed again, and skip as true if the table had no differences.
+func (td *tableDiffer) getRediffRanges(dbClient binlogplayer.DBClient) (ranges []*tableChunk, skip bool, err error) {
+	ct := td.wd.ct
+	binds := []*querypb.BindVariable{
+		sqltypes.StringBindVariable(td.wd.opts.CoreOptions.GetRediffUuid()),
+		sqltypes.StringBindVariable(ct.vde.thisTablet.Keyspace),
+		sqltypes.StringBindVariable(ct.workflow),
+		sqltypes.StringBindVariable(td.table.Name),
+	}
+	query, err := sqlparser.ParseAndBind(sqlGetRediffTable, binds...)
+	if err != nil {
+		return nil, false, err
+	}
+	qr, err := dbClient.ExecuteFetch(query, 1)
+	if err != nil {
+		return nil, false, err
+	}
+	row := qr.Named().Row()
+	if row == nil || VDiffState(row.AsString("state", "")) != CompletedState {
+		// The table was not diffed, or not completely, so it's diffed in full.
+		return nil, false, nil
+	}
+	if !row.AsBool("mismatch", false) {
+		return nil, true, nil
+	}
+
+	query, err = sqlparser.ParseAndBind(sqlGetRediffTableChunks, binds...)
+	if err != nil {
+		return nil, false, err
+	}
+	if qr, err = dbClient.ExecuteFetch(query, -1); err != nil {
+		return nil, false, err
+	}
+	for _, row := range qr.Named().Rows {
+		if !row.AsBool("mismatch", false) {
+			continue
+		}
+		chunk := &tableChunk{}
+		if chunk.lowerPK, err = unmarshalTableLastPK(row.AsBytes("lower_pk", nil)); err != nil {
+			return nil, false, err
+		}
+		if chunk.upperPK, err = unmarshalTableLastPK(row.AsBytes("upper_pk", nil)); err != nil {
+			return nil, false, err
+		}
+		ranges = append(ranges, chunk)
+	}
+	return ranges, false, nil
+}
+
+// pastChunkEnd returns a function that reports whether a row is beyond the
+// upper bound of the chunk being diffed, if there is one.
+func (td *tableDiffer) pastChunkEnd() (func(row []sqltypes.Value) (bool, error), error) {
+	if td.chunk == nil || td.chunk.upperPK == nil {
+		return nil, nil
+	}
+	upper := sqltypes.Proto3ToResult(td.chunk.upperPK.Target)
+	if len(upper.Rows) != 1 || len(upper.Rows[0]) != len(td.tablePlan.pkCols) {
+		return nil, fmt.Errorf("invalid upper bound %v for chunk %d of table %s", td.chunk.upperPK, td.chunk.num, td.table.Name)
+	}
+	// Build a row holding the bound's values in the PK columns, so that
+	// it can be compared with the rows using the PK comparison.
+	bound := make([]sqltypes.Value, slices.Max(td.tablePlan.pkCols)+1)
+	for i, colIndex := range td.tablePlan.pkCols {
+		bound[colIndex] = upper.Rows[0][i]
+	}
+	return func(row []sqltypes.Value) (bool, error) {
+		c, err := td.compare(row, bound, td.tablePlan.comparePKs, false)
+		return c > 0, err
+	}, nil
+}
+
+// skipTable completes a table that does not need to be diffed again.
+func (td *tableDiffer) skipTable(ctx context.Context, dbClient binlogplayer.DBClient) error {
+	insertVDiffLog(ctx, dbClient, td.wd.ct.id, fmt.Sprintf("Table %s had no differences in vdiff %s, skipping it",
+		td.table.Name, td.wd.opts.CoreOptions.GetRediffUuid()))
+	return td.updateTableStateAndReport(ctx, dbClient, CompletedState, &DiffReport{TableName: td.table.Name})
+}
@@ -0,0 +1,325 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vdiff
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestBuildChunkUpperPKQuery(t *testing.T) {
+	testCases := []struct {
+		name   string
+		pkCols []string
+		lower  []sqltypes.Value
+		want   string
+	}{
+		{
+			name:   "first chunk",
+			pkCols: []string{"id"},
+			want:   "select `id` from `vt_ks`.`t1` order by `id` limit 999, 1",
+		},
+		{
+			name:   "next chunk",
+			pkCols: []string{"id"},
+			lower:  []sqltypes.Value{sqltypes.NewInt64(1000)},
+			want:   "select `id` from `vt_ks`.`t1` where (`id`) > (1000) order by `id` limit 999, 1",
+		},
+		{
+			name:   "multi-column pk",
+			pkCols: []string{"c1", "c2"},
+			lower:  []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarChar("it's")},
+			want:   "select `c1`, `c2` from `vt_ks`.`t1` where (`c1`, `c2`) > (1, 'it\\'s') order by `c1`, `c2` limit 999, 1",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, buildChunkUpperPKQuery("vt_ks", "t1", tc.pkCols, tc.lower, 1000))
+		})
+	}
+}
+
+func TestPastChunkEnd(t *testing.T) {
+	fields := []*querypb.Field{
+		{Name: "c1", Type: sqltypes.Int64},
+		{Name: "c2", Type: sqltypes.VarChar},
+		{Name: "val", Type: sqltypes.VarChar},
+	}
+	td := &tableDiffer{
+		wd:    &workflowDiffer{collationEnv: collations.MySQL8()},
+		table: &tabletmanagerdatapb.TableDefinition{Name: "t1"},
+		tablePlan: &tablePlan{
+			pkCols: []int{0, 1},
+			comparePKs: []compareColInfo{
+				{colIndex: 0, isPK: true, colName: "c1"},
+				{colIndex: 1, isPK: true, colName: "c2", collation: collations.MySQL8().LookupByName("utf8mb4_0900_ai_ci")},
+			},
+			table: &tabletmanagerdatapb.TableDefinition{Fields: fields},
+		},
+	}
+	pastEnd, err := td.pastChunkEnd()
+	require.NoError(t, err)
+	require.Nil(t, pastEnd, "a table that is not chunked has no end")
+
+	td.chunk = &tableChunk{
+		num: 1,
+		upperPK: &tabletmanagerdatapb.VDiffTableLastPK{
+			Target: &querypb.QueryResult{
+				Fields: fields[:2],
+				Rows:   []*querypb.Row{sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(2), sqltypes.NewVarChar("b")})},
+			},
+		},
+	}
+	pastEnd, err = td.pastChunkEnd()
+	require.NoError(t, err)
+	row := func(c1 int64, c2 string) []sqltypes.Value {
+		return []sqltypes.Value{sqltypes.NewInt64(c1), sqltypes.NewVarChar(c2), sqltypes.NewVarChar("val")}
+	}
+	rows := [][]sqltypes.Value{row(1, "z"), row(2, "a"), row(2, "B"), row(2, "c"), row(3, "a")}
+	var got []bool
+	for _, r := range rows {
+		past, err := pastEnd(r)
+		require.NoError(t, err)
+		got = append(got, past)
+	}
+	require.Equal(t, []bool{false, false, false, true, true}, got)
+
+	// The executors stop at the first row that is past the end of the chunk.
+	pe := &primitiveExecutor{resultch: make(chan *sqltypes.Result, 1), pastEnd: pastEnd}
+	pe.resultch <- &sqltypes.Result{Rows: rows}
+	close(pe.resultch)
+	count, err := pe.drain(context.Background())
+	require.NoError(t, err)
+	require.EqualValues(t, 3, count)
+	next, err := pe.next()
+	require.NoError(t, err)
+	require.Nil(t, next)
+}
+
+func TestUpdateChunkProgress(t *testing.T) {
+	wd := &workflowDiffer{
+		ct: &controller{
+			id:                 1,
+			TableDiffRowCounts: stats.NewCountersWithSingleLabel("", "", "Rows"),
+		},
+		opts: &tabletmanagerdatapb.VDiffOptions{
+			CoreOptions: &tabletmanagerdatapb.VDiffCoreOptions{
+				ChunkRows: 100,
+				MaxRows:   1000,
+			},
+		},
+	}
+	td := &tableDiffer{
+		wd:    wd,
+		table: &tabletmanagerdatapb.TableDefinition{Name: "t1"},
+		tablePlan: &tablePlan{
+			pkCols:       []int{0},
+			sourcePkCols: []int{0},
+			table: &tabletmanagerdatapb.TableDefinition{
+				Fields: []*querypb.Field{{Name: "id", Type: sqltypes.Int64}},
+			},
+		},
+	}
+	// The table's report includes the 100 rows of the first chunk, one of
+	// which did not match, and the 10 rows of the second chunk that were
+	// compared before the vdiff was resumed.
+	ctd := newChunkedTableDiff(&DiffReport{TableName: "t1", ProcessedRows: 110, MatchingRows: 109, MismatchedRows: 1}, wd.opts)
+	require.EqualValues(t, 890, ctd.rowsLeft.Load())
+	cd := td.newChunkDiffer(&tableChunk{num: 2, state: PendingState, rowsCompared: 10}, ctd)
+	require.Equal(t, StartedState, cd.chunk.state)
+	dr := cd.chunk.report
+
+	dbc := binlogplayer.NewMockDBClient(t)
+	dbc.ExpectRequest(`update _vt.vdiff_table set rows_compared = 150, report = '{"TableName":"t1","ProcessedRows":150,"MatchingRows":149,"MismatchedRows":1,"ExtraRowsSource":0,"ExtraRowsTarget":0}' where vdiff_id = 1 and table_name = 't1'`, &sqltypes.Result{}, nil)
+	dbc.ExpectRequest(`update _vt.vdiff_table_chunk set state = 'started', rows_compared = 50, mismatch = 0, lastpk = 'target:{fields:{name:"id" type:INT64} rows:{lengths:3 values:"150"}}' where vdiff_id = 1 and table_name = 't1' and chunk_num = 2`, &sqltypes.Result{}, nil)
+	dr.ProcessedRows, dr.MatchingRows = 40, 40
+	require.NoError(t, cd.updateTableProgress(dbc, dr, []sqltypes.Value{sqltypes.NewInt64(150)}))
+
+	dbc.ExpectRequestRE("update _vt.vdiff_table set rows_compared = 160, report = .*", &sqltypes.Result{}, nil)
+	dbc.ExpectRequest(`update _vt.vdiff_table_chunk set state = 'started', rows_compared = 60, mismatch = 1, lastpk = 'target:{fields:{name:"id" type:INT64} rows:{lengths:3 values:"160"}}' where vdiff_id = 1 and table_name = 't1' and chunk_num = 2`, &sqltypes.Result{}, nil)
+	dr.ProcessedRows, dr.ExtraRowsTarget = 50, 1
+	require.NoError(t, cd.updateTableProgress(dbc, dr, []sqltypes.Value{sqltypes.NewInt64(160)}))
+	dbc.Wait()
+}
+
+func TestChunkedTableDiffReport(t *testing.T) {
+	opts := &tabletmanagerdatapb.VDiffOptions{
+		CoreOptions:   &tabletmanagerdatapb.VDiffCoreOptions{MaxExtraRowsToCompare: 2},
+		ReportOptions: &tabletmanagerdatapb.VDiffReportOptions{MaxSampleRows: 1},
+	}
+	sample := func(id string) *RowDiff {
+		return &RowDiff{Row: map[string]string{"id": id}}
+	}
+	ctd := newChunkedTableDiff(&DiffReport{
+		TableName:            "t1",
+		ProcessedRows:        10,
+		MatchingRows:         9,
+		ExtraRowsSource:      1,
+		ExtraRowsSourceDiffs: []*RowDiff{sample("1")},
+	}, opts)
+	ctd.reports[3] = &DiffReport{
+		TableName:            "t1",
+		ProcessedRows:        5,
+		MatchingRows:         3,
+		MismatchedRows:       1,
+		ExtraRowsSource:      1,
+		ExtraRowsSourceDiffs: []*RowDiff{sample("23")},
+		MismatchedRowsDiffs:  []*DiffMismatch{{Source: sample("22"), Target: sample("22")}},
+	}
+	ctd.reports[2] = &DiffReport{
+		TableName:            "t1",
+		ProcessedRows:        5,
+		MatchingRows:         3,
+		MismatchedRows:       1,
+		ExtraRowsSource:      1,
+		ExtraRowsSourceDiffs: []*RowDiff{sample("13")},
+		MismatchedRowsDiffs:  []*DiffMismatch{{Source: sample("12"), Target: sample("12")}},
+	}
+	require.Equal(t, &DiffReport{
+		TableName:            "t1",
+		ProcessedRows:        20,
+		MatchingRows:         15,
+		MismatchedRows:       2,
+		ExtraRowsSource:      3,
+		ExtraRowsSourceDiffs: []*RowDiff{sample("1"), sample("13")},
+		MismatchedRowsDiffs:  []*DiffMismatch{{Source: sample("12"), Target: sample("12")}},
+	}, ctd.report())
+}
+
+// TestForEachChunk tests that the chunks of a table are diffed concurrently,
+// with no more than chunk-concurrency of them at the same time.
+func TestForEachChunk(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	const (
+		numChunks   = 6
+		concurrency = 3
+	)
+	wd := &workflowDiffer{
+		ct: &controller{
+			id:   1,
+			uuid: "uuid",
+			vde:  &Engine{thisTablet: &topodatapb.Tablet{Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}}},
+		},
+		opts: &tabletmanagerdatapb.VDiffOptions{
+			CoreOptions: &tabletmanagerdatapb.VDiffCoreOptions{
+				RediffUuid:       "rediff-uuid",
+				ChunkConcurrency: concurrency,
+				MaxRows:          1000,
+			},
+		},
+	}
+	td := &tableDiffer{wd: wd, table: &tabletmanagerdatapb.TableDefinition{Name: "t1"}}
+	var chunks []*tableChunk
+	for i := range numChunks {
+		state := PendingState
+		if i == 0 {
+			state = CompletedState
+		}
+		chunks = append(chunks, &tableChunk{num: int64(i + 1), state: state})
+	}
+	reportQR := sqltypes.MakeTestResult(sqltypes.MakeTestFields("lastpk|mismatch|report", "varbinary|int64|json"),
+		`|0|{"TableName":"t1","ProcessedRows":10,"MatchingRows":10}`)
+
+	testCases := []struct {
+		name    string
+		failing int64
+		wantErr string
+	}{
+		{
+			name: "all chunks",
+		},
+		{
+			name:    "failing chunk",
+			failing: 4,
+			wantErr: "chunk 4 failed",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbc := binlogplayer.NewMockDBClient(t)
+			dbc.ExpectRequestRE("select vdt.lastpk as lastpk.*", reportQR, nil)
+
+			var (
+				mu             sync.Mutex
+				running        int
+				maxRunning     int
+				diffed         []int64
+				allRunning     = make(chan struct{})
+				allRunningOnce sync.Once
+			)
+			diffChunk := func(ctx context.Context, cd *tableDiffer) error {
+				mu.Lock()
+				running++
+				maxRunning = max(maxRunning, running)
+				diffed = append(diffed, cd.chunk.num)
+				if running == concurrency {
+					allRunningOnce.Do(func() { close(allRunning) })
+				}
+				mu.Unlock()
+				defer func() {
+					mu.Lock()
+					running--
+					mu.Unlock()
+				}()
+
+				// Wait until as many chunks as allowed are being diffed.
+				select {
+				case <-allRunning:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+				if cd.chunk.num == tc.failing {
+					return fmt.Errorf("chunk %d failed", cd.chunk.num)
+				}
+				cd.chunk.report.ProcessedRows = 10
+				cd.chunk.report.MatchingRows = 10
+				cd.chunks.mu.Lock()
+				cd.chunks.reports[cd.chunk.num] = cd.chunk.report
+				cd.chunks.mu.Unlock()
+				return nil
+			}
+
+			dr, err := wd.forEachChunk(ctx, dbc, td, slices.Clone(chunks), diffChunk)
+			dbc.Wait()
+			require.Equal(t, concurrency, maxRunning)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.ElementsMatch(t, []int64{2, 3, 4, 5, 6}, diffed)
+			require.EqualValues(t, 60, dr.ProcessedRows)
+			require.EqualValues(t, 60, dr.MatchingRows)
+		})
+	}
+}
@@ -93,6 +93,17 @@ type tableDiffer struct {
 	table        *tabletmanagerdatapb.TableDefinition
 	lastSourcePK *querypb.QueryResult
 	lastTargetPK *querypb.QueryResult
+	// chunk is the PK range being diffed, when the table is diffed in chunks,
+	// and chunks is the state shared with the differs of the table's other
+	// chunks.
+	chunk  *tableChunk
+	chunks *chunkedTableDiff
+
+	// sources and targetShardStreamer are the shard streamers used to diff the
+	// table. The sources are the controller's, except for the differs of the
+	// table's chunks, which each have their own.
+	sources             map[string]*migrationSource
+	targetShardStreamer *shardStreamer
 
 	// wgShardStreamers is used, with a cancellable context, to wait for all shard streamers
 	// to finish after each diff is complete.
@@ -102,7 +113,7 @@ type tableDiffer struct {
 }
 
 func newTableDiffer(wd *workflowDiffer, table *tabletmanagerdatapb.TableDefinition, sourceQuery string) *tableDiffer {
-	return &tableDiffer{wd: wd, table: table, sourceQuery: sourceQuery}
+	return &tableDiffer{wd: wd, table: table, sourceQuery: sourceQuery, sources: wd.ct.sources}
 }
 
 // initialize
@@ -205,17 +216,16 @@ func (td *tableDiffer) stopTargetVReplicationStreams(ctx context.Context, dbClie
 		if err := prototext.Unmarshal(sourceBytes, &bls); err != nil {
 			return err
 		}
-		ct.sources[bls.Shard].position = mpos
+		td.sources[bls.Shard].position = mpos
 	}
 
 	return nil
 }
 
 func (td *tableDiffer) forEachSource(cb func(source *migrationSource) error) error {
-	ct := td.wd.ct
 	var wg sync.WaitGroup
 	allErrors := &concurrency.AllErrorRecorder{}
-	for _, source := range ct.sources {
+	for _, source := range td.sources {
 		wg.Add(1)
 		go func(source *migrationSource) {
 			defer wg.Done()
@@ -276,7 +286,7 @@ func (td *tableDiffer) selectTablets(ctx context.Context) error {
 		if targetErr != nil {
 			return
 		}
-		td.wd.ct.targetShardStreamer = &shardStreamer{
+		td.targetShardStreamer = &shardStreamer{
 			tablet: targetTablet,
 			shard:  targetTablet.Shard,
 		}
@@ -344,16 +354,15 @@ func (td *tableDiffer) syncTargetStreams(ctx context.Context) error {
 
 func (td *tableDiffer) startTargetDataStream(ctx context.Context) error {
 	defer td.wd.ct.TableDiffPhaseTimings.Record(fmt.Sprintf("%s.%s", td.table.Name, startingTargets), time.Now())
-	ct := td.wd.ct
 	gtidch := make(chan string, 1)
-	ct.targetShardStreamer.result = make(chan *sqltypes.Result, 1)
-	go td.streamOneShard(ctx, ct.targetShardStreamer, td.tablePlan.targetQuery, td.lastTargetPK, gtidch)
+	td.targetShardStreamer.result = make(chan *sqltypes.Result, 1)
+	go td.streamOneShard(ctx, td.targetShardStreamer, td.tablePlan.targetQuery, td.lastTargetPK, gtidch)
 	gtid, ok := <-gtidch
 	if !ok {
-		log.Infof("streaming error: %v", ct.targetShardStreamer.err)
-		return ct.targetShardStreamer.err
+		log.Infof("streaming error: %v", td.targetShardStreamer.err)
+		return td.targetShardStreamer.err
 	}
-	ct.targetShardStreamer.snapshotPosition = gtid
+	td.targetShardStreamer.snapshotPosition = gtid
 	return nil
 }
 
@@ -469,14 +478,14 @@ func (td *tableDiffer) streamOneShard(ctx context.Context, participant *shardStr
 func (td *tableDiffer) setupRowSorters() {
 	// Combine all sources into a slice and create a merge sorter for it.
 	sources := make(map[string]*shardStreamer)
-	for shard, source := range td.wd.ct.sources {
+	for shard, source := range td.sources {
 		sources[shard] = source.shardStreamer
 	}
 	td.sourcePrimitive = newMergeSorter(sources, td.tablePlan.comparePKs, td.wd.collationEnv)
 
 	// Create a merge sorter for the target.
 	targets := make(map[string]*shardStreamer)
-	targets[td.wd.ct.targetShardStreamer.shard] = td.wd.ct.targetShardStreamer
+	targets[td.targetShardStreamer.shard] = td.targetShardStreamer
 	td.targetPrimitive = newMergeSorter(targets, td.tablePlan.comparePKs, td.wd.collationEnv)
 
 	// If there were aggregate expressions, we have to re-aggregate
@@ -498,39 +507,37 @@ func (td *tableDiffer) diff(ctx context.Context, coreOpts *tabletmanagerdatapb.V
 	}
 	defer dbClient.Close()
 
-	// We need to continue were we left off when appropriate. This can be an
-	// auto-retry on error, or a manual retry via the resume command.
-	// Otherwise the existing state will be empty and we start from scratch.
-	query, err := sqlparser.ParseAndBind(sqlGetVDiffTable,
-		sqltypes.Int64BindVariable(td.wd.ct.id),
-		sqltypes.StringBindVariable(td.table.Name),
+	var (
+		dr       *DiffReport
+		mismatch bool
+		err      error
 	)
-	if err != nil {
-		return nil, err
+	if td.chunk == nil {
+		// We need to continue were we left off when appropriate. This can be an
+		// auto-retry on error, or a manual retry via the resume command.
+		// Otherwise the existing state will be empty and we start from scratch.
+		if dr, mismatch, err = td.getDiffReport(dbClient); err != nil {
+			return nil, err
+		}
+	} else {
+		// The chunk's report is merged into the table's one as we go.
+		dr = td.chunk.report
 	}
-	cs, err := dbClient.ExecuteFetch(query, -1)
+
+	rowsToCompare := coreOpts.GetMaxRows()
+	pastEnd, err := td.pastChunkEnd()
 	if err != nil {
 		return nil, err
 	}
-	if len(cs.Rows) == 0 {
-		return nil, fmt.Errorf("no state found for vdiff table %s for vdiff_id %d on tablet %v",
-			td.table.Name, td.wd.ct.id, td.wd.ct.vde.thisTablet.Alias)
-	} else if len(cs.Rows) > 1 {
-		return nil, fmt.Errorf("invalid state found for vdiff table %s (multiple records) for vdiff_id %d on tablet %v",
-			td.table.Name, td.wd.ct.id, td.wd.ct.vde.thisTablet.Alias)
-	}
-	curState := cs.Named().Row()
-	mismatch := curState.AsBool("mismatch", false)
-	dr := &DiffReport{}
-	if rpt := curState.AsBytes("report", []byte("{}")); json.Valid(rpt) {
-		if err = json.Unmarshal(rpt, dr); err != nil {
-			return nil, err
-		}
-	}
-	dr.TableName = td.table.Name
 
+	// The executors stop reading from the shard streams when a chunk's upper
+	// bound is reached, so let them know when we're done.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 	sourceExecutor := newPrimitiveExecutor(ctx, td.sourcePrimitive, "source")
+	sourceExecutor.pastEnd = pastEnd
 	targetExecutor := newPrimitiveExecutor(ctx, td.targetPrimitive, "target")
+	targetExecutor.pastEnd = pastEnd
 	var sourceRow, lastProcessedRow, targetRow []sqltypes.Value
 	advanceSource := true
 	advanceTarget := true
@@ -543,7 +550,6 @@ func (td *tableDiffer) diff(ctx context.Context, coreOpts *tabletmanagerdatapb.V
 		globalStats.RowsDiffedCount.Add(dr.ProcessedRows)
 	}()
 
-	rowsToCompare := coreOpts.GetMaxRows()
 	maxExtraRowsToCompare := coreOpts.GetMaxExtraRowsToCompare()
 	maxReportSampleRows := reportOpts.GetMaxSampleRows()
 
@@ -570,8 +576,15 @@ func (td *tableDiffer) diff(ctx context.Context, coreOpts *tabletmanagerdatapb.V
 		}
 
 		rowsToCompare--
+		if td.chunks != nil {
+			// The row limit applies to the table as a whole.
+			rowsToCompare = td.chunks.rowsLeft.Add(-1)
+		}
 		if rowsToCompare < 0 {
 			log.Infof("Stopping vdiff, specified row limit reached")
+			if td.chunk != nil {
+				td.chunk.limitReached = true
+			}
 			return dr, nil
 		}
 		if advanceSource {
@@ -694,6 +707,39 @@ func (td *tableDiffer) diff(ctx context.Context, coreOpts *tabletmanagerdatapb.V
 	}
 }
 
+// getDiffReport returns the table's current diff report, and whether a
+// mismatch has already been flagged for it.
+func (td *tableDiffer) getDiffReport(dbClient binlogplayer.DBClient) (*DiffReport, bool, error) {
+	query, err := sqlparser.ParseAndBind(sqlGetVDiffTable,
+		sqltypes.Int64BindVariable(td.wd.ct.id),
+		sqltypes.StringBindVariable(td.table.Name),
+	)
+	if err != nil {
+		return nil, false, err
+	}
+	cs, err := dbClient.ExecuteFetch(query, -1)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(cs.Rows) == 0 {
+		return nil, false, fmt.Errorf("no state found for vdiff table %s for vdiff_id %d on tablet %v",
+			td.table.Name, td.wd.ct.id, td.wd.ct.vde.thisTablet.Alias)
+	} else if len(cs.Rows) > 1 {
+		return nil, false, fmt.Errorf("invalid state found for vdiff table %s (multiple records) for vdiff_id %d on tablet %v",
+			td.table.Name, td.wd.ct.id, td.wd.ct.vde.thisTablet.Alias)
+	}
+	curState := cs.Named().Row()
+	mismatch := curState.AsBool("mismatch", false)
+	dr := &DiffReport{}
+	if rpt := curState.AsBytes("report", []byte("{}")); json.Valid(rpt) {
+		if err = json.Unmarshal(rpt, dr); err != nil {
+			return nil, false, err
+		}
+	}
+	dr.TableName = td.table.Name
+	return dr, mismatch, nil
+}
+
 func (td *tableDiffer) compare(sourceRow, targetRow []sqltypes.Value, cols []compareColInfo, compareOnlyNonPKs bool) (int, error) {
 	for _, col := range cols {
 		if col.isPK && compareOnlyNonPKs {
@@ -726,6 +772,10 @@ func (td *tableDiffer) updateTableProgress(dbClient binlogplayer.DBClient, dr *D
 		return fmt.Errorf("cannot update progress with a nil diff report")
 	}
 
+	if td.chunk != nil {
+		return td.updateChunkedTableProgress(dbClient, dr, lastRow)
+	}
+
 	var err error
 	var query string
 	rpt, err := json.Marshal(dr)
@@ -745,17 +795,7 @@ func (td *tableDiffer) updateTableProgress(dbClient binlogplayer.DBClient, dr *D
 		}
 	} else {
 		lastPK := td.lastPKFromRow(lastRow)
-		if td.wd.opts.CoreOptions.MaxDiffSeconds > 0 {
-			// Update the in-memory lastPK as well so that we can restart the table
-			// diff if needed.
-			td.lastTargetPK = lastPK.Target
-			if lastPK.Source == nil {
-				// If the source PK is nil, we use the target value for both.
-				td.lastSourcePK = lastPK.Target
-			} else {
-				td.lastSourcePK = lastPK.Source
-			}
-		}
+		td.setLastPK(lastPK)
 		lastPKTxt, err := prototext.Marshal(lastPK)
 		if err != nil {
 			return vterrors.Wrapf(err, "failed to marshal lastpk value %+v for table %s", lastPK, td.table.Name)
@@ -779,6 +819,21 @@ func (td *tableDiffer) updateTableProgress(dbClient binlogplayer.DBClient, dr *D
 	return nil
 }
 
+// setLastPK updates the in-memory lastPK when the diff can be restarted with
+// new snapshots, so that it's restarted from where it left off.
+func (td *tableDiffer) setLastPK(lastPK *tabletmanagerdatapb.VDiffTableLastPK) {
+	if td.wd.opts.CoreOptions.MaxDiffSeconds <= 0 {
+		return
+	}
+	td.lastTargetPK = lastPK.Target
+	if lastPK.Source == nil {
+		// If the source PK is nil, we use the target value for both.
+		td.lastSourcePK = lastPK.Target
+	} else {
+		td.lastSourcePK = lastPK.Source
+	}
+}
+
 func (td *tableDiffer) updateTableState(ctx context.Context, dbClient binlogplayer.DBClient, state VDiffState) error {
 	query, err := sqlparser.ParseAndBind(sqlUpdateTableState,
 		sqltypes.StringBindVariable(string(state)),
@@ -25,6 +25,7 @@ import (
 	"strings"
 	"time"
 
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/protobuf/encoding/prototext"
 
 	"vitess.io/vitess/go/mysql/collations"
@@ -194,6 +195,143 @@ func (wd *workflowDiffer) doReconcileExtraRows(dr *DiffReport, maxExtraRowsToCom
 }
 
 func (wd *workflowDiffer) diffTable(ctx context.Context, dbClient binlogplayer.DBClient, td *tableDiffer) error {
+	log.Infof("Starting differ on table %s for vdiff %s", td.table.Name, wd.ct.uuid)
+	if err := td.updateTableState(ctx, dbClient, StartedState); err != nil {
+		return err
+	}
+
+	var chunks []*tableChunk
+	if wd.chunked() {
+		var err error
+		if chunks, err = td.getChunks(dbClient); err != nil {
+			return err
+		}
+		if len(chunks) == 0 && td.lastTargetPK == nil { // We have not started diffing the table yet
+			var skip bool
+			if chunks, skip, err = td.planChunks(dbClient); err != nil {
+				return err
+			}
+			if skip {
+				return td.skipTable(ctx, dbClient)
+			}
+		}
+	}
+
+	var (
+		diffReport *DiffReport
+		diffErr    error
+	)
+	if len(chunks) == 0 {
+		diffReport, diffErr = wd.diffTableRange(ctx, td)
+	} else {
+		diffReport, diffErr = wd.diffTableChunks(ctx, dbClient, td, chunks)
+	}
+	if diffErr != nil {
+		return diffErr
+	}
+	log.Infof("Table diff done on table %s for vdiff %s with report: %+v", td.table.Name, wd.ct.uuid, diffReport)
+
+	if diffReport.ExtraRowsSource > 0 || diffReport.ExtraRowsTarget > 0 {
+		if err := wd.reconcileExtraRows(diffReport, wd.opts.CoreOptions.MaxExtraRowsToCompare, wd.opts.ReportOptions.MaxSampleRows); err != nil {
+			log.Errorf("Encountered an error reconciling extra rows found for table %s for vdiff %s: %v", td.table.Name, wd.ct.uuid, err)
+			return vterrors.Wrap(err, "failed to reconcile extra rows")
+		}
+	}
+
+	if diffReport.MismatchedRows > 0 || diffReport.ExtraRowsTarget > 0 || diffReport.ExtraRowsSource > 0 {
+		if err := updateTableMismatch(dbClient, wd.ct.id, td.table.Name); err != nil {
+			return err
+		}
+	}
+
+	log.Infof("Completed reconciliation on table %s for vdiff %s with updated report: %+v", td.table.Name, wd.ct.uuid, diffReport)
+	if err := td.updateTableStateAndReport(ctx, dbClient, CompletedState, diffReport); err != nil {
+		return err
+	}
+	return nil
+}
+
+// diffTableChunks diffs the table's chunks that have not been completed yet,
+// adding chunks as needed when the chunk-rows option is used.
+func (wd *workflowDiffer) diffTableChunks(ctx context.Context, dbClient binlogplayer.DBClient, td *tableDiffer, chunks []*tableChunk) (*DiffReport, error) {
+	return wd.forEachChunk(ctx, dbClient, td, chunks, wd.diffChunk)
+}
+
+// forEachChunk calls diffChunk with a differ for each of the table's chunks
+// that have not been completed yet. Up to chunkConcurrency chunks are diffed
+// at the same time. It returns the table's diff report, merged from the
+// reports of all the chunks.
+func (wd *workflowDiffer) forEachChunk(ctx context.Context, dbClient binlogplayer.DBClient, td *tableDiffer, chunks []*tableChunk,
+	diffChunk func(ctx context.Context, cd *tableDiffer) error) (*DiffReport, error) {
+	dr, _, err := td.getDiffReport(dbClient)
+	if err != nil {
+		return nil, err
+	}
+	ctd := newChunkedTableDiff(dr, wd.opts)
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(wd.chunkConcurrency())
+	var nextChunkErr error
+	for i := 0; gctx.Err() == nil; i++ {
+		if i == len(chunks) {
+			chunk, err := td.nextChunk(dbClient, chunks[i-1])
+			if err != nil {
+				nextChunkErr = err
+				break
+			}
+			if chunk == nil { // We're done
+				break
+			}
+			chunks = append(chunks, chunk)
+		}
+		chunk := chunks[i]
+		if chunk.state == CompletedState {
+			continue
+		}
+		if ctd.rowsLeft.Load() <= 0 {
+			log.Infof("Stopping vdiff, specified row limit reached")
+			break
+		}
+		cd := td.newChunkDiffer(chunk, ctd)
+		// This blocks while chunkConcurrency chunks are being diffed.
+		g.Go(func() error {
+			log.Infof("Starting diff of chunk %d on table %s for vdiff %s", chunk.num, td.table.Name, wd.ct.uuid)
+			return diffChunk(gctx, cd)
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	if nextChunkErr != nil {
+		return nil, nextChunkErr
+	}
+	if ctx.Err() != nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_CANCELED, "context has expired")
+	}
+	return ctd.report(), nil
+}
+
+// diffChunk diffs one of the table's chunks, and then marks it as completed.
+func (wd *workflowDiffer) diffChunk(ctx context.Context, cd *tableDiffer) error {
+	if _, err := wd.diffTableRange(ctx, cd); err != nil {
+		return err
+	}
+	if cd.chunk.limitReached {
+		// The rest of the chunk was not diffed.
+		return nil
+	}
+	dbClient := wd.ct.dbClientFactory()
+	if err := dbClient.Connect(); err != nil {
+		return err
+	}
+	defer dbClient.Close()
+	cd.chunk.state = CompletedState
+	return cd.updateChunkProgress(dbClient)
+}
+
+// diffTableRange diffs the table, or the table's current chunk, restarting the
+// diff with new snapshots when it takes longer than the max-diff-duration.
+func (wd *workflowDiffer) diffTableRange(ctx context.Context, td *tableDiffer) (*DiffReport, error) {
 	cancelShardStreams := func() {
 		if td.shardStreamsCancel != nil {
 			td.shardStreamsCancel()
@@ -227,17 +365,12 @@ func (wd *workflowDiffer) diffTable(ctx context.Context, dbClient binlogplayer.D
 		maxDiffRuntime = time.Duration(wd.ct.options.CoreOptions.MaxDiffSeconds) * time.Second
 	}
 
-	log.Infof("Starting differ on table %s for vdiff %s", td.table.Name, wd.ct.uuid)
-	if err := td.updateTableState(ctx, dbClient, StartedState); err != nil {
-		return err
-	}
-
 	for {
 		select {
 		case <-ctx.Done():
-			return vterrors.Errorf(vtrpcpb.Code_CANCELED, "context has expired")
+			return nil, vterrors.Errorf(vtrpcpb.Code_CANCELED, "context has expired")
 		case <-wd.ct.done:
-			return ErrVDiffStoppedByUser
+			return nil, ErrVDiffStoppedByUser
 		default:
 		}
 
@@ -255,39 +388,19 @@ func (wd *workflowDiffer) diffTable(ctx context.Context, dbClient binlogplayer.D
 			time.Sleep(30 * time.Second)
 		}
 		if err := td.initialize(ctx); err != nil { // Setup the consistent snapshots
-			return err
+			return nil, err
 		}
 		log.Infof("Table initialization done on table %s for vdiff %s", td.table.Name, wd.ct.uuid)
 		diffTimer = time.NewTimer(maxDiffRuntime)
 		diffReport, diffErr = td.diff(ctx, wd.opts.CoreOptions, wd.opts.ReportOptions, diffTimer.C)
 		if diffErr == nil { // We finished the diff successfully
-			break
+			return diffReport, nil
 		}
 		log.Errorf("Encountered an error diffing table %s for vdiff %s: %v", td.table.Name, wd.ct.uuid, diffErr)
 		if !errors.Is(diffErr, ErrMaxDiffDurationExceeded) { // We only want to retry if we hit the max-diff-duration
-			return diffErr
-		}
-	}
-	log.Infof("Table diff done on table %s for vdiff %s with report: %+v", td.table.Name, wd.ct.uuid, diffReport)
-
-	if diffReport.ExtraRowsSource > 0 || diffReport.ExtraRowsTarget > 0 {
-		if err := wd.reconcileExtraRows(diffReport, wd.opts.CoreOptions.MaxExtraRowsToCompare, wd.opts.ReportOptions.MaxSampleRows); err != nil {
-			log.Errorf("Encountered an error reconciling extra rows found for table %s for vdiff %s: %v", td.table.Name, wd.ct.uuid, err)
-			return vterrors.Wrap(err, "failed to reconcile extra rows")
-		}
-	}
-
-	if diffReport.MismatchedRows > 0 || diffReport.ExtraRowsTarget > 0 || diffReport.ExtraRowsSource > 0 {
-		if err := updateTableMismatch(dbClient, wd.ct.id, td.table.Name); err != nil {
-			return err
+			return nil, diffErr
 		}
 	}
-
-	log.Infof("Completed reconciliation on table %s for vdiff %s with updated report: %+v", td.table.Name, wd.ct.uuid, diffReport)
-	if err := td.updateTableStateAndReport(ctx, dbClient, CompletedState, diffReport); err != nil {
-		return err
-	}
-	return nil
 }
 
 func (wd *workflowDiffer) diff(ctx context.Context) (err error) {
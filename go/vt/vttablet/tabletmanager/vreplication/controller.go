@@ -285,7 +285,7 @@ func (ct *controller) runBlp(ctx context.Context) (err error) {
 		var vsClient VStreamerClient
 		var err error
 		if name := ct.source.GetExternalMysql(); name != "" {
-			vsClient, err = ct.vre.ec.Get(name)
+			vsClient, err = ct.vre.ec.Get(ctx, name)
 			if err != nil {
 				return err
 			}
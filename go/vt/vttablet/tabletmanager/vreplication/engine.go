@@ -139,7 +139,7 @@ func NewEngine(env *vtenv.Environment, config *tabletenv.TabletConfig, ts *topo.
 		cell:            cell,
 		mysqld:          mysqld,
 		journaler:       make(map[string]*journalEvent),
-		ec:              newExternalConnector(env, config.ExternalConnections),
+		ec:              newExternalConnector(env, config.ExternalConnections, ts),
 		throttlerClient: throttle.NewBackgroundClient(lagThrottler, throttlerapp.VReplicationName, base.UndefinedScope),
 	}
 
@@ -174,7 +174,7 @@ func NewTestEngine(ts *topo.Server, cell string, mysqld mysqlctl.MysqlDaemon, db
 		dbClientFactoryDba:      dbClientFactoryDba,
 		dbName:                  dbname,
 		journaler:               make(map[string]*journalEvent),
-		ec:                      newExternalConnector(env, externalConfig),
+		ec:                      newExternalConnector(env, externalConfig, ts),
 	}
 	return vre
 }
@@ -193,7 +193,7 @@ func NewSimpleTestEngine(ts *topo.Server, cell string, mysqld mysqlctl.MysqlDaem
 		dbClientFactoryDba:      dbClientFactoryDba,
 		dbName:                  dbname,
 		journaler:               make(map[string]*journalEvent),
-		ec:                      newExternalConnector(env, externalConfig),
+		ec:                      newExternalConnector(env, externalConfig, ts),
 		shortcircuit:            true,
 	}
 	return vre
@@ -348,6 +348,12 @@ func (vre *Engine) ExecWithDBA(query string) (*sqltypes.Result, error) {
 	return vre.exec(query, true /*runAsAdmin*/)
 }
 
+// ExecuteFetchOnExternalMysql executes a read-only query on the named
+// external MySQL.
+func (vre *Engine) ExecuteFetchOnExternalMysql(ctx context.Context, name, query string, maxRows int) (*sqltypes.Result, error) {
+	return vre.ec.ExecuteFetch(ctx, name, query, maxRows)
+}
+
 // Exec runs the specified query as the Filtered user.
 func (vre *Engine) Exec(query string) (*sqltypes.Result, error) {
 	return vre.exec(query, false /*runAsAdmin*/)
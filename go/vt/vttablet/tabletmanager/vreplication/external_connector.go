@@ -23,10 +23,8 @@ import (
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/dbconfigs"
 	"vitess.io/vitess/go/vt/grpcclient"
-	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
-	querypb "vitess.io/vitess/go/vt/proto/query"
-	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
-	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vttablet/queryservice"
@@ -35,6 +33,11 @@ import (
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/vstreamer"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
 var (
@@ -65,13 +68,17 @@ type externalConnector struct {
 	mu         sync.Mutex
 	dbconfigs  map[string]*dbconfigs.DBConfigs
 	connectors map[string]*mysqlConnector
+	// ts is used to look up the external mysqls that are not in the tablet
+	// config, but were registered in the topo by an Import workflow.
+	ts *topo.Server
 }
 
-func newExternalConnector(env *vtenv.Environment, dbcfgs map[string]*dbconfigs.DBConfigs) *externalConnector {
+func newExternalConnector(env *vtenv.Environment, dbcfgs map[string]*dbconfigs.DBConfigs, ts *topo.Server) *externalConnector {
 	return &externalConnector{
 		env:        env,
 		dbconfigs:  dbcfgs,
 		connectors: make(map[string]*mysqlConnector),
+		ts:         ts,
 	}
 }
 
@@ -82,7 +89,7 @@ func (ec *externalConnector) Close() {
 	ec.connectors = make(map[string]*mysqlConnector)
 }
 
-func (ec *externalConnector) Get(name string) (*mysqlConnector, error) {
+func (ec *externalConnector) Get(ctx context.Context, name string) (*mysqlConnector, error) {
 	ec.mu.Lock()
 	defer ec.mu.Unlock()
 	if c, ok := ec.connectors[name]; ok {
@@ -90,10 +97,10 @@ func (ec *externalConnector) Get(name string) (*mysqlConnector, error) {
 	}
 
 	// Construct
+	var err error
 	config := tabletenv.NewDefaultConfig()
-	config.DB = ec.dbconfigs[name]
-	if config.DB == nil {
-		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "external mysqlConnector %v not found", name)
+	if config.DB, err = ec.getDBConfigs(ctx, name); err != nil {
+		return nil, err
 	}
 	c := &mysqlConnector{}
 	c.env = tabletenv.NewEnv(ec.env, config, name)
@@ -113,6 +120,69 @@ func (ec *externalConnector) Get(name string) (*mysqlConnector, error) {
 	return c, nil
 }
 
+// ExecuteFetch executes a read-only query on the named external MySQL,
+// using a new connection.
+func (ec *externalConnector) ExecuteFetch(ctx context.Context, name, query string, maxRows int) (*sqltypes.Result, error) {
+	switch sqlparser.Preview(query) {
+	case sqlparser.StmtSelect, sqlparser.StmtShow:
+	default:
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "only read-only queries can be executed on external mysql %v: %s", name, query)
+	}
+	ec.mu.Lock()
+	dbcfgs, err := ec.getDBConfigs(ctx, name)
+	ec.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	connector := dbcfgs.AllPrivsWithDB()
+	conn, err := connector.Connect(ctx)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to connect to external mysql %v", name)
+	}
+	defer conn.Close()
+	return conn.ExecuteFetch(query, maxRows, true)
+}
+
+// getDBConfigs returns the connection parameters of the named external
+// mysql, from the externalConnections of the tablet config or else from the
+// topo. It must be called with the lock held.
+func (ec *externalConnector) getDBConfigs(ctx context.Context, name string) (*dbconfigs.DBConfigs, error) {
+	if dbcfgs := ec.dbconfigs[name]; dbcfgs != nil {
+		return dbcfgs, nil
+	}
+	if ec.ts != nil {
+		em, err := ec.ts.GetExternalMysql(ctx, name)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "failed to read external mysql %v from the topo", name)
+		}
+		if em != nil {
+			return newExternalMysqlDBConfigs(ec.env, em), nil
+		}
+	}
+	return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "external mysql %v not found in the externalConnections of the tablet config or in the topo", name)
+}
+
+// newExternalMysqlDBConfigs returns the connection parameters of an external
+// mysql that was registered in the topo. All of the users connect with the
+// registered user, whose password the db credentials server provides when
+// none was registered.
+func newExternalMysqlDBConfigs(env *vtenv.Environment, em *topodatapb.ExternalMysql) *dbconfigs.DBConfigs {
+	uc := dbconfigs.UserConfig{User: em.User, Password: em.Password, UseTCP: true}
+	dbcfgs := &dbconfigs.DBConfigs{
+		Host:     em.Host,
+		Port:     int(em.Port),
+		DBName:   em.DbName,
+		App:      uc,
+		Dba:      uc,
+		Filtered: uc,
+		Repl:     uc,
+		Appdebug: uc,
+		Allprivs: uc,
+	}
+	dbcfgs.InitWithSocket("", env.CollationEnv())
+	return dbcfgs
+}
+
 // -----------------------------------------------------------
 
 type mysqlConnector struct {
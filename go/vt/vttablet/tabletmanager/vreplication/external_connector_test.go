@@ -17,14 +17,21 @@ limitations under the License.
 package vreplication
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/vt/binlog/binlogplayer"
-	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	"vitess.io/vitess/go/vt/dbconfigs"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
 	qh "vitess.io/vitess/go/vt/vttablet/tabletmanager/vreplication/queryhistory"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
 func TestExternalConnectorCopy(t *testing.T) {
@@ -133,6 +140,43 @@ func TestExternalConnectorCopy(t *testing.T) {
 	assert.Equal(t, 2, len(playerEngine.ec.connectors))
 }
 
+func TestExternalConnectorTopoConfig(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+	env := vtenv.NewTestEnv()
+
+	configured := &dbconfigs.DBConfigs{Host: "configured.example.com"}
+	ec := newExternalConnector(env, map[string]*dbconfigs.DBConfigs{"legacy": configured}, ts)
+	require.NoError(t, ts.CreateExternalMysql(ctx, "legacy", &topodatapb.ExternalMysql{Host: "registered.example.com"}))
+	require.NoError(t, ts.CreateExternalMysql(ctx, "imported", &topodatapb.ExternalMysql{
+		Host:     "imported.example.com",
+		Port:     3307,
+		User:     "vt_import",
+		Password: "secret",
+		DbName:   "legacy",
+	}))
+
+	// The tablet config takes precedence over the topo.
+	dbcfgs, err := ec.getDBConfigs(ctx, "legacy")
+	require.NoError(t, err)
+	require.Same(t, configured, dbcfgs)
+
+	dbcfgs, err = ec.getDBConfigs(ctx, "imported")
+	require.NoError(t, err)
+	params, err := dbcfgs.AllPrivsWithDB().MysqlParams()
+	require.NoError(t, err)
+	assert.Equal(t, "imported.example.com", params.Host)
+	assert.Equal(t, 3307, params.Port)
+	assert.Equal(t, "vt_import", params.Uname)
+	assert.Equal(t, "secret", params.Pass)
+	assert.Equal(t, "legacy", params.DbName)
+
+	_, err = ec.getDBConfigs(ctx, "unknown")
+	require.ErrorContains(t, err, "external mysql unknown not found in the externalConnections of the tablet config or in the topo")
+}
+
 func TestExternalConnectorPlay(t *testing.T) {
 	execStatements(t, []string{
 		"create table tab1(id int, val varbinary(128), primary key(id))",
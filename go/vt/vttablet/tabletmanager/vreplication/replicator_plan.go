@@ -92,7 +92,7 @@ func (rp *ReplicatorPlan) buildExecutionPlan(fieldEvent *binlogdatapb.FieldEvent
 		return &tplanv, nil
 	}
 	// select * construct was used. We need to use the field names.
-	tplan, err := rp.buildFromFields(prelim.TargetName, prelim.Lastpk, fieldEvent.Fields)
+	tplan, err := rp.buildFromFields(prelim.TargetName, prelim.Lastpk, fieldEvent.Fields, prelim.RenameColumns)
 	if err != nil {
 		return nil, vterrors.Wrapf(err, "failed to build replication plan for %s table", fieldEvent.TableName)
 	}
@@ -103,7 +103,9 @@ func (rp *ReplicatorPlan) buildExecutionPlan(fieldEvent *binlogdatapb.FieldEvent
 // buildFromFields builds a full TablePlan, but uses the field info as the
 // full column list. This happens when the query used was a 'select *', which
 // requires us to wait for the field info sent by the source.
-func (rp *ReplicatorPlan) buildFromFields(tableName string, lastpk *sqltypes.Result, fields []*querypb.Field) (*TablePlan, error) {
+// renameColumns optionally maps a lower case source field name to a
+// differently named target column.
+func (rp *ReplicatorPlan) buildFromFields(tableName string, lastpk *sqltypes.Result, fields []*querypb.Field, renameColumns map[string]string) (*TablePlan, error) {
 	tpb := &tablePlanBuilder{
 		name:           sqlparser.NewIdentifierCS(tableName),
 		lastpk:         lastpk,
@@ -115,11 +117,15 @@ func (rp *ReplicatorPlan) buildFromFields(tableName string, lastpk *sqltypes.Res
 	}
 	for _, field := range fields {
 		colName := sqlparser.NewIdentifierCI(field.Name)
+		targetColName := colName
+		if targetName, ok := renameColumns[strings.ToLower(field.Name)]; ok {
+			targetColName = sqlparser.NewIdentifierCI(targetName)
+		}
 		generated := false
 		// We have to loop over the columns in the plan as the columns between the
 		// source and target are not always 1 to 1.
 		for _, colInfo := range tpb.colInfos {
-			if !strings.EqualFold(colInfo.Name, field.Name) {
+			if !strings.EqualFold(colInfo.Name, targetColName.String()) {
 				continue
 			}
 			if colInfo.IsGenerated {
@@ -128,7 +134,7 @@ func (rp *ReplicatorPlan) buildFromFields(tableName string, lastpk *sqltypes.Res
 			break
 		}
 		cexpr := &colExpr{
-			colName: colName,
+			colName: targetColName,
 			colType: field.Type,
 			expr: &sqlparser.ColName{
 				Name: colName,
@@ -217,6 +223,10 @@ type TablePlan struct {
 	FieldsToSkip            map[string]bool
 	ConvertCharset          map[string](*binlogdatapb.CharsetConversion)
 	HasExtraSourcePkColumns bool
+	// RenameColumns maps lower case source column names to target column
+	// names. It's only used for 'select *' plans, when building the plan
+	// from fields.
+	RenameColumns map[string]string
 
 	TablePlanBuilder *tablePlanBuilder
 	// PartialInserts is a dynamically generated cache of insert ParsedQueries, which update only some columns.
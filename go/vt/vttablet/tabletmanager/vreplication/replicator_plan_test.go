@@ -831,6 +831,46 @@ func TestBuildPlayerPlanExclude(t *testing.T) {
 	assert.Equal(t, string(gotPlan), string(wantPlan))
 }
 
+func TestBuildExecutionPlanRenameColumns(t *testing.T) {
+	colInfos := map[string][]*ColumnInfo{
+		"t1": {&ColumnInfo{Name: "c1", IsPK: true}, &ColumnInfo{Name: "c2"}},
+	}
+	input := &binlogdatapb.Filter{
+		Rules: []*binlogdatapb.Rule{{
+			Match:         "t1",
+			RenameColumns: map[string]string{"renamed_c2": "c2"},
+		}},
+	}
+	vttablet.InitVReplicationConfigDefaults()
+	vr := &vreplicator{
+		workflowConfig: vttablet.DefaultVReplicationConfig,
+	}
+	plan, err := vr.buildReplicatorPlan(getSource(input), colInfos, nil, binlogplayer.NewStats(), collations.MySQL8(), sqlparser.NewTestParser())
+	require.NoError(t, err)
+
+	tplan, err := plan.buildExecutionPlan(&binlogdatapb.FieldEvent{
+		TableName: "t1",
+		Fields: sqltypes.MakeTestFields(
+			"c1|renamed_c2",
+			"int64|varchar",
+		),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "insert into t1(c1,c2) values (:a_c1,:a_renamed_c2)", tplan.Insert.Query)
+	assert.Equal(t, "update t1 set c2=:a_renamed_c2 where c1=:b_c1", tplan.Update.Query)
+
+	// The mapping applies to the source column whatever the case of its name.
+	tplan, err = plan.buildExecutionPlan(&binlogdatapb.FieldEvent{
+		TableName: "t1",
+		Fields: sqltypes.MakeTestFields(
+			"c1|Renamed_C2",
+			"int64|varchar",
+		),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "insert into t1(c1,c2) values (:a_c1,:a_Renamed_C2)", tplan.Insert.Query)
+}
+
 func TestAppendFromRow(t *testing.T) {
 	testCases := []struct {
 		name    string
@@ -0,0 +1,280 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"vitess.io/vitess/go/vt/schemadiff"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+// safeDDLPlan is the outcome of analyzing a source DDL for a workflow that
+// runs with the EXEC_SAFE OnDDLAction.
+type safeDDLPlan struct {
+	// sourceTable is the replicated source table that the DDL applies to.
+	// It is empty if the DDL does not affect any table in the workflow.
+	sourceTable string
+	// targetDDL is the statement to apply on the target. It is empty
+	// when the DDL does not require any change on the target, e.g. when
+	// it only renames explicitly mapped columns.
+	targetDDL string
+	// incompatible explains why the DDL cannot be safely applied. When
+	// it is set, the workflow must be stopped.
+	incompatible string
+}
+
+// safeDDLAnalyzer decides whether a source DDL is compatible with a running
+// workflow. It knows the target table's current definition, whether the
+// table plan selects all of the source columns (select *), and the explicit
+// source to target column mapping from the filter rule.
+type safeDDLAnalyzer struct {
+	env *schemadiff.Environment
+	// targetTable is the name of the target table.
+	targetTable string
+	// targetCreateTable is the current CREATE TABLE statement of the target table.
+	targetCreateTable string
+	// selectStar is true when the table plan is a 'select *' plan, whose
+	// column mapping is rebuilt from the field event after the DDL.
+	selectStar bool
+	// renameColumns maps a source column name to a target column name.
+	renameColumns map[string]string
+}
+
+func incompatibleDDL(format string, args ...any) *safeDDLPlan {
+	return &safeDDLPlan{incompatible: fmt.Sprintf(format, args...)}
+}
+
+// analyze returns the plan for applying the given source DDL on the target.
+// Only ALTER TABLE statements are considered. Among those, the following
+// changes are safe:
+//   - adding columns that are NULL-able or have a default value
+//   - modifying a column such that its data range is unchanged or widened,
+//     within the same type family
+//   - renaming a column, if the new source name is mapped to the existing
+//     target column in the rule's rename_columns
+//
+// Anything else is reported as incompatible.
+func (a *safeDDLAnalyzer) analyze(stmt sqlparser.Statement) (*safeDDLPlan, error) {
+	alterTable, ok := stmt.(*sqlparser.AlterTable)
+	if !ok {
+		return incompatibleDDL("%s statements are not supported", sqlparser.ASTToStatementType(stmt).String()), nil
+	}
+	if alterTable.PartitionSpec != nil || alterTable.PartitionOption != nil {
+		return incompatibleDDL("partitioning changes are not supported"), nil
+	}
+	targetEntity, err := schemadiff.NewCreateTableEntityFromSQL(a.env, a.targetCreateTable)
+	if err != nil {
+		return nil, err
+	}
+	targetColumns := targetEntity.ColumnDefinitionEntitiesMap()
+
+	// The target ALTER is the source ALTER, minus the mapped column renames,
+	// and with mapped column names substituted by their target names.
+	targetAlter := &sqlparser.AlterTable{
+		Table: sqlparser.NewTableName(a.targetTable),
+	}
+	for _, option := range alterTable.AlterOptions {
+		switch option := option.(type) {
+		case *sqlparser.AddColumns:
+			for _, col := range option.Columns {
+				if _, ok := targetColumns[col.Name.Lowered()]; ok {
+					return incompatibleDDL("column %s already exists on the target", col.Name.String()), nil
+				}
+				if col.Type.Options.Null != nil && !*col.Type.Options.Null && col.Type.Options.Default == nil {
+					return incompatibleDDL("added column %s is NOT NULL and has no default", col.Name.String()), nil
+				}
+			}
+			targetAlter.AlterOptions = append(targetAlter.AlterOptions, option)
+		case *sqlparser.ModifyColumn:
+			targetName, err := a.targetColumnName(option.NewColDefinition.Name.String())
+			if err != nil {
+				return incompatibleDDL("%v", err), nil
+			}
+			def := sqlparser.Clone(option.NewColDefinition)
+			def.Name = sqlparser.NewIdentifierCI(targetName)
+			targetAlter.AlterOptions = append(targetAlter.AlterOptions, &sqlparser.ModifyColumn{NewColDefinition: def})
+		case *sqlparser.ChangeColumn:
+			oldName, newName := option.OldColumn.Name.String(), option.NewColDefinition.Name.String()
+			targetName := oldName
+			if !strings.EqualFold(oldName, newName) {
+				var ok bool
+				if targetName, ok = a.mappedRename(oldName, newName); !ok {
+					return incompatibleDDL("column %s is renamed to %s without a mapping in rename_columns", oldName, newName), nil
+				}
+			}
+			def := sqlparser.Clone(option.NewColDefinition)
+			def.Name = sqlparser.NewIdentifierCI(targetName)
+			targetAlter.AlterOptions = append(targetAlter.AlterOptions, &sqlparser.ModifyColumn{NewColDefinition: def})
+		case *sqlparser.RenameColumn:
+			oldName, newName := option.OldName.Name.String(), option.NewName.Name.String()
+			if _, ok := a.mappedRename(oldName, newName); !ok {
+				return incompatibleDDL("column %s is renamed to %s without a mapping in rename_columns", oldName, newName), nil
+			}
+		default:
+			return incompatibleDDL("%s is not supported", sqlparser.String(option)), nil
+		}
+	}
+	if len(targetAlter.AlterOptions) == 0 {
+		return &safeDDLPlan{}, nil
+	}
+
+	// Let schemadiff validate the change against the target table, and then
+	// compare the resulting column definitions with the current ones.
+	applied, err := targetEntity.Apply(schemadiff.EntityDiffByStatement(targetAlter))
+	if err != nil {
+		return incompatibleDDL("cannot apply %s on the target: %v", sqlparser.String(targetAlter), err), nil
+	}
+	appliedColumns := applied.(*schemadiff.CreateTableEntity).ColumnDefinitionEntitiesMap()
+	for name, before := range targetColumns {
+		after, ok := appliedColumns[name]
+		if !ok {
+			return incompatibleDDL("column %s would be dropped from the target", before.Name()), nil
+		}
+		if beforeFamily, afterFamily := columnTypeFamily(before.Type()), columnTypeFamily(after.Type()); beforeFamily != afterFamily {
+			return incompatibleDDL("change to column %s is not a widening change: type changes from %s to %s", before.Name(), before.Type(), after.Type()), nil
+		}
+		if narrowed, reason := schemadiff.ColumnChangeExpandsDataRange(after, before); narrowed {
+			return incompatibleDDL("change to column %s is not a widening change: %s", before.Name(), reason), nil
+		}
+	}
+	return &safeDDLPlan{targetDDL: sqlparser.String(targetAlter)}, nil
+}
+
+// columnTypeFamily returns the family of a column type. A column change can
+// only widen the data range of a column within the same family: e.g. INT to
+// BIGINT is a widening change, while INT to VARCHAR or DECIMAL to FLOAT is
+// not, even though schemadiff does not consider it a narrowing one.
+func columnTypeFamily(columnType string) string {
+	columnType = strings.ToLower(columnType)
+	switch {
+	case schemadiff.IsIntegralType(columnType):
+		return "integer"
+	case schemadiff.IsDecimalType(columnType):
+		return "decimal"
+	case schemadiff.IsFloatingPointType(columnType):
+		return "float"
+	}
+	switch columnType {
+	case "char", "varchar", "tinytext", "text", "mediumtext", "longtext":
+		return "text"
+	case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob":
+		return "binary"
+	}
+	return columnType
+}
+
+// targetColumnName returns the name of the target column that the given
+// source column is replicated into.
+func (a *safeDDLAnalyzer) targetColumnName(sourceName string) (string, error) {
+	for from, to := range a.renameColumns {
+		if strings.EqualFold(from, sourceName) {
+			if !a.selectStar {
+				return "", fmt.Errorf("column %s is mapped to %s but the rule does not select all columns", from, to)
+			}
+			return to, nil
+		}
+	}
+	return sourceName, nil
+}
+
+// mappedRename returns the target column for a source column rename, if
+// the new source name is explicitly mapped to the target column that the
+// old source name was replicated into.
+func (a *safeDDLAnalyzer) mappedRename(oldName, newName string) (string, bool) {
+	if !a.selectStar {
+		return "", false
+	}
+	targetName, err := a.targetColumnName(newName)
+	if err != nil || strings.EqualFold(targetName, newName) {
+		return "", false
+	}
+	previous, err := a.targetColumnName(oldName)
+	if err != nil || !strings.EqualFold(previous, targetName) {
+		return "", false
+	}
+	return targetName, true
+}
+
+// planSafeDDL analyzes a DDL received from the source for the EXEC_SAFE
+// OnDDLAction. DDLs on tables that are not part of the workflow are ignored.
+func (vp *vplayer) planSafeDDL(ctx context.Context, ddl string) (*safeDDLPlan, error) {
+	stmt, err := vp.vr.vre.env.Parser().Parse(ddl)
+	if err != nil {
+		return incompatibleDDL("cannot parse DDL: %v", err), nil
+	}
+	ddlStmt, ok := stmt.(sqlparser.DDLStatement)
+	if !ok {
+		return incompatibleDDL("%s statements are not supported", sqlparser.ASTToStatementType(stmt).String()), nil
+	}
+	var sourceTable string
+	var tablePlan *TablePlan
+	for _, table := range ddlStmt.AffectedTables() {
+		if tp, ok := vp.replicatorPlan.TablePlans[table.Name.String()]; ok {
+			if tablePlan != nil {
+				return incompatibleDDL("DDL affects more than one table in the workflow"), nil
+			}
+			sourceTable, tablePlan = table.Name.String(), tp
+		}
+	}
+	if tablePlan == nil {
+		return &safeDDLPlan{}, nil
+	}
+	req := &tabletmanagerdatapb.GetSchemaRequest{Tables: []string{tablePlan.TargetName}}
+	sd, err := vp.vr.mysqld.GetSchema(ctx, vp.vr.dbClient.DBName(), req)
+	if err != nil {
+		return nil, err
+	}
+	if len(sd.TableDefinitions) != 1 {
+		return nil, fmt.Errorf("could not find the definition of target table %s", tablePlan.TargetName)
+	}
+	venv := vp.vr.vre.env
+	analyzer := &safeDDLAnalyzer{
+		env:               schemadiff.NewEnv(venv, venv.CollationEnv().DefaultConnectionCharset()),
+		targetTable:       tablePlan.TargetName,
+		targetCreateTable: sd.TableDefinitions[0].Schema,
+		selectStar:        tablePlan.Insert == nil,
+		renameColumns:     tablePlan.RenameColumns,
+	}
+	plan, err := analyzer.analyze(stmt)
+	if err != nil {
+		return nil, err
+	}
+	plan.sourceTable = sourceTable
+	return plan, nil
+}
+
+// refreshTablePlan reloads the target column info after a safe DDL, and
+// drops the execution plan of the affected table. The plan is rebuilt
+// from the field event that the source sends after the DDL.
+func (vp *vplayer) refreshTablePlan(ctx context.Context, sourceTable string) error {
+	if sourceTable == "" {
+		return nil
+	}
+	colInfoMap, err := vp.vr.buildColInfoMap(ctx)
+	if err != nil {
+		return err
+	}
+	vp.vr.colInfoMap = colInfoMap
+	vp.replicatorPlan.ColInfoMap = colInfoMap
+	delete(vp.tablePlans, sourceTable)
+	return nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/schemadiff"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+func TestSafeDDLAnalyzer(t *testing.T) {
+	const targetCreateTable = "create table t1 (id int not null, name varchar(64), email varchar(128), amount int, primary key (id))"
+	testcases := []struct {
+		name          string
+		ddl           string
+		selectStar    bool
+		renameColumns map[string]string
+		targetDDL     string
+		incompatible  string
+	}{
+		{
+			name:       "add nullable column",
+			ddl:        "alter table t1 add column notes text",
+			selectStar: true,
+			targetDDL:  "alter table t1 add column notes text",
+		},
+		{
+			name:       "add not null column with default",
+			ddl:        "alter table t1 add column status int not null default 0",
+			selectStar: true,
+			targetDDL:  "alter table t1 add column `status` int not null default 0",
+		},
+		{
+			name:         "add not null column without default",
+			ddl:          "alter table t1 add column status int not null",
+			selectStar:   true,
+			incompatible: "added column status is NOT NULL and has no default",
+		},
+		{
+			name:         "add existing column",
+			ddl:          "alter table t1 add column name varchar(64)",
+			selectStar:   true,
+			incompatible: "column name already exists on the target",
+		},
+		{
+			name:       "widen varchar",
+			ddl:        "alter table t1 modify column name varchar(255)",
+			selectStar: true,
+			targetDDL:  "alter table t1 modify column `name` varchar(255)",
+		},
+		{
+			name:       "widen int",
+			ddl:        "alter table t1 modify column amount bigint",
+			selectStar: true,
+			targetDDL:  "alter table t1 modify column amount bigint",
+		},
+		{
+			name:         "narrow varchar",
+			ddl:          "alter table t1 modify column name varchar(16)",
+			selectStar:   true,
+			incompatible: "change to column name is not a widening change: increased length",
+		},
+		{
+			name:         "int to varchar",
+			ddl:          "alter table t1 modify column amount varchar(255)",
+			selectStar:   true,
+			incompatible: "change to column amount is not a widening change: type changes from int to varchar",
+		},
+		{
+			name:         "int to decimal",
+			ddl:          "alter table t1 modify column amount decimal(65,2)",
+			selectStar:   true,
+			incompatible: "change to column amount is not a widening change: type changes from int to decimal",
+		},
+		{
+			name:         "int to float",
+			ddl:          "alter table t1 modify column amount double",
+			selectStar:   true,
+			incompatible: "change to column amount is not a widening change: type changes from int to double",
+		},
+		{
+			name:         "make column not null",
+			ddl:          "alter table t1 modify column amount int not null",
+			selectStar:   true,
+			incompatible: "change to column amount is not a widening change: target is NULL-able, source is not",
+		},
+		{
+			name:         "unmapped rename",
+			ddl:          "alter table t1 rename column email to contact_email",
+			selectStar:   true,
+			incompatible: "column email is renamed to contact_email without a mapping in rename_columns",
+		},
+		{
+			name:          "mapped rename",
+			ddl:           "alter table t1 rename column email to contact_email",
+			selectStar:    true,
+			renameColumns: map[string]string{"contact_email": "email"},
+		},
+		{
+			name:          "mapped rename without select star",
+			ddl:           "alter table t1 rename column email to contact_email",
+			renameColumns: map[string]string{"contact_email": "email"},
+			incompatible:  "column email is renamed to contact_email without a mapping in rename_columns",
+		},
+		{
+			name:          "mapped change column",
+			ddl:           "alter table t1 change column email contact_email varchar(255)",
+			selectStar:    true,
+			renameColumns: map[string]string{"contact_email": "email"},
+			targetDDL:     "alter table t1 modify column email varchar(255)",
+		},
+		{
+			name:          "modify previously renamed column",
+			ddl:           "alter table t1 modify column contact_email varchar(255)",
+			selectStar:    true,
+			renameColumns: map[string]string{"contact_email": "email"},
+			targetDDL:     "alter table t1 modify column email varchar(255)",
+		},
+		{
+			name:         "drop column",
+			ddl:          "alter table t1 drop column amount",
+			selectStar:   true,
+			incompatible: "drop column amount is not supported",
+		},
+		{
+			name:         "add index",
+			ddl:          "alter table t1 add index name_idx (name)",
+			selectStar:   true,
+			incompatible: "add key name_idx (`name`) is not supported",
+		},
+		{
+			name:         "drop table",
+			ddl:          "drop table t1",
+			selectStar:   true,
+			incompatible: "DDL statements are not supported",
+		},
+	}
+	parser := sqlparser.NewTestParser()
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			stmt, err := parser.Parse(tcase.ddl)
+			require.NoError(t, err)
+			analyzer := &safeDDLAnalyzer{
+				env:               schemadiff.NewTestEnv(),
+				targetTable:       "t1",
+				targetCreateTable: targetCreateTable,
+				selectStar:        tcase.selectStar,
+				renameColumns:     tcase.renameColumns,
+			}
+			plan, err := analyzer.analyze(stmt)
+			require.NoError(t, err)
+			assert.Equal(t, tcase.incompatible, plan.incompatible)
+			assert.Equal(t, tcase.targetDDL, plan.targetDDL)
+		})
+	}
+}
@@ -251,6 +251,7 @@ func buildTablePlan(tableName string, rule *binlogdatapb.Rule, colInfos []*Colum
 			Stats:            stats,
 			ConvertCharset:   rule.ConvertCharset,
 			ConvertIntToEnum: rule.ConvertIntToEnum,
+			RenameColumns:    lowerCaseRenameColumns(rule.RenameColumns),
 			CollationEnv:     collationEnv,
 			WorkflowConfig:   workflowConfig,
 		}
@@ -989,3 +990,16 @@ func (bvf *bindvarFormatter) formatter(buf *sqlparser.TrackedBuffer, node sqlpar
 	}
 	node.Format(buf)
 }
+
+// lowerCaseRenameColumns returns the column mapping of a rule keyed by the
+// lower case source column names, as column names are case insensitive.
+func lowerCaseRenameColumns(renameColumns map[string]string) map[string]string {
+	if len(renameColumns) == 0 {
+		return nil
+	}
+	lowered := make(map[string]string, len(renameColumns))
+	for from, to := range renameColumns {
+		lowered[strings.ToLower(from)] = to
+	}
+	return lowered
+}
@@ -99,6 +99,9 @@ type vplayer struct {
 	// foreignKeyChecksStateInitialized is set to true once we have initialized the foreignKeyChecksEnabled.
 	// The initialization is done on the first row event that this vplayer sees.
 	foreignKeyChecksStateInitialized bool
+
+	// parallelApplier is set when transactions are applied in parallel, see parallelApplier.
+	parallelApplier *parallelApplier
 }
 
 // NoForeignKeyCheckFlagBitmask is the bitmask for the 2nd bit (least significant) of the flags in a binlog row event.
@@ -480,6 +483,20 @@ func (vp *vplayer) recordHeartbeat() error {
 func (vp *vplayer) applyEvents(ctx context.Context, relay *relayLog) error {
 	defer vp.vr.dbClient.Rollback()
 
+	// Transactions are only applied in parallel in the running phase, when
+	// there is no stop position to stop at exactly.
+	if workers := vp.vr.workflowConfig.ParallelApplyWorkers; workers > 1 && len(vp.copyState) == 0 && vp.stopPos.IsZero() {
+		pa, err := newParallelApplier(ctx, vp, workers)
+		if err != nil {
+			return err
+		}
+		vp.parallelApplier = pa
+		defer func() {
+			pa.close()
+			vp.parallelApplier = nil
+		}()
+	}
+
 	estimateLag := func() {
 		behind := time.Now().UnixNano() - vp.lastTimestampNs - vp.timeOffsetNs
 		vp.vr.stats.ReplicationLagSeconds.Store(behind / 1e9)
@@ -515,6 +532,12 @@ func (vp *vplayer) applyEvents(ctx context.Context, relay *relayLog) error {
 		// In both cases, now > timeLastSaved. If so, the GTID of the last unsavedEvent
 		// must be saved.
 		if time.Since(vp.timeLastSaved) >= idleTimeout && vp.unsavedEvent != nil {
+			if vp.parallelApplier != nil {
+				// The in-flight transactions precede the unsaved event.
+				if err := vp.parallelApplier.wait(ctx); err != nil {
+					return err
+				}
+			}
 			posReached, err := vp.updatePos(ctx, vp.unsavedEvent.Timestamp)
 			if err != nil {
 				return err
@@ -555,11 +578,18 @@ func (vp *vplayer) applyEvents(ctx context.Context, relay *relayLog) error {
 					// applying the next set of events as part of the current transaction. This approach
 					// also handles the case where the last transaction is partial. In that case,
 					// we only group the transactions with commits we've seen so far.
-					if hasAnotherCommit(items, i, j+1) {
+					// Commits are not grouped when applying transactions in parallel.
+					if vp.parallelApplier == nil && hasAnotherCommit(items, i, j+1) {
 						continue
 					}
 				}
-				if err := vp.applyEvent(ctx, event, mustSave); err != nil {
+				var err error
+				if vp.parallelApplier != nil {
+					err = vp.parallelApplier.applyEvent(ctx, event)
+				} else {
+					err = vp.applyEvent(ctx, event, mustSave)
+				}
+				if err != nil {
 					if err != io.EOF {
 						vp.vr.stats.ErrorCounts.Add([]string{"Apply"}, 1)
 						var table, tableLogMsg, gtidLogMsg string
@@ -804,6 +834,46 @@ func (vp *vplayer) applyEvent(ctx context.Context, event *binlogdatapb.VEvent, m
 			if posReached {
 				return io.EOF
 			}
+		case binlogdatapb.OnDDLAction_EXEC_SAFE:
+			plan, err := vp.planSafeDDL(ctx, event.Statement)
+			if err != nil {
+				return err
+			}
+			if plan.incompatible != "" {
+				// Same as STOP, but with the reason in the message.
+				if err := vp.vr.dbClient.Begin(); err != nil {
+					return err
+				}
+				if _, err := vp.updatePos(ctx, event.Timestamp); err != nil {
+					return err
+				}
+				if err := vp.vr.setState(binlogdatapb.VReplicationWorkflowState_Stopped, fmt.Sprintf("Stopped at incompatible DDL %s: %s", event.Statement, plan.incompatible)); err != nil {
+					return err
+				}
+				if err := vp.commit(); err != nil {
+					return err
+				}
+				return io.EOF
+			}
+			// As with EXEC, the DDL is applied first, and then the position is saved.
+			if plan.targetDDL != "" {
+				if _, err := vp.query(ctx, plan.targetDDL); err != nil {
+					return err
+				}
+				if stats != nil {
+					stats.Send(plan.targetDDL)
+				}
+			}
+			if err := vp.refreshTablePlan(ctx, plan.sourceTable); err != nil {
+				return err
+			}
+			posReached, err := vp.updatePos(ctx, event.Timestamp)
+			if err != nil {
+				return err
+			}
+			if posReached {
+				return io.EOF
+			}
 		}
 	case binlogdatapb.VEventType_JOURNAL:
 		if vp.vr.dbClient.InTransaction {
@@ -2121,6 +2121,83 @@ func TestPlayerDDL(t *testing.T) {
 	cancel()
 }
 
+func TestPlayerDDLExecSafe(t *testing.T) {
+	defer deleteTablet(addTablet(100))
+	execStatements(t, []string{
+		"create table t1(id int, val varchar(64), primary key(id))",
+		fmt.Sprintf("create table %s.t1(id int, val varchar(64), primary key(id))", vrepldb),
+	})
+	defer execStatements(t, []string{
+		"drop table t1",
+		fmt.Sprintf("drop table %s.t1", vrepldb),
+	})
+
+	filter := &binlogdatapb.Filter{
+		Rules: []*binlogdatapb.Rule{{
+			Match:         "t1",
+			RenameColumns: map[string]string{"contact": "val"},
+		}},
+	}
+	bls := &binlogdatapb.BinlogSource{
+		Keyspace: env.KeyspaceName,
+		Shard:    env.ShardName,
+		Filter:   filter,
+		OnDdl:    binlogdatapb.OnDDLAction_EXEC_SAFE,
+	}
+	cancel, id := startVReplication(t, bls, "")
+	defer cancel()
+	// Issue a dummy change to ensure vreplication is initialized. See TestPlayerDDL.
+	execStatements(t, []string{"insert into t1 values(1, 'a')"})
+	expectDBClientQueries(t, qh.Expect(
+		"begin",
+		"insert into t1(id,val) values (1,'a')",
+		"/update _vt.vreplication set pos=",
+		"commit",
+	))
+
+	// A safe change is applied on the target.
+	execStatements(t, []string{"alter table t1 add column notes varchar(128)"})
+	expectDBClientQueries(t, qh.Expect(
+		"alter table t1 add column notes varchar(128)",
+		"/update _vt.vreplication set pos=",
+		// The apply of the DDL on target generates an "other" event.
+		"/update _vt.vreplication set pos=",
+	))
+
+	// A mapped rename is not applied on the target, and the renamed source
+	// column keeps being replicated into the existing target column.
+	execStatements(t, []string{"alter table t1 rename column val to contact"})
+	expectDBClientQueries(t, qh.Expect(
+		"/update _vt.vreplication set pos=",
+	))
+	execStatements(t, []string{"insert into t1 values(2, 'b', 'c')"})
+	expectDBClientQueries(t, qh.Expect(
+		"begin",
+		"insert into t1(id,val,notes) values (2,'b','c')",
+		"/update _vt.vreplication set pos=",
+		"commit",
+	))
+	expectData(t, "t1", [][]string{
+		{"1", "a", ""},
+		{"2", "b", "c"},
+	})
+
+	// An incompatible change stops the workflow at the DDL, with the reason
+	// in the message.
+	execStatements(t, []string{"alter table t1 modify column notes int"})
+	pos := primaryPosition(t)
+	expectDBClientQueries(t, qh.Expect(
+		"begin",
+		fmt.Sprintf("/update _vt.vreplication set pos='%s'", pos),
+		"/update _vt.vreplication set state='Stopped', message='Stopped at incompatible DDL alter table t1 modify column notes int: change to column notes is not a widening change: type changes from varchar to int'",
+		"commit",
+	))
+	qr, err := playerEngine.Exec(fmt.Sprintf("select state from _vt.vreplication where id=%d", id))
+	require.NoError(t, err)
+	require.Equal(t, binlogdatapb.VReplicationWorkflowState_Stopped.String(), qr.Rows[0][0].ToString())
+	execStatements(t, []string{"alter table t1 rename column contact to val"})
+}
+
 func TestGTIDCompress(t *testing.T) {
 	ctx := context.Background()
 	defer deleteTablet(addTablet(100))
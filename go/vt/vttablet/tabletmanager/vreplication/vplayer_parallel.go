@@ -0,0 +1,579 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/collations/colldata"
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+// maxTrackedWritesets is the number of writeset keys above which the keys of
+// transactions that have already been committed are pruned from the tracker.
+const maxTrackedWritesets = 100000
+
+// writesetColumn is a column of a unique key of the target table, as seen in
+// the row events of the source table.
+type writesetColumn struct {
+	// field is the index of the source field that the target column is
+	// replicated from.
+	field int
+	// collation is the collation of the target column, for textual columns.
+	// Values are compared using their weight strings, so that rows that
+	// conflict in the target unique key are also detected as conflicting
+	// when they differ in case, trailing spaces, etc.
+	collation collations.ID
+}
+
+// tableWriteset computes the writeset of the row changes of a table. The
+// writeset of a row change is the set of values of all unique keys of the
+// target table, in the before and after images of the row. Two transactions
+// can be applied concurrently only if their writesets don't intersect.
+type tableWriteset struct {
+	table string
+	// keys has an entry for each unique key of the target table, including
+	// the primary key.
+	keys [][]writesetColumn
+	// barrier is set when the writeset of a row change cannot be reliably
+	// computed from the row events of the table. Transactions that modify
+	// such a table are applied on their own.
+	barrier bool
+}
+
+// buildTableWriteset builds the writeset definition for the given table plan,
+// using the CREATE TABLE statement of the target table. The target unique keys
+// must be computed from plain source columns. Tables with foreign keys, and
+// tables whose plans aggregate rows, are always applied as barriers.
+func buildTableWriteset(tplan *TablePlan, targetCreateTable string, parser *sqlparser.Parser, collationEnv *collations.Environment) *tableWriteset {
+	tw := &tableWriteset{table: tplan.TargetName, barrier: true}
+	tpb := tplan.TablePlanBuilder
+	if tpb == nil || tpb.onInsert != insertNormal || len(tplan.ConvertCharset) > 0 {
+		return tw
+	}
+	stmt, err := parser.Parse(targetCreateTable)
+	if err != nil {
+		return tw
+	}
+	createTable, ok := stmt.(*sqlparser.CreateTable)
+	if !ok || createTable.TableSpec == nil {
+		return tw
+	}
+	for _, constraint := range createTable.TableSpec.Constraints {
+		if _, ok := constraint.Details.(*sqlparser.ForeignKeyDefinition); ok {
+			return tw
+		}
+	}
+
+	fieldIndexes := make(map[string]int, len(tplan.Fields))
+	for i, field := range tplan.Fields {
+		fieldIndexes[strings.ToLower(field.Name)] = i
+	}
+	columnCollations := make(map[string]collations.ID, len(tpb.colInfos))
+	for _, colInfo := range tpb.colInfos {
+		if colInfo.Collation != "" {
+			columnCollations[strings.ToLower(colInfo.Name)] = collationEnv.LookupByName(colInfo.Collation)
+		}
+	}
+	// Map each target column to the index of the source field it's
+	// replicated from, if it's replicated as is.
+	sourceFields := make(map[string]int, len(tpb.colExprs))
+	for _, cexpr := range tpb.colExprs {
+		if cexpr.operation != opExpr {
+			continue
+		}
+		colName, ok := cexpr.expr.(*sqlparser.ColName)
+		if !ok {
+			continue
+		}
+		if i, ok := fieldIndexes[colName.Name.Lowered()]; ok {
+			sourceFields[cexpr.colName.Lowered()] = i
+		}
+	}
+
+	for _, index := range createTable.TableSpec.Indexes {
+		if !index.Info.IsUnique() {
+			continue
+		}
+		key := make([]writesetColumn, 0, len(index.Columns))
+		for _, col := range index.Columns {
+			if col.Column.IsEmpty() || col.Length != nil {
+				// Functional and prefix key parts are not supported.
+				return tw
+			}
+			field, ok := sourceFields[col.Column.Lowered()]
+			if !ok {
+				return tw
+			}
+			wc := writesetColumn{field: field}
+			if sqltypes.IsText(tplan.Fields[field].Type) {
+				wc.collation = columnCollations[col.Column.Lowered()]
+			}
+			key = append(key, wc)
+		}
+		tw.keys = append(tw.keys, key)
+	}
+	tw.barrier = len(tw.keys) == 0
+	return tw
+}
+
+// rowKeys returns the writeset keys of a row change. It returns false if the
+// keys cannot be computed, e.g. for partial row images.
+func (tw *tableWriteset) rowKeys(tplan *TablePlan, rowChange *binlogdatapb.RowChange) ([]string, bool) {
+	if tw.barrier || tplan.isPartial(rowChange) {
+		return nil, false
+	}
+	var keys []string
+	for _, row := range []*querypb.Row{rowChange.Before, rowChange.After} {
+		if row == nil {
+			continue
+		}
+		vals := sqltypes.MakeRowTrusted(tplan.Fields, row)
+		for i, key := range tw.keys {
+			if k, ok := tw.encodeKey(i, key, vals); ok {
+				keys = append(keys, k)
+			}
+		}
+	}
+	return keys, true
+}
+
+// encodeKey encodes the values of a unique key of a row. It returns false if
+// any of the values is NULL, since NULLs never conflict in a unique key.
+func (tw *tableWriteset) encodeKey(index int, key []writesetColumn, vals []sqltypes.Value) (string, bool) {
+	var buf []byte
+	buf = binary.AppendUvarint(buf, uint64(len(tw.table)))
+	buf = append(buf, tw.table...)
+	buf = binary.AppendUvarint(buf, uint64(index))
+	for _, col := range key {
+		if col.field >= len(vals) || vals[col.field].IsNull() {
+			return "", false
+		}
+		raw := vals[col.field].Raw()
+		if col.collation != collations.Unknown {
+			if coll := colldata.Lookup(col.collation); coll != nil {
+				raw = coll.WeightString(nil, bytes.TrimRight(raw, " "), 0)
+			}
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(raw)))
+		buf = append(buf, raw...)
+	}
+	return string(buf), true
+}
+
+// writesetTracker assigns sequence numbers to transactions in source order,
+// and computes the latest earlier transaction that each one depends on.
+// It's only used by the goroutine that dispatches the transactions.
+type writesetTracker struct {
+	lastSeq int64
+	// lastBarrier is the sequence number of the latest barrier transaction.
+	// All transactions depend on it.
+	lastBarrier int64
+	// lastWriter maps a writeset key to the sequence number of the latest
+	// transaction that modified it.
+	lastWriter map[string]int64
+}
+
+func newWritesetTracker() *writesetTracker {
+	return &writesetTracker{lastWriter: make(map[string]int64)}
+}
+
+// track returns the sequence number of the next transaction, and the sequence
+// number of the latest transaction that must be committed before it can be
+// applied. A barrier transaction depends on all the earlier transactions.
+func (wt *writesetTracker) track(keys []string, barrier bool) (seq, dependsOn int64) {
+	wt.lastSeq++
+	seq = wt.lastSeq
+	dependsOn = wt.lastBarrier
+	if barrier {
+		wt.lastBarrier = seq
+		return seq, seq - 1
+	}
+	for _, key := range keys {
+		if last := wt.lastWriter[key]; last > dependsOn {
+			dependsOn = last
+		}
+	}
+	// The keys are only recorded once all of them have been looked up, since
+	// the same key can appear more than once, e.g. in the before and after
+	// images of an update.
+	for _, key := range keys {
+		wt.lastWriter[key] = seq
+	}
+	return seq, dependsOn
+}
+
+// prune forgets the keys that were last modified by transactions that have
+// already been committed.
+func (wt *writesetTracker) prune(committed int64) {
+	for key, seq := range wt.lastWriter {
+		if seq <= committed {
+			delete(wt.lastWriter, key)
+		}
+	}
+}
+
+// parallelTxn is a transaction received from the source, to be applied by a
+// parallel apply worker.
+type parallelTxn struct {
+	seq       int64
+	dependsOn int64
+	pos       replication.Position
+	timestamp int64
+	events    []*parallelTxnEvent
+	keys      []string
+	barrier   bool
+}
+
+type parallelTxnEvent struct {
+	event *binlogdatapb.VEvent
+	tplan *TablePlan
+}
+
+// parallelApplyWorker applies transactions on its own connection.
+type parallelApplyWorker struct {
+	dbClient *vdbClient
+	// See vplayer.updateFKCheck.
+	foreignKeyChecksEnabled          bool
+	foreignKeyChecksStateInitialized bool
+}
+
+// parallelApplier applies the transactions of the running phase concurrently
+// on a pool of workers. Each transaction only waits for the earlier ones that
+// modified the same rows, as determined by their writesets. Transactions are
+// still committed in source order, each one along with its own position, so
+// the position saved in the vreplication table is always one that everything
+// before it has been applied for. After a restart, replication resumes from
+// that position.
+//
+// Events other than row events and transaction boundaries, e.g. DDLs, are
+// barriers: the applier waits for all the in-flight transactions to commit,
+// and then the vplayer applies the event as usual.
+type parallelApplier struct {
+	vp *vplayer
+
+	workers    chan *parallelApplyWorker
+	allWorkers []*parallelApplyWorker
+	wg         sync.WaitGroup
+
+	// The fields below are only accessed by the dispatching goroutine.
+	tracker   *writesetTracker
+	writesets map[string]*tableWriteset
+	txn       *parallelTxn
+
+	mu   sync.Mutex
+	cond *sync.Cond
+	// committed is the sequence number of the latest committed transaction.
+	committed int64
+	// err is set if a transaction failed to apply. No more transactions
+	// are committed after that.
+	err error
+
+	stopAfterFunc func() bool
+}
+
+func newParallelApplier(ctx context.Context, vp *vplayer, numWorkers int) (*parallelApplier, error) {
+	pa := &parallelApplier{
+		vp:        vp,
+		workers:   make(chan *parallelApplyWorker, numWorkers),
+		tracker:   newWritesetTracker(),
+		writesets: make(map[string]*tableWriteset),
+	}
+	pa.cond = sync.NewCond(&pa.mu)
+	pa.stopAfterFunc = context.AfterFunc(ctx, func() {
+		pa.mu.Lock()
+		defer pa.mu.Unlock()
+		pa.cond.Broadcast()
+	})
+	for range numWorkers {
+		dbClient, err := vp.vr.newClientConnection(ctx)
+		if err != nil {
+			pa.close()
+			return nil, err
+		}
+		w := &parallelApplyWorker{dbClient: dbClient}
+		pa.allWorkers = append(pa.allWorkers, w)
+		// Transactions that don't conflict can still block each other on gap
+		// locks. An earlier transaction waiting on a lock held by a later one,
+		// which in turn waits for its turn to commit, would then stall until
+		// the lock wait timeout. Gap locks are not taken in READ COMMITTED.
+		if _, err := dbClient.Execute("set session transaction isolation level read committed"); err != nil {
+			pa.close()
+			return nil, vterrors.Wrap(err, "failed to set transaction isolation level")
+		}
+		pa.workers <- w
+	}
+	log.Infof("VReplication player id: %v, name: %v, applying transactions with %d parallel workers", vp.vr.id, vp.vr.WorkflowName, numWorkers)
+	return pa, nil
+}
+
+// close waits for the in-flight transactions and closes the worker connections.
+func (pa *parallelApplier) close() {
+	pa.wg.Wait()
+	pa.stopAfterFunc()
+	for _, w := range pa.allWorkers {
+		w.dbClient.Close()
+	}
+}
+
+// waitFor waits until the transaction with the given sequence number has been
+// committed, or until applying transactions failed.
+func (pa *parallelApplier) waitFor(ctx context.Context, seq int64) error {
+	pa.mu.Lock()
+	defer pa.mu.Unlock()
+	for pa.committed < seq && pa.err == nil && ctx.Err() == nil {
+		pa.cond.Wait()
+	}
+	if pa.err != nil {
+		return pa.err
+	}
+	return ctx.Err()
+}
+
+// wait waits for all the dispatched transactions to be committed.
+func (pa *parallelApplier) wait(ctx context.Context) error {
+	return pa.waitFor(ctx, pa.tracker.lastSeq)
+}
+
+// applyEvent is the parallel counterpart of vplayer.applyEvent. Row events are
+// accumulated into a transaction, which is dispatched to a worker on commit.
+func (pa *parallelApplier) applyEvent(ctx context.Context, event *binlogdatapb.VEvent) error {
+	vp := pa.vp
+	switch event.Type {
+	case binlogdatapb.VEventType_GTID, binlogdatapb.VEventType_BEGIN, binlogdatapb.VEventType_HEARTBEAT:
+		return vp.applyEvent(ctx, event, false)
+	case binlogdatapb.VEventType_FIELD:
+		tplan, err := vp.replicatorPlan.buildExecutionPlan(event.FieldEvent)
+		if err != nil {
+			return err
+		}
+		createTable, err := vp.targetTableSchema(ctx, tplan.TargetName)
+		if err != nil {
+			return err
+		}
+		vp.tablePlans[event.FieldEvent.TableName] = tplan
+		pa.writesets[event.FieldEvent.TableName] = buildTableWriteset(tplan, createTable, vp.vr.vre.env.Parser(), vp.vr.vre.env.CollationEnv())
+	case binlogdatapb.VEventType_ROW:
+		rowEvent := event.RowEvent
+		tplan := vp.tablePlans[rowEvent.TableName]
+		tw := pa.writesets[rowEvent.TableName]
+		if tplan == nil || tw == nil {
+			return fmt.Errorf("unexpected event on table %s", rowEvent.TableName)
+		}
+		txn := pa.currentTxn()
+		txn.events = append(txn.events, &parallelTxnEvent{event: event, tplan: tplan})
+		for _, change := range rowEvent.RowChanges {
+			keys, ok := tw.rowKeys(tplan, change)
+			if !ok {
+				txn.barrier = true
+				break
+			}
+			txn.keys = append(txn.keys, keys...)
+		}
+	case binlogdatapb.VEventType_INSERT, binlogdatapb.VEventType_DELETE, binlogdatapb.VEventType_UPDATE,
+		binlogdatapb.VEventType_REPLACE, binlogdatapb.VEventType_SAVEPOINT:
+		sql := event.Statement
+		if sql == "" {
+			sql = event.Dml
+		}
+		// Same as in vplayer.applyEvent.
+		if strings.Contains(sql, " mysql.rds_") || strings.Contains(sql, " percona.checksums") {
+			return nil
+		}
+		if event.Type != binlogdatapb.VEventType_SAVEPOINT && !vp.canAcceptStmtEvents {
+			return fmt.Errorf("filter rules are not supported for SBR replication: %v", vp.vr.source.Filter.GetRules())
+		}
+		txn := pa.currentTxn()
+		txn.events = append(txn.events, &parallelTxnEvent{event: event})
+		// The rows modified by a statement are unknown.
+		if event.Type != binlogdatapb.VEventType_SAVEPOINT {
+			txn.barrier = true
+		}
+	case binlogdatapb.VEventType_COMMIT:
+		if pa.txn == nil {
+			// An empty transaction, which is handled by the vplayer.
+			return vp.applyEvent(ctx, event, false)
+		}
+		txn := pa.txn
+		pa.txn = nil
+		txn.pos = vp.pos
+		txn.timestamp = event.Timestamp
+		return pa.dispatch(ctx, txn)
+	default:
+		if pa.txn != nil {
+			// Unreachable
+			log.Errorf("internal error: vplayer is in a transaction on event: %v", event)
+			return fmt.Errorf("internal error: vplayer is in a transaction on event: %v", event)
+		}
+		if err := pa.wait(ctx); err != nil {
+			return err
+		}
+		return vp.applyEvent(ctx, event, false)
+	}
+	return nil
+}
+
+func (pa *parallelApplier) currentTxn() *parallelTxn {
+	if pa.txn == nil {
+		pa.txn = &parallelTxn{}
+	}
+	return pa.txn
+}
+
+// dispatch hands the transaction over to the next available worker.
+func (pa *parallelApplier) dispatch(ctx context.Context, txn *parallelTxn) error {
+	var w *parallelApplyWorker
+	select {
+	case w = <-pa.workers:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	pa.mu.Lock()
+	err, committed := pa.err, pa.committed
+	pa.mu.Unlock()
+	if err != nil {
+		pa.workers <- w
+		return err
+	}
+	txn.seq, txn.dependsOn = pa.tracker.track(txn.keys, txn.barrier)
+	if len(pa.tracker.lastWriter) > maxTrackedWritesets {
+		pa.tracker.prune(committed)
+	}
+	pa.wg.Add(1)
+	go func() {
+		defer pa.wg.Done()
+		err := pa.apply(ctx, w, txn)
+		if err != nil {
+			if rbErr := w.dbClient.Rollback(); rbErr != nil {
+				log.Errorf("Error rolling back transaction at position %v: %v", txn.pos, rbErr)
+			}
+		}
+		pa.mu.Lock()
+		if err != nil {
+			if pa.err == nil {
+				pa.err = err
+			}
+		} else {
+			pa.committed = txn.seq
+		}
+		pa.cond.Broadcast()
+		pa.mu.Unlock()
+		pa.workers <- w
+	}()
+	return nil
+}
+
+// apply applies a transaction once the transactions it depends on have been
+// committed, and then commits it, along with its position, once all the
+// earlier transactions have been committed.
+func (pa *parallelApplier) apply(ctx context.Context, w *parallelApplyWorker, txn *parallelTxn) error {
+	vr := pa.vp.vr
+	if err := pa.waitFor(ctx, txn.dependsOn); err != nil {
+		return err
+	}
+	if err := w.dbClient.Begin(); err != nil {
+		return err
+	}
+	query := func(sql string) (*sqltypes.Result, error) {
+		start := time.Now()
+		qr, err := w.dbClient.ExecuteWithRetry(ctx, sql)
+		vr.stats.QueryCount.Add(pa.vp.phase, 1)
+		vr.stats.QueryTimings.Record(pa.vp.phase, start)
+		return qr, err
+	}
+	for _, ev := range txn.events {
+		if ev.event.Type != binlogdatapb.VEventType_ROW {
+			sql := ev.event.Statement
+			if sql == "" {
+				sql = ev.event.Dml
+			}
+			if _, err := query(sql); err != nil {
+				return vterrors.Wrapf(err, "error applying transaction at position %v", txn.pos)
+			}
+			continue
+		}
+		rowEvent := ev.event.RowEvent
+		if err := w.updateFKCheck(pa.vp, rowEvent.Flags); err != nil {
+			return err
+		}
+		for _, change := range rowEvent.RowChanges {
+			if _, err := ev.tplan.applyChange(change, query); err != nil {
+				return vterrors.Wrapf(err, "error applying transaction for table %s at position %v", rowEvent.TableName, txn.pos)
+			}
+		}
+	}
+	if err := pa.waitFor(ctx, txn.seq-1); err != nil {
+		return err
+	}
+	update := binlogplayer.GenerateUpdatePos(vr.id, txn.pos, time.Now().Unix(), txn.timestamp, vr.stats.CopyRowCount.Get(), vr.workflowConfig.StoreCompressedGTID)
+	if _, err := query(update); err != nil {
+		return fmt.Errorf("error %v updating position", err)
+	}
+	if err := w.dbClient.Commit(); err != nil {
+		return err
+	}
+	vr.stats.SetLastPosition(txn.pos)
+	return nil
+}
+
+// updateFKCheck is the worker's counterpart of vplayer.updateFKCheck, for the
+// worker's own session. Parallel apply only runs in the running phase.
+func (w *parallelApplyWorker) updateFKCheck(vp *vplayer, flags2 uint32) error {
+	if vp.vr.state != binlogdatapb.VReplicationWorkflowState_Running {
+		return nil
+	}
+	dbForeignKeyChecksEnabled := !(flags2&NoForeignKeyCheckFlagBitmask == NoForeignKeyCheckFlagBitmask)
+	if w.foreignKeyChecksStateInitialized && dbForeignKeyChecksEnabled == w.foreignKeyChecksEnabled {
+		return nil
+	}
+	if _, err := w.dbClient.Execute("set @@session.foreign_key_checks=" + strconv.FormatBool(dbForeignKeyChecksEnabled)); err != nil {
+		return fmt.Errorf("failed to set session foreign_key_checks: %w", err)
+	}
+	w.foreignKeyChecksEnabled = dbForeignKeyChecksEnabled
+	w.foreignKeyChecksStateInitialized = true
+	return nil
+}
+
+// targetTableSchema returns the CREATE TABLE statement of a target table.
+func (vp *vplayer) targetTableSchema(ctx context.Context, targetTable string) (string, error) {
+	req := &tabletmanagerdatapb.GetSchemaRequest{Tables: []string{targetTable}}
+	sd, err := vp.vr.mysqld.GetSchema(ctx, vp.vr.dbClient.DBName(), req)
+	if err != nil {
+		return "", err
+	}
+	if len(sd.TableDefinitions) != 1 {
+		return "", fmt.Errorf("could not find the definition of target table %s", targetTable)
+	}
+	return sd.TableDefinitions[0].Schema, nil
+}
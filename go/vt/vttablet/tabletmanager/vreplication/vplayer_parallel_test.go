@@ -0,0 +1,515 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/capabilities"
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/sqlparser"
+	vttablet "vitess.io/vitess/go/vt/vttablet/common"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+func TestWritesetTracker(t *testing.T) {
+	type txn struct {
+		keys          []string
+		barrier       bool
+		wantDependsOn int64
+	}
+	txns := []txn{
+		{keys: []string{"a"}, wantDependsOn: 0},
+		{keys: []string{"b"}, wantDependsOn: 0},
+		{keys: []string{"a", "c"}, wantDependsOn: 1},
+		{keys: []string{"c", "b"}, wantDependsOn: 3},
+		{keys: []string{"d"}, wantDependsOn: 0},
+		{barrier: true, wantDependsOn: 5},
+		{keys: []string{"e"}, wantDependsOn: 6},
+		{keys: []string{"a"}, wantDependsOn: 6},
+		{keys: []string{"e"}, wantDependsOn: 7},
+		{keys: []string{"f", "f"}, wantDependsOn: 6},
+	}
+	wt := newWritesetTracker()
+	for i, txn := range txns {
+		seq, dependsOn := wt.track(txn.keys, txn.barrier)
+		assert.EqualValues(t, i+1, seq)
+		assert.Equal(t, txn.wantDependsOn, dependsOn, "transaction %d", seq)
+	}
+
+	wt.prune(7)
+	assert.Equal(t, map[string]int64{"a": 8, "e": 9, "f": 10}, wt.lastWriter)
+}
+
+func TestTableWriteset(t *testing.T) {
+	colInfos := map[string][]*ColumnInfo{
+		"t1": {
+			{Name: "id", IsPK: true},
+			{Name: "email", Collation: "utf8mb4_0900_ai_ci"},
+			{Name: "val"},
+		},
+	}
+	fields := sqltypes.MakeTestFields("id|email|val", "int64|varchar|int64")
+	row := func(id int64, email string, val int64) *binlogdatapb.RowChange {
+		return &binlogdatapb.RowChange{
+			After: sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(id), sqltypes.NewVarChar(email), sqltypes.NewInt64(val)}),
+		}
+	}
+
+	testcases := []struct {
+		name        string
+		filter      string
+		createTable string
+		barrier     bool
+	}{
+		{
+			name:        "primary and unique keys",
+			createTable: "create table t1 (id bigint, email varchar(128), val bigint, primary key (id), unique key (email))",
+		},
+		{
+			name:        "foreign key",
+			createTable: "create table t1 (id bigint, email varchar(128), val bigint, primary key (id), foreign key (val) references t2 (id))",
+			barrier:     true,
+		},
+		{
+			name:        "prefix unique key",
+			createTable: "create table t1 (id bigint, email varchar(128), val bigint, primary key (id), unique key (email(10)))",
+			barrier:     true,
+		},
+		{
+			name:        "unique key on computed column",
+			filter:      "select id, email, val+1 as val from t1",
+			createTable: "create table t1 (id bigint, email varchar(128), val bigint, primary key (id), unique key (val))",
+			barrier:     true,
+		},
+		{
+			name:        "no unique key",
+			createTable: "create table t1 (id bigint, email varchar(128), val bigint, key (id))",
+			barrier:     true,
+		},
+	}
+	vttablet.InitVReplicationConfigDefaults()
+	vr := &vreplicator{
+		workflowConfig: vttablet.DefaultVReplicationConfig,
+	}
+	parser := sqlparser.NewTestParser()
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			input := &binlogdatapb.Filter{
+				Rules: []*binlogdatapb.Rule{{Match: "t1", Filter: tcase.filter}},
+			}
+			plan, err := vr.buildReplicatorPlan(getSource(input), colInfos, nil, binlogplayer.NewStats(), collations.MySQL8(), parser)
+			require.NoError(t, err)
+			tplan, err := plan.buildExecutionPlan(&binlogdatapb.FieldEvent{TableName: "t1", Fields: fields})
+			require.NoError(t, err)
+
+			tw := buildTableWriteset(tplan, tcase.createTable, parser, collations.MySQL8())
+			assert.Equal(t, tcase.barrier, tw.barrier)
+			if tcase.barrier {
+				_, ok := tw.rowKeys(tplan, row(1, "a@example.com", 1))
+				assert.False(t, ok)
+				return
+			}
+
+			keys1, ok := tw.rowKeys(tplan, row(1, "a@example.com", 1))
+			require.True(t, ok)
+			require.Len(t, keys1, 2)
+			// Different primary key, and the same email in the column's collation.
+			keys2, ok := tw.rowKeys(tplan, row(2, "A@Example.com ", 2))
+			require.True(t, ok)
+			require.Len(t, keys2, 2)
+			assert.NotEqual(t, keys1[0], keys2[0])
+			assert.Equal(t, keys1[1], keys2[1])
+			// Same primary key, different email.
+			keys3, ok := tw.rowKeys(tplan, row(1, "b@example.com", 3))
+			require.True(t, ok)
+			assert.Equal(t, keys1[0], keys3[0])
+			assert.NotEqual(t, keys1[1], keys3[1])
+
+			// An update has the keys of both the before and after images.
+			update := row(1, "c@example.com", 1)
+			update.Before = row(1, "a@example.com", 1).After
+			keys, ok := tw.rowKeys(tplan, update)
+			require.True(t, ok)
+			assert.Len(t, keys, 4)
+			assert.Contains(t, keys, keys1[1])
+
+			// NULLs never conflict.
+			nullRow := &binlogdatapb.RowChange{
+				After: sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(4), sqltypes.NULL, sqltypes.NewInt64(4)}),
+			}
+			keys, ok = tw.rowKeys(tplan, nullRow)
+			require.True(t, ok)
+			assert.Len(t, keys, 1)
+
+			// Partial row images are not supported.
+			partial := row(5, "d@example.com", 5)
+			partial.DataColumns = &binlogdatapb.RowChange_Bitmap{Count: 3, Cols: []byte{0x03}}
+			_, ok = tw.rowKeys(tplan, partial)
+			assert.False(t, ok)
+		})
+	}
+}
+
+// parallelApplyTestDB is a fake database for the parallel applier tests. It
+// records the statements of the transactions that are committed, in commit
+// order, along with a log of all the statements as they are executed.
+type parallelApplyTestDB struct {
+	mu sync.Mutex
+	// blocked maps a statement fragment to a channel that the statements
+	// containing it wait on before executing.
+	blocked map[string]chan struct{}
+	// failing is a statement fragment that makes the statements containing
+	// it fail.
+	failing string
+	// executed has an entry for each statement executed, and for each commit.
+	executed []string
+	// committed has the statements of each committed transaction.
+	committed [][]string
+}
+
+func newParallelApplyTestDB() *parallelApplyTestDB {
+	return &parallelApplyTestDB{blocked: make(map[string]chan struct{})}
+}
+
+func (db *parallelApplyTestDB) block(fragment string) chan struct{} {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	ch := make(chan struct{})
+	db.blocked[fragment] = ch
+	return ch
+}
+
+func (db *parallelApplyTestDB) hasExecuted(fragment string) bool {
+	return db.executedIndex(fragment) >= 0
+}
+
+// executedIndex returns the index of the first executed statement that
+// contains the fragment, or -1.
+func (db *parallelApplyTestDB) executedIndex(fragment string) int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	for i, query := range db.executed {
+		if strings.Contains(query, fragment) {
+			return i
+		}
+	}
+	return -1
+}
+
+// committedPositions returns the positions saved by the committed transactions.
+func (db *parallelApplyTestDB) committedPositions() []string {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	var positions []string
+	for _, queries := range db.committed {
+		for _, query := range queries {
+			if _, pos, ok := strings.Cut(query, "set pos='"); ok {
+				pos, _, _ = strings.Cut(pos, "'")
+				positions = append(positions, pos)
+			}
+		}
+	}
+	return positions
+}
+
+type parallelApplyTestConn struct {
+	db      *parallelApplyTestDB
+	pending []string
+}
+
+var _ binlogplayer.DBClient = (*parallelApplyTestConn)(nil)
+
+func (dc *parallelApplyTestConn) DBName() string  { return "db" }
+func (dc *parallelApplyTestConn) Connect() error  { return nil }
+func (dc *parallelApplyTestConn) Close()          {}
+func (dc *parallelApplyTestConn) IsClosed() bool  { return false }
+func (dc *parallelApplyTestConn) Begin() error    { return nil }
+func (dc *parallelApplyTestConn) Rollback() error { dc.pending = nil; return nil }
+
+func (dc *parallelApplyTestConn) Commit() error {
+	dc.db.mu.Lock()
+	defer dc.db.mu.Unlock()
+	dc.db.executed = append(dc.db.executed, "commit")
+	dc.db.committed = append(dc.db.committed, dc.pending)
+	dc.pending = nil
+	return nil
+}
+
+func (dc *parallelApplyTestConn) ExecuteFetch(query string, maxrows int) (*sqltypes.Result, error) {
+	dc.db.mu.Lock()
+	var blocked chan struct{}
+	for fragment, ch := range dc.db.blocked {
+		if strings.Contains(query, fragment) {
+			blocked = ch
+		}
+	}
+	dc.db.mu.Unlock()
+	if blocked != nil {
+		<-blocked
+	}
+
+	dc.db.mu.Lock()
+	defer dc.db.mu.Unlock()
+	if dc.db.failing != "" && strings.Contains(query, dc.db.failing) {
+		return nil, fmt.Errorf("failed to execute %s", query)
+	}
+	dc.db.executed = append(dc.db.executed, query)
+	if !strings.HasPrefix(query, "set ") {
+		dc.pending = append(dc.pending, query)
+	}
+	return &sqltypes.Result{RowsAffected: 1}, nil
+}
+
+func (dc *parallelApplyTestConn) ExecuteFetchMulti(query string, maxrows int) ([]*sqltypes.Result, error) {
+	return nil, fmt.Errorf("unexpected multi-statement query: %s", query)
+}
+
+func (dc *parallelApplyTestConn) SupportsCapability(capabilities.FlavorCapability) (bool, error) {
+	return false, nil
+}
+
+// parallelApplyTestEnv drives a parallel applier with the transactions of a
+// single table t1, whose rows are identified by their primary key.
+type parallelApplyTestEnv struct {
+	t      *testing.T
+	db     *parallelApplyTestDB
+	vp     *vplayer
+	tplan  *TablePlan
+	fields []*querypb.Field
+}
+
+func newParallelApplyTestEnv(t *testing.T) *parallelApplyTestEnv {
+	vttablet.InitVReplicationConfigDefaults()
+	vr := &vreplicator{
+		id:             1,
+		state:          binlogdatapb.VReplicationWorkflowState_Running,
+		stats:          binlogplayer.NewStats(),
+		workflowConfig: vttablet.DefaultVReplicationConfig,
+	}
+	t.Cleanup(vr.stats.Stop)
+	colInfos := map[string][]*ColumnInfo{
+		"t1": {{Name: "id", IsPK: true}, {Name: "val"}},
+	}
+	fields := sqltypes.MakeTestFields("id|val", "int64|int64")
+	parser := sqlparser.NewTestParser()
+	filter := &binlogdatapb.Filter{Rules: []*binlogdatapb.Rule{{Match: "t1"}}}
+	plan, err := vr.buildReplicatorPlan(getSource(filter), colInfos, nil, vr.stats, collations.MySQL8(), parser)
+	require.NoError(t, err)
+	tplan, err := plan.buildExecutionPlan(&binlogdatapb.FieldEvent{TableName: "t1", Fields: fields})
+	require.NoError(t, err)
+
+	return &parallelApplyTestEnv{
+		t:  t,
+		db: newParallelApplyTestDB(),
+		vp: &vplayer{
+			vr:         vr,
+			phase:      "test",
+			tablePlans: map[string]*TablePlan{"t1": tplan},
+		},
+		tplan:  tplan,
+		fields: fields,
+	}
+}
+
+// newApplier returns an applier with the given number of workers. It's what
+// the vplayer creates when it starts, or restarts, replicating.
+func (env *parallelApplyTestEnv) newApplier(ctx context.Context, numWorkers int) *parallelApplier {
+	pa := &parallelApplier{
+		vp:        env.vp,
+		workers:   make(chan *parallelApplyWorker, numWorkers),
+		tracker:   newWritesetTracker(),
+		writesets: map[string]*tableWriteset{"t1": buildTableWriteset(env.tplan, "create table t1 (id bigint, val bigint, primary key (id))", sqlparser.NewTestParser(), collations.MySQL8())},
+	}
+	pa.cond = sync.NewCond(&pa.mu)
+	pa.stopAfterFunc = context.AfterFunc(ctx, func() {
+		pa.mu.Lock()
+		defer pa.mu.Unlock()
+		pa.cond.Broadcast()
+	})
+	for range numWorkers {
+		w := &parallelApplyWorker{dbClient: newVDBClient(&parallelApplyTestConn{db: env.db}, env.vp.vr.stats, 100)}
+		pa.allWorkers = append(pa.allWorkers, w)
+		pa.workers <- w
+	}
+	return pa
+}
+
+// position returns the position of the n-th transaction.
+func (env *parallelApplyTestEnv) position(n int) replication.Position {
+	pos, err := binlogplayer.DecodePosition(fmt.Sprintf("MySQL56/00000000-0000-0000-0000-000000000001:1-%d", n))
+	require.NoError(env.t, err)
+	return pos
+}
+
+// encodedPositions returns the encoded positions of the first n transactions.
+func (env *parallelApplyTestEnv) encodedPositions(n int) []string {
+	positions := make([]string, 0, n)
+	for i := 1; i <= n; i++ {
+		positions = append(positions, replication.EncodePosition(env.position(i)))
+	}
+	return positions
+}
+
+// applyTxn applies the n-th transaction, which upserts a row with the given id.
+func (env *parallelApplyTestEnv) applyTxn(ctx context.Context, pa *parallelApplier, n int, id int64) error {
+	row := sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(id), sqltypes.NewInt64(int64(n))})
+	event := &binlogdatapb.VEvent{
+		Type: binlogdatapb.VEventType_ROW,
+		RowEvent: &binlogdatapb.RowEvent{
+			TableName:  "t1",
+			RowChanges: []*binlogdatapb.RowChange{{Before: row, After: row}},
+		},
+	}
+	if err := pa.applyEvent(ctx, event); err != nil {
+		return err
+	}
+	env.vp.pos = env.position(n)
+	return pa.applyEvent(ctx, &binlogdatapb.VEvent{Type: binlogdatapb.VEventType_COMMIT, Timestamp: int64(n)})
+}
+
+// valFragment is a fragment of the statement that applies the n-th transaction.
+func valFragment(n int) string {
+	return fmt.Sprintf("val=%d ", n)
+}
+
+// TestParallelApplierConflictOrdering tests that a transaction that modifies
+// the same row as an earlier one is only applied once the earlier one has been
+// committed, while one that doesn't is applied concurrently.
+func TestParallelApplierConflictOrdering(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	env := newParallelApplyTestEnv(t)
+	pa := env.newApplier(ctx, 3)
+	defer pa.close()
+
+	release := env.db.block(valFragment(1))
+	require.NoError(t, env.applyTxn(ctx, pa, 1, 1))
+	require.NoError(t, env.applyTxn(ctx, pa, 2, 2))
+	require.NoError(t, env.applyTxn(ctx, pa, 3, 1))
+
+	// The second transaction doesn't conflict with the first one.
+	require.Eventually(t, func() bool {
+		return env.db.hasExecuted(valFragment(2))
+	}, 5*time.Second, 10*time.Millisecond)
+	// The third one waits for the first one, which is blocked.
+	time.Sleep(100 * time.Millisecond)
+	assert.False(t, env.db.hasExecuted(valFragment(3)))
+	assert.Empty(t, env.db.committedPositions())
+
+	close(release)
+	require.NoError(t, pa.wait(ctx))
+	first, third := env.db.executedIndex(valFragment(1)), env.db.executedIndex(valFragment(3))
+	require.GreaterOrEqual(t, first, 0)
+	require.Greater(t, third, first)
+	assert.Contains(t, env.db.executed[first:third], "commit")
+	assert.Equal(t, env.encodedPositions(3), env.db.committedPositions())
+}
+
+// TestParallelApplierOutOfOrderCommit tests that transactions that are applied
+// before an earlier one are only committed after it, so that the saved position
+// is always one that all the earlier transactions have been committed for.
+func TestParallelApplierOutOfOrderCommit(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	env := newParallelApplyTestEnv(t)
+	pa := env.newApplier(ctx, 4)
+	defer pa.close()
+
+	release := env.db.block(valFragment(1))
+	for n := 1; n <= 4; n++ {
+		require.NoError(t, env.applyTxn(ctx, pa, n, int64(n)))
+	}
+	// The later transactions are applied, but none of them is committed
+	// while the first one is blocked.
+	require.Eventually(t, func() bool {
+		return env.db.hasExecuted(valFragment(2)) && env.db.hasExecuted(valFragment(3)) && env.db.hasExecuted(valFragment(4))
+	}, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	assert.Empty(t, env.db.committedPositions())
+	assert.True(t, env.vp.vr.stats.LastPosition().IsZero())
+
+	close(release)
+	require.NoError(t, pa.wait(ctx))
+	assert.Equal(t, env.encodedPositions(4), env.db.committedPositions())
+	// Each transaction is committed along with its own position.
+	for i, queries := range env.db.committed {
+		require.Len(t, queries, 2)
+		assert.Contains(t, queries[0], valFragment(i+1))
+	}
+	assert.True(t, env.vp.vr.stats.LastPosition().Equal(env.position(4)))
+}
+
+// TestParallelApplierRestart tests that when a transaction fails, the later
+// ones that were already applied are rolled back, and replication can restart
+// from the saved position without losing or reapplying any transaction.
+func TestParallelApplierRestart(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	env := newParallelApplyTestEnv(t)
+	pa := env.newApplier(ctx, 3)
+
+	env.db.failing = valFragment(2)
+	release := env.db.block(valFragment(2))
+	require.NoError(t, env.applyTxn(ctx, pa, 1, 1))
+	require.NoError(t, pa.wait(ctx))
+	require.NoError(t, env.applyTxn(ctx, pa, 2, 2))
+	require.NoError(t, env.applyTxn(ctx, pa, 3, 3))
+	require.Eventually(t, func() bool {
+		return env.db.hasExecuted(valFragment(3))
+	}, 5*time.Second, 10*time.Millisecond)
+	close(release)
+	err := pa.wait(ctx)
+	require.ErrorContains(t, err, "error applying transaction for table t1 at position")
+	// No more transactions are accepted after a failure.
+	require.Error(t, env.applyTxn(ctx, pa, 4, 4))
+	pa.close()
+
+	// The third transaction was applied, but not committed.
+	saved := env.db.committedPositions()
+	require.Equal(t, env.encodedPositions(1), saved)
+	assert.True(t, env.vp.vr.stats.LastPosition().Equal(env.position(1)))
+
+	// Restart from the saved position.
+	env.db.mu.Lock()
+	env.db.failing = ""
+	env.db.blocked = make(map[string]chan struct{})
+	env.db.mu.Unlock()
+	pa = env.newApplier(ctx, 3)
+	defer pa.close()
+	for n := 2; n <= 4; n++ {
+		require.NoError(t, env.applyTxn(ctx, pa, n, int64(n)))
+	}
+	require.NoError(t, pa.wait(ctx))
+	assert.Equal(t, env.encodedPositions(4), env.db.committedPositions())
+	for i, queries := range env.db.committed {
+		assert.Contains(t, queries[0], valFragment(i+1))
+	}
+}
@@ -1119,11 +1119,12 @@ func (vr *vreplicator) execPostCopyActions(ctx context.Context, tableName string
 
 // supportsDeferredSecondaryKeys tells you if related work should be done
 // for the workflow. Deferring secondary index generation is only supported
-// with MoveTables, Migrate, and Reshard.
+// with MoveTables, Migrate, Reshard, and Import.
 func (vr *vreplicator) supportsDeferredSecondaryKeys() bool {
 	return vr.WorkflowType == int32(binlogdatapb.VReplicationWorkflowType_MoveTables) ||
 		vr.WorkflowType == int32(binlogdatapb.VReplicationWorkflowType_Migrate) ||
-		vr.WorkflowType == int32(binlogdatapb.VReplicationWorkflowType_Reshard)
+		vr.WorkflowType == int32(binlogdatapb.VReplicationWorkflowType_Reshard) ||
+		vr.WorkflowType == int32(binlogdatapb.VReplicationWorkflowType_Import)
 }
 
 func (vr *vreplicator) newClientConnection(ctx context.Context) (*vdbClient, error) {
@@ -68,6 +68,7 @@ const (
 	VStreamerName         Name = "vstreamer"
 	VPlayerName           Name = "vplayer"
 	VCopierName           Name = "vcopier"
+	VDiffRepairName       Name = "vdiff-repair"
 	ResultStreamerName    Name = "resultstreamer"
 	RowStreamerName       Name = "rowstreamer"
 	ExternalConnectorName Name = "external-connector"
@@ -210,6 +210,9 @@ type TabletManagerClient interface {
 	CreateVReplicationWorkflow(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.CreateVReplicationWorkflowRequest) (*tabletmanagerdatapb.CreateVReplicationWorkflowResponse, error)
 	DeleteTableData(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.DeleteTableDataRequest) (*tabletmanagerdatapb.DeleteTableDataResponse, error)
 	DeleteVReplicationWorkflow(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.DeleteVReplicationWorkflowRequest) (*tabletmanagerdatapb.DeleteVReplicationWorkflowResponse, error)
+	// ExecuteFetchOnExternalMysql executes a read-only query on an external
+	// MySQL that the tablet is configured to replicate from.
+	ExecuteFetchOnExternalMysql(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.ExecuteFetchOnExternalMysqlRequest) (*tabletmanagerdatapb.ExecuteFetchOnExternalMysqlResponse, error)
 	HasVReplicationWorkflows(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.HasVReplicationWorkflowsRequest) (*tabletmanagerdatapb.HasVReplicationWorkflowsResponse, error)
 	ReadVReplicationWorkflows(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.ReadVReplicationWorkflowsRequest) (*tabletmanagerdatapb.ReadVReplicationWorkflowsResponse, error)
 	ReadVReplicationWorkflow(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.ReadVReplicationWorkflowRequest) (*tabletmanagerdatapb.ReadVReplicationWorkflowResponse, error)
@@ -278,6 +281,14 @@ type TabletManagerClient interface {
 	// PromoteReplica makes the tablet the new primary
 	PromoteReplica(ctx context.Context, tablet *topodatapb.Tablet, semiSync bool) (string, error)
 
+	// InspectBinlogTransactions reads the transactions of the given GTID set
+	// from the binary logs of the tablet, and tells which of them write data.
+	InspectBinlogTransactions(ctx context.Context, tablet *topodatapb.Tablet, gtidSet string) (*tabletmanagerdatapb.InspectBinlogTransactionsResponse, error)
+
+	// InjectEmptyTransactions commits an empty transaction for each GTID
+	// of the given set on the tablet.
+	InjectEmptyTransactions(ctx context.Context, tablet *topodatapb.Tablet, gtidSet string) error
+
 	//
 	// Backup / restore related methods
 	//
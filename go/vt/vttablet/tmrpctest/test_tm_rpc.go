@@ -111,6 +111,11 @@ func (fra *fakeRPCTM) ResetSequences(ctx context.Context, tables []string) error
 	panic("implement me")
 }
 
+func (fra *fakeRPCTM) ExecuteFetchOnExternalMysql(ctx context.Context, req *tabletmanagerdatapb.ExecuteFetchOnExternalMysqlRequest) (*tabletmanagerdatapb.ExecuteFetchOnExternalMysqlResponse, error) {
+	// TODO implement me
+	panic("implement me")
+}
+
 func (fra *fakeRPCTM) ValidateVReplicationPermissions(ctx context.Context, req *tabletmanagerdatapb.ValidateVReplicationPermissionsRequest) (*tabletmanagerdatapb.ValidateVReplicationPermissionsResponse, error) {
 	// TODO implement me
 	panic("implement me")
@@ -1418,6 +1423,51 @@ func tmRPCTestPromoteReplicaPanic(ctx context.Context, t *testing.T, client tmcl
 	expectHandleRPCPanic(t, "PromoteReplica", true /*verbose*/, err)
 }
 
+var testInspectBinlogTransactionsGTIDSet = "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1-3"
+var testInspectBinlogTransactionsResponse = &tabletmanagerdatapb.InspectBinlogTransactionsResponse{
+	EmptyGtidSet: "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1-2",
+	WriteGtidSet: "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:3",
+}
+
+func (fra *fakeRPCTM) InspectBinlogTransactions(ctx context.Context, gtidSet string) (*tabletmanagerdatapb.InspectBinlogTransactionsResponse, error) {
+	if fra.panics {
+		panic(fmt.Errorf("test-triggered panic"))
+	}
+	compare(fra.t, "InspectBinlogTransactions gtidSet", gtidSet, testInspectBinlogTransactionsGTIDSet)
+	return testInspectBinlogTransactionsResponse, nil
+}
+
+func tmRPCTestInspectBinlogTransactions(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	response, err := client.InspectBinlogTransactions(ctx, tablet, testInspectBinlogTransactionsGTIDSet)
+	compareError(t, "InspectBinlogTransactions", err, response, testInspectBinlogTransactionsResponse)
+}
+
+func tmRPCTestInspectBinlogTransactionsPanic(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	_, err := client.InspectBinlogTransactions(ctx, tablet, testInspectBinlogTransactionsGTIDSet)
+	expectHandleRPCPanic(t, "InspectBinlogTransactions", true /*verbose*/, err)
+}
+
+var testInjectEmptyTransactionsCalled = false
+
+func (fra *fakeRPCTM) InjectEmptyTransactions(ctx context.Context, gtidSet string) error {
+	if fra.panics {
+		panic(fmt.Errorf("test-triggered panic"))
+	}
+	compare(fra.t, "InjectEmptyTransactions gtidSet", gtidSet, testInspectBinlogTransactionsGTIDSet)
+	testInjectEmptyTransactionsCalled = true
+	return nil
+}
+
+func tmRPCTestInjectEmptyTransactions(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	err := client.InjectEmptyTransactions(ctx, tablet, testInspectBinlogTransactionsGTIDSet)
+	compareError(t, "InjectEmptyTransactions", err, true, testInjectEmptyTransactionsCalled)
+}
+
+func tmRPCTestInjectEmptyTransactionsPanic(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	err := client.InjectEmptyTransactions(ctx, tablet, testInspectBinlogTransactionsGTIDSet)
+	expectHandleRPCPanic(t, "InjectEmptyTransactions", true /*verbose*/, err)
+}
+
 //
 // Backup / restore related methods
 //
@@ -1597,6 +1647,8 @@ func Run(t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.T
 	tmRPCTestSetReplicationSource(ctx, t, client, tablet)
 	tmRPCTestStopReplicationAndGetStatus(ctx, t, client, tablet)
 	tmRPCTestPromoteReplica(ctx, t, client, tablet)
+	tmRPCTestInspectBinlogTransactions(ctx, t, client, tablet)
+	tmRPCTestInjectEmptyTransactions(ctx, t, client, tablet)
 
 	tmRPCTestInitReplica(ctx, t, client, tablet)
 	tmRPCTestReplicaWasPromoted(ctx, t, client, tablet)
@@ -1659,6 +1711,8 @@ func Run(t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.T
 	tmRPCTestSetReplicationSourcePanic(ctx, t, client, tablet)
 	tmRPCTestStopReplicationAndGetStatusPanic(ctx, t, client, tablet)
 	tmRPCTestPromoteReplicaPanic(ctx, t, client, tablet)
+	tmRPCTestInspectBinlogTransactionsPanic(ctx, t, client, tablet)
+	tmRPCTestInjectEmptyTransactionsPanic(ctx, t, client, tablet)
 
 	tmRPCTestInitReplicaPanic(ctx, t, client, tablet)
 	tmRPCTestReplicaWasPromotedPanic(ctx, t, client, tablet)
@@ -127,12 +127,8 @@ func (wr *Wrangler) TabletExternallyReparented(ctx context.Context, newPrimaryAl
 	if tablet.Type != topodatapb.TabletType_PRIMARY {
 		log.Infof("TabletExternallyReparented: executing tablet type change to PRIMARY")
 
-		durabilityName, err := wr.ts.GetKeyspaceDurability(ctx, tablet.Keyspace)
-		if err != nil {
-			return err
-		}
-		log.Infof("Getting a new durability policy for %v", durabilityName)
-		durability, err := policy.GetDurabilityPolicy(durabilityName)
+		log.Infof("Getting a new durability policy for keyspace %v", tablet.Keyspace)
+		durability, err := policy.GetKeyspaceDurabilityPolicy(ctx, wr.ts, tablet.Keyspace)
 		if err != nil {
 			return err
 		}
@@ -138,11 +138,7 @@ func (wr *Wrangler) shouldSendSemiSyncAck(ctx context.Context, tablet *topodatap
 		return false, err
 	}
 
-	durabilityName, err := wr.ts.GetKeyspaceDurability(ctx, tablet.Keyspace)
-	if err != nil {
-		return false, err
-	}
-	durability, err := policy.GetDurabilityPolicy(durabilityName)
+	durability, err := policy.GetKeyspaceDurabilityPolicy(ctx, wr.ts, tablet.Keyspace)
 	if err != nil {
 		return false, err
 	}